@@ -49,4 +49,19 @@ const (
 
 	// Deploy is the string value for the deploy action when logging.
 	Deploy = "deploy"
+
+	// Drain is the string for the drain action when logging.
+	Drain = "drain"
+
+	// Uncordon is the string for the uncordon action when logging.
+	Uncordon = "uncordon"
+
+	// Repair is the string for the repair action when logging.
+	Repair = "repair"
+
+	// Scale is the string for the scale action when logging.
+	Scale = "scale"
+
+	// Revoke is the string for the revoke action when logging.
+	Revoke = "revoke"
 )
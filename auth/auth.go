@@ -67,6 +67,12 @@ var (
 	ErrAuth0TokenBadIssuer = errors.New("auth0 token issuer does not match value configured in API")
 	// ErrAuth0TokenBadAudience is thrown when the audience claim in an auth0 token does not match the value of the target API
 	ErrAuth0TokenBadAudience = errors.New("auth0 token audience does not match the target API")
+	// ErrOIDCTokenExpired is thrown when an OIDC token is expired
+	ErrOIDCTokenExpired = errors.New("OIDC token expired")
+	// ErrOIDCTokenBadIssuer is thrown when the issuer of an OIDC token does not match the configured provider
+	ErrOIDCTokenBadIssuer = errors.New("OIDC token issuer does not match the configured provider")
+	// ErrOIDCTokenBadAudience is thrown when the audience of an OIDC token does not include serviced
+	ErrOIDCTokenBadAudience = errors.New("OIDC token audience does not match the configured audience")
 
 	log = logging.PackageLogger()
 )
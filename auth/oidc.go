@@ -0,0 +1,433 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/utils"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	// DefaultOIDCScope is the scope requested from an OIDC provider when none
+	// is configured.
+	DefaultOIDCScope = "openid profile email"
+
+	// DefaultOIDCGroupsClaim is the name of the token claim that lists the
+	// user's groups when none is configured.
+	DefaultOIDCGroupsClaim = "groups"
+
+	// oidcKeyRefreshInterval is the least amount of time between fetches of
+	// the provider's signing keys.
+	oidcKeyRefreshInterval = time.Minute
+)
+
+// OIDCConfig describes a generic OpenID Connect identity provider
+type OIDCConfig struct {
+	Issuer       string   // URL of the provider; the discovery document is found under it
+	ClientID     string   // ID of the client registered with the provider
+	ClientSecret string   // Secret of the client registered with the provider
+	Audience     string   // Audience required of bearer tokens; defaults to the client id
+	Scope        string   // Scope to request at login
+	GroupsClaim  string   // Name of the claim that lists the user's groups
+	AdminGroups  []string // Groups whose members are granted admin access
+}
+
+// OIDCDiscovery is the subset of the provider's discovery document that is
+// used by serviced.
+type OIDCDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint,omitempty"`
+}
+
+// OIDCToken is a validated token issued by an OIDC provider
+type OIDCToken interface {
+	HasAdminAccess() bool
+	User() string
+	Groups() []string
+	Expiration() int64
+}
+
+type oidcToken struct {
+	user        string
+	groups      []string
+	expiresAt   int64
+	adminAccess bool
+}
+
+// HasAdminAccess implements OIDCToken
+func (t *oidcToken) HasAdminAccess() bool { return t.adminAccess }
+
+// User implements OIDCToken
+func (t *oidcToken) User() string { return t.user }
+
+// Groups implements OIDCToken
+func (t *oidcToken) Groups() []string { return t.groups }
+
+// Expiration implements OIDCToken
+func (t *oidcToken) Expiration() int64 { return t.expiresAt }
+
+// OIDCProvider validates tokens and performs the authorization code flow
+// against a generic OpenID Connect provider.
+type OIDCProvider struct {
+	config OIDCConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	discovery   *OIDCDiscovery
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+}
+
+// NewOIDCProvider returns a provider for the given configuration.  The
+// discovery document is not loaded until it is needed.
+func NewOIDCProvider(cfg OIDCConfig) *OIDCProvider {
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	if cfg.Audience == "" {
+		cfg.Audience = cfg.ClientID
+	}
+	if cfg.Scope == "" {
+		cfg.Scope = DefaultOIDCScope
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = DefaultOIDCGroupsClaim
+	}
+	return &OIDCProvider{
+		config: cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+var (
+	oidcProvider     *OIDCProvider
+	oidcProviderOnce sync.Once
+)
+
+// OIDCIsConfigured returns true if an OIDC provider has been configured
+func OIDCIsConfigured() bool {
+	opts := config.GetOptions()
+	return len(opts.OIDCIssuer) > 0 && len(opts.OIDCClientID) > 0
+}
+
+// GetOIDCProvider returns the OIDC provider described by the global options
+func GetOIDCProvider() *OIDCProvider {
+	oidcProviderOnce.Do(func() {
+		opts := config.GetOptions()
+		var adminGroups []string
+		for _, g := range strings.Split(opts.OIDCAdminGroups, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				adminGroups = append(adminGroups, g)
+			}
+		}
+		oidcProvider = NewOIDCProvider(OIDCConfig{
+			Issuer:       opts.OIDCIssuer,
+			ClientID:     opts.OIDCClientID,
+			ClientSecret: opts.OIDCClientSecret,
+			Audience:     opts.OIDCAudience,
+			Scope:        opts.OIDCScope,
+			GroupsClaim:  opts.OIDCGroupsClaim,
+			AdminGroups:  adminGroups,
+		})
+	})
+	return oidcProvider
+}
+
+// Discover returns the provider's discovery document, which is loaded on
+// first use.  The request is made without holding p.mu.
+func (p *OIDCProvider) Discover() (*OIDCDiscovery, error) {
+	p.mu.Lock()
+	doc := p.discovery
+	p.mu.Unlock()
+	if doc != nil {
+		return doc, nil
+	}
+
+	doc = &OIDCDiscovery{}
+	if err := p.getJSON(p.config.Issuer+"/.well-known/openid-configuration", doc); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != p.config.Issuer {
+		log.WithFields(logrus.Fields{
+			"issuer":   p.config.Issuer,
+			"reported": doc.Issuer,
+		}).Warn("OIDC discovery document is for a different issuer")
+		return nil, ErrOIDCTokenBadIssuer
+	}
+	p.mu.Lock()
+	p.discovery = doc
+	p.mu.Unlock()
+	return doc, nil
+}
+
+// AuthCodeURL returns the url of the provider's login page
+func (p *OIDCProvider) AuthCodeURL(redirectURL, state string) (string, error) {
+	doc, err := p.Discover()
+	if err != nil {
+		return "", err
+	}
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.config.ClientID)
+	v.Set("redirect_uri", redirectURL)
+	v.Set("scope", p.config.Scope)
+	v.Set("state", state)
+	sep := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return doc.AuthorizationEndpoint + sep + v.Encode(), nil
+}
+
+// Exchange trades an authorization code for an ID token and returns the
+// validated token.
+func (p *OIDCProvider) Exchange(code, redirectURL string) (OIDCToken, error) {
+	doc, err := p.Discover()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.PostForm(doc.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {p.config.ClientID},
+		"client_secret": {p.config.ClientSecret},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+	var result struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.IDToken == "" {
+		return nil, ErrIdentityTokenInvalid
+	}
+	return p.parse(result.IDToken, p.config.ClientID)
+}
+
+// ParseToken validates a bearer token issued by the provider
+func (p *OIDCProvider) ParseToken(token string) (OIDCToken, error) {
+	return p.parse(token, p.config.Audience)
+}
+
+func (p *OIDCProvider) parse(token, audience string) (OIDCToken, error) {
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, ErrInvalidSigningMethod
+		}
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(kid)
+	})
+	if err != nil {
+		if verr, ok := err.(*jwt.ValidationError); ok {
+			if verr.Errors&jwt.ValidationErrorExpired != 0 {
+				return nil, ErrOIDCTokenExpired
+			}
+			if verr.Errors&(jwt.ValidationErrorSignatureInvalid|jwt.ValidationErrorUnverifiable) != 0 {
+				return nil, ErrIdentityTokenBadSig
+			}
+			if verr.Inner != nil {
+				return nil, verr.Inner
+			}
+		}
+		return nil, err
+	}
+	if !parsed.Valid {
+		return nil, ErrIdentityTokenInvalid
+	}
+
+	// the expiration is required
+	if !claims.VerifyExpiresAt(jwt.TimeFunc().Unix(), true) {
+		return nil, ErrOIDCTokenExpired
+	}
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != p.config.Issuer {
+		return nil, ErrOIDCTokenBadIssuer
+	}
+	if !utils.StringInSlice(audience, claimStrings(claims["aud"])) {
+		return nil, ErrOIDCTokenBadAudience
+	}
+
+	t := &oidcToken{groups: claimStrings(claims[p.config.GroupsClaim])}
+	if exp, ok := claims["exp"].(float64); ok {
+		t.expiresAt = int64(exp)
+	}
+	for _, name := range []string{"preferred_username", "email", "sub"} {
+		if t.user, _ = claims[name].(string); t.user != "" {
+			break
+		}
+	}
+	for _, group := range t.groups {
+		if utils.StringInSlice(group, p.config.AdminGroups) {
+			t.adminAccess = true
+			break
+		}
+	}
+	return t, nil
+}
+
+// claimStrings returns the value of a claim that may either be a string or a
+// list of strings.
+func claimStrings(v interface{}) []string {
+	switch value := v.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		result := []string{}
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// getKey returns the provider's signing key with the given key id, fetching
+// the key set if the key is not already known.
+func (p *OIDCProvider) getKey(kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.lookupKey(kid)
+	lastFetched := p.keysFetched
+	refresh := !ok && time.Since(lastFetched) >= oidcKeyRefreshInterval
+	if refresh {
+		// claim the refresh, so that concurrent lookups do not fetch too
+		p.keysFetched = time.Now()
+	}
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	} else if !refresh {
+		return nil, ErrNoPublicKey
+	}
+
+	// fetch the keys without holding p.mu, so that a slow provider does not
+	// hold up the validation of tokens with known keys
+	keys, err := p.fetchKeys()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		p.keysFetched = lastFetched
+		return nil, err
+	}
+	p.keys = keys
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, ErrNoPublicKey
+}
+
+// fetchKeys loads the provider's signing keys
+func (p *OIDCProvider) fetchKeys() (map[string]*rsa.PublicKey, error) {
+	doc, err := p.Discover()
+	if err != nil {
+		return nil, err
+	}
+	var jwks Jwks
+	if err := p.getJSON(doc.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := jwkPublicKey(jwk)
+		if err != nil {
+			log.WithField("kid", jwk.Kid).WithError(err).Warn("Could not load OIDC signing key")
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+// lookupKey returns a cached key.  A token without a key id may only be used
+// with a provider that has a single key.  Call p.mu.Lock() first.
+func (p *OIDCProvider) lookupKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *OIDCProvider) getJSON(url string, v interface{}) error {
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jwkPublicKey returns the RSA public key described by a JSON web key
+func jwkPublicKey(jwk JSONWebkeys) (*rsa.PublicKey, error) {
+	if jwk.N != "" && jwk.E != "" {
+		n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(jwk.N, "="))
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(jwk.E, "="))
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	}
+	if len(jwk.X5c) > 0 {
+		der, err := base64.StdEncoding.DecodeString(jwk.X5c[0])
+		if err != nil {
+			return nil, err
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+	}
+	return nil, ErrNotRSAPublicKey
+}
@@ -0,0 +1,201 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/control-center/serviced/auth"
+	jwt "github.com/dgrijalva/jwt-go"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&OIDCTestSuite{})
+
+// OIDCTestSuite runs the OIDC provider against a stub issuer
+type OIDCTestSuite struct {
+	key      *rsa.PrivateKey
+	server   *httptest.Server
+	provider *auth.OIDCProvider
+	codes    map[string]string
+	certsOff bool
+}
+
+func (s *OIDCTestSuite) SetUpSuite(c *C) {
+	var err error
+	s.key, err = rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(err, IsNil)
+}
+
+func (s *OIDCTestSuite) SetUpTest(c *C) {
+	s.codes = make(map[string]string)
+	s.certsOff = false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(auth.OIDCDiscovery{
+			Issuer:                s.server.URL,
+			AuthorizationEndpoint: s.server.URL + "/auth",
+			TokenEndpoint:         s.server.URL + "/token",
+			JWKSURI:               s.server.URL + "/certs",
+		})
+	})
+	mux.HandleFunc("/certs", func(w http.ResponseWriter, r *http.Request) {
+		if s.certsOff {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": "key1",
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		token, ok := s.codes[r.PostForm.Get("code")]
+		if !ok || r.PostForm.Get("client_secret") != "secret" {
+			http.Error(w, "invalid_grant", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": token})
+	})
+	s.server = httptest.NewServer(mux)
+	s.provider = auth.NewOIDCProvider(auth.OIDCConfig{
+		Issuer:       s.server.URL + "/",
+		ClientID:     "serviced",
+		ClientSecret: "secret",
+		Audience:     "serviced-api",
+		AdminGroups:  []string{"cc-admins"},
+	})
+}
+
+func (s *OIDCTestSuite) TearDownTest(c *C) {
+	s.server.Close()
+}
+
+func (s *OIDCTestSuite) sign(c *C, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "key1"
+	signed, err := token.SignedString(s.key)
+	c.Assert(err, IsNil)
+	return signed
+}
+
+func (s *OIDCTestSuite) claims(aud interface{}) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                s.server.URL,
+		"aud":                aud,
+		"sub":                "f5a1c7",
+		"preferred_username": "jdoe",
+		"groups":             []string{"users", "cc-admins"},
+		"exp":                time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func (s *OIDCTestSuite) TestParseToken(c *C) {
+	token, err := s.provider.ParseToken(s.sign(c, s.claims([]string{"account", "serviced-api"})))
+	c.Assert(err, IsNil)
+	c.Check(token.User(), Equals, "jdoe")
+	c.Check(token.Groups(), DeepEquals, []string{"users", "cc-admins"})
+	c.Check(token.HasAdminAccess(), Equals, true)
+
+	// a single audience and no admin group
+	claims := s.claims("serviced-api")
+	claims["groups"] = "users"
+	token, err = s.provider.ParseToken(s.sign(c, claims))
+	c.Assert(err, IsNil)
+	c.Check(token.HasAdminAccess(), Equals, false)
+}
+
+func (s *OIDCTestSuite) TestParseTokenInvalid(c *C) {
+	claims := s.claims("other-api")
+	_, err := s.provider.ParseToken(s.sign(c, claims))
+	c.Check(err, Equals, auth.ErrOIDCTokenBadAudience)
+
+	claims = s.claims("serviced-api")
+	claims["iss"] = "https://elsewhere.example.com"
+	_, err = s.provider.ParseToken(s.sign(c, claims))
+	c.Check(err, Equals, auth.ErrOIDCTokenBadIssuer)
+
+	claims = s.claims("serviced-api")
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	_, err = s.provider.ParseToken(s.sign(c, claims))
+	c.Check(err, Equals, auth.ErrOIDCTokenExpired)
+
+	claims = s.claims("serviced-api")
+	delete(claims, "exp")
+	_, err = s.provider.ParseToken(s.sign(c, claims))
+	c.Check(err, Equals, auth.ErrOIDCTokenExpired)
+
+	// signed by someone else
+	other, err := rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(err, IsNil)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, s.claims("serviced-api"))
+	token.Header["kid"] = "key1"
+	signed, err := token.SignedString(other)
+	c.Assert(err, IsNil)
+	_, err = s.provider.ParseToken(signed)
+	c.Check(err, Equals, auth.ErrIdentityTokenBadSig)
+}
+
+func (s *OIDCTestSuite) TestParseTokenKeysUnavailable(c *C) {
+	signed := s.sign(c, s.claims("serviced-api"))
+	s.certsOff = true
+	_, err := s.provider.ParseToken(signed)
+	c.Assert(err, NotNil)
+
+	// a failed fetch does not hold off the next one
+	s.certsOff = false
+	_, err = s.provider.ParseToken(signed)
+	c.Assert(err, IsNil)
+}
+
+func (s *OIDCTestSuite) TestAuthCodeFlow(c *C) {
+	redirect := "https://cc.example.com/oidc/callback"
+	authURL, err := s.provider.AuthCodeURL(redirect, "abc123")
+	c.Assert(err, IsNil)
+	u, err := url.Parse(authURL)
+	c.Assert(err, IsNil)
+	c.Check(u.Path, Equals, "/auth")
+	c.Check(u.Query().Get("client_id"), Equals, "serviced")
+	c.Check(u.Query().Get("redirect_uri"), Equals, redirect)
+	c.Check(u.Query().Get("scope"), Equals, auth.DefaultOIDCScope)
+	c.Check(u.Query().Get("state"), Equals, "abc123")
+
+	// the id token is issued to the client
+	s.codes["code1"] = s.sign(c, s.claims("serviced"))
+	token, err := s.provider.Exchange("code1", redirect)
+	c.Assert(err, IsNil)
+	c.Check(token.User(), Equals, "jdoe")
+	c.Check(token.HasAdminAccess(), Equals, true)
+
+	_, err = s.provider.Exchange("bogus", redirect)
+	c.Check(err, NotNil)
+}
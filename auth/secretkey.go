@@ -0,0 +1,152 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+)
+
+const (
+	// SecretKeyFileName is the location of the master's secret key, relative
+	// to the isvcs path.
+	SecretKeyFileName = ".keys/secret.key"
+
+	secretKeyBytes = 32
+)
+
+var (
+	// ErrNoSecretKey is thrown when the secret key has not been loaded
+	ErrNoSecretKey = errors.New("Cannot retrieve key to encrypt or decrypt secrets")
+	// ErrBadSecretKey is thrown when the secret key file is malformed
+	ErrBadSecretKey = errors.New("Secret key is invalid")
+	// ErrBadCiphertext is thrown when an encrypted secret cannot be decrypted
+	ErrBadCiphertext = errors.New("Unable to decrypt secret")
+
+	secretKey     []byte
+	secretKeyLock sync.RWMutex
+)
+
+// CreateOrLoadSecretKey will load the key used to encrypt tenant secrets
+//  from disk.  If the file does not exist, it will generate a new key and
+//  write it to disk.
+func CreateOrLoadSecretKey(filename string) error {
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		if err = os.MkdirAll(path.Dir(filename), os.ModeDir|0755); err != nil {
+			return err
+		}
+
+		key := make([]byte, secretKeyBytes)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return err
+		}
+		data := base64.StdEncoding.EncodeToString(key)
+		if err := ioutil.WriteFile(filename, []byte(data), 0600); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	key, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return ErrBadSecretKey
+	}
+	return LoadSecretKey(key)
+}
+
+// LoadSecretKey sets the key used to encrypt and decrypt tenant secrets
+func LoadSecretKey(key []byte) error {
+	if len(key) != secretKeyBytes {
+		return ErrBadSecretKey
+	}
+	secretKeyLock.Lock()
+	defer secretKeyLock.Unlock()
+	secretKey = key
+	return nil
+}
+
+func getSecretCipher() (cipher.AEAD, error) {
+	secretKeyLock.RLock()
+	defer secretKeyLock.RUnlock()
+	if secretKey == nil {
+		return nil, ErrNoSecretKey
+	}
+	block, err := aes.NewCipher(secretKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ChecksumKey returns a key, derived from the secret key, for checksums of
+// content that may include decrypted secrets.  Checksums made with it can be
+// stored and compared without revealing the secrets.
+func ChecksumKey() ([]byte, error) {
+	secretKeyLock.RLock()
+	defer secretKeyLock.RUnlock()
+	if secretKey == nil {
+		return nil, ErrNoSecretKey
+	}
+	mac := hmac.New(sha256.New, secretKey)
+	mac.Write([]byte("serviced checksum key"))
+	return mac.Sum(nil), nil
+}
+
+// EncryptSecret encrypts a value with the secret key and returns it base64
+// encoded.
+func EncryptSecret(plaintext string) (string, error) {
+	gcm, err := getSecretCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret decrypts a value that was encrypted by EncryptSecret
+func DecryptSecret(ciphertext string) (string, error) {
+	gcm, err := getSecretCipher()
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return "", ErrBadCiphertext
+	}
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", ErrBadCiphertext
+	}
+	return string(plaintext), nil
+}
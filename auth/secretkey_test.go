@@ -0,0 +1,83 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package auth_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/control-center/serviced/auth"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAuthSuite) TestCreateOrLoadSecretKey(c *C) {
+	keyFile := filepath.Join(c.MkDir(), ".keys", "secret.key")
+
+	err := auth.CreateOrLoadSecretKey(keyFile)
+	c.Assert(err, IsNil)
+	data, err := ioutil.ReadFile(keyFile)
+	c.Assert(err, IsNil)
+
+	ciphertext, err := auth.EncryptSecret("s3cr3t")
+	c.Assert(err, IsNil)
+	c.Assert(ciphertext, Not(Equals), "s3cr3t")
+
+	// Loading the key again must not regenerate it
+	err = auth.CreateOrLoadSecretKey(keyFile)
+	c.Assert(err, IsNil)
+	reloaded, err := ioutil.ReadFile(keyFile)
+	c.Assert(err, IsNil)
+	c.Assert(reloaded, DeepEquals, data)
+
+	plaintext, err := auth.DecryptSecret(ciphertext)
+	c.Assert(err, IsNil)
+	c.Assert(plaintext, Equals, "s3cr3t")
+}
+
+func (s *TestAuthSuite) TestDecryptSecretFailures(c *C) {
+	err := auth.LoadSecretKey([]byte("too short"))
+	c.Assert(err, Equals, auth.ErrBadSecretKey)
+
+	key := make([]byte, 32)
+	c.Assert(auth.LoadSecretKey(key), IsNil)
+	ciphertext, err := auth.EncryptSecret("s3cr3t")
+	c.Assert(err, IsNil)
+
+	_, err = auth.DecryptSecret("not base64!")
+	c.Assert(err, Equals, auth.ErrBadCiphertext)
+
+	// A different key cannot decrypt the value
+	key[0] = 1
+	c.Assert(auth.LoadSecretKey(key), IsNil)
+	_, err = auth.DecryptSecret(ciphertext)
+	c.Assert(err, Equals, auth.ErrBadCiphertext)
+}
+
+func (s *TestAuthSuite) TestChecksumKey(c *C) {
+	key := make([]byte, 32)
+	c.Assert(auth.LoadSecretKey(key), IsNil)
+	checksumKey, err := auth.ChecksumKey()
+	c.Assert(err, IsNil)
+	c.Assert(checksumKey, HasLen, 32)
+	c.Assert(checksumKey, Not(DeepEquals), key)
+
+	// The checksum key follows the secret key
+	key[0] = 1
+	c.Assert(auth.LoadSecretKey(key), IsNil)
+	other, err := auth.ChecksumKey()
+	c.Assert(err, IsNil)
+	c.Assert(other, Not(DeepEquals), checksumKey)
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import api "github.com/control-center/serviced/cli/api"
+import apitoken "github.com/control-center/serviced/domain/apitoken"
 import applicationendpoint "github.com/control-center/serviced/domain/applicationendpoint"
 import dao "github.com/control-center/serviced/dao"
 import host "github.com/control-center/serviced/domain/host"
@@ -10,11 +11,17 @@ import metrics "github.com/control-center/serviced/metrics"
 import mock "github.com/stretchr/testify/mock"
 import pool "github.com/control-center/serviced/domain/pool"
 import script "github.com/control-center/serviced/script"
+import secret "github.com/control-center/serviced/domain/secret"
 import "github.com/control-center/serviced/utils"
+import time "time"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
 import servicetemplate "github.com/control-center/serviced/domain/servicetemplate"
 import volume "github.com/control-center/serviced/volume"
+import webhook "github.com/control-center/serviced/domain/webhook"
+import snapshotschedule "github.com/control-center/serviced/domain/snapshotschedule"
+import zzk "github.com/control-center/serviced/zzk"
+import zkservice "github.com/control-center/serviced/zzk/service"
 
 // API is an autogenerated mock type for the API type
 type API struct {
@@ -1700,3 +1707,556 @@ func (_m *API) GetBackupEstimate(_a0 string, _a1 []string) (*dao.BackupEstimate,
 }
 
 var _ api.API = (*API)(nil)
+
+// SetSecret provides a mock function with given fields: tenantID, name, value
+func (_m *API) SetSecret(tenantID string, name string, value string) error {
+	ret := _m.Called(tenantID, name, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(tenantID, name, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSecrets provides a mock function with given fields: tenantID
+func (_m *API) GetSecrets(tenantID string) ([]secret.Secret, error) {
+	ret := _m.Called(tenantID)
+
+	var r0 []secret.Secret
+	if rf, ok := ret.Get(0).(func(string) []secret.Secret); ok {
+		r0 = rf(tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]secret.Secret)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSecret provides a mock function with given fields: tenantID, name
+func (_m *API) RemoveSecret(tenantID string, name string) error {
+	ret := _m.Called(tenantID, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(tenantID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenderServiceConfigs provides a mock function with given fields: serviceID, instanceID
+func (_m *API) RenderServiceConfigs(serviceID string, instanceID int) ([]servicedefinition.ConfigFile, error) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 []servicedefinition.ConfigFile
+	if rf, ok := ret.Get(0).(func(string, int) []servicedefinition.ConfigFile); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]servicedefinition.ConfigFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceConfigDrift provides a mock function with given fields: serviceID
+func (_m *API) GetServiceConfigDrift(serviceID string) ([]service.ConfigDrift, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.ConfigDrift
+	if rf, ok := ret.Get(0).(func(string) []service.ConfigDrift); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ConfigDrift)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LintServiceTemplate provides a mock function with given fields: _a0
+func (_m *API) LintServiceTemplate(_a0 string) ([]servicedefinition.LintWarning, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []servicedefinition.LintWarning
+	if rf, ok := ret.Get(0).(func(string) []servicedefinition.LintWarning); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]servicedefinition.LintWarning)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceDependencyGraph provides a mock function with given fields: serviceID
+func (_m *API) GetServiceDependencyGraph(serviceID string) (*service.DependencyGraph, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 *service.DependencyGraph
+	if rf, ok := ret.Get(0).(func(string) *service.DependencyGraph); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.DependencyGraph)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UncordonHost provides a mock function with given fields: _a0
+func (_m *API) UncordonHost(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDrainStatus provides a mock function with given fields: _a0
+func (_m *API) GetDrainStatus(_a0 string) (*host.DrainReport, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *host.DrainReport
+	if rf, ok := ret.Get(0).(func(string) *host.DrainReport); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.DrainReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DrainHost provides a mock function with given fields: _a0, _a1
+func (_m *API) DrainHost(_a0 string, _a1 time.Duration) (*host.DrainReport, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *host.DrainReport
+	if rf, ok := ret.Get(0).(func(string, time.Duration) *host.DrainReport); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.DrainReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCoordinatorTree provides a mock function with given fields: pth
+func (_m *API) GetCoordinatorTree(pth string) (*zzk.TreeNode, error) {
+	ret := _m.Called(pth)
+
+	var r0 *zzk.TreeNode
+	if rf, ok := ret.Get(0).(func(string) *zzk.TreeNode); ok {
+		r0 = rf(pth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*zzk.TreeNode)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(pth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckCoordinatorState provides a mock function with given fields: repair
+func (_m *API) CheckCoordinatorState(repair bool) ([]zkservice.Inconsistency, error) {
+	ret := _m.Called(repair)
+
+	var r0 []zkservice.Inconsistency
+	if rf, ok := ret.Get(0).(func(bool) []zkservice.Inconsistency); ok {
+		r0 = rf(repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]zkservice.Inconsistency)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVirtualIPStatus provides a mock function with given fields: _a0
+func (_m *API) GetVirtualIPStatus(_a0 string) ([]zkservice.VirtualIPStatus, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []zkservice.VirtualIPStatus
+	if rf, ok := ret.Get(0).(func(string) []zkservice.VirtualIPStatus); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]zkservice.VirtualIPStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRollingRestartStatus provides a mock function with given fields: serviceID
+func (_m *API) GetRollingRestartStatus(serviceID string) ([]service.RollingRestartStatus, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.RollingRestartStatus
+	if rf, ok := ret.Get(0).(func(string) []service.RollingRestartStatus); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.RollingRestartStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetServiceImage provides a mock function with given fields: serviceID, imageID, rollout
+func (_m *API) SetServiceImage(serviceID string, imageID string, rollout *service.RollingRestart) (bool, error) {
+	ret := _m.Called(serviceID, imageID, rollout)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, *service.RollingRestart) bool); ok {
+		r0 = rf(serviceID, imageID, rollout)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, *service.RollingRestart) error); ok {
+		r1 = rf(serviceID, imageID, rollout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+// CreateAPIToken provides a mock function with given fields: name, scope, expires
+func (_m *API) CreateAPIToken(name string, scope apitoken.Scope, expires time.Time) (string, *apitoken.APIToken, error) {
+	ret := _m.Called(name, scope, expires)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, apitoken.Scope, time.Time) string); ok {
+		r0 = rf(name, scope, expires)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 *apitoken.APIToken
+	if rf, ok := ret.Get(1).(func(string, apitoken.Scope, time.Time) *apitoken.APIToken); ok {
+		r1 = rf(name, scope, expires)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*apitoken.APIToken)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, apitoken.Scope, time.Time) error); ok {
+		r2 = rf(name, scope, expires)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAPITokens provides a mock function with given fields:
+func (_m *API) GetAPITokens() ([]apitoken.APIToken, error) {
+	ret := _m.Called()
+
+	var r0 []apitoken.APIToken
+	if rf, ok := ret.Get(0).(func() []apitoken.APIToken); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]apitoken.APIToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIToken provides a mock function with given fields: idOrName
+func (_m *API) RevokeAPIToken(idOrName string) error {
+	ret := _m.Called(idOrName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(idOrName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddWebhook provides a mock function with given fields: url, events, tenantID, secret
+func (_m *API) AddWebhook(url string, events []string, tenantID string, secret string) (string, *webhook.Webhook, error) {
+	ret := _m.Called(url, events, tenantID, secret)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, []string, string, string) string); ok {
+		r0 = rf(url, events, tenantID, secret)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 *webhook.Webhook
+	if rf, ok := ret.Get(1).(func(string, []string, string, string) *webhook.Webhook); ok {
+		r1 = rf(url, events, tenantID, secret)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*webhook.Webhook)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, []string, string, string) error); ok {
+		r2 = rf(url, events, tenantID, secret)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetWebhooks provides a mock function with given fields:
+func (_m *API) GetWebhooks() ([]webhook.Webhook, error) {
+	ret := _m.Called()
+
+	var r0 []webhook.Webhook
+	if rf, ok := ret.Get(0).(func() []webhook.Webhook); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]webhook.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveWebhook provides a mock function with given fields: id
+func (_m *API) RemoveWebhook(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CloneSnapshot provides a mock function with given fields: snapshotID, name
+func (_m *API) CloneSnapshot(snapshotID string, name string) (string, error) {
+	ret := _m.Called(snapshotID, name)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(snapshotID, name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(snapshotID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiffSnapshots provides a mock function with given fields: snapshotID, toSnapshotID
+func (_m *API) DiffSnapshots(snapshotID string, toSnapshotID string) (*dao.SnapshotDiff, error) {
+	ret := _m.Called(snapshotID, toSnapshotID)
+
+	var r0 *dao.SnapshotDiff
+	if rf, ok := ret.Get(0).(func(string, string) *dao.SnapshotDiff); ok {
+		r0 = rf(snapshotID, toSnapshotID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.SnapshotDiff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(snapshotID, toSnapshotID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetSnapshotSchedule provides a mock function with given fields: serviceID, interval, cron, retention
+func (_m *API) SetSnapshotSchedule(serviceID string, interval time.Duration, cron string, retention snapshotschedule.Retention) (*snapshotschedule.SnapshotSchedule, error) {
+	ret := _m.Called(serviceID, interval, cron, retention)
+
+	var r0 *snapshotschedule.SnapshotSchedule
+	if rf, ok := ret.Get(0).(func(string, time.Duration, string, snapshotschedule.Retention) *snapshotschedule.SnapshotSchedule); ok {
+		r0 = rf(serviceID, interval, cron, retention)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*snapshotschedule.SnapshotSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Duration, string, snapshotschedule.Retention) error); ok {
+		r1 = rf(serviceID, interval, cron, retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSnapshotSchedules provides a mock function with given fields:
+func (_m *API) GetSnapshotSchedules() ([]snapshotschedule.SnapshotSchedule, error) {
+	ret := _m.Called()
+
+	var r0 []snapshotschedule.SnapshotSchedule
+	if rf, ok := ret.Get(0).(func() []snapshotschedule.SnapshotSchedule); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]snapshotschedule.SnapshotSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSnapshotSchedule provides a mock function with given fields: serviceID
+func (_m *API) RemoveSnapshotSchedule(serviceID string) error {
+	ret := _m.Called(serviceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(serviceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
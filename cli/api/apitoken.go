@@ -0,0 +1,51 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/apitoken"
+)
+
+// CreateAPIToken creates a named, scoped API token and returns its bearer
+// token
+func (a *api) CreateAPIToken(name string, scope apitoken.Scope, expires time.Time) (string, *apitoken.APIToken, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return client.CreateAPIToken(name, scope, expires)
+}
+
+// GetAPITokens returns all API tokens
+func (a *api) GetAPITokens() ([]apitoken.APIToken, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetAPITokens()
+}
+
+// RevokeAPIToken revokes an API token by id or name
+func (a *api) RevokeAPIToken(idOrName string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RevokeAPIToken(idOrName)
+}
@@ -22,6 +22,8 @@ import (
 	commonsdocker "github.com/control-center/serviced/commons/docker"
 	"github.com/control-center/serviced/config"
 	coordclient "github.com/control-center/serviced/coordinator/client"
+	coordetcd "github.com/control-center/serviced/coordinator/client/etcd"
+	_ "github.com/control-center/serviced/coordinator/client/memory"
 	coordzk "github.com/control-center/serviced/coordinator/client/zookeeper"
 	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/dao"
@@ -33,13 +35,18 @@ import (
 	"github.com/control-center/serviced/dfs/nfs"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/apitoken"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/properties"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/snapshotschedule"
 	"github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/domain/webhook"
+	"github.com/control-center/serviced/events"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/isvcs"
@@ -169,7 +176,8 @@ func (d *daemon) getEsClusterName(name string) string {
 
 func (d *daemon) startISVCS() {
 	options := config.GetOptions()
-	startZK := options.StartZK
+	// the zookeeper isvc is not needed by the other coordinator drivers
+	startZK := options.StartZK && options.CoordinatorDriver == "zookeeper"
 	bigtable := options.BigTableMetrics
 	isvcs.Init(options.ESStartupTimeout, options.DockerLogDriver, convertStringSliceToMap(options.DockerLogConfigList), d.docker, startZK, bigtable)
 	isvcs.Mgr.SetVolumesDir(options.IsvcsPath)
@@ -316,6 +324,8 @@ func (d *daemon) run() (err error) {
 
 	// Start the RPC server
 	d.startRPC()
+	d.startMetricsServer()
+	tracer := startTraceExporter()
 
 	//Start the zookeeper client
 	localClient, err := d.initZK(options.Zookeepers)
@@ -378,6 +388,10 @@ func (d *daemon) run() (err error) {
 	zzk.ShutdownConnections()
 	log.Info("Disconnected from ZooKeeper")
 
+	if tracer != nil {
+		tracer.Close()
+	}
+
 	switch sig {
 	case syscall.SIGHUP:
 		command := os.Args
@@ -406,6 +420,18 @@ func (d *daemon) initContext() datastore.Context {
 
 func (d *daemon) initZK(zks []string) (*coordclient.Client, error) {
 	options := config.GetOptions()
+	switch options.CoordinatorDriver {
+	case "etcd":
+		dsn := coordetcd.NewDSN(options.EtcdEndpoints, time.Duration(options.ZKSessionTimeout)*time.Second).String()
+		log.WithFields(logrus.Fields{
+			"dsn":            dsn,
+			"sessiontimeout": options.ZKSessionTimeout,
+		}).Debug("Establishing connection to etcd")
+		return coordclient.New("etcd", dsn, "/", nil)
+	case "memory":
+		log.Warn("Using the in-memory coordinator; coordination data will not survive a restart")
+		return coordclient.New("memory", "", "/", nil)
+	}
 	coordzk.RegisterZKLogger()
 	dsn := coordzk.NewDSN(zks,
 		time.Duration(options.ZKSessionTimeout)*time.Second,
@@ -457,6 +483,17 @@ func (d *daemon) startMaster() (err error) {
 
 	keylog.Info("Loaded master keys from disk")
 
+	// Load the key used to encrypt tenant secrets, else generate it
+	secretKeyFile := filepath.Join(options.IsvcsPath, auth.SecretKeyFileName)
+	secretlog := log.WithFields(logrus.Fields{
+		"keyfile": secretKeyFile,
+	})
+	if err = auth.CreateOrLoadSecretKey(secretKeyFile); err != nil {
+		secretlog.WithError(err).Fatal("Unable to load or create secret key")
+	}
+
+	secretlog.Info("Loaded secret key from disk")
+
 	// This is storage related
 	storagelogger := log.WithFields(logrus.Fields{
 		"path":   options.VolumesPath,
@@ -555,6 +592,7 @@ func (d *daemon) startMaster() (err error) {
 	d.initWeb()
 	d.addTemplates()
 	d.startScheduler()
+	d.startWebhooks()
 	d.startPoolListener()
 
 	log.Info("Started serviced master")
@@ -1003,11 +1041,14 @@ func (d *daemon) startAgent() error {
 			LogstashURL:           options.LogstashURL,
 			DockerLogDriver:       options.DockerLogDriver,
 			DockerLogConfig:       convertStringSliceToMap(options.DockerLogConfigList),
+			SeccompProfileDir:     filepath.Join(options.EtcPath, "seccomp"),
 			ZKSessionTimeout:      options.ZKSessionTimeout,
 			ZKConnectTimeout:      options.ZKConnectTimeout,
 			ZKPerHostConnectDelay: options.ZKPerHostConnectDelay,
 			ZKReconnectStartDelay: options.ZKReconnectStartDelay,
 			ZKReconnectMaxDelay:   options.ZKReconnectMaxDelay,
+			CoordinatorDriver:     options.CoordinatorDriver,
+			EtcdEndpoints:         options.EtcdEndpoints,
 			DelegateKeyFile:       delegateKeyFile,
 			TokenFile:             tokenFile,
 		}
@@ -1054,8 +1095,11 @@ func (d *daemon) startAgent() error {
 			}
 		}
 
+		registerInstanceMetrics(poolBasedConn, d.hostID)
+
 		// storage stats (thinpool, etc)
 		if options.Master {
+			registerStorageMetrics()
 			storageStatsDest := fmt.Sprintf("http://%s/api/metrics/store", options.HostStats)
 			storageStatsDuration := time.Second * time.Duration(options.StorageReportInterval)
 			log := log.WithFields(logrus.Fields{
@@ -1205,6 +1249,10 @@ func (d *daemon) initDriver() datastore.Driver {
 	eDriver.AddMapping(addressassignment.MAPPING)
 	eDriver.AddMapping(serviceconfigfile.MAPPING)
 	eDriver.AddMapping(user.MAPPING)
+	eDriver.AddMapping(secret.MAPPING)
+	eDriver.AddMapping(apitoken.MAPPING)
+	eDriver.AddMapping(webhook.MAPPING)
+	eDriver.AddMapping(snapshotschedule.MAPPING)
 	err := eDriver.Initialize(10 * time.Second)
 	if err != nil {
 		log.WithError(err).Fatal("Unable to establish connection to Elastic database")
@@ -1373,6 +1421,15 @@ func (d *daemon) startStorageMonitor() {
 					log.WithError(err).Error("Unable to perform emergency stop of application")
 				} else {
 					log.WithField("numservices", n).Info("Emergency stop initiated")
+					d.facade.PublishEvent(events.Event{
+						Type:      events.EmergencyShutdown,
+						TenantID:  tenant,
+						ServiceID: tenant,
+						Detail: map[string]string{
+							"reason":   "low storage",
+							"services": strconv.Itoa(n),
+						},
+					})
 				}
 			}
 		}
@@ -1430,6 +1487,13 @@ func (d *daemon) initWeb() {
 		options.SnapshotSpacePercent,
 		d.facade)
 
+	if err := web.ConfigureSessions(options.UISessionStore,
+		time.Duration(options.UISessionIdleTimeout)*time.Minute,
+		time.Duration(options.UISessionMaxAge)*time.Minute); err != nil {
+		log.WithError(err).Fatal("Unable to configure UI sessions")
+	}
+	log.WithField("sessionstore", options.UISessionStore).Debug("Configured UI sessions")
+
 	web.SetServiceStatsCacheTimeout(options.SvcStatsCacheTimeout)
 	log.WithFields(logrus.Fields{
 		"cachetimeout": options.SvcStatsCacheTimeout,
@@ -1443,6 +1507,13 @@ func (d *daemon) startScheduler() {
 	go d.runScheduler()
 }
 
+// startWebhooks delivers cluster events to the configured webhooks
+func (d *daemon) startWebhooks() {
+	deadLetterPath := filepath.Join(utils.ServicedLogDir(), "webhook-deadletter.log")
+	log.WithField("deadletterlog", deadLetterPath).Debug("Starting webhook delivery")
+	go d.facade.DeliverWebhooks(d.dsContext, d.shutdown, deadLetterPath)
+}
+
 func (d *daemon) addTemplates() {
 	root := utils.LocalDir("templates")
 	log := log.WithFields(logrus.Fields{
@@ -1548,6 +1619,7 @@ func (d *daemon) runScheduler() {
 func (d *daemon) initServiceStateManager(runLevelTimeout time.Duration) {
 	bssm := servicestatemanager.NewBatchServiceStateManager(d.facade, d.dsContext, runLevelTimeout)
 	d.ssm = bssm
+	registerSchedulerMetrics(bssm)
 	go func() {
 		bssm.Start()
 		log.WithField("leveltimeout", runLevelTimeout).Info("Started service state manager")
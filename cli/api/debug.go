@@ -13,6 +13,11 @@
 
 package api
 
+import (
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
 
 func (a *api) DebugEnableMetrics() (string, error) {
 	client, err := a.connectMaster()
@@ -31,3 +36,24 @@ func (a *api) DebugDisableMetrics() (string, error) {
 
 	return client.DebugDisableMetrics()
 }
+
+// GetCoordinatorTree returns the coordinator's tree at the given path
+func (a *api) GetCoordinatorTree(pth string) (*zzk.TreeNode, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetCoordinatorTree(pth)
+}
+
+// CheckCoordinatorState compares the coordinator with the database and
+// optionally repairs any inconsistencies
+func (a *api) CheckCoordinatorState(repair bool) ([]zkservice.Inconsistency, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.CheckCoordinatorState(repair)
+}
@@ -229,6 +229,37 @@ func (a *api) RemoveHost(id string) error {
 	return client.RemoveHost(id)
 }
 
+// DrainHost puts a host into maintenance mode, moving its service instances
+// and virtual IPs onto other hosts in the pool
+func (a *api) DrainHost(id string, timeout time.Duration) (*host.DrainReport, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.DrainHost(id, timeout)
+}
+
+// GetDrainStatus returns the progress of the most recent drain of a host
+func (a *api) GetDrainStatus(id string) (*host.DrainReport, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetDrainStatus(id)
+}
+
+// UncordonHost takes a host out of maintenance mode
+func (a *api) UncordonHost(id string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.UncordonHost(id)
+}
+
 // Sets the memory allocation for an existing host
 func (a *api) SetHostMemory(config HostUpdateConfig) error {
 	client, err := a.connectMaster()
@@ -15,19 +15,26 @@ package api
 
 import (
 	"io"
+	"time"
 
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/apitoken"
 	"github.com/control-center/serviced/domain/applicationendpoint"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	template "github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/snapshotschedule"
+	"github.com/control-center/serviced/domain/webhook"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/script"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 // API is the intermediary between the command-line interface and the dao layer
@@ -44,6 +51,9 @@ type API interface {
 	AddHost(HostConfig) (*host.Host, []byte, error)
 	AddHostPrivate(HostConfig) (*host.Host, []byte, error)
 	RemoveHost(string) error
+	DrainHost(string, time.Duration) (*host.DrainReport, error)
+	GetDrainStatus(string) (*host.DrainReport, error)
+	UncordonHost(string) error
 	GetHostMemory(string) (*metrics.MemoryUsageStats, error)
 	SetHostMemory(HostUpdateConfig) error
 	GetHostPublicKey(string) ([]byte, error)
@@ -64,6 +74,7 @@ type API interface {
 	GetPoolIPs(string) (*pool.PoolIPs, error)
 	AddVirtualIP(pool.VirtualIP) error
 	RemoveVirtualIP(pool.VirtualIP) error
+	GetVirtualIPStatus(string) ([]zkservice.VirtualIPStatus, error)
 
 	// Services
 	GetAllServiceDetails() ([]service.ServiceDetails, error)
@@ -83,6 +94,11 @@ type API interface {
 	GetEndpoints(serviceID string, reportImports, reportExports, validate bool) ([]applicationendpoint.EndpointReport, error)
 	ResolveServicePath(path string) ([]service.ServiceDetails, error)
 	ClearEmergency(serviceID string) (int, error)
+	RenderServiceConfigs(serviceID string, instanceID int) ([]servicedefinition.ConfigFile, error)
+	GetServiceConfigDrift(serviceID string) ([]service.ConfigDrift, error)
+	GetServiceDependencyGraph(serviceID string) (*service.DependencyGraph, error)
+	GetRollingRestartStatus(serviceID string) ([]service.RollingRestartStatus, error)
+	SetServiceImage(serviceID, imageID string, rollout *service.RollingRestart) (bool, error)
 	RemoveIP(args []string) error
 	SetIP(IPConfig) error
 
@@ -97,8 +113,13 @@ type API interface {
 	AddSnapshot(SnapshotConfig) (string, error)
 	RemoveSnapshot(string) error
 	Rollback(string, bool) error
+	CloneSnapshot(snapshotID, name string) (string, error)
+	DiffSnapshots(snapshotID, toSnapshotID string) (*dao.SnapshotDiff, error)
 	TagSnapshot(string, string) error
 	RemoveSnapshotTag(string, string) (string, error)
+	SetSnapshotSchedule(serviceID string, interval time.Duration, cron string, retention snapshotschedule.Retention) (*snapshotschedule.SnapshotSchedule, error)
+	GetSnapshotSchedules() ([]snapshotschedule.SnapshotSchedule, error)
+	RemoveSnapshotSchedule(serviceID string) error
 
 	// Templates
 	GetServiceTemplates() ([]template.ServiceTemplate, error)
@@ -106,8 +127,24 @@ type API interface {
 	AddServiceTemplate(io.Reader) (*template.ServiceTemplate, error)
 	RemoveServiceTemplate(string) error
 	CompileServiceTemplate(CompileTemplateConfig) (*template.ServiceTemplate, error)
+	LintServiceTemplate(string) ([]servicedefinition.LintWarning, error)
 	DeployServiceTemplate(DeployTemplateConfig) ([]service.ServiceDetails, error)
 
+	// Secrets
+	SetSecret(tenantID, name, value string) error
+	GetSecrets(tenantID string) ([]secret.Secret, error)
+	RemoveSecret(tenantID, name string) error
+
+	// API tokens
+	CreateAPIToken(name string, scope apitoken.Scope, expires time.Time) (string, *apitoken.APIToken, error)
+	GetAPITokens() ([]apitoken.APIToken, error)
+	RevokeAPIToken(idOrName string) error
+
+	// Webhooks
+	AddWebhook(url string, events []string, tenantID, secret string) (string, *webhook.Webhook, error)
+	GetWebhooks() ([]webhook.Webhook, error)
+	RemoveWebhook(id string) error
+
 	// Backup & Restore
 	GetBackupEstimate(string, []string) (*dao.BackupEstimate, error)
 	Backup(string, []string, bool) (string, error)
@@ -151,4 +188,6 @@ type API interface {
 	// Debug Management
 	DebugEnableMetrics() (string, error)
 	DebugDisableMetrics() (string, error)
+	GetCoordinatorTree(pth string) (*zzk.TreeNode, error)
+	CheckCoordinatorState(repair bool) ([]zkservice.Inconsistency, error)
 }
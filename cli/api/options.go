@@ -68,6 +68,31 @@ func ValidateServerOptions(options *config.Options) error {
 		return err
 	}
 
+	switch options.CoordinatorDriver {
+	case "zookeeper", "etcd":
+	case "memory":
+		// the in-process store cannot be shared with other hosts, nor with
+		// the service containers, which reach the coordinator over the network
+		if !options.Master || options.Agent {
+			return fmt.Errorf("The memory coordinator driver is only supported on a master that does not run services")
+		}
+	default:
+		return fmt.Errorf("Invalid coordinator driver %q", options.CoordinatorDriver)
+	}
+
+	if len(options.OIDCIssuer) > 0 && len(options.OIDCClientID) == 0 {
+		return fmt.Errorf("An OIDC client id is required when an OIDC issuer is configured")
+	}
+
+	switch options.UISessionStore {
+	case "coordinator", "memory":
+	default:
+		return fmt.Errorf("Invalid UI session store %q", options.UISessionStore)
+	}
+	if options.UISessionIdleTimeout <= 0 {
+		return fmt.Errorf("The UI session idle timeout must be a positive number of minutes")
+	}
+
 	// Make sure we have an endpoint to work with
 	if len(options.Endpoint) == 0 {
 		if options.Master {
@@ -158,6 +183,8 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		LogstashMaxSize:            cfg.IntVal("LOGSTASH_MAX_SIZE", 10),
 		LogstashCycleTime:          cfg.IntVal("LOGSTASH_CYCLE_TIME", 6),
 		DebugPort:                  cfg.IntVal("DEBUG_PORT", 6006),
+		MetricsPort:                cfg.IntVal("METRICS_PORT", 0),
+		TraceCollector:             cfg.StringVal("TRACE_COLLECTOR", ""),
 		AdminGroup:                 cfg.StringVal("ADMIN_GROUP", getDefaultAdminGroup()),
 		MaxRPCClients:              cfg.IntVal("MAX_RPC_CLIENTS", 3),
 		MUXTLSCiphers:              cfg.StringSlice("MUX_TLS_CIPHERS", utils.GetDefaultCiphers("mux")),
@@ -178,6 +205,9 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		DockerLogConfigList:        cfg.StringSlice("DOCKER_LOG_CONFIG", []string{"max-file=5", "max-size=10m"}),
 		AllowLoopBack:              strconv.FormatBool(cfg.BoolVal("ALLOW_LOOP_BACK", false)),
 		UIPollFrequency:            cfg.IntVal("UI_POLL_FREQUENCY", 3),
+		UISessionStore:             cfg.StringVal("UI_SESSION_STORE", "coordinator"),
+		UISessionIdleTimeout:       cfg.IntVal("UI_SESSION_IDLE_TIMEOUT", 30),
+		UISessionMaxAge:            cfg.IntVal("UI_SESSION_MAX_AGE", 720),
 		ConntrackFlush:             strconv.FormatBool(cfg.BoolVal("CONNTRACK_FLUSH", false)),
 		StorageStatsUpdateInterval: cfg.IntVal("STORAGE_STATS_UPDATE_INTERVAL", 300),
 		SnapshotSpacePercent:       cfg.IntVal("SNAPSHOT_USE_PERCENT", 20),
@@ -186,6 +216,8 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		ZKPerHostConnectDelay:      cfg.IntVal("ZK_PER_HOST_CONNECT_DELAY", 0),
 		ZKReconnectStartDelay:      cfg.IntVal("ZK_RECONNECT_START_DELAY", 1),
 		ZKReconnectMaxDelay:        cfg.IntVal("ZK_RECONNECT_MAX_DELAY", 1),
+		CoordinatorDriver:          cfg.StringVal("COORDINATOR_DRIVER", "zookeeper"),
+		EtcdEndpoints:              cfg.StringSlice("ETCD", []string{}),
 		TokenExpiration:            cfg.IntVal("AUTH_TOKEN_EXPIRATION", 60*60),
 		ServiceRunLevelTimeout:     cfg.IntVal("RUN_LEVEL_TIMEOUT", 60*10),
 		StorageReportInterval:      cfg.IntVal("STORAGE_REPORT_INTERVAL", 30),
@@ -200,6 +232,16 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		Auth0Group:    cfg.StringVal("AUTH0_GROUP", ""),
 		Auth0ClientID: cfg.StringVal("AUTH0_CLIENT_ID", ""),
 		Auth0Scope:    cfg.StringVal("AUTH0_SCOPE", ""),
+		// Generic OpenID Connect configuration parameters.  Login through an
+		// OIDC provider is enabled by setting the issuer and client id.
+		OIDCIssuer:       cfg.StringVal("OIDC_ISSUER", ""),
+		OIDCClientID:     cfg.StringVal("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret: cfg.StringVal("OIDC_CLIENT_SECRET", ""),
+		OIDCAudience:     cfg.StringVal("OIDC_AUDIENCE", ""),
+		OIDCScope:        cfg.StringVal("OIDC_SCOPE", "openid profile email"),
+		OIDCRedirectURL:  cfg.StringVal("OIDC_REDIRECT_URL", ""),
+		OIDCGroupsClaim:  cfg.StringVal("OIDC_GROUPS_CLAIM", "groups"),
+		OIDCAdminGroups:  cfg.StringVal("OIDC_ADMIN_GROUPS", ""),
 	}
 
 	options.Endpoint = cfg.StringVal("ENDPOINT", "")
@@ -123,6 +123,88 @@ func (s *TestAPISuite) TestValidateServerOptionsSetsEndpointIfMasterMissingEndpo
 	c.Assert(len(config.GetOptions().Endpoint), Not(Equals), 0)
 }
 
+func (s *TestAPISuite) TestValidateServerOptionsCoordinatorDriver(c *C) {
+	configReader := utils.TestConfigReader(map[string]string{})
+	testOptions := GetDefaultOptions(configReader)
+	testOptions.Master = true
+	testOptions.FSType = volume.DriverTypeBtrFS
+	config.LoadOptions(testOptions)
+	c.Assert(testOptions.CoordinatorDriver, Equals, "zookeeper")
+
+	testOptions.CoordinatorDriver = "etcd"
+	c.Assert(ValidateServerOptions(&testOptions), IsNil)
+
+	testOptions.CoordinatorDriver = "memory"
+	c.Assert(ValidateServerOptions(&testOptions), IsNil)
+
+	testOptions.CoordinatorDriver = "consul"
+	err := ValidateServerOptions(&testOptions)
+	s.assertErrorContent(c, err, `Invalid coordinator driver "consul"`)
+}
+
+func (s *TestAPISuite) TestValidateServerOptionsFailsIfAgentUsesMemoryDriver(c *C) {
+	configReader := utils.TestConfigReader(map[string]string{})
+	testOptions := GetDefaultOptions(configReader)
+	testOptions.Agent = true
+	testOptions.FSType = volume.DriverTypeBtrFS
+	testOptions.Endpoint = "10.0.0.1:4979"
+	testOptions.CoordinatorDriver = "memory"
+	config.LoadOptions(testOptions)
+
+	err := ValidateServerOptions(&testOptions)
+	s.assertErrorContent(c, err, "only supported on a master that does not run services")
+}
+
+func (s *TestAPISuite) TestValidateServerOptionsFailsIfMasterAgentUsesMemoryDriver(c *C) {
+	configReader := utils.TestConfigReader(map[string]string{})
+	testOptions := GetDefaultOptions(configReader)
+	testOptions.Master = true
+	testOptions.Agent = true
+	testOptions.FSType = volume.DriverTypeBtrFS
+	testOptions.CoordinatorDriver = "memory"
+	config.LoadOptions(testOptions)
+
+	err := ValidateServerOptions(&testOptions)
+	s.assertErrorContent(c, err, "only supported on a master that does not run services")
+}
+
+func (s *TestAPISuite) TestValidateServerOptionsFailsIfOIDCMissingClientID(c *C) {
+	configReader := utils.TestConfigReader(map[string]string{
+		"OIDC_ISSUER": "https://idp.example.com/realms/cc",
+	})
+	testOptions := GetDefaultOptions(configReader)
+	testOptions.Master = true
+	testOptions.FSType = volume.DriverTypeBtrFS
+	config.LoadOptions(testOptions)
+
+	err := ValidateServerOptions(&testOptions)
+	s.assertErrorContent(c, err, "An OIDC client id is required")
+
+	testOptions.OIDCClientID = "serviced"
+	c.Assert(ValidateServerOptions(&testOptions), IsNil)
+}
+
+func (s *TestAPISuite) TestValidateServerOptionsUISessions(c *C) {
+	configReader := utils.TestConfigReader(map[string]string{
+		"UI_SESSION_STORE": "redis",
+	})
+	testOptions := GetDefaultOptions(configReader)
+	testOptions.Master = true
+	testOptions.FSType = volume.DriverTypeBtrFS
+	config.LoadOptions(testOptions)
+
+	err := ValidateServerOptions(&testOptions)
+	s.assertErrorContent(c, err, "Invalid UI session store")
+
+	testOptions.UISessionStore = "memory"
+	testOptions.UISessionIdleTimeout = 0
+	err = ValidateServerOptions(&testOptions)
+	s.assertErrorContent(c, err, "UI session idle timeout")
+
+	testOptions.UISessionIdleTimeout = 30
+	c.Assert(ValidateServerOptions(&testOptions), IsNil)
+}
+
 func (s *TestAPISuite) assertErrorContent(c *C, err error, expectedContent string) {
 	c.Assert(err, Not(IsNil))
 	if !strings.Contains(err.Error(), expectedContent) {
@@ -15,6 +15,7 @@ package api
 
 import (
 	"github.com/control-center/serviced/domain/pool"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 const ()
@@ -121,3 +122,13 @@ func (a *api) RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error {
 
 	return client.RemoveVirtualIP(requestVirtualIP)
 }
+
+// Returns the owner and failover history of the VirtualIPs in a pool
+func (a *api) GetVirtualIPStatus(poolID string) ([]zkservice.VirtualIPStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetVirtualIPStatus(poolID)
+}
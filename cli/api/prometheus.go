@@ -0,0 +1,135 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	coordclient "github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/metrics/prometheus"
+	"github.com/control-center/serviced/scheduler/servicestatemanager"
+	"github.com/control-center/serviced/volume"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// startMetricsServer serves this process's metrics at /metrics in the
+// Prometheus exposition format, if a metrics port is configured
+func (d *daemon) startMetricsServer() {
+	options := config.GetOptions()
+	if options.MetricsPort <= 0 {
+		return
+	}
+	address := fmt.Sprintf(":%d", options.MetricsPort)
+	logger := log.WithFields(logrus.Fields{
+		"server":  "metrics",
+		"address": address,
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", prometheus.Handler(prometheus.DefaultRegistry))
+	go func() {
+		logger.Info("Listening for incoming metrics scrapes")
+		if err := http.ListenAndServe(address, mux); err != nil {
+			logger.WithError(err).Warning("Unable to bind to metrics port. Is another instance running?")
+		}
+	}()
+}
+
+// registerStorageMetrics exports the usage reported by the storage drivers,
+// under the same metric names that are posted to OpenTSDB
+func registerStorageMetrics() {
+	prometheus.Register(prometheus.NewGaugeFunc("serviced_storage_usage",
+		"Application storage usage reported by the volume drivers, by OpenTSDB metric name",
+		func() []prometheus.Sample {
+			var samples []prometheus.Sample
+			statuses := volume.GetStatus()
+			if statuses == nil {
+				return samples
+			}
+			for path, status := range statuses.GetAllStatuses() {
+				for _, usage := range status.GetUsageData() {
+					if usage.GetMetricName() == "" {
+						continue
+					}
+					value, ok := usageValue(usage)
+					if !ok {
+						continue
+					}
+					samples = append(samples, prometheus.Sample{
+						Labels: prometheus.Labels{"path": path, "metric": usage.GetMetricName()},
+						Value:  value,
+					})
+				}
+			}
+			return samples
+		}))
+}
+
+// usageValue returns a storage usage as a float, whichever type it was
+// reported as
+func usageValue(usage volume.Usage) (float64, bool) {
+	value, err := usage.GetValueUInt64()
+	if err == nil {
+		return float64(value), true
+	} else if err != volume.ErrWrongDataType {
+		return 0, false
+	}
+	fvalue, err := usage.GetValueFloat64()
+	return fvalue, err == nil
+}
+
+// registerInstanceMetrics exports the number of service instances on this
+// host by the status of their containers
+func registerInstanceMetrics(conn coordclient.Connection, hostID string) {
+	prometheus.Register(prometheus.NewGaugeFunc("serviced_instances",
+		"Service instances scheduled on this host, by container status",
+		func() []prometheus.Sample {
+			states, err := zkservice.GetHostStates(conn, "", hostID)
+			if err != nil {
+				log.WithError(err).Debug("Could not count instances for metrics")
+				return nil
+			}
+			counts := make(map[string]int)
+			for _, state := range states {
+				counts[string(state.Status)]++
+			}
+			samples := make([]prometheus.Sample, 0, len(counts))
+			for status, count := range counts {
+				samples = append(samples, prometheus.Sample{
+					Labels: prometheus.Labels{"status": status},
+					Value:  float64(count),
+				})
+			}
+			return samples
+		}))
+}
+
+// registerSchedulerMetrics exports how many services are waiting in the
+// service state manager's queues
+func registerSchedulerMetrics(ssm *servicestatemanager.BatchServiceStateManager) {
+	prometheus.Register(prometheus.NewGaugeFunc("serviced_scheduler_queued_services",
+		"Services waiting to be scheduled, by desired state",
+		func() []prometheus.Sample {
+			var samples []prometheus.Sample
+			for desiredState, depth := range ssm.QueueDepths() {
+				samples = append(samples, prometheus.Sample{
+					Labels: prometheus.Labels{"desired_state": desiredState.String()},
+					Value:  float64(depth),
+				})
+			}
+			return samples
+		}))
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/secret"
+)
+
+// SetSecret sets the value of a tenant secret
+func (a *api) SetSecret(tenantID, name, value string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.SetSecret(tenantID, name, value)
+}
+
+// GetSecrets returns the secrets of a tenant without their values
+func (a *api) GetSecrets(tenantID string) ([]secret.Secret, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetSecrets(tenantID)
+}
+
+// RemoveSecret removes a tenant secret
+func (a *api) RemoveSecret(tenantID, name string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RemoveSecret(tenantID, name)
+}
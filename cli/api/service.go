@@ -48,9 +48,10 @@ type ServiceConfig struct {
 }
 
 type SchedulerConfig struct {
-	ServiceIDs  []string
-	AutoLaunch  bool
-	Synchronous bool
+	ServiceIDs     []string
+	AutoLaunch     bool
+	Synchronous    bool
+	RollingRestart *service.RollingRestart
 }
 
 // IPConfig is the deserialized object from the command-line
@@ -342,7 +343,7 @@ func (a *api) StartService(config SchedulerConfig) (int, error) {
 	}
 
 	var affected int
-	err = client.StartService(dao.ScheduleServiceRequest{config.ServiceIDs, config.AutoLaunch, config.Synchronous}, &affected)
+	err = client.StartService(dao.ScheduleServiceRequest{ServiceIDs: config.ServiceIDs, AutoLaunch: config.AutoLaunch, Synchronous: config.Synchronous}, &affected)
 	return affected, err
 }
 
@@ -354,7 +355,7 @@ func (a *api) RestartService(config SchedulerConfig) (int, error) {
 	}
 
 	var affected int
-	err = client.RestartService(dao.ScheduleServiceRequest{config.ServiceIDs, config.AutoLaunch, config.Synchronous}, &affected)
+	err = client.RestartService(dao.ScheduleServiceRequest{ServiceIDs: config.ServiceIDs, AutoLaunch: config.AutoLaunch, Synchronous: config.Synchronous, RollingRestart: config.RollingRestart}, &affected)
 	return affected, err
 }
 
@@ -366,7 +367,7 @@ func (a *api) RebalanceService(config SchedulerConfig) (int, error) {
 	}
 
 	var affected int
-	err = client.RebalanceService(dao.ScheduleServiceRequest{config.ServiceIDs, config.AutoLaunch, config.Synchronous}, &affected)
+	err = client.RebalanceService(dao.ScheduleServiceRequest{ServiceIDs: config.ServiceIDs, AutoLaunch: config.AutoLaunch, Synchronous: config.Synchronous}, &affected)
 	return affected, err
 }
 
@@ -378,7 +379,7 @@ func (a *api) StopService(config SchedulerConfig) (int, error) {
 	}
 
 	var affected int
-	err = client.StopService(dao.ScheduleServiceRequest{config.ServiceIDs, config.AutoLaunch, config.Synchronous}, &affected)
+	err = client.StopService(dao.ScheduleServiceRequest{ServiceIDs: config.ServiceIDs, AutoLaunch: config.AutoLaunch, Synchronous: config.Synchronous}, &affected)
 	return affected, err
 }
 
@@ -390,7 +391,7 @@ func (a *api) PauseService(config SchedulerConfig) (int, error) {
 	}
 
 	var affected int
-	err = client.PauseService(dao.ScheduleServiceRequest{config.ServiceIDs, config.AutoLaunch, config.Synchronous}, &affected)
+	err = client.PauseService(dao.ScheduleServiceRequest{ServiceIDs: config.ServiceIDs, AutoLaunch: config.AutoLaunch, Synchronous: config.Synchronous}, &affected)
 	return affected, err
 }
 
@@ -479,3 +480,58 @@ func (a *api) ClearEmergency(serviceID string) (int, error) {
 
 	return client.ClearEmergency(serviceID)
 }
+
+// RenderServiceConfigs returns the config files of a service instance as they
+// would be rendered now
+func (a *api) RenderServiceConfigs(serviceID string, instanceID int) ([]servicedefinition.ConfigFile, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.RenderServiceConfigs(serviceID, instanceID)
+}
+
+// GetServiceConfigDrift returns the config files of running instances that
+// differ from what would be rendered now
+func (a *api) GetServiceConfigDrift(serviceID string) ([]service.ConfigDrift, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetServiceConfigDrift(serviceID)
+}
+
+// GetServiceDependencyGraph returns the endpoint dependency graph of the
+// tenant that the service belongs to
+func (a *api) GetServiceDependencyGraph(serviceID string) (*service.DependencyGraph, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetServiceDependencyGraph(serviceID)
+}
+
+// GetRollingRestartStatus returns the progress of the most recent rolling
+// restart of a service, or of all services if serviceID is empty
+func (a *api) GetRollingRestartStatus(serviceID string) ([]service.RollingRestartStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetRollingRestartStatus(serviceID)
+}
+
+// SetServiceImage changes the image of a service, rolling it out to the
+// running instances if rollout is set.  Returns true if a rollout was started.
+func (a *api) SetServiceImage(serviceID, imageID string, rollout *service.RollingRestart) (bool, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return false, err
+	}
+
+	return client.SetServiceImage(serviceID, imageID, rollout)
+}
@@ -131,6 +131,29 @@ func (a *api) Rollback(snapshotID string, forceRestart bool) error {
 	return nil
 }
 
+// CloneSnapshot creates a new application with the given name from a
+// snapshot, and returns the id of its tenant
+func (a *api) CloneSnapshot(snapshotID, name string) (string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+
+	return client.CloneSnapshot(snapshotID, name)
+}
+
+// DiffSnapshots returns the files and services that differ going from a
+// snapshot to another snapshot, or to the current state of the application
+// if toSnapshotID is empty
+func (a *api) DiffSnapshots(snapshotID, toSnapshotID string) (*dao.SnapshotDiff, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.DiffSnapshots(snapshotID, toSnapshotID)
+}
+
 // TagSnapshot tags an existing snapshot with 1 or more strings
 func (a *api) TagSnapshot(snapshotID string, tagName string) error {
 	client, err := a.connectDAO()
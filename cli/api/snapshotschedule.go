@@ -0,0 +1,51 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/snapshotschedule"
+)
+
+// SetSnapshotSchedule sets when the tenant of a service is snapshotted and
+// which of its snapshots are kept
+func (a *api) SetSnapshotSchedule(serviceID string, interval time.Duration, cron string, retention snapshotschedule.Retention) (*snapshotschedule.SnapshotSchedule, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SetSnapshotSchedule(serviceID, interval, cron, retention)
+}
+
+// GetSnapshotSchedules returns the snapshot schedules of all tenants
+func (a *api) GetSnapshotSchedules() ([]snapshotschedule.SnapshotSchedule, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetSnapshotSchedules()
+}
+
+// RemoveSnapshotSchedule stops scheduled snapshots of the tenant of a service
+func (a *api) RemoveSnapshotSchedule(serviceID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RemoveSnapshotSchedule(serviceID)
+}
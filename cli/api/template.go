@@ -17,6 +17,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"sort"
 
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
@@ -29,6 +32,7 @@ type DeployTemplateConfig struct {
 	PoolID          string
 	DeploymentID    string
 	ManualAssignIPs bool
+	Parameters      map[string]string
 }
 
 // CompileTemplateConfig is the configuration object to conpile a template directory
@@ -135,6 +139,45 @@ func (a *api) CompileServiceTemplate(config CompileTemplateConfig) (*template.Se
 	return st, nil
 }
 
+// LintServiceTemplate reports likely mistakes in a directory of service
+// definitions or a compiled template file
+func (a *api) LintServiceTemplate(path string) ([]servicedefinition.LintWarning, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if fi.IsDir() {
+		sd, err := servicedefinition.BuildFromPath(path)
+		if err != nil {
+			return nil, err
+		}
+		// subservices are loaded in no particular order
+		sortServiceDefinitions(sd.Services)
+		return servicedefinition.Lint(sd), nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var st template.ServiceTemplate
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	if err := st.ValidEntity(); err != nil {
+		return nil, err
+	}
+	return servicedefinition.LintServices(st.Services), nil
+}
+
+func sortServiceDefinitions(sds []servicedefinition.ServiceDefinition) {
+	sort.Sort(servicedefinition.ServiceDefinitionByName(sds))
+	for i := range sds {
+		sortServiceDefinitions(sds[i].Services)
+	}
+}
+
 // DeployTemplate deploys a template given its template ID
 func (a *api) DeployServiceTemplate(config DeployTemplateConfig) ([]service.ServiceDetails, error) {
 	client, err := a.connectMaster()
@@ -146,6 +189,7 @@ func (a *api) DeployServiceTemplate(config DeployTemplateConfig) ([]service.Serv
 		PoolID:       config.PoolID,
 		TemplateID:   config.ID,
 		DeploymentID: config.DeploymentID,
+		Parameters:   config.Parameters,
 	}
 
 	ids, err := client.DeployTemplate(req);
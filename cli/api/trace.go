@@ -0,0 +1,35 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/trace"
+)
+
+// startTraceExporter sends the spans recorded by this process to the
+// configured trace collector.  It returns nil if no collector is configured.
+func startTraceExporter() *trace.CollectorExporter {
+	options := config.GetOptions()
+	if options.TraceCollector == "" {
+		return nil
+	}
+	exporter := trace.NewCollectorExporter(options.TraceCollector, "serviced")
+	trace.SetExporter(exporter)
+	log.WithFields(logrus.Fields{
+		"collector": options.TraceCollector,
+	}).Info("Exporting request traces")
+	return exporter
+}
@@ -0,0 +1,49 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/webhook"
+)
+
+// AddWebhook subscribes a URL to cluster events and returns the secret that
+// signs deliveries
+func (a *api) AddWebhook(url string, events []string, tenantID, secret string) (string, *webhook.Webhook, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return client.AddWebhook(url, events, tenantID, secret)
+}
+
+// GetWebhooks returns all webhooks
+func (a *api) GetWebhooks() ([]webhook.Webhook, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetWebhooks()
+}
+
+// RemoveWebhook deletes a webhook
+func (a *api) RemoveWebhook(id string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RemoveWebhook(id)
+}
@@ -18,6 +18,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 
 	"github.com/Sirupsen/logrus"
@@ -27,6 +28,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/servicedversion"
+	"github.com/control-center/serviced/trace"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume"
 	"github.com/control-center/serviced/volume/nfs"
@@ -43,6 +45,8 @@ type ServicedCli struct {
 	config       utils.ConfigReader
 	logControl   logging.LogControl
 	exitDisabled bool
+	span         *trace.Span              // root of the trace of this command
+	tracer       *trace.CollectorExporter // sends spans to the trace collector, if configured
 }
 
 // New instantiates a new command-line client
@@ -111,6 +115,8 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.StringFlag{"mc-password", defaultOps.MCPasswd, "Password for the Zenoss metric consumer"},
 		cli.StringFlag{"cpuprofile", defaultOps.CPUProfile, "write cpu profile to file"},
 		cli.IntFlag{"debug-port", defaultOps.DebugPort, "Port on which to listen for profiler connections"},
+		cli.IntFlag{"metrics-port", defaultOps.MetricsPort, "Port on which to serve /metrics for Prometheus (0 to disable)"},
+		cli.StringFlag{"trace-collector", defaultOps.TraceCollector, "OpenTelemetry collector URL to export request traces to, e.g. http://localhost:4318/v1/traces"},
 		cli.IntFlag{"max-rpc-clients", defaultOps.MaxRPCClients, "max number of rpc clients to an endpoint"},
 		cli.IntFlag{"rpc-dial-timeout", defaultOps.RPCDialTimeout, "timeout for creating rpc connections"},
 		cli.StringFlag{"rpc-cert-verify", defaultOps.RPCCertVerify, "enable verification of rpc server certificate"},
@@ -124,12 +130,17 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.StringSliceFlag{"log-config", convertToStringSlice(defaultOps.DockerLogConfigList), "comma-separated list of key=value settings for docker log driver"},
 
 		cli.IntFlag{"ui-poll-frequency", defaultOps.UIPollFrequency, "frequency in seconds that the UI polls serviced for changes"},
+		cli.StringFlag{"ui-session-store", defaultOps.UISessionStore, "where UI sessions are kept so they survive restarts (coordinator, memory)"},
+		cli.IntFlag{"ui-session-idle-timeout", defaultOps.UISessionIdleTimeout, "minutes a UI session may go unused before it expires"},
+		cli.IntFlag{"ui-session-max-age", defaultOps.UISessionMaxAge, "minutes a UI session may last however often it is used, 0 for no limit"},
 		cli.IntFlag{"storage-stats-update-interval", defaultOps.StorageStatsUpdateInterval, "frequency in seconds that the thin pool usage will be analyzed"},
 		cli.IntFlag{"zk-session-timeout", defaultOps.ZKSessionTimeout, "zookeeper session timeout in seconds"},
 		cli.IntFlag{"zk-connection-timeout", defaultOps.ZKConnectTimeout, "zookeeper connection timeout in seconds"},
 		cli.IntFlag{"zk-per-host-connect-delay", defaultOps.ZKPerHostConnectDelay, "zookeeper per-host delay in seconds"},
 		cli.IntFlag{"zk-reconnect-start-delay", defaultOps.ZKReconnectStartDelay, "zookeeper initial reconnect delay in seconds"},
 		cli.IntFlag{"zk-reconnect-max-delay", defaultOps.ZKReconnectMaxDelay, "zookeeper max recoonect delay in seconds"},
+		cli.StringFlag{"coordinator-driver", defaultOps.CoordinatorDriver, "coordination service driver (zookeeper, etcd or memory)"},
+		cli.StringSliceFlag{"etcd", convertToStringSlice(defaultOps.EtcdEndpoints), "Specify an etcd endpoint to connect to when using the etcd coordinator driver (e.g. -etcd http://localhost:2379)"},
 		cli.IntFlag{"auth-token-expiry", defaultOps.TokenExpiration, "authentication token expiration in seconds"},
 		cli.StringFlag{"conntrack-flush", defaultOps.ConntrackFlush, "whether to flush the conntrack table when a service with an assigned IP is started"},
 		cli.IntFlag{"service-run-level-timeout", defaultOps.ServiceRunLevelTimeout, "max time in seconds to wait for services to start/stop before moving on to services at the next run level"},
@@ -160,6 +171,14 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.StringFlag{"auth0-group", defaultOps.Auth0Group, "Group configured for application in Auth0"},
 		cli.StringFlag{"auth0-client-id", defaultOps.Auth0ClientID, "Client ID of Auth0 application"},
 		cli.StringFlag{"auth0-scope", defaultOps.Auth0Scope, "Scope to request in Auth0"},
+		cli.StringFlag{"oidc-issuer", defaultOps.OIDCIssuer, "URL of an OpenID Connect provider to log in with"},
+		cli.StringFlag{"oidc-client-id", defaultOps.OIDCClientID, "Client ID registered with the OIDC provider"},
+		cli.StringFlag{"oidc-client-secret", defaultOps.OIDCClientSecret, "Client secret registered with the OIDC provider"},
+		cli.StringFlag{"oidc-audience", defaultOps.OIDCAudience, "Audience required of OIDC bearer tokens, defaults to the client ID"},
+		cli.StringFlag{"oidc-scope", defaultOps.OIDCScope, "Scope to request from the OIDC provider"},
+		cli.StringFlag{"oidc-redirect-url", defaultOps.OIDCRedirectURL, "URL the OIDC provider redirects to after login"},
+		cli.StringFlag{"oidc-groups-claim", defaultOps.OIDCGroupsClaim, "Name of the OIDC token claim that lists the user's groups"},
+		cli.StringFlag{"oidc-admin-groups", defaultOps.OIDCAdminGroups, "Comma-separated OIDC groups whose members are granted admin access"},
 	}
 
 	c.initVersion()
@@ -178,6 +197,9 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 	c.initServer()
 	c.initVolume()
 	c.initKey()
+	c.initSecret()
+	c.initToken()
+	c.initWebhook()
 	c.initDebug()
 
 	return c
@@ -185,9 +207,11 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 
 // Run builds the command-line interface for serviced and runs.
 func (c *ServicedCli) Run(args []string) {
-	if err := c.app.Run(args); err != nil {
+	err := c.app.Run(args)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 	}
+	c.endTrace(err)
 }
 
 // cmdInit is executed before EVERY CLI command/subcommand. Any messages output by this
@@ -210,13 +234,54 @@ func (c *ServicedCli) cmdInit(ctx *cli.Context) error {
 		fmt.Fprintf(os.Stderr, "Unable to set logging options: %s\n", err)
 	}
 
+	// The daemon traces the requests it receives, not its whole lifetime
+	if ctx.Args().First() != "server" {
+		c.startTrace(options, ctx.Args())
+	}
+
 	return nil
 }
 
+// startTrace makes the command the root of a trace, so the requests it makes
+// can be followed through the master and agents.  Commands are only traced if
+// a trace collector is configured.
+func (c *ServicedCli) startTrace(options config.Options, args []string) {
+	if options.TraceCollector == "" {
+		return
+	}
+	c.tracer = trace.NewCollectorExporter(options.TraceCollector, "serviced-cli")
+	trace.SetExporter(c.tracer)
+
+	// Name the span after the command; arguments and flags may hold secrets
+	name := []string{"serviced"}
+	for _, arg := range args {
+		if len(name) > 2 || strings.HasPrefix(arg, "-") {
+			break
+		}
+		name = append(name, arg)
+	}
+	c.span = trace.Start(strings.Join(name, " "), trace.SpanContext{})
+	trace.SetDefault(c.span.Context)
+	log.WithFields(c.span.Fields()).Debug("Tracing command")
+}
+
+// endTrace finishes the command's span and sends any spans still queued
+func (c *ServicedCli) endTrace(err error) {
+	c.span.Finish(err)
+	if c.tracer != nil {
+		c.tracer.Close()
+	}
+}
+
 func (c *ServicedCli) exit(code int) error {
 	if c.exitDisabled {
 		return fmt.Errorf("exit code %v", code)
 	}
+	if code != 0 {
+		c.endTrace(fmt.Errorf("exit code %v", code))
+	} else {
+		c.endTrace(nil)
+	}
 	os.Exit(code)
 	return nil
 }
@@ -271,6 +336,8 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		LogstashCycleTime:          ctx.GlobalInt("logstash-cycle-time"),
 		LogstashURL:                ctx.GlobalString("logstashurl"),
 		DebugPort:                  ctx.GlobalInt("debug-port"),
+		MetricsPort:                ctx.GlobalInt("metrics-port"),
+		TraceCollector:             ctx.GlobalString("trace-collector"),
 		AdminGroup:                 ctx.GlobalString("admin-group"),
 		MaxRPCClients:              ctx.GlobalInt("max-rpc-clients"),
 		RPCDialTimeout:             ctx.GlobalInt("rpc-dial-timeout"),
@@ -292,6 +359,9 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		DockerLogConfigList:        ctx.GlobalStringSlice("log-config"),
 		AllowLoopBack:              ctx.GlobalString("allow-loop-back"),
 		UIPollFrequency:            ctx.GlobalInt("ui-poll-frequency"),
+		UISessionStore:             ctx.GlobalString("ui-session-store"),
+		UISessionIdleTimeout:       ctx.GlobalInt("ui-session-idle-timeout"),
+		UISessionMaxAge:            ctx.GlobalInt("ui-session-max-age"),
 		StorageStatsUpdateInterval: ctx.GlobalInt("storage-stats-update-interval"),
 		StorageReportInterval:      ctx.GlobalInt("storage-report-interval"),
 		ZKSessionTimeout:           ctx.GlobalInt("zk-session-timeout"),
@@ -299,6 +369,8 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		ZKPerHostConnectDelay:      ctx.GlobalInt("zk-per-host-connect-delay"),
 		ZKReconnectStartDelay:      ctx.GlobalInt("zk-reconnect-start-delay"),
 		ZKReconnectMaxDelay:        ctx.GlobalInt("zk-reconnect-max-delay"),
+		CoordinatorDriver:          ctx.GlobalString("coordinator-driver"),
+		EtcdEndpoints:              ctx.GlobalStringSlice("etcd"),
 		TokenExpiration:            ctx.GlobalInt("auth-token-expiry"),
 		ConntrackFlush:             ctx.GlobalString("conntrack-flush"),
 		ServiceRunLevelTimeout:     ctx.GlobalInt("service-run-level-timeout"),
@@ -312,6 +384,14 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		Auth0Group:                 ctx.String("auth0-group"),
 		Auth0ClientID:              ctx.String("auth0-client-id"),
 		Auth0Scope:                 ctx.String("auth0-scope"),
+		OIDCIssuer:                 ctx.String("oidc-issuer"),
+		OIDCClientID:               ctx.String("oidc-client-id"),
+		OIDCClientSecret:           ctx.String("oidc-client-secret"),
+		OIDCAudience:               ctx.String("oidc-audience"),
+		OIDCScope:                  ctx.String("oidc-scope"),
+		OIDCRedirectURL:            ctx.String("oidc-redirect-url"),
+		OIDCGroupsClaim:            ctx.String("oidc-groups-claim"),
+		OIDCAdminGroups:            ctx.String("oidc-admin-groups"),
 	}
 
 	// Long story, but due to the way codegangsta handles bools and the way we start system services vs
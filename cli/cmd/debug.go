@@ -14,8 +14,12 @@
 package cmd
 
 import (
-	"github.com/codegangsta/cli"
+	"encoding/json"
 	"fmt"
+	"os"
+
+	"github.com/codegangsta/cli"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 // Initializer for serviced debug
@@ -36,7 +40,31 @@ func (c *ServicedCli) initDebug() {
 				Usage:        "Disable debug metrics",
 				Description:  "serviced debug disable-metrics",
 				Before:       c.cmdDisableDebugMetrics,
-			},		},
+			},
+			{
+				Name:        "zk",
+				Usage:       "Inspect and repair coordination state",
+				Description: "",
+				Subcommands: []cli.Command{
+					{
+						Name:        "dump",
+						Usage:       "Print the coordinator's tree as JSON",
+						Description: "serviced debug zk dump [PATH]",
+						Action:      c.cmdDebugZKDump,
+					}, {
+						Name:        "diff",
+						Usage:       "Compare the coordinator's state with the database",
+						Description: "serviced debug zk diff",
+						Action:      c.cmdDebugZKDiff,
+					}, {
+						Name:        "repair",
+						Usage:       "Fix differences between the coordinator's state and the database",
+						Description: "serviced debug zk repair",
+						Action:      c.cmdDebugZKRepair,
+					},
+				},
+			},
+		},
 	})
 }
 
@@ -58,3 +86,86 @@ func (c *ServicedCli) cmdDisableDebugMetrics(ctx *cli.Context) error {
 	return fmt.Errorf(message)
 }
 
+// serviced debug zk dump [PATH]
+func (c *ServicedCli) cmdDebugZKDump(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) > 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "dump")
+		c.exit(1)
+		return
+	}
+
+	pth := "/"
+	if len(args) == 1 {
+		pth = args[0]
+	}
+
+	tree, err := c.driver.GetCoordinatorTree(pth)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if jsonTree, err := json.MarshalIndent(tree, " ", "  "); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal coordinator tree: %s\n", err)
+		c.exit(1)
+	} else {
+		fmt.Println(string(jsonTree))
+	}
+}
+
+// serviced debug zk diff
+func (c *ServicedCli) cmdDebugZKDiff(ctx *cli.Context) {
+	found, err := c.driver.CheckCoordinatorState(false)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(found) == 0 {
+		fmt.Println("coordinator state matches the database")
+		return
+	}
+
+	printInconsistencies(found, "Type,Path,Message")
+	c.exit(1)
+}
+
+// serviced debug zk repair
+func (c *ServicedCli) cmdDebugZKRepair(ctx *cli.Context) {
+	found, err := c.driver.CheckCoordinatorState(true)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(found) == 0 {
+		fmt.Println("coordinator state matches the database")
+		return
+	}
+
+	printInconsistencies(found, "Type,Path,Message,Repaired")
+	for _, i := range found {
+		if !i.Repaired {
+			fmt.Fprintln(os.Stderr, "not all inconsistencies could be repaired")
+			c.exit(1)
+			return
+		}
+	}
+}
+
+// printInconsistencies prints a table of differences between the coordinator
+// and the database
+func printInconsistencies(found []zkservice.Inconsistency, fields string) {
+	t := NewTable(fields)
+	for _, i := range found {
+		t.AddRow(map[string]interface{}{
+			"Type":     i.Type,
+			"Path":     i.Path,
+			"Message":  i.Message,
+			"Repaired": i.Repaired,
+		})
+	}
+	t.Padding = 6
+	t.Print()
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+var DefaultDebugAPITest = DebugAPITest{
+	tree: &zzk.TreeNode{
+		Path: "/pools",
+		Children: []zzk.TreeNode{
+			{Path: "/pools/default", Data: json.RawMessage(`{"ID":"default"}`)},
+		},
+	},
+	found: []zkservice.Inconsistency{
+		{Type: zkservice.UnknownNode, Path: "/pools/default/hosts/h2", Message: "host h2 is not in the database"},
+		{Type: zkservice.StaleLock, Path: "/pools/default/services/s1", Message: "service s1 is locked"},
+	},
+}
+
+type DebugAPITest struct {
+	api.API
+	fail  bool
+	tree  *zzk.TreeNode
+	found []zkservice.Inconsistency
+}
+
+func InitDebugAPITest(t DebugAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func (t DebugAPITest) GetCoordinatorTree(pth string) (*zzk.TreeNode, error) {
+	if t.fail {
+		return nil, errors.New("could not connect to the coordinator")
+	}
+	return t.tree, nil
+}
+
+func (t DebugAPITest) CheckCoordinatorState(repair bool) ([]zkservice.Inconsistency, error) {
+	if t.fail {
+		return nil, errors.New("dfs is busy")
+	}
+	found := make([]zkservice.Inconsistency, len(t.found))
+	for i, inc := range t.found {
+		inc.Repaired = repair
+		found[i] = inc
+	}
+	return found, nil
+}
+
+func ExampleServicedCLI_CmdDebugZKDump() {
+	InitDebugAPITest(DefaultDebugAPITest, "serviced", "debug", "zk", "dump", "/pools")
+
+	// Output:
+	// {
+	//    "Path": "/pools",
+	//    "Children": [
+	//      {
+	//        "Path": "/pools/default",
+	//        "Data": {
+	//          "ID": "default"
+	//        }
+	//      }
+	//    ]
+	//  }
+}
+
+func ExampleServicedCLI_CmdDebugZKDump_err() {
+	pipeStderr(func() { InitDebugAPITest(DebugAPITest{fail: true}, "serviced", "debug", "zk", "dump") })
+
+	// Output:
+	// could not connect to the coordinator
+}
+
+func ExampleServicedCLI_CmdDebugZKDiff() {
+	InitDebugAPITest(DefaultDebugAPITest, "serviced", "debug", "zk", "diff")
+	InitDebugAPITest(DebugAPITest{}, "serviced", "debug", "zk", "diff")
+
+	// Output:
+	// Type            Path                            Message
+	// unknown         /pools/default/hosts/h2         host h2 is not in the database
+	// stale lock      /pools/default/services/s1      service s1 is locked
+	// coordinator state matches the database
+}
+
+func ExampleServicedCLI_CmdDebugZKRepair() {
+	InitDebugAPITest(DefaultDebugAPITest, "serviced", "debug", "zk", "repair")
+	pipeStderr(func() { InitDebugAPITest(DebugAPITest{fail: true}, "serviced", "debug", "zk", "repair") })
+
+	// Output:
+	// Type            Path                            Message                             Repaired
+	// unknown         /pools/default/hosts/h2         host h2 is not in the database      true
+	// stale lock      /pools/default/services/s1      service s1 is locked                true
+	// dfs is busy
+}
@@ -20,9 +20,11 @@ import (
 	"net"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/utils"
 	"github.com/pivotal-golang/bytefmt"
 )
@@ -109,6 +111,25 @@ func (c *ServicedCli) initHost() {
 				Usage:       "Set the authentication keys to use for this host. When KEYSFILE is -, read from stdin.",
 				Description: "serviced host register KEYSFILE",
 				Action:      c.cmdHostRegister,
+			}, {
+				Name:         "drain",
+				Usage:        "Puts a host into maintenance mode and moves its services to other hosts",
+				Description:  "serviced host drain HOSTID",
+				BashComplete: c.printHostsFirst,
+				Action:       c.cmdHostDrain,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "timeout",
+						Value: "5m",
+						Usage: "How long to wait for each instance to start on another host (e.g. 5m, 30s)",
+					},
+				},
+			}, {
+				Name:         "uncordon",
+				Usage:        "Takes a host out of maintenance mode",
+				Description:  "serviced host uncordon HOSTID",
+				BashComplete: c.printHostsFirst,
+				Action:       c.cmdHostUncordon,
 			}, {
 				Name:         "set-memory",
 				Usage:        "Set the memory allocation for a specific host",
@@ -238,6 +259,7 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 				"Cur/Max/Avg": usage,
 				"Network":     h.PrivateNetwork,
 				"Release":     h.ServiceD.Release,
+				"Drained":     h.Unschedulable,
 			})
 		}
 		t.Padding = 6
@@ -449,3 +471,107 @@ func (c *ServicedCli) cmdHostRegister(ctx *cli.Context) {
 	}
 
 }
+
+// serviced host drain HOSTID [--timeout DURATION]
+func (c *ServicedCli) cmdHostDrain(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "drain")
+		c.exit(1)
+		return
+	}
+
+	timeout, err := time.ParseDuration(ctx.String("timeout"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not parse duration: %s\n", err)
+		c.exit(1)
+		return
+	} else if timeout <= 0 {
+		fmt.Fprintln(os.Stderr, "timeout must be positive")
+		c.exit(1)
+		return
+	}
+
+	// print the progress of the drain while it runs
+	var report *host.DrainReport
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		report, err = c.driver.DrainHost(args[0], timeout)
+	}()
+	progress := &drainProgress{}
+	ticker := time.NewTicker(drainProgressInterval)
+	defer ticker.Stop()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		case <-ticker.C:
+			if status, err := c.driver.GetDrainStatus(args[0]); err == nil {
+				progress.print(status)
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	progress.print(report)
+
+	if !report.Drained() {
+		fmt.Fprintf(os.Stderr, "host %s is unschedulable but still has running instances\n", report.HostID)
+		c.exit(1)
+		return
+	}
+	fmt.Printf("host %s drained\n", report.HostID)
+}
+
+// drainProgressInterval is how often the progress of a drain is checked
+var drainProgressInterval = time.Second
+
+// drainProgress prints the parts of a drain report that have not been
+// printed yet
+type drainProgress struct {
+	movedIPs  bool
+	instances int
+}
+
+func (p *drainProgress) print(report *host.DrainReport) {
+	if !p.movedIPs && (len(report.VirtualIPs) > 0 || len(report.Instances) > 0) {
+		for _, ip := range report.VirtualIPs {
+			fmt.Printf("virtual IP %s: moved\n", ip)
+		}
+		p.movedIPs = true
+	}
+	for ; p.instances < len(report.Instances); p.instances++ {
+		inst := report.Instances[p.instances]
+		line := fmt.Sprintf("%s/%d: %s", inst.ServiceName, inst.InstanceID, inst.Status)
+		if inst.HostID != "" {
+			line += " to " + inst.HostID
+		}
+		if inst.Message != "" {
+			line += " (" + inst.Message + ")"
+		}
+		fmt.Println(line)
+	}
+}
+
+// serviced host uncordon HOSTID
+func (c *ServicedCli) cmdHostUncordon(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "uncordon")
+		c.exit(1)
+		return
+	}
+
+	if err := c.driver.UncordonHost(args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Println(args[0])
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/host"
@@ -75,7 +76,9 @@ type HostAPITest struct {
 }
 
 func InitHostAPITest(args ...string) {
-	New(DefaultHostAPITest, utils.TestConfigReader(make(map[string]string)), MockLogControl{}).Run(args)
+	c := New(DefaultHostAPITest, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
 }
 
 func (t HostAPITest) GetHosts() ([]host.Host, error) {
@@ -129,6 +132,66 @@ func (t HostAPITest) RemoveHost(id string) error {
 	return nil
 }
 
+func (t HostAPITest) DrainHost(id string, timeout time.Duration) (*host.DrainReport, error) {
+	if h, err := t.GetHost(id); err != nil {
+		return nil, err
+	} else if h == nil {
+		return nil, ErrNoHostFound
+	}
+	report := &host.DrainReport{
+		HostID:     id,
+		VirtualIPs: []string{"10.0.0.5"},
+		Instances: []host.DrainInstance{
+			{ServiceName: "db", InstanceID: 0, StartLevel: 1, HostID: "test-host-id-2", Status: host.DrainMigrated},
+			{ServiceName: "web", InstanceID: 1, HostID: "test-host-id-2", Status: host.DrainMigrated},
+		},
+	}
+	if id == "test-host-id-2" {
+		report.Instances = append(report.Instances, host.DrainInstance{
+			ServiceName: "ldap",
+			InstanceID:  0,
+			Status:      host.DrainSkipped,
+			Message:     "instance is assigned to static IP 192.168.0.1 on this host",
+		})
+	}
+	if id == "test-host-id-3" {
+		// finish once the progress has been checked
+		<-drainChecked
+	}
+	return report, nil
+}
+
+// drainChecked is closed when the progress of the drain of test-host-id-3 is
+// checked
+var drainChecked chan struct{}
+
+func (t HostAPITest) GetDrainStatus(id string) (*host.DrainReport, error) {
+	if id != "test-host-id-3" {
+		return nil, errors.New("host has not been drained")
+	}
+	select {
+	case <-drainChecked:
+	default:
+		close(drainChecked)
+	}
+	return &host.DrainReport{
+		HostID:     id,
+		VirtualIPs: []string{"10.0.0.5"},
+		Instances: []host.DrainInstance{
+			{ServiceName: "db", InstanceID: 0, StartLevel: 1, HostID: "test-host-id-2", Status: host.DrainMigrated},
+		},
+	}, nil
+}
+
+func (t HostAPITest) UncordonHost(id string) error {
+	if h, err := t.GetHost(id); err != nil {
+		return err
+	} else if h == nil {
+		return ErrNoHostFound
+	}
+	return nil
+}
+
 func (t HostAPITest) RegisterRemoteHost(h *host.Host, nat utils.URL, data []byte, prompt bool) error {
 	if t.registerFail {
 		return errors.New("Forcing RemoteRegisterHost to fail for testing")
@@ -418,6 +481,77 @@ func ExampleServicedCLI_CmdHostRemove_complete() {
 	// test-host-id-3
 }
 
+func ExampleServicedCLI_CmdHostDrain() {
+	InitHostAPITest("serviced", "host", "drain", "test-host-id-1", "--timeout", "1m")
+
+	// Output:
+	// virtual IP 10.0.0.5: moved
+	// db/0: migrated to test-host-id-2
+	// web/1: migrated to test-host-id-2
+	// host test-host-id-1 drained
+}
+
+func ExampleServicedCLI_CmdHostDrain_progress() {
+	drainChecked = make(chan struct{})
+	defer func(interval time.Duration) { drainProgressInterval = interval }(drainProgressInterval)
+	drainProgressInterval = 10 * time.Millisecond
+	InitHostAPITest("serviced", "host", "drain", "test-host-id-3")
+
+	// Output:
+	// virtual IP 10.0.0.5: moved
+	// db/0: migrated to test-host-id-2
+	// web/1: migrated to test-host-id-2
+	// host test-host-id-3 drained
+}
+
+func ExampleServicedCLI_CmdHostDrain_skipped() {
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "drain", "test-host-id-2") })
+
+	// Output:
+	// virtual IP 10.0.0.5: moved
+	// db/0: migrated to test-host-id-2
+	// web/1: migrated to test-host-id-2
+	// ldap/0: skipped (instance is assigned to static IP 192.168.0.1 on this host)
+	// host test-host-id-2 is unschedulable but still has running instances
+}
+
+func ExampleServicedCLI_CmdHostDrain_err() {
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "drain", "test-host-id-0") })
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "drain", "test-host-id-1", "--timeout", "soon") })
+
+	// Output:
+	// no host found
+	// could not parse duration: time: invalid duration "soon"
+}
+
+func ExampleServicedCLI_CmdHostDrain_usage() {
+	InitHostAPITest("serviced", "host", "drain")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    drain - Puts a host into maintenance mode and moves its services to other hosts
+	//
+	// USAGE:
+	//    command drain [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced host drain HOSTID
+	//
+	// OPTIONS:
+	//    --timeout '5m'	How long to wait for each instance to start on another host (e.g. 5m, 30s)
+}
+
+func ExampleServicedCLI_CmdHostUncordon() {
+	InitHostAPITest("serviced", "host", "uncordon", "test-host-id-1")
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "uncordon", "test-host-id-0") })
+
+	// Output:
+	// test-host-id-1
+	// no host found
+}
+
 func ExampleServicedCLI_CmdHostRegister_usage() {
 	InitHostAPITest("serviced", "host", "register")
 
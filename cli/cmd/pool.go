@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -102,12 +103,43 @@ func (c *ServicedCli) initPool() {
 				Description:  "serviced pool remove-virtual-ip POOLID IPADDRESS",
 				BashComplete: c.printPoolsFirst,
 				Action:       c.cmdRemoveVirtualIP,
+			}, {
+				Name:        "vip",
+				Usage:       "Manage failover of the virtual IPs in a pool",
+				Description: "serviced pool vip",
+				Subcommands: []cli.Command{
+					{
+						Name:         "status",
+						Usage:        "Shows the current owner and failover history of each virtual IP",
+						Description:  "serviced pool vip status POOLID",
+						BashComplete: c.printPoolsFirst,
+						Action:       c.cmdVirtualIPStatus,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "verbose, v",
+								Usage: "Show JSON format",
+							},
+						},
+					}, {
+						Name:         "prefer",
+						Usage:        "Sets the hosts to assign a virtual IP to, most preferred first",
+						Description:  "serviced pool vip prefer POOLID IPADDRESS [HOSTID ...]",
+						BashComplete: c.printPoolsFirst,
+						Action:       c.cmdVirtualIPPrefer,
+					},
+				},
 			}, {
 				Name:         "set-conn-timeout",
 				Usage:        "Set a connection timeout for a high latency resource pool (e.g. 5m, 2h, 6.6s)",
 				Description:  "serviced pool set-conn-timeout POOLID TIMEOUT",
 				BashComplete: c.printPoolsFirst,
 				Action:       c.cmdSetConnTimeout,
+			}, {
+				Name:         "set-strict-cpu",
+				Usage:        "Refuse to schedule instances that would commit more cores than a host has",
+				Description:  "serviced pool set-strict-cpu POOLID true|false",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdSetStrictCPU,
 			}, {
 				Name:         "set-permission",
 				Usage:        "Set permission flags for hosts in a pool",
@@ -403,6 +435,134 @@ func (c *ServicedCli) cmdSetConnTimeout(ctx *cli.Context) {
 	}
 }
 
+// serviced pool vip status POOLID
+func (c *ServicedCli) cmdVirtualIPStatus(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "status")
+		return
+	}
+
+	statuses, err := c.driver.GetVirtualIPStatus(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(statuses) == 0 {
+		fmt.Fprintln(os.Stderr, "no virtual ips found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonStatus, err := json.MarshalIndent(statuses, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal virtual ip status: %s", err)
+		} else {
+			fmt.Println(string(jsonStatus))
+		}
+		return
+	}
+
+	t := NewTable("IPAddress,HostID,Since,PreferredHosts")
+	t.Padding = 6
+	for _, status := range statuses {
+		since := ""
+		if status.HostID != "" {
+			since = status.Since.Format(time.RFC3339)
+		}
+		t.AddRow(map[string]interface{}{
+			"IPAddress":      status.IPAddress,
+			"HostID":         status.HostID,
+			"Since":          since,
+			"PreferredHosts": strings.Join(status.PreferredHosts, ","),
+		})
+	}
+	t.Print()
+
+	fmt.Println()
+	h := NewTable("IPAddress,Time,Event,HostID,Reason")
+	h.Padding = 6
+	for _, status := range statuses {
+		for _, event := range status.History {
+			h.AddRow(map[string]interface{}{
+				"IPAddress": status.IPAddress,
+				"Time":      event.Time.Format(time.RFC3339),
+				"Event":     event.Event,
+				"HostID":    event.HostID,
+				"Reason":    event.Reason,
+			})
+		}
+	}
+	h.Print()
+}
+
+// serviced pool vip prefer POOLID IPADDRESS [HOSTID ...]
+func (c *ServicedCli) cmdVirtualIPPrefer(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "prefer")
+		return
+	}
+
+	p, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if p == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	found := false
+	for i := range p.VirtualIPs {
+		if p.VirtualIPs[i].IP == args[1] {
+			p.VirtualIPs[i].PreferredHosts = args[2:]
+			found = true
+			break
+		}
+	}
+	if !found {
+		fmt.Fprintln(os.Stderr, "virtual ip not found")
+		return
+	}
+
+	if err := c.driver.UpdateResourcePool(*p); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
+
+// serviced pool set-strict-cpu POOLID true|false
+func (c *ServicedCli) cmdSetStrictCPU(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-strict-cpu")
+		return
+	}
+
+	strict, err := strconv.ParseBool(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not parse %s as true or false\n", args[1])
+		return
+	}
+
+	pool, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if pool == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	pool.StrictCPU = strict
+	if err := c.driver.UpdateResourcePool(*pool); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
+
 func (c *ServicedCli) cmdSetPermission(ctx *cli.Context) {
 	args := ctx.Args()
 	if len(args) != 1 {
@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 const (
@@ -161,6 +163,28 @@ func (t PoolAPITest) GetPoolIPs(id string) (*pool.PoolIPs, error) {
 	return &pool.PoolIPs{PoolID: p.ID, HostIPs: t.hostIPs}, nil
 }
 
+func (t PoolAPITest) GetVirtualIPStatus(id string) ([]zkservice.VirtualIPStatus, error) {
+	if p, err := t.GetResourcePool(id); err != nil {
+		return nil, err
+	} else if p == nil {
+		return nil, ErrNoPoolFound
+	}
+
+	since := time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []zkservice.VirtualIPStatus{
+		{
+			IPAddress:      "10.0.0.1",
+			HostID:         "test-host-id-2",
+			Since:          since,
+			PreferredHosts: []string{"test-host-id-1", "test-host-id-2"},
+			History: []zkservice.VirtualIPEvent{
+				{Time: since, HostID: "test-host-id-2", Event: zkservice.IPAssigned, Reason: "failover"},
+				{Time: since.Add(-time.Minute), HostID: "test-host-id-1", Event: zkservice.IPFailed, Reason: "interface eth1 is down"},
+			},
+		},
+	}, nil
+}
+
 func (t PoolAPITest) UpdateResourcePool(pool pool.ResourcePool) error {
 	for i, p := range *t.pools {
 		if p.ID == pool.ID {
@@ -447,3 +471,80 @@ func TestServicedCLI_CmdPoolSetPermission(t *testing.T) {
 	RunCmd(test, "serviced", "pool", "set-permission", "--admin", "--dfs=false", poolID)
 	assertPerm(poolID, pool.AdminAccess)
 }
+
+func TestServicedCLI_CmdPoolSetStrictCPU(t *testing.T) {
+	test := EmptyPoolAPI()
+	poolID := "poolID"
+	RunCmd(test, "serviced", "pool", "add", poolID)
+
+	RunCmd(test, "serviced", "pool", "set-strict-cpu", poolID, "true")
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err)
+	} else if !p.StrictCPU {
+		t.Fatalf("Expected strict cpu for %s", poolID)
+	}
+
+	RunCmd(test, "serviced", "pool", "set-strict-cpu", poolID, "false")
+	if p, err := test.GetResourcePool(poolID); err != nil {
+		t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err)
+	} else if p.StrictCPU {
+		t.Fatalf("Expected no strict cpu for %s", poolID)
+	}
+}
+
+func ExampleServicedCLI_CmdPoolSetStrictCPU_fail() {
+	pipeStderr(func() { RunCmd(DefaultPoolAPI(), "serviced", "pool", "set-strict-cpu", "test-pool-id-1", "maybe") })
+
+	// Output:
+	// could not parse maybe as true or false
+}
+
+func ExampleServicedCLI_CmdPoolVirtualIPStatus() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "vip", "status", "test-pool-id-1")
+
+	// Output:
+	// IPAddress      HostID              Since                     PreferredHosts
+	// 10.0.0.1       test-host-id-2      2017-03-01T12:00:00Z      test-host-id-1,test-host-id-2
+	//
+	// IPAddress      Time                      Event         HostID              Reason
+	// 10.0.0.1       2017-03-01T12:00:00Z      assigned      test-host-id-2      failover
+	// 10.0.0.1       2017-03-01T11:59:00Z      failed        test-host-id-1      interface eth1 is down
+}
+
+func ExampleServicedCLI_CmdPoolVirtualIPStatus_err() {
+	pipeStderr(func() { RunCmd(DefaultPoolAPI(), "serviced", "pool", "vip", "status", "test-pool-id-0") })
+
+	// Output:
+	// no pool found
+}
+
+func TestServicedCLI_CmdPoolVirtualIPPrefer(t *testing.T) {
+	test := EmptyPoolAPI()
+	*test.pools = append(*test.pools, pool.ResourcePool{
+		ID:         "poolID",
+		VirtualIPs: []pool.VirtualIP{{PoolID: "poolID", IP: "10.0.0.1"}},
+	})
+
+	RunCmd(test, "serviced", "pool", "vip", "prefer", "poolID", "10.0.0.1", "host2", "host1")
+	if p, err := test.GetResourcePool("poolID"); err != nil {
+		t.Fatalf("GetResourcePool(\"poolID\"): %s", err)
+	} else if !reflect.DeepEqual(p.VirtualIPs[0].PreferredHosts, []string{"host2", "host1"}) {
+		t.Fatalf("Unexpected preferred hosts %v", p.VirtualIPs[0].PreferredHosts)
+	}
+
+	RunCmd(test, "serviced", "pool", "vip", "prefer", "poolID", "10.0.0.1")
+	if p, err := test.GetResourcePool("poolID"); err != nil {
+		t.Fatalf("GetResourcePool(\"poolID\"): %s", err)
+	} else if len(p.VirtualIPs[0].PreferredHosts) != 0 {
+		t.Fatalf("Expected no preferred hosts, got %v", p.VirtualIPs[0].PreferredHosts)
+	}
+}
+
+func ExampleServicedCLI_CmdPoolVirtualIPPrefer_fail() {
+	pipeStderr(func() {
+		RunCmd(DefaultPoolAPI(), "serviced", "pool", "vip", "prefer", "test-pool-id-1", "10.0.0.9", "host1")
+	})
+
+	// Output:
+	// virtual ip not found
+}
@@ -0,0 +1,168 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Initializer for serviced secret subcommands
+func (c *ServicedCli) initSecret() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "secret",
+		Usage:       "Administers tenant secrets",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "set",
+				Usage:       "Sets the value of a secret",
+				Description: "serviced secret set TENANTID NAME [VALUE]",
+				Action:      c.cmdSecretSet,
+			}, {
+				Name:        "list",
+				Usage:       "Lists the secrets of a tenant",
+				Description: "serviced secret list TENANTID",
+				Action:      c.cmdSecretList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "Name,UpdatedAt",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			}, {
+				Name:        "remove",
+				ShortName:   "rm",
+				Usage:       "Removes secrets from a tenant",
+				Description: "serviced secret remove TENANTID NAME ...",
+				Action:      c.cmdSecretRemove,
+			},
+		},
+	})
+}
+
+// readSecretValue reads the value of a secret from the terminal without
+// echoing it, or from stdin if it is not a terminal
+func readSecretValue() (string, error) {
+	if terminal.IsTerminal(syscall.Stdin) {
+		fmt.Fprint(os.Stderr, "Value: ")
+		value, err := terminal.ReadPassword(syscall.Stdin)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("could not read secret value: %s", err)
+		}
+		return string(value), nil
+	}
+	value, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("could not read secret value: %s", err)
+	}
+	return strings.TrimRight(string(value), "\r\n"), nil
+}
+
+// serviced secret set TENANTID NAME [VALUE]
+func (c *ServicedCli) cmdSecretSet(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set")
+		return
+	}
+
+	var value string
+	if len(args) > 2 {
+		value = args[2]
+	} else {
+		var err error
+		if value, err = readSecretValue(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+	}
+
+	if err := c.driver.SetSecret(args[0], args[1], value); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else {
+		fmt.Println(args[1])
+	}
+}
+
+// serviced secret list TENANTID
+func (c *ServicedCli) cmdSecretList(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "list")
+		return
+	}
+
+	secrets, err := c.driver.GetSecrets(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(secrets) == 0 {
+		fmt.Fprintln(os.Stderr, "no secrets found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonSecrets, err := json.MarshalIndent(secrets, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal secret list: %s", err)
+		} else {
+			fmt.Println(string(jsonSecrets))
+		}
+	} else {
+		t := NewTable(ctx.String("show-fields"))
+		t.Padding = 6
+		for _, s := range secrets {
+			t.AddRow(map[string]interface{}{
+				"Name":      s.Name,
+				"TenantID":  s.TenantID,
+				"UpdatedAt": s.UpdatedAt.Format(time.RFC3339),
+			})
+		}
+		t.Print()
+	}
+}
+
+// serviced secret remove TENANTID NAME ...
+func (c *ServicedCli) cmdSecretRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	for _, name := range args[1:] {
+		if err := c.driver.RemoveSecret(args[0], name); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+		} else {
+			fmt.Println(name)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/secret"
+)
+
+var ErrInvalidSecret = errors.New("invalid secret")
+
+type SecretAPITest struct {
+	api.API
+	secrets map[string]string
+}
+
+func DefaultSecretAPI() *SecretAPITest {
+	return &SecretAPITest{
+		secrets: map[string]string{"db_password": "s3cr3t"},
+	}
+}
+
+func (t *SecretAPITest) SetSecret(tenantID, name, value string) error {
+	if tenantID != "test-tenant" {
+		return ErrInvalidSecret
+	}
+	t.secrets[name] = value
+	return nil
+}
+
+func (t *SecretAPITest) GetSecrets(tenantID string) ([]secret.Secret, error) {
+	if tenantID != "test-tenant" {
+		return nil, ErrInvalidSecret
+	}
+	var secrets []secret.Secret
+	for name := range t.secrets {
+		secrets = append(secrets, secret.Secret{TenantID: tenantID, Name: name, Value: secret.Redacted})
+	}
+	return secrets, nil
+}
+
+func (t *SecretAPITest) RemoveSecret(tenantID, name string) error {
+	if _, ok := t.secrets[name]; !ok {
+		return ErrInvalidSecret
+	}
+	delete(t.secrets, name)
+	return nil
+}
+
+func ExampleServicedCLI_CmdSecretSet() {
+	test := DefaultSecretAPI()
+	RunCmd(test, "serviced", "secret", "set", "test-tenant", "db_user", "admin")
+	pipeStderr(func() { RunCmd(test, "serviced", "secret", "set", "test-tenant-0", "db_user", "admin") })
+
+	// Output:
+	// db_user
+	// invalid secret
+}
+
+func ExampleServicedCLI_CmdSecretSet_usage() {
+	RunCmd(DefaultSecretAPI(), "serviced", "secret", "set", "test-tenant")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    set - Sets the value of a secret
+	//
+	// USAGE:
+	//    command set [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced secret set TENANTID NAME [VALUE]
+	//
+	// OPTIONS:
+}
+
+func ExampleServicedCLI_CmdSecretList() {
+	RunCmd(DefaultSecretAPI(), "serviced", "secret", "list", "test-tenant", "--show-fields", "Name")
+	pipeStderr(func() { RunCmd(DefaultSecretAPI(), "serviced", "secret", "list", "test-tenant-0") })
+
+	// Output:
+	// Name
+	// db_password
+	// invalid secret
+}
+
+func ExampleServicedCLI_CmdSecretRemove() {
+	test := DefaultSecretAPI()
+	pipeStderr(func() { RunCmd(test, "serviced", "secret", "rm", "test-tenant", "db_password", "db_user") })
+
+	// Output:
+	// db_password
+	// db_user: invalid secret
+}
@@ -182,6 +182,88 @@ func (c *ServicedCli) initService() {
 						Name:  "rebalance",
 						Usage: "Stops all instances before restarting them, instead of performing a rolling restart",
 					},
+					cli.IntFlag{
+						Name:  "batch-size",
+						Value: 0,
+						Usage: "Number of instances to restart at a time",
+					},
+					cli.IntFlag{
+						Name:  "batch-percent",
+						Value: 0,
+						Usage: "Percentage of instances to restart at a time",
+					},
+					cli.StringFlag{
+						Name:  "pause",
+						Value: "",
+						Usage: "Time to wait between batches (e.g. 30s)",
+					},
+					cli.StringFlag{
+						Name:  "health-timeout",
+						Value: "",
+						Usage: "Time a batch has to start and pass its health checks",
+					},
+					cli.StringFlag{
+						Name:  "window",
+						Value: "",
+						Usage: "Time a batch must stay up and healthy after it starts",
+					},
+					cli.BoolFlag{
+						Name:  "halt",
+						Usage: "Stops the restart when a batch does not become healthy in time",
+					},
+				},
+			}, {
+				Name:         "set-image",
+				Usage:        "Changes the image of a service",
+				Description:  "serviced service set-image { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME } IMAGEID",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceSetImage,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "rolling",
+						Usage: "Restarts running instances onto the new image in batches, reverting if a batch fails",
+					},
+					cli.IntFlag{
+						Name:  "batch-size",
+						Value: 0,
+						Usage: "Number of instances to restart at a time",
+					},
+					cli.IntFlag{
+						Name:  "batch-percent",
+						Value: 0,
+						Usage: "Percentage of instances to restart at a time",
+					},
+					cli.StringFlag{
+						Name:  "pause",
+						Value: "",
+						Usage: "Time to wait between batches (e.g. 30s)",
+					},
+					cli.StringFlag{
+						Name:  "health-timeout",
+						Value: "",
+						Usage: "Time a batch has to start and pass its health checks",
+					},
+					cli.StringFlag{
+						Name:  "window",
+						Value: "",
+						Usage: "Time a batch must stay up and healthy after it starts (default 30s)",
+					},
+					cli.BoolFlag{
+						Name:  "no-revert",
+						Usage: "Leaves the new image in place when the rollout halts",
+					},
+				},
+			}, {
+				Name:         "restart-status",
+				Usage:        "Shows the progress of rolling restarts",
+				Description:  "serviced service restart-status [SERVICEID]",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceRestartStatus,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
 				},
 			}, {
 				Name:         "stop",
@@ -340,6 +422,55 @@ func (c *ServicedCli) initService() {
 						Usage: "verify endpoints",
 					},
 				},
+			}, {
+				Name:        "config",
+				Usage:       "Inspect the config files of a service",
+				Description: "serviced service config",
+				Subcommands: []cli.Command{
+					{
+						Name:         "render",
+						Usage:        "Prints the config files of a service instance as they would be rendered now",
+						Description:  "serviced service config render { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME/INSTANCE }",
+						BashComplete: c.printServicesFirst,
+						Action:       c.cmdServiceConfigRender,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "verbose, v",
+								Usage: "Show JSON format",
+							},
+						},
+					}, {
+						Name:         "drift",
+						Usage:        "Lists config files of running instances that differ from what would be rendered now",
+						Description:  "serviced service config drift { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }",
+						BashComplete: c.printServicesFirst,
+						Action:       c.cmdServiceConfigDrift,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "verbose, v",
+								Usage: "Show JSON format",
+							},
+						},
+					},
+				},
+			}, {
+				Name:         "graph",
+				Usage:        "Prints the endpoint dependency graph of the services in a tenant",
+				Description:  "serviced service graph { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceGraph,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "format",
+						Value: "dot",
+						Usage: "Output format (dot or json)",
+					},
+					cli.StringFlag{
+						Name:  "impact",
+						Value: "",
+						Usage: "Only show the services that are affected when this service stops",
+					},
+				},
 			}, {
 				Name:        "public-endpoints",
 				Usage:       "Manage public endpoints for a service",
@@ -1192,7 +1323,7 @@ func (c *ServicedCli) cmdServiceStart(ctx *cli.Context) {
 		serviceIDs[i] = svc.ID
 	}
 
-	if affected, err := c.driver.StartService(api.SchedulerConfig{serviceIDs, ctx.Bool("auto-launch"), ctx.Bool("sync")}); err != nil {
+	if affected, err := c.driver.StartService(api.SchedulerConfig{ServiceIDs: serviceIDs, AutoLaunch: ctx.Bool("auto-launch"), Synchronous: ctx.Bool("sync")}); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	} else if affected == 0 {
 		fmt.Println("Service(s) already started")
@@ -1239,13 +1370,18 @@ func (c *ServicedCli) cmdServiceRestart(ctx *cli.Context) {
 	// Batch start services
 	if len(sIds) > 0 {
 		if ctx.Bool("rebalance") {
-			if affected, err := c.driver.RebalanceService(api.SchedulerConfig{sIds, ctx.Bool("auto-launch"), ctx.Bool("sync")}); err != nil {
+			if affected, err := c.driver.RebalanceService(api.SchedulerConfig{ServiceIDs: sIds, AutoLaunch: ctx.Bool("auto-launch"), Synchronous: ctx.Bool("sync")}); err != nil {
 				fmt.Fprintln(os.Stderr, err)
 			} else {
 				fmt.Printf("Restarting %d service(s)\n", affected)
 			}
 		} else {
-			if affected, err := c.driver.RestartService(api.SchedulerConfig{sIds, ctx.Bool("auto-launch"), ctx.Bool("sync")}); err != nil {
+			opts, err := rollingRestartOptions(ctx)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return
+			}
+			if affected, err := c.driver.RestartService(api.SchedulerConfig{ServiceIDs: sIds, AutoLaunch: ctx.Bool("auto-launch"), Synchronous: ctx.Bool("sync"), RollingRestart: opts}); err != nil {
 				fmt.Fprintln(os.Stderr, err)
 			} else {
 				fmt.Printf("Restarting %d service(s)\n", affected)
@@ -1263,6 +1399,126 @@ func (c *ServicedCli) cmdServiceRestart(ctx *cli.Context) {
 	}
 }
 
+// rollingRestartOptions returns the rolling restart options set on the
+// command line, or nil if none were set.
+func rollingRestartOptions(ctx *cli.Context) (*service.RollingRestart, error) {
+	if !ctx.IsSet("batch-size") && !ctx.IsSet("batch-percent") && !ctx.IsSet("pause") && !ctx.IsSet("health-timeout") && !ctx.IsSet("window") && !ctx.Bool("halt") {
+		return nil, nil
+	}
+
+	opts := &service.RollingRestart{
+		BatchSize:    ctx.Int("batch-size"),
+		BatchPercent: ctx.Int("batch-percent"),
+		Halt:         ctx.Bool("halt"),
+	}
+	if pause := ctx.String("pause"); pause != "" {
+		d, err := time.ParseDuration(pause)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse pause: %s", err)
+		}
+		opts.Pause = d
+	}
+	if timeout := ctx.String("health-timeout"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse health timeout: %s", err)
+		}
+		opts.Timeout = d
+	}
+	if window := ctx.String("window"); window != "" {
+		d, err := time.ParseDuration(window)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse window: %s", err)
+		}
+		opts.Window = d
+	}
+	if err := opts.ValidEntity(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// serviced service set-image SERVICEID IMAGEID [--rolling]
+func (c *ServicedCli) cmdServiceSetImage(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-image")
+		return
+	}
+
+	svc, _, err := c.searchForService(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	var rollout *service.RollingRestart
+	if ctx.Bool("rolling") {
+		if rollout, err = rollingRestartOptions(ctx); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		} else if rollout == nil {
+			rollout = &service.RollingRestart{}
+		}
+		rollout.Revert = !ctx.Bool("no-revert")
+	}
+
+	started, err := c.driver.SetServiceImage(svc.ID, args[1], rollout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else if started {
+		fmt.Printf("Rolling out %s to %s; see serviced service restart-status %s\n", args[1], svc.Name, svc.ID)
+	} else {
+		fmt.Printf("Set the image of %s to %s\n", svc.Name, args[1])
+	}
+}
+
+// serviced service restart-status [SERVICEID]
+func (c *ServicedCli) cmdServiceRestartStatus(ctx *cli.Context) {
+	var serviceID string
+	if args := ctx.Args(); len(args) > 0 {
+		svc, _, err := c.searchForService(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		serviceID = svc.ID
+	}
+
+	statuses, err := c.driver.GetRollingRestartStatus(serviceID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(statuses) == 0 {
+		fmt.Fprintln(os.Stderr, "no rolling restarts found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonStatuses, err := json.MarshalIndent(statuses, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal rolling restart status: %s", err)
+		} else {
+			fmt.Println(string(jsonStatuses))
+		}
+		return
+	}
+
+	t := NewTable("ServiceID,Name,State,Batch,Restarted,Message")
+	t.Padding = 4
+	for _, s := range statuses {
+		t.AddRow(map[string]interface{}{
+			"ServiceID": s.ServiceID,
+			"Name":      s.ServiceName,
+			"State":     s.State,
+			"Batch":     fmt.Sprintf("%d/%d", s.Batch, s.Batches),
+			"Restarted": fmt.Sprintf("%d/%d", s.Restarted, s.Instances),
+			"Message":   s.Message,
+		})
+	}
+	t.Print()
+}
+
 // serviced service stop SERVICEID
 func (c *ServicedCli) cmdServiceStop(ctx *cli.Context) {
 	args := ctx.Args()
@@ -1283,7 +1539,7 @@ func (c *ServicedCli) cmdServiceStop(ctx *cli.Context) {
 		serviceIDs[i] = svc.ID
 	}
 
-	if affected, err := c.driver.StopService(api.SchedulerConfig{serviceIDs, ctx.Bool("auto-launch"), ctx.Bool("sync")}); err != nil {
+	if affected, err := c.driver.StopService(api.SchedulerConfig{ServiceIDs: serviceIDs, AutoLaunch: ctx.Bool("auto-launch"), Synchronous: ctx.Bool("sync")}); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	} else if affected == 0 {
 		fmt.Println("Service(s) already stopped")
@@ -1312,7 +1568,7 @@ func (c *ServicedCli) cmdServicePause(ctx *cli.Context) {
 		serviceIDs[i] = svc.ID
 	}
 
-	if affected, err := c.driver.PauseService(api.SchedulerConfig{serviceIDs, ctx.Bool("auto-launch"), ctx.Bool("sync")}); err != nil {
+	if affected, err := c.driver.PauseService(api.SchedulerConfig{ServiceIDs: serviceIDs, AutoLaunch: ctx.Bool("auto-launch"), Synchronous: ctx.Bool("sync")}); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	} else if affected == 0 {
 		fmt.Println("Service(s) already paused")
@@ -1794,6 +2050,205 @@ func (c *ServicedCli) cmdServiceEndpoints(ctx *cli.Context) {
 	}
 }
 
+// serviced service config render { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME/INSTANCE }
+func (c *ServicedCli) cmdServiceConfigRender(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "render")
+		return
+	}
+
+	svc, instanceID, err := c.searchForService(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if instanceID < 0 {
+		instanceID = 0
+	}
+
+	confs, err := c.driver.RenderServiceConfigs(svc.ID, instanceID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(confs) == 0 {
+		fmt.Fprintf(os.Stderr, "%s - no config files defined\n", svc.Name)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonConfs, err := json.MarshalIndent(confs, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal config files: %s", err)
+		} else {
+			fmt.Println(string(jsonConfs))
+		}
+		return
+	}
+
+	for i, conf := range confs {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("==> %s <==\n", conf.Filename)
+		fmt.Print(conf.Content)
+		if !strings.HasSuffix(conf.Content, "\n") {
+			fmt.Println()
+		}
+	}
+}
+
+// serviced service config drift { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }
+func (c *ServicedCli) cmdServiceConfigDrift(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "drift")
+		return
+	}
+
+	svc, _, err := c.searchForService(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	drift, err := c.driver.GetServiceConfigDrift(svc.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(drift) == 0 {
+		fmt.Fprintf(os.Stderr, "%s - no config drift found\n", svc.Name)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonDrift, err := json.MarshalIndent(drift, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal config drift: %s", err)
+		} else {
+			fmt.Println(string(jsonDrift))
+		}
+		return
+	}
+
+	t := NewTable("Name,HostID,Filename,Status")
+	t.Padding = 4
+	for _, d := range drift {
+		t.AddRow(map[string]interface{}{
+			"Name":     fmt.Sprintf("%s/%d", svc.Name, d.InstanceID),
+			"HostID":   d.HostID,
+			"Filename": d.Filename,
+			"Status":   d.Status,
+		})
+	}
+	t.Print()
+}
+
+// serviced service graph { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME } [--format dot|json] [--impact SERVICE]
+func (c *ServicedCli) cmdServiceGraph(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "graph")
+		return
+	}
+
+	format := ctx.String("format")
+	if format != "dot" && format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format %q; use dot or json\n", format)
+		return
+	}
+
+	svc, _, err := c.searchForService(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	graph, err := c.driver.GetServiceDependencyGraph(svc.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if impact := ctx.String("impact"); impact != "" {
+		stopped, _, err := c.searchForService(impact)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		graph = graph.Impact(stopped.ID)
+	}
+
+	if format == "json" {
+		if jsonGraph, err := json.MarshalIndent(graph, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal dependency graph: %s", err)
+		} else {
+			fmt.Println(string(jsonGraph))
+		}
+	} else {
+		printDependencyGraphDot(graph)
+	}
+
+	names := make(map[string]string)
+	for _, node := range graph.Nodes {
+		names[node.ServiceID] = node.Name
+	}
+	for _, imp := range graph.Unresolved {
+		fmt.Fprintf(os.Stderr, "%s imports %s, which is not exported by any service\n", names[imp.ServiceID], imp.Application)
+	}
+	for _, cycle := range graph.Cycles {
+		cycleNames := make([]string, len(cycle))
+		for i, id := range cycle {
+			cycleNames[i] = names[id]
+		}
+		fmt.Fprintf(os.Stderr, "dependency cycle between %s\n", strings.Join(cycleNames, ", "))
+	}
+}
+
+// printDependencyGraphDot prints the dependency graph in the graphviz dot
+// format.  Nodes are colored by their current state; unresolved imports and
+// edges in cycles are red, and edges to services that start later are dashed.
+func printDependencyGraphDot(graph *service.DependencyGraph) {
+	cycles := make(map[string]int)
+	for i, cycle := range graph.Cycles {
+		for _, id := range cycle {
+			cycles[id] = i + 1
+		}
+	}
+
+	fmt.Printf("digraph %s {\n", strconv.Quote(graph.TenantID))
+	for _, node := range graph.Nodes {
+		color := "orange"
+		switch {
+		case node.EmergencyShutdown:
+			color = "red"
+		case node.CurrentState == service.SVCCSRunning:
+			color = "darkgreen"
+		case node.CurrentState == service.SVCCSStopped:
+			color = "gray"
+		}
+		label := fmt.Sprintf("%s\n%s %d/%d", node.Name, node.CurrentState, node.RunningInstances, node.Instances)
+		fmt.Printf("\t%s [label=%s color=%s];\n", strconv.Quote(node.ServiceID), strconv.Quote(label), color)
+	}
+	for _, edge := range graph.Edges {
+		attrs := fmt.Sprintf("label=%s", strconv.Quote(edge.Application))
+		if cycle := cycles[edge.ImporterID]; cycle > 0 && cycle == cycles[edge.ExporterID] {
+			attrs += " color=red"
+		}
+		if edge.OutOfOrder {
+			attrs += " style=dashed"
+		}
+		fmt.Printf("\t%s -> %s [%s];\n", strconv.Quote(edge.ImporterID), strconv.Quote(edge.ExporterID), attrs)
+	}
+	for i, imp := range graph.Unresolved {
+		id := strconv.Quote(fmt.Sprintf("unresolved-%d", i))
+		fmt.Printf("\t%s [label=%s shape=box style=dashed color=red];\n", id, strconv.Quote(imp.Application))
+		fmt.Printf("\t%s -> %s [color=red];\n", strconv.Quote(imp.ServiceID), id)
+	}
+	fmt.Println("}")
+}
+
 // serviced service clear-emergency { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }
 func (c *ServicedCli) cmdServiceClearEmergency(ctx *cli.Context) {
 	// verify args
@@ -31,6 +31,7 @@ import (
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -451,6 +452,75 @@ func (t ServiceAPITest) ClearEmergency(serviceID string) (int, error) {
 	return 1, nil
 }
 
+func (t ServiceAPITest) RenderServiceConfigs(serviceID string, instanceID int) ([]servicedefinition.ConfigFile, error) {
+	if t.errs["RenderServiceConfigs"] != nil {
+		return nil, t.errs["RenderServiceConfigs"]
+	}
+	if serviceID != "test-service-2" {
+		return nil, nil
+	}
+	return []servicedefinition.ConfigFile{
+		{Filename: "/etc/zope.conf", Content: fmt.Sprintf("instance=%d\n", instanceID)},
+		{Filename: "/etc/zope.ini", Content: "password=<redacted>"},
+	}, nil
+}
+
+func (t ServiceAPITest) GetServiceDependencyGraph(serviceID string) (*service.DependencyGraph, error) {
+	if t.errs["GetServiceDependencyGraph"] != nil {
+		return nil, t.errs["GetServiceDependencyGraph"]
+	}
+	return &service.DependencyGraph{
+		TenantID: "test-service-1",
+		Nodes: []service.DependencyNode{
+			{ServiceID: "test-service-1", Name: "Zenoss", CurrentState: service.SVCCSRunning, RunningInstances: 1, Instances: 1},
+			{ServiceID: "test-service-2", Name: "Zope", CurrentState: service.SVCCSStopped, Instances: 1},
+		},
+		Edges: []service.DependencyEdge{
+			{ImporterID: "test-service-1", ExporterID: "test-service-2", Application: "zope", OutOfOrder: true},
+		},
+		Unresolved: []service.DependencyImport{{ServiceID: "test-service-2", Application: "mariadb"}},
+		Cycles:     [][]string{},
+	}, nil
+}
+
+func (t ServiceAPITest) GetRollingRestartStatus(serviceID string) ([]service.RollingRestartStatus, error) {
+	if t.errs["GetRollingRestartStatus"] != nil {
+		return nil, t.errs["GetRollingRestartStatus"]
+	}
+	statuses := []service.RollingRestartStatus{
+		{ServiceID: "test-service-2", ServiceName: "Zope", State: service.RollingRestartCompleted, Instances: 2, BatchSize: 1, Batch: 2, Batches: 2, Restarted: 2, Message: "restarted 2 instances"},
+		{ServiceID: "test-service-3", ServiceName: "zencommand", State: service.RollingRestartHalted, Instances: 2, BatchSize: 1, Batch: 1, Batches: 2, Restarted: 1, Message: "batch 1 did not become healthy"},
+	}
+	if serviceID == "" {
+		return statuses, nil
+	}
+	for _, status := range statuses {
+		if status.ServiceID == serviceID {
+			return []service.RollingRestartStatus{status}, nil
+		}
+	}
+	return []service.RollingRestartStatus{}, nil
+}
+
+func (t ServiceAPITest) SetServiceImage(serviceID, imageID string, rollout *service.RollingRestart) (bool, error) {
+	if t.errs["SetServiceImage"] != nil {
+		return false, t.errs["SetServiceImage"]
+	}
+	return rollout != nil, nil
+}
+
+func (t ServiceAPITest) GetServiceConfigDrift(serviceID string) ([]service.ConfigDrift, error) {
+	if t.errs["GetServiceConfigDrift"] != nil {
+		return nil, t.errs["GetServiceConfigDrift"]
+	}
+	if serviceID != "test-service-2" {
+		return nil, nil
+	}
+	return []service.ConfigDrift{
+		{ServiceID: serviceID, InstanceID: 0, HostID: "hostID1", Filename: "/etc/zope.conf", Status: service.ConfigDriftChanged},
+	}, nil
+}
+
 func TestServicedCLI_CmdServiceList_one(t *testing.T) {
 	serviceID := "test-service-1"
 
@@ -920,6 +990,12 @@ func ExampleServicedCLI_CmdServiceRestart_usage() {
 	//    --auto-launch	Recursively schedules child services
 	//    --sync, -s		Schedules services synchronously
 	//    --rebalance		Stops all instances before restarting them, instead of performing a rolling restart
+	//    --batch-size '0'	Number of instances to restart at a time
+	//    --batch-percent '0'	Percentage of instances to restart at a time
+	//    --pause 		Time to wait between batches (e.g. 30s)
+	//    --health-timeout 	Time a batch has to start and pass its health checks
+	//    --window 		Time a batch must stay up and healthy after it starts
+	//    --halt		Stops the restart when a batch does not become healthy in time
 }
 
 func ExampleServicedCLI_CmdServiceRestart_fail() {
@@ -962,6 +1038,91 @@ func ExampleServicedCLI_CmdServiceRestart() {
 
 }
 
+func ExampleServicedCLI_CmdServiceRestart_rollingOptions() {
+	InitServiceAPITest("serviced", "service", "restart", "--batch-size", "2", "--pause", "10s", "--halt", "test-service-2")
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "restart", "--pause=-5s", "test-service-2") })
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "restart", "--batch-size", "2", "--batch-percent", "50", "test-service-2")
+	})
+
+	// Output:
+	// Restarting 1 service(s)
+	// pause, timeout and window must not be negative
+	// batch size and batch percent cannot both be set
+}
+
+func ExampleServicedCLI_CmdServiceSetImage_usage() {
+	InitServiceAPITest("serviced", "service", "set-image", "test-service-1")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    set-image - Changes the image of a service
+	//
+	// USAGE:
+	//    command set-image [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced service set-image { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME } IMAGEID
+	//
+	// OPTIONS:
+	//    --rolling		Restarts running instances onto the new image in batches, reverting if a batch fails
+	//    --batch-size '0'	Number of instances to restart at a time
+	//    --batch-percent '0'	Percentage of instances to restart at a time
+	//    --pause 		Time to wait between batches (e.g. 30s)
+	//    --health-timeout 	Time a batch has to start and pass its health checks
+	//    --window 		Time a batch must stay up and healthy after it starts (default 30s)
+	//    --no-revert		Leaves the new image in place when the rollout halts
+}
+
+func ExampleServicedCLI_CmdServiceSetImage() {
+	InitServiceAPITest("serviced", "service", "set-image", "test-service-2", "zenoss/core:2")
+	InitServiceAPITest("serviced", "service", "set-image", "--rolling", "--batch-percent", "50", "test-service-2", "zenoss/core:2")
+
+	// Output:
+	// Set the image of Zope to zenoss/core:2
+	// Rolling out zenoss/core:2 to Zope; see serviced service restart-status test-service-2
+}
+
+func ExampleServicedCLI_CmdServiceSetImage_err() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "set-image", "test-service-0", "zenoss/core:2") })
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "set-image", "--rolling", "--window=-1s", "test-service-2", "zenoss/core:2")
+	})
+	DefaultServiceAPITest.errs["SetServiceImage"] = errors.New("service already uses this image")
+	defer func() { DefaultServiceAPITest.errs["SetServiceImage"] = nil }()
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "set-image", "test-service-2", "zenoss/core:2") })
+
+	// Output:
+	// service not found
+	// pause, timeout and window must not be negative
+	// service already uses this image
+}
+
+func ExampleServicedCLI_CmdServiceRestartStatus() {
+	InitServiceAPITest("serviced", "service", "restart-status")
+
+	// Output:
+	// ServiceID         Name          State        Batch    Restarted    Message
+	// test-service-2    Zope          completed    2/2      2/2          restarted 2 instances
+	// test-service-3    zencommand    halted       1/2      1/2          batch 1 did not become healthy
+}
+
+func ExampleServicedCLI_CmdServiceRestartStatus_none() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "restart-status", "test-service-1") })
+
+	// Output:
+	// no rolling restarts found
+}
+
+func ExampleServicedCLI_CmdServiceRestartStatus_err() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "restart-status", "test-service-0") })
+
+	// Output:
+	// service not found
+}
+
 func ExampleServicedCLI_CmdServiceStop_usage() {
 	InitServiceAPITest("serviced", "service", "stop")
 
@@ -1379,3 +1540,80 @@ func ExampleServicedCLI_CmdServiceClearEmergency_usage() {
 	// OPTIONS:
 	//
 }
+
+func ExampleServicedCLI_CmdServiceConfigRender_err() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "config", "render", "test-service-0") })
+
+	// Output:
+	// service not found
+}
+
+func ExampleServicedCLI_CmdServiceConfigRender_worksNoConfigs() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "config", "render", "test-service-1") })
+
+	// Output:
+	// Zenoss - no config files defined
+}
+
+func ExampleServicedCLI_CmdServiceConfigRender_works() {
+	InitServiceAPITest("serviced", "service", "config", "render", "test-service-2")
+
+	// Output:
+	// ==> /etc/zope.conf <==
+	// instance=0
+	//
+	// ==> /etc/zope.ini <==
+	// password=<redacted>
+}
+
+func ExampleServicedCLI_CmdServiceConfigDrift_worksNoDrift() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "config", "drift", "test-service-1") })
+
+	// Output:
+	// Zenoss - no config drift found
+}
+
+func ExampleServicedCLI_CmdServiceConfigDrift_works() {
+	InitServiceAPITest("serviced", "service", "config", "drift", "test-service-2")
+
+	// Output:
+	// Name      HostID     Filename          Status
+	// Zope/0    hostID1    /etc/zope.conf    changed
+}
+
+func ExampleServicedCLI_CmdServiceGraph_err() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "graph", "test-service-0") })
+
+	// Output:
+	// service not found
+}
+
+func ExampleServicedCLI_CmdServiceGraph_badFormat() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "graph", "--format", "svg", "test-service-1") })
+
+	// Output:
+	// unknown format "svg"; use dot or json
+}
+
+func ExampleServicedCLI_CmdServiceGraph_works() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "graph", "test-service-1") })
+
+	// Output:
+	// digraph "test-service-1" {
+	// 	"test-service-1" [label="Zenoss\nstarted 1/1" color=darkgreen];
+	// 	"test-service-2" [label="Zope\nstopped 0/1" color=gray];
+	// 	"test-service-1" -> "test-service-2" [label="zope" style=dashed];
+	// 	"unresolved-0" [label="mariadb" shape=box style=dashed color=red];
+	// 	"test-service-2" -> "unresolved-0" [color=red];
+	// }
+	// Zope imports mariadb, which is not exported by any service
+}
+
+func ExampleServicedCLI_CmdServiceGraph_impact() {
+	InitServiceAPITest("serviced", "service", "graph", "--impact", "test-service-1", "test-service-1")
+
+	// Output:
+	// digraph "test-service-1" {
+	// 	"test-service-1" [label="Zenoss\nstarted 1/1" color=darkgreen];
+	// }
+}
@@ -14,13 +14,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/snapshotschedule"
+	"github.com/control-center/serviced/volume"
 )
 
 // initSnapshot is the initializer for serviced snapshot
@@ -90,6 +94,31 @@ func (c *ServicedCli) initSnapshot() {
 				},
 				BashComplete: c.printSnapshotsFirst,
 				Action:       c.cmdSnapshotRollback,
+			}, {
+				Name:        "clone",
+				Usage:       "Creates a new application from the given snapshot",
+				Description: "serviced snapshot clone SNAPSHOTID --name NAME",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "name",
+						Value: "",
+						Usage: "name of the new application",
+					},
+				},
+				BashComplete: c.printSnapshotsFirst,
+				Action:       c.cmdSnapshotClone,
+			}, {
+				Name:        "diff",
+				Usage:       "Shows the files and services that differ between two snapshots",
+				Description: "serviced snapshot diff SNAPSHOTID [SNAPSHOTID | live]",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+				BashComplete: c.printSnapshotsAll,
+				Action:       c.cmdSnapshotDiff,
 			}, {
 				Name:         "tag",
 				Usage:        "Tags an existing snapshot with TAG-NAME",
@@ -102,6 +131,69 @@ func (c *ServicedCli) initSnapshot() {
 				Description:  "serviced snapshot untag SERVICEID TAG-NAME",
 				BashComplete: c.printServicesFirst,
 				Action:       c.cmdSnapshotRemoveTag,
+			}, {
+				Name:        "schedule",
+				Usage:       "Manages scheduled snapshots and how long they are kept",
+				Description: "serviced snapshot schedule",
+				Subcommands: []cli.Command{
+					{
+						Name:         "set",
+						Usage:        "Snapshots the tenant of a service on a schedule",
+						Description:  "serviced snapshot schedule set SERVICEID (--interval INTERVAL | --cron EXPRESSION)",
+						BashComplete: c.printServicesFirst,
+						Action:       c.cmdSnapshotScheduleSet,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "interval",
+								Value: "",
+								Usage: "time between snapshots (e.g. 30m, 4h)",
+							},
+							cli.StringFlag{
+								Name:  "cron",
+								Value: "",
+								Usage: "cron expression of when to take snapshots, in the master's time zone (e.g. \"0 2 * * *\")",
+							},
+							cli.IntFlag{
+								Name:  "keep-hourly",
+								Value: 0,
+								Usage: "number of hours to keep the latest snapshot of",
+							},
+							cli.IntFlag{
+								Name:  "keep-daily",
+								Value: 0,
+								Usage: "number of days to keep the latest snapshot of",
+							},
+							cli.IntFlag{
+								Name:  "keep-weekly",
+								Value: 0,
+								Usage: "number of weeks to keep the latest snapshot of",
+							},
+						},
+					}, {
+						Name:        "list",
+						Usage:       "Lists snapshot schedules",
+						Description: "serviced snapshot schedule list",
+						Action:      c.cmdSnapshotScheduleList,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "verbose, v",
+								Usage: "Show JSON format",
+							},
+							cli.StringFlag{
+								Name:  "show-fields",
+								Value: "TenantID,Frequency,Retention,NextRun",
+								Usage: "Comma-delimited list describing which fields to display",
+							},
+						},
+					}, {
+						Name:         "remove",
+						ShortName:    "rm",
+						Usage:        "Stops scheduled snapshots of the tenant of a service",
+						Description:  "serviced snapshot schedule remove SERVICEID ...",
+						BashComplete: c.printServicesAll,
+						Action:       c.cmdSnapshotScheduleRemove,
+					},
+				},
 			},
 		},
 	})
@@ -349,6 +441,80 @@ func (c *ServicedCli) cmdSnapshotRollback(ctx *cli.Context) {
 	}
 }
 
+// serviced snapshot clone SNAPSHOTID --name NAME
+func (c *ServicedCli) cmdSnapshotClone(ctx *cli.Context) {
+	args := ctx.Args()
+	name := strings.TrimSpace(ctx.String("name"))
+	if len(args) != 1 || name == "" {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "clone")
+		return
+	}
+
+	if tenantID, err := c.driver.CloneSnapshot(args[0], name); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+	} else {
+		fmt.Println(tenantID)
+	}
+}
+
+// serviced snapshot diff SNAPSHOTID [SNAPSHOTID | live]
+func (c *ServicedCli) cmdSnapshotDiff(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "diff")
+		return
+	}
+
+	// compare with the current state of the application by default
+	toSnapshotID := ""
+	if len(args) == 2 && args[1] != "live" {
+		toSnapshotID = args[1]
+	}
+
+	diff, err := c.driver.DiffSnapshots(args[0], toSnapshotID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonDiff, err := json.MarshalIndent(diff, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal snapshot diff: %s", err)
+		} else {
+			fmt.Println(string(jsonDiff))
+		}
+		return
+	} else if len(diff.Files) == 0 && len(diff.Services) == 0 {
+		fmt.Fprintln(os.Stderr, "no differences found")
+		return
+	}
+
+	if len(diff.Services) > 0 {
+		fmt.Println("Services:")
+		for _, change := range diff.Services {
+			if len(change.Fields) > 0 {
+				fmt.Printf("  %-8s  %s (%s)\n", change.Change, change.Path, strings.Join(change.Fields, ", "))
+			} else {
+				fmt.Printf("  %-8s  %s\n", change.Change, change.Path)
+			}
+		}
+	}
+	if len(diff.Files) > 0 {
+		fmt.Println("Files:")
+		for _, change := range diff.Files {
+			size := change.Size
+			if change.Change == volume.FileRemoved {
+				size = change.OldSize
+			}
+			fmt.Printf("  %-8s  %12d  %s\n", change.Change, size, change.Path)
+		}
+	}
+}
+
 // serviced snapshot tag SNAPSHOTID TAG-NAME
 func (c *ServicedCli) cmdSnapshotTag(ctx *cli.Context) {
 	args := ctx.Args()
@@ -391,3 +557,90 @@ func (c *ServicedCli) cmdSnapshotRemoveTag(ctx *cli.Context) {
 	}
 	fmt.Printf("%s\n", snapshotID)
 }
+
+// serviced snapshot schedule set SERVICEID (--interval INTERVAL | --cron EXPRESSION) [--keep-hourly N] [--keep-daily N] [--keep-weekly N]
+func (c *ServicedCli) cmdSnapshotScheduleSet(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 || (ctx.String("interval") == "") == (ctx.String("cron") == "") {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set")
+		return
+	}
+
+	var interval time.Duration
+	if value := ctx.String("interval"); value != "" {
+		var err error
+		if interval, err = time.ParseDuration(value); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid interval %q: must be a duration such as 30m or 4h\n", value)
+			return
+		}
+	}
+	retention := snapshotschedule.Retention{
+		Hourly: ctx.Int("keep-hourly"),
+		Daily:  ctx.Int("keep-daily"),
+		Weekly: ctx.Int("keep-weekly"),
+	}
+
+	schedule, err := c.driver.SetSnapshotSchedule(args[0], interval, ctx.String("cron"), retention)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(schedule.TenantID)
+}
+
+// serviced snapshot schedule list
+func (c *ServicedCli) cmdSnapshotScheduleList(ctx *cli.Context) {
+	schedules, err := c.driver.GetSnapshotSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(schedules) == 0 {
+		fmt.Fprintln(os.Stderr, "no snapshot schedules found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonSchedules, err := json.MarshalIndent(schedules, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal snapshot schedule list: %s", err)
+		} else {
+			fmt.Println(string(jsonSchedules))
+		}
+	} else {
+		t := NewTable(ctx.String("show-fields"))
+		t.Padding = 6
+		for _, schedule := range schedules {
+			nextRun := ""
+			if next, err := schedule.Next(); err == nil && !next.IsZero() {
+				nextRun = next.UTC().Format(time.RFC3339)
+			}
+			t.AddRow(map[string]interface{}{
+				"TenantID":  schedule.TenantID,
+				"Frequency": schedule.Frequency(),
+				"Retention": schedule.Retention.String(),
+				"LastRun":   schedule.LastRun.Format(time.RFC3339),
+				"NextRun":   nextRun,
+				"CreatedBy": schedule.CreatedBy,
+			})
+		}
+		t.Print()
+	}
+}
+
+// serviced snapshot schedule remove SERVICEID ...
+func (c *ServicedCli) cmdSnapshotScheduleRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	for _, serviceID := range args {
+		if err := c.driver.RemoveSnapshotSchedule(serviceID); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", serviceID, err)
+		} else {
+			fmt.Println(serviceID)
+		}
+	}
+}
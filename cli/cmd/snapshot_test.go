@@ -20,10 +20,13 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/snapshotschedule"
 	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/volume"
 	"github.com/control-center/serviced/volume/btrfs"
 )
 
@@ -145,6 +148,41 @@ func (t SnapshotAPITest) Rollback(id string, f bool) error {
 	return t.RemoveSnapshot(id)
 }
 
+func (t SnapshotAPITest) CloneSnapshot(id, name string) (string, error) {
+	if ok, err := t.hasSnapshot(id); err != nil {
+		return "", err
+	} else if !ok {
+		return "", ErrNoSnapshotFound
+	}
+	return fmt.Sprintf("%s-clone", name), nil
+}
+
+func (t SnapshotAPITest) DiffSnapshots(id, toID string) (*dao.SnapshotDiff, error) {
+	for _, snapshotID := range []string{id, toID} {
+		if snapshotID == "" {
+			continue
+		}
+		if ok, err := t.hasSnapshot(snapshotID); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, ErrNoSnapshotFound
+		}
+	}
+	return &dao.SnapshotDiff{
+		SnapshotID:   id,
+		ToSnapshotID: toID,
+		Files: []volume.FileChange{
+			{Path: "etc/app.conf", Change: volume.FileModified, Size: 1024, OldSize: 512},
+			{Path: "var/lib/new.db", Change: volume.FileAdded, Size: 4096},
+			{Path: "var/log/old.log", Change: volume.FileRemoved, OldSize: 100},
+		},
+		Services: []dao.ServiceChange{
+			{ServiceID: "svc-a", Path: "/app/alpha", Change: dao.ServiceModified, Fields: []string{"Instances", "Startup"}},
+			{ServiceID: "svc-b", Path: "/app/beta", Change: dao.ServiceAdded},
+		},
+	}, nil
+}
+
 func (t SnapshotAPITest) TagSnapshot(snapshotID string, tagName string) error {
 	if t.fail {
 		return ErrInvalidSnapshot
@@ -527,6 +565,77 @@ func ExampleServicedCLI_CmdSnapshotRollback_usage() {
 	//    --force-restart	restarts running services during rollback
 }
 
+func ExampleServicedCLI_CmdSnapshotClone() {
+	InitSnapshotAPITest("serviced", "snapshot", "clone", "test-service-1-snapshot-1", "--name", "staging")
+
+	// Output:
+	// staging-clone
+}
+
+func ExampleServicedCLI_CmdSnapshotClone_usage() {
+	InitSnapshotAPITest("serviced", "snapshot", "clone", "test-service-1-snapshot-1")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    clone - Creates a new application from the given snapshot
+	//
+	// USAGE:
+	//    command clone [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced snapshot clone SNAPSHOTID --name NAME
+	//
+	// OPTIONS:
+	//    --name 	name of the new application
+}
+
+func ExampleServicedCLI_CmdSnapshotDiff() {
+	InitSnapshotAPITest("serviced", "snapshot", "diff", "test-service-1-snapshot-1", "test-service-1-snapshot-2")
+
+	// Output:
+	// Services:
+	//   modified  /app/alpha (Instances, Startup)
+	//   added     /app/beta
+	// Files:
+	//   modified          1024  etc/app.conf
+	//   added             4096  var/lib/new.db
+	//   removed            100  var/log/old.log
+}
+
+func ExampleServicedCLI_CmdSnapshotDiff_live() {
+	InitSnapshotAPITest("serviced", "snapshot", "diff", "test-service-1-snapshot-1", "live")
+
+	// Output:
+	// Services:
+	//   modified  /app/alpha (Instances, Startup)
+	//   added     /app/beta
+	// Files:
+	//   modified          1024  etc/app.conf
+	//   added             4096  var/lib/new.db
+	//   removed            100  var/log/old.log
+}
+
+func ExampleServicedCLI_CmdSnapshotDiff_usage() {
+	InitSnapshotAPITest("serviced", "snapshot", "diff")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    diff - Shows the files and services that differ between two snapshots
+	//
+	// USAGE:
+	//    command diff [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced snapshot diff SNAPSHOTID [SNAPSHOTID | live]
+	//
+	// OPTIONS:
+	//    --verbose, -v	Show JSON format
+}
+
 /*
 this command exits 1 which fails the test runner
 func ExampleServicedCLI_CmdSnapshotRollback_err() {
@@ -619,3 +728,101 @@ func ExampleServicedCLI_CmdSnapshotUntag_btrfsFail() {
 	// Output:
 	// operation not supported on btrfs driver
 }
+
+var ErrNoSnapshotSchedule = errors.New("snapshot schedule not found")
+
+type SnapshotScheduleAPITest struct {
+	api.API
+	schedules []snapshotschedule.SnapshotSchedule
+}
+
+func DefaultSnapshotScheduleAPI() *SnapshotScheduleAPITest {
+	lastRun := time.Date(2017, 6, 1, 2, 0, 0, 0, time.UTC)
+	return &SnapshotScheduleAPITest{
+		schedules: []snapshotschedule.SnapshotSchedule{
+			{TenantID: "test-service-1", Interval: 4 * time.Hour, Retention: snapshotschedule.Retention{Hourly: 24, Weekly: 4}, LastRun: lastRun},
+			{TenantID: "test-service-2", Cron: "0 2 * * *", LastRun: lastRun},
+		},
+	}
+}
+
+func (t *SnapshotScheduleAPITest) SetSnapshotSchedule(serviceID string, interval time.Duration, cron string, retention snapshotschedule.Retention) (*snapshotschedule.SnapshotSchedule, error) {
+	schedule := snapshotschedule.SnapshotSchedule{TenantID: serviceID, Interval: interval, Cron: cron, Retention: retention}
+	if err := schedule.ValidEntity(); err != nil {
+		return nil, err
+	}
+	t.schedules = append(t.schedules, schedule)
+	return &schedule, nil
+}
+
+func (t *SnapshotScheduleAPITest) GetSnapshotSchedules() ([]snapshotschedule.SnapshotSchedule, error) {
+	return t.schedules, nil
+}
+
+func (t *SnapshotScheduleAPITest) RemoveSnapshotSchedule(serviceID string) error {
+	for i, schedule := range t.schedules {
+		if schedule.TenantID == serviceID {
+			t.schedules = append(t.schedules[:i], t.schedules[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNoSnapshotSchedule
+}
+
+func ExampleServicedCLI_CmdSnapshotScheduleSet() {
+	test := DefaultSnapshotScheduleAPI()
+	RunCmd(test, "serviced", "snapshot", "schedule", "set", "test-service-3", "--interval", "6h", "--keep-daily", "7")
+	fmt.Println(test.schedules[2].Frequency(), test.schedules[2].Retention)
+	pipeStderr(func() { RunCmd(test, "serviced", "snapshot", "schedule", "set", "test-service-3", "--interval", "often") })
+
+	// Output:
+	// test-service-3
+	// every 6h0m0s 7 daily
+	// invalid interval "often": must be a duration such as 30m or 4h
+}
+
+func ExampleServicedCLI_CmdSnapshotScheduleSet_usage() {
+	RunCmd(DefaultSnapshotScheduleAPI(), "serviced", "snapshot", "schedule", "set", "test-service-3", "--interval", "1h", "--cron", "0 * * * *")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    set - Snapshots the tenant of a service on a schedule
+	//
+	// USAGE:
+	//    command set [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced snapshot schedule set SERVICEID (--interval INTERVAL | --cron EXPRESSION)
+	//
+	// OPTIONS:
+	//    --interval 		time between snapshots (e.g. 30m, 4h)
+	//    --cron 		cron expression of when to take snapshots, in the master's time zone (e.g. "0 2 * * *")
+	//    --keep-hourly '0'	number of hours to keep the latest snapshot of
+	//    --keep-daily '0'	number of days to keep the latest snapshot of
+	//    --keep-weekly '0'	number of weeks to keep the latest snapshot of
+}
+
+func ExampleServicedCLI_CmdSnapshotScheduleList() {
+	// cron expressions are evaluated in the local time zone
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.UTC
+	RunCmd(DefaultSnapshotScheduleAPI(), "serviced", "snapshot", "schedule", "list")
+
+	// Output:
+	// TenantID            Frequency         Retention                NextRun
+	// test-service-1      every 4h0m0s      24 hourly, 4 weekly      2017-06-01T06:00:00Z
+	// test-service-2      0 2 * * *         snapshot-ttl             2017-06-02T02:00:00Z
+}
+
+func ExampleServicedCLI_CmdSnapshotScheduleRemove() {
+	test := DefaultSnapshotScheduleAPI()
+	pipeStderr(func() { RunCmd(test, "serviced", "snapshot", "schedule", "rm", "test-service-1", "nope") })
+	fmt.Println(len(test.schedules))
+
+	// Output:
+	// test-service-1
+	// nope: snapshot schedule not found
+	// 1
+}
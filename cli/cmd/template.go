@@ -14,16 +14,20 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"syscall"
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
 	template "github.com/control-center/serviced/domain/servicetemplate"
 	"github.com/control-center/serviced/servicedversion"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 // initTemplate is the initializer for serviced template
@@ -73,6 +77,11 @@ func (c *ServicedCli) initTemplate() {
 						Name:  "manual-assign-ips",
 						Usage: "Manually assign IP addresses",
 					},
+					cli.StringSliceFlag{
+						Name:  "param",
+						Value: &cli.StringSlice{},
+						Usage: "Value of a template parameter (e.g. --param dbhost=db.example.com)",
+					},
 				},
 			}, {
 				Name:        "compile",
@@ -87,6 +96,12 @@ func (c *ServicedCli) initTemplate() {
 					},
 				},
 			},
+			{
+				Name:        "lint",
+				Usage:       "Check a directory of service definitions or a compiled template for likely mistakes",
+				Description: "serviced template lint PATH",
+				Action:      c.cmdTemplateLint,
+			},
 		},
 	})
 }
@@ -237,7 +252,7 @@ func (c *ServicedCli) cmdTemplateRemove(ctx *cli.Context) {
 	}
 }
 
-// serviced template deploy TEMPLATEID POOLID DEPLOYMENTID [--manual-assign-ips]
+// serviced template deploy TEMPLATEID POOLID DEPLOYMENTID [--manual-assign-ips] [--param NAME=VALUE ...]
 func (c *ServicedCli) cmdTemplateDeploy(ctx *cli.Context) {
 	args := ctx.Args()
 	if len(args) < 3 {
@@ -246,11 +261,32 @@ func (c *ServicedCli) cmdTemplateDeploy(ctx *cli.Context) {
 		return
 	}
 
+	params := make(map[string]string)
+	for _, param := range ctx.StringSlice("param") {
+		parts := strings.SplitN(param, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			fmt.Fprintf(os.Stderr, "invalid template parameter %s: expected NAME=VALUE\n", param)
+			return
+		}
+		params[parts[0]] = parts[1]
+	}
+
+	// prompt for any required parameters that were not passed in
+	if terminal.IsTerminal(syscall.Stdin) {
+		if tpl, err := c.driver.GetServiceTemplate(args[0]); err == nil && tpl != nil {
+			if err := promptTemplateParameters(tpl.Parameters, params, os.Stdin, os.Stderr); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return
+			}
+		}
+	}
+
 	cfg := api.DeployTemplateConfig{
 		ID:              args[0],
 		PoolID:          args[1],
 		DeploymentID:    args[2],
 		ManualAssignIPs: ctx.Bool("manual-assign-ips"),
+		Parameters:      params,
 	}
 
 	fmt.Fprintln(os.Stderr, "Deploying template - please wait...")
@@ -265,6 +301,28 @@ func (c *ServicedCli) cmdTemplateDeploy(ctx *cli.Context) {
 	}
 }
 
+// promptTemplateParameters asks for the value of each required parameter
+// that has neither a value nor a default
+func promptTemplateParameters(declared []template.TemplateParameter, params map[string]string, r io.Reader, w io.Writer) error {
+	reader := bufio.NewReader(r)
+	for _, p := range declared {
+		if !p.Required || p.Default != "" || params[p.Name] != "" {
+			continue
+		}
+		if p.Description != "" {
+			fmt.Fprintf(w, "%s (%s): ", p.Name, p.Description)
+		} else {
+			fmt.Fprintf(w, "%s: ", p.Name)
+		}
+		value, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("could not read value for template parameter %s: %s", p.Name, err)
+		}
+		params[p.Name] = strings.TrimSpace(value)
+	}
+	return nil
+}
+
 type metaTemplate struct {
 	template.ServiceTemplate
 	ServicedVersion servicedversion.ServicedVersion
@@ -325,3 +383,27 @@ func (c *ServicedCli) cmdTemplateCompile(ctx *cli.Context) {
 		}
 	}
 }
+
+// serviced template lint PATH
+func (c *ServicedCli) cmdTemplateLint(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "lint")
+		return
+	}
+
+	warnings, err := c.driver.LintServiceTemplate(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	for _, warning := range warnings {
+		fmt.Println(warning)
+	}
+	if len(warnings) > 0 {
+		fmt.Fprintf(os.Stderr, "%d warning(s) found\n", len(warnings))
+		c.exit(1)
+	}
+}
@@ -18,6 +18,7 @@ package cmd
 import (
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	template "github.com/control-center/serviced/domain/servicetemplate"
 	"github.com/control-center/serviced/utils"
 
@@ -62,7 +63,9 @@ type TemplateAPITest struct {
 }
 
 func InitTemplateAPITest(args ...string) {
-	New(DefaultTemplateAPITest, utils.TestConfigReader(make(map[string]string)), MockLogControl{}).Run(args)
+	c := New(DefaultTemplateAPITest, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
 }
 
 func (t TemplateAPITest) GetServiceTemplates() ([]template.ServiceTemplate, error) {
@@ -118,6 +121,18 @@ func (t TemplateAPITest) CompileServiceTemplate(cfg api.CompileTemplateConfig) (
 	return &tpl, nil
 }
 
+func (t TemplateAPITest) LintServiceTemplate(path string) ([]servicedefinition.LintWarning, error) {
+	if t.fail {
+		return nil, ErrInvalidTemplate
+	} else if path == NilTemplate {
+		return nil, nil
+	}
+	return []servicedefinition.LintWarning{
+		{Path: "$.Services[0].Endpoints[1]", Message: "imports zope, which is not exported by any service"},
+		{Path: "$.Services[0].LogConfigs[0].Filters[0]", Message: "log filter zope is not defined by the service or its parents"},
+	}, nil
+}
+
 func (t TemplateAPITest) DeployServiceTemplate(cfg api.DeployTemplateConfig) ([]service.ServiceDetails, error) {
 	tpl, err := t.GetServiceTemplate(cfg.ID)
 	if err != nil {
@@ -274,7 +289,8 @@ func ExampleServicedCLI_CmdTemplateDeploy_usage() {
 	//    serviced template deploy TEMPLATEID POOLID DEPLOYMENTID
 	//
 	// OPTIONS:
-	//    --manual-assign-ips	Manually assign IP addresses
+	//    --manual-assign-ips				Manually assign IP addresses
+	//    --param '--param option --param option'	Value of a template parameter (e.g. --param dbhost=db.example.com)
 }
 
 func ExampleServicedCLI_CmdTemplateDeploy_fail() {
@@ -353,3 +369,45 @@ func ExampleServicedCLI_CmdTemplateCompile_err() {
 	// Output:
 	// received nil template
 }
+
+func ExampleServicedCLI_CmdTemplateLint_usage() {
+	InitTemplateAPITest("serviced", "template", "lint")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    lint - Check a directory of service definitions or a compiled template for likely mistakes
+	//
+	// USAGE:
+	//    command lint [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced template lint PATH
+	//
+	// OPTIONS:
+}
+
+func ExampleServicedCLI_CmdTemplateLint_fail() {
+	DefaultTemplateAPITest.fail = true
+	defer func() { DefaultTemplateAPITest.fail = false }()
+	pipeStderr(func() { InitTemplateAPITest("serviced", "template", "lint", "/path/to/template") })
+
+	// Output:
+	// invalid template
+}
+
+func ExampleServicedCLI_CmdTemplateLint_clean() {
+	InitTemplateAPITest("serviced", "template", "lint", NilTemplate)
+
+	// Output:
+}
+
+func ExampleServicedCLI_CmdTemplateLint() {
+	pipeStderr(func() { InitTemplateAPITest("serviced", "template", "lint", "/path/to/template") })
+
+	// Output:
+	// $.Services[0].Endpoints[1]: imports zope, which is not exported by any service
+	// $.Services[0].LogConfigs[0].Filters[0]: log filter zope is not defined by the service or its parents
+	// 2 warning(s) found
+}
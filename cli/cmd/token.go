@@ -0,0 +1,174 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/apitoken"
+)
+
+// Initializer for serviced token subcommands
+func (c *ServicedCli) initToken() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "token",
+		Usage:       "Administers API tokens",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "create",
+				Usage:       "Creates an API token for use as a bearer token",
+				Description: "serviced token create NAME",
+				Action:      c.cmdTokenCreate,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "expires",
+						Value: "720h",
+						Usage: "How long the token is valid for",
+					},
+					cli.BoolFlag{
+						Name:  "read-only",
+						Usage: "Only allow requests that make no changes",
+					},
+					cli.StringSliceFlag{
+						Name:  "tenant",
+						Value: &cli.StringSlice{},
+						Usage: "Tenant ID whose services the token may address; may be repeated",
+					},
+					cli.StringSliceFlag{
+						Name:  "verb",
+						Value: &cli.StringSlice{},
+						Usage: "HTTP method the token may use (GET, POST, PUT, DELETE); may be repeated",
+					},
+				},
+			}, {
+				Name:        "list",
+				Usage:       "Lists API tokens",
+				Description: "serviced token list",
+				Action:      c.cmdTokenList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "ID,Name,Scope,ExpiresAt,Status",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			}, {
+				Name:        "revoke",
+				Usage:       "Revokes API tokens",
+				Description: "serviced token revoke TOKENID|NAME ...",
+				Action:      c.cmdTokenRevoke,
+			},
+		},
+	})
+}
+
+// serviced token create NAME [--expires DURATION] [--read-only] [--tenant TENANTID ...] [--verb VERB ...]
+func (c *ServicedCli) cmdTokenCreate(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "create")
+		return
+	}
+
+	expires, err := time.ParseDuration(ctx.String("expires"))
+	if err != nil || expires <= 0 {
+		fmt.Fprintf(os.Stderr, "invalid expiry %q: must be a positive duration such as 24h\n", ctx.String("expires"))
+		return
+	}
+
+	scope := apitoken.Scope{
+		ReadOnly: ctx.Bool("read-only"),
+		Tenants:  ctx.StringSlice("tenant"),
+	}
+	for _, verb := range ctx.StringSlice("verb") {
+		if !apitoken.IsVerb(verb) {
+			fmt.Fprintf(os.Stderr, "invalid verb %q: must be one of %s\n", verb, strings.Join(apitoken.Verbs, ", "))
+			return
+		}
+		scope.Verbs = append(scope.Verbs, strings.ToUpper(verb))
+	}
+
+	token, _, err := c.driver.CreateAPIToken(args[0], scope, time.Now().Add(expires))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Store this token now; it cannot be shown again.")
+	fmt.Println(token)
+}
+
+// serviced token list
+func (c *ServicedCli) cmdTokenList(ctx *cli.Context) {
+	tokens, err := c.driver.GetAPITokens()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(tokens) == 0 {
+		fmt.Fprintln(os.Stderr, "no API tokens found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonTokens, err := json.MarshalIndent(tokens, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal API token list: %s", err)
+		} else {
+			fmt.Println(string(jsonTokens))
+		}
+	} else {
+		now := time.Now()
+		t := NewTable(ctx.String("show-fields"))
+		t.Padding = 6
+		for _, token := range tokens {
+			t.AddRow(map[string]interface{}{
+				"ID":        token.ID,
+				"Name":      token.Name,
+				"Scope":     token.Scope.String(),
+				"CreatedBy": token.CreatedBy,
+				"CreatedAt": token.CreatedAt.Format(time.RFC3339),
+				"ExpiresAt": token.ExpiresAt.Format(time.RFC3339),
+				"Status":    token.Status(now),
+			})
+		}
+		t.Print()
+	}
+}
+
+// serviced token revoke TOKENID|NAME ...
+func (c *ServicedCli) cmdTokenRevoke(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "revoke")
+		return
+	}
+
+	for _, token := range args {
+		if err := c.driver.RevokeAPIToken(token); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", token, err)
+		} else {
+			fmt.Println(token)
+		}
+	}
+}
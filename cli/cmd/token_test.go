@@ -0,0 +1,124 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/apitoken"
+)
+
+var ErrInvalidAPIToken = errors.New("invalid API token")
+
+type TokenAPITest struct {
+	api.API
+	tokens []apitoken.APIToken
+}
+
+func DefaultTokenAPI() *TokenAPITest {
+	return &TokenAPITest{
+		tokens: []apitoken.APIToken{
+			{ID: "0123456789abcdef", Name: "ci", ExpiresAt: time.Now().Add(time.Hour), Scope: apitoken.Scope{ReadOnly: true}},
+			{ID: "fedcba9876543210", Name: "old", ExpiresAt: time.Now().Add(-time.Hour)},
+		},
+	}
+}
+
+func (t *TokenAPITest) CreateAPIToken(name string, scope apitoken.Scope, expires time.Time) (string, *apitoken.APIToken, error) {
+	for _, token := range t.tokens {
+		if token.Name == name {
+			return "", nil, ErrInvalidAPIToken
+		}
+	}
+	token := apitoken.APIToken{ID: "id", Name: name, Scope: scope, ExpiresAt: expires}
+	t.tokens = append(t.tokens, token)
+	return apitoken.Prefix + "id_secret", &token, nil
+}
+
+func (t *TokenAPITest) GetAPITokens() ([]apitoken.APIToken, error) {
+	return t.tokens, nil
+}
+
+func (t *TokenAPITest) RevokeAPIToken(idOrName string) error {
+	for i, token := range t.tokens {
+		if token.ID == idOrName || token.Name == idOrName {
+			t.tokens[i].Revoked = true
+			return nil
+		}
+	}
+	return ErrInvalidAPIToken
+}
+
+func ExampleServicedCLI_CmdTokenCreate() {
+	test := DefaultTokenAPI()
+	RunCmd(test, "serviced", "token", "create", "deploy", "--tenant", "t1", "--verb", "get", "--verb", "POST")
+	fmt.Println(test.tokens[2].Scope.String())
+	pipeStderr(func() { RunCmd(test, "serviced", "token", "create", "ci") })
+	pipeStderr(func() { RunCmd(test, "serviced", "token", "create", "x", "--verb", "PATCH") })
+	pipeStderr(func() { RunCmd(test, "serviced", "token", "create", "x", "--expires", "-1h") })
+
+	// Output:
+	// cct_id_secret
+	// verbs=GET,POST tenants=t1
+	// invalid API token
+	// invalid verb "PATCH": must be one of GET, POST, PUT, DELETE
+	// invalid expiry "-1h": must be a positive duration such as 24h
+}
+
+func ExampleServicedCLI_CmdTokenCreate_usage() {
+	RunCmd(DefaultTokenAPI(), "serviced", "token", "create")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    create - Creates an API token for use as a bearer token
+	//
+	// USAGE:
+	//    command create [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced token create NAME
+	//
+	// OPTIONS:
+	//    --expires '720h'				How long the token is valid for
+	//    --read-only					Only allow requests that make no changes
+	//    --tenant '--tenant option --tenant option'	Tenant ID whose services the token may address; may be repeated
+	//    --verb '--verb option --verb option'		HTTP method the token may use (GET, POST, PUT, DELETE); may be repeated
+}
+
+func ExampleServicedCLI_CmdTokenList() {
+	RunCmd(DefaultTokenAPI(), "serviced", "token", "list", "--show-fields", "Name,Scope,Status")
+
+	// Output:
+	// Name      Scope          Status
+	// ci        read-only      active
+	// old       all            expired
+}
+
+func ExampleServicedCLI_CmdTokenRevoke() {
+	test := DefaultTokenAPI()
+	pipeStderr(func() { RunCmd(test, "serviced", "token", "revoke", "ci", "nope") })
+	fmt.Println(test.tokens[0].Revoked)
+
+	// Output:
+	// ci
+	// nope: invalid API token
+	// true
+}
@@ -0,0 +1,166 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/events"
+)
+
+// Initializer for serviced webhook subcommands
+func (c *ServicedCli) initWebhook() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "webhook",
+		Usage:       "Administers webhooks that receive cluster events",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "add",
+				Usage:       "Subscribes a URL to cluster events",
+				Description: "serviced webhook add URL",
+				Action:      c.cmdWebhookAdd,
+				Flags: []cli.Flag{
+					cli.StringSliceFlag{
+						Name:  "event",
+						Value: &cli.StringSlice{},
+						Usage: "Event type to deliver; may be repeated (default: all)",
+					},
+					cli.StringFlag{
+						Name:  "tenant",
+						Value: "",
+						Usage: "Only deliver events for this tenant ID",
+					},
+					cli.StringFlag{
+						Name:  "secret",
+						Value: "",
+						Usage: "Key used to sign deliveries (default: generated)",
+					},
+				},
+			}, {
+				Name:        "list",
+				Usage:       "Lists webhooks",
+				Description: "serviced webhook list",
+				Action:      c.cmdWebhookList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "ID,URL,Events,TenantID",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			}, {
+				Name:        "remove",
+				ShortName:   "rm",
+				Usage:       "Removes webhooks",
+				Description: "serviced webhook remove WEBHOOKID ...",
+				Action:      c.cmdWebhookRemove,
+			},
+		},
+	})
+}
+
+// serviced webhook add URL [--event TYPE ...] [--tenant TENANTID] [--secret SECRET]
+func (c *ServicedCli) cmdWebhookAdd(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add")
+		return
+	}
+
+	eventTypes := ctx.StringSlice("event")
+	for _, eventType := range eventTypes {
+		if !events.IsType(eventType) {
+			names := make([]string, len(events.Types))
+			for i, t := range events.Types {
+				names[i] = string(t)
+			}
+			fmt.Fprintf(os.Stderr, "invalid event %q: must be one of %s\n", eventType, strings.Join(names, ", "))
+			return
+		}
+	}
+
+	secret, hook, err := c.driver.AddWebhook(args[0], eventTypes, ctx.String("tenant"), ctx.String("secret"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(hook.ID)
+	if ctx.String("secret") == "" {
+		fmt.Fprintln(os.Stderr, "Store this signing secret now; it cannot be shown again.")
+		fmt.Println(secret)
+	}
+}
+
+// serviced webhook list
+func (c *ServicedCli) cmdWebhookList(ctx *cli.Context) {
+	hooks, err := c.driver.GetWebhooks()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(hooks) == 0 {
+		fmt.Fprintln(os.Stderr, "no webhooks found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonHooks, err := json.MarshalIndent(hooks, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal webhook list: %s", err)
+		} else {
+			fmt.Println(string(jsonHooks))
+		}
+	} else {
+		t := NewTable(ctx.String("show-fields"))
+		t.Padding = 6
+		for _, hook := range hooks {
+			t.AddRow(map[string]interface{}{
+				"ID":        hook.ID,
+				"URL":       hook.URL,
+				"Events":    hook.EventList(),
+				"TenantID":  hook.TenantID,
+				"CreatedBy": hook.CreatedBy,
+				"CreatedAt": hook.CreatedAt.Format(time.RFC3339),
+			})
+		}
+		t.Print()
+	}
+}
+
+// serviced webhook remove WEBHOOKID ...
+func (c *ServicedCli) cmdWebhookRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	for _, id := range args {
+		if err := c.driver.RemoveWebhook(id); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", id, err)
+		} else {
+			fmt.Println(id)
+		}
+	}
+}
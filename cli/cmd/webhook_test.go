@@ -0,0 +1,125 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/webhook"
+)
+
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+type WebhookAPITest struct {
+	api.API
+	hooks []webhook.Webhook
+}
+
+func DefaultWebhookAPI() *WebhookAPITest {
+	return &WebhookAPITest{
+		hooks: []webhook.Webhook{
+			{ID: "0123456789abcdef", URL: "https://example.com/hook"},
+			{ID: "fedcba9876543210", URL: "http://ops.example.com/cc", Events: []string{"instance.failed", "host.offline"}, TenantID: "t1"},
+		},
+	}
+}
+
+func (t *WebhookAPITest) AddWebhook(url string, events []string, tenantID, secret string) (string, *webhook.Webhook, error) {
+	if url == "" {
+		return "", nil, ErrInvalidWebhook
+	}
+	if secret == "" {
+		secret = "generated"
+	}
+	hook := webhook.Webhook{ID: "id", URL: url, Events: events, TenantID: tenantID}
+	t.hooks = append(t.hooks, hook)
+	return secret, &hook, nil
+}
+
+func (t *WebhookAPITest) GetWebhooks() ([]webhook.Webhook, error) {
+	return t.hooks, nil
+}
+
+func (t *WebhookAPITest) RemoveWebhook(id string) error {
+	for i, hook := range t.hooks {
+		if hook.ID == id {
+			t.hooks = append(t.hooks[:i], t.hooks[i+1:]...)
+			return nil
+		}
+	}
+	return ErrInvalidWebhook
+}
+
+func ExampleServicedCLI_CmdWebhookAdd() {
+	test := DefaultWebhookAPI()
+	pipeStderr(func() {
+		RunCmd(test, "serviced", "webhook", "add", "https://example.com/new", "--event", "backup.finished", "--tenant", "t1")
+	})
+	fmt.Println(test.hooks[2].EventList(), test.hooks[2].TenantID)
+	RunCmd(test, "serviced", "webhook", "add", "https://example.com/other", "--secret", "s3cret")
+	pipeStderr(func() { RunCmd(test, "serviced", "webhook", "add", "https://example.com/x", "--event", "nope") })
+
+	// Output:
+	// id
+	// generated
+	// Store this signing secret now; it cannot be shown again.
+	// backup.finished t1
+	// id
+	// invalid event "nope": must be one of instance.started, instance.stopped, instance.failed, health.changed, service.desiredstate, service.emergencyshutdown, host.online, host.offline, deployment.started, deployment.finished, backup.started, backup.finished
+}
+
+func ExampleServicedCLI_CmdWebhookAdd_usage() {
+	RunCmd(DefaultWebhookAPI(), "serviced", "webhook", "add")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    add - Subscribes a URL to cluster events
+	//
+	// USAGE:
+	//    command add [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced webhook add URL
+	//
+	// OPTIONS:
+	//    --event '--event option --event option'	Event type to deliver; may be repeated (default: all)
+	//    --tenant 					Only deliver events for this tenant ID
+	//    --secret 					Key used to sign deliveries (default: generated)
+}
+
+func ExampleServicedCLI_CmdWebhookList() {
+	RunCmd(DefaultWebhookAPI(), "serviced", "webhook", "list", "--show-fields", "URL,TenantID,Events")
+
+	// Output:
+	// URL                            TenantID      Events
+	// https://example.com/hook                     all
+	// http://ops.example.com/cc      t1            instance.failed,host.offline
+}
+
+func ExampleServicedCLI_CmdWebhookRemove() {
+	test := DefaultWebhookAPI()
+	pipeStderr(func() { RunCmd(test, "serviced", "webhook", "rm", "0123456789abcdef", "nope") })
+	fmt.Println(len(test.hooks))
+
+	// Output:
+	// 0123456789abcdef
+	// nope: invalid webhook
+	// 1
+}
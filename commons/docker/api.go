@@ -86,6 +86,13 @@ func (err *ImageNotFound) Error() string {
 // it will be executed after the container has been started. Note, if the start parameter is
 // false the container won't be started and the start action will not be executed.
 func NewContainer(cd *dockerclient.CreateContainerOptions, start bool, timeout time.Duration, oncreate ContainerActionFunc, onstart ContainerActionFunc) (*Container, error) {
+	return NewContainerWithHostConfig(cd, nil, start, timeout, oncreate, onstart)
+}
+
+// NewContainerWithHostConfig is NewContainer for containers that need host
+// config fields the vendored go-dockerclient does not support.  If hostConfig
+// is not nil, it is used instead of the host config of the options.
+func NewContainerWithHostConfig(cd *dockerclient.CreateContainerOptions, hostConfig *HostConfig, start bool, timeout time.Duration, oncreate ContainerActionFunc, onstart ContainerActionFunc) (*Container, error) {
 
 	args := struct {
 		containerOptions *dockerclient.CreateContainerOptions
@@ -110,14 +117,20 @@ func NewContainer(cd *dockerclient.CreateContainerOptions, start bool, timeout t
 		return nil, err
 	}
 	glog.V(2).Infof("creating container: %#v", *args.containerOptions)
-	ctr, err := dc.CreateContainer(*args.containerOptions)
+	create := func() (*dockerclient.Container, error) {
+		if hostConfig != nil {
+			return dc.CreateContainerWithHostConfig(*args.containerOptions, hostConfig)
+		}
+		return dc.CreateContainer(*args.containerOptions)
+	}
+	ctr, err := create()
 	switch {
 	case IsImageNotFound(err):
 		if err := PullImage(iid.String()); err != nil {
 			glog.V(2).Infof("Unable to pull image %s: %v", iid.String(), err)
 			return nil, err
 		}
-		ctr, err = dc.CreateContainer(*args.containerOptions)
+		ctr, err = create()
 		if err != nil {
 			glog.V(2).Infof("container creation failed %+v: %v", *args.containerOptions, err)
 			return nil, err
@@ -43,6 +43,8 @@ type ClientInterface interface {
 
 	CreateContainer(opts dockerclient.CreateContainerOptions) (*dockerclient.Container, error)
 
+	CreateContainerWithHostConfig(opts dockerclient.CreateContainerOptions, hostConfig *HostConfig) (*dockerclient.Container, error)
+
 	ExportContainer(opts dockerclient.ExportContainerOptions) error
 
 	ImportImage(opts dockerclient.ImportImageOptions) error
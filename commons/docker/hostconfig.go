@@ -0,0 +1,83 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	dockerclient "github.com/fsouza/go-dockerclient"
+)
+
+// HostConfig is the host configuration of a container, with the fields that
+// the vendored go-dockerclient does not support yet.  Containers that use them
+// are created with CreateContainerWithHostConfig.
+type HostConfig struct {
+	dockerclient.HostConfig
+	Tmpfs     map[string]string `json:"Tmpfs,omitempty"`
+	PidsLimit int64             `json:"PidsLimit,omitempty"`
+}
+
+// unixHTTPClient talks to the docker daemon over its socket
+var unixHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		Dial: func(_, _ string) (net.Conn, error) {
+			return net.Dial("unix", strings.TrimPrefix(dockerep, "unix://"))
+		},
+	},
+}
+
+// CreateContainerWithHostConfig creates a container like CreateContainer,
+// using the given host config instead of the one in the options.
+func (c *Client) CreateContainerWithHostConfig(opts dockerclient.CreateContainerOptions, hostConfig *HostConfig) (*dockerclient.Container, error) {
+	data, err := json.Marshal(struct {
+		*dockerclient.Config
+		HostConfig *HostConfig `json:"HostConfig,omitempty"`
+	}{opts.Config, hostConfig})
+	if err != nil {
+		return nil, err
+	}
+	path := "http://docker/containers/create?name=" + url.QueryEscape(opts.Name)
+	req, err := http.NewRequest("POST", path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := unixHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, dockerclient.ErrNoSuchImage
+	case resp.StatusCode == http.StatusConflict:
+		return nil, dockerclient.ErrContainerAlreadyExists
+	case resp.StatusCode < 200 || resp.StatusCode >= 400:
+		message, _ := ioutil.ReadAll(resp.Body)
+		return nil, &dockerclient.Error{Status: resp.StatusCode, Message: string(message)}
+	}
+	var container dockerclient.Container
+	if err := json.NewDecoder(resp.Body).Decode(&container); err != nil {
+		return nil, err
+	}
+	container.Name = opts.Name
+	return &container, nil
+}
@@ -37,6 +37,11 @@ func (mdc *MockDockerClient) CreateContainer(opts dockerclient.CreateContainerOp
 	return args.Get(0).(*dockerclient.Container), args.Error(1)
 }
 
+func (mdc *MockDockerClient) CreateContainerWithHostConfig(opts dockerclient.CreateContainerOptions, hostConfig *docker.HostConfig) (*dockerclient.Container, error) {
+	args := mdc.Mock.Called(opts, hostConfig)
+	return args.Get(0).(*dockerclient.Container), args.Error(1)
+}
+
 func (mdc *MockDockerClient) ExportContainer(opts dockerclient.ExportContainerOptions) error {
 	return mdc.Mock.Called(opts).Error(0)
 }
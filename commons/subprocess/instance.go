@@ -34,14 +34,22 @@ type Instance struct {
 	closeLock      sync.Mutex     // mutex to synchronize Close() calls
 	sigtermTimeout time.Duration  // sigterm timeout
 	signalChan     chan os.Signal // used to send signals to the command process
+	procAttr       *syscall.SysProcAttr
 }
 
 // New creates a subprocess.Instance
 func New(sigtermTimeout time.Duration, env []string, command string, args ...string) (*Instance, chan error, error) {
+	return NewWithProcAttr(sigtermTimeout, nil, env, command, args...)
+}
+
+// NewWithProcAttr creates a subprocess.Instance whose command is started with
+// the given process attributes, e.g. to run it as a different user.
+func NewWithProcAttr(sigtermTimeout time.Duration, procAttr *syscall.SysProcAttr, env []string, command string, args ...string) (*Instance, chan error, error) {
 	s := &Instance{
 		command:        command,
 		args:           args,
 		env:            env,
+		procAttr:       procAttr,
 		commandExit:    make(chan error, 1),
 		sigtermTimeout: sigtermTimeout,
 		signalChan:     make(chan os.Signal, 1),
@@ -87,6 +95,7 @@ func (s *Instance) loop() {
 		glog.Infof("about to execute: %s , %v[%d]", s.command, s.args, len(s.args))
 		cmd := exec.Command(s.command, s.args...)
 		cmd.Env = s.env
+		cmd.SysProcAttr = s.procAttr
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 		cmd.Stdin = os.Stdin
@@ -87,6 +87,8 @@ type Options struct {
 	LogstashCycleTime          int    // Logstash purging cycle time in hours
 	LogstashURL                string
 	DebugPort                  int      // Port to listen for profile clients
+	MetricsPort                int      // Port to serve Prometheus metrics on
+	TraceCollector             string   // OpenTelemetry collector URL to export request traces to
 	AdminGroup                 string   // user group that can log in to control center
 	MaxRPCClients              int      // the max number of rpc clients to an endpoint
 	MUXTLSCiphers              []string // List of tls ciphers supported for mux
@@ -110,6 +112,9 @@ type Options struct {
 	DockerLogConfigList        []string          // List of comma-separated key=value options for docker logging
 	AllowLoopBack              string            // Allow loop back devices for DM storage, string val of bool
 	UIPollFrequency            int               // frequency in seconds that UI should poll for service changes
+	UISessionStore             string            // Where UI sessions are kept: coordinator or memory
+	UISessionIdleTimeout       int               // Minutes a UI session may go unused before it expires
+	UISessionMaxAge            int               // Minutes a UI session may last, however often it is used; 0 for no limit
 	StorageStatsUpdateInterval int               // frequency in seconds that low-level devicemapper storage stats should be refreshed
 	SnapshotSpacePercent       int               // Percent of tenant volume size that is assumed to be needed to create a snapshot
 	ZKSessionTimeout           int               // The session timeout of a zookeeper client connection.
@@ -117,6 +122,8 @@ type Options struct {
 	ZKPerHostConnectDelay      int               // The delay, in seconds, between connection attempts to other zookeeper servers.
 	ZKReconnectStartDelay      int               // The initial delay, in seconds, before attempting to reconnect after none of the zookeepers are reachable
 	ZKReconnectMaxDelay        int               // The maximum delay, in seconds, before attempting to reconnect after none of the zookeepers are reachable
+	CoordinatorDriver          string            // The coordination service driver (zookeeper, etcd or memory)
+	EtcdEndpoints              []string          // The etcd endpoints to connect to when using the etcd driver
 	TokenExpiration            int               // The time in seconds before an authentication token expires
 	ConntrackFlush             string            // Whether to flush the conntrack table when a service with an assigned IP is started
 	LogConfigFilename          string            // Path to the logri configuration
@@ -134,6 +141,14 @@ type Options struct {
 	Auth0Group                 string            // Group membership required in Auth0 token for login
 	Auth0ClientID              string            // ClientID of Auth0 Application
 	Auth0Scope                 string            // Auth0 Scope for request.
+	OIDCIssuer                 string            // URL of a generic OpenID Connect provider
+	OIDCClientID               string            // Client ID registered with the OIDC provider
+	OIDCClientSecret           string            // Client secret registered with the OIDC provider
+	OIDCAudience               string            // Audience required of OIDC bearer tokens; defaults to the client ID
+	OIDCScope                  string            // Scope to request from the OIDC provider
+	OIDCRedirectURL            string            // URL the OIDC provider returns to after login; defaults to /oidc/callback on the requested host
+	OIDCGroupsClaim            string            // Name of the OIDC token claim that lists the user's groups
+	OIDCAdminGroups            string            // Comma-separated OIDC groups whose members are granted admin access
 }
 
 // GetOptions returns a COPY of the global options struct
@@ -57,6 +57,8 @@ var (
 	ErrInvalidServiceID = errors.New("container: invalid service id")
 	// ErrInvalidService is returned if a Service is empty or malformed
 	ErrInvalidService = errors.New("container: invalid serviced")
+	// ErrMemoryCoordinator is returned when the host uses the in-process coordinator
+	ErrMemoryCoordinator = errors.New("container: memory coordinator driver cannot be shared")
 	// ErrInvalidHostID is returned if the host is empty or malformed
 	ErrInvalidHostID = errors.New("container: invalid host id")
 	// ErrNoServiceEndpoints is returned if we can't fetch the service endpoints
@@ -127,6 +129,7 @@ type Controller struct {
 	endpoints          *ContainerEndpoints
 	healthChecks       map[string]health.HealthCheck
 	ccApiProxy         *servicedApiProxy
+	serviceProcAttr    *syscall.SysProcAttr // attributes of the service command, e.g. its user
 }
 
 // Close shuts down the controller
@@ -314,12 +317,25 @@ func NewController(options ControllerOptions) (*Controller, error) {
 	}
 	c.healthChecks = service.HealthChecks
 	c.tenantID = tenantID
+
+	// run the service command, its health checks and its scripts as the user
+	// requested by its security profile
+	if service.Security.User != "" {
+		cred, err := lookupCredential(service.Security.User)
+		if err != nil {
+			glog.Errorf("Could not look up user %s for service %s: %s", service.Security.User, options.Service.ID, err)
+			return c, err
+		}
+		c.serviceProcAttr = &syscall.SysProcAttr{Credential: cred}
+	}
 	options.ServiceNamePath = svcPath
 
 	if service.PIDFile != "" {
 		if strings.HasPrefix(service.PIDFile, "exec ") {
 			cmd := service.PIDFile[5:len(service.PIDFile)]
-			out, err := exec.Command("sh", "-c", cmd).Output()
+			pidCmd := exec.Command("sh", "-c", cmd)
+			pidCmd.SysProcAttr = c.serviceProcAttr
+			out, err := pidCmd.Output()
 			if err != nil {
 				glog.Errorf("Unable to run command '%s'", cmd)
 			} else {
@@ -410,7 +426,14 @@ func NewController(options ControllerOptions) (*Controller, error) {
 
 	// endpoints are created at the root level (not pool aware)
 	rootBasePath := ""
-	zClient, err := coordclient.New("zookeeper", c.zkInfo.ZkDSN, rootBasePath, nil)
+	driver := c.zkInfo.Driver
+	if driver == "" {
+		driver = "zookeeper"
+	} else if driver == "memory" {
+		glog.Errorf("Coordinator driver %s is not shared with the host", driver)
+		return c, ErrMemoryCoordinator
+	}
+	zClient, err := coordclient.New(driver, c.zkInfo.ZkDSN, rootBasePath, nil)
 	if err != nil {
 		glog.Errorf("failed create a new coordclient: %v", err)
 		return c, err
@@ -607,13 +630,17 @@ func (c *Controller) Run() (err error) {
 	env = append(env, fmt.Sprintf("CONTROLPLANE_SERVICED_ID=%s", c.options.Service.ID))
 
 	if err := writeEnvFile(env); err != nil {
-		return err
+		// the environment file is only a convenience for shells, so don't
+		// fail when the service runs with a read-only root filesystem
+		if perr, ok := err.(*os.PathError); !ok || perr.Err != syscall.EROFS {
+			return err
+		}
 	}
 
 	args := []string{"-c", "exec " + strings.Join(c.options.Service.Command, " ")}
 
 	startService := func() (*subprocess.Instance, chan error) {
-		service, serviceExited, _ := subprocess.New(time.Second*10, env, "/bin/sh", args...)
+		service, serviceExited, _ := subprocess.NewWithProcAttr(time.Second*10, c.serviceProcAttr, env, "/bin/sh", args...)
 		return service, serviceExited
 	}
 
@@ -778,7 +805,9 @@ func (c *Controller) checkPrereqs(prereqsPassed chan bool, rpcDead chan struct{}
 			failedAny := false
 			for _, script := range c.prereqs {
 				glog.Infof("Running prereq command: %s", script.Script)
-				out, err := exec.Command("sh", "-c", script.Script).CombinedOutput()
+				cmd := exec.Command("sh", "-c", script.Script)
+				cmd.SysProcAttr = c.serviceProcAttr
+				out, err := cmd.CombinedOutput()
 				if err != nil {
 					msg := fmt.Sprintf("Service %s not starting. Output: %s; error: %s", script.Name, out, err)
 					glog.Warning(msg)
@@ -814,6 +843,7 @@ func (c *Controller) kickOffHealthChecks(healthExit chan struct{}) {
 	for name, hc := range c.healthChecks {
 		glog.Infof("Kicking off health check %s.", name)
 		glog.Infof("Setting up health check: %s", hc.Script)
+		hc.SysProcAttr = c.serviceProcAttr
 		key := health.HealthStatusKey{
 			ServiceID:       c.options.Service.ID,
 			InstanceID:      instanceID,
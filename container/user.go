@@ -0,0 +1,62 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// lookupCredential returns the credential for a user spec of the form
+// user[:group], where user and group are names or numeric ids.  A numeric uid
+// that is not in the passwd database runs with gid 0, as it does in docker.
+func lookupCredential(spec string) (*syscall.Credential, error) {
+	parts := strings.SplitN(spec, ":", 2)
+
+	var uid, gid uint64
+	var err error
+	if uid, err = strconv.ParseUint(parts[0], 10, 32); err == nil {
+		if u, err := user.LookupId(parts[0]); err == nil {
+			if gid, err = strconv.ParseUint(u.Gid, 10, 32); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		u, err := user.Lookup(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		if uid, err = strconv.ParseUint(u.Uid, 10, 32); err != nil {
+			return nil, err
+		}
+		if gid, err = strconv.ParseUint(u.Gid, 10, 32); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(parts) == 2 {
+		if gid, err = strconv.ParseUint(parts[1], 10, 32); err != nil {
+			g, err := user.LookupGroup(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			if gid, err = strconv.ParseUint(g.Gid, 10, 32); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package container
+
+import "testing"
+
+func TestLookupCredential(t *testing.T) {
+	for spec, expected := range map[string][2]uint32{
+		"root":      {0, 0},
+		"0":         {0, 0},
+		"54321":     {54321, 0},
+		"54321:123": {54321, 123},
+		"root:0":    {0, 0},
+	} {
+		cred, err := lookupCredential(spec)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %s", spec, err)
+			continue
+		}
+		if cred.Uid != expected[0] || cred.Gid != expected[1] {
+			t.Errorf("Expected %d:%d for %s, got %d:%d", expected[0], expected[1], spec, cred.Uid, cred.Gid)
+		}
+	}
+
+	for _, spec := range []string{"nosuchuser-serviced", "root:nosuchgroup-serviced"} {
+		if _, err := lookupCredential(spec); err == nil {
+			t.Errorf("Expected error for %s", spec)
+		}
+	}
+}
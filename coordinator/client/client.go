@@ -83,6 +83,13 @@ var (
 	ErrNothing                 = errors.New("coord-client: no server responsees to process")
 	ErrSessionMoved            = errors.New("coord-client: session moved to another server, so operation is ignored")
 	ErrNoServer                = errors.New("coord-client: could not connect to a server")
+	// ErrDeadlock is returned when a lock or lead is acquired twice on the same object
+	ErrDeadlock = errors.New("coord-client: trying to acquire a lock twice")
+	// ErrNotLocked is returned when a caller attempts to release a lock that
+	// has not been acquired
+	ErrNotLocked = errors.New("coord-client: not locked")
+	// ErrNoLeaderFound is returned when a leader has not been elected
+	ErrNoLeaderFound = errors.New("coord-client: no leader found")
 )
 
 var (
@@ -0,0 +1,762 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// protectedPrefix is prepended to the names of ephemeral nodes, matching the
+// naming used by the zookeeper driver.
+const protectedPrefix = "_c_"
+
+// Stat is the version object of nodes stored by the etcd driver.  Version
+// counts the number of times the node has been updated since it was created.
+type Stat struct {
+	Version int64
+}
+
+// Connection is an etcd based implementation of client.Connection.  Nodes are
+// stored as keys of the form <prefix>/n/<depth><path> so that the direct
+// children of a node can be listed with a single range request.  Ephemeral
+// nodes are attached to a lease that is kept alive for the lifetime of the
+// connection.
+type Connection struct {
+	sync.RWMutex
+	api      *gateway
+	ttl      time.Duration
+	lease    int64
+	prefix   string
+	basePath string
+	closing  chan struct{}
+	onClose  func(int)
+	id       int
+}
+
+// Assert that Connection implements client.Connection.
+var _ client.Connection = &Connection{}
+
+// IsClosed returns connection closed error if true, otherwise returns nil.
+func (c *Connection) isClosed() error {
+	if c.api == nil {
+		return client.ErrConnectionClosed
+	}
+	return nil
+}
+
+// Close closes the client connection to etcd and revokes its lease, which
+// removes all of the connection's ephemeral nodes. Calling close twice will
+// result in a no-op.
+func (c *Connection) Close() {
+	c.Lock()
+	api, lease := c.api, c.lease
+	if api == nil {
+		c.Unlock()
+		return
+	}
+	close(c.closing)
+	c.api = nil
+	if c.onClose != nil {
+		c.onClose(c.id)
+		c.onClose = nil
+	}
+	c.Unlock()
+
+	// revoke the lease without holding the lock, so that a slow endpoint
+	// does not block the other users of the connection
+	if err := api.call("/lease/revoke", leaseRevokeRequest{ID: int64s(lease)}, &struct{}{}); err != nil {
+		plog.WithError(err).WithField("lease", lease).Warn("Could not revoke lease")
+	}
+}
+
+// SetID sets the connection ID
+func (c *Connection) SetID(i int) {
+	c.Lock()
+	defer c.Unlock()
+	c.id = i
+}
+
+// ID gets the connection ID
+func (c *Connection) ID() int {
+	c.RLock()
+	defer c.RUnlock()
+	return c.id
+}
+
+// SetOnClose performs cleanup when a connection is closed
+func (c *Connection) SetOnClose(onClose func(int)) {
+	c.Lock()
+	defer c.Unlock()
+	if err := c.isClosed(); err == nil {
+		c.onClose = onClose
+	}
+}
+
+// grantLease creates a lease that can own the connection's ephemeral nodes
+// and returns its id.
+func (c *Connection) grantLease(api *gateway) (int64, error) {
+	ttl := int64(c.ttl / time.Second)
+	if ttl < 1 {
+		ttl = 1
+	}
+	resp := &leaseGrantResponse{}
+	if err := api.call("/lease/grant", leaseGrantRequest{TTL: int64s(ttl)}, resp); err != nil {
+		return 0, err
+	} else if resp.Error != "" {
+		return 0, fmt.Errorf("etcd: %s", resp.Error)
+	}
+	return int64(resp.ID), nil
+}
+
+// keepAlive refreshes the connection's lease until the connection is closed.
+// If the lease expires, the ephemeral nodes are lost and a new lease is
+// granted, just as a zookeeper session would be re-established.  Requests are
+// made without holding the connection lock.
+func (c *Connection) keepAlive() {
+	interval := c.ttl / 3
+	if interval < 100*time.Millisecond {
+		interval = 100 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.closing:
+			return
+		}
+		c.RLock()
+		api, lease := c.api, c.lease
+		c.RUnlock()
+		if api == nil {
+			return
+		}
+		logger := plog.WithField("lease", lease)
+		resp := &leaseKeepAliveResponse{}
+		if err := api.call("/lease/keepalive", leaseKeepAliveRequest{ID: int64s(lease)}, resp); err != nil {
+			logger.WithError(err).Warn("Could not refresh lease")
+			continue
+		} else if resp.Result.TTL > 0 {
+			continue
+		}
+		logger.Warn("Lease expired, ephemeral nodes have been removed")
+		newLease, err := c.grantLease(api)
+		if err != nil {
+			logger.WithError(err).Warn("Could not grant a new lease")
+			continue
+		}
+		c.Lock()
+		if c.api != nil {
+			c.lease = newLease
+		}
+		c.Unlock()
+	}
+}
+
+// key returns the etcd key of the node at the given absolute path
+func (c *Connection) key(p string) []byte {
+	p = path.Clean(p)
+	depth := strings.Count(p, "/")
+	if p == "/" {
+		depth = 0
+	}
+	return []byte(fmt.Sprintf("%s/n/%03d%s", c.prefix, depth, p))
+}
+
+// childPrefix returns the key prefix of the children of the node at the given
+// absolute path
+func (c *Connection) childPrefix(p string) []byte {
+	p = path.Clean(p)
+	depth := strings.Count(p, "/") + 1
+	if p == "/" {
+		depth, p = 1, ""
+	}
+	return []byte(fmt.Sprintf("%s/n/%03d%s/", c.prefix, depth, p))
+}
+
+// seqKey returns the key of the sequence counter for the children of the node
+// at the given absolute path
+func (c *Connection) seqKey(p string) []byte {
+	return []byte(c.prefix + "/seq" + path.Clean(p))
+}
+
+// parentCmp returns the comparisons that assert that the parent of a node
+// exists and is not ephemeral, or nil if the node is at the root.
+func (c *Connection) parentCmp(p string) []compare {
+	dir := path.Dir(path.Clean(p))
+	if dir == "/" {
+		return nil
+	}
+	key := c.key(dir)
+	return []compare{
+		{Result: compareGreater, Target: targetCreate, Key: key, CreateRevision: newInt64s(0)},
+		{Result: compareEqual, Target: targetLease, Key: key, Lease: newInt64s(0)},
+	}
+}
+
+// NewTransaction creates a new transaction object
+func (c *Connection) NewTransaction() client.Transaction {
+	return &Transaction{conn: c}
+}
+
+// NewLock creates a new lock object
+func (c *Connection) NewLock(p string) (client.Lock, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return nil, err
+	}
+	return client.NewSequentialLock(c, p), nil
+}
+
+// NewLeader returns a managed leader object at the given path bound to the
+// current connection.
+func (c *Connection) NewLeader(p string) (client.Leader, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return nil, err
+	}
+	return client.NewSequentialLeader(c, p), nil
+}
+
+// Create adds a node at the specified path
+func (c *Connection) Create(path string, node client.Node) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	if err := c.ensurePath(path); err != nil {
+		return err
+	}
+	return c.create(path, node)
+}
+
+// CreateIfExists adds a node at the specified path if the dirpath already
+// exists.
+func (c *Connection) CreateIfExists(path string, node client.Node) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	return c.create(path, node)
+}
+
+func (c *Connection) create(p string, node client.Node) error {
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return client.ErrSerialization
+	}
+	if err := c.put(path.Join(c.basePath, p), bytes); err != nil {
+		return err
+	}
+	node.SetVersion(&Stat{})
+	return nil
+}
+
+// put creates a persistent node at the given absolute path
+func (c *Connection) put(pth string, data []byte) error {
+	key := c.key(pth)
+	resp, err := c.api.txn(txnRequest{
+		Compare: append(c.parentCmp(pth), compare{Result: compareEqual, Target: targetCreate, Key: key, CreateRevision: newInt64s(0)}),
+		Success: []requestOp{{RequestPut: &putRequest{Key: key, Value: data}}},
+	})
+	if err != nil {
+		return err
+	} else if !resp.Succeeded {
+		return c.createError(pth)
+	}
+	return nil
+}
+
+// createError returns the reason that a node could not be created
+func (c *Connection) createError(pth string) error {
+	if ok, err := c.exists(pth); err != nil {
+		return err
+	} else if ok {
+		return client.ErrNodeExists
+	}
+	return c.parentError(path.Dir(pth))
+}
+
+// parentError returns the reason that a node cannot be created under the
+// parent at the given absolute path, or nil if the parent is valid.
+func (c *Connection) parentError(dir string) error {
+	if path.Clean(dir) == "/" {
+		return nil
+	}
+	resp, err := c.api.get(c.key(dir))
+	if err != nil {
+		return err
+	} else if len(resp.Kvs) == 0 {
+		return client.ErrNoNode
+	} else if resp.Kvs[0].Lease != 0 {
+		return client.ErrNoChildrenForEphemerals
+	}
+	return nil
+}
+
+// CreateDir adds a dir at the specified path
+func (c *Connection) CreateDir(path string) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	if err := c.ensurePath(path); err != nil {
+		return err
+	}
+	return c.createDir(path)
+}
+
+func (c *Connection) createDir(p string) error {
+	return c.put(path.Join(c.basePath, p), []byte{})
+}
+
+func (c *Connection) ensurePath(p string) error {
+	dp := path.Dir(p)
+	if p == "/" || p == "" {
+		return nil
+	}
+	if exists, err := c.exists(path.Join(c.basePath, dp)); err != nil {
+		return err
+	} else if exists {
+		return nil
+	}
+	if err := c.ensurePath(dp); err != nil {
+		return err
+	} else if err := c.createDir(dp); err != client.ErrNodeExists {
+		return err
+	}
+	return nil
+}
+
+// CreateEphemeral creates a node whose existance depends on the persistence of
+// the connection.
+func (c *Connection) CreateEphemeral(path string, node client.Node) (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return "", err
+	}
+	if err := c.ensurePath(path); err != nil {
+		return "", err
+	}
+	return c.createEphemeral(path, node)
+}
+
+// CreateEphemeralIfExists creates an ephemeral node at the given path if it
+// exists.
+func (c *Connection) CreateEphemeralIfExists(path string, node client.Node) (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return "", err
+	}
+	return c.createEphemeral(path, node)
+}
+
+// createEphemeral creates a protected, sequential node that is owned by the
+// connection's lease and returns its absolute path.
+func (c *Connection) createEphemeral(p string, node client.Node) (string, error) {
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return "", client.ErrSerialization
+	}
+	guid := make([]byte, 16)
+	if _, err := rand.Read(guid); err != nil {
+		return "", err
+	}
+	dir, name := path.Split(path.Join(c.basePath, p))
+	name = fmt.Sprintf("%s%x-%s", protectedPrefix, guid, name)
+	seqKey := c.seqKey(dir)
+	for {
+		// reserve the next sequence number of the parent and create the node
+		// in the same transaction
+		resp, err := c.api.get(seqKey)
+		if err != nil {
+			return "", err
+		}
+		var seq, version int64
+		if len(resp.Kvs) > 0 {
+			if seq, err = strconv.ParseInt(string(resp.Kvs[0].Value), 10, 64); err != nil {
+				return "", err
+			}
+			version = int64(resp.Kvs[0].Version)
+		}
+		epth := path.Join(dir, fmt.Sprintf("%s%010d", name, seq))
+		txn, err := c.api.txn(txnRequest{
+			Compare: append(c.parentCmp(epth), compare{Result: compareEqual, Target: targetVersion, Key: seqKey, Version: newInt64s(version)}),
+			Success: []requestOp{
+				{RequestPut: &putRequest{Key: seqKey, Value: []byte(strconv.FormatInt(seq+1, 10))}},
+				{RequestPut: &putRequest{Key: c.key(epth), Value: bytes, Lease: int64s(c.lease)}},
+			},
+		})
+		if err != nil {
+			return "", err
+		} else if txn.Succeeded {
+			return epth, nil
+		}
+		if err := c.parentError(path.Dir(epth)); err != nil {
+			return "", err
+		}
+	}
+}
+
+// Set assigns a value to an existing node at a given path
+func (c *Connection) Set(path string, node client.Node) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	return c.set(path, node)
+}
+
+func (c *Connection) set(p string, node client.Node) error {
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return client.ErrSerialization
+	}
+	version, err := getVersion(node)
+	if err != nil {
+		return err
+	}
+	pth := path.Join(c.basePath, p)
+	key := c.key(pth)
+	resp, err := c.api.txn(txnRequest{
+		Compare: []compare{{Result: compareEqual, Target: targetVersion, Key: key, Version: newInt64s(version + 1)}},
+		Success: []requestOp{{RequestPut: &putRequest{Key: key, Value: bytes, IgnoreLease: true}}},
+	})
+	if err != nil {
+		return err
+	} else if !resp.Succeeded {
+		return c.versionError(pth)
+	}
+	return nil
+}
+
+// versionError returns the reason that a node could not be updated
+func (c *Connection) versionError(pth string) error {
+	if ok, err := c.exists(pth); err != nil {
+		return err
+	} else if !ok {
+		return client.ErrNoNode
+	}
+	return client.ErrBadVersion
+}
+
+// Delete recursively removes a path and its children
+func (c *Connection) Delete(path string) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	return c.delete(path)
+}
+
+func (c *Connection) delete(p string) error {
+	children, err := c.children(p)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := c.delete(path.Join(p, child)); err != nil {
+			return err
+		}
+	}
+	key := c.key(path.Join(c.basePath, p))
+	resp, err := c.api.txn(txnRequest{
+		Compare: []compare{{Result: compareGreater, Target: targetCreate, Key: key, CreateRevision: newInt64s(0)}},
+		Success: []requestOp{{RequestDeleteRange: &deleteRangeRequest{Key: key}}},
+	})
+	if err != nil {
+		return err
+	} else if !resp.Succeeded {
+		return client.ErrNoNode
+	}
+	return nil
+}
+
+// Exists returns true if the path exists
+func (c *Connection) Exists(p string) (bool, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return false, err
+	}
+	return c.exists(path.Join(c.basePath, p))
+}
+
+// exists returns true if the node at the absolute path exists
+func (c *Connection) exists(pth string) (bool, error) {
+	if path.Clean(pth) == "/" {
+		return true, nil
+	}
+	resp, err := c.api.get(c.key(pth))
+	if err != nil {
+		return false, err
+	}
+	return len(resp.Kvs) > 0, nil
+}
+
+// ExistsW sets a watch on a node and alerts whenever it is added or removed.
+func (c *Connection) ExistsW(p string, cancel <-chan struct{}) (bool, <-chan client.Event, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return false, nil, err
+	}
+	key := c.key(path.Join(c.basePath, p))
+	resp, err := c.api.get(key)
+	if err != nil {
+		return false, nil, err
+	}
+	ev, err := c.watch(key, nil, int64(resp.Header.Revision), cancel, nodeEvent)
+	if err != nil {
+		return false, nil, err
+	}
+	return len(resp.Kvs) > 0, ev, nil
+}
+
+// Get returns the node at the given path.
+func (c *Connection) Get(path string, node client.Node) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	_, err := c.get(path, node)
+	return err
+}
+
+func (c *Connection) get(p string, node client.Node) (*rangeResponse, error) {
+	resp, err := c.api.get(c.key(path.Join(c.basePath, p)))
+	if err != nil {
+		return nil, err
+	} else if len(resp.Kvs) == 0 {
+		return nil, client.ErrNoNode
+	}
+	kv := resp.Kvs[0]
+	if len(kv.Value) > 0 {
+		if err := json.Unmarshal(kv.Value, node); err != nil {
+			return nil, client.ErrSerialization
+		}
+	} else {
+		err = client.ErrEmptyNode
+	}
+	node.SetVersion(&Stat{Version: int64(kv.Version) - 1})
+	return resp, err
+}
+
+// GetW returns the node at the given path as well as a channel to watch for
+// events on that node.
+func (c *Connection) GetW(p string, node client.Node, cancel <-chan struct{}) (<-chan client.Event, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return nil, err
+	}
+	resp, err := c.get(p, node)
+	if err != nil {
+		return nil, err
+	}
+	return c.watch(c.key(path.Join(c.basePath, p)), nil, int64(resp.Header.Revision), cancel, nodeEvent)
+}
+
+// Children returns the children of the node at the given path.
+func (c *Connection) Children(path string) ([]string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return []string{}, err
+	}
+	return c.children(path)
+}
+
+func (c *Connection) children(p string) ([]string, error) {
+	children, _, err := c.listChildren(path.Join(c.basePath, p))
+	return children, err
+}
+
+// listChildren returns the names of the children of the node at the given
+// absolute path and the revision they were read at.
+func (c *Connection) listChildren(pth string) ([]string, int64, error) {
+	if ok, err := c.exists(pth); err != nil {
+		return []string{}, 0, err
+	} else if !ok {
+		return []string{}, 0, client.ErrNoNode
+	}
+	prefix := c.childPrefix(pth)
+	resp, err := c.api.list(prefix)
+	if err != nil {
+		return []string{}, 0, err
+	}
+	children := make([]string, len(resp.Kvs))
+	for i, kv := range resp.Kvs {
+		children[i] = string(kv.Key[len(prefix):])
+	}
+	return children, int64(resp.Header.Revision), nil
+}
+
+// ChildrenW returns the children of the node at the given path as well as a
+// channel to watch for events on that node.
+func (c *Connection) ChildrenW(p string, cancel <-chan struct{}) ([]string, <-chan client.Event, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return []string{}, nil, err
+	}
+	pth := path.Join(c.basePath, p)
+	children, rev, err := c.listChildren(pth)
+	if err != nil {
+		return []string{}, nil, err
+	}
+
+	// watch both the node and its children, whichever fires first wins
+	done := make(chan struct{})
+	nodeEv, err := c.watch(c.key(pth), nil, rev, done, func(ev watchEvent) (client.EventType, bool) {
+		return client.EventNodeDeleted, ev.Type == eventDelete
+	})
+	if err != nil {
+		close(done)
+		return []string{}, nil, err
+	}
+	prefix := c.childPrefix(pth)
+	childEv, err := c.watch(prefix, prefixEnd(prefix), rev, done, childEvent)
+	if err != nil {
+		close(done)
+		return []string{}, nil, err
+	}
+	evCh := make(chan client.Event, 1)
+	go func() {
+		defer close(done)
+		var ev client.Event
+		select {
+		case ev = <-nodeEv:
+		case ev = <-childEv:
+		case <-cancel:
+			return
+		}
+		select {
+		case evCh <- ev:
+		case <-cancel:
+		}
+	}()
+	return children, evCh, nil
+}
+
+// nodeEvent translates an event on a node
+func nodeEvent(ev watchEvent) (client.EventType, bool) {
+	if ev.Type == eventDelete {
+		return client.EventNodeDeleted, true
+	} else if ev.Kv.Version == 1 {
+		return client.EventNodeCreated, true
+	}
+	return client.EventNodeDataChanged, true
+}
+
+// childEvent translates an event on the children of a node.  Updates to the
+// data of a child are ignored.
+func childEvent(ev watchEvent) (client.EventType, bool) {
+	return client.EventNodeChildrenChanged, ev.Type == eventDelete || ev.Kv.Version == 1
+}
+
+// watch streams events on a key range starting after the given revision and
+// sends the first event accepted by the filter.  The watch is stopped when
+// the cancel channel is closed or the connection is closed, in which case
+// EventNotWatching is sent.
+func (c *Connection) watch(key, rangeEnd []byte, rev int64, cancel <-chan struct{}, filter func(watchEvent) (client.EventType, bool)) (<-chan client.Event, error) {
+	ctx, stop := context.WithCancel(context.Background())
+	resp, err := c.api.open(ctx, "/watch", watchRequest{CreateRequest: watchCreateRequest{
+		Key:           key,
+		RangeEnd:      rangeEnd,
+		StartRevision: int64s(rev + 1),
+	}})
+	if err != nil {
+		stop()
+		return nil, err
+	}
+
+	// read the stream
+	events := make(chan client.Event, 1)
+	go func() {
+		defer resp.Body.Close()
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var wr watchResponse
+			if err := decoder.Decode(&wr); err != nil {
+				events <- client.Event{Type: client.EventNotWatching, Err: err}
+				return
+			} else if wr.Error != nil {
+				events <- client.Event{Type: client.EventNotWatching, Err: wr.Error}
+				return
+			} else if wr.Result.Canceled {
+				events <- client.Event{Type: client.EventNotWatching, Err: client.ErrClosing}
+				return
+			}
+			for _, ev := range wr.Result.Events {
+				if evType, ok := filter(ev); ok {
+					events <- client.Event{Type: evType}
+					return
+				}
+			}
+		}
+	}()
+
+	evCh := make(chan client.Event, 1)
+	closing := c.closing
+	go func() {
+		defer stop()
+		var ev client.Event
+		select {
+		case ev = <-events:
+		case <-closing:
+			ev = client.Event{Type: client.EventNotWatching, Err: client.ErrClosing}
+		case <-cancel:
+			return
+		}
+		select {
+		case evCh <- ev:
+		case <-cancel:
+		}
+	}()
+	return evCh, nil
+}
+
+// getVersion returns the version of a node to be updated
+func getVersion(node client.Node) (int64, error) {
+	version := node.Version()
+	if version == nil {
+		return 0, nil
+	}
+	stat, ok := version.(*Stat)
+	if !ok {
+		return 0, client.ErrInvalidVersionObj
+	}
+	return stat.Version, nil
+}
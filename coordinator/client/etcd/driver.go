@@ -0,0 +1,127 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/logging"
+)
+
+var (
+	plog = logging.PackageLogger() // the standard package logger
+)
+
+const (
+	// DefaultEndpoint is the address of the etcd gateway when none is given
+	DefaultEndpoint = "http://127.0.0.1:2379"
+	// DefaultPrefix is the key prefix under which all nodes are stored
+	DefaultPrefix = "/serviced"
+	// DefaultSessionTimeout is the ttl of the lease that owns ephemeral nodes
+	DefaultSessionTimeout = 15 * time.Second
+	// DefaultRequestTimeout is how long a single request to the gateway may
+	// take before it is abandoned
+	DefaultRequestTimeout = 5 * time.Second
+)
+
+// Driver implements an etcd v3 based client.Driver interface. It talks to the
+// JSON gateway that is served by etcd on its client port.
+type Driver struct{}
+
+// Assert that the etcd driver meets the Driver interface
+var _ client.Driver = &Driver{}
+
+func init() {
+	client.RegisterDriver("etcd", &Driver{})
+}
+
+// DSN is an etcd specific struct used for connections. It can be serialized.
+type DSN struct {
+	Endpoints      []string
+	SessionTimeout time.Duration
+	RequestTimeout time.Duration
+	Prefix         string
+}
+
+// NewDSN returns a new DSN object from endpoints and timeout.
+func NewDSN(endpoints []string, sessionTimeout time.Duration) DSN {
+	dsn := DSN{
+		Endpoints:      []string{},
+		SessionTimeout: sessionTimeout,
+		RequestTimeout: DefaultRequestTimeout,
+		Prefix:         DefaultPrefix,
+	}
+	for _, endpoint := range endpoints {
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+		dsn.Endpoints = append(dsn.Endpoints, endpoint)
+	}
+	if len(dsn.Endpoints) == 0 {
+		dsn.Endpoints = []string{DefaultEndpoint}
+	}
+	if dsn.SessionTimeout <= 0 {
+		dsn.SessionTimeout = DefaultSessionTimeout
+	}
+	return dsn
+}
+
+// String creates a parsable (JSON) string represenation of this DSN.
+func (dsn DSN) String() string {
+	bytes, err := json.Marshal(dsn)
+	if err != nil {
+		panic(err)
+	}
+	return string(bytes)
+}
+
+// ParseDSN decodes a string (JSON) represnation of a DSN object.
+func ParseDSN(dsn string) (val DSN, err error) {
+	err = json.Unmarshal([]byte(dsn), &val)
+	return val, err
+}
+
+// GetConnection returns an etcd connection given the dsn. The caller is
+// responsible for closing the returned connection.
+func (driver *Driver) GetConnection(dsn, basePath string) (client.Connection, error) {
+	dsnVal, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, client.ErrInvalidDSN
+	}
+	prefix := dsnVal.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	timeout := dsnVal.RequestTimeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	dsnVal = NewDSN(dsnVal.Endpoints, dsnVal.SessionTimeout)
+
+	conn := &Connection{
+		api:      newGateway(dsnVal.Endpoints, timeout),
+		ttl:      dsnVal.SessionTimeout,
+		prefix:   strings.TrimRight(prefix, "/"),
+		basePath: basePath,
+		closing:  make(chan struct{}),
+	}
+	if conn.lease, err = conn.grantLease(conn.api); err != nil {
+		return nil, err
+	}
+	go conn.keepAlive()
+	return conn, nil
+}
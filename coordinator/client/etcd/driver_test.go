@@ -0,0 +1,118 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+func TestNewDSN(t *testing.T) {
+	dsn := NewDSN(nil, 0)
+	expected := DSN{Endpoints: []string{DefaultEndpoint}, SessionTimeout: DefaultSessionTimeout, RequestTimeout: DefaultRequestTimeout, Prefix: DefaultPrefix}
+	if !reflect.DeepEqual(dsn, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, dsn)
+	}
+
+	dsn = NewDSN([]string{"10.0.0.1:2379", "https://10.0.0.2:2379"}, time.Minute)
+	if !reflect.DeepEqual(dsn.Endpoints, []string{"http://10.0.0.1:2379", "https://10.0.0.2:2379"}) {
+		t.Fatalf("unexpected endpoints %v", dsn.Endpoints)
+	}
+	parsed, err := ParseDSN(dsn.String())
+	if err != nil {
+		t.Fatalf("could not parse dsn: %s", err)
+	}
+	if !reflect.DeepEqual(parsed, dsn) {
+		t.Fatalf("expected %+v, got %+v", dsn, parsed)
+	}
+
+	drv := Driver{}
+	if _, err := drv.GetConnection("not json", "/"); err != client.ErrInvalidDSN {
+		t.Fatalf("expected %s, got %v", client.ErrInvalidDSN, err)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	c := &Connection{prefix: "/serviced"}
+	for p, expected := range map[string]string{
+		"/":      "/serviced/n/000/",
+		"/a":     "/serviced/n/001/a",
+		"/a/b/":  "/serviced/n/002/a/b",
+		"/a/b/c": "/serviced/n/003/a/b/c",
+	} {
+		if actual := string(c.key(p)); actual != expected {
+			t.Errorf("expected key %s for %s, got %s", expected, p, actual)
+		}
+	}
+	if actual := string(c.childPrefix("/")); actual != "/serviced/n/001/" {
+		t.Errorf("unexpected child prefix %s", actual)
+	}
+	if actual := string(c.childPrefix("/a/b")); actual != "/serviced/n/003/a/b/" {
+		t.Errorf("unexpected child prefix %s", actual)
+	}
+	if actual := string(prefixEnd([]byte("/a/"))); actual != "/a0" {
+		t.Errorf("unexpected prefix end %s", actual)
+	}
+}
+
+func TestInt64sJSON(t *testing.T) {
+	data, err := json.Marshal(compare{Result: compareEqual, Target: targetVersion, Key: []byte("k"), Version: newInt64s(0)})
+	if err != nil {
+		t.Fatalf("could not marshal compare: %s", err)
+	}
+	expected := `{"result":"EQUAL","target":"VERSION","key":"aw==","version":"0"}`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+
+	kv := keyValue{}
+	if err := json.Unmarshal([]byte(`{"version":"3","lease":7}`), &kv); err != nil {
+		t.Fatalf("could not unmarshal key value: %s", err)
+	}
+	if kv.Version != 3 || kv.Lease != 7 {
+		t.Fatalf("unexpected key value %+v", kv)
+	}
+}
+
+func TestGatewayTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	api := newGateway([]string{srv.URL}, 100*time.Millisecond)
+	errc := make(chan error, 1)
+	go func() {
+		_, err := api.get([]byte("/a"))
+		errc <- err
+	}()
+	select {
+	case err := <-errc:
+		if err != context.DeadlineExceeded {
+			t.Fatalf("expected %s, got %v", context.DeadlineExceeded, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("request to a hung endpoint did not time out")
+	}
+}
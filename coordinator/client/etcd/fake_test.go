@@ -0,0 +1,246 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package etcd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+)
+
+// fakeEtcd implements the subset of the etcd v3 JSON gateway that is used by
+// the driver.
+type fakeEtcd struct {
+	sync.Mutex
+	rev       int64
+	kvs       map[string]*keyValue
+	leases    map[int64]bool
+	nextLease int64
+	history   []watchEvent
+	watchers  map[*fakeWatcher]bool
+}
+
+type fakeWatcher struct {
+	key, end []byte
+	ch       chan watchEvent
+}
+
+func (w *fakeWatcher) matches(key []byte) bool {
+	if w.end == nil {
+		return bytes.Equal(key, w.key)
+	}
+	return bytes.Compare(key, w.key) >= 0 && bytes.Compare(key, w.end) < 0
+}
+
+func newFakeEtcd() *httptest.Server {
+	f := &fakeEtcd{
+		kvs:      make(map[string]*keyValue),
+		leases:   make(map[int64]bool),
+		watchers: make(map[*fakeWatcher]bool),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/kv/range", f.handle(func(body []byte) interface{} {
+		req := rangeRequest{}
+		json.Unmarshal(body, &req)
+		return f.doRange(&req)
+	}))
+	mux.HandleFunc("/v3/kv/txn", f.handle(func(body []byte) interface{} {
+		req := txnRequest{}
+		json.Unmarshal(body, &req)
+		return f.doTxn(&req)
+	}))
+	mux.HandleFunc("/v3/lease/grant", f.handle(func(body []byte) interface{} {
+		req := leaseGrantRequest{}
+		json.Unmarshal(body, &req)
+		f.nextLease++
+		f.leases[f.nextLease] = true
+		return leaseGrantResponse{ID: int64s(f.nextLease), TTL: req.TTL}
+	}))
+	mux.HandleFunc("/v3/lease/keepalive", f.handle(func(body []byte) interface{} {
+		req := leaseKeepAliveRequest{}
+		json.Unmarshal(body, &req)
+		resp := leaseKeepAliveResponse{}
+		resp.Result.ID = req.ID
+		if f.leases[int64(req.ID)] {
+			resp.Result.TTL = 1
+		}
+		return resp
+	}))
+	mux.HandleFunc("/v3/lease/revoke", f.handle(func(body []byte) interface{} {
+		req := leaseRevokeRequest{}
+		json.Unmarshal(body, &req)
+		f.revoke(int64(req.ID))
+		return struct{}{}
+	}))
+	mux.HandleFunc("/v3/watch", f.watch)
+	return httptest.NewServer(mux)
+}
+
+func (f *fakeEtcd) handle(op func([]byte) interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		buf := &bytes.Buffer{}
+		buf.ReadFrom(r.Body)
+		f.Lock()
+		resp := op(buf.Bytes())
+		f.Unlock()
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func (f *fakeEtcd) doRange(req *rangeRequest) *rangeResponse {
+	w := &fakeWatcher{key: req.Key, end: req.RangeEnd}
+	resp := &rangeResponse{Header: responseHeader{Revision: int64s(f.rev)}}
+	keys := []string{}
+	for key := range f.kvs {
+		if w.matches([]byte(key)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		kv := *f.kvs[key]
+		if req.KeysOnly {
+			kv.Value = nil
+		}
+		resp.Kvs = append(resp.Kvs, kv)
+	}
+	return resp
+}
+
+func (f *fakeEtcd) doTxn(req *txnRequest) *txnResponse {
+	succeeded := true
+	for _, cmp := range req.Compare {
+		var actual, expected int64
+		kv, ok := f.kvs[string(cmp.Key)]
+		switch cmp.Target {
+		case targetCreate:
+			expected = int64(*cmp.CreateRevision)
+			if ok {
+				actual = int64(kv.CreateRevision)
+			}
+		case targetLease:
+			expected = int64(*cmp.Lease)
+			if ok {
+				actual = int64(kv.Lease)
+			}
+		case targetVersion:
+			expected = int64(*cmp.Version)
+			if ok {
+				actual = int64(kv.Version)
+			}
+		}
+		switch cmp.Result {
+		case compareEqual:
+			succeeded = succeeded && actual == expected
+		case compareGreater:
+			succeeded = succeeded && actual > expected
+		}
+	}
+	ops := req.Success
+	if !succeeded {
+		ops = req.Failure
+	}
+	changed := false
+	for _, op := range ops {
+		if op.RequestPut != nil || op.RequestDeleteRange != nil {
+			if !changed {
+				f.rev++
+				changed = true
+			}
+		}
+		if put := op.RequestPut; put != nil {
+			kv, ok := f.kvs[string(put.Key)]
+			if !ok {
+				kv = &keyValue{Key: put.Key, CreateRevision: int64s(f.rev)}
+				f.kvs[string(put.Key)] = kv
+			}
+			kv.Value, kv.ModRevision = put.Value, int64s(f.rev)
+			kv.Version++
+			if !put.IgnoreLease {
+				kv.Lease = put.Lease
+			}
+			f.notify(watchEvent{Kv: *kv})
+		} else if del := op.RequestDeleteRange; del != nil {
+			f.remove(del.Key)
+		}
+	}
+	return &txnResponse{Header: responseHeader{Revision: int64s(f.rev)}, Succeeded: succeeded}
+}
+
+func (f *fakeEtcd) remove(key []byte) {
+	if _, ok := f.kvs[string(key)]; ok {
+		delete(f.kvs, string(key))
+		f.notify(watchEvent{Type: eventDelete, Kv: keyValue{Key: key, ModRevision: int64s(f.rev)}})
+	}
+}
+
+func (f *fakeEtcd) revoke(lease int64) {
+	delete(f.leases, lease)
+	f.rev++
+	for key, kv := range f.kvs {
+		if int64(kv.Lease) == lease {
+			f.remove([]byte(key))
+		}
+	}
+}
+
+func (f *fakeEtcd) notify(ev watchEvent) {
+	f.history = append(f.history, ev)
+	for w := range f.watchers {
+		if w.matches(ev.Kv.Key) {
+			w.ch <- ev
+		}
+	}
+}
+
+func (f *fakeEtcd) watch(w http.ResponseWriter, r *http.Request) {
+	req := watchRequest{}
+	json.NewDecoder(r.Body).Decode(&req)
+	watcher := &fakeWatcher{key: req.CreateRequest.Key, end: req.CreateRequest.RangeEnd, ch: make(chan watchEvent, 100)}
+
+	f.Lock()
+	for _, ev := range f.history {
+		if ev.Kv.ModRevision >= req.CreateRequest.StartRevision && watcher.matches(ev.Kv.Key) {
+			watcher.ch <- ev
+		}
+	}
+	f.watchers[watcher] = true
+	f.Unlock()
+	defer func() {
+		f.Lock()
+		delete(f.watchers, watcher)
+		f.Unlock()
+	}()
+
+	encoder := json.NewEncoder(w)
+	resp := watchResponse{}
+	resp.Result.Created = true
+	encoder.Encode(resp)
+	w.(http.Flusher).Flush()
+	for {
+		select {
+		case ev := <-watcher.ch:
+			resp := watchResponse{}
+			resp.Result.Events = []watchEvent{ev}
+			encoder.Encode(resp)
+			w.(http.Flusher).Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
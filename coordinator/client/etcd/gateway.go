@@ -0,0 +1,298 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiPrefix is the path of the etcd v3 JSON gateway
+const apiPrefix = "/v3"
+
+// int64s is an int64 that is encoded as a string, as the etcd gateway
+// does for all 64 bit integers.
+type int64s int64
+
+func (i int64s) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(i), 10))
+}
+
+func (i *int64s) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*i = int64s(v)
+	return nil
+}
+
+func newInt64s(i int64) *int64s {
+	v := int64s(i)
+	return &v
+}
+
+type responseHeader struct {
+	Revision int64s `json:"revision"`
+}
+
+type keyValue struct {
+	Key            []byte `json:"key"`
+	CreateRevision int64s `json:"create_revision"`
+	ModRevision    int64s `json:"mod_revision"`
+	Version        int64s `json:"version"`
+	Value          []byte `json:"value"`
+	Lease          int64s `json:"lease"`
+}
+
+type rangeRequest struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end,omitempty"`
+	KeysOnly bool   `json:"keys_only,omitempty"`
+}
+
+type rangeResponse struct {
+	Header responseHeader `json:"header"`
+	Kvs    []keyValue     `json:"kvs"`
+}
+
+type putRequest struct {
+	Key         []byte `json:"key"`
+	Value       []byte `json:"value"`
+	Lease       int64s `json:"lease,omitempty"`
+	IgnoreLease bool   `json:"ignore_lease,omitempty"`
+}
+
+type deleteRangeRequest struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end,omitempty"`
+}
+
+// compare results and targets
+const (
+	compareEqual   = "EQUAL"
+	compareGreater = "GREATER"
+
+	targetVersion = "VERSION"
+	targetCreate  = "CREATE"
+	targetLease   = "LEASE"
+)
+
+type compare struct {
+	Result         string  `json:"result"`
+	Target         string  `json:"target"`
+	Key            []byte  `json:"key"`
+	Version        *int64s `json:"version,omitempty"`
+	CreateRevision *int64s `json:"create_revision,omitempty"`
+	Lease          *int64s `json:"lease,omitempty"`
+}
+
+type requestOp struct {
+	RequestRange       *rangeRequest       `json:"request_range,omitempty"`
+	RequestPut         *putRequest         `json:"request_put,omitempty"`
+	RequestDeleteRange *deleteRangeRequest `json:"request_delete_range,omitempty"`
+}
+
+type txnRequest struct {
+	Compare []compare   `json:"compare"`
+	Success []requestOp `json:"success"`
+	Failure []requestOp `json:"failure,omitempty"`
+}
+
+type txnResponse struct {
+	Header    responseHeader `json:"header"`
+	Succeeded bool           `json:"succeeded"`
+}
+
+type leaseGrantRequest struct {
+	TTL int64s `json:"TTL"`
+}
+
+type leaseGrantResponse struct {
+	ID    int64s `json:"ID"`
+	TTL   int64s `json:"TTL"`
+	Error string `json:"error"`
+}
+
+type leaseKeepAliveRequest struct {
+	ID int64s `json:"ID"`
+}
+
+type leaseKeepAliveResponse struct {
+	Result struct {
+		ID  int64s `json:"ID"`
+		TTL int64s `json:"TTL"`
+	} `json:"result"`
+}
+
+type leaseRevokeRequest struct {
+	ID int64s `json:"ID"`
+}
+
+type watchCreateRequest struct {
+	Key           []byte `json:"key"`
+	RangeEnd      []byte `json:"range_end,omitempty"`
+	StartRevision int64s `json:"start_revision,omitempty"`
+}
+
+type watchRequest struct {
+	CreateRequest watchCreateRequest `json:"create_request"`
+}
+
+// watch event types
+const (
+	eventPut    = "PUT"
+	eventDelete = "DELETE"
+)
+
+type watchEvent struct {
+	// Type is omitted by the gateway for PUT events
+	Type string   `json:"type"`
+	Kv   keyValue `json:"kv"`
+}
+
+type watchResponse struct {
+	Result struct {
+		Created  bool         `json:"created"`
+		Canceled bool         `json:"canceled"`
+		Events   []watchEvent `json:"events"`
+	} `json:"result"`
+	Error *gatewayError `json:"error"`
+}
+
+type gatewayError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+func (e *gatewayError) Error() string {
+	return fmt.Sprintf("etcd: %s (code %d)", e.Message, e.Code)
+}
+
+// gateway is a client of the etcd v3 JSON gateway
+type gateway struct {
+	endpoints []string
+	client    *http.Client
+	timeout   time.Duration
+}
+
+// newGateway returns a gateway whose requests time out after the given
+// duration.  The http client itself has no overall timeout, because watches
+// are long lived streams; only dialing and the tls handshake are bounded.
+func newGateway(endpoints []string, timeout time.Duration) *gateway {
+	return &gateway{
+		endpoints: endpoints,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   timeout,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout: timeout,
+			},
+		},
+		timeout: timeout,
+	}
+}
+
+// open sends a request to the first endpoint that responds and returns the
+// response.  The caller is responsible for closing the response body.
+func (g *gateway) open(ctx context.Context, method string, req interface{}) (*http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for _, endpoint := range g.endpoints {
+		r, err := http.NewRequest("POST", strings.TrimRight(endpoint, "/")+apiPrefix+method, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Set("Content-Type", "application/json")
+		resp, err := g.client.Do(r.WithContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			plog.WithError(err).WithField("endpoint", endpoint).Debug("Could not reach etcd endpoint")
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			data, _ := ioutil.ReadAll(resp.Body)
+			gerr := &gatewayError{}
+			if err := json.Unmarshal(data, gerr); err != nil || gerr.Message == "" {
+				gerr.Message, gerr.Code = strings.TrimSpace(string(data)), resp.StatusCode
+			}
+			return nil, gerr
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
+// call sends a request and decodes the response, giving up if both have not
+// completed within the gateway's timeout
+func (g *gateway) call(method string, req, resp interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	r, err := g.open(ctx, method, req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	return json.NewDecoder(r.Body).Decode(resp)
+}
+
+func (g *gateway) get(key []byte) (*rangeResponse, error) {
+	resp := &rangeResponse{}
+	err := g.call("/kv/range", rangeRequest{Key: key}, resp)
+	return resp, err
+}
+
+func (g *gateway) list(prefix []byte) (*rangeResponse, error) {
+	resp := &rangeResponse{}
+	err := g.call("/kv/range", rangeRequest{Key: prefix, RangeEnd: prefixEnd(prefix), KeysOnly: true}, resp)
+	return resp, err
+}
+
+func (g *gateway) txn(req txnRequest) (*txnResponse, error) {
+	resp := &txnResponse{}
+	err := g.call("/kv/txn", req, resp)
+	return resp, err
+}
+
+// prefixEnd returns the range end that matches all keys with the given prefix
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return []byte{0}
+}
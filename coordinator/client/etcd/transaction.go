@@ -0,0 +1,154 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"encoding/json"
+	"path"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+const (
+	multiCreate int = iota
+	multiSet
+	multiDelete
+)
+
+type multiReq struct {
+	Type int
+	Path string
+	Node client.Node
+}
+
+// Transaction commits a set of operations in a single etcd transaction.
+type Transaction struct {
+	conn *Connection
+	ops  []multiReq
+}
+
+func (t *Transaction) Create(path string, node client.Node) client.Transaction {
+	t.ops = append(t.ops, multiReq{multiCreate, path, node})
+	return t
+}
+
+func (t *Transaction) Set(path string, node client.Node) client.Transaction {
+	t.ops = append(t.ops, multiReq{multiSet, path, node})
+	return t
+}
+
+func (t *Transaction) Delete(path string) client.Transaction {
+	t.ops = append(t.ops, multiReq{multiDelete, path, nil})
+	return t
+}
+
+func (t *Transaction) Commit() error {
+	t.conn.RLock()
+	defer t.conn.RUnlock()
+	if err := t.conn.isClosed(); err != nil {
+		return err
+	}
+	req := txnRequest{}
+	created := make(map[string]bool)
+	for _, op := range t.ops {
+		pth := path.Join(t.conn.basePath, op.Path)
+		key := t.conn.key(pth)
+		logger := plog.WithField("path", pth)
+		switch op.Type {
+		case multiCreate:
+			data, err := json.Marshal(op.Node)
+			if err != nil {
+				logger.WithError(err).WithField("node", op.Node).Error("Could not serialize node at path")
+				return client.ErrSerialization
+			}
+			// a parent created earlier in the same transaction cannot be
+			// compared, because comparisons are evaluated before any puts
+			if !created[path.Dir(pth)] {
+				req.Compare = append(req.Compare, t.conn.parentCmp(pth)...)
+			}
+			req.Compare = append(req.Compare, compare{Result: compareEqual, Target: targetCreate, Key: key, CreateRevision: newInt64s(0)})
+			req.Success = append(req.Success, requestOp{RequestPut: &putRequest{Key: key, Value: data}})
+			created[pth] = true
+		case multiSet:
+			data, err := json.Marshal(op.Node)
+			if err != nil {
+				logger.WithError(err).WithField("node", op.Node).Error("Could not serialize node at path")
+				return client.ErrSerialization
+			}
+			version, err := getVersion(op.Node)
+			if err != nil {
+				logger.WithError(err).WithField("node", op.Node).Error("Could not parse version of node at path")
+				return err
+			}
+			req.Compare = append(req.Compare, compare{Result: compareEqual, Target: targetVersion, Key: key, Version: newInt64s(version + 1)})
+			req.Success = append(req.Success, requestOp{RequestPut: &putRequest{Key: key, Value: data, IgnoreLease: true}})
+		case multiDelete:
+			// deletes within a transaction are not recursive
+			children, err := t.conn.children(op.Path)
+			if err != nil {
+				logger.WithError(err).Error("Could not find path for delete")
+				return err
+			} else if len(children) > 0 {
+				return client.ErrNotEmpty
+			}
+			req.Compare = append(req.Compare, compare{Result: compareGreater, Target: targetCreate, Key: key, CreateRevision: newInt64s(0)})
+			req.Success = append(req.Success, requestOp{RequestDeleteRange: &deleteRangeRequest{Key: key}})
+		}
+	}
+	resp, err := t.conn.api.txn(req)
+	if err != nil {
+		return err
+	} else if !resp.Succeeded {
+		return t.failure()
+	}
+	for _, op := range t.ops {
+		if op.Type == multiCreate {
+			op.Node.SetVersion(&Stat{})
+		}
+	}
+	return nil
+}
+
+// failure returns the reason that the transaction was not committed
+func (t *Transaction) failure() error {
+	created := make(map[string]bool)
+	for _, op := range t.ops {
+		pth := path.Join(t.conn.basePath, op.Path)
+		switch op.Type {
+		case multiCreate:
+			if ok, err := t.conn.exists(pth); err != nil {
+				return err
+			} else if ok {
+				return client.ErrNodeExists
+			}
+			if dir := path.Dir(pth); !created[dir] {
+				if err := t.conn.parentError(dir); err != nil {
+					return err
+				}
+			}
+			created[pth] = true
+		case multiSet:
+			if err := t.conn.versionError(pth); err == client.ErrNoNode {
+				return err
+			}
+		case multiDelete:
+			if ok, err := t.conn.exists(pth); err != nil {
+				return err
+			} else if !ok {
+				return client.ErrNoNode
+			}
+		}
+	}
+	return client.ErrBadVersion
+}
@@ -0,0 +1,372 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"encoding/json"
+	"path"
+	"sync"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// Stat is the version object of nodes stored by the memory driver.
+type Stat struct {
+	Version int32
+}
+
+// Connection is an in-memory implementation of client.Connection.
+type Connection struct {
+	sync.RWMutex
+	store    *store
+	session  int64
+	basePath string
+	onClose  func(int)
+	id       int
+}
+
+// Assert that Connection implements client.Connection.
+var _ client.Connection = &Connection{}
+
+// IsClosed returns connection closed error if true, otherwise returns nil.
+func (c *Connection) isClosed() error {
+	if c.store == nil {
+		return client.ErrConnectionClosed
+	}
+	return nil
+}
+
+// Close closes the connection, removing all of its ephemeral nodes. Calling
+// close twice will result in a no-op.
+func (c *Connection) Close() {
+	c.Lock()
+	defer c.Unlock()
+	if c.store != nil {
+		c.store.closeSession(c.session)
+		c.store = nil
+		if c.onClose != nil {
+			c.onClose(c.id)
+			c.onClose = nil
+		}
+	}
+}
+
+// SetID sets the connection ID
+func (c *Connection) SetID(i int) {
+	c.Lock()
+	defer c.Unlock()
+	c.id = i
+}
+
+// ID gets the connection ID
+func (c *Connection) ID() int {
+	c.RLock()
+	defer c.RUnlock()
+	return c.id
+}
+
+// SetOnClose performs cleanup when a connection is closed
+func (c *Connection) SetOnClose(onClose func(int)) {
+	c.Lock()
+	defer c.Unlock()
+	if err := c.isClosed(); err == nil {
+		c.onClose = onClose
+	}
+}
+
+// NewTransaction creates a new transaction object
+func (c *Connection) NewTransaction() client.Transaction {
+	return &Transaction{conn: c}
+}
+
+// NewLock creates a new lock object
+func (c *Connection) NewLock(p string) (client.Lock, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return nil, err
+	}
+	return client.NewSequentialLock(c, p), nil
+}
+
+// NewLeader returns a managed leader object at the given path bound to the
+// current connection.
+func (c *Connection) NewLeader(p string) (client.Leader, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return nil, err
+	}
+	return client.NewSequentialLeader(c, p), nil
+}
+
+// Create adds a node at the specified path
+func (c *Connection) Create(path string, node client.Node) error {
+	return c.create(path, node, true)
+}
+
+// CreateIfExists adds a node at the specified path if the dirpath already
+// exists.
+func (c *Connection) CreateIfExists(path string, node client.Node) error {
+	return c.create(path, node, false)
+}
+
+func (c *Connection) create(p string, node client.Node, ensurePath bool) error {
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return client.ErrSerialization
+	}
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	c.store.Lock()
+	defer c.store.Unlock()
+	defer c.store.flush()
+	pth := path.Join(c.basePath, p)
+	if ensurePath {
+		if err := c.store.mkdirs(pth); err != nil {
+			return err
+		}
+	}
+	if _, err := c.store.create(pth, bytes, 0, false); err != nil {
+		return err
+	}
+	node.SetVersion(&Stat{})
+	return nil
+}
+
+// CreateDir adds a dir at the specified path
+func (c *Connection) CreateDir(p string) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	c.store.Lock()
+	defer c.store.Unlock()
+	defer c.store.flush()
+	pth := path.Join(c.basePath, p)
+	if err := c.store.mkdirs(pth); err != nil {
+		return err
+	}
+	_, err := c.store.create(pth, []byte{}, 0, false)
+	return err
+}
+
+// CreateEphemeral creates a node whose existance depends on the persistence of
+// the connection.
+func (c *Connection) CreateEphemeral(path string, node client.Node) (string, error) {
+	return c.createEphemeral(path, node, true)
+}
+
+// CreateEphemeralIfExists creates an ephemeral node at the given path if it
+// exists.
+func (c *Connection) CreateEphemeralIfExists(path string, node client.Node) (string, error) {
+	return c.createEphemeral(path, node, false)
+}
+
+func (c *Connection) createEphemeral(p string, node client.Node, ensurePath bool) (string, error) {
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return "", client.ErrSerialization
+	}
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return "", err
+	}
+	c.store.Lock()
+	defer c.store.Unlock()
+	defer c.store.flush()
+	pth := path.Join(c.basePath, p)
+	if ensurePath {
+		if err := c.store.mkdirs(pth); err != nil {
+			return "", err
+		}
+	}
+	return c.store.createProtected(pth, bytes, c.session)
+}
+
+// Set assigns a value to an existing node at a given path
+func (c *Connection) Set(p string, node client.Node) error {
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return client.ErrSerialization
+	}
+	version, err := getVersion(node)
+	if err != nil {
+		return err
+	}
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	c.store.Lock()
+	defer c.store.Unlock()
+	defer c.store.flush()
+	return c.store.set(path.Join(c.basePath, p), bytes, version)
+}
+
+// Delete recursively removes a path and its children
+func (c *Connection) Delete(p string) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	c.store.Lock()
+	defer c.store.Unlock()
+	defer c.store.flush()
+	return c.store.removeAll(path.Join(c.basePath, p))
+}
+
+// Exists returns true if the path exists
+func (c *Connection) Exists(p string) (bool, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return false, err
+	}
+	c.store.Lock()
+	defer c.store.Unlock()
+	return c.store.lookup(path.Join(c.basePath, p)) != nil, nil
+}
+
+// ExistsW sets a watch on a node and alerts whenever it is added or removed.
+func (c *Connection) ExistsW(p string, cancel <-chan struct{}) (bool, <-chan client.Event, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return false, nil, err
+	}
+	c.store.Lock()
+	defer c.store.Unlock()
+	pth := path.Join(c.basePath, p)
+	ok := c.store.lookup(pth) != nil
+	w := c.store.addWatch(c.store.dataW, pth, c.session)
+	return ok, c.toClientEvent(w, cancel), nil
+}
+
+// Get returns the node at the given path.
+func (c *Connection) Get(p string, node client.Node) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	c.store.Lock()
+	bytes, version, err := c.store.get(path.Join(c.basePath, p))
+	c.store.Unlock()
+	if err != nil {
+		return err
+	}
+	if len(bytes) > 0 {
+		if err := json.Unmarshal(bytes, node); err != nil {
+			return client.ErrSerialization
+		}
+	} else {
+		err = client.ErrEmptyNode
+	}
+	node.SetVersion(&Stat{Version: version})
+	return err
+}
+
+// GetW returns the node at the given path as well as a channel to watch for
+// events on that node.
+func (c *Connection) GetW(p string, node client.Node, cancel <-chan struct{}) (<-chan client.Event, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return nil, err
+	}
+	c.store.Lock()
+	defer c.store.Unlock()
+	pth := path.Join(c.basePath, p)
+	bytes, version, err := c.store.get(pth)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes) == 0 {
+		return nil, client.ErrEmptyNode
+	} else if err := json.Unmarshal(bytes, node); err != nil {
+		return nil, client.ErrSerialization
+	}
+	node.SetVersion(&Stat{Version: version})
+	w := c.store.addWatch(c.store.dataW, pth, c.session)
+	return c.toClientEvent(w, cancel), nil
+}
+
+// Children returns the children of the node at the given path.
+func (c *Connection) Children(p string) ([]string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return []string{}, err
+	}
+	c.store.Lock()
+	defer c.store.Unlock()
+	return c.store.children(path.Join(c.basePath, p))
+}
+
+// ChildrenW returns the children of the node at the given path as well as a
+// channel to watch for events on that node.
+func (c *Connection) ChildrenW(p string, cancel <-chan struct{}) ([]string, <-chan client.Event, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return []string{}, nil, err
+	}
+	c.store.Lock()
+	defer c.store.Unlock()
+	pth := path.Join(c.basePath, p)
+	children, err := c.store.children(pth)
+	if err != nil {
+		return []string{}, nil, err
+	}
+	w := c.store.addWatch(c.store.childW, pth, c.session)
+	return children, c.toClientEvent(w, cancel), nil
+}
+
+func (c *Connection) toClientEvent(w *watch, cancel <-chan struct{}) <-chan client.Event {
+	evCh := make(chan client.Event, 1)
+	s := c.store
+	go func() {
+		select {
+		case ev := <-w.ch:
+			select {
+			case evCh <- client.Event{Type: ev.Type}:
+			case <-cancel:
+			}
+		case <-cancel:
+			s.cancelWatch(w)
+		}
+	}()
+	return evCh
+}
+
+// getVersion returns the version of a node to be updated
+func getVersion(node client.Node) (int32, error) {
+	version := node.Version()
+	if version == nil {
+		return 0, nil
+	}
+	stat, ok := version.(*Stat)
+	if !ok {
+		return 0, client.ErrInvalidVersionObj
+	}
+	return stat.Version, nil
+}
@@ -0,0 +1,457 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package memory
+
+import (
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+type testNodeT struct {
+	Name    string
+	version interface{}
+}
+
+func (n *testNodeT) SetVersion(version interface{}) { n.version = version }
+func (n *testNodeT) Version() interface{}           { return n.version }
+
+func newTestConnection(t *testing.T, dsn string) client.Connection {
+	drv := Driver{}
+	conn, err := drv.GetConnection(dsn, "/basePath")
+	if err != nil {
+		t.Fatalf("unexpected error getting connection: %s", err)
+	}
+	return conn
+}
+
+func waitForEvent(t *testing.T, ev <-chan client.Event, expected client.EventType) {
+	select {
+	case e := <-ev:
+		if e.Type != expected {
+			t.Fatalf("expected event %v; actual: %v", expected, e.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for event %v", expected)
+	}
+}
+
+func TestMemoryDriver(t *testing.T) {
+	defer Reset("driver")
+	conn := newTestConnection(t, "driver")
+	defer conn.Close()
+
+	if err := conn.CreateDir("/foo"); err != nil {
+		t.Fatalf("creating /foo should work: %s", err)
+	}
+	if err := conn.Get("/foo", &testNodeT{}); err != client.ErrEmptyNode {
+		t.Fatalf("expected empty node, got %v", err)
+	}
+	if err := conn.CreateDir("/foo"); err != client.ErrNodeExists {
+		t.Fatalf("expected %s, got %v", client.ErrNodeExists, err)
+	}
+
+	node := &testNodeT{Name: "bar"}
+	if err := conn.Create("/a/b/bar", node); err != nil {
+		t.Fatalf("creating /a/b/bar should work: %s", err)
+	}
+	if err := conn.CreateIfExists("/c/d", &testNodeT{}); err != client.ErrNoNode {
+		t.Fatalf("expected %s, got %v", client.ErrNoNode, err)
+	}
+
+	actual := &testNodeT{}
+	if err := conn.Get("/a/b/bar", actual); err != nil {
+		t.Fatalf("could not get /a/b/bar: %s", err)
+	}
+	if actual.Name != "bar" {
+		t.Fatalf("expected bar, got %s", actual.Name)
+	}
+
+	// set updates the version, so a stale node cannot overwrite the data
+	actual.Name = "baz"
+	if err := conn.Set("/a/b/bar", actual); err != nil {
+		t.Fatalf("could not set /a/b/bar: %s", err)
+	}
+	node.Name = "qux"
+	if err := conn.Set("/a/b/bar", node); err != client.ErrBadVersion {
+		t.Fatalf("expected %s, got %v", client.ErrBadVersion, err)
+	}
+	if err := conn.Get("/a/b/bar", node); err != nil {
+		t.Fatalf("could not get /a/b/bar: %s", err)
+	} else if node.Name != "baz" {
+		t.Fatalf("expected baz, got %s", node.Name)
+	}
+	node.SetVersion("bad version")
+	if err := conn.Set("/a/b/bar", node); err != client.ErrInvalidVersionObj {
+		t.Fatalf("expected %s, got %v", client.ErrInvalidVersionObj, err)
+	}
+
+	if err := conn.Create("/a/b/abc", &testNodeT{}); err != nil {
+		t.Fatalf("creating /a/b/abc should work: %s", err)
+	}
+	children, err := conn.Children("/a/b")
+	if err != nil {
+		t.Fatalf("could not get children of /a/b: %s", err)
+	}
+	if strings.Join(children, ",") != "abc,bar" {
+		t.Fatalf("unexpected children %v", children)
+	}
+
+	// delete is recursive
+	if err := conn.Delete("/a"); err != nil {
+		t.Fatalf("could not delete /a: %s", err)
+	}
+	if ok, err := conn.Exists("/a/b/bar"); err != nil || ok {
+		t.Fatalf("expected /a/b/bar to be deleted (%v)", err)
+	}
+	if err := conn.Delete("/a"); err != client.ErrNoNode {
+		t.Fatalf("expected %s, got %v", client.ErrNoNode, err)
+	}
+
+	// connections with a different base path share the same tree
+	drv := Driver{}
+	root, err := drv.GetConnection("driver", "/")
+	if err != nil {
+		t.Fatalf("unexpected error getting connection: %s", err)
+	}
+	if ok, err := root.Exists("/basePath/foo"); err != nil || !ok {
+		t.Fatalf("expected /basePath/foo to exist (%v)", err)
+	}
+
+	root.Close()
+	if _, err := root.Exists("/basePath/foo"); err != client.ErrConnectionClosed {
+		t.Fatalf("expected %s, got %v", client.ErrConnectionClosed, err)
+	}
+}
+
+func TestMemoryDriver_Multi(t *testing.T) {
+	defer Reset("multi")
+	conn := newTestConnection(t, "multi")
+	defer conn.Close()
+
+	if err := conn.CreateDir("/multi"); err != nil {
+		t.Fatalf("creating /multi should work: %s", err)
+	}
+	node := &testNodeT{Name: "a"}
+	if err := conn.Create("/multi/a", node); err != nil {
+		t.Fatalf("creating /multi/a should work: %s", err)
+	}
+
+	// a failing operation rolls back the whole transaction
+	node.Name = "changed"
+	err := conn.NewTransaction().
+		Create("/multi/b", &testNodeT{Name: "b"}).
+		Set("/multi/a", node).
+		Delete("/multi/missing").
+		Commit()
+	if err != client.ErrNoNode {
+		t.Fatalf("expected %s, got %v", client.ErrNoNode, err)
+	}
+	if ok, _ := conn.Exists("/multi/b"); ok {
+		t.Fatal("expected /multi/b to be rolled back")
+	}
+	actual := &testNodeT{}
+	if err := conn.Get("/multi/a", actual); err != nil {
+		t.Fatalf("could not get /multi/a: %s", err)
+	} else if actual.Name != "a" {
+		t.Fatalf("expected a, got %s", actual.Name)
+	}
+
+	// delete is not recursive within a transaction
+	if err := conn.NewTransaction().Delete("/multi").Commit(); err != client.ErrNotEmpty {
+		t.Fatalf("expected %s, got %v", client.ErrNotEmpty, err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	_, ev, err := conn.ChildrenW("/multi", done)
+	if err != nil {
+		t.Fatalf("could not watch /multi: %s", err)
+	}
+	err = conn.NewTransaction().
+		Create("/multi/b", &testNodeT{Name: "b"}).
+		Set("/multi/a", actual).
+		Delete("/multi/a").
+		Commit()
+	if err != nil {
+		t.Fatalf("could not commit transaction: %s", err)
+	}
+	waitForEvent(t, ev, client.EventNodeChildrenChanged)
+	children, err := conn.Children("/multi")
+	if err != nil {
+		t.Fatalf("could not get children of /multi: %s", err)
+	}
+	if strings.Join(children, ",") != "b" {
+		t.Fatalf("unexpected children %v", children)
+	}
+}
+
+func TestMemoryDriver_Ephemeral(t *testing.T) {
+	defer Reset("ephemeral")
+	conn := newTestConnection(t, "ephemeral")
+	defer conn.Close()
+	other := newTestConnection(t, "ephemeral")
+
+	p1, err := other.CreateEphemeral("/ephemeral/node", &testNodeT{Name: "1"})
+	if err != nil {
+		t.Fatalf("could not create ephemeral node: %s", err)
+	}
+	p2, err := other.CreateEphemeralIfExists("/ephemeral/node", &testNodeT{Name: "2"})
+	if err != nil {
+		t.Fatalf("could not create ephemeral node: %s", err)
+	}
+	if !strings.HasPrefix(p1, "/basePath/ephemeral/_c_") || !strings.HasSuffix(p1, "-node0000000000") {
+		t.Fatalf("unexpected ephemeral path %s", p1)
+	}
+	if !strings.HasSuffix(p2, "-node0000000001") {
+		t.Fatalf("unexpected ephemeral path %s", p2)
+	}
+	if _, err := other.CreateEphemeralIfExists("/missing/node", &testNodeT{}); err != client.ErrNoNode {
+		t.Fatalf("expected %s, got %v", client.ErrNoNode, err)
+	}
+	if err := conn.Create(path.Join("/ephemeral", path.Base(p1), "child"), &testNodeT{}); err != client.ErrNoChildrenForEphemerals {
+		t.Fatalf("expected %s, got %v", client.ErrNoChildrenForEphemerals, err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	_, ev, err := conn.ChildrenW("/ephemeral", done)
+	if err != nil {
+		t.Fatalf("could not watch /ephemeral: %s", err)
+	}
+	_, otherEv, err := other.ExistsW("/ephemeral", done)
+	if err != nil {
+		t.Fatalf("could not watch /ephemeral: %s", err)
+	}
+
+	// closing the connection removes its ephemeral nodes and watches
+	other.Close()
+	waitForEvent(t, ev, client.EventNodeChildrenChanged)
+	waitForEvent(t, otherEv, client.EventNotWatching)
+	children, err := conn.Children("/ephemeral")
+	if err != nil {
+		t.Fatalf("could not get children of /ephemeral: %s", err)
+	}
+	if len(children) != 0 {
+		t.Fatalf("expected no children, got %v", children)
+	}
+}
+
+func TestMemoryDriver_Watch(t *testing.T) {
+	defer Reset("watch")
+	conn := newTestConnection(t, "watch")
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	ok, ev, err := conn.ExistsW("/foo", done)
+	if err != nil {
+		t.Fatalf("could not watch /foo: %s", err)
+	} else if ok {
+		t.Fatal("expected /foo to not exist")
+	}
+	node := &testNodeT{Name: "foo"}
+	if err := conn.Create("/foo", node); err != nil {
+		t.Fatalf("creating /foo should work: %s", err)
+	}
+	waitForEvent(t, ev, client.EventNodeCreated)
+
+	ev, err = conn.GetW("/foo", node, done)
+	if err != nil {
+		t.Fatalf("could not watch /foo: %s", err)
+	}
+	if err := conn.Set("/foo", node); err != nil {
+		t.Fatalf("could not set /foo: %s", err)
+	}
+	waitForEvent(t, ev, client.EventNodeDataChanged)
+
+	// watches only fire once
+	if err := conn.Get("/foo", node); err != nil {
+		t.Fatalf("could not get /foo: %s", err)
+	}
+	if err := conn.Set("/foo", node); err != nil {
+		t.Fatalf("could not set /foo: %s", err)
+	}
+	select {
+	case e := <-ev:
+		t.Fatalf("unexpected event %v", e)
+	default:
+	}
+
+	_, ev, err = conn.ChildrenW("/foo", done)
+	if err != nil {
+		t.Fatalf("could not watch /foo: %s", err)
+	}
+	ev2, err := conn.GetW("/foo", node, done)
+	if err != nil {
+		t.Fatalf("could not watch /foo: %s", err)
+	}
+	if err := conn.Delete("/foo"); err != nil {
+		t.Fatalf("could not delete /foo: %s", err)
+	}
+	waitForEvent(t, ev, client.EventNodeDeleted)
+	waitForEvent(t, ev2, client.EventNodeDeleted)
+
+	// cancelled watches do not fire
+	cancel := make(chan struct{})
+	_, ev, err = conn.ExistsW("/foo", cancel)
+	if err != nil {
+		t.Fatalf("could not watch /foo: %s", err)
+	}
+	close(cancel)
+	time.Sleep(100 * time.Millisecond)
+	if err := conn.CreateDir("/foo"); err != nil {
+		t.Fatalf("creating /foo should work: %s", err)
+	}
+	select {
+	case e := <-ev:
+		t.Fatalf("unexpected event %v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestMemoryDriver_Lock(t *testing.T) {
+	defer Reset("lock")
+	conn := newTestConnection(t, "lock")
+	defer conn.Close()
+
+	lock, err := conn.NewLock("/foo/bar")
+	if err != nil {
+		t.Fatalf("unexpected error initializing lock: %s", err)
+	}
+	if err := lock.Lock(); err != nil {
+		t.Fatalf("unexpected error acquiring lock: %s", err)
+	}
+	if err := lock.Lock(); err != client.ErrDeadlock {
+		t.Fatalf("expected %s, got %v", client.ErrDeadlock, err)
+	}
+
+	lock2, err := conn.NewLock("/foo/bar")
+	if err != nil {
+		t.Fatalf("unexpected error initializing lock: %s", err)
+	}
+	lock2Response := make(chan error)
+	go func() {
+		lock2Response <- lock2.Lock()
+	}()
+	select {
+	case response := <-lock2Response:
+		t.Fatalf("Expected second lock to block, got %v", response)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatalf("unexpected error releasing lock: %s", err)
+	}
+	select {
+	case response := <-lock2Response:
+		if response != nil {
+			t.Fatalf("Did not expect error when attempting second lock: %s", response)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout on second lock")
+	}
+	if err := lock2.Unlock(); err != nil {
+		t.Fatalf("unexpected error releasing lock: %s", err)
+	}
+	if err := lock2.Unlock(); err != client.ErrNotLocked {
+		t.Fatalf("expected %s, got %v", client.ErrNotLocked, err)
+	}
+	children, err := conn.Children("/foo/bar")
+	if err != nil {
+		t.Fatalf("could not get children of /foo/bar: %s", err)
+	}
+	if len(children) != 0 {
+		t.Fatalf("expected no children, got %v", children)
+	}
+}
+
+func TestMemoryDriver_Leader(t *testing.T) {
+	defer Reset("leader")
+	conn := newTestConnection(t, "leader")
+	defer conn.Close()
+	other := newTestConnection(t, "leader")
+
+	if err := conn.CreateDir("/election"); err != nil {
+		t.Fatalf("creating /election should work: %s", err)
+	}
+	leader, err := conn.NewLeader("/election")
+	if err != nil {
+		t.Fatalf("unexpected error initializing leader: %s", err)
+	}
+	if err := leader.Current(&testNodeT{}); err != client.ErrNoLeaderFound {
+		t.Fatalf("expected %s, got %v", client.ErrNoLeaderFound, err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	leader1, err := other.NewLeader("/election")
+	if err != nil {
+		t.Fatalf("unexpected error initializing leader: %s", err)
+	}
+	ev, err := leader1.TakeLead(&testNodeT{Name: "leader1"}, done)
+	if err != nil {
+		t.Fatalf("could not take lead: %s", err)
+	}
+
+	leader2, err := conn.NewLeader("/election")
+	if err != nil {
+		t.Fatalf("unexpected error initializing leader: %s", err)
+	}
+	leader2Response := make(chan error)
+	go func() {
+		_, err := leader2.TakeLead(&testNodeT{Name: "leader2"}, done)
+		leader2Response <- err
+	}()
+	select {
+	case response := <-leader2Response:
+		t.Fatalf("Expected second leader to block, got %v", response)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	current := &testNodeT{}
+	if err := leader.Current(current); err != nil {
+		t.Fatalf("could not get current leader: %s", err)
+	} else if current.Name != "leader1" {
+		t.Fatalf("expected leader1, got %s", current.Name)
+	}
+
+	// losing the session hands the lead to the next candidate
+	other.Close()
+	waitForEvent(t, ev, client.EventNotWatching)
+	select {
+	case response := <-leader2Response:
+		if response != nil {
+			t.Fatalf("Did not expect error when taking lead: %s", response)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout on second leader")
+	}
+	if err := leader.Current(current); err != nil {
+		t.Fatalf("could not get current leader: %s", err)
+	} else if current.Name != "leader2" {
+		t.Fatalf("expected leader2, got %s", current.Name)
+	}
+	if err := leader2.ReleaseLead(); err != nil {
+		t.Fatalf("could not release lead: %s", err)
+	}
+	if err := leader2.ReleaseLead(); err != client.ErrNotLocked {
+		t.Fatalf("expected %s, got %v", client.ErrNotLocked, err)
+	}
+}
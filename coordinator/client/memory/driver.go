@@ -0,0 +1,81 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"sync"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/logging"
+)
+
+var (
+	plog = logging.PackageLogger() // the standard package logger
+
+	storesLock sync.Mutex
+	stores     = make(map[string]*store)
+)
+
+// DefaultDSN is the name of the store used when the dsn is empty
+const DefaultDSN = "default"
+
+// Driver implements an in-process client.Driver.  Connections that are
+// opened with the same dsn share the same tree of nodes, which makes the
+// driver suitable for tests and single-host deployments that do not need an
+// external coordination service.  Nothing is persisted once the process
+// exits.
+type Driver struct{}
+
+// Assert that the memory driver meets the Driver interface
+var _ client.Driver = &Driver{}
+
+func init() {
+	client.RegisterDriver("memory", &Driver{})
+}
+
+// GetConnection returns a connection to the store named by the dsn. The caller
+// is responsible for closing the returned connection.
+func (driver *Driver) GetConnection(dsn, basePath string) (client.Connection, error) {
+	s := getStore(dsn)
+	return &Connection{
+		store:    s,
+		session:  s.newSession(),
+		basePath: basePath,
+	}, nil
+}
+
+// Reset discards all of the data in the store named by the dsn.  Connections
+// that are open to the previous store are unaffected.
+func Reset(dsn string) {
+	if dsn == "" {
+		dsn = DefaultDSN
+	}
+	storesLock.Lock()
+	defer storesLock.Unlock()
+	delete(stores, dsn)
+}
+
+func getStore(dsn string) *store {
+	if dsn == "" {
+		dsn = DefaultDSN
+	}
+	storesLock.Lock()
+	defer storesLock.Unlock()
+	s, ok := stores[dsn]
+	if !ok {
+		s = newStore()
+		stores[dsn] = s
+	}
+	return s
+}
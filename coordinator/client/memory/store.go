@@ -0,0 +1,322 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"crypto/rand"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// protectedPrefix is prepended to the names of ephemeral nodes, matching the
+// naming used by the zookeeper driver.
+const protectedPrefix = "_c_"
+
+// node is an entry in the in-memory tree
+type node struct {
+	data     []byte
+	version  int32
+	owner    int64 // session that owns an ephemeral node, 0 if persistent
+	cseq     int32 // sequence counter for sequential children
+	children map[string]*node
+}
+
+func newNode(data []byte, owner int64) *node {
+	return &node{data: data, owner: owner, children: make(map[string]*node)}
+}
+
+// watch is a one-shot subscription to changes on a path
+type watch struct {
+	session int64
+	ch      chan client.Event
+}
+
+// store is an in-process tree of nodes shared by all connections that use the
+// same dsn.
+type store struct {
+	sync.Mutex
+	root     *node
+	sessions int64
+	dataW    map[string][]*watch
+	childW   map[string][]*watch
+	pending  []func()
+}
+
+func newStore() *store {
+	return &store{
+		root:   newNode(nil, 0),
+		dataW:  make(map[string][]*watch),
+		childW: make(map[string][]*watch),
+	}
+}
+
+// newSession returns a new session id
+func (s *store) newSession() int64 {
+	s.Lock()
+	defer s.Unlock()
+	s.sessions++
+	return s.sessions
+}
+
+// lookup returns the node at the given path or nil if it does not exist
+func (s *store) lookup(p string) *node {
+	n := s.root
+	for _, name := range split(p) {
+		if n = n.children[name]; n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// mkdirs creates all of the missing ancestors of p
+func (s *store) mkdirs(p string) error {
+	dir := path.Dir(path.Clean(p))
+	if dir == "/" || dir == "." {
+		return nil
+	}
+	if s.lookup(dir) != nil {
+		return nil
+	}
+	if err := s.mkdirs(dir); err != nil {
+		return err
+	}
+	_, err := s.create(dir, []byte{}, 0, false)
+	return err
+}
+
+// create adds a node at the given path.  If sequential is true, a sequence
+// number is appended to the name of the node.
+func (s *store) create(p string, data []byte, owner int64, sequential bool) (string, error) {
+	p = path.Clean(p)
+	if p == "/" {
+		return "", client.ErrNodeExists
+	}
+	dir, name := path.Split(p)
+	parent := s.lookup(dir)
+	if parent == nil {
+		return "", client.ErrNoNode
+	} else if parent.owner != 0 {
+		return "", client.ErrNoChildrenForEphemerals
+	}
+	if sequential {
+		name = fmt.Sprintf("%s%010d", name, parent.cseq)
+		p = path.Join(dir, name)
+	}
+	if _, ok := parent.children[name]; ok {
+		return "", client.ErrNodeExists
+	}
+	parent.cseq++
+	parent.children[name] = newNode(data, owner)
+	s.notify(s.dataW, p, client.EventNodeCreated)
+	s.notify(s.childW, path.Clean(dir), client.EventNodeChildrenChanged)
+	return p, nil
+}
+
+// createProtected creates an ephemeral sequential node whose name is prefixed
+// with a unique id.
+func (s *store) createProtected(p string, data []byte, owner int64) (string, error) {
+	guid := make([]byte, 16)
+	if _, err := rand.Read(guid); err != nil {
+		return "", err
+	}
+	dir, name := path.Split(path.Clean(p))
+	name = fmt.Sprintf("%s%x-%s", protectedPrefix, guid, name)
+	return s.create(path.Join(dir, name), data, owner, true)
+}
+
+// get returns the data and version of the node at the given path
+func (s *store) get(p string) ([]byte, int32, error) {
+	n := s.lookup(p)
+	if n == nil {
+		return nil, 0, client.ErrNoNode
+	}
+	return n.data, n.version, nil
+}
+
+// set updates the data of the node at the given path if the version matches.
+// A version of -1 matches any version.
+func (s *store) set(p string, data []byte, version int32) error {
+	n := s.lookup(p)
+	if n == nil {
+		return client.ErrNoNode
+	} else if version != -1 && version != n.version {
+		return client.ErrBadVersion
+	}
+	n.data = data
+	n.version++
+	s.notify(s.dataW, path.Clean(p), client.EventNodeDataChanged)
+	return nil
+}
+
+// remove deletes a leaf node if the version matches.  A version of -1
+// matches any version.
+func (s *store) remove(p string, version int32) error {
+	p = path.Clean(p)
+	n := s.lookup(p)
+	if n == nil || p == "/" {
+		return client.ErrNoNode
+	} else if len(n.children) > 0 {
+		return client.ErrNotEmpty
+	} else if version != -1 && version != n.version {
+		return client.ErrBadVersion
+	}
+	dir, name := path.Split(p)
+	delete(s.lookup(dir).children, name)
+	s.notify(s.dataW, p, client.EventNodeDeleted)
+	s.notify(s.childW, p, client.EventNodeDeleted)
+	s.notify(s.childW, path.Clean(dir), client.EventNodeChildrenChanged)
+	return nil
+}
+
+// removeAll deletes the node at the given path and all of its children
+func (s *store) removeAll(p string) error {
+	n := s.lookup(p)
+	if n == nil {
+		return client.ErrNoNode
+	}
+	for _, name := range sortedNames(n) {
+		if err := s.removeAll(path.Join(p, name)); err != nil {
+			return err
+		}
+	}
+	return s.remove(p, -1)
+}
+
+// children returns the sorted names of the children at the given path
+func (s *store) children(p string) ([]string, error) {
+	n := s.lookup(p)
+	if n == nil {
+		return []string{}, client.ErrNoNode
+	}
+	return sortedNames(n), nil
+}
+
+// addWatch registers a one-shot watch on the given path
+func (s *store) addWatch(watches map[string][]*watch, p string, session int64) *watch {
+	w := &watch{session: session, ch: make(chan client.Event, 1)}
+	p = path.Clean(p)
+	watches[p] = append(watches[p], w)
+	return w
+}
+
+// cancelWatch removes a watch that has not yet fired
+func (s *store) cancelWatch(w *watch) {
+	s.Lock()
+	defer s.Unlock()
+	for _, watches := range []map[string][]*watch{s.dataW, s.childW} {
+		for p, ws := range watches {
+			for i := range ws {
+				if ws[i] == w {
+					watches[p] = append(ws[:i], ws[i+1:]...)
+					if len(watches[p]) == 0 {
+						delete(watches, p)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// notify queues an event for all of the watches on a path.  Events are not
+// delivered until flush is called, so that a failed transaction does not
+// trigger any watches.
+func (s *store) notify(watches map[string][]*watch, p string, evType client.EventType) {
+	s.pending = append(s.pending, func() {
+		for _, w := range watches[p] {
+			w.ch <- client.Event{Type: evType, Path: p}
+		}
+		delete(watches, p)
+	})
+}
+
+// flush delivers all of the queued events
+func (s *store) flush() {
+	for _, f := range s.pending {
+		f()
+	}
+	s.pending = nil
+}
+
+// discard drops all of the queued events
+func (s *store) discard() {
+	s.pending = nil
+}
+
+// closeSession stops all of the watches owned by the session and removes its
+// ephemeral nodes.
+func (s *store) closeSession(session int64) {
+	s.Lock()
+	defer s.Unlock()
+	for _, watches := range []map[string][]*watch{s.dataW, s.childW} {
+		for p, ws := range watches {
+			var keep []*watch
+			for _, w := range ws {
+				if w.session == session {
+					w.ch <- client.Event{Type: client.EventNotWatching, Path: p, Err: client.ErrClosing}
+				} else {
+					keep = append(keep, w)
+				}
+			}
+			if len(keep) > 0 {
+				watches[p] = keep
+			} else {
+				delete(watches, p)
+			}
+		}
+	}
+	for _, p := range s.ephemerals("/", s.root, session) {
+		s.remove(p, -1)
+	}
+	s.flush()
+}
+
+// ephemerals returns the paths of all of the nodes owned by the session
+func (s *store) ephemerals(p string, n *node, session int64) []string {
+	var paths []string
+	for name, child := range n.children {
+		cp := path.Join(p, name)
+		if child.owner == session {
+			paths = append(paths, cp)
+		} else {
+			paths = append(paths, s.ephemerals(cp, child, session)...)
+		}
+	}
+	return paths
+}
+
+func sortedNames(n *node) []string {
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func split(p string) []string {
+	var names []string
+	for _, name := range strings.Split(path.Clean(p), "/") {
+		if name != "" && name != "." {
+			names = append(names, name)
+		}
+	}
+	return names
+}
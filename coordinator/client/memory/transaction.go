@@ -0,0 +1,146 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"encoding/json"
+	"path"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+const (
+	multiCreate int = iota
+	multiSet
+	multiDelete
+)
+
+type multiReq struct {
+	Type int
+	Path string
+	Node client.Node
+}
+
+// Transaction applies a set of operations atomically.  If any operation
+// fails, none of the changes are applied.
+type Transaction struct {
+	conn *Connection
+	ops  []multiReq
+}
+
+func (t *Transaction) Create(path string, node client.Node) client.Transaction {
+	t.ops = append(t.ops, multiReq{multiCreate, path, node})
+	return t
+}
+
+func (t *Transaction) Set(path string, node client.Node) client.Transaction {
+	t.ops = append(t.ops, multiReq{multiSet, path, node})
+	return t
+}
+
+func (t *Transaction) Delete(path string) client.Transaction {
+	t.ops = append(t.ops, multiReq{multiDelete, path, nil})
+	return t
+}
+
+func (t *Transaction) Commit() error {
+	data := make([][]byte, len(t.ops))
+	versions := make([]int32, len(t.ops))
+	for i, op := range t.ops {
+		if op.Type == multiDelete {
+			continue
+		}
+		bytes, err := json.Marshal(op.Node)
+		if err != nil {
+			plog.WithError(err).WithField("path", op.Path).Error("Could not serialize node at path")
+			return client.ErrSerialization
+		}
+		data[i] = bytes
+		if op.Type == multiSet {
+			if versions[i], err = getVersion(op.Node); err != nil {
+				plog.WithError(err).WithField("path", op.Path).Error("Could not parse version of node at path")
+				return err
+			}
+		}
+	}
+
+	t.conn.RLock()
+	defer t.conn.RUnlock()
+	if err := t.conn.isClosed(); err != nil {
+		return err
+	}
+	s := t.conn.store
+	s.Lock()
+	defer s.Unlock()
+
+	var undo []func()
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+		s.discard()
+	}
+	for i, op := range t.ops {
+		pth := path.Join(t.conn.basePath, op.Path)
+		dir, name := path.Split(path.Clean(pth))
+		parent := s.lookup(dir)
+		switch op.Type {
+		case multiCreate:
+			var cseq int32
+			if parent != nil {
+				cseq = parent.cseq
+			}
+			if _, err := s.create(pth, data[i], 0, false); err != nil {
+				rollback()
+				return err
+			}
+			undo = append(undo, func() {
+				delete(parent.children, name)
+				parent.cseq = cseq
+			})
+		case multiSet:
+			n := s.lookup(pth)
+			var (
+				oldData    []byte
+				oldVersion int32
+			)
+			if n != nil {
+				oldData, oldVersion = n.data, n.version
+			}
+			if err := s.set(pth, data[i], versions[i]); err != nil {
+				rollback()
+				return err
+			}
+			undo = append(undo, func() {
+				n.data, n.version = oldData, oldVersion
+			})
+		case multiDelete:
+			n := s.lookup(pth)
+			if err := s.remove(pth, -1); err != nil {
+				rollback()
+				return err
+			}
+			undo = append(undo, func() {
+				parent.children[name] = n
+			})
+		}
+	}
+	for _, op := range t.ops {
+		if op.Type == multiCreate {
+			op.Node.SetVersion(&Stat{})
+		}
+	}
+	s.flush()
+	return nil
+}
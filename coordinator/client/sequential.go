@@ -0,0 +1,249 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetLowestSequence returns the lowest sequenced value ephemeral node from the
+// list.
+func GetLowestSequence(ch []string) (string, error) {
+	var (
+		lowestSequence uint64 = math.MaxUint64
+		leader                = ""
+	)
+
+	for _, p := range ch {
+		s, err := ParseSequence(p)
+		if err != nil {
+			return "", err
+		}
+		if s < lowestSequence {
+			lowestSequence, leader = s, p
+		}
+	}
+	return leader, nil
+}
+
+// ParseSequence returns the sequence number appended to a sequential node
+// name.
+func ParseSequence(p string) (uint64, error) {
+	parts := strings.Split(p, "-")
+	return strconv.ParseUint(parts[len(parts)-1], 10, 64)
+}
+
+// sequentialNode is a placeholder node written by the sequential lock recipe.
+type sequentialNode struct {
+	version interface{}
+}
+
+func (n *sequentialNode) Version() interface{}       { return n.version }
+func (n *sequentialNode) SetVersion(def interface{}) { n.version = def }
+
+// sequentialChildren returns the names of the children of p that start with
+// the given prefix sorted by their sequence number.
+func sequentialChildren(conn Connection, p, prefix string) ([]string, []uint64, error) {
+	children, err := conn.Children(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	seqs := make(map[string]uint64)
+	names := []string{}
+	for _, child := range children {
+		if !strings.Contains(child, prefix) {
+			continue
+		}
+		seq, err := ParseSequence(child)
+		if err != nil {
+			continue
+		}
+		seqs[child] = seq
+		names = append(names, child)
+	}
+	sort.Sort(bySequence{names, seqs})
+	ordered := make([]uint64, len(names))
+	for i, name := range names {
+		ordered[i] = seqs[name]
+	}
+	return names, ordered, nil
+}
+
+type bySequence struct {
+	names []string
+	seqs  map[string]uint64
+}
+
+func (s bySequence) Len() int           { return len(s.names) }
+func (s bySequence) Swap(i, j int)      { s.names[i], s.names[j] = s.names[j], s.names[i] }
+func (s bySequence) Less(i, j int) bool { return s.seqs[s.names[i]] < s.seqs[s.names[j]] }
+
+// SequentialLock implements the ZooKeeper lock recipe on top of any
+// connection that supports ephemeral sequential nodes.
+type SequentialLock struct {
+	conn     Connection
+	path     string
+	lockPath string
+}
+
+// NewSequentialLock returns a lock on the given path.
+func NewSequentialLock(conn Connection, p string) *SequentialLock {
+	return &SequentialLock{conn: conn, path: p}
+}
+
+// Lock blocks until the lock is acquired.
+func (l *SequentialLock) Lock() error {
+	if l.lockPath != "" {
+		return ErrDeadlock
+	}
+	lockPath, err := l.conn.CreateEphemeral(path.Join(l.path, "lock-"), &sequentialNode{})
+	if err != nil {
+		return err
+	}
+	name := path.Base(lockPath)
+	for {
+		children, _, err := sequentialChildren(l.conn, l.path, "lock-")
+		if err != nil {
+			l.conn.Delete(path.Join(l.path, name))
+			return err
+		}
+		prev := ""
+		found := false
+		for _, child := range children {
+			if child == name {
+				found = true
+				break
+			}
+			prev = child
+		}
+		if !found {
+			return ErrNoNode
+		}
+		if prev == "" {
+			l.lockPath = path.Join(l.path, name)
+			return nil
+		}
+
+		// wait for the node ahead of us to go away
+		done := make(chan struct{})
+		exists, ev, err := l.conn.ExistsW(path.Join(l.path, prev), done)
+		if err != nil {
+			close(done)
+			l.conn.Delete(path.Join(l.path, name))
+			return err
+		}
+		if exists {
+			<-ev
+		}
+		close(done)
+	}
+}
+
+// Unlock releases the lock.
+func (l *SequentialLock) Unlock() error {
+	if l.lockPath == "" {
+		return ErrNotLocked
+	}
+	if err := l.conn.Delete(l.lockPath); err != nil && err != ErrNoNode {
+		return err
+	}
+	l.lockPath = ""
+	return nil
+}
+
+// SequentialLeader implements the ZooKeeper leader election recipe on top of
+// any connection that supports ephemeral sequential nodes.
+type SequentialLeader struct {
+	conn     Connection
+	path     string
+	lockPath string
+}
+
+// NewSequentialLeader returns a leader election on the given path.
+func NewSequentialLeader(conn Connection, p string) *SequentialLeader {
+	return &SequentialLeader{conn: conn, path: p}
+}
+
+// Current returns the currect elected leader and deserializes it in to node.
+// It will return ErrNoLeaderFound if no leader has been elected.
+func (l *SequentialLeader) Current(node Node) error {
+	children, _, err := sequentialChildren(l.conn, l.path, "leader-")
+	if err != nil {
+		return err
+	}
+	if len(children) == 0 {
+		return ErrNoLeaderFound
+	}
+	return l.conn.Get(path.Join(l.path, children[0]), node)
+}
+
+// TakeLead blocks until the leader role is acquired and returns a channel
+// that fires when the leader node changes.
+func (l *SequentialLeader) TakeLead(node Node, done <-chan struct{}) (<-chan Event, error) {
+	if l.lockPath != "" {
+		return nil, ErrDeadlock
+	}
+	lockPath, err := l.conn.CreateEphemeral(path.Join(l.path, "leader-"), node)
+	if err != nil {
+		return nil, err
+	}
+	name := path.Base(lockPath)
+	lockSeq, err := ParseSequence(name)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		children, seqs, err := sequentialChildren(l.conn, l.path, "leader-")
+		if err != nil {
+			return nil, err
+		}
+		if len(children) == 0 || seqs[0] > lockSeq {
+			return nil, ErrNoNode
+		}
+		leader := path.Join(l.path, children[0])
+		exists, ev, err := l.conn.ExistsW(leader, done)
+		if err != nil {
+			return nil, err
+		} else if !exists {
+			continue
+		}
+		if children[0] == name {
+			l.lockPath = leader
+			return ev, nil
+		}
+		select {
+		case <-ev:
+		case <-done:
+			l.conn.Delete(path.Join(l.path, name))
+			return nil, ErrNoLeaderFound
+		}
+	}
+}
+
+// ReleaseLead release the current leader role. It will return ErrNotLocked if
+// the current object is not locked.
+func (l *SequentialLeader) ReleaseLead() error {
+	if l.lockPath == "" {
+		return ErrNotLocked
+	}
+	if err := l.conn.Delete(l.lockPath); err != nil && err != ErrNoNode {
+		return err
+	}
+	l.lockPath = ""
+	return nil
+}
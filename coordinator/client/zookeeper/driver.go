@@ -15,15 +15,22 @@ package zookeeper
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	zklib "github.com/control-center/go-zookeeper/zk"
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/metrics/prometheus"
 )
 
 var (
 	plog = logging.PackageLogger() // the standard package logger
+
+	// connectionStates counts this process's zookeeper connections by the
+	// state of their session
+	connectionStates = prometheus.NewGaugeVec("serviced_zookeeper_connections",
+		"Zookeeper connections by session state", "state")
 )
 
 // Driver implements a Zookeeper based client.Driver interface
@@ -34,6 +41,13 @@ var _ client.Driver = &Driver{}
 
 func init() {
 	client.RegisterDriver("zookeeper", &Driver{})
+	prometheus.Register(connectionStates)
+}
+
+// stateLabel names a session state for the connection state metric, e.g.
+// "hassession" or "disconnected"
+func stateLabel(state zklib.State) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "State"))
 }
 
 // DSN is a Zookeeper specific struct used for connections. It can be
@@ -118,15 +132,23 @@ func (driver *Driver) GetConnection(dsn, basePath string) (client.Connection, er
 			}
 		}
 	}
+	state := stateLabel(zklib.StateHasSession)
+	connectionStates.Add(1, state)
 	go func() {
 		for {
 			select {
 			case e, ok := <-event:
 				if !ok {
 					plog.WithField("event", e).Debug("zk event channel closed")
+					connectionStates.Add(-1, state)
 					return
 				} else {
 					plog.WithField("event", e).Debug("zk state change event received")
+					if e.Type == zklib.EventSession {
+						connectionStates.Add(-1, state)
+						state = stateLabel(e.State)
+						connectionStates.Add(1, state)
+					}
 				}
 			}
 		}
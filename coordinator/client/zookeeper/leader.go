@@ -15,7 +15,6 @@ package zookeeper
 
 import (
 	"encoding/json"
-	"errors"
 	"math"
 	"path"
 	"strconv"
@@ -27,14 +26,14 @@ import (
 
 var (
 	// ErrDeadlock is returned when a lock is aquired twice on the same object.
-	ErrDeadlock = errors.New("zk: trying to acquire a lock twice")
+	ErrDeadlock = client.ErrDeadlock
 
 	// ErrNotLocked is returned when a caller attempts to release a lock that
 	// has not been aquired
-	ErrNotLocked = errors.New("zk: not locked")
+	ErrNotLocked = client.ErrNotLocked
 
 	// ErrNoLeaderFound is returned when a leader has not been elected
-	ErrNoLeaderFound = errors.New("zk: no leader found")
+	ErrNoLeaderFound = client.ErrNoLeaderFound
 )
 
 // Leader is an object to facilitate creating an election in zookeeper.
@@ -207,19 +206,5 @@ func parseSeq(path string) (uint64, error) {
 // GetLowestSequence returns the lowest sequenced value ephemeral node from the
 // list.
 func GetLowestSequence(ch []string) (string, error) {
-	var (
-		lowestSequence uint64 = math.MaxUint64
-		leader                = ""
-	)
-
-	for _, p := range ch {
-		s, err := parseSeq(p)
-		if err != nil {
-			return "", err
-		}
-		if s < lowestSequence {
-			lowestSequence, leader = s, p
-		}
-	}
-	return leader, nil
+	return client.GetLowestSequence(ch)
 }
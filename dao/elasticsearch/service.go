@@ -165,31 +165,31 @@ func (this *ControlPlaneDao) FindChildService(request dao.FindChildRequest, serv
 
 // start the provided service
 func (this *ControlPlaneDao) StartService(request dao.ScheduleServiceRequest, affected *int) (err error) {
-	*affected, err = this.facade.StartService(datastore.Get(), request)
+	*affected, err = this.facade.StartService(datastore.WithTrace(datastore.Get(), request.Trace), request)
 	return err
 }
 
 // restart the provided service
 func (this *ControlPlaneDao) RestartService(request dao.ScheduleServiceRequest, affected *int) (err error) {
-	*affected, err = this.facade.RestartService(datastore.Get(), request)
+	*affected, err = this.facade.RestartService(datastore.WithTrace(datastore.Get(), request.Trace), request)
 	return err
 }
 
 // rebalance the provided service
 func (this *ControlPlaneDao) RebalanceService(request dao.ScheduleServiceRequest, affected *int) (err error) {
-	*affected, err = this.facade.RebalanceService(datastore.Get(), request)
+	*affected, err = this.facade.RebalanceService(datastore.WithTrace(datastore.Get(), request.Trace), request)
 	return err
 }
 
 // stop the provided service
 func (this *ControlPlaneDao) StopService(request dao.ScheduleServiceRequest, affected *int) (err error) {
-	*affected, err = this.facade.StopService(datastore.Get(), request)
+	*affected, err = this.facade.StopService(datastore.WithTrace(datastore.Get(), request.Trace), request)
 	return err
 }
 
 // pause the provided service
 func (this *ControlPlaneDao) PauseService(request dao.ScheduleServiceRequest, affected *int) (err error) {
-	*affected, err = this.facade.PauseService(datastore.Get(), request)
+	*affected, err = this.facade.PauseService(datastore.WithTrace(datastore.Get(), request.Trace), request)
 	return err
 }
 
@@ -31,6 +31,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/domain/logfilter"
+	"github.com/control-center/serviced/trace"
 )
 
 // A generic ControlPlane error
@@ -72,9 +73,21 @@ type ServiceStateRequest struct {
 }
 
 type ScheduleServiceRequest struct {
-	ServiceIDs  []string
-	AutoLaunch  bool
-	Synchronous bool
+	ServiceIDs     []string
+	AutoLaunch     bool
+	Synchronous    bool
+	RollingRestart *service.RollingRestart // Options for restarts; nil uses the defaults
+	Trace          trace.SpanContext `json:"-"` // Set from the RPC trace header
+}
+
+// TraceContext implements trace.Carrier
+func (r *ScheduleServiceRequest) TraceContext() trace.SpanContext {
+	return r.Trace
+}
+
+// SetTraceContext implements trace.Carrier
+func (r *ScheduleServiceRequest) SetTraceContext(sc trace.SpanContext) {
+	r.Trace = sc
 }
 
 type WaitServiceRequest struct {
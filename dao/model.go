@@ -20,6 +20,7 @@ import (
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/volume"
 )
 
 type NullRequest struct{}
@@ -114,6 +115,40 @@ func (s *SnapshotInfo) Equals(s2 *SnapshotInfo) bool {
 		s.Invalid == s2.Invalid
 }
 
+// ServiceChangeType describes how a service differs between two states of an
+// application
+type ServiceChangeType string
+
+const (
+	// ServiceAdded is a service that only exists in the newer state
+	ServiceAdded ServiceChangeType = "added"
+	// ServiceRemoved is a service that only exists in the older state
+	ServiceRemoved ServiceChangeType = "removed"
+	// ServiceModified is a service whose definition differs between the two
+	// states
+	ServiceModified ServiceChangeType = "modified"
+)
+
+// ServiceChange is a service whose definition differs between two states of
+// an application.  Fields lists the top-level fields of a modified service
+// that differ.
+type ServiceChange struct {
+	ServiceID string
+	Path      string
+	Change    ServiceChangeType
+	Fields    []string
+}
+
+// SnapshotDiff describes the changes going from a snapshot to another
+// snapshot of the same application, or to its current state if ToSnapshotID
+// is empty.
+type SnapshotDiff struct {
+	SnapshotID   string
+	ToSnapshotID string
+	Files        []volume.FileChange
+	Services     []ServiceChange
+}
+
 // ServiceInstanceRequest requests information about a service instance given
 // the service ID and instance ID.
 type ServiceInstanceRequest struct {
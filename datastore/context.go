@@ -15,6 +15,7 @@ package datastore
 
 import (
 	"github.com/control-center/serviced/metrics"
+	"github.com/control-center/serviced/trace"
 )
 
 // Context is the context of the application or request being made
@@ -84,3 +85,21 @@ func (c *context) SetUser(user string) {
 func (c *context) User() string {
 	return c.user
 }
+
+// WithTrace returns a context for a request that is part of a trace
+func WithTrace(ctx Context, sc trace.SpanContext) Context {
+	return &tracedContext{Context: ctx, trace: sc}
+}
+
+// TraceOf returns the trace of the request the context was made for, if any
+func TraceOf(ctx Context) trace.SpanContext {
+	if c, ok := ctx.(*tracedContext); ok {
+		return c.trace
+	}
+	return trace.SpanContext{}
+}
+
+type tracedContext struct {
+	Context
+	trace trace.SpanContext
+}
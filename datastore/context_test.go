@@ -17,6 +17,8 @@ package datastore
 
 import (
 	"testing"
+
+	"github.com/control-center/serviced/trace"
 )
 
 type testDriver struct{}
@@ -53,3 +55,19 @@ func TestContext(t *testing.T) {
 		t.Error("Expected connection, got nil")
 	}
 }
+
+func TestWithTrace(t *testing.T) {
+	ctx := newCtx(&testDriver{})
+	if TraceOf(ctx).IsValid() {
+		t.Error("Expected no trace on a new context")
+	}
+
+	sc := trace.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	traced := WithTrace(ctx, sc)
+	if TraceOf(traced) != sc {
+		t.Errorf("Expected trace %+v, got %+v", sc, TraceOf(traced))
+	}
+	if traced.Metrics() != ctx.Metrics() {
+		t.Error("Expected traced context to share metrics with its parent")
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"github.com/control-center/serviced/dfs/docker"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/zenoss/glog"
+)
+
+// Clone initializes a new application volume from the data and images of an
+// existing snapshot.
+func (dfs *DistributedFilesystem) Clone(snapshotID, tenantID string) error {
+	vol, info, err := dfs.getSnapshotVolumeAndInfo(snapshotID)
+	if err != nil {
+		return err
+	}
+	// tag the images of the snapshot as the latest images of the new tenant
+	r, err := vol.ReadMetadata(info.Label, ImagesMetadataFile)
+	if err != nil {
+		glog.Errorf("Could not receive images metadata from snapshot %s: %s", snapshotID, err)
+		return err
+	}
+	var images []string
+	if err := importJSON(r, &images); err != nil {
+		glog.Errorf("Could not interpret images metadata file from snapshot %s: %s", snapshotID, err)
+		return err
+	}
+	for _, image := range images {
+		rImage, err := dfs.index.FindImage(image)
+		if err != nil {
+			glog.Errorf("Could not find image %s from snapshot %s: %s", image, snapshotID, err)
+			return err
+		}
+		cImage := &registry.Image{
+			Library: tenantID,
+			Repo:    rImage.Repo,
+			Tag:     docker.Latest,
+		}
+		if err := dfs.index.PushImage(cImage.String(), rImage.UUID, rImage.Hash); err != nil {
+			glog.Errorf("Could not add image %s from snapshot %s for tenant %s in the registry: %s", image, snapshotID, tenantID, err)
+			return err
+		}
+	}
+	cvol, err := dfs.disk.Clone(snapshotID, tenantID)
+	if err != nil {
+		glog.Errorf("Could not clone volume for tenant %s from snapshot %s: %s", tenantID, snapshotID, err)
+		return err
+	}
+	glog.V(1).Infof("Volume cloned for %s at %s", tenantID, cvol.Path())
+	if err := dfs.export(cvol.Path()); err != nil {
+		glog.Errorf("Could not export volume at %s: %s", cvol.Path(), err)
+		if err := dfs.disk.Remove(tenantID); err != nil {
+			glog.Errorf("Could not remove volume for tenant %s: %s", tenantID, err)
+		}
+		return err
+	}
+	return nil
+}
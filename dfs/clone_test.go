@@ -0,0 +1,124 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/volume"
+	volumemock "github.com/control-center/serviced/volume/mocks"
+	. "gopkg.in/check.v1"
+)
+
+func (s *DFSTestSuite) setupCloneSnapshot(c *C) *volumemock.Volume {
+	vinfo := &volume.SnapshotInfo{
+		Name:     "BASE_LABEL",
+		TenantID: "BASE",
+		Label:    "LABEL",
+		Created:  time.Now().UTC(),
+	}
+	vimagesbuf := bytes.NewBufferString("")
+	err := json.NewEncoder(vimagesbuf).Encode([]string{"BASE/repo:LABEL"})
+	c.Assert(err, IsNil)
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(vinfo, nil)
+	vol.On("ReadMetadata", "LABEL", ImagesMetadataFile).Return(&NopCloser{vimagesbuf}, nil)
+	return vol
+}
+
+func (s *DFSTestSuite) TestClone_NoSnapshot(c *C) {
+	s.disk.On("GetTenant", "BASE_LABEL").Return(&volumemock.Volume{}, volume.ErrVolumeNotExists)
+	err := s.dfs.Clone("BASE_LABEL", "CLONE")
+	c.Assert(err, Equals, volume.ErrVolumeNotExists)
+}
+
+func (s *DFSTestSuite) TestClone_ImageNoPush(c *C) {
+	s.setupCloneSnapshot(c)
+	rImage := &registry.Image{
+		Library: "BASE",
+		Repo:    "repo",
+		Tag:     "LABEL",
+		UUID:    "testuuid",
+		Hash:    "hashvalue",
+	}
+	s.index.On("FindImage", "BASE/repo:LABEL").Return(rImage, nil)
+	s.index.On("PushImage", "CLONE/repo:latest", "testuuid", "hashvalue").Return(ErrTestNoPush)
+	err := s.dfs.Clone("BASE_LABEL", "CLONE")
+	c.Assert(err, Equals, ErrTestNoPush)
+	s.disk.AssertNotCalled(c, "Clone", "BASE_LABEL", "CLONE")
+}
+
+func (s *DFSTestSuite) TestClone_NoVolume(c *C) {
+	s.setupCloneSnapshot(c)
+	rImage := &registry.Image{
+		Library: "BASE",
+		Repo:    "repo",
+		Tag:     "LABEL",
+		UUID:    "testuuid",
+		Hash:    "hashvalue",
+	}
+	s.index.On("FindImage", "BASE/repo:LABEL").Return(rImage, nil)
+	s.index.On("PushImage", "CLONE/repo:latest", "testuuid", "hashvalue").Return(nil)
+	s.disk.On("Clone", "BASE_LABEL", "CLONE").Return(&volumemock.Volume{}, ErrTestVolumeNotCreated)
+	err := s.dfs.Clone("BASE_LABEL", "CLONE")
+	c.Assert(err, Equals, ErrTestVolumeNotCreated)
+}
+
+func (s *DFSTestSuite) TestClone_NoExport(c *C) {
+	s.setupCloneSnapshot(c)
+	rImage := &registry.Image{
+		Library: "BASE",
+		Repo:    "repo",
+		Tag:     "LABEL",
+		UUID:    "testuuid",
+		Hash:    "hashvalue",
+	}
+	s.index.On("FindImage", "BASE/repo:LABEL").Return(rImage, nil)
+	s.index.On("PushImage", "CLONE/repo:latest", "testuuid", "hashvalue").Return(nil)
+	cvol := &volumemock.Volume{}
+	cvol.On("Path").Return("/path/to/CLONE")
+	s.disk.On("Clone", "BASE_LABEL", "CLONE").Return(cvol, nil)
+	s.net.On("AddVolume", "/path/to/CLONE").Return(ErrTestShareNotAdded)
+	s.disk.On("Remove", "CLONE").Return(nil)
+	err := s.dfs.Clone("BASE_LABEL", "CLONE")
+	c.Assert(err, Equals, ErrTestShareNotAdded)
+	s.disk.AssertCalled(c, "Remove", "CLONE")
+}
+
+func (s *DFSTestSuite) TestClone_Success(c *C) {
+	s.setupCloneSnapshot(c)
+	rImage := &registry.Image{
+		Library: "BASE",
+		Repo:    "repo",
+		Tag:     "LABEL",
+		UUID:    "testuuid",
+		Hash:    "hashvalue",
+	}
+	s.index.On("FindImage", "BASE/repo:LABEL").Return(rImage, nil)
+	s.index.On("PushImage", "CLONE/repo:latest", "testuuid", "hashvalue").Return(nil)
+	cvol := &volumemock.Volume{}
+	cvol.On("Path").Return("/path/to/CLONE")
+	s.disk.On("Clone", "BASE_LABEL", "CLONE").Return(cvol, nil)
+	s.net.On("AddVolume", "/path/to/CLONE").Return(nil)
+	s.net.On("Sync").Return(nil)
+	err := s.dfs.Clone("BASE_LABEL", "CLONE")
+	c.Assert(err, IsNil)
+	s.disk.AssertNotCalled(c, "Remove", "CLONE")
+}
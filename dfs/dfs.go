@@ -49,6 +49,11 @@ type DFS interface {
 	Snapshot(info SnapshotInfo, SnapshotSpacePercent int) (string, error)
 	// Rollback reverts application to a specific snapshot
 	Rollback(snapshotID string) error
+	// Clone creates a new application from a specific snapshot
+	Clone(snapshotID, tenantID string) error
+	// Diff compares the data of a snapshot with another snapshot or the
+	// current state of its application
+	Diff(snapshotID, toSnapshotID string) ([]volume.FileChange, error)
 	// Delete deletes an application's snapshot
 	Delete(snapshotID string) error
 	// List lists snapshots for a particular application
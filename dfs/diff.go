@@ -0,0 +1,53 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/volume"
+	"github.com/zenoss/glog"
+)
+
+var (
+	ErrDiffTenantMismatch = errors.New("snapshots belong to different applications")
+)
+
+// Diff returns the files that differ going from one snapshot of an
+// application to another, or to the current state of the application if
+// toSnapshotID is empty.
+func (dfs *DistributedFilesystem) Diff(snapshotID, toSnapshotID string) ([]volume.FileChange, error) {
+	vol, info, err := dfs.getSnapshotVolumeAndInfo(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	var toLabel string
+	if toSnapshotID != "" {
+		_, toInfo, err := dfs.getSnapshotVolumeAndInfo(toSnapshotID)
+		if err != nil {
+			return nil, err
+		}
+		if toInfo.TenantID != info.TenantID {
+			glog.Errorf("Could not compare snapshot %s of tenant %s with snapshot %s of tenant %s", snapshotID, info.TenantID, toSnapshotID, toInfo.TenantID)
+			return nil, ErrDiffTenantMismatch
+		}
+		toLabel = toInfo.Label
+	}
+	changes, err := vol.Diff(info.Label, toLabel)
+	if err != nil {
+		glog.Errorf("Could not compare the data of snapshot %s for tenant %s: %s", snapshotID, info.TenantID, err)
+		return nil, err
+	}
+	return changes, nil
+}
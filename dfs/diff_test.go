@@ -0,0 +1,69 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/volume"
+	volumemock "github.com/control-center/serviced/volume/mocks"
+	. "gopkg.in/check.v1"
+)
+
+func (s *DFSTestSuite) TestDiff_NoSnapshot(c *C) {
+	s.disk.On("GetTenant", "BASE_LABEL").Return(&volumemock.Volume{}, volume.ErrVolumeNotExists)
+	_, err := s.dfs.Diff("BASE_LABEL", "")
+	c.Assert(err, Equals, volume.ErrVolumeNotExists)
+}
+
+func (s *DFSTestSuite) TestDiff_TenantMismatch(c *C) {
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(&volume.SnapshotInfo{Name: "BASE_LABEL", TenantID: "BASE", Label: "LABEL", Created: time.Now().UTC()}, nil)
+	vol2 := s.getVolumeFromSnapshot("BASE2_LABEL2", "BASE2")
+	vol2.On("SnapshotInfo", "BASE2_LABEL2").Return(&volume.SnapshotInfo{Name: "BASE2_LABEL2", TenantID: "BASE2", Label: "LABEL2", Created: time.Now().UTC()}, nil)
+	_, err := s.dfs.Diff("BASE_LABEL", "BASE2_LABEL2")
+	c.Assert(err, Equals, ErrDiffTenantMismatch)
+}
+
+func (s *DFSTestSuite) TestDiff_Snapshots(c *C) {
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(&volume.SnapshotInfo{Name: "BASE_LABEL", TenantID: "BASE", Label: "LABEL", Created: time.Now().UTC()}, nil)
+	s.disk.On("GetTenant", "BASE_LABEL2").Return(vol, nil)
+	vol.On("SnapshotInfo", "BASE_LABEL2").Return(&volume.SnapshotInfo{Name: "BASE_LABEL2", TenantID: "BASE", Label: "LABEL2", Created: time.Now().UTC()}, nil)
+	expected := []volume.FileChange{
+		{Path: "a file", Change: volume.FileModified, Size: 15, OldSize: 9},
+	}
+	vol.On("Diff", "LABEL", "LABEL2").Return(expected, nil)
+	changes, err := s.dfs.Diff("BASE_LABEL", "BASE_LABEL2")
+	c.Assert(err, IsNil)
+	c.Assert(changes, DeepEquals, expected)
+}
+
+func (s *DFSTestSuite) TestDiff_Live(c *C) {
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(&volume.SnapshotInfo{Name: "BASE_LABEL", TenantID: "BASE", Label: "LABEL", Created: time.Now().UTC()}, nil)
+	vol.On("Diff", "LABEL", "").Return(nil, ErrTestGeneric).Once()
+	_, err := s.dfs.Diff("BASE_LABEL", "")
+	c.Assert(err, Equals, ErrTestGeneric)
+	expected := []volume.FileChange{
+		{Path: "differentfile", Change: volume.FileRemoved, OldSize: 9},
+	}
+	vol.On("Diff", "LABEL", "").Return(expected, nil).Once()
+	changes, err := s.dfs.Diff("BASE_LABEL", "")
+	c.Assert(err, IsNil)
+	c.Assert(changes, DeepEquals, expected)
+}
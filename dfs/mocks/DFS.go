@@ -8,6 +8,7 @@ import "time"
 
 import (
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/volume"
 )
 
 type DFS struct {
@@ -134,6 +135,43 @@ func (_m *DFS) Snapshot(info dfs.SnapshotInfo, spaceFactor int) (string, error)
 	return r0, r1
 }
 
+// Clone provides a mock function with given fields: snapshotID, tenantID
+func (_m *DFS) Clone(snapshotID string, tenantID string) error {
+	ret := _m.Called(snapshotID, tenantID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(snapshotID, tenantID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Diff provides a mock function with given fields: snapshotID, toSnapshotID
+func (_m *DFS) Diff(snapshotID string, toSnapshotID string) ([]volume.FileChange, error) {
+	ret := _m.Called(snapshotID, toSnapshotID)
+
+	var r0 []volume.FileChange
+	if rf, ok := ret.Get(0).(func(string, string) []volume.FileChange); ok {
+		r0 = rf(snapshotID, toSnapshotID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]volume.FileChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(snapshotID, toSnapshotID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Rollback provides a mock function with given fields: snapshotID
 func (_m *DFS) Rollback(snapshotID string) error {
 	ret := _m.Called(snapshotID)
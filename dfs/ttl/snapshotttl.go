@@ -41,11 +41,14 @@ type SnapshotTTLInterface interface {
 // SnapshotTTL is the TTL for snapshots
 type SnapshotTTL struct {
 	client SnapshotTTLInterface
+	// exempt reports whether a snapshot of a tenant is purged by some other
+	// means, such as a retention policy, and not by age.
+	exempt func(tenantID string, snapshot dao.SnapshotInfo) bool
 }
 
-// RunSnapshotTTL runs the ttl for snapshots
-func RunSnapshotTTL(client SnapshotTTLInterface, cancel <-chan interface{}, min, max time.Duration) {
-	utils.RunTTL(&SnapshotTTL{client}, cancel, min, max)
+// RunSnapshotTTL runs the ttl for snapshots, skipping snapshots that are exempt
+func RunSnapshotTTL(client SnapshotTTLInterface, exempt func(tenantID string, snapshot dao.SnapshotInfo) bool, cancel <-chan interface{}, min, max time.Duration) {
+	utils.RunTTL(&SnapshotTTL{client: client, exempt: exempt}, cancel, min, max)
 }
 
 // Name identifies the TTL instance
@@ -78,6 +81,13 @@ func (ttl *SnapshotTTL) Purge(age time.Duration) (time.Duration, error) {
 			return 0, err
 		}
 		for _, s := range snapshots {
+			if ttl.exempt != nil && ttl.exempt(tenantID, s) {
+				logger.WithFields(log.Fields{
+					"tenantid":   tenantID,
+					"snapshotid": s.SnapshotID,
+				}).Debug("Skipping snapshot exempt from snapshot ttl")
+				continue
+			}
 			//ignore snapshots that have any tag
 			if len(s.Tags) == 0 {
 				// check the age of the snapshot
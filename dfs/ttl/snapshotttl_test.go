@@ -77,7 +77,7 @@ func (s *SnapshotTTLTestSuite) SetUpTest(c *C) {
 
 func (s *SnapshotTTLTestSuite) TestSnapshotTTL_Purge_ServiceError(c *C) {
 	iface := &TestSnapshotTTLInterface{tenantIDs: nil, snaps: []dao.SnapshotInfo{}}
-	ttl := SnapshotTTL{iface}
+	ttl := SnapshotTTL{client: iface}
 	if _, err := ttl.Purge(100); err == nil {
 		c.Errorf("Expected error!")
 	}
@@ -88,7 +88,7 @@ func (s *SnapshotTTLTestSuite) TestSnapshotTTL_Purge_SnapshotError(c *C) {
 		tenantIDs: []string{"test service id"},
 		snaps: nil,
 	}
-	ttl := &SnapshotTTL{iface}
+	ttl := &SnapshotTTL{client: iface}
 	if _, err := ttl.Purge(100); err == nil {
 		c.Errorf("Expected error!")
 	}
@@ -99,7 +99,7 @@ func (s *SnapshotTTLTestSuite) TestSnapshotTTL_Purge_NoSnapshot(c *C) {
 		tenantIDs: []string{"test service id"},
 		snaps: []dao.SnapshotInfo{},
 	}
-	ttl := &SnapshotTTL{iface}
+	ttl := &SnapshotTTL{client: iface}
 	if age, err := ttl.Purge(100); err != nil {
 		c.Errorf("Unexpected error: %s", err)
 	} else if age != 100 {
@@ -115,7 +115,7 @@ func (s *SnapshotTTLTestSuite) TestSnapshotTTL_Purge_NewTTL(c *C) {
 			{SnapshotID: "snapshottag_" + snapTime.Format(timeFormat), Created: snapTime},
 		},
 	}
-	ttl := &SnapshotTTL{iface}
+	ttl := &SnapshotTTL{client: iface}
 	if age, err := ttl.Purge(time.Minute); err != nil {
 		c.Errorf("Unexpected error: %s", err)
 	} else if age >= time.Minute {
@@ -131,7 +131,7 @@ func (s *SnapshotTTLTestSuite) TestSnapshotTTL_Purge_Delete(c *C) {
 			{SnapshotID: "snapshottag_" + snapTime.Format(timeFormat), Created: snapTime},
 		},
 	}
-	ttl := &SnapshotTTL{iface}
+	ttl := &SnapshotTTL{client: iface}
 	if age, err := ttl.Purge(time.Minute); err != nil {
 		c.Errorf("Unexpected error: %s", err)
 	} else if age != time.Minute {
@@ -162,7 +162,7 @@ func (s *SnapshotTTLTestSuite) TestSnapshotTTL_Purge_DontDeleteTaggedSnap(c *C)
 		tenantIDs: []string{"test service id"},
 		snaps: []dao.SnapshotInfo{snapToPurge, snapToSave},
 	}
-	ttl := &SnapshotTTL{iface}
+	ttl := &SnapshotTTL{client: iface}
 	if age, err := ttl.Purge(time.Minute); err != nil {
 		c.Errorf("Unexpected error: %s", err)
 	} else if age != time.Minute {
@@ -185,3 +185,25 @@ func (s *SnapshotTTLTestSuite) TestSnapshotTTL_Purge_DontDeleteTaggedSnap(c *C)
 		c.Errorf("Tags missing from remaning snapshot")
 	}
 }
+
+func (s *SnapshotTTLTestSuite) TestSnapshotTTL_Purge_Exempt(c *C) {
+	snapTime := time.Now().UTC().Add(-5 * time.Minute)
+	iface := &TestSnapshotTTLInterface{
+		tenantIDs: []string{"test service id"},
+		snaps: []dao.SnapshotInfo{
+			{SnapshotID: "snapshottag_" + snapTime.Format(timeFormat), Created: snapTime, Description: "scheduled"},
+		},
+	}
+	ttl := &SnapshotTTL{client: iface, exempt: func(tenantID string, snapshot dao.SnapshotInfo) bool {
+		return tenantID == "test service id" && snapshot.Description == "scheduled"
+	}}
+	if age, err := ttl.Purge(time.Minute); err != nil {
+		c.Errorf("Unexpected error: %s", err)
+	} else if age != time.Minute {
+		c.Errorf("Expected %d; got %d", time.Minute, age)
+	}
+
+	if len(iface.snaps) != 1 {
+		c.Errorf("Exempt snaps should not have been deleted")
+	}
+}
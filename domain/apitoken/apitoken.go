@@ -0,0 +1,179 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/utils"
+)
+
+// Prefix starts every API token, so that it can be told apart from the other
+// bearer tokens accepted by the REST API.
+const Prefix = "cct_"
+
+// APIToken is a named, revocable credential for automated REST API access.
+// Only a hash of the token's secret is stored.
+type APIToken struct {
+	ID        string    // Identifies the token; the first part of the bearer token
+	Name      string    // Name given to the token by its creator
+	Hash      string    // SHA-256 of the token's secret
+	Scope     Scope     // Limits what the token may be used for
+	CreatedBy string    // User that created the token
+	CreatedAt time.Time // Time the token was created
+	ExpiresAt time.Time // Time after which the token may no longer be used
+	Revoked   bool      // Whether the token has been revoked
+	RevokedAt time.Time // Time the token was revoked
+	datastore.VersionedEntity
+}
+
+// Scope restricts the requests an API token may make.  An empty list allows
+// everything.
+type Scope struct {
+	ReadOnly bool     // Only allow requests that do not make changes
+	Tenants  []string // Tenant IDs whose services may be addressed
+	Verbs    []string // HTTP methods that may be used
+}
+
+// Verbs lists the HTTP methods that may be named in a token's scope
+var Verbs = []string{"GET", "POST", "PUT", "DELETE"}
+
+// AllowsMethod returns true if the scope permits the HTTP method
+func (s Scope) AllowsMethod(method string) bool {
+	method = strings.ToUpper(method)
+	if s.ReadOnly && method != "GET" && method != "HEAD" {
+		return false
+	}
+	if len(s.Verbs) == 0 {
+		return true
+	}
+	if method == "HEAD" {
+		method = "GET"
+	}
+	for _, verb := range s.Verbs {
+		if strings.ToUpper(verb) == method {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsTenant returns true if the scope permits access to the tenant
+func (s Scope) AllowsTenant(tenantID string) bool {
+	return len(s.Tenants) == 0 || utils.StringInSlice(tenantID, s.Tenants)
+}
+
+// String describes the scope in a compact form
+func (s Scope) String() string {
+	parts := []string{}
+	if s.ReadOnly {
+		parts = append(parts, "read-only")
+	}
+	if len(s.Verbs) > 0 {
+		parts = append(parts, "verbs="+strings.Join(s.Verbs, ","))
+	}
+	if len(s.Tenants) > 0 {
+		parts = append(parts, "tenants="+strings.Join(s.Tenants, ","))
+	}
+	if len(parts) == 0 {
+		return "all"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Active returns true if the token has been neither revoked nor expired
+func (t *APIToken) Active(now time.Time) bool {
+	return !t.Revoked && now.Before(t.ExpiresAt)
+}
+
+// Status describes whether the token may be used
+func (t *APIToken) Status(now time.Time) string {
+	if t.Revoked {
+		return "revoked"
+	} else if !now.Before(t.ExpiresAt) {
+		return "expired"
+	}
+	return "active"
+}
+
+// Principal names the token as the user performing an action
+func (t *APIToken) Principal() string {
+	return fmt.Sprintf("token:%s", t.Name)
+}
+
+// Matches returns true if the secret hashes to the token's hash
+func (t *APIToken) Matches(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashSecret(secret)), []byte(t.Hash)) == 1
+}
+
+// GetType return the APIToken's type
+// It returns the type as a string
+func GetType() string {
+	return kind
+}
+
+// GetType returns the APIToken instance's type
+// It returns the type as a string
+func (t *APIToken) GetType() string {
+	return GetType()
+}
+
+// GetID return an APIToken instance's ID
+// It returns the ID as a string
+func (t *APIToken) GetID() string {
+	return t.ID
+}
+
+// Generate returns a new token id, its secret, and the bearer token that
+// combines them
+func Generate() (id, secret, token string, err error) {
+	b := make([]byte, 40)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	id, secret = hex.EncodeToString(b[:8]), hex.EncodeToString(b[8:])
+	return id, secret, Prefix + id + "_" + secret, nil
+}
+
+// Parse splits a bearer token into its id and secret.  It returns false if
+// the value is not an API token.
+func Parse(token string) (id, secret string, ok bool) {
+	if !strings.HasPrefix(token, Prefix) {
+		return "", "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(token, Prefix), "_")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// HashSecret returns the hash of a token's secret that is stored
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsVerb returns true if the value names an HTTP method allowed in a scope
+func IsVerb(verb string) bool {
+	return utils.StringInSlice(strings.ToUpper(verb), Verbs)
+}
+
@@ -0,0 +1,101 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package apitoken
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type apiTokenSuite struct{}
+
+var _ = Suite(&apiTokenSuite{})
+
+func (s *apiTokenSuite) TestGenerateAndParse(c *C) {
+	id, secret, token, err := Generate()
+	c.Assert(err, IsNil)
+	c.Assert(token, Matches, Prefix+"[0-9a-f]+_[0-9a-f]+")
+
+	pid, psecret, ok := Parse(token)
+	c.Assert(ok, Equals, true)
+	c.Assert(pid, Equals, id)
+	c.Assert(psecret, Equals, secret)
+
+	t := &APIToken{Hash: HashSecret(secret)}
+	c.Assert(t.Matches(psecret), Equals, true)
+	c.Assert(t.Matches("wrong"), Equals, false)
+}
+
+func (s *apiTokenSuite) TestParse_NotAnAPIToken(c *C) {
+	for _, value := range []string{"", "eyJhbGciOi.x.y", Prefix, Prefix + "id", Prefix + "_secret", Prefix + "a_b_c"} {
+		_, _, ok := Parse(value)
+		c.Check(ok, Equals, false, Commentf("value %q", value))
+	}
+}
+
+func (s *apiTokenSuite) TestActive(c *C) {
+	now := time.Now()
+	t := &APIToken{ExpiresAt: now.Add(time.Minute)}
+	c.Assert(t.Active(now), Equals, true)
+	c.Assert(t.Status(now), Equals, "active")
+	c.Assert(t.Active(now.Add(time.Minute)), Equals, false)
+	c.Assert(t.Status(now.Add(time.Minute)), Equals, "expired")
+	t.Revoked = true
+	c.Assert(t.Active(now), Equals, false)
+	c.Assert(t.Status(now), Equals, "revoked")
+}
+
+func (s *apiTokenSuite) TestScope_AllowsMethod(c *C) {
+	c.Assert(Scope{}.AllowsMethod("DELETE"), Equals, true)
+
+	readOnly := Scope{ReadOnly: true}
+	c.Assert(readOnly.AllowsMethod("GET"), Equals, true)
+	c.Assert(readOnly.AllowsMethod("HEAD"), Equals, true)
+	c.Assert(readOnly.AllowsMethod("PUT"), Equals, false)
+
+	verbs := Scope{Verbs: []string{"get", "POST"}}
+	c.Assert(verbs.AllowsMethod("GET"), Equals, true)
+	c.Assert(verbs.AllowsMethod("head"), Equals, true)
+	c.Assert(verbs.AllowsMethod("POST"), Equals, true)
+	c.Assert(verbs.AllowsMethod("DELETE"), Equals, false)
+}
+
+func (s *apiTokenSuite) TestScope_AllowsTenant(c *C) {
+	c.Assert(Scope{}.AllowsTenant("t1"), Equals, true)
+	scope := Scope{Tenants: []string{"t1"}}
+	c.Assert(scope.AllowsTenant("t1"), Equals, true)
+	c.Assert(scope.AllowsTenant("t2"), Equals, false)
+	c.Assert(scope.String(), Equals, "tenants=t1")
+}
+
+func (s *apiTokenSuite) TestValidEntity(c *C) {
+	t := &APIToken{}
+	err := t.ValidEntity()
+	c.Assert(err, ErrorMatches, "(?s).*APIToken.Name.*")
+	c.Assert(err, ErrorMatches, "(?s).*APIToken.ExpiresAt.*")
+
+	t = &APIToken{ID: "id", Name: "ci", Hash: "hash", ExpiresAt: time.Now(), Scope: Scope{Verbs: []string{"PATCH"}}}
+	c.Assert(t.ValidEntity(), ErrorMatches, `(?s).*invalid verb "PATCH".*`)
+	t.Scope.Verbs = []string{"get"}
+	c.Assert(t.ValidEntity(), IsNil)
+}
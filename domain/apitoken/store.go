@@ -0,0 +1,87 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitoken
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// Store is the database for API tokens
+type Store interface {
+	// Get an APIToken by id. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*APIToken, error)
+
+	// Put adds or updates an APIToken
+	Put(ctx datastore.Context, t *APIToken) error
+
+	// GetAPITokens returns all APITokens
+	GetAPITokens(ctx datastore.Context) ([]*APIToken, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore creates a Store for APITokens
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Get an APIToken by id.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*APIToken, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("APITokenStore.Get"))
+	val := &APIToken{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates an APIToken
+func (s *storeImpl) Put(ctx datastore.Context, val *APIToken) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("APITokenStore.Put"))
+	return s.ds.Put(ctx, Key(val.ID), val)
+}
+
+// GetAPITokens returns all APITokens
+func (s *storeImpl) GetAPITokens(ctx datastore.Context) ([]*APIToken, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("APITokenStore.GetAPITokens"))
+	q := datastore.NewQuery(ctx)
+	search := search.Search("controlplane").Type(kind).Size("50000")
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting and putting APITokens
+func Key(id string) datastore.Key {
+	return datastore.NewKey(kind, strings.TrimSpace(id))
+}
+
+func convert(results datastore.Results) ([]*APIToken, error) {
+	tokens := make([]*APIToken, results.Len())
+	for idx := range tokens {
+		token := APIToken{}
+		if err := results.Get(idx, &token); err != nil {
+			return []*APIToken{}, err
+		}
+		tokens[idx] = &token
+	}
+	return tokens, nil
+}
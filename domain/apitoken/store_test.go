@@ -0,0 +1,77 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package apitoken
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+func (s *S) Test_APITokenCRUD(c *C) {
+	expected := &APIToken{
+		ID:        "0123456789abcdef",
+		Name:      "ci",
+		Hash:      HashSecret("secret"),
+		Scope:     Scope{ReadOnly: true, Tenants: []string{"tenant"}},
+		CreatedBy: "admin",
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: time.Now().UTC().Add(time.Hour),
+	}
+	actual, err := s.store.Get(s.ctx, expected.ID)
+	c.Assert(err, NotNil)
+	c.Assert(actual, IsNil)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	err = s.store.Put(s.ctx, expected)
+	c.Assert(err, IsNil)
+
+	actual, err = s.store.Get(s.ctx, expected.ID)
+	c.Assert(err, IsNil)
+	c.Assert(actual.Name, Equals, expected.Name)
+	c.Assert(actual.Scope, DeepEquals, expected.Scope)
+	c.Assert(actual.Matches("secret"), Equals, true)
+
+	actual.Revoked = true
+	err = s.store.Put(s.ctx, actual)
+	c.Assert(err, IsNil)
+
+	tokens, err := s.store.GetAPITokens(s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(tokens, HasLen, 1)
+	c.Assert(tokens[0].Revoked, Equals, true)
+}
@@ -0,0 +1,41 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitoken
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates APIToken fields
+func (t *APIToken) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("APIToken.ID", t.ID))
+	violations.Add(validation.NotEmpty("APIToken.Name", t.Name))
+	violations.Add(validation.NotEmpty("APIToken.Hash", t.Hash))
+	if t.ExpiresAt.IsZero() {
+		violations.Add(validation.NewViolation("APIToken.ExpiresAt must be set"))
+	}
+	for _, verb := range t.Scope.Verbs {
+		if !IsVerb(verb) {
+			violations.Add(validation.NewViolation(fmt.Sprintf("invalid verb %q: must be one of %v", verb, Verbs)))
+		}
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host
+
+// Possible outcomes for a service instance when its host is drained
+const (
+	DrainMigrated = "migrated"
+	DrainStopped  = "stopped"
+	DrainSkipped  = "skipped"
+	DrainFailed   = "failed"
+)
+
+// DrainInstance describes what happened to a service instance that was
+// running on a host when the host was drained.
+type DrainInstance struct {
+	ServiceID   string
+	ServiceName string
+	InstanceID  int
+	StartLevel  uint
+	HostID      string // The host where the instance is now running
+	Status      string
+	Message     string
+}
+
+// DrainReport is the result of draining a host for maintenance.
+type DrainReport struct {
+	HostID     string
+	VirtualIPs []string // Virtual IPs that were moved off the host
+	Instances  []DrainInstance
+}
+
+// Drained returns true if no instances were left behind on the host.
+func (r *DrainReport) Drained() bool {
+	for _, inst := range r.Instances {
+		if inst.Status == DrainSkipped || inst.Status == DrainFailed {
+			return false
+		}
+	}
+	return true
+}
@@ -75,7 +75,8 @@ type Host struct {
 	}
 	MonitoringProfile domain.MonitorProfile
 	datastore.VersionedEntity
-	NatIP string
+	NatIP         string
+	Unschedulable bool // The host is drained and will not be assigned new instances or virtual IPs
 }
 
 //ReadHost is a minimal representation of hosts.
@@ -90,6 +91,7 @@ type ReadHost struct {
 	KernelRelease string
 	ServiceD      ReadServiced
 	IPs           []HostIPResource
+	Unschedulable bool
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -169,6 +171,9 @@ func (a *Host) Equals(b *Host) bool {
 	if !a.MonitoringProfile.Equals(&b.MonitoringProfile) {
 		return false
 	}
+	if a.Unschedulable != b.Unschedulable {
+		return false
+	}
 
 	return true
 }
@@ -28,10 +28,11 @@ import (
 var plog = logging.PackageLogger()
 
 type VirtualIP struct {
-	PoolID        string
-	IP            string
-	Netmask       string
-	BindInterface string
+	PoolID         string
+	IP             string
+	Netmask        string
+	BindInterface  string
+	PreferredHosts []string // Hosts to assign the virtual IP to, most preferred first
 }
 
 type Permission uint
@@ -52,6 +53,7 @@ type ResourcePool struct {
 	CoreCapacity      int         // Number of cores available as a sum of all cores on all hosts in the pool
 	MemoryCapacity    uint64      // Amount (bytes) of RAM available as a sum of all memory on all hosts in the pool
 	MemoryCommitment  uint64      // Amount (bytes) of RAM committed to services
+	StrictCPU         bool        // Refuse to schedule instances that would commit more cores than a host has
 	ConnectionTimeout int         // Wait delay on service rescheduling when an outage is reported (milliseconds)
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
@@ -125,6 +127,9 @@ func (a *ResourcePool) Equals(b *ResourcePool) bool {
 	if a.MemoryCommitment != b.MemoryCommitment {
 		return false
 	}
+	if a.StrictCPU != b.StrictCPU {
+		return false
+	}
 	if a.CreatedAt.Unix() != b.CreatedAt.Unix() {
 		return false
 	}
@@ -24,6 +24,7 @@ type ReadPool struct {
 	MemoryCapacity    uint64     // Sum of all RAM available (bytes) on all hosts in the pool
 	MemoryCommitment  uint64     // Sum of RAM committed (bytes) to services in the pool
 	ConnectionTimeout int        // Wait delay on service rescheduling when an outage is reported (milliseconds)
+	StrictCPU         bool       // Whether hosts may not commit more cores than they have
 	CreatedAt         time.Time  // When the pool was created
 	UpdatedAt         time.Time  // When the poool was last updated
 	Permissions       Permission // A bitset of pemissions for this pool's hosts
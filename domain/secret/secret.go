@@ -0,0 +1,63 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// Redacted is displayed in place of a secret's value wherever it is not
+// needed in plain text.
+const Redacted = "********"
+
+// Secret is a named, encrypted value that belongs to a tenant.  Service
+// definitions reference it by name, e.g. {{secret "db_password"}}.
+type Secret struct {
+	TenantID  string    // Tenant that owns the secret
+	Name      string    // Name used to reference the secret
+	Value     string    // Encrypted value of the secret
+	UpdatedAt time.Time // Time the value was last set
+	datastore.VersionedEntity
+}
+
+// Redact returns a copy of the secret without its value
+func (s Secret) Redact() Secret {
+	s.Value = Redacted
+	return s
+}
+
+// GetType return the Secret's type
+// It returns the type as a string
+func GetType() string {
+	return kind
+}
+
+// GetType returns the Secret instance's type
+// It returns the type as a string
+func (s *Secret) GetType() string {
+	return GetType()
+}
+
+// GetID return a Secret instance's ID
+// It returns the ID as a string
+func (s *Secret) GetID() string {
+	return buildID(s.TenantID, s.Name)
+}
+
+func buildID(tenantID, name string) string {
+	return fmt.Sprintf("%s-%s", tenantID, name)
+}
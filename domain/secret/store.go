@@ -0,0 +1,101 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// Store is the database for tenant Secrets
+type Store interface {
+	// Get a Secret by tenant and name. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, tenantID, name string) (*Secret, error)
+
+	// Put adds or updates a Secret
+	Put(ctx datastore.Context, s *Secret) error
+
+	// Delete removes a Secret if it exists
+	Delete(ctx datastore.Context, tenantID, name string) error
+
+	// GetSecrets returns all Secrets of a tenant
+	GetSecrets(ctx datastore.Context, tenantID string) ([]*Secret, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore creates a Store for Secrets
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Get a Secret by tenant and name.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, tenantID, name string) (*Secret, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SecretStore.Get"))
+	val := &Secret{}
+	if err := s.ds.Get(ctx, Key(tenantID, name), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates a Secret
+func (s *storeImpl) Put(ctx datastore.Context, val *Secret) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SecretStore.Put"))
+	return s.ds.Put(ctx, Key(val.TenantID, val.Name), val)
+}
+
+// Delete removes a Secret
+func (s *storeImpl) Delete(ctx datastore.Context, tenantID, name string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SecretStore.Delete"))
+	return s.ds.Delete(ctx, Key(tenantID, name))
+}
+
+// GetSecrets returns all Secrets of a tenant
+func (s *storeImpl) GetSecrets(ctx datastore.Context, tenantID string) ([]*Secret, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SecretStore.GetSecrets"))
+	q := datastore.NewQuery(ctx)
+	search := search.Search("controlplane").Type(kind).Size("50000").Filter(
+		"and",
+		search.Filter().Terms("TenantID", tenantID),
+	)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+//Key creates a Key suitable for getting, putting and deleting Secrets
+func Key(tenantID, name string) datastore.Key {
+	tenantID = strings.TrimSpace(tenantID)
+	name = strings.TrimSpace(name)
+	return datastore.NewKey(kind, buildID(tenantID, name))
+}
+
+func convert(results datastore.Results) ([]*Secret, error) {
+	secrets := make([]*Secret, results.Len())
+	for idx := range secrets {
+		secret := Secret{}
+		if err := results.Get(idx, &secret); err != nil {
+			return []*Secret{}, err
+		}
+		secrets[idx] = &secret
+	}
+	return secrets, nil
+}
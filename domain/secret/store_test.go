@@ -0,0 +1,85 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package secret
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+func (s *S) Test_SecretCRUD(c *C) {
+	expected := &Secret{
+		TenantID: "tenant",
+		Name:     "db_password",
+		Value:    "ciphertext",
+	}
+	actual, err := s.store.Get(s.ctx, expected.TenantID, expected.Name)
+	c.Assert(err, NotNil)
+	c.Assert(actual, IsNil)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	err = s.store.Put(s.ctx, expected)
+	expected.DatabaseVersion++
+	c.Assert(err, IsNil)
+
+	actual, err = s.store.Get(s.ctx, expected.TenantID, expected.Name)
+	c.Assert(err, IsNil)
+	c.Assert(actual.Value, Equals, expected.Value)
+
+	err = s.store.Delete(s.ctx, expected.TenantID, expected.Name)
+	c.Assert(err, IsNil)
+
+	actual, err = s.store.Get(s.ctx, expected.TenantID, expected.Name)
+	c.Assert(err, NotNil)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
+
+func (s *S) Test_GetSecrets(c *C) {
+	for _, secret := range []*Secret{
+		{TenantID: "tenant1", Name: "a", Value: "x"},
+		{TenantID: "tenant1", Name: "b", Value: "y"},
+		{TenantID: "tenant2", Name: "a", Value: "z"},
+	} {
+		c.Assert(s.store.Put(s.ctx, secret), IsNil)
+	}
+
+	secrets, err := s.store.GetSecrets(s.ctx, "tenant1")
+	c.Assert(err, IsNil)
+	c.Assert(secrets, HasLen, 2)
+	for _, secret := range secrets {
+		c.Assert(secret.TenantID, Equals, "tenant1")
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// namePattern matches the names that may be used for a secret
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ValidName checks that a secret name can be referenced from a template
+func ValidName(name string) error {
+	if !namePattern.MatchString(name) {
+		return validation.NewViolation(fmt.Sprintf("invalid secret name %q: only letters, digits, '_', '.' and '-' are allowed", name))
+	}
+	return nil
+}
+
+// ValidEntity validates Secret fields
+func (s *Secret) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Secret.TenantID", s.TenantID))
+	violations.Add(validation.NotEmpty("Secret.Name", s.Name))
+	if s.Name != "" {
+		violations.Add(ValidName(s.Name))
+	}
+	violations.Add(validation.NotEmpty("Secret.Value", s.Value))
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
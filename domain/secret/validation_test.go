@@ -0,0 +1,71 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package secret
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type validationSuite struct{}
+
+var _ = Suite(&validationSuite{})
+
+func (s *validationSuite) TestSecret_Success(c *C) {
+	secret := Secret{
+		TenantID: "tenant",
+		Name:     "db_password",
+		Value:    "ciphertext",
+	}
+	err := secret.ValidEntity()
+	c.Assert(err, IsNil)
+}
+
+func (s *validationSuite) TestSecret_NonBlankFields(c *C) {
+	secret := Secret{}
+	err := secret.ValidEntity()
+	c.Assert(err, NotNil)
+	c.Assert(err, ErrorMatches, "(?s).*Secret.TenantID.*")
+	c.Assert(err, ErrorMatches, "(?s).*Secret.Name.*")
+	c.Assert(err, ErrorMatches, "(?s).*Secret.Value.*")
+}
+
+func (s *validationSuite) TestSecret_InvalidName(c *C) {
+	secret := Secret{
+		TenantID: "tenant",
+		Name:     "db password",
+		Value:    "ciphertext",
+	}
+	err := secret.ValidEntity()
+	c.Assert(err, ErrorMatches, `(?s).*invalid secret name "db password".*`)
+}
+
+func (s *validationSuite) TestSecret_Redact(c *C) {
+	secret := Secret{
+		TenantID: "tenant",
+		Name:     "db_password",
+		Value:    "ciphertext",
+	}
+	redacted := secret.Redact()
+	c.Assert(redacted.Value, Equals, Redacted)
+	c.Assert(secret.Value, Equals, "ciphertext")
+}
@@ -75,3 +75,22 @@ type Config struct {
 	ID       string
 	Filename string
 }
+
+// Statuses of a config file of a running instance compared to the file that
+// would be rendered for the instance now
+const (
+	ConfigDriftChanged = "changed"
+	ConfigDriftAdded   = "added"
+	ConfigDriftRemoved = "removed"
+	ConfigDriftUnknown = "unknown"
+)
+
+// ConfigDrift describes a config file of a running instance that differs
+// from what would be rendered for the instance now
+type ConfigDrift struct {
+	ServiceID  string
+	InstanceID int
+	HostID     string
+	Filename   string
+	Status     string
+}
@@ -15,6 +15,8 @@ package service
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
 	"fmt"
 	"runtime"
 	"strconv"
@@ -22,6 +24,8 @@ import (
 	"text/template"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/secret"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 func parent(gs GetService) func(s *runtimeContext) (*runtimeContext, error) {
@@ -96,6 +100,17 @@ func context(gs GetService) func(s *runtimeContext) (map[string]interface{}, err
 	}
 }
 
+// lookupSecret returns the value of a tenant secret.  Unless the service is
+// being evaluated for a container, the value is redacted.
+func lookupSecret(gsec GetSecret) func(name string) (string, error) {
+	return func(name string) (string, error) {
+		if gsec == nil {
+			return secret.Redacted, nil
+		}
+		return gsec(name)
+	}
+}
+
 func contextFilter(gs GetService) func(s *runtimeContext, prefix string) (map[string]interface{}, error) {
 	return func(s *runtimeContext, prefix string) (map[string]interface{}, error) {
 		ctx := make(map[string]interface{})
@@ -210,6 +225,7 @@ func (service *Service) evaluateTemplate(gs GetService, fc FindChildService, ins
 		"plus":          plus,
 		"uintToInt":     uintToInt,
 		"each":          each,
+		"secret":        lookupSecret(service.getSecret),
 	}
 
 	// parse the template
@@ -303,6 +319,20 @@ func (service *Service) EvaluateConfigFilesTemplate(gs GetService, fc FindChildS
 	return
 }
 
+// ConfigFileChecksums returns the HMAC of the content of each config file,
+// keyed by filename, so that rendered files can be compared without keeping
+// their content.  Rendered files may contain secrets, so the key must not be
+// known to anyone that can read the checksums.
+func ConfigFileChecksums(configFiles map[string]servicedefinition.ConfigFile, key []byte) map[string]string {
+	checksums := make(map[string]string)
+	for _, configFile := range configFiles {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(configFile.Content))
+		checksums[configFile.Filename] = fmt.Sprintf("%x", mac.Sum(nil))
+	}
+	return checksums
+}
+
 // EvaluatePrereqsTemplate parses and evals the Script field for each Prereq.
 func (service *Service) EvaluatePrereqsTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
 	log.WithFields(log.Fields{
@@ -455,6 +485,23 @@ func newRuntimeContext(svc *Service, instanceID int) *runtimeContext {
 	}
 }
 
+// EvaluateWithSecrets evaluates the Service like Evaluate, but resolves
+// references to tenant secrets with the plain text values returned by
+// getSecret.  It should only be used to render a service for a container.
+func (service *Service) EvaluateWithSecrets(getSvc GetService, findChild FindChildService, getSecret GetSecret, instanceID int) error {
+	service.getSecret = getSecret
+	defer func() { service.getSecret = nil }()
+	return service.Evaluate(getSvc, findChild, instanceID)
+}
+
+// RenderConfigFiles evaluates only the config files of the Service, resolving
+// references to tenant secrets with getSecret.
+func (service *Service) RenderConfigFiles(getSvc GetService, findChild FindChildService, getSecret GetSecret, instanceID int) error {
+	service.getSecret = getSecret
+	defer func() { service.getSecret = nil }()
+	return service.EvaluateConfigFilesTemplate(getSvc, findChild, instanceID)
+}
+
 // Evaluate evaluates all the fields of the Service that we care about, using
 // a runtimeContext with the current Service embedded, and adding instanceID
 // as an extra attribute.
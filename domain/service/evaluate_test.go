@@ -16,7 +16,11 @@
 package service_test
 
 import (
+	"errors"
+
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	. "gopkg.in/check.v1"
 )
 
@@ -40,3 +44,47 @@ func (s *ServiceDomainUnitTestSuite) TestRound(c *C) {
 		c.Assert(service.Round(test.value), Equals, test.expected)
 	}
 }
+
+func (s *ServiceDomainUnitTestSuite) TestEvaluateSecrets(c *C) {
+	getSvc := func(serviceID string) (service.Service, error) {
+		return service.Service{}, errors.New("not found")
+	}
+	findChild := func(parentID, childName string) (service.Service, error) {
+		return service.Service{}, errors.New("not found")
+	}
+	getSecret := func(name string) (string, error) {
+		if name == "db_password" {
+			return "s3cr3t", nil
+		}
+		return "", errors.New("no such secret")
+	}
+	newService := func() *service.Service {
+		return &service.Service{
+			ID:          "svc",
+			Environment: []string{`DB_PASSWORD={{secret "db_password"}}`},
+			ConfigFiles: map[string]servicedefinition.ConfigFile{
+				"/etc/db.conf": {Filename: "/etc/db.conf", Content: `password={{secret "db_password"}}`},
+			},
+		}
+	}
+
+	// secrets are redacted unless a lookup is supplied
+	svc := newService()
+	c.Assert(svc.Evaluate(getSvc, findChild, 0), IsNil)
+	c.Assert(svc.Environment[0], Equals, "DB_PASSWORD="+secret.Redacted)
+	c.Assert(svc.ConfigFiles["/etc/db.conf"].Content, Equals, "password="+secret.Redacted)
+
+	svc = newService()
+	c.Assert(svc.EvaluateWithSecrets(getSvc, findChild, getSecret, 0), IsNil)
+	c.Assert(svc.Environment[0], Equals, "DB_PASSWORD=s3cr3t")
+	c.Assert(svc.ConfigFiles["/etc/db.conf"].Content, Equals, "password=s3cr3t")
+
+	// the lookup does not outlive the evaluation
+	svc.Environment = []string{`DB_PASSWORD={{secret "db_password"}}`}
+	c.Assert(svc.EvaluateEnvironmentTemplate(getSvc, findChild, 0), IsNil)
+	c.Assert(svc.Environment[0], Equals, "DB_PASSWORD="+secret.Redacted)
+
+	svc = newService()
+	svc.Environment = []string{`DB_USER={{secret "db_user"}}`}
+	c.Assert(svc.EvaluateWithSecrets(getSvc, findChild, getSecret, 0), NotNil)
+}
@@ -0,0 +1,242 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// DependencyGraph describes how the services of a tenant depend on each other
+// through their imported and exported endpoints
+type DependencyGraph struct {
+	TenantID   string
+	Nodes      []DependencyNode
+	Edges      []DependencyEdge
+	Unresolved []DependencyImport // imports that no service exports
+	Cycles     [][]string         // groups of service ids that depend on each other
+}
+
+// DependencyNode is a service in the dependency graph
+type DependencyNode struct {
+	ServiceID         string
+	Name              string
+	ParentServiceID   string
+	StartLevel        uint
+	Instances         int
+	DesiredState      DesiredState
+	CurrentState      ServiceCurrentState
+	RunningInstances  int
+	EmergencyShutdown bool
+}
+
+// DependencyEdge points from a service to a service whose exported endpoint it
+// imports
+type DependencyEdge struct {
+	ImporterID  string
+	ExporterID  string
+	Application string
+	OutOfOrder  bool // the importer is configured to start before the exporter
+}
+
+// DependencyImport is an endpoint imported by a service
+type DependencyImport struct {
+	ServiceID   string
+	Application string
+}
+
+// BuildDependencyGraph builds the dependency graph of a tenant's services.
+// Services that import their own endpoints do not depend on themselves.
+func BuildDependencyGraph(tenantID string, svcs []Service) *DependencyGraph {
+	sorted := make([]Service, len(svcs))
+	copy(sorted, svcs)
+	sort.Sort(servicesByName(sorted))
+
+	g := &DependencyGraph{
+		TenantID:   tenantID,
+		Nodes:      make([]DependencyNode, len(sorted)),
+		Edges:      []DependencyEdge{},
+		Unresolved: []DependencyImport{},
+		Cycles:     [][]string{},
+	}
+	for i, svc := range sorted {
+		g.Nodes[i] = DependencyNode{
+			ServiceID:       svc.ID,
+			Name:            svc.Name,
+			ParentServiceID: svc.ParentServiceID,
+			StartLevel:      svc.StartLevel,
+			Instances:       svc.Instances,
+			DesiredState:    DesiredState(svc.DesiredState),
+		}
+	}
+
+	seen := make(map[DependencyEdge]bool)
+	for _, importer := range sorted {
+		for _, imp := range importer.GetServiceImports() {
+			matcher, err := regexp.Compile(fmt.Sprintf("^%s$", imp.Application))
+			found := false
+			for _, exporter := range sorted {
+				for _, exp := range exporter.GetServiceExports() {
+					if exp.Application != imp.Application && (err != nil || !matcher.MatchString(exp.Application)) {
+						continue
+					}
+					found = true
+					// a StartLevel of 0 starts last
+					edge := DependencyEdge{
+						ImporterID:  importer.ID,
+						ExporterID:  exporter.ID,
+						Application: exp.Application,
+						OutOfOrder:  importer.StartLevel-1 < exporter.StartLevel-1,
+					}
+					if exporter.ID != importer.ID && !seen[edge] {
+						seen[edge] = true
+						g.Edges = append(g.Edges, edge)
+					}
+				}
+			}
+			if !found {
+				g.Unresolved = append(g.Unresolved, DependencyImport{ServiceID: importer.ID, Application: imp.Application})
+			}
+		}
+	}
+
+	g.Cycles = g.findCycles()
+	return g
+}
+
+// Dependents returns the ids of the services that directly or indirectly
+// import endpoints from the service, and so are affected when it stops
+func (g *DependencyGraph) Dependents(serviceID string) []string {
+	visited := map[string]bool{serviceID: true}
+	queue := []string{serviceID}
+	var dependents []string
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Edges {
+			if edge.ExporterID == id && !visited[edge.ImporterID] {
+				visited[edge.ImporterID] = true
+				dependents = append(dependents, edge.ImporterID)
+				queue = append(queue, edge.ImporterID)
+			}
+		}
+	}
+	return dependents
+}
+
+// Impact returns the part of the graph that is affected when the service
+// stops: the service, its dependents and the edges between them.
+func (g *DependencyGraph) Impact(serviceID string) *DependencyGraph {
+	ids := map[string]bool{serviceID: true}
+	for _, id := range g.Dependents(serviceID) {
+		ids[id] = true
+	}
+
+	impact := &DependencyGraph{
+		TenantID:   g.TenantID,
+		Nodes:      []DependencyNode{},
+		Edges:      []DependencyEdge{},
+		Unresolved: []DependencyImport{},
+		Cycles:     [][]string{},
+	}
+	for _, node := range g.Nodes {
+		if ids[node.ServiceID] {
+			impact.Nodes = append(impact.Nodes, node)
+		}
+	}
+	for _, edge := range g.Edges {
+		if ids[edge.ImporterID] && ids[edge.ExporterID] {
+			impact.Edges = append(impact.Edges, edge)
+		}
+	}
+	for _, imp := range g.Unresolved {
+		if ids[imp.ServiceID] {
+			impact.Unresolved = append(impact.Unresolved, imp)
+		}
+	}
+	impact.Cycles = impact.findCycles()
+	return impact
+}
+
+// findCycles returns the strongly connected components of the graph that
+// contain more than one service (Tarjan's algorithm)
+func (g *DependencyGraph) findCycles() [][]string {
+	adjacent := make(map[string][]string)
+	for _, edge := range g.Edges {
+		adjacent[edge.ImporterID] = append(adjacent[edge.ImporterID], edge.ExporterID)
+	}
+
+	index := 0
+	indexes := make(map[string]int)
+	lowlinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	cycles := [][]string{}
+
+	var connect func(id string)
+	connect = func(id string) {
+		indexes[id] = index
+		lowlinks[id] = index
+		index++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, next := range adjacent[id] {
+			if _, ok := indexes[next]; !ok {
+				connect(next)
+				if lowlinks[next] < lowlinks[id] {
+					lowlinks[id] = lowlinks[next]
+				}
+			} else if onStack[next] && indexes[next] < lowlinks[id] {
+				lowlinks[id] = indexes[next]
+			}
+		}
+
+		if lowlinks[id] == indexes[id] {
+			var component []string
+			for {
+				last := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[last] = false
+				component = append(component, last)
+				if last == id {
+					break
+				}
+			}
+			if len(component) > 1 {
+				sort.Strings(component)
+				cycles = append(cycles, component)
+			}
+		}
+	}
+
+	for _, node := range g.Nodes {
+		if _, ok := indexes[node.ServiceID]; !ok {
+			connect(node.ServiceID)
+		}
+	}
+	return cycles
+}
+
+type servicesByName []Service
+
+func (s servicesByName) Len() int      { return len(s) }
+func (s servicesByName) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s servicesByName) Less(i, j int) bool {
+	if s[i].Name == s[j].Name {
+		return s[i].ID < s[j].ID
+	}
+	return s[i].Name < s[j].Name
+}
@@ -0,0 +1,85 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+func graphServices() []service.Service {
+	endpoint := func(purpose, application string) service.ServiceEndpoint {
+		ep := service.ServiceEndpoint{}
+		ep.Name = application
+		ep.Application = application
+		ep.Purpose = purpose
+		return ep
+	}
+	return []service.Service{
+		{
+			ID:         "web",
+			Name:       "web",
+			StartLevel: 2,
+			Endpoints:  []service.ServiceEndpoint{endpoint("import", "db"), endpoint("import", "cache")},
+		}, {
+			ID:         "db",
+			Name:       "db",
+			StartLevel: 1,
+			Endpoints:  []service.ServiceEndpoint{endpoint("export", "db"), endpoint("import", "db")},
+		}, {
+			ID:         "worker",
+			Name:       "worker",
+			StartLevel: 1,
+			Endpoints:  []service.ServiceEndpoint{endpoint("import_all", "d.*"), endpoint("export", "jobs"), endpoint("import", "queue")},
+		}, {
+			ID:        "queue",
+			Name:      "queue",
+			Endpoints: []service.ServiceEndpoint{endpoint("export", "queue"), endpoint("import", "jobs")},
+		},
+	}
+}
+
+func (s *ServiceDomainUnitTestSuite) TestBuildDependencyGraph(c *C) {
+	g := service.BuildDependencyGraph("tenant", graphServices())
+
+	c.Assert(g.TenantID, Equals, "tenant")
+	c.Assert(g.Nodes, HasLen, 4)
+	c.Assert(g.Nodes[0].ServiceID, Equals, "db")
+	c.Assert(g.Nodes[3].ServiceID, Equals, "worker")
+	c.Assert(g.Edges, DeepEquals, []service.DependencyEdge{
+		{ImporterID: "queue", ExporterID: "worker", Application: "jobs"},
+		{ImporterID: "web", ExporterID: "db", Application: "db"},
+		{ImporterID: "worker", ExporterID: "db", Application: "db"},
+		{ImporterID: "worker", ExporterID: "queue", Application: "queue", OutOfOrder: true},
+	})
+	c.Assert(g.Unresolved, DeepEquals, []service.DependencyImport{{ServiceID: "web", Application: "cache"}})
+	c.Assert(g.Cycles, DeepEquals, [][]string{{"queue", "worker"}})
+}
+
+func (s *ServiceDomainUnitTestSuite) TestDependencyGraphImpact(c *C) {
+	g := service.BuildDependencyGraph("tenant", graphServices())
+
+	c.Assert(g.Dependents("db"), DeepEquals, []string{"web", "worker", "queue"})
+	c.Assert(g.Dependents("web"), HasLen, 0)
+
+	impact := g.Impact("queue")
+	c.Assert(impact.Nodes, HasLen, 2)
+	c.Assert(impact.Nodes[0].ServiceID, Equals, "queue")
+	c.Assert(impact.Nodes[1].ServiceID, Equals, "worker")
+	c.Assert(impact.Edges, HasLen, 2)
+	c.Assert(impact.Unresolved, HasLen, 0)
+	c.Assert(impact.Cycles, DeepEquals, [][]string{{"queue", "worker"}})
+}
@@ -35,6 +35,7 @@ const (
 	StatePendingRestart    InstanceCurrentState = "pending_restart"
 	StateEmergencyStopping InstanceCurrentState = "emergency_stopping"
 	StateEmergencyStopped  InstanceCurrentState = "emergency_stopped"
+	StateCrashLoop         InstanceCurrentState = "crash_loop"
 )
 
 // Usage describes the current, max, and avg values of an instance
@@ -62,6 +63,7 @@ type Instance struct {
 	Scheduled     time.Time
 	Started       time.Time
 	Terminated    time.Time
+	Crashes       int
 }
 
 // StrategyInstance collects service strategy information about a service
@@ -70,6 +72,7 @@ type StrategyInstance struct {
 	HostID        string
 	ServiceID     string
 	CPUCommitment int
+	CPUQuota      float64
 	RAMCommitment uint64
 	RAMThreshold  uint
 	HostPolicy    servicedefinition.HostPolicy
@@ -0,0 +1,99 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"time"
+)
+
+// Rolling restart states
+const (
+	RollingRestartRunning   = "running"
+	RollingRestartCompleted = "completed"
+	RollingRestartHalted    = "halted"
+	RollingRestartReverted  = "reverted"
+	RollingRestartCancelled = "cancelled"
+)
+
+var (
+	// ErrBadBatchSize is returned when the rolling restart batch size is out of range
+	ErrBadBatchSize = errors.New("batch size must not be negative")
+	// ErrBadBatchPercent is returned when the rolling restart batch percentage is out of range
+	ErrBadBatchPercent = errors.New("batch percent must be between 0 and 100")
+	// ErrBatchConflict is returned when both a batch size and percentage are set
+	ErrBatchConflict = errors.New("batch size and batch percent cannot both be set")
+	// ErrBadRollingRestartDuration is returned when the pause, timeout or window is negative
+	ErrBadRollingRestartDuration = errors.New("pause, timeout and window must not be negative")
+)
+
+// RollingRestart describes how the instances of a service are restarted.  The
+// zero value restarts one instance at a time and moves on when an instance
+// does not become healthy in time.
+type RollingRestart struct {
+	BatchSize       int           // Number of instances to restart at a time
+	BatchPercent    int           // Percentage of instances to restart at a time, if BatchSize is not set
+	Pause           time.Duration // Time to wait between batches
+	Timeout         time.Duration // Time a batch has to start and pass its health checks
+	Window          time.Duration // Time a batch must stay up and healthy after it starts
+	Halt            bool          // Stop the restart when a batch does not become healthy in time
+	Revert          bool          // Put back PreviousImageID when the restart is halted
+	PreviousImageID string        // Image the service ran before the restart
+}
+
+// ValidEntity checks the rolling restart options
+func (r RollingRestart) ValidEntity() error {
+	if r.BatchSize < 0 {
+		return ErrBadBatchSize
+	} else if r.BatchPercent < 0 || r.BatchPercent > 100 {
+		return ErrBadBatchPercent
+	} else if r.BatchSize > 0 && r.BatchPercent > 0 {
+		return ErrBatchConflict
+	} else if r.Pause < 0 || r.Timeout < 0 || r.Window < 0 {
+		return ErrBadRollingRestartDuration
+	}
+	return nil
+}
+
+// GetBatchSize returns the number of instances to restart at a time
+func (r RollingRestart) GetBatchSize(instances int) int {
+	size := r.BatchSize
+	if size == 0 && r.BatchPercent > 0 {
+		size = instances * r.BatchPercent / 100
+	}
+	if size < 1 {
+		size = 1
+	} else if size > instances && instances > 0 {
+		size = instances
+	}
+	return size
+}
+
+// RollingRestartStatus is the progress of the most recent rolling restart of
+// a service
+type RollingRestartStatus struct {
+	ServiceID       string
+	ServiceName     string
+	State           string
+	Instances       int
+	BatchSize       int
+	Batch           int // Batch being restarted, starting at 1
+	Batches         int
+	Restarted       int // Instances that have been restarted
+	ImageID         string
+	PreviousImageID string // Image to revert to if the restart is halted
+	Started         time.Time
+	Updated         time.Time
+	Message         string
+}
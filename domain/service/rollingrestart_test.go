@@ -0,0 +1,42 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+func (s *ServiceDomainUnitTestSuite) TestRollingRestart_ValidEntity(c *C) {
+	c.Assert(service.RollingRestart{}.ValidEntity(), IsNil)
+	c.Assert(service.RollingRestart{BatchSize: 5, Pause: time.Second, Halt: true}.ValidEntity(), IsNil)
+	c.Assert(service.RollingRestart{BatchSize: -1}.ValidEntity(), Equals, service.ErrBadBatchSize)
+	c.Assert(service.RollingRestart{BatchPercent: 101}.ValidEntity(), Equals, service.ErrBadBatchPercent)
+	c.Assert(service.RollingRestart{BatchSize: 2, BatchPercent: 10}.ValidEntity(), Equals, service.ErrBatchConflict)
+	c.Assert(service.RollingRestart{Pause: -time.Second}.ValidEntity(), Equals, service.ErrBadRollingRestartDuration)
+	c.Assert(service.RollingRestart{Window: -time.Second}.ValidEntity(), Equals, service.ErrBadRollingRestartDuration)
+}
+
+func (s *ServiceDomainUnitTestSuite) TestRollingRestart_GetBatchSize(c *C) {
+	c.Assert(service.RollingRestart{}.GetBatchSize(20), Equals, 1)
+	c.Assert(service.RollingRestart{BatchSize: 5}.GetBatchSize(20), Equals, 5)
+	c.Assert(service.RollingRestart{BatchSize: 5}.GetBatchSize(3), Equals, 3)
+	c.Assert(service.RollingRestart{BatchPercent: 25}.GetBatchSize(20), Equals, 5)
+	c.Assert(service.RollingRestart{BatchPercent: 25}.GetBatchSize(2), Equals, 1)
+	c.Assert(service.RollingRestart{BatchPercent: 100}.GetBatchSize(20), Equals, 20)
+}
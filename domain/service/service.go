@@ -251,7 +251,16 @@ type Service struct {
 	MonitoringProfile domain.MonitorProfile
 	MemoryLimit       float64
 	CPUShares         int64
-	PIDFile           string
+	CPUQuota          float64
+	CPUSet            string
+	Security          servicedefinition.SecurityProfile
+	AutoScale         servicedefinition.AutoScale
+	// AutoScaledUp and AutoScaledDown are when the autoscaler last added or
+	// removed instances of the service, so that the cooldown of each
+	// direction outlasts a restart of the master.
+	AutoScaledUp   time.Time
+	AutoScaledDown time.Time
+	PIDFile        string
 	// StartLevel represents the order in which services are started and stopped
 	// in normal operations.  All services of a given level start before any services
 	// at higher levels.  Stopping services occurs in the reverse order.  Services
@@ -268,7 +277,20 @@ type Service struct {
 	// EmergencyShutdown is a flag that indicates whether this service has been shutdown due
 	// to an emergency (low-storage) situation.  Services with this flag set can not be started
 	EmergencyShutdown bool
+	// Trace is the traceparent of the request that is scheduling the service.
+	// It is passed on to the scheduler and never stored.
+	Trace string `json:"-"`
+	// RollingRestart holds the options of the rolling restart requested for
+	// the service.  It is passed on to the scheduler and never stored.
+	RollingRestart *RollingRestart `json:"-"`
+	// ConfigChecksums are the keyed checksums of the rendered config files,
+	// by filename.  They are only set on evaluated services, so that agents
+	// can record them without the key, and are never stored.
+	ConfigChecksums map[string]string `json:",omitempty"`
 	datastore.VersionedEntity
+
+	// getSecret looks up tenant secrets while the service is being evaluated
+	getSecret GetSecret
 }
 
 //ServiceEndpoint endpoint exported or imported by a service
@@ -409,6 +431,10 @@ func BuildService(sd servicedefinition.ServiceDefinition, parentServiceID string
 	svc.MonitoringProfile = *profile
 	svc.MemoryLimit = sd.MemoryLimit
 	svc.CPUShares = sd.CPUShares
+	svc.CPUQuota = sd.CPUQuota
+	svc.CPUSet = sd.CPUSet
+	svc.Security = sd.Security
+	svc.AutoScale = sd.AutoScale
 
 	return &svc, nil
 }
@@ -427,6 +453,8 @@ func CloneService(fromSvc *Service, suffix string) (*Service, error) {
 	now := time.Now()
 	svc.CreatedAt = now
 	svc.UpdatedAt = now
+	svc.AutoScaledUp = time.Time{}
+	svc.AutoScaledDown = time.Time{}
 
 	// add suffix to make certain things unique
 	suffix = strings.TrimSpace(suffix)
@@ -896,6 +924,24 @@ func (s *Service) Equals(b *Service) bool {
 	if s.Privileged != b.Privileged {
 		return false
 	}
+	if s.CPUQuota != b.CPUQuota {
+		return false
+	}
+	if s.CPUSet != b.CPUSet {
+		return false
+	}
+	if !reflect.DeepEqual(s.Security, b.Security) {
+		return false
+	}
+	if s.AutoScale != b.AutoScale {
+		return false
+	}
+	if s.AutoScaledUp.Unix() != b.AutoScaledUp.Unix() {
+		return false
+	}
+	if s.AutoScaledDown.Unix() != b.AutoScaledDown.Unix() {
+		return false
+	}
 	if s.HostPolicy != b.HostPolicy {
 		return false
 	}
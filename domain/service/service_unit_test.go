@@ -110,3 +110,16 @@ func (s *ServiceDomainUnitTestSuite) TestBuildServiceSimple(t *C) {
 	t.Check(actual.StartLevel, Equals, startLevel)
 	t.Check(actual.EmergencyShutdownLevel, Equals, shutdownLevel)
 }
+
+func (s *ServiceDomainUnitTestSuite) TestEqualsComparesCPULimits(t *C) {
+	a := service.Service{ID: "svc", CPUQuota: 1.5, CPUSet: "0-1"}
+	b := a
+	t.Check(a.Equals(&b), Equals, true)
+
+	b.CPUQuota = 2
+	t.Check(a.Equals(&b), Equals, false)
+
+	b = a
+	b.CPUSet = "2"
+	t.Check(a.Equals(&b), Equals, false)
+}
@@ -131,6 +131,7 @@ func (s *storeImpl) Put(ctx datastore.Context, svc *Service) error {
 	//No need to store ConfigFiles
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("ServiceStore.Put"))
 	svc.ConfigFiles = make(map[string]servicedefinition.ConfigFile)
+	svc.ConfigChecksums = nil
 
 	err := s.ds.Put(ctx, Key(svc.ID), svc)
 	if err == nil {
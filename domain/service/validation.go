@@ -17,6 +17,7 @@ import (
 	"fmt"
 
 	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/validation"
 )
 
@@ -50,6 +51,23 @@ func (s *Service) ValidEntity() error {
 		vErr.Add(ep.ValidEntity())
 	}
 
+	// validate the cpu limits
+	if s.CPUQuota < 0 {
+		vErr.Add(fmt.Errorf("CPU quota (%v) must not be negative", s.CPUQuota))
+	}
+	if _, err := servicedefinition.ParseCPUSet(s.CPUSet); err != nil {
+		vErr.Add(err)
+	}
+
+	// validate the container security profile
+	vErr.Add(s.Security.ValidEntity())
+
+	// validate the autoscaler settings
+	vErr.Add(s.AutoScale.ValidEntity())
+	if s.AutoScale.Enabled() && s.InstanceLimits.Max < 1 {
+		vErr.Add(fmt.Errorf("autoscale requires a maximum number of instances"))
+	}
+
 	if vErr.HasError() {
 		return vErr
 	}
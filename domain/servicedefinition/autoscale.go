@@ -0,0 +1,112 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicedefinition
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/validation"
+)
+
+// Metrics that the autoscaler can scale on besides the ones pushed by the
+// service with 'serviced metric push'
+const (
+	AutoScaleCPU    = "cpu"    // CPU time spent in user mode per second
+	AutoScaleMemory = "memory" // Resident memory, in bytes
+)
+
+// Defaults for the autoscaler
+const (
+	DefaultAutoScaleWindow       = 5 * time.Minute
+	DefaultAutoScaleUpCooldown   = 3 * time.Minute
+	DefaultAutoScaleDownCooldown = 10 * time.Minute
+)
+
+// AutoScale sets the number of instances of a service from a metric, within
+// the service's instance limits.  The autoscaler aims to keep the average
+// value of the metric per instance at the target.
+type AutoScale struct {
+	Metric            string  // "cpu", "memory" or the name of a metric pushed by the service; empty disables autoscaling
+	Rate              bool    // The metric is a counter; scale on its rate per second
+	Target            float64 // Average value of the metric per instance
+	Window            int     // Seconds of metric data to average, 0 = 300
+	ScaleUpCooldown   int     // Seconds to wait after scaling before adding instances, 0 = 180
+	ScaleDownCooldown int     // Seconds to wait after scaling before removing instances, 0 = 600
+}
+
+// Enabled returns true if the service is autoscaled
+func (a AutoScale) Enabled() bool {
+	return a.Metric != ""
+}
+
+// GetWindow returns how much metric data to average
+func (a AutoScale) GetWindow() time.Duration {
+	if a.Window > 0 {
+		return time.Duration(a.Window) * time.Second
+	}
+	return DefaultAutoScaleWindow
+}
+
+// GetCooldown returns how long to wait after scaling before scaling in the
+// same direction again
+func (a AutoScale) GetCooldown(up bool) time.Duration {
+	if up && a.ScaleUpCooldown > 0 {
+		return time.Duration(a.ScaleUpCooldown) * time.Second
+	} else if up {
+		return DefaultAutoScaleUpCooldown
+	} else if a.ScaleDownCooldown > 0 {
+		return time.Duration(a.ScaleDownCooldown) * time.Second
+	}
+	return DefaultAutoScaleDownCooldown
+}
+
+// DesiredInstances returns the number of instances that brings the average
+// value of the metric per instance to the target, within the limits.
+func (a AutoScale) DesiredInstances(instances int, average float64, limits domain.MinMax) int {
+	desired := instances
+	if a.Target > 0 && instances > 0 {
+		desired = int(math.Ceil(float64(instances) * average / a.Target))
+	}
+	if desired < 1 {
+		desired = 1
+	}
+	if desired < limits.Min {
+		desired = limits.Min
+	}
+	if limits.Max > 0 && desired > limits.Max {
+		desired = limits.Max
+	}
+	return desired
+}
+
+// ValidEntity makes sure the autoscaler can act on the settings
+func (a AutoScale) ValidEntity() error {
+	if !a.Enabled() {
+		return nil
+	}
+	vErr := validation.NewValidationError()
+	if a.Target <= 0 {
+		vErr.AddViolation(fmt.Sprintf("autoscale target %v must be positive", a.Target))
+	}
+	if a.Window < 0 || a.ScaleUpCooldown < 0 || a.ScaleDownCooldown < 0 {
+		vErr.AddViolation("autoscale window and cooldowns must not be negative")
+	}
+	if vErr.HasError() {
+		return vErr
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicedefinition
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseCPUSet returns the sorted CPU numbers in a cpuset list such as
+// "0-3,6".  An empty cpuset returns no CPUs.
+func ParseCPUSet(cpuset string) ([]int, error) {
+	if cpuset == "" {
+		return nil, nil
+	}
+	found := make(map[int]struct{})
+	for _, part := range strings.Split(cpuset, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		lo, err := strconv.Atoi(bounds[0])
+		if err != nil || lo < 0 {
+			return nil, fmt.Errorf("invalid cpuset %s", cpuset)
+		}
+		hi := lo
+		if len(bounds) == 2 {
+			if hi, err = strconv.Atoi(bounds[1]); err != nil || hi < lo {
+				return nil, fmt.Errorf("invalid cpuset %s", cpuset)
+			}
+		}
+		for cpu := lo; cpu <= hi; cpu++ {
+			found[cpu] = struct{}{}
+		}
+	}
+	cpus := make([]int, 0, len(found))
+	for cpu := range found {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
@@ -0,0 +1,253 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicedefinition
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LintWarning describes a likely mistake in a service definition that is not
+// caught by ValidEntity
+type LintWarning struct {
+	Path    string // JSON path of the offending field, e.g. $.Services[0].Endpoints[1]
+	Message string
+}
+
+func (w LintWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Path, w.Message)
+}
+
+// lintNode is a service definition in the hierarchy being linted
+type lintNode struct {
+	path       string
+	sd         *ServiceDefinition
+	logFilters map[string]struct{} // log filters defined by the service or its parents
+}
+
+// lintExport is an exported endpoint that may be imported by other services
+type lintExport struct {
+	node     *lintNode
+	index    int
+	imported bool
+}
+
+// Lint reports likely mistakes in a service definition and its subservices.
+// Paths are relative to the service definition.  Images are not inspected, so
+// a health check script that is neither a config file nor on a volume is only
+// reported when its directory holds other config files, and it may still be
+// provided by the image.
+func Lint(sd *ServiceDefinition) []LintWarning {
+	return lint(collectLintNodes(nil, "$", sd, nil))
+}
+
+// LintServices reports likely mistakes in the services of a template.  Paths
+// are relative to the template.
+func LintServices(sds []ServiceDefinition) []LintWarning {
+	var nodes []*lintNode
+	for i := range sds {
+		nodes = collectLintNodes(nodes, fmt.Sprintf("$.Services[%d]", i), &sds[i], nil)
+	}
+	return lint(nodes)
+}
+
+func collectLintNodes(nodes []*lintNode, jsonPath string, sd *ServiceDefinition, parentFilters map[string]struct{}) []*lintNode {
+	filters := make(map[string]struct{})
+	for name := range parentFilters {
+		filters[name] = struct{}{}
+	}
+	for name := range sd.LogFilters {
+		filters[name] = struct{}{}
+	}
+	nodes = append(nodes, &lintNode{path: jsonPath, sd: sd, logFilters: filters})
+	for i := range sd.Services {
+		nodes = collectLintNodes(nodes, fmt.Sprintf("%s.Services[%d]", jsonPath, i), &sd.Services[i], filters)
+	}
+	return nodes
+}
+
+func lint(nodes []*lintNode) []LintWarning {
+	var warnings []LintWarning
+	warn := func(jsonPath, format string, args ...interface{}) {
+		warnings = append(warnings, LintWarning{Path: jsonPath, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var exports []*lintExport
+	for _, node := range nodes {
+		for i, ep := range node.sd.Endpoints {
+			if ep.Purpose == "export" && !isTemplated(ep.Application) {
+				exports = append(exports, &lintExport{node: node, index: i})
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		sd := node.sd
+
+		// imports
+		addresses := make(map[string]int)
+		for i, ep := range sd.Endpoints {
+			if ep.Purpose != "import" && ep.Purpose != "import_all" {
+				continue
+			}
+			epPath := fmt.Sprintf("%s.Endpoints[%d]", node.path, i)
+			if ep.VirtualAddress != "" {
+				if j, ok := addresses[ep.VirtualAddress]; ok {
+					warn(epPath+".VirtualAddress", "virtual address %s is also used by endpoint %s", ep.VirtualAddress, sd.Endpoints[j].Name)
+				} else {
+					addresses[ep.VirtualAddress] = i
+				}
+			}
+			if isTemplated(ep.Application) {
+				continue
+			}
+			found := false
+			for _, export := range exports {
+				exporter := export.node.sd
+				if !matchesApplication(ep.Application, exporter.Endpoints[export.index].Application) {
+					continue
+				}
+				found = true
+				export.imported = true
+				if export.node != node && startOrder(sd) < startOrder(exporter) {
+					warn(node.path+".StartLevel", "service starts before %s (StartLevel %d), which exports %s", exporter.Name, exporter.StartLevel, ep.Application)
+				}
+			}
+			if !found {
+				warn(epPath, "imports %s, which is not exported by any service", ep.Application)
+			}
+		}
+
+		// log configs
+		for i, logConfig := range sd.LogConfigs {
+			for j, filter := range logConfig.Filters {
+				if _, ok := node.logFilters[filter]; !ok {
+					warn(fmt.Sprintf("%s.LogConfigs[%d].Filters[%d]", node.path, i, j), "log filter %s is not defined by the service or its parents", filter)
+				}
+			}
+		}
+
+		// health checks
+		names := make([]string, 0, len(sd.HealthChecks))
+		for name := range sd.HealthChecks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			hcPath := fmt.Sprintf("%s.HealthChecks['%s'].Script", node.path, name)
+			script := strings.TrimSpace(sd.HealthChecks[name].Script)
+			if script == "" {
+				warn(hcPath, "health check has no script")
+				continue
+			}
+			for _, command := range scriptCommands(script) {
+				if conf, ok := sd.ConfigFiles[command]; ok {
+					if conf.Permissions != "" && !isExecutable(conf.Permissions) {
+						warn(hcPath, "runs %s, which is not executable (Permissions %s)", command, conf.Permissions)
+					}
+				} else if !onVolume(sd, command) && providesConfigDir(sd, path.Dir(command)) {
+					warn(hcPath, "runs %s, which is not defined in ConfigFiles (ignore if the image provides it)", command)
+				}
+			}
+		}
+	}
+
+	// exports that nobody imports; public endpoints are reached from outside
+	// the application
+	for _, export := range exports {
+		ep := export.node.sd.Endpoints[export.index]
+		if !export.imported && len(ep.VHostList) == 0 && len(ep.PortList) == 0 {
+			warn(fmt.Sprintf("%s.Endpoints[%d]", export.node.path, export.index), "exports %s, which is not imported by any service", ep.Application)
+		}
+	}
+	return warnings
+}
+
+// isTemplated returns true if the value is only known after the template is
+// evaluated
+func isTemplated(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// matchesApplication returns true if the imported application name (which may
+// be a regular expression) matches the exported application
+func matchesApplication(imported, exported string) bool {
+	if imported == exported {
+		return true
+	}
+	re, err := regexp.Compile("^(?:" + imported + ")$")
+	return err == nil && re.MatchString(exported)
+}
+
+// startOrder returns the position of the service in the startup sequence; a
+// StartLevel of 0 starts last.
+func startOrder(sd *ServiceDefinition) uint {
+	return sd.StartLevel - 1
+}
+
+// scriptCommands returns the absolute paths run by a shell script
+func scriptCommands(script string) []string {
+	var commands []string
+	segments := regexp.MustCompile(`&&|\|\||[;|\n]`).Split(script, -1)
+	for _, segment := range segments {
+		for _, field := range strings.Fields(segment) {
+			if strings.Contains(field, "=") && !strings.HasPrefix(field, "/") {
+				// environment variable assignment
+				continue
+			}
+			if strings.HasPrefix(field, "/") {
+				commands = append(commands, field)
+			}
+			break
+		}
+	}
+	return commands
+}
+
+// isExecutable returns true if the octal permissions allow a file to be
+// executed
+func isExecutable(permissions string) bool {
+	mode, err := strconv.ParseUint(permissions, 8, 32)
+	return err != nil || mode&0111 != 0
+}
+
+// providesConfigDir returns true if the service defines config files in the
+// directory
+func providesConfigDir(sd *ServiceDefinition, dir string) bool {
+	for filename := range sd.ConfigFiles {
+		if path.Dir(filename) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// onVolume returns true if the file is under the container path of one of the
+// service's volumes, which provide their own content at runtime
+func onVolume(sd *ServiceDefinition, filename string) bool {
+	for _, v := range sd.Volumes {
+		if v.ContainerPath == "" {
+			continue
+		}
+		dir := strings.TrimSuffix(path.Clean(v.ContainerPath), "/")
+		if filename == dir || strings.HasPrefix(filename, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
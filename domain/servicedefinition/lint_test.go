@@ -0,0 +1,117 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package servicedefinition_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/health"
+)
+
+func lintDefinition() *servicedefinition.ServiceDefinition {
+	return &servicedefinition.ServiceDefinition{
+		Name:       "app",
+		LogFilters: map[string]string{"app": "filter {}"},
+		Services: []servicedefinition.ServiceDefinition{
+			{
+				Name:       "db",
+				StartLevel: 1,
+				Endpoints: []servicedefinition.EndpointDefinition{
+					{Name: "mysql", Application: "mysql", Purpose: "export", PortNumber: 3306},
+					{Name: "unused", Application: "unused", Purpose: "export", PortNumber: 4000},
+					{Name: "web", Application: "web", Purpose: "export", PortNumber: 8080, PortList: []servicedefinition.Port{{PortAddr: ":8080"}}},
+				},
+			}, {
+				Name:       "worker",
+				StartLevel: 2,
+				Endpoints: []servicedefinition.EndpointDefinition{
+					{Name: "mysql", Application: "mysql", Purpose: "import", VirtualAddress: "db:3306"},
+					{Name: "cache", Application: "cache", Purpose: "import", VirtualAddress: "db:3306"},
+					{Name: "other", Application: "{{(parent .).Name}}_other", Purpose: "import"},
+				},
+				LogConfigs: []servicedefinition.LogConfig{
+					{Path: "/var/log/worker.log", Filters: []string{"app", "worker"}},
+				},
+				ConfigFiles: map[string]servicedefinition.ConfigFile{
+					"/opt/worker/check.sh":      {Filename: "/opt/worker/check.sh", Permissions: "0644"},
+					"/opt/worker/data/conf.ini": {Filename: "/opt/worker/data/conf.ini"},
+				},
+				Volumes: []servicedefinition.Volume{
+					{ResourcePath: "worker", ContainerPath: "/opt/worker/data"},
+				},
+				HealthChecks: map[string]health.HealthCheck{
+					"alive":   {Script: "/opt/worker/check.sh"},
+					"missing": {Script: "FOO=1 /opt/worker/ready.sh && echo ok"},
+					"volume":  {Script: "/opt/worker/data/check.sh"},
+					"empty":   {Script: " "},
+					"ping":    {Script: "curl -s http://localhost:8080 | grep ok"},
+				},
+			}, {
+				Name: "web",
+				Endpoints: []servicedefinition.EndpointDefinition{
+					{Name: "mysql", Application: "my.*", Purpose: "import"},
+				},
+			},
+		},
+	}
+}
+
+func TestLint(t *testing.T) {
+	warnings := servicedefinition.Lint(lintDefinition())
+	expected := []servicedefinition.LintWarning{
+		{Path: "$.Services[1].Endpoints[1].VirtualAddress", Message: "virtual address db:3306 is also used by endpoint mysql"},
+		{Path: "$.Services[1].Endpoints[1]", Message: "imports cache, which is not exported by any service"},
+		{Path: "$.Services[1].LogConfigs[0].Filters[1]", Message: "log filter worker is not defined by the service or its parents"},
+		{Path: "$.Services[1].HealthChecks['alive'].Script", Message: "runs /opt/worker/check.sh, which is not executable (Permissions 0644)"},
+		{Path: "$.Services[1].HealthChecks['empty'].Script", Message: "health check has no script"},
+		{Path: "$.Services[1].HealthChecks['missing'].Script", Message: "runs /opt/worker/ready.sh, which is not defined in ConfigFiles (ignore if the image provides it)"},
+		{Path: "$.Services[0].Endpoints[1]", Message: "exports unused, which is not imported by any service"},
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Expected %v, got %v", expected, warnings)
+	}
+}
+
+func TestLintStartLevel(t *testing.T) {
+	sd := lintDefinition()
+	sd.Services[0].StartLevel = 0
+	warnings := servicedefinition.Lint(sd)
+
+	found := 0
+	for _, w := range warnings {
+		if w.Path == "$.Services[1].StartLevel" || w.Path == "$.Services[2].StartLevel" {
+			found++
+		}
+	}
+	// the web service also starts last, so only the worker is out of order
+	if found != 1 {
+		t.Errorf("Expected 1 StartLevel warning, got %v", warnings)
+	}
+}
+
+func TestLintServices(t *testing.T) {
+	sd := lintDefinition()
+	warnings := servicedefinition.LintServices(sd.Services[:1])
+	expected := []servicedefinition.LintWarning{
+		{Path: "$.Services[0].Endpoints[0]", Message: "exports mysql, which is not imported by any service"},
+		{Path: "$.Services[0].Endpoints[1]", Message: "exports unused, which is not imported by any service"},
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Expected %v, got %v", expected, warnings)
+	}
+}
@@ -39,6 +39,7 @@ type ServiceDefinition struct {
 	Tags                   []string               // Searchable service tags
 	ImageID                string                 // Docker image hosting the service
 	Instances              domain.MinMax          // Constraints on the number of instances
+	InstancesParameter     string                 // Name of the template parameter that sets the default number of instances
 	ChangeOptions          []ChangeOption         // Control options for what happens when a running service is changed
 	Launch                 string                 // Must be "AUTO", the default, or "MANUAL"
 	HostPolicy             HostPolicy             // Policy for starting up instances
@@ -64,9 +65,13 @@ type ServiceDefinition struct {
 	MonitoringProfile      domain.MonitorProfile         // An optional list of queryable metrics, graphs, and thresholds
 	MemoryLimit            float64
 	CPUShares              int64
-	PIDFile                string // An optional path or command to generate a path for a PID file to which signals are relayed.
-	StartLevel             uint   // Services start in the order implied by this field (low to high) and stopped in reverse order
-	EmergencyShutdownLevel uint   // In case of low storage, Services stopped in the order implied by this field (low to high)
+	CPUQuota               float64         // Hard limit on CPU usage, in (fractional) cores, 0 = unlimited
+	CPUSet                 string          // CPUs the instances may run on, e.g. "0-3,6"
+	Security               SecurityProfile // Restrictions on the privileges of the service's containers
+	AutoScale              AutoScale       // Sets the number of instances from a metric
+	PIDFile                string          // An optional path or command to generate a path for a PID file to which signals are relayed.
+	StartLevel             uint            // Services start in the order implied by this field (low to high) and stopped in reverse order
+	EmergencyShutdownLevel uint            // In case of low storage, Services stopped in the order implied by this field (low to high)
 }
 
 // SnapshotCommands commands to be called during and after a snapshot
@@ -75,6 +80,29 @@ type SnapshotCommands struct {
 	Resume string // bash command to resume the volume (unquiesce)
 }
 
+// SecurityProfile restricts what the processes in a service's containers are
+// allowed to do.  User applies to the service command, its health checks,
+// prereqs and PIDFile command; commands run with "serviced service attach"
+// and the snapshot pause and resume scripts still run as root.
+type SecurityProfile struct {
+	CapAdd          []string          // Linux capabilities to add, e.g. "NET_ADMIN"
+	CapDrop         []string          // Linux capabilities to drop, or "ALL"
+	ReadOnlyRootFS  bool              // Mount the container's root filesystem read-only
+	Tmpfs           map[string]string // tmpfs mounts keyed by container path, with their mount options
+	User            string            // User to run the service's processes as: name, uid or uid:gid
+	NoNewPrivileges bool              // Prevent processes from gaining privileges through setuid binaries
+	PidsLimit       int64             // Maximum number of processes in the container, 0 = unlimited
+	Ulimits         []Ulimit          // Resource limits; the core ulimit is 0 unless overridden here
+	SeccompProfile  string            // "unconfined", or the name of a profile in the agent's seccomp directory
+}
+
+// Ulimit is a resource limit applied to a service's containers
+type Ulimit struct {
+	Name string // Name of the resource, e.g. "nofile"
+	Soft int64
+	Hard int64
+}
+
 // EndpointDefinition An endpoint that a Service exposes.
 type EndpointDefinition struct {
 	Name                string // Human readable name of the endpoint. Unique per service definition
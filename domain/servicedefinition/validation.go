@@ -15,6 +15,7 @@ package servicedefinition
 
 import (
 	"fmt"
+	"path"
 	"regexp"
 	"strings"
 
@@ -66,9 +67,56 @@ func (sd *ServiceDefinition) validate(context *validationContext) error {
 		return fmt.Errorf("service definition %v: invalid monitoring profile %s", sd.Name, err)
 	}
 
+	// validate the cpu limits
+	if sd.CPUQuota < 0 {
+		return fmt.Errorf("service definition %v: cpu quota %v must not be negative", sd.Name, sd.CPUQuota)
+	}
+	if _, err := ParseCPUSet(sd.CPUSet); err != nil {
+		return fmt.Errorf("service definition %v: %s", sd.Name, err)
+	}
+
+	// validate the autoscaler settings
+	if err := sd.AutoScale.ValidEntity(); err != nil {
+		return fmt.Errorf("service definition %v: invalid autoscale %s", sd.Name, err)
+	}
+	if sd.AutoScale.Enabled() && sd.Instances.Max < 1 {
+		return fmt.Errorf("service definition %v: autoscale requires a maximum number of instances", sd.Name)
+	}
+
+	// validate the container security profile
+	if err := sd.Security.ValidEntity(); err != nil {
+		return fmt.Errorf("service definition %v: invalid security profile %s", sd.Name, err)
+	}
+	if sd.Security.ReadOnlyRootFS {
+		for _, conf := range sd.ConfigFiles {
+			if !sd.isWritable(conf.Filename) {
+				return fmt.Errorf("service definition %v: config file %s must be on a tmpfs mount or volume when the root filesystem is read-only", sd.Name, conf.Filename)
+			}
+		}
+	}
+
 	return validServiceDefinitions(&sd.Services, context)
 }
 
+// isWritable returns true if the container path is on a tmpfs mount or volume
+func (sd *ServiceDefinition) isWritable(filename string) bool {
+	mounts := []string{"/tmp"}
+	for mount := range sd.Security.Tmpfs {
+		mounts = append(mounts, mount)
+	}
+	for _, volume := range sd.Volumes {
+		mounts = append(mounts, volume.ContainerPath)
+	}
+	filename = path.Clean(filename)
+	for _, mount := range mounts {
+		mount = path.Clean(mount)
+		if strings.HasPrefix(filename, mount+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // validServiceDefinitions validates an array of ServiceDefinition recursively
 func validServiceDefinitions(ds *[]ServiceDefinition, context *validationContext) error {
 	for _, sd := range *ds {
@@ -158,3 +206,73 @@ func (arc *AddressResourceConfig) Normalize() {
 	testProto := strings.Trim(strings.ToLower(arc.Protocol), " ")
 	arc.Protocol = testProto
 }
+
+// capabilities are the Linux capability names accepted by docker, without
+// the CAP_ prefix
+var capabilities = map[string]struct{}{
+	"ALL": {}, "AUDIT_CONTROL": {}, "AUDIT_READ": {}, "AUDIT_WRITE": {},
+	"BLOCK_SUSPEND": {}, "CHOWN": {}, "DAC_OVERRIDE": {}, "DAC_READ_SEARCH": {},
+	"FOWNER": {}, "FSETID": {}, "IPC_LOCK": {}, "IPC_OWNER": {}, "KILL": {},
+	"LEASE": {}, "LINUX_IMMUTABLE": {}, "MAC_ADMIN": {}, "MAC_OVERRIDE": {},
+	"MKNOD": {}, "NET_ADMIN": {}, "NET_BIND_SERVICE": {}, "NET_BROADCAST": {},
+	"NET_RAW": {}, "SETFCAP": {}, "SETGID": {}, "SETPCAP": {}, "SETUID": {},
+	"SYSLOG": {}, "SYS_ADMIN": {}, "SYS_BOOT": {}, "SYS_CHROOT": {},
+	"SYS_MODULE": {}, "SYS_NICE": {}, "SYS_PACCT": {}, "SYS_PTRACE": {},
+	"SYS_RAWIO": {}, "SYS_RESOURCE": {}, "SYS_TIME": {}, "SYS_TTY_CONFIG": {},
+	"WAKE_ALARM": {},
+}
+
+// ulimits are the resource names that may be set on a container
+var ulimits = map[string]struct{}{
+	"core": {}, "cpu": {}, "data": {}, "fsize": {}, "locks": {}, "memlock": {},
+	"msgqueue": {}, "nice": {}, "nofile": {}, "nproc": {}, "rss": {},
+	"rtprio": {}, "rttime": {}, "sigpending": {}, "stack": {},
+}
+
+var (
+	userRegex    = regexp.MustCompile(`^([a-z_][a-z0-9_.-]*|[0-9]+)(:([a-z_][a-z0-9_.-]*|[0-9]+))?$`)
+	seccompRegex = regexp.MustCompile(`^[a-zA-Z0-9_-][a-zA-Z0-9_.-]*$`)
+)
+
+//ValidEntity makes sure the security profile can be applied to a container
+func (sp SecurityProfile) ValidEntity() error {
+	vErr := validation.NewValidationError()
+	for _, caps := range [][]string{sp.CapAdd, sp.CapDrop} {
+		for _, c := range caps {
+			name := strings.TrimPrefix(strings.ToUpper(c), "CAP_")
+			if _, ok := capabilities[name]; !ok {
+				vErr.AddViolation(fmt.Sprintf("unknown capability %s", c))
+			}
+		}
+	}
+	for mount := range sp.Tmpfs {
+		if !path.IsAbs(mount) || path.Clean(mount) == "/" {
+			vErr.AddViolation(fmt.Sprintf("tmpfs mount %s must be an absolute path below /", mount))
+		}
+	}
+	if sp.User != "" && !userRegex.MatchString(sp.User) {
+		vErr.AddViolation(fmt.Sprintf("invalid user %s", sp.User))
+	}
+	if sp.PidsLimit < 0 {
+		vErr.AddViolation(fmt.Sprintf("pids limit %d must not be negative", sp.PidsLimit))
+	}
+	names := make(map[string]struct{})
+	for _, ulimit := range sp.Ulimits {
+		if _, ok := ulimits[ulimit.Name]; !ok {
+			vErr.AddViolation(fmt.Sprintf("unknown ulimit %s", ulimit.Name))
+		} else if _, ok := names[ulimit.Name]; ok {
+			vErr.AddViolation(fmt.Sprintf("duplicate ulimit %s", ulimit.Name))
+		}
+		names[ulimit.Name] = struct{}{}
+		if ulimit.Soft < 0 || ulimit.Hard < 0 || ulimit.Soft > ulimit.Hard {
+			vErr.AddViolation(fmt.Sprintf("ulimit %s: soft limit %d and hard limit %d must satisfy 0 <= soft <= hard", ulimit.Name, ulimit.Soft, ulimit.Hard))
+		}
+	}
+	if sp.SeccompProfile != "" && !seccompRegex.MatchString(sp.SeccompProfile) {
+		vErr.AddViolation(fmt.Sprintf("invalid seccomp profile name %s", sp.SeccompProfile))
+	}
+	if vErr.HasError() {
+		return vErr
+	}
+	return nil
+}
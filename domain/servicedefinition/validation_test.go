@@ -17,11 +17,13 @@ package servicedefinition_test
 
 import (
 	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain"
 	. "github.com/control-center/serviced/domain/servicedefinition"
 	. "github.com/control-center/serviced/domain/servicedefinition/testutils"
 
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestServiceDefinitionValidate(t *testing.T) {
@@ -137,3 +139,166 @@ func TestServiceDefinitionEmptyEndpointName(t *testing.T) {
 		t.Errorf("Unexpected Error %v", err)
 	}
 }
+
+func TestServiceDefinitionSecurityProfile(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Security = SecurityProfile{
+		CapAdd:          []string{"NET_ADMIN", "cap_sys_ptrace"},
+		CapDrop:         []string{"ALL"},
+		ReadOnlyRootFS:  true,
+		Tmpfs:           map[string]string{"/run": "rw,size=64m"},
+		User:            "1000:1000",
+		NoNewPrivileges: true,
+		PidsLimit:       256,
+		Ulimits:         []Ulimit{{Name: "nofile", Soft: 1024, Hard: 4096}},
+		SeccompProfile:  "strict",
+	}
+	if err := sd.ValidEntity(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sd.Services[0].Security = SecurityProfile{
+		CapAdd:         []string{"FLY"},
+		Tmpfs:          map[string]string{"run": ""},
+		User:           "Bad User",
+		PidsLimit:      -1,
+		Ulimits:        []Ulimit{{Name: "nofile", Soft: 10, Hard: 1}, {Name: "nofile"}, {Name: "files"}},
+		SeccompProfile: "../etc/profile",
+	}
+	err := sd.ValidEntity()
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	for _, msg := range []string{
+		"unknown capability FLY",
+		"tmpfs mount run must be an absolute path",
+		"invalid user Bad User",
+		"pids limit -1 must not be negative",
+		"ulimit nofile: soft limit 10 and hard limit 1",
+		"duplicate ulimit nofile",
+		"unknown ulimit files",
+		"invalid seccomp profile name ../etc/profile",
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("Expected %q in error %q", msg, err)
+		}
+	}
+}
+
+func TestServiceDefinitionReadOnlyConfigFiles(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Security = SecurityProfile{
+		ReadOnlyRootFS: true,
+		Tmpfs:          map[string]string{"/etc/app": ""},
+	}
+	sd.Services[0].Volumes = []Volume{{ContainerPath: "/data"}}
+	sd.Services[0].ConfigFiles = map[string]ConfigFile{
+		"/etc/app/app.conf": {Filename: "/etc/app/app.conf"},
+		"/data/extra.conf":  {Filename: "/data/extra.conf"},
+		"/tmp/scratch.conf": {Filename: "/tmp/scratch.conf"},
+	}
+	if err := sd.ValidEntity(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sd.Services[0].ConfigFiles["/etc/other.conf"] = ConfigFile{Filename: "/etc/other.conf"}
+	err := sd.ValidEntity()
+	if err == nil || !strings.Contains(err.Error(), "config file /etc/other.conf must be on a tmpfs mount or volume") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestServiceDefinitionCPULimits(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].CPUQuota = 1.5
+	sd.Services[0].CPUSet = "0-3,6"
+	if err := sd.ValidEntity(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sd.Services[0].CPUQuota = -1
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "cpu quota -1 must not be negative") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	sd.Services[0].CPUQuota = 0
+	for _, cpuset := range []string{"3-1", "a", "1,,2", "-1"} {
+		sd.Services[0].CPUSet = cpuset
+		if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "invalid cpuset "+cpuset) {
+			t.Errorf("Unexpected error for %s: %v", cpuset, err)
+		}
+	}
+}
+
+func TestParseCPUSet(t *testing.T) {
+	cpus, err := ParseCPUSet("6,0-3,2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []int{0, 1, 2, 3, 6}
+	if len(cpus) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, cpus)
+	}
+	for i := range expected {
+		if cpus[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, cpus)
+		}
+	}
+	if cpus, err := ParseCPUSet(""); err != nil || len(cpus) != 0 {
+		t.Errorf("Expected no cpus, got %v (%v)", cpus, err)
+	}
+}
+
+func TestServiceDefinitionAutoScale(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].AutoScale = AutoScale{Metric: AutoScaleCPU, Target: 0.5}
+	sd.Services[0].Instances = domain.MinMax{Min: 1, Max: 5}
+	if err := sd.ValidEntity(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sd.Services[0].AutoScale.Target = 0
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "autoscale target 0 must be positive") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	sd.Services[0].AutoScale.Target = 0.5
+	sd.Services[0].AutoScale.ScaleDownCooldown = -1
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "autoscale window and cooldowns must not be negative") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	sd.Services[0].AutoScale.ScaleDownCooldown = 0
+	sd.Services[0].Instances = domain.MinMax{Min: 1}
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "autoscale requires a maximum number of instances") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestAutoScaleDesiredInstances(t *testing.T) {
+	a := AutoScale{Metric: "queue.depth", Target: 100}
+	limits := domain.MinMax{Min: 2, Max: 10}
+	for _, tc := range []struct {
+		instances int
+		average   float64
+		expected  int
+	}{
+		{4, 100, 4},   // on target
+		{4, 150, 6},   // 600 total needs 6 instances
+		{4, 101, 5},   // rounds up
+		{4, 10, 2},    // held at the minimum
+		{4, 1000, 10}, // held at the maximum
+	} {
+		if actual := a.DesiredInstances(tc.instances, tc.average, limits); actual != tc.expected {
+			t.Errorf("%d instances averaging %v: expected %d, got %d", tc.instances, tc.average, tc.expected, actual)
+		}
+	}
+
+	if a.GetCooldown(true) != DefaultAutoScaleUpCooldown || a.GetCooldown(false) != DefaultAutoScaleDownCooldown {
+		t.Errorf("Expected default cooldowns")
+	}
+	a.ScaleUpCooldown = 60
+	if a.GetCooldown(true) != time.Minute {
+		t.Errorf("Expected a cooldown of a minute, got %s", a.GetCooldown(true))
+	}
+}
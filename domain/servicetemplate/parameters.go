@@ -0,0 +1,259 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicetemplate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/validation"
+)
+
+// Types of values that a template parameter may hold
+const (
+	ParamString = "string"
+	ParamInt    = "int"
+	ParamBool   = "bool"
+)
+
+var (
+	// paramNamePattern matches a valid parameter name
+	paramNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	// paramPattern matches a parameter placeholder, e.g. ${param:dbhost}
+	paramPattern = regexp.MustCompile(`\$\{param:([A-Za-z0-9_.-]+)\}`)
+)
+
+// TemplateParameter is a site-specific value declared by a template and
+// supplied when the template is deployed.  Parameters are referenced from
+// the Context, Environment and ConfigFiles of a service definition with
+// ${param:NAME}, and may set the default instance count of a service via
+// ServiceDefinition.InstancesParameter.
+type TemplateParameter struct {
+	Name        string // Name used to reference the parameter
+	Type        string // Type of the value; one of "string" (the default), "int" or "bool"
+	Default     string // Value used when none is supplied at deploy time
+	Required    bool   // Deployment fails if no value is supplied and there is no default
+	Description string // Meaningful description of the parameter
+}
+
+// ValidEntity checks the declaration of a template parameter
+func (p TemplateParameter) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("TemplateParameter.Name", p.Name))
+	if p.Name != "" && !paramNamePattern.MatchString(p.Name) {
+		violations.AddViolation(fmt.Sprintf("invalid template parameter name %q", p.Name))
+	}
+	if err := validation.StringIn(p.Type, "", ParamString, ParamInt, ParamBool); err != nil {
+		violations.AddViolation(fmt.Sprintf("template parameter %s: invalid type: %s", p.Name, err))
+	} else if p.Default != "" {
+		if _, err := p.convert(p.Default); err != nil {
+			violations.AddViolation(fmt.Sprintf("template parameter %s: invalid default: %s", p.Name, err))
+		}
+	}
+	if violations.HasError() {
+		return violations
+	}
+	return nil
+}
+
+// convert parses a raw parameter value into its declared type
+func (p TemplateParameter) convert(value string) (interface{}, error) {
+	switch p.Type {
+	case ParamInt:
+		return strconv.Atoi(value)
+	case ParamBool:
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// ResolveParameters merges the supplied values with the defaults of the
+// template's declared parameters.  It returns an error if a value is supplied
+// for an undeclared parameter, a required parameter has no value or a value
+// does not match its declared type.
+func (st *ServiceTemplate) ResolveParameters(values map[string]string) (map[string]string, error) {
+	declared := make(map[string]TemplateParameter)
+	for _, p := range st.Parameters {
+		declared[p.Name] = p
+	}
+
+	violations := validation.NewValidationError()
+	var unknown []string
+	for name := range values {
+		if _, ok := declared[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		violations.AddViolation(fmt.Sprintf("unknown template parameter %s", name))
+	}
+
+	resolved := make(map[string]string)
+	for _, p := range st.Parameters {
+		value, ok := values[p.Name]
+		if !ok || value == "" {
+			value = p.Default
+		}
+		if value == "" {
+			if p.Required {
+				violations.AddViolation(fmt.Sprintf("missing value for required template parameter %s", p.Name))
+				continue
+			} else if p.Type == ParamInt || p.Type == ParamBool {
+				// leave it unset so that any reference to it fails
+				continue
+			}
+		}
+		if _, err := p.convert(value); err != nil {
+			violations.AddViolation(fmt.Sprintf("invalid value %q for template parameter %s of type %s", value, p.Name, p.Type))
+			continue
+		}
+		resolved[p.Name] = value
+	}
+
+	if violations.HasError() {
+		return nil, violations
+	}
+	return resolved, nil
+}
+
+// ApplyParameters substitutes resolved parameter values into the
+// service definitions and config files of the template.
+func (st *ServiceTemplate) ApplyParameters(values map[string]string) error {
+	typed := make(map[string]interface{})
+	for _, p := range st.Parameters {
+		if value, ok := values[p.Name]; ok {
+			v, err := p.convert(value)
+			if err != nil {
+				return err
+			}
+			typed[p.Name] = v
+		}
+	}
+
+	subst := &substituter{values: values, typed: typed}
+	for name, cf := range st.ConfigFiles {
+		cf.Content = subst.String(cf.Content)
+		st.ConfigFiles[name] = cf
+	}
+	for i := range st.Services {
+		if err := subst.ServiceDefinition(&st.Services[i]); err != nil {
+			return err
+		}
+	}
+	return subst.Err()
+}
+
+// substituter replaces parameter placeholders and remembers any that do not
+// have a value
+type substituter struct {
+	values  map[string]string
+	typed   map[string]interface{}
+	missing map[string]struct{}
+}
+
+// String replaces every placeholder in s with its value
+func (s *substituter) String(str string) string {
+	return paramPattern.ReplaceAllStringFunc(str, func(match string) string {
+		name := paramPattern.FindStringSubmatch(match)[1]
+		if value, ok := s.values[name]; ok {
+			return value
+		}
+		if s.missing == nil {
+			s.missing = make(map[string]struct{})
+		}
+		s.missing[name] = struct{}{}
+		return match
+	})
+}
+
+// Value replaces placeholders in strings nested within a context value.  A
+// string that consists of exactly one placeholder is replaced by the typed
+// value of the parameter.
+func (s *substituter) Value(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if m := paramPattern.FindStringSubmatch(v); m != nil && m[0] == v {
+			if typed, ok := s.typed[m[1]]; ok {
+				return typed
+			}
+		}
+		return s.String(v)
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = s.Value(val)
+		}
+		return v
+	case []interface{}:
+		for i, val := range v {
+			v[i] = s.Value(val)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// ServiceDefinition substitutes parameters into a service definition and
+// all of its children
+func (s *substituter) ServiceDefinition(sd *servicedefinition.ServiceDefinition) error {
+	for key, value := range sd.Context {
+		sd.Context[key] = s.Value(value)
+	}
+	for i, env := range sd.Environment {
+		sd.Environment[i] = s.String(env)
+	}
+	for name, cf := range sd.ConfigFiles {
+		cf.Content = s.String(cf.Content)
+		sd.ConfigFiles[name] = cf
+	}
+	if name := sd.InstancesParameter; name != "" {
+		typed, ok := s.typed[name]
+		if !ok {
+			return fmt.Errorf("service definition %s: no value for instances parameter %s", sd.Name, name)
+		}
+		count, ok := typed.(int)
+		if !ok {
+			return fmt.Errorf("service definition %s: instances parameter %s is not an int", sd.Name, name)
+		}
+		sd.Instances.Default = count
+		if err := sd.Instances.Validate(); err != nil {
+			return fmt.Errorf("service definition %s: %s", sd.Name, err)
+		}
+	}
+	for i := range sd.Services {
+		if err := s.ServiceDefinition(&sd.Services[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Err reports placeholders that referenced parameters without a value
+func (s *substituter) Err() error {
+	if len(s.missing) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(s.missing))
+	for name := range s.missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("no value for template parameters: %s", strings.Join(names, ", "))
+}
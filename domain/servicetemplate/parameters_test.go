@@ -0,0 +1,175 @@
+// Copyright 2016 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package servicetemplate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+func parameterTemplate() *ServiceTemplate {
+	return &ServiceTemplate{
+		ID: "test_id",
+		Parameters: []TemplateParameter{
+			{Name: "dbhost", Required: true},
+			{Name: "collectors", Type: ParamInt, Default: "2"},
+			{Name: "debug", Type: ParamBool, Default: "false"},
+		},
+		Services: []servicedefinition.ServiceDefinition{
+			{
+				Name:        "app",
+				Launch:      "auto",
+				Environment: []string{"DB_HOST=${param:dbhost}"},
+				Context:     map[string]interface{}{"debug": "${param:debug}", "url": "jdbc://${param:dbhost}/app"},
+				Services: []servicedefinition.ServiceDefinition{
+					{
+						Name:               "collector",
+						Launch:             "auto",
+						Instances:          domain.MinMax{Min: 1, Max: 10, Default: 1},
+						InstancesParameter: "collectors",
+						ConfigFiles: map[string]servicedefinition.ConfigFile{
+							"/etc/collector.conf": {Filename: "/etc/collector.conf", Content: "db={{.Name}}@${param:dbhost}"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestResolveParameters(t *testing.T) {
+	st := parameterTemplate()
+	values, err := st.ResolveParameters(map[string]string{"dbhost": "db1", "collectors": "5"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{"dbhost": "db1", "collectors": "5", "debug": "false"}
+	if len(values) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, values)
+	}
+	for k, v := range expected {
+		if values[k] != v {
+			t.Errorf("Expected %s=%s, got %s", k, v, values[k])
+		}
+	}
+}
+
+func TestResolveParametersErrors(t *testing.T) {
+	st := parameterTemplate()
+	_, err := st.ResolveParameters(map[string]string{"collectors": "many", "other": "x"})
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	for _, msg := range []string{
+		"unknown template parameter other",
+		"missing value for required template parameter dbhost",
+		`invalid value "many" for template parameter collectors of type int`,
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("Expected %q in error %q", msg, err)
+		}
+	}
+}
+
+func TestApplyParameters(t *testing.T) {
+	st := parameterTemplate()
+	values, err := st.ResolveParameters(map[string]string{"dbhost": "db1", "collectors": "5", "debug": "true"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := st.ApplyParameters(values); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	app := st.Services[0]
+	if app.Environment[0] != "DB_HOST=db1" {
+		t.Errorf("Unexpected environment %s", app.Environment[0])
+	}
+	if app.Context["debug"] != true {
+		t.Errorf("Expected typed context value, got %#v", app.Context["debug"])
+	}
+	if app.Context["url"] != "jdbc://db1/app" {
+		t.Errorf("Unexpected context value %v", app.Context["url"])
+	}
+	collector := app.Services[0]
+	if collector.Instances.Default != 5 {
+		t.Errorf("Expected 5 instances, got %d", collector.Instances.Default)
+	}
+	if content := collector.ConfigFiles["/etc/collector.conf"].Content; content != "db={{.Name}}@db1" {
+		t.Errorf("Unexpected config file content %s", content)
+	}
+}
+
+func TestApplyParametersInstancesOutOfRange(t *testing.T) {
+	st := parameterTemplate()
+	values, err := st.ResolveParameters(map[string]string{"dbhost": "db1", "collectors": "50"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := st.ApplyParameters(values); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestApplyParametersUndeclared(t *testing.T) {
+	st := parameterTemplate()
+	st.Services[0].Environment = append(st.Services[0].Environment, "PORT=${param:port}")
+	values, err := st.ResolveParameters(map[string]string{"dbhost": "db1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := st.ApplyParameters(values); err == nil || !strings.Contains(err.Error(), "port") {
+		t.Errorf("Expected error for undeclared parameter, got %v", err)
+	}
+}
+
+func TestServiceTemplateValidateInstancesParameter(t *testing.T) {
+	template := parameterTemplate()
+	if err := template.ValidEntity(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	template.Services[0].InstancesParameter = "dbhost"
+	err := template.ValidEntity()
+	if err == nil || !strings.Contains(err.Error(), "instances parameter dbhost is not a declared int parameter") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestServiceTemplateValidateParameters(t *testing.T) {
+	template := ServiceTemplate{}
+	template.ID = "test_id"
+	template.Parameters = []TemplateParameter{
+		{Name: "a"},
+		{Name: "a"},
+		{Name: "b", Type: "float"},
+		{Name: "c", Type: ParamInt, Default: "x"},
+	}
+	err := template.ValidEntity()
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	for _, msg := range []string{
+		"duplicate template parameter found: a",
+		"template parameter b: invalid type",
+		"template parameter c: invalid default",
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("Expected %q in error %q", msg, err)
+		}
+	}
+}
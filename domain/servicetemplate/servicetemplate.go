@@ -30,9 +30,10 @@ var plog = logging.PackageLogger()
 
 // A request to deploy a service template
 type ServiceTemplateDeploymentRequest struct {
-	PoolID       string // Pool Id to deploy service into
-	TemplateID   string // Id of template to be deployed
-	DeploymentID string // Unique id of the instance of this template
+	PoolID       string            // Pool Id to deploy service into
+	TemplateID   string            // Id of template to be deployed
+	DeploymentID string            // Unique id of the instance of this template
+	Parameters   map[string]string // Values for the template's declared parameters
 }
 
 // ServiceTemplate type to hold service definitions
@@ -43,6 +44,7 @@ type ServiceTemplate struct {
 	Description string                                  // Meaningful description of service
 	Services    []servicedefinition.ServiceDefinition   // Child services
 	ConfigFiles map[string]servicedefinition.ConfigFile // Config file templates
+	Parameters  []TemplateParameter                     // Parameters supplied at deploy time
 	datastore.VersionedEntity
 }
 
@@ -66,6 +68,9 @@ func (a *ServiceTemplate) Equals(b *ServiceTemplate) bool {
 	if !reflect.DeepEqual(a.ConfigFiles, b.ConfigFiles) {
 		return false
 	}
+	if !reflect.DeepEqual(a.Parameters, b.Parameters) {
+		return false
+	}
 	return true
 }
 
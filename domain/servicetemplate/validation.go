@@ -37,11 +37,28 @@ func (st *ServiceTemplate) ValidEntity() error {
 		}
 	}
 
+	//parameter names must be unique
+	params := make(map[string]TemplateParameter)
+	for _, p := range st.Parameters {
+		if err := p.ValidEntity(); err != nil {
+			violations.Add(err)
+		}
+		if _, found := params[p.Name]; found {
+			violations.AddViolation(fmt.Sprintf("duplicate template parameter found: %s", p.Name))
+		}
+		params[p.Name] = p
+	}
+
 	//keep track of seen vhosts
 	vhosts := make(map[string]struct{})
 
 	//grab the vhost from every endpoing
 	visit := func(sd *servicedefinition.ServiceDefinition) error {
+		if name := sd.InstancesParameter; name != "" {
+			if p, found := params[name]; !found || p.Type != ParamInt {
+				return fmt.Errorf("instances parameter %s is not a declared int parameter; ServiceDefinition %s", name, sd)
+			}
+		}
 		for _, ep := range sd.Endpoints {
 			for _, vhost := range ep.VHostList {
 				if _, found := vhosts[vhost.Name]; found {
@@ -0,0 +1,54 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshotschedule
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/logging"
+)
+
+var (
+	kind          = "snapshotschedule"
+	plog          = logging.PackageLogger()
+	mappingString = fmt.Sprintf(`
+{
+     "%s": {
+      "properties":{
+        "TenantID":       {"type": "string", "index":"not_analyzed"},
+        "Interval":       {"type": "long", "index":"no"},
+        "Cron":           {"type": "string", "index":"no"},
+        "Retention":      {
+          "properties": {
+            "Hourly":     {"type": "long", "index":"no"},
+            "Daily":      {"type": "long", "index":"no"},
+            "Weekly":     {"type": "long", "index":"no"}
+          }
+        },
+        "LastRun":        {"type": "date", "format" : "dateOptionalTime"},
+        "CreatedBy":      {"type": "string", "index":"not_analyzed"}
+      }
+    }
+}
+`, kind)
+	// MAPPING is the elastic mapping for a snapshot schedule
+	MAPPING, mappingError = elastic.NewMapping(mappingString)
+)
+
+func init() {
+	if mappingError != nil {
+		plog.WithError(mappingError).Fatal("error creating mapping for the snapshotschedule object")
+	}
+}
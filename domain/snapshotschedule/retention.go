@@ -0,0 +1,81 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshotschedule
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Snapshot is what retention needs to know about a snapshot
+type Snapshot struct {
+	ID      string
+	Created time.Time
+	Tagged  bool
+}
+
+type byNewest []Snapshot
+
+func (s byNewest) Len() int           { return len(s) }
+func (s byNewest) Less(i, j int) bool { return s[i].Created.After(s[j].Created) }
+func (s byNewest) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// Expired returns the ids of the snapshots that the retention rules do not
+// keep, oldest first.  If there are no rules, nothing expires.
+func (r Retention) Expired(snapshots []Snapshot) []string {
+	if r.IsZero() {
+		return nil
+	}
+	untagged := []Snapshot{}
+	for _, s := range snapshots {
+		if !s.Tagged {
+			untagged = append(untagged, s)
+		}
+	}
+	sort.Sort(byNewest(untagged))
+
+	keep := make(map[string]bool)
+	rules := []struct {
+		count  int
+		period func(time.Time) string
+	}{
+		{r.Hourly, func(t time.Time) string { return t.Format("2006-01-02 15") }},
+		{r.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{r.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-%d", year, week)
+		}},
+	}
+	for _, rule := range rules {
+		seen := make(map[string]bool)
+		for _, s := range untagged {
+			if len(seen) >= rule.count {
+				break
+			}
+			if p := rule.period(s.Created); !seen[p] {
+				seen[p] = true
+				keep[s.ID] = true
+			}
+		}
+	}
+
+	var expired []string
+	for i := len(untagged) - 1; i >= 0; i-- {
+		if !keep[untagged[i].ID] {
+			expired = append(expired, untagged[i].ID)
+		}
+	}
+	return expired
+}
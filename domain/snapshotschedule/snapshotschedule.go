@@ -0,0 +1,122 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshotschedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// Message is the message of the snapshots taken by a schedule.  Only those
+// snapshots are subject to the schedule's retention rules.
+const Message = "Scheduled snapshot"
+
+// SnapshotSchedule is when the master snapshots a tenant and which of the
+// tenant's snapshots it keeps.  A tenant has at most one schedule.
+type SnapshotSchedule struct {
+	TenantID  string        // Tenant that is snapshotted
+	Interval  time.Duration // Time between snapshots, if not scheduled by Cron
+	Cron      string        // Cron expression of when to snapshot, if no Interval
+	Retention Retention     // Snapshots of the tenant to keep
+	LastRun   time.Time     // Time the schedule last ran, or was set
+	CreatedBy string        // User that set the schedule
+	datastore.VersionedEntity
+}
+
+// Next returns the time the schedule is due to run after it last ran.  Cron
+// expressions are evaluated in the local time zone of the master, as they are
+// by the system's cron.
+func (s *SnapshotSchedule) Next() (time.Time, error) {
+	if s.Cron != "" {
+		spec, err := ParseCron(s.Cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+		next, ok := spec.Next(s.LastRun.In(time.Local))
+		if !ok {
+			return time.Time{}, fmt.Errorf("cron expression %q never matches", s.Cron)
+		}
+		return next, nil
+	}
+	return s.LastRun.Add(s.Interval), nil
+}
+
+// Due returns true if the schedule should run at the given time
+func (s *SnapshotSchedule) Due(now time.Time) bool {
+	next, err := s.Next()
+	return err == nil && !next.IsZero() && !next.After(now)
+}
+
+// Frequency describes how often the schedule runs
+func (s *SnapshotSchedule) Frequency() string {
+	if s.Cron != "" {
+		return s.Cron
+	}
+	return "every " + s.Interval.String()
+}
+
+// Retention is how many scheduled snapshots of a tenant to keep.  For each
+// period, the newest snapshot of each of the most recent N periods that have
+// one is kept.  Tagged snapshots are always kept, and do not count towards any
+// period.  Snapshots that the schedule did not take are purged by age.
+type Retention struct {
+	Hourly int
+	Daily  int
+	Weekly int
+}
+
+// IsZero returns true if the retention has no rules, in which case snapshots
+// are purged by age like those of tenants without a schedule.
+func (r Retention) IsZero() bool {
+	return r.Hourly == 0 && r.Daily == 0 && r.Weekly == 0
+}
+
+// String describes the retention rules
+func (r Retention) String() string {
+	if r.IsZero() {
+		return "snapshot-ttl"
+	}
+	var rules []string
+	if r.Hourly > 0 {
+		rules = append(rules, fmt.Sprintf("%d hourly", r.Hourly))
+	}
+	if r.Daily > 0 {
+		rules = append(rules, fmt.Sprintf("%d daily", r.Daily))
+	}
+	if r.Weekly > 0 {
+		rules = append(rules, fmt.Sprintf("%d weekly", r.Weekly))
+	}
+	return strings.Join(rules, ", ")
+}
+
+// GetType return the SnapshotSchedule's type
+// It returns the type as a string
+func GetType() string {
+	return kind
+}
+
+// GetType returns the SnapshotSchedule instance's type
+// It returns the type as a string
+func (s *SnapshotSchedule) GetType() string {
+	return GetType()
+}
+
+// GetID return a SnapshotSchedule instance's ID, which is its tenant's ID
+// It returns the ID as a string
+func (s *SnapshotSchedule) GetID() string {
+	return s.TenantID
+}
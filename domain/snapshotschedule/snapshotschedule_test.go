@@ -0,0 +1,198 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package snapshotschedule
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type scheduleSuite struct{}
+
+var _ = Suite(&scheduleSuite{})
+
+func at(value string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func (s *scheduleSuite) TestParseCron(c *C) {
+	for _, bad := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseCron(bad)
+		c.Check(err, NotNil, Commentf("expected %q to be invalid", bad))
+	}
+	_, err := ParseCron("0,30 6-18/2 1-15 */3 1-5")
+	c.Assert(err, IsNil)
+}
+
+func (s *scheduleSuite) TestCronNext(c *C) {
+	for _, test := range []struct {
+		expr, from, next string
+	}{
+		{"* * * * *", "2017-03-01 10:15", "2017-03-01 10:16"},
+		{"0 6 * * *", "2017-03-01 10:15", "2017-03-02 06:00"},
+		{"0 6 * * *", "2017-03-01 05:59", "2017-03-01 06:00"},
+		{"*/15 * * * *", "2017-03-01 10:15", "2017-03-01 10:30"},
+		{"30 2 1 * *", "2017-12-15 00:00", "2018-01-01 02:30"},
+		{"0 0 * * 0", "2017-03-01 00:00", "2017-03-05 00:00"},
+		{"0 0 * * 7", "2017-03-01 00:00", "2017-03-05 00:00"},
+		// either day field matches when both are restricted
+		{"0 0 15 * 0", "2017-03-06 00:00", "2017-03-12 00:00"},
+		{"0 0 29 2 *", "2017-01-01 00:00", "2020-02-29 00:00"},
+	} {
+		spec, err := ParseCron(test.expr)
+		c.Assert(err, IsNil)
+		next, ok := spec.Next(at(test.from))
+		c.Check(ok, Equals, true)
+		c.Check(next, Equals, at(test.next), Commentf("%s from %s", test.expr, test.from))
+	}
+
+	spec, err := ParseCron("0 0 30 2 *")
+	c.Assert(err, IsNil)
+	next, ok := spec.Next(at("2017-01-01 00:00"))
+	c.Check(ok, Equals, false)
+	c.Check(next.IsZero(), Equals, true)
+}
+
+func (s *scheduleSuite) TestCronMinGap(c *C) {
+	for _, test := range []struct {
+		expr string
+		gap  time.Duration
+	}{
+		{"* * * * *", time.Minute},
+		{"*/15 * * * *", 15 * time.Minute},
+		{"0 6 * * *", 24 * time.Hour},
+		{"0,50 0,23 * * *", 10 * time.Minute},
+		{"0 0 * * 1,3", 48 * time.Hour},
+	} {
+		spec, err := ParseCron(test.expr)
+		c.Assert(err, IsNil)
+		gap, ok := spec.MinGap(at("2017-01-01 00:00"))
+		c.Check(ok, Equals, true)
+		c.Check(gap, Equals, test.gap, Commentf("%s", test.expr))
+	}
+
+	spec, err := ParseCron("0 0 30 2 *")
+	c.Assert(err, IsNil)
+	_, ok := spec.MinGap(at("2017-01-01 00:00"))
+	c.Check(ok, Equals, false)
+}
+
+func (s *scheduleSuite) TestDue(c *C) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.UTC
+
+	interval := &SnapshotSchedule{TenantID: "tenant", Interval: 4 * time.Hour, LastRun: at("2017-03-01 06:00")}
+	c.Check(interval.Due(at("2017-03-01 09:59")), Equals, false)
+	c.Check(interval.Due(at("2017-03-01 10:00")), Equals, true)
+	c.Check(interval.Frequency(), Equals, "every 4h0m0s")
+
+	cron := &SnapshotSchedule{TenantID: "tenant", Cron: "0 6 * * *", LastRun: at("2017-03-01 06:00")}
+	c.Check(cron.Due(at("2017-03-02 05:59")), Equals, false)
+	c.Check(cron.Due(at("2017-03-02 06:00")), Equals, true)
+	c.Check(cron.Frequency(), Equals, "0 6 * * *")
+
+	broken := &SnapshotSchedule{TenantID: "tenant", Cron: "bad"}
+	c.Check(broken.Due(at("2017-03-02 06:00")), Equals, false)
+
+	never := &SnapshotSchedule{TenantID: "tenant", Cron: "0 0 30 2 *", LastRun: at("2017-03-01 06:00")}
+	c.Check(never.Due(at("2017-03-02 06:00")), Equals, false)
+}
+
+func (s *scheduleSuite) TestDueLocalTime(c *C) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.FixedZone("UTC+2", 2*60*60)
+
+	// 06:00 on the master is 04:00 UTC
+	cron := &SnapshotSchedule{TenantID: "tenant", Cron: "0 6 * * *", LastRun: at("2017-03-01 04:00")}
+	c.Check(cron.Due(at("2017-03-02 03:59")), Equals, false)
+	c.Check(cron.Due(at("2017-03-02 04:00")), Equals, true)
+}
+
+func (s *scheduleSuite) TestValidEntity(c *C) {
+	valid := []SnapshotSchedule{
+		{TenantID: "tenant", Interval: time.Hour},
+		{TenantID: "tenant", Cron: "0 6 * * *", Retention: Retention{Daily: 7}},
+		{TenantID: "tenant", Cron: "*/15 * * * *"},
+	}
+	for _, schedule := range valid {
+		c.Check(schedule.ValidEntity(), IsNil)
+	}
+	invalid := []SnapshotSchedule{
+		{Interval: time.Hour},
+		{TenantID: "tenant"},
+		{TenantID: "tenant", Interval: time.Minute},
+		{TenantID: "tenant", Interval: time.Hour, Cron: "0 6 * * *"},
+		{TenantID: "tenant", Cron: "0 6 * *"},
+		{TenantID: "tenant", Cron: "0 0 30 2 *"},
+		{TenantID: "tenant", Cron: "* * * * *"},
+		{TenantID: "tenant", Cron: "0,10 * * * *"},
+		{TenantID: "tenant", Interval: time.Hour, Retention: Retention{Hourly: -1}},
+	}
+	for _, schedule := range invalid {
+		c.Check(schedule.ValidEntity(), NotNil, Commentf("%+v", schedule))
+	}
+}
+
+func (s *scheduleSuite) TestRetention(c *C) {
+	c.Check(Retention{}.IsZero(), Equals, true)
+	c.Check(Retention{}.String(), Equals, "snapshot-ttl")
+	c.Check(Retention{Hourly: 24, Weekly: 4}.String(), Equals, "24 hourly, 4 weekly")
+
+	snapshots := []Snapshot{
+		{ID: "mon-old", Created: at("2017-02-27 06:00")},
+		{ID: "tue", Created: at("2017-02-28 06:00")},
+		{ID: "wed-tagged", Created: at("2017-03-01 06:00"), Tagged: true},
+		{ID: "wed-0600", Created: at("2017-03-01 06:00")},
+		{ID: "wed-1000", Created: at("2017-03-01 10:00")},
+		{ID: "wed-1030", Created: at("2017-03-01 10:30")},
+		{ID: "wed-1100", Created: at("2017-03-01 11:00")},
+		{ID: "last-week", Created: at("2017-02-20 06:00")},
+		{ID: "two-weeks", Created: at("2017-02-13 06:00")},
+	}
+
+	// Nothing expires without rules
+	c.Check(Retention{}.Expired(snapshots), HasLen, 0)
+
+	// The newest snapshot of each of the last 2 hours
+	c.Check(Retention{Hourly: 2}.Expired(snapshots), DeepEquals,
+		[]string{"two-weeks", "last-week", "mon-old", "tue", "wed-0600", "wed-1000"})
+
+	// The newest snapshot of each of the last 2 days, and of the last 2 weeks
+	c.Check(Retention{Daily: 2, Weekly: 2}.Expired(snapshots), DeepEquals,
+		[]string{"two-weeks", "mon-old", "wed-0600", "wed-1000", "wed-1030"})
+}
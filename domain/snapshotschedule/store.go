@@ -0,0 +1,96 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshotschedule
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// Store is the database for snapshot schedules
+type Store interface {
+	// Get the SnapshotSchedule of a tenant. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, tenantID string) (*SnapshotSchedule, error)
+
+	// Put adds or updates a SnapshotSchedule
+	Put(ctx datastore.Context, s *SnapshotSchedule) error
+
+	// Delete removes a SnapshotSchedule if it exists
+	Delete(ctx datastore.Context, tenantID string) error
+
+	// GetSnapshotSchedules returns all SnapshotSchedules
+	GetSnapshotSchedules(ctx datastore.Context) ([]*SnapshotSchedule, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore creates a Store for SnapshotSchedules
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Get the SnapshotSchedule of a tenant.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, tenantID string) (*SnapshotSchedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SnapshotScheduleStore.Get"))
+	val := &SnapshotSchedule{}
+	if err := s.ds.Get(ctx, Key(tenantID), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates a SnapshotSchedule
+func (s *storeImpl) Put(ctx datastore.Context, val *SnapshotSchedule) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SnapshotScheduleStore.Put"))
+	return s.ds.Put(ctx, Key(val.TenantID), val)
+}
+
+// Delete removes a SnapshotSchedule
+func (s *storeImpl) Delete(ctx datastore.Context, tenantID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SnapshotScheduleStore.Delete"))
+	return s.ds.Delete(ctx, Key(tenantID))
+}
+
+// GetSnapshotSchedules returns all SnapshotSchedules
+func (s *storeImpl) GetSnapshotSchedules(ctx datastore.Context) ([]*SnapshotSchedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SnapshotScheduleStore.GetSnapshotSchedules"))
+	q := datastore.NewQuery(ctx)
+	search := search.Search("controlplane").Type(kind).Size("50000")
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting and putting SnapshotSchedules
+func Key(tenantID string) datastore.Key {
+	return datastore.NewKey(kind, strings.TrimSpace(tenantID))
+}
+
+func convert(results datastore.Results) ([]*SnapshotSchedule, error) {
+	schedules := make([]*SnapshotSchedule, results.Len())
+	for idx := range schedules {
+		schedule := SnapshotSchedule{}
+		if err := results.Get(idx, &schedule); err != nil {
+			return []*SnapshotSchedule{}, err
+		}
+		schedules[idx] = &schedule
+	}
+	return schedules, nil
+}
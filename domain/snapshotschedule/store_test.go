@@ -0,0 +1,75 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package snapshotschedule
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+func (s *S) Test_SnapshotScheduleCRUD(c *C) {
+	expected := &SnapshotSchedule{
+		TenantID:  "tenant",
+		Cron:      "0 6 * * *",
+		Retention: Retention{Hourly: 24, Daily: 7, Weekly: 4},
+		LastRun:   time.Now().UTC(),
+		CreatedBy: "admin",
+	}
+	actual, err := s.store.Get(s.ctx, expected.TenantID)
+	c.Assert(err, NotNil)
+	c.Assert(actual, IsNil)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	err = s.store.Put(s.ctx, expected)
+	c.Assert(err, IsNil)
+
+	actual, err = s.store.Get(s.ctx, expected.TenantID)
+	c.Assert(err, IsNil)
+	c.Assert(actual.Cron, Equals, expected.Cron)
+	c.Assert(actual.Retention, Equals, expected.Retention)
+
+	schedules, err := s.store.GetSnapshotSchedules(s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(schedules, HasLen, 1)
+
+	err = s.store.Delete(s.ctx, expected.TenantID)
+	c.Assert(err, IsNil)
+	schedules, err = s.store.GetSnapshotSchedules(s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(schedules, HasLen, 0)
+}
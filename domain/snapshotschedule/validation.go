@@ -0,0 +1,52 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshotschedule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// MinInterval is the shortest time allowed between scheduled snapshots
+const MinInterval = 15 * time.Minute
+
+// ValidEntity validates SnapshotSchedule fields
+func (s *SnapshotSchedule) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("SnapshotSchedule.TenantID", s.TenantID))
+	if s.Cron != "" {
+		if s.Interval != 0 {
+			violations.Add(validation.NewViolation("a snapshot schedule needs an interval or a cron expression, not both"))
+		}
+		if spec, err := ParseCron(s.Cron); err != nil {
+			violations.Add(validation.NewViolation(err.Error()))
+		} else if gap, ok := spec.MinGap(time.Now().UTC()); !ok {
+			violations.Add(validation.NewViolation(fmt.Sprintf("invalid cron expression %q: never matches", s.Cron)))
+		} else if gap < MinInterval {
+			violations.Add(validation.NewViolation(fmt.Sprintf("invalid cron expression %q: runs every %s, must be at least %s apart", s.Cron, gap, MinInterval)))
+		}
+	} else if s.Interval < MinInterval {
+		violations.Add(validation.NewViolation(fmt.Sprintf("invalid snapshot interval %s: must be at least %s", s.Interval, MinInterval)))
+	}
+	if s.Retention.Hourly < 0 || s.Retention.Daily < 0 || s.Retention.Weekly < 0 {
+		violations.Add(validation.NewViolation("snapshot retention counts cannot be negative"))
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// Store is the database for webhooks
+type Store interface {
+	// Get a Webhook by id. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Webhook, error)
+
+	// Put adds or updates a Webhook
+	Put(ctx datastore.Context, w *Webhook) error
+
+	// Delete removes a Webhook if it exists
+	Delete(ctx datastore.Context, id string) error
+
+	// GetWebhooks returns all Webhooks
+	GetWebhooks(ctx datastore.Context) ([]*Webhook, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore creates a Store for Webhooks
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Get a Webhook by id.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Webhook, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("WebhookStore.Get"))
+	val := &Webhook{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates a Webhook
+func (s *storeImpl) Put(ctx datastore.Context, val *Webhook) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("WebhookStore.Put"))
+	return s.ds.Put(ctx, Key(val.ID), val)
+}
+
+// Delete removes a Webhook
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("WebhookStore.Delete"))
+	return s.ds.Delete(ctx, Key(id))
+}
+
+// GetWebhooks returns all Webhooks
+func (s *storeImpl) GetWebhooks(ctx datastore.Context) ([]*Webhook, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("WebhookStore.GetWebhooks"))
+	q := datastore.NewQuery(ctx)
+	search := search.Search("controlplane").Type(kind).Size("50000")
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting and putting Webhooks
+func Key(id string) datastore.Key {
+	return datastore.NewKey(kind, strings.TrimSpace(id))
+}
+
+func convert(results datastore.Results) ([]*Webhook, error) {
+	hooks := make([]*Webhook, results.Len())
+	for idx := range hooks {
+		hook := Webhook{}
+		if err := results.Get(idx, &hook); err != nil {
+			return []*Webhook{}, err
+		}
+		hooks[idx] = &hook
+	}
+	return hooks, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package webhook
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+func (s *S) Test_WebhookCRUD(c *C) {
+	expected := &Webhook{
+		ID:        "0123456789abcdef",
+		URL:       "https://chat.example.com/hook",
+		Events:    []string{"instance.failed", "host.offline"},
+		TenantID:  "tenant",
+		Secret:    "encrypted",
+		CreatedBy: "admin",
+		CreatedAt: time.Now().UTC(),
+	}
+	actual, err := s.store.Get(s.ctx, expected.ID)
+	c.Assert(err, NotNil)
+	c.Assert(actual, IsNil)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	err = s.store.Put(s.ctx, expected)
+	c.Assert(err, IsNil)
+
+	actual, err = s.store.Get(s.ctx, expected.ID)
+	c.Assert(err, IsNil)
+	c.Assert(actual.URL, Equals, expected.URL)
+	c.Assert(actual.Events, DeepEquals, expected.Events)
+	c.Assert(actual.Secret, Equals, expected.Secret)
+
+	hooks, err := s.store.GetWebhooks(s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(hooks, HasLen, 1)
+
+	err = s.store.Delete(s.ctx, expected.ID)
+	c.Assert(err, IsNil)
+	hooks, err = s.store.GetWebhooks(s.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(hooks, HasLen, 0)
+}
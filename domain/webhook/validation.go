@@ -0,0 +1,42 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/control-center/serviced/events"
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates Webhook fields
+func (w *Webhook) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Webhook.ID", w.ID))
+	violations.Add(validation.NotEmpty("Webhook.Secret", w.Secret))
+	if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		violations.Add(validation.NewViolation(fmt.Sprintf("invalid webhook url %q: must be an http or https url", w.URL)))
+	}
+	for _, typ := range w.Events {
+		if !events.IsType(typ) {
+			violations.Add(validation.NewViolation(fmt.Sprintf("invalid event type %q: must be one of %v", typ, events.Types)))
+		}
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
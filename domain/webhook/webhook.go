@@ -0,0 +1,122 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/events"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a delivery's body, keyed with
+// the webhook's secret, so that receivers can verify where it came from.
+const SignatureHeader = "X-Serviced-Signature"
+
+// EventHeader carries the type of the delivered event.
+const EventHeader = "X-Serviced-Event"
+
+// DeliveryHeader carries the id of the delivered event, which is the same
+// across retries.
+const DeliveryHeader = "X-Serviced-Delivery"
+
+// Webhook is a subscription that delivers cluster events to a URL.
+type Webhook struct {
+	ID        string    // Identifies the webhook
+	URL       string    // Endpoint that events are POSTed to
+	Events    []string  // Event types to deliver; empty delivers all types
+	TenantID  string    // Only deliver events of this tenant, if set
+	Secret    string    // Encrypted key used to sign deliveries
+	CreatedBy string    // User that added the webhook
+	CreatedAt time.Time // Time the webhook was added
+	datastore.VersionedEntity
+}
+
+// Matches returns true if the event should be delivered to the webhook.
+func (w *Webhook) Matches(e events.Event) bool {
+	if w.TenantID != "" && w.TenantID != e.TenantID {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, typ := range w.Events {
+		if typ == string(e.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns a copy of the webhook without its secret
+func (w Webhook) Redact() Webhook {
+	w.Secret = ""
+	return w
+}
+
+// EventList describes the event types delivered to the webhook.
+func (w *Webhook) EventList() string {
+	if len(w.Events) == 0 {
+		return "all"
+	}
+	return strings.Join(w.Events, ",")
+}
+
+// GetType return the Webhook's type
+// It returns the type as a string
+func GetType() string {
+	return kind
+}
+
+// GetType returns the Webhook instance's type
+// It returns the type as a string
+func (w *Webhook) GetType() string {
+	return GetType()
+}
+
+// GetID return a Webhook instance's ID
+// It returns the ID as a string
+func (w *Webhook) GetID() string {
+	return w.ID
+}
+
+// NewID returns a random webhook id
+func NewID() (string, error) {
+	return randomHex(8)
+}
+
+// NewSecret returns a random secret for signing deliveries
+func NewSecret() (string, error) {
+	return randomHex(32)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign returns the value of the signature header for a body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/control-center/serviced/events"
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type webhookSuite struct{}
+
+var _ = Suite(&webhookSuite{})
+
+func (s *webhookSuite) TestMatches(c *C) {
+	failed := events.Event{Type: events.InstanceFailed, TenantID: "tenant"}
+	offline := events.Event{Type: events.HostOffline}
+
+	all := &Webhook{}
+	c.Check(all.Matches(failed), Equals, true)
+	c.Check(all.Matches(offline), Equals, true)
+
+	typed := &Webhook{Events: []string{"host.offline"}}
+	c.Check(typed.Matches(failed), Equals, false)
+	c.Check(typed.Matches(offline), Equals, true)
+
+	tenant := &Webhook{TenantID: "tenant"}
+	c.Check(tenant.Matches(failed), Equals, true)
+	c.Check(tenant.Matches(offline), Equals, false)
+}
+
+func (s *webhookSuite) TestSign(c *C) {
+	body := []byte(`{"type":"host.offline"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	c.Assert(Sign("secret", body), Equals, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	c.Assert(Sign("other", body), Not(Equals), Sign("secret", body))
+}
+
+func (s *webhookSuite) TestValidEntity(c *C) {
+	w := &Webhook{ID: "abc", Secret: "encrypted", URL: "https://chat.example.com/hook", Events: []string{"instance.failed"}}
+	c.Assert(w.ValidEntity(), IsNil)
+
+	w.URL = "chat.example.com/hook"
+	c.Assert(w.ValidEntity(), NotNil)
+
+	w.URL = "ftp://chat.example.com/hook"
+	c.Assert(w.ValidEntity(), NotNil)
+
+	w.URL = "http://chat.example.com/hook"
+	w.Events = []string{"resync"}
+	c.Assert(w.ValidEntity(), NotNil)
+
+	w.Events = nil
+	w.Secret = ""
+	c.Assert(w.ValidEntity(), NotNil)
+}
+
+func (s *webhookSuite) TestNewSecret(c *C) {
+	a, err := NewSecret()
+	c.Assert(err, IsNil)
+	b, err := NewSecret()
+	c.Assert(err, IsNil)
+	c.Assert(a, HasLen, 64)
+	c.Assert(a, Not(Equals), b)
+}
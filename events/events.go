@@ -0,0 +1,244 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events keeps a short history of cluster state changes and fans them
+// out to subscribers, so clients can follow the cluster without polling.
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Type identifies the kind of state change an event describes.
+type Type string
+
+const (
+	// InstanceStarted is published when a service instance starts running.
+	InstanceStarted Type = "instance.started"
+	// InstanceStopped is published when a service instance stops.
+	InstanceStopped Type = "instance.stopped"
+	// InstanceFailed is published when a service instance fails to start or
+	// exits unexpectedly.
+	InstanceFailed Type = "instance.failed"
+	// HealthChanged is published when the result of a health check changes.
+	HealthChanged Type = "health.changed"
+	// DesiredStateChanged is published when a service is started, stopped or
+	// paused.
+	DesiredStateChanged Type = "service.desiredstate"
+	// HostOnline is published when a host connects to the coordinator.
+	HostOnline Type = "host.online"
+	// HostOffline is published when a host drops off the coordinator.
+	HostOffline Type = "host.offline"
+	// DeploymentStarted is published when a template deployment begins.
+	DeploymentStarted Type = "deployment.started"
+	// DeploymentFinished is published when a template deployment completes
+	// or fails.
+	DeploymentFinished Type = "deployment.finished"
+	// BackupStarted is published when a backup begins.
+	BackupStarted Type = "backup.started"
+	// BackupFinished is published when a backup completes or fails.
+	BackupFinished Type = "backup.finished"
+	// EmergencyShutdown is published when a tenant is stopped because its
+	// storage is running out.
+	EmergencyShutdown Type = "service.emergencyshutdown"
+	// Resync tells a subscriber that events were missed and that it should
+	// reload the full state before following the stream.
+	Resync Type = "resync"
+)
+
+// Types lists the types of events that describe cluster state changes.
+var Types = []Type{
+	InstanceStarted, InstanceStopped, InstanceFailed, HealthChanged,
+	DesiredStateChanged, EmergencyShutdown, HostOnline, HostOffline,
+	DeploymentStarted, DeploymentFinished, BackupStarted, BackupFinished,
+}
+
+// IsType returns true if the value names one of the event Types.
+func IsType(value string) bool {
+	for _, t := range Types {
+		if string(t) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultHistory is the number of events a bus retains for resuming
+// subscribers.
+const DefaultHistory = 1024
+
+// subscriberBuffer is the number of events queued for a subscriber before it
+// is considered too slow and dropped.
+const subscriberBuffer = 256
+
+// Event is a single cluster state change.
+type Event struct {
+	ID         string            `json:"id"`
+	Type       Type              `json:"type"`
+	Time       time.Time         `json:"time"`
+	TenantID   string            `json:"tenantId,omitempty"`
+	ServiceID  string            `json:"serviceId,omitempty"`
+	HostID     string            `json:"hostId,omitempty"`
+	InstanceID int               `json:"instanceId"`
+	Detail     map[string]string `json:"detail,omitempty"`
+}
+
+// Filter restricts a subscription to a single tenant and/or service.  Empty
+// fields match everything.
+type Filter struct {
+	TenantID  string
+	ServiceID string
+}
+
+// Match returns true if the event passes the filter.
+func (f Filter) Match(e Event) bool {
+	if f.TenantID != "" && f.TenantID != e.TenantID {
+		return false
+	}
+	if f.ServiceID != "" && f.ServiceID != e.ServiceID {
+		return false
+	}
+	return true
+}
+
+// Bus assigns ids to published events, keeps the most recent of them, and
+// delivers them to subscribers.
+type Bus struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	size    int
+	history []Event
+	subs    map[*Subscription]struct{}
+}
+
+// NewBus returns a bus that retains up to size events for resuming
+// subscribers.
+func NewBus(size int) *Bus {
+	if size <= 0 {
+		size = DefaultHistory
+	}
+	return &Bus{
+		epoch: strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:  size,
+		subs:  make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish stamps the event with an id and time and delivers it to every
+// matching subscriber.  Subscribers that cannot keep up are closed rather
+// than allowed to block the publisher.
+func (b *Bus) Publish(e Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	e.ID = fmt.Sprintf("%s-%d", b.epoch, b.seq)
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	b.history = append(b.history, e)
+	if len(b.history) > b.size {
+		b.history = append([]Event(nil), b.history[len(b.history)-b.size:]...)
+	}
+	for sub := range b.subs {
+		if !sub.filter.Match(e) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			b.unsubscribe(sub)
+		}
+	}
+	return e
+}
+
+// Subscribe registers a subscriber for events matching the filter.  If after
+// is the id of a previously delivered event, the retained events that
+// followed it are returned as the backlog.  ok is false if the id cannot be
+// resumed from, either because it was issued before the master restarted or
+// because too many events have happened since; the subscriber should then
+// resynchronize its state.
+func (b *Bus) Subscribe(after string, filter Filter) (sub *Subscription, backlog []Event, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub = &Subscription{
+		bus:    b,
+		filter: filter,
+		events: make(chan Event, subscriberBuffer),
+	}
+	b.subs[sub] = struct{}{}
+	if after == "" {
+		return sub, nil, true
+	}
+	seq, ok := b.parse(after)
+	if !ok {
+		return sub, nil, false
+	}
+	oldest := b.seq - uint64(len(b.history)) + 1
+	if seq+1 < oldest {
+		return sub, nil, false
+	}
+	for _, e := range b.history[seq+1-oldest:] {
+		if filter.Match(e) {
+			backlog = append(backlog, e)
+		}
+	}
+	return sub, backlog, true
+}
+
+// parse returns the sequence number of an event id issued by this bus.
+func (b *Bus) parse(id string) (uint64, bool) {
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 || parts[0] != b.epoch {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil || seq > b.seq {
+		return 0, false
+	}
+	return seq, true
+}
+
+// unsubscribe is non thread-safe
+func (b *Bus) unsubscribe(sub *Subscription) {
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.events)
+	}
+}
+
+// Subscription is a registered event subscriber.
+type Subscription struct {
+	bus    *Bus
+	filter Filter
+	events chan Event
+}
+
+// Events returns the channel on which events are delivered.  The channel is
+// closed when the subscription is closed, or when the subscriber fell too
+// far behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close unregisters the subscription.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.unsubscribe(s)
+}
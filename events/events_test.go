@@ -0,0 +1,119 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package events_test
+
+import (
+	"testing"
+
+	. "github.com/control-center/serviced/events"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+var _ = Suite(&EventsTestSuite{})
+
+type EventsTestSuite struct{}
+
+func (s *EventsTestSuite) TestFilter(c *C) {
+	e := Event{TenantID: "tenant", ServiceID: "svc"}
+	c.Check(Filter{}.Match(e), Equals, true)
+	c.Check(Filter{TenantID: "tenant"}.Match(e), Equals, true)
+	c.Check(Filter{TenantID: "tenant", ServiceID: "svc"}.Match(e), Equals, true)
+	c.Check(Filter{TenantID: "other"}.Match(e), Equals, false)
+	c.Check(Filter{ServiceID: "other"}.Match(e), Equals, false)
+	c.Check(Filter{TenantID: "tenant"}.Match(Event{Type: HostOnline}), Equals, false)
+}
+
+func (s *EventsTestSuite) TestPublishSubscribe(c *C) {
+	bus := NewBus(10)
+	sub, backlog, ok := bus.Subscribe("", Filter{ServiceID: "svc"})
+	defer sub.Close()
+	c.Assert(ok, Equals, true)
+	c.Assert(backlog, HasLen, 0)
+
+	bus.Publish(Event{Type: InstanceStarted, ServiceID: "other"})
+	published := bus.Publish(Event{Type: InstanceStarted, ServiceID: "svc"})
+	c.Check(published.ID, Not(Equals), "")
+	c.Check(published.Time.IsZero(), Equals, false)
+
+	select {
+	case e := <-sub.Events():
+		c.Check(e, DeepEquals, published)
+	default:
+		c.Fatalf("expected an event")
+	}
+	select {
+	case e := <-sub.Events():
+		c.Fatalf("unexpected event %+v", e)
+	default:
+	}
+}
+
+func (s *EventsTestSuite) TestResume(c *C) {
+	bus := NewBus(3)
+	first := bus.Publish(Event{Type: HostOnline, HostID: "a"})
+	second := bus.Publish(Event{Type: HostOnline, HostID: "b"})
+	third := bus.Publish(Event{Type: HostOnline, HostID: "c"})
+
+	sub, backlog, ok := bus.Subscribe(first.ID, Filter{})
+	sub.Close()
+	c.Assert(ok, Equals, true)
+	c.Check(backlog, DeepEquals, []Event{second, third})
+
+	sub, backlog, ok = bus.Subscribe(third.ID, Filter{})
+	sub.Close()
+	c.Assert(ok, Equals, true)
+	c.Check(backlog, HasLen, 0)
+
+	// the first event drops out of the history
+	bus.Publish(Event{Type: HostOnline, HostID: "d"})
+	bus.Publish(Event{Type: HostOnline, HostID: "e"})
+	sub, _, ok = bus.Subscribe(first.ID, Filter{})
+	sub.Close()
+	c.Check(ok, Equals, false)
+
+	// ids from another bus (i.e. before a restart) cannot be resumed
+	sub, _, ok = NewBus(3).Subscribe(third.ID, Filter{})
+	sub.Close()
+	c.Check(ok, Equals, false)
+
+	sub, _, ok = bus.Subscribe("garbage", Filter{})
+	sub.Close()
+	c.Check(ok, Equals, false)
+}
+
+func (s *EventsTestSuite) TestSlowSubscriber(c *C) {
+	bus := NewBus(10)
+	sub, _, _ := bus.Subscribe("", Filter{})
+	for i := 0; i < 1000; i++ {
+		bus.Publish(Event{Type: HostOnline})
+	}
+	count := 0
+	for range sub.Events() {
+		count++
+	}
+	c.Check(count > 0, Equals, true)
+	c.Check(count < 1000, Equals, true)
+	sub.Close()
+}
+
+func (s *EventsTestSuite) TestIsType(c *C) {
+	c.Check(IsType("instance.failed"), Equals, true)
+	c.Check(IsType(string(EmergencyShutdown)), Equals, true)
+	c.Check(IsType(string(Resync)), Equals, false)
+	c.Check(IsType("bogus"), Equals, false)
+}
@@ -0,0 +1,166 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/apitoken"
+)
+
+var (
+	// ErrAPITokenExists is returned when an active token already has the
+	// requested name
+	ErrAPITokenExists = errors.New("facade: an active API token with that name already exists")
+
+	// ErrAPITokenNotFound is returned when no token matches an id or name
+	ErrAPITokenNotFound = errors.New("facade: API token not found")
+
+	// ErrAPITokenInvalid is returned when a bearer token is not a valid,
+	// active API token
+	ErrAPITokenInvalid = errors.New("facade: invalid API token")
+)
+
+// CreateAPIToken creates a named API token that expires at the given time
+// and is limited to the given scope.  It returns the bearer token, which
+// cannot be recovered afterwards, along with the stored token.
+func (f *Facade) CreateAPIToken(ctx datastore.Context, name string, scope apitoken.Scope, expires time.Time) (string, *apitoken.APIToken, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.CreateAPIToken"))
+	alog := f.auditLogger.Message(ctx, "Create API Token").Action(audit.Add).
+		Type(apitoken.GetType()).WithField("name", name).WithField("scope", scope.String())
+	tokens, err := f.apiTokenStore.GetAPITokens(ctx)
+	if err != nil {
+		return "", nil, alog.Error(err)
+	}
+	now := time.Now().UTC()
+	for _, t := range tokens {
+		if t.Name == name && t.Active(now) {
+			return "", nil, alog.Error(ErrAPITokenExists)
+		}
+	}
+	id, secret, bearer, err := apitoken.Generate()
+	if err != nil {
+		return "", nil, alog.Error(err)
+	}
+	alog = alog.ID(id)
+	t := &apitoken.APIToken{
+		ID:        id,
+		Name:      name,
+		Hash:      apitoken.HashSecret(secret),
+		Scope:     scope,
+		CreatedBy: ctx.User(),
+		CreatedAt: now,
+		ExpiresAt: expires.UTC(),
+	}
+	if err := t.ValidEntity(); err != nil {
+		return "", nil, alog.Error(err)
+	}
+	if err := f.apiTokenStore.Put(ctx, t); err != nil {
+		return "", nil, alog.Error(err)
+	}
+	alog.Succeeded()
+	return bearer, t, nil
+}
+
+// GetAPITokens returns all API tokens, including those that have expired or
+// been revoked
+func (f *Facade) GetAPITokens(ctx datastore.Context) ([]apitoken.APIToken, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetAPITokens"))
+	tokens, err := f.apiTokenStore.GetAPITokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]apitoken.APIToken, len(tokens))
+	for i, t := range tokens {
+		result[i] = *t
+	}
+	return result, nil
+}
+
+// RevokeAPIToken revokes the token with the given id, or the active token
+// with the given name
+func (f *Facade) RevokeAPIToken(ctx datastore.Context, idOrName string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RevokeAPIToken"))
+	alog := f.auditLogger.Message(ctx, "Revoke API Token").Action(audit.Revoke).
+		Type(apitoken.GetType())
+	t, err := f.findAPIToken(ctx, idOrName)
+	if err != nil {
+		return alog.ID(idOrName).Error(err)
+	}
+	alog = alog.ID(t.ID).WithField("name", t.Name)
+	if t.Revoked {
+		alog.Succeeded()
+		return nil
+	}
+	t.Revoked = true
+	t.RevokedAt = time.Now().UTC()
+	if err := f.apiTokenStore.Put(ctx, t); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// AuthenticateAPIToken returns the API token matching the bearer token, if
+// it is active
+func (f *Facade) AuthenticateAPIToken(ctx datastore.Context, bearer string) (*apitoken.APIToken, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AuthenticateAPIToken"))
+	id, secret, ok := apitoken.Parse(bearer)
+	if !ok {
+		return nil, ErrAPITokenInvalid
+	}
+	t, err := f.apiTokenStore.Get(ctx, id)
+	if datastore.IsErrNoSuchEntity(err) {
+		return nil, ErrAPITokenInvalid
+	} else if err != nil {
+		return nil, err
+	}
+	if !t.Matches(secret) || !t.Active(time.Now()) {
+		return nil, ErrAPITokenInvalid
+	}
+	return t, nil
+}
+
+// findAPIToken looks up a token by id, then by name, preferring an active
+// token when several share the name
+func (f *Facade) findAPIToken(ctx datastore.Context, idOrName string) (*apitoken.APIToken, error) {
+	if t, err := f.apiTokenStore.Get(ctx, idOrName); err == nil {
+		return t, nil
+	} else if !datastore.IsErrNoSuchEntity(err) {
+		return nil, err
+	}
+	tokens, err := f.apiTokenStore.GetAPITokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found *apitoken.APIToken
+	now := time.Now()
+	for _, t := range tokens {
+		if t.Name != idOrName {
+			continue
+		}
+		if t.Active(now) {
+			return t, nil
+		} else if found == nil {
+			found = t
+		}
+	}
+	if found == nil {
+		return nil, ErrAPITokenNotFound
+	}
+	return found, nil
+}
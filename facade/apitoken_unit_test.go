@@ -0,0 +1,106 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/apitoken"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_CreateAPITokenStoresHash(c *C) {
+	ft.ctx.On("User").Return("admin")
+	ft.apiTokenStore.On("GetAPITokens", ft.ctx).Return([]*apitoken.APIToken{}, nil)
+	ft.apiTokenStore.On("Put", ft.ctx, mock.AnythingOfType("*apitoken.APIToken")).Return(nil)
+
+	scope := apitoken.Scope{ReadOnly: true}
+	bearer, t, err := ft.Facade.CreateAPIToken(ft.ctx, "ci", scope, time.Now().Add(time.Hour))
+	c.Assert(err, IsNil)
+
+	id, secret, ok := apitoken.Parse(bearer)
+	c.Assert(ok, Equals, true)
+	c.Assert(t.ID, Equals, id)
+	c.Assert(t.Name, Equals, "ci")
+	c.Assert(t.CreatedBy, Equals, "admin")
+	c.Assert(t.Scope, DeepEquals, scope)
+	c.Assert(t.Hash, Not(Equals), secret)
+	c.Assert(t.Matches(secret), Equals, true)
+}
+
+func (ft *FacadeUnitTest) Test_CreateAPITokenDuplicateName(c *C) {
+	ft.apiTokenStore.On("GetAPITokens", ft.ctx).Return([]*apitoken.APIToken{
+		{ID: "abc", Name: "ci", ExpiresAt: time.Now().Add(time.Hour)},
+	}, nil)
+
+	_, _, err := ft.Facade.CreateAPIToken(ft.ctx, "ci", apitoken.Scope{}, time.Now().Add(time.Hour))
+	c.Assert(err, Equals, facade.ErrAPITokenExists)
+	ft.apiTokenStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_RevokeAPITokenByName(c *C) {
+	ft.apiTokenStore.On("Get", ft.ctx, "ci").Return(nil, datastore.ErrNoSuchEntity{})
+	ft.apiTokenStore.On("GetAPITokens", ft.ctx).Return([]*apitoken.APIToken{
+		{ID: "old", Name: "ci", ExpiresAt: time.Now().Add(-time.Hour)},
+		{ID: "new", Name: "ci", ExpiresAt: time.Now().Add(time.Hour)},
+	}, nil)
+	ft.apiTokenStore.On("Put", ft.ctx, mock.AnythingOfType("*apitoken.APIToken")).Return(nil)
+
+	err := ft.Facade.RevokeAPIToken(ft.ctx, "ci")
+	c.Assert(err, IsNil)
+	stored := ft.apiTokenStore.Calls[2].Arguments.Get(1).(*apitoken.APIToken)
+	c.Assert(stored.ID, Equals, "new")
+	c.Assert(stored.Revoked, Equals, true)
+}
+
+func (ft *FacadeUnitTest) Test_RevokeAPITokenNotFound(c *C) {
+	ft.apiTokenStore.On("Get", ft.ctx, "ci").Return(nil, datastore.ErrNoSuchEntity{})
+	ft.apiTokenStore.On("GetAPITokens", ft.ctx).Return([]*apitoken.APIToken{}, nil)
+
+	err := ft.Facade.RevokeAPIToken(ft.ctx, "ci")
+	c.Assert(err, Equals, facade.ErrAPITokenNotFound)
+}
+
+func (ft *FacadeUnitTest) Test_AuthenticateAPIToken(c *C) {
+	id, secret, bearer, err := apitoken.Generate()
+	c.Assert(err, IsNil)
+	t := &apitoken.APIToken{ID: id, Name: "ci", Hash: apitoken.HashSecret(secret), ExpiresAt: time.Now().Add(time.Hour)}
+	ft.apiTokenStore.On("Get", ft.ctx, id).Return(t, nil)
+
+	actual, err := ft.Facade.AuthenticateAPIToken(ft.ctx, bearer)
+	c.Assert(err, IsNil)
+	c.Assert(actual, Equals, t)
+
+	_, err = ft.Facade.AuthenticateAPIToken(ft.ctx, apitoken.Prefix+id+"_bad")
+	c.Assert(err, Equals, facade.ErrAPITokenInvalid)
+
+	t.Revoked = true
+	_, err = ft.Facade.AuthenticateAPIToken(ft.ctx, bearer)
+	c.Assert(err, Equals, facade.ErrAPITokenInvalid)
+}
+
+func (ft *FacadeUnitTest) Test_AuthenticateAPITokenUnknown(c *C) {
+	ft.apiTokenStore.On("Get", ft.ctx, "abc").Return(nil, datastore.ErrNoSuchEntity{})
+
+	_, err := ft.Facade.AuthenticateAPIToken(ft.ctx, apitoken.Prefix+"abc_def")
+	c.Assert(err, Equals, facade.ErrAPITokenInvalid)
+
+	_, err = ft.Facade.AuthenticateAPIToken(ft.ctx, "not-a-token")
+	c.Assert(err, Equals, facade.ErrAPITokenInvalid)
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/metrics"
+)
+
+// autoScaleMetric returns the name of the metric to query for the autoscale
+// settings and whether to take its rate.
+func autoScaleMetric(a servicedefinition.AutoScale) (string, bool) {
+	switch a.Metric {
+	case servicedefinition.AutoScaleCPU:
+		return "docker.usageinusermode", true
+	case servicedefinition.AutoScaleMemory:
+		return "cgroup.memory.totalrss", false
+	}
+	return a.Metric, a.Rate
+}
+
+// AutoScaleServices sets the number of instances of every running service
+// that has autoscaling enabled from its metric.  Each change is recorded in
+// the audit log.
+func (f *Facade) AutoScaleServices(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AutoScaleServices"))
+	svcs, err := f.serviceStore.GetServices(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range svcs {
+		svc := &svcs[i]
+		if !svc.AutoScale.Enabled() || svc.DesiredState != int(service.SVCRun) {
+			continue
+		}
+		if err := f.autoScaleService(ctx, svc, now); err != nil {
+			plog.WithFields(log.Fields{
+				"serviceid":   svc.ID,
+				"servicename": svc.Name,
+			}).WithError(err).Warn("Could not autoscale service")
+		}
+	}
+	return nil
+}
+
+// autoScaleService reads the metric of a service and changes its number of
+// instances if the autoscaler decides to.
+func (f *Facade) autoScaleService(ctx datastore.Context, svc *service.Service, now time.Time) error {
+	metric, rate := autoScaleMetric(svc.AutoScale)
+	stats, err := f.metricsClient.GetServiceMetric(svc.AutoScale.GetWindow(), svc.ID, metric, rate)
+	if err != nil {
+		return err
+	}
+	desired, ok := autoScaleDecision(svc, stats, now)
+	if !ok {
+		return nil
+	}
+
+	alog := f.auditLogger.Action(audit.Scale).Message(ctx, "Autoscaling Service").Type(service.GetType()).ID(svc.ID).WithFields(log.Fields{
+		"servicename": svc.Name,
+		"metric":      metric,
+		"average":     stats.Average,
+		"target":      svc.AutoScale.Target,
+		"from":        svc.Instances,
+		"to":          desired,
+	})
+	if err := f.scaleService(ctx, svc.ID, desired, now); err != nil {
+		return alog.Error(err)
+	}
+	return alog.Error(nil)
+}
+
+// autoScaleDecision returns the number of instances a service should have
+// for the metric and whether the autoscaler should change it now.  Nothing
+// changes while no instance reports the metric or during the cooldown of the
+// direction it would scale in.
+func autoScaleDecision(svc *service.Service, stats *metrics.ServiceMetricStats, now time.Time) (int, bool) {
+	if stats.Instances == 0 {
+		return svc.Instances, false
+	}
+	desired := svc.AutoScale.DesiredInstances(svc.Instances, stats.Average, svc.InstanceLimits)
+	if desired == svc.Instances {
+		return desired, false
+	}
+	up := desired > svc.Instances
+	last := svc.AutoScaledDown
+	if up {
+		last = svc.AutoScaledUp
+	}
+	if now.Sub(last) < svc.AutoScale.GetCooldown(up) {
+		return desired, false
+	}
+	return desired, true
+}
+
+// scaleService sets the number of instances of a service and records when
+// it was scaled up or down
+func (f *Facade) scaleService(ctx datastore.Context, serviceID string, instances int, now time.Time) error {
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	mutex := getTenantLock(tenantID)
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	if svc.Instances == instances {
+		return nil
+	}
+	if err = f.fillServiceConfigs(ctx, svc); err != nil {
+		return err
+	}
+	if instances > svc.Instances {
+		svc.AutoScaledUp = now
+	} else {
+		svc.AutoScaledDown = now
+	}
+	svc.Instances = instances
+	return f.updateService(ctx, tenantID, *svc, false, false)
+}
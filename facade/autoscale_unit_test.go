@@ -0,0 +1,42 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/metrics"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_AutoScaleServicesSkipsServices(c *C) {
+	autoscale := servicedefinition.AutoScale{Metric: "queue.depth", Target: 100}
+	limits := domain.MinMax{Min: 1, Max: 4}
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{
+		{ID: "manual", Instances: 1, DesiredState: int(service.SVCRun)},
+		{ID: "stopped", Instances: 1, InstanceLimits: limits, AutoScale: autoscale, DesiredState: int(service.SVCStop)},
+		{ID: "ontarget", Instances: 2, InstanceLimits: limits, AutoScale: autoscale, DesiredState: int(service.SVCRun)},
+	}, nil)
+	ft.metricsClient.On("GetServiceMetric", servicedefinition.DefaultAutoScaleWindow, "ontarget", "queue.depth", false).
+		Return(&metrics.ServiceMetricStats{ServiceID: "ontarget", Instances: 2, Average: 80}, nil)
+
+	err := ft.Facade.AutoScaleServices(ft.ctx)
+	c.Assert(err, IsNil)
+	ft.metricsClient.AssertNumberOfCalls(c, "GetServiceMetric", 1)
+	ft.serviceStore.AssertNotCalled(c, "Get", mock.Anything, mock.Anything)
+}
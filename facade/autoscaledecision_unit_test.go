@@ -0,0 +1,112 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/metrics"
+
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&AutoScaleTest{})
+
+type AutoScaleTest struct {
+	svc *service.Service
+}
+
+func (t *AutoScaleTest) SetUpTest(c *C) {
+	t.svc = &service.Service{
+		ID:             "service1",
+		Instances:      2,
+		InstanceLimits: domain.MinMax{Min: 1, Max: 6},
+		AutoScale: servicedefinition.AutoScale{
+			Metric:            "queue.depth",
+			Target:            100,
+			ScaleUpCooldown:   60,
+			ScaleDownCooldown: 600,
+		},
+	}
+}
+
+func (t *AutoScaleTest) Test_autoScaleDecision(c *C) {
+	now := time.Now()
+
+	// no data
+	stats := &metrics.ServiceMetricStats{}
+	_, ok := autoScaleDecision(t.svc, stats, now)
+	c.Assert(ok, Equals, false)
+
+	// on target
+	stats = &metrics.ServiceMetricStats{Instances: 2, Average: 90}
+	_, ok = autoScaleDecision(t.svc, stats, now)
+	c.Assert(ok, Equals, false)
+
+	// scale up, limited by the maximum
+	stats = &metrics.ServiceMetricStats{Instances: 2, Average: 500}
+	desired, ok := autoScaleDecision(t.svc, stats, now)
+	c.Assert(ok, Equals, true)
+	c.Assert(desired, Equals, 6)
+}
+
+func (t *AutoScaleTest) Test_autoScaleDecisionCooldown(c *C) {
+	now := time.Now()
+	t.svc.AutoScaledUp = now.Add(-2 * time.Minute)
+
+	// the scale up cooldown has passed
+	stats := &metrics.ServiceMetricStats{Instances: 2, Average: 150}
+	desired, ok := autoScaleDecision(t.svc, stats, now)
+	c.Assert(ok, Equals, true)
+	c.Assert(desired, Equals, 3)
+
+	// scaling up does not hold off scaling down
+	stats = &metrics.ServiceMetricStats{Instances: 2, Average: 10}
+	desired, ok = autoScaleDecision(t.svc, stats, now)
+	c.Assert(ok, Equals, true)
+	c.Assert(desired, Equals, 1)
+
+	// the scale down cooldown has not passed
+	t.svc.AutoScaledDown = now.Add(-2 * time.Minute)
+	_, ok = autoScaleDecision(t.svc, stats, now)
+	c.Assert(ok, Equals, false)
+
+	_, ok = autoScaleDecision(t.svc, stats, now.Add(10*time.Minute))
+	c.Assert(ok, Equals, true)
+
+	// the scale up cooldown has not passed
+	t.svc.AutoScaledUp = now.Add(-30 * time.Second)
+	stats = &metrics.ServiceMetricStats{Instances: 2, Average: 150}
+	_, ok = autoScaleDecision(t.svc, stats, now)
+	c.Assert(ok, Equals, false)
+}
+
+func (t *AutoScaleTest) Test_autoScaleMetric(c *C) {
+	metric, rate := autoScaleMetric(servicedefinition.AutoScale{Metric: servicedefinition.AutoScaleCPU})
+	c.Assert(metric, Equals, "docker.usageinusermode")
+	c.Assert(rate, Equals, true)
+
+	metric, rate = autoScaleMetric(servicedefinition.AutoScale{Metric: servicedefinition.AutoScaleMemory})
+	c.Assert(metric, Equals, "cgroup.memory.totalrss")
+	c.Assert(rate, Equals, false)
+
+	metric, rate = autoScaleMetric(servicedefinition.AutoScale{Metric: "requests", Rate: true})
+	c.Assert(metric, Equals, "requests")
+	c.Assert(rate, Equals, true)
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"strconv"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// GetCoordinatorTree returns the node at the given path in the coordinator
+// along with all of its descendants.
+func (f *Facade) GetCoordinatorTree(ctx datastore.Context, pth string) (*zzk.TreeNode, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetCoordinatorTree"))
+	tree, err := f.zzk.GetTree(pth)
+	if err != nil {
+		plog.WithField("zkpath", pth).WithError(err).Debug("Could not look up the coordinator tree")
+		return nil, err
+	}
+	return tree, nil
+}
+
+// CheckCoordinatorState compares the resource pools, hosts and services in
+// the coordinator with the database, and looks for orphaned instance states,
+// stale service locks and dangling exports.  If repair is true, the
+// inconsistencies are fixed.  The DFS lock is held for the duration of the
+// check, so that services locked by a running snapshot or restore are not
+// reported as stale.
+func (f *Facade) CheckCoordinatorState(ctx datastore.Context, repair bool) ([]zkservice.Inconsistency, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.CheckCoordinatorState"))
+	alog := f.auditLogger.Message(ctx, "Repairing Coordinator State").Action(audit.Repair)
+
+	if err := f.DFSLock(ctx).LockWithTimeout("check coordinator state", userLockTimeout); err != nil {
+		plog.WithError(err).Debug("Could not lock DFS")
+		return nil, f.checkCoordinatorError(alog, repair, err)
+	}
+	defer f.DFSLock(ctx).Unlock()
+
+	view, err := f.getFacadeView(ctx)
+	if err != nil {
+		return nil, f.checkCoordinatorError(alog, repair, err)
+	}
+
+	found, err := f.zzk.CheckState(ctx, *view, repair)
+	if err != nil {
+		plog.WithError(err).Debug("Could not check the coordinator state")
+		return nil, f.checkCoordinatorError(alog, repair, err)
+	}
+
+	if repair {
+		alog.WithField("repaired", strconv.Itoa(len(found))).Succeeded()
+		plog.WithField("repaired", len(found)).Info("Repaired coordinator state")
+	}
+	return found, nil
+}
+
+// checkCoordinatorError audits a failed repair of the coordinator state
+func (f *Facade) checkCoordinatorError(alog audit.Logger, repair bool, err error) error {
+	if repair {
+		return alog.Error(err)
+	}
+	return err
+}
+
+// getFacadeView loads the resource pools, hosts, services and tenants that
+// are expected to be synced into the coordinator.
+func (f *Facade) getFacadeView(ctx datastore.Context) (*zkservice.FacadeView, error) {
+	pools, err := f.GetResourcePools(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not get resource pools")
+		return nil, err
+	}
+
+	view := &zkservice.FacadeView{
+		Pools:    pools,
+		Hosts:    make(map[string][]host.Host),
+		Services: make(map[string][]service.Service),
+	}
+
+	for _, p := range pools {
+		logger := plog.WithField("poolid", p.ID)
+
+		hosts, err := f.FindHostsInPool(ctx, p.ID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not get hosts in resource pool")
+			return nil, err
+		}
+		view.Hosts[p.ID] = hosts
+
+		svcs, err := f.GetServicesByPool(ctx, p.ID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not get services in resource pool")
+			return nil, err
+		}
+		view.Services[p.ID] = svcs
+	}
+
+	if view.TenantIDs, err = f.GetTenantIDs(ctx); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
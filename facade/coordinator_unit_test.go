@@ -0,0 +1,94 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupCoordinatorView() zkservice.FacadeView {
+	pools := []pool.ResourcePool{{ID: "default"}}
+	hosts := []host.Host{{ID: "h1", PoolID: "default", IPAddr: "10.0.0.1"}}
+	svcs := []service.Service{{ID: "tenant", PoolID: "default"}}
+	tenants := []service.ServiceDetails{{ID: "tenant"}}
+
+	ft.poolStore.On("GetResourcePools", ft.ctx).Return(pools, nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "default").Return(hosts, nil)
+	ft.serviceStore.On("GetServicesByPool", ft.ctx, "default").Return(svcs, nil)
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, "", time.Duration(0)).Return(tenants, nil)
+
+	return zkservice.FacadeView{
+		Pools:     pools,
+		Hosts:     map[string][]host.Host{"default": hosts},
+		Services:  map[string][]service.Service{"default": svcs},
+		TenantIDs: []string{"tenant"},
+	}
+}
+
+func (ft *FacadeUnitTest) Test_GetCoordinatorTree(c *C) {
+	tree := &zzk.TreeNode{Path: "/pools", Children: []zzk.TreeNode{{Path: "/pools/default"}}}
+	ft.zzk.On("GetTree", "/pools").Return(tree, nil)
+
+	actual, err := ft.Facade.GetCoordinatorTree(ft.ctx, "/pools")
+	c.Assert(err, IsNil)
+	c.Assert(actual, DeepEquals, tree)
+}
+
+func (ft *FacadeUnitTest) Test_CheckCoordinatorState(c *C) {
+	ft.setupMockDFSLocking()
+	view := ft.setupCoordinatorView()
+
+	found := []zkservice.Inconsistency{
+		{Type: zkservice.OrphanState, Path: "/pools/default/services/tenant/h2-tenant-0", Message: "orphan"},
+	}
+	ft.zzk.On("CheckState", ft.ctx, view, false).Return(found, nil)
+
+	actual, err := ft.Facade.CheckCoordinatorState(ft.ctx, false)
+	c.Assert(err, IsNil)
+	c.Assert(actual, DeepEquals, found)
+}
+
+func (ft *FacadeUnitTest) Test_CheckCoordinatorStateRepair(c *C) {
+	ft.setupMockDFSLocking()
+	view := ft.setupCoordinatorView()
+
+	found := []zkservice.Inconsistency{
+		{Type: zkservice.StaleLock, Path: "/pools/default/services/tenant", Message: "locked", Repaired: true},
+	}
+	ft.zzk.On("CheckState", ft.ctx, view, true).Return(found, nil)
+
+	actual, err := ft.Facade.CheckCoordinatorState(ft.ctx, true)
+	c.Assert(err, IsNil)
+	c.Assert(actual, DeepEquals, found)
+}
+
+func (ft *FacadeUnitTest) Test_CheckCoordinatorStateNoPools(c *C) {
+	ft.setupMockDFSLocking()
+	ft.poolStore.On("GetResourcePools", ft.ctx).Return(nil, errors.New("elastic is down"))
+
+	_, err := ft.Facade.CheckCoordinatorState(ft.ctx, true)
+	c.Assert(err, NotNil)
+	ft.zzk.AssertNotCalled(c, "CheckState", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/events"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/volume"
 	"github.com/dustin/go-humanize"
@@ -67,8 +68,18 @@ var registryVersionInfos = map[int]registryVersionInfo{
 }
 
 // Backup takes a backup of all installed applications
-func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, snapshotSpacePercent int, backupFilename string) error {
+func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, snapshotSpacePercent int, backupFilename string) (err error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.Backup"))
+	tenants, err := f.GetTenantIDs(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not get tenants")
+		return err
+	}
+	detail := map[string]string{"file": backupFilename}
+	f.PublishEvent(forTenants(events.Event{Type: events.BackupStarted, Detail: detail}, tenants))
+	defer func() {
+		f.PublishEvent(forTenants(finishedEvent(events.BackupFinished, detail, err), tenants))
+	}()
 	// Do not DFSLock here, ControlPlaneDao does that
 	stime := time.Now()
 	message := fmt.Sprintf("started backup at %s", stime.UTC())
@@ -93,11 +104,6 @@ func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, s
 		return alog.Error(err)
 	}
 	plog.WithField("elapsed", time.Since(stime)).Info("Loaded resource pools")
-	tenants, err := f.GetTenantIDs(ctx)
-	if err != nil {
-		plog.WithError(err).Debug("Could not get tenants")
-		return alog.Error(err)
-	}
 	snapshots := make([]string, len(tenants))
 	snapshotExcludes := map[string][]string{}
 	for i, tenant := range tenants {
@@ -0,0 +1,77 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/events"
+)
+
+// PublishEvent publishes a cluster state change to the event stream.  If the
+// event names a service but not its tenant, the tenant is looked up so that
+// subscribers filtering by tenant receive it.
+func (f *Facade) PublishEvent(e events.Event) {
+	if e.ServiceID != "" && e.TenantID == "" {
+		if ctx := datastore.Get(); ctx != nil {
+			tenantID, err := f.GetTenantID(ctx, e.ServiceID)
+			if err != nil {
+				plog.WithError(err).WithField("serviceid", e.ServiceID).Debug("Could not look up tenant for event")
+			}
+			e.TenantID = tenantID
+		}
+	}
+	f.events.Publish(e)
+}
+
+// SubscribeEvents subscribes to cluster state changes that match the filter,
+// resuming after the event with the given id if possible.  See
+// events.Bus.Subscribe.
+func (f *Facade) SubscribeEvents(after string, filter events.Filter) (*events.Subscription, []events.Event, bool) {
+	return f.events.Subscribe(after, filter)
+}
+
+// finishedEvent returns an event that reports the outcome of an operation.
+func finishedEvent(typ events.Type, detail map[string]string, err error) events.Event {
+	result := make(map[string]string)
+	for k, v := range detail {
+		result[k] = v
+	}
+	if err != nil {
+		result["result"] = "failed"
+		result["error"] = err.Error()
+	} else {
+		result["result"] = "succeeded"
+	}
+	return events.Event{Type: typ, Detail: result}
+}
+
+// forTenants returns the event marked with the tenants the operation it
+// reports on was about.  An event carries a single tenant, so an operation
+// on several tenants lists them in the event's detail instead.
+func forTenants(e events.Event, tenantIDs []string) events.Event {
+	switch len(tenantIDs) {
+	case 0:
+	case 1:
+		e.TenantID = tenantIDs[0]
+	default:
+		detail := map[string]string{"tenantIds": strings.Join(tenantIDs, ",")}
+		for k, v := range e.Detail {
+			detail[k] = v
+		}
+		e.Detail = detail
+	}
+	return e
+}
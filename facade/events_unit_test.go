@@ -0,0 +1,109 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/events"
+	"github.com/control-center/serviced/health"
+	. "gopkg.in/check.v1"
+)
+
+// nextEvent returns the next event on the subscription, failing if there is
+// none waiting.
+func nextEvent(c *C, sub *events.Subscription) events.Event {
+	select {
+	case e := <-sub.Events():
+		return e
+	default:
+		c.Fatalf("expected an event")
+	}
+	return events.Event{}
+}
+
+func noEvent(c *C, sub *events.Subscription) {
+	select {
+	case e := <-sub.Events():
+		c.Fatalf("unexpected event %+v", e)
+	default:
+	}
+}
+
+func (ft *FacadeUnitTest) Test_ReportHealthStatusPublishesChanges(c *C) {
+	ft.Facade.SetHealthCache(health.New())
+	sub, _, _ := ft.Facade.SubscribeEvents("", events.Filter{ServiceID: "healthsvc"})
+	defer sub.Close()
+
+	key := health.HealthStatusKey{ServiceID: "healthsvc", InstanceID: 1, HealthCheckName: "answering"}
+
+	// the first report of a check is not a change
+	ft.Facade.ReportHealthStatus(key, health.HealthStatus{Status: health.OK}, time.Minute)
+	noEvent(c, sub)
+
+	// an unchanged status is not published again
+	ft.Facade.ReportHealthStatus(key, health.HealthStatus{Status: health.OK}, time.Minute)
+	noEvent(c, sub)
+
+	ft.Facade.ReportHealthStatus(key, health.HealthStatus{Status: health.Failed}, time.Minute)
+	e := nextEvent(c, sub)
+	c.Check(e.Type, Equals, events.HealthChanged)
+	c.Check(e.ServiceID, Equals, "healthsvc")
+	c.Check(e.InstanceID, Equals, 1)
+	c.Check(e.Detail, DeepEquals, map[string]string{"check": "answering", "status": "failed", "previous": "passed"})
+}
+
+func (ft *FacadeUnitTest) Test_DeployTemplatePublishesOutcome(c *C) {
+	sub, _, _ := ft.Facade.SubscribeEvents("", events.Filter{})
+	defer sub.Close()
+
+	ft.templateStore.On("Get", ft.ctx, "template").Return(nil, errors.New("no template"))
+	_, err := ft.Facade.DeployTemplate(ft.ctx, "pool", "template", "deployment", nil)
+	c.Assert(err, NotNil)
+
+	e := nextEvent(c, sub)
+	c.Check(e.Type, Equals, events.DeploymentStarted)
+	c.Check(e.Detail["deploymentId"], Equals, "deployment")
+	e = nextEvent(c, sub)
+	c.Check(e.Type, Equals, events.DeploymentFinished)
+	c.Check(e.Detail["deploymentId"], Equals, "deployment")
+	c.Check(e.Detail["result"], Equals, "failed")
+	c.Check(e.Detail["error"], Equals, "no template")
+	noEvent(c, sub)
+}
+
+func (ft *FacadeUnitTest) Test_BackupPublishesTenant(c *C) {
+	sub, _, _ := ft.Facade.SubscribeEvents("", events.Filter{TenantID: "tenant"})
+	defer sub.Close()
+
+	tenants := []service.ServiceDetails{{ID: "tenant"}}
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, "", time.Duration(0)).Return(tenants, nil)
+	ft.templateStore.On("GetServiceTemplates", ft.ctx).Return(nil, errors.New("no templates"))
+	err := ft.Facade.Backup(ft.ctx, ioutil.Discard, nil, 0, "backup.tgz")
+	c.Assert(err, NotNil)
+
+	e := nextEvent(c, sub)
+	c.Check(e.Type, Equals, events.BackupStarted)
+	c.Check(e.TenantID, Equals, "tenant")
+	e = nextEvent(c, sub)
+	c.Check(e.Type, Equals, events.BackupFinished)
+	c.Check(e.TenantID, Equals, "tenant")
+	c.Check(e.Detail["result"], Equals, "failed")
+	noEvent(c, sub)
+}
@@ -19,14 +19,19 @@ import (
 	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/apitoken"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/hostkey"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/snapshotschedule"
 	"github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/domain/webhook"
+	"github.com/control-center/serviced/events"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/metrics"
@@ -37,6 +42,7 @@ import (
 type MetricsClient interface {
 	GetInstanceMemoryStats(time.Time, ...metrics.ServiceInstance) ([]metrics.MemoryUsageStats, error)
 	GetAvailableStorage(time.Duration, string, ...string) (*metrics.StorageMetrics, error)
+	GetServiceMetric(time.Duration, string, string, bool) (*metrics.ServiceMetricStats, error)
 }
 
 // instantiate the package logger
@@ -57,11 +63,18 @@ func New() *Facade {
 		configStore:    serviceconfigfile.NewStore(),
 		templateStore:  servicetemplate.NewStore(),
 		logFilterStore: logfilter.NewStore(),
+		secretStore:    secret.NewStore(),
+		apiTokenStore:  apitoken.NewStore(),
+		webhookStore:   webhook.NewStore(),
+		scheduleStore:  snapshotschedule.NewStore(),
 		userStore:      user.NewStore(),
 		serviceCache:   NewServiceCache(),
 		poolCache:      NewPoolCache(),
 		hostRegistry:   auth.NewHostExpirationRegistry(),
 		deployments:    NewPendingDeploymentMgr(),
+		events:         events.NewBus(events.DefaultHistory),
+		webhooks:       newWebhookDispatcher(),
+		drains:         newDrainMgr(),
 		zzk:            getZZK(),
 	}
 }
@@ -74,6 +87,10 @@ type Facade struct {
 	poolStore      pool.Store
 	templateStore  servicetemplate.Store
 	logFilterStore logfilter.Store
+	secretStore    secret.Store
+	apiTokenStore  apitoken.Store
+	webhookStore   webhook.Store
+	scheduleStore  snapshotschedule.Store
 	serviceStore   service.Store
 	configStore    serviceconfigfile.Store
 	userStore      user.Store
@@ -87,6 +104,9 @@ type Facade struct {
 	poolCache     *poolCache
 	hostRegistry  auth.HostExpirationRegistryInterface
 	deployments   *PendingDeploymentMgr
+	events        *events.Bus
+	webhooks      *webhookDispatcher
+	drains        *drainMgr
 	ssm           servicestatemanager.ServiceStateManager
 	isvcsPath     string
 
@@ -128,6 +148,14 @@ func (f *Facade) SetTemplateStore(store servicetemplate.Store) { f.templateStore
 
 func (f *Facade) SetLogFilterStore(store logfilter.Store) { f.logFilterStore = store }
 
+func (f *Facade) SetSecretStore(store secret.Store) { f.secretStore = store }
+
+func (f *Facade) SetAPITokenStore(store apitoken.Store) { f.apiTokenStore = store }
+
+func (f *Facade) SetWebhookStore(store webhook.Store) { f.webhookStore = store }
+
+func (f *Facade) SetSnapshotScheduleStore(store snapshotschedule.Store) { f.scheduleStore = store }
+
 func (f *Facade) SetHealthCache(hcache *health.HealthStatusCache) { f.hcache = hcache }
 
 func (f *Facade) SetMetricsClient(client MetricsClient) { f.metricsClient = client }
@@ -23,14 +23,18 @@ import (
 	authmocks "github.com/control-center/serviced/auth/mocks"
 	datastoremocks "github.com/control-center/serviced/datastore/mocks"
 	dfsmocks "github.com/control-center/serviced/dfs/mocks"
+	apitokenmocks "github.com/control-center/serviced/domain/apitoken/mocks"
 	hostmocks "github.com/control-center/serviced/domain/host/mocks"
 	keymocks "github.com/control-center/serviced/domain/hostkey/mocks"
 	poolmocks "github.com/control-center/serviced/domain/pool/mocks"
 	registrymocks "github.com/control-center/serviced/domain/registry/mocks"
+	secretmocks "github.com/control-center/serviced/domain/secret/mocks"
 	servicemocks "github.com/control-center/serviced/domain/service/mocks"
 	configmocks "github.com/control-center/serviced/domain/serviceconfigfile/mocks"
 	templatemocks "github.com/control-center/serviced/domain/servicetemplate/mocks"
 	logfiltermocks "github.com/control-center/serviced/domain/logfilter/mocks"
+	schedulemocks "github.com/control-center/serviced/domain/snapshotschedule/mocks"
+	webhookmocks "github.com/control-center/serviced/domain/webhook/mocks"
 	"github.com/control-center/serviced/facade"
 	zzkmocks "github.com/control-center/serviced/facade/mocks"
 	"github.com/control-center/serviced/metrics"
@@ -53,6 +57,10 @@ type FacadeUnitTest struct {
 	configStore      *configmocks.Store
 	templateStore    *templatemocks.Store
 	logFilterStore   *logfiltermocks.Store
+	secretStore      *secretmocks.Store
+	apiTokenStore    *apitokenmocks.Store
+	webhookStore     *webhookmocks.Store
+	scheduleStore    *schedulemocks.Store
 	metricsClient    *zzkmocks.MetricsClient
 	hostauthregistry *authmocks.HostExpirationRegistryInterface
 }
@@ -63,6 +71,9 @@ func (ft *FacadeUnitTest) SetUpSuite(c *C) {
 	// Create a master key pair
 	pub, priv, _ := auth.GenerateRSAKeyPairPEM(nil)
 	auth.LoadMasterKeysFromPEM(pub, priv)
+
+	// Create a key to encrypt secrets
+	auth.LoadSecretKey(make([]byte, 32))
 }
 
 func (ft *FacadeUnitTest) SetUpTest(c *C) {
@@ -112,6 +123,18 @@ func (ft *FacadeUnitTest) SetUpTest(c *C) {
 	ft.logFilterStore = &logfiltermocks.Store{}
 	ft.Facade.SetLogFilterStore(ft.logFilterStore)
 
+	ft.secretStore = &secretmocks.Store{}
+	ft.Facade.SetSecretStore(ft.secretStore)
+
+	ft.apiTokenStore = &apitokenmocks.Store{}
+	ft.Facade.SetAPITokenStore(ft.apiTokenStore)
+
+	ft.webhookStore = &webhookmocks.Store{}
+	ft.Facade.SetWebhookStore(ft.webhookStore)
+
+	ft.scheduleStore = &schedulemocks.Store{}
+	ft.Facade.SetSnapshotScheduleStore(ft.scheduleStore)
+
 	ft.zzk = &zzkmocks.ZZK{}
 	ft.Facade.SetZZK(ft.zzk)
 
@@ -14,18 +14,44 @@
 package facade
 
 import (
+	"strings"
 	"time"
 
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/events"
 	"github.com/control-center/serviced/health"
 	zkservice "github.com/control-center/serviced/zzk/service"
 	"github.com/zenoss/glog"
 )
 
-// ReportHealthStatus writes the status of a health check to the cache.
+// ReportHealthStatus writes the status of a health check to the cache, and
+// publishes an event if the status of the check has changed.  The first
+// report of a check, e.g. from a new instance, has no previous status and is
+// not a change.
 func (f *Facade) ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration) {
+	previous, ok := f.hcache.Get(key)
 	f.hcache.Set(key, value, expires)
+	if ok && previous.Status != value.Status {
+		detail := map[string]string{
+			"check":    key.HealthCheckName,
+			"status":   healthStatusString(value.Status),
+			"previous": healthStatusString(previous.Status),
+		}
+		f.PublishEvent(events.Event{
+			Type:       events.HealthChanged,
+			ServiceID:  key.ServiceID,
+			InstanceID: key.InstanceID,
+			Detail:     detail,
+		})
+	}
+}
+
+// healthStatusString returns the name of a health status as it is rendered in
+// JSON.
+func healthStatusString(status health.Status) string {
+	b, _ := status.MarshalJSON()
+	return strings.Trim(string(b), `"`)
 }
 
 // ReportInstanceDead removes all health checks of a particular instance from
@@ -266,6 +266,9 @@ func (f *Facade) UpdateHost(ctx datastore.Context, entity *host.Host) error {
 		return alog.Error(err)
 	} else if host == nil {
 		return alog.Error(fmt.Errorf("host does not exist: %s", entity.ID))
+	} else {
+		// maintenance mode is only changed by draining or uncordoning the host
+		entity.Unschedulable = host.Unschedulable
 	}
 
 	// validate the pool exists
@@ -574,8 +577,9 @@ func toReadHost(h host.Host) host.ReadHost {
 			Date:    h.ServiceD.Date,
 			Release: h.ServiceD.Release,
 		},
-		IPs:       h.IPs,
-		CreatedAt: h.CreatedAt,
-		UpdatedAt: h.UpdatedAt,
+		IPs:           h.IPs,
+		Unschedulable: h.Unschedulable,
+		CreatedAt:     h.CreatedAt,
+		UpdatedAt:     h.UpdatedAt,
 	}
 }
@@ -0,0 +1,379 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// ErrHostNotDraining is returned when asking for the progress of a drain for a
+// host that has not been drained since the master started
+var ErrHostNotDraining = errors.New("facade: host has not been drained")
+
+// ErrHostDraining is returned when a drain is requested for a host that is
+// already being drained
+var ErrHostDraining = errors.New("facade: host is already being drained")
+
+// ErrLastSchedulableHost is returned when draining a host would leave no
+// online host in its pool that can be assigned instances
+var ErrLastSchedulableHost = errors.New("facade: no other host in the pool is available for scheduling")
+
+// drainPollInterval is how often to check whether a migrated instance has
+// started on its new host
+var drainPollInterval = time.Second
+
+// drainTask is a service instance that needs to be moved off of a host
+type drainTask struct {
+	svc   *service.Service
+	state zkservice.State
+}
+
+// drainOrder sorts instances by start level, with services that have no
+// start level going last, so that dependencies come up on the new hosts first.
+type drainOrder []drainTask
+
+func (d drainOrder) Len() int      { return len(d) }
+func (d drainOrder) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d drainOrder) Less(i, j int) bool {
+	a, b := d[i].svc.StartLevel-1, d[j].svc.StartLevel-1
+	if a != b {
+		return a < b
+	}
+	if d[i].svc.Name != d[j].svc.Name {
+		return d[i].svc.Name < d[j].svc.Name
+	}
+	return d[i].state.InstanceID < d[j].state.InstanceID
+}
+
+// drainMgr keeps the progress of the most recent drain of each host
+type drainMgr struct {
+	reports  map[string]host.DrainReport
+	draining map[string]bool
+	mutex    sync.Mutex
+}
+
+// newDrainMgr returns a new drainMgr
+func newDrainMgr() *drainMgr {
+	return &drainMgr{
+		reports:  make(map[string]host.DrainReport),
+		draining: make(map[string]bool),
+	}
+}
+
+// Start marks a host as being drained.  It returns false if the host is
+// already being drained.
+func (m *drainMgr) Start(hostID string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.draining[hostID] {
+		return false
+	}
+	m.draining[hostID] = true
+	return true
+}
+
+// Finish marks the drain of a host as done
+func (m *drainMgr) Finish(hostID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.draining, hostID)
+}
+
+// Update records the progress of a drain
+func (m *drainMgr) Update(report *host.DrainReport) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	r := *report
+	r.VirtualIPs = append([]string{}, report.VirtualIPs...)
+	r.Instances = append([]host.DrainInstance{}, report.Instances...)
+	m.reports[r.HostID] = r
+}
+
+// Get returns the progress of the most recent drain of a host
+func (m *drainMgr) Get(hostID string) (*host.DrainReport, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	r, ok := m.reports[hostID]
+	return &r, ok
+}
+
+// DrainHost puts a host into maintenance mode.  The host is marked
+// unschedulable, its virtual IPs are moved to other hosts in the pool, and its
+// service instances are migrated in start level order.  Each instance is given
+// up to timeout to start on its new host before moving on to the next.  The
+// report is available from GetDrainStatus as it is built.  The last host in
+// a pool that is available for scheduling cannot be drained.
+func (f *Facade) DrainHost(ctx datastore.Context, hostID string, timeout time.Duration) (*host.DrainReport, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.DrainHost"))
+	alog := f.auditLogger.Message(ctx, "Draining Host").Action(audit.Drain).ID(hostID).Type(host.GetType())
+	logger := plog.WithField("hostid", hostID)
+
+	if !f.drains.Start(hostID) {
+		return nil, alog.Error(ErrHostDraining)
+	}
+	defer f.drains.Finish(hostID)
+
+	report := &host.DrainReport{HostID: hostID, VirtualIPs: []string{}, Instances: []host.DrainInstance{}}
+	f.drains.Update(report)
+
+	hst, err := f.setHostUnschedulable(ctx, hostID, true)
+	if err != nil {
+		logger.WithError(err).Debug("Could not mark host as unschedulable")
+		return nil, alog.Error(err)
+	}
+	logger = logger.WithField("poolid", hst.PoolID)
+	logger.Info("Marked host as unschedulable")
+
+	// move the virtual ips first, so that instances bound to them can follow
+	p, err := f.GetResourcePool(ctx, hst.PoolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up resource pool")
+		return nil, alog.Error(err)
+	} else if p == nil {
+		return nil, alog.Error(ErrPoolNotExists)
+	}
+	if report.VirtualIPs, err = f.zzk.ReassignVirtualIPs(p, hostID); err != nil {
+		logger.WithError(err).Debug("Could not reassign virtual IPs")
+		return nil, alog.Error(err)
+	}
+	if len(report.VirtualIPs) > 0 {
+		logger.WithField("virtualips", report.VirtualIPs).Info("Reassigned virtual IPs")
+	}
+	f.drains.Update(report)
+
+	targets, err := f.getDrainTargets(ctx, hst.PoolID, hostID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up hosts available for scheduling")
+		return nil, alog.Error(err)
+	}
+
+	states, err := f.zzk.GetHostStates(ctx, hst.PoolID, hostID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up instances on host")
+		return nil, alog.Error(err)
+	}
+
+	svcs := make(map[string]*service.Service)
+	tasks := make([]drainTask, len(states))
+	for i, state := range states {
+		svc, ok := svcs[state.ServiceID]
+		if !ok {
+			if svc, err = f.serviceStore.Get(ctx, state.ServiceID); err != nil {
+				logger.WithError(err).WithField("serviceid", state.ServiceID).Debug("Could not look up service")
+				return nil, alog.Error(err)
+			}
+			svcs[state.ServiceID] = svc
+		}
+		tasks[i] = drainTask{svc: svc, state: state}
+	}
+	sort.Sort(drainOrder(tasks))
+
+	for _, task := range tasks {
+		inst := f.drainInstance(ctx, task, targets, timeout)
+		logger.WithFields(log.Fields{
+			"serviceid":  inst.ServiceID,
+			"instanceid": inst.InstanceID,
+			"status":     inst.Status,
+			"newhostid":  inst.HostID,
+		}).Info(fmt.Sprintf("Drained instance %s/%d", inst.ServiceName, inst.InstanceID))
+		report.Instances = append(report.Instances, inst)
+		f.drains.Update(report)
+	}
+
+	return report, alog.Error(nil)
+}
+
+// GetDrainStatus returns the progress of the most recent drain of a host.  The
+// instances that have been drained so far are listed in the order they were
+// drained.
+func (f *Facade) GetDrainStatus(ctx datastore.Context, hostID string) (*host.DrainReport, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetDrainStatus"))
+	report, ok := f.drains.Get(hostID)
+	if !ok {
+		return nil, ErrHostNotDraining
+	}
+	return report, nil
+}
+
+// UncordonHost takes a host out of maintenance mode, so that it can receive
+// service instances and virtual IPs again.
+func (f *Facade) UncordonHost(ctx datastore.Context, hostID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.UncordonHost"))
+	alog := f.auditLogger.Message(ctx, "Uncordoning Host").Action(audit.Uncordon).ID(hostID).Type(host.GetType())
+	if _, err := f.setHostUnschedulable(ctx, hostID, false); err != nil {
+		plog.WithField("hostid", hostID).WithError(err).Debug("Could not mark host as schedulable")
+		return alog.Error(err)
+	}
+	return alog.Error(nil)
+}
+
+// setHostUnschedulable updates the maintenance flag on the host
+func (f *Facade) setHostUnschedulable(ctx datastore.Context, hostID string, unschedulable bool) (*host.Host, error) {
+	if err := f.DFSLock(ctx).LockWithTimeout("update host scheduling", userLockTimeout); err != nil {
+		return nil, err
+	}
+	defer f.DFSLock(ctx).Unlock()
+
+	hst, err := f.GetHost(ctx, hostID)
+	if err != nil {
+		return nil, err
+	} else if hst == nil {
+		return nil, ErrHostDoesNotExist
+	}
+
+	// the scheduler waits for a host to become available, so at least one
+	// must always be left in the pool
+	if unschedulable {
+		targets, err := f.getDrainTargets(ctx, hst.PoolID, hostID)
+		if err != nil {
+			return nil, err
+		} else if len(targets) == 0 {
+			return nil, ErrLastSchedulableHost
+		}
+	}
+
+	hst.Unschedulable = unschedulable
+	hst.UpdatedAt = time.Now()
+	if err := f.hostStore.Put(ctx, host.HostKey(hst.ID), hst); err != nil {
+		return nil, err
+	}
+	if err := f.zzk.UpdateHost(hst); err != nil {
+		return nil, err
+	}
+	f.poolCache.SetDirty()
+	return hst, nil
+}
+
+// getDrainTargets returns the set of online hosts in the pool, other than the
+// one being drained, that can be assigned instances.
+func (f *Facade) getDrainTargets(ctx datastore.Context, poolID, hostID string) (map[string]bool, error) {
+	var active []string
+	if err := f.zzk.GetActiveHosts(ctx, poolID, &active); err != nil {
+		return nil, err
+	}
+	isActive := make(map[string]bool)
+	for _, id := range active {
+		isActive[id] = true
+	}
+
+	hosts, err := f.hostStore.FindHostsWithPoolID(ctx, poolID)
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[string]bool)
+	for _, h := range hosts {
+		if h.ID != hostID && isActive[h.ID] && !h.Unschedulable {
+			targets[h.ID] = true
+		}
+	}
+	return targets, nil
+}
+
+// drainInstance moves a single instance off of the host being drained and
+// waits for it to start somewhere else.
+func (f *Facade) drainInstance(ctx datastore.Context, task drainTask, targets map[string]bool, timeout time.Duration) host.DrainInstance {
+	svc, state := task.svc, task.state
+	inst := host.DrainInstance{
+		ServiceID:   svc.ID,
+		ServiceName: svc.Name,
+		InstanceID:  state.InstanceID,
+		StartLevel:  svc.StartLevel,
+	}
+	logger := plog.WithFields(log.Fields{
+		"hostid":     state.HostID,
+		"serviceid":  svc.ID,
+		"instanceid": state.InstanceID,
+	})
+
+	// instances of services that are not supposed to be running are just
+	// stopped.
+	if svc.DesiredState != int(service.SVCRun) {
+		if err := f.zzk.StopServiceInstance(svc.PoolID, svc.ID, state.InstanceID); err != nil {
+			logger.WithError(err).Debug("Could not stop service instance")
+			inst.Status, inst.Message = host.DrainFailed, err.Error()
+			return inst
+		}
+		inst.Status, inst.Message = host.DrainStopped, "service is not running"
+		return inst
+	}
+
+	// an instance that is bound to one of this host's ips cannot move
+	if state.Static && state.AssignedIP != "" {
+		inst.Status = host.DrainSkipped
+		inst.Message = fmt.Sprintf("instance is assigned to static IP %s on this host", state.AssignedIP)
+		return inst
+	}
+
+	available := len(targets)
+	if svc.HostPolicy == servicedefinition.RequireSeparate {
+		others, err := f.zzk.GetServiceStates(ctx, svc.PoolID, svc.ID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not look up service instances")
+			inst.Status, inst.Message = host.DrainFailed, err.Error()
+			return inst
+		}
+		occupied := make(map[string]bool)
+		for _, other := range others {
+			if targets[other.HostID] && !occupied[other.HostID] {
+				occupied[other.HostID] = true
+				available--
+			}
+		}
+	}
+	if available <= 0 {
+		inst.Status = host.DrainSkipped
+		inst.Message = "no other host is available to run the instance"
+		if svc.HostPolicy == servicedefinition.RequireSeparate {
+			inst.Message += " (" + servicedefinition.RequireSeparate + ")"
+		}
+		return inst
+	}
+
+	if err := f.zzk.StopServiceInstance(svc.PoolID, svc.ID, state.InstanceID); err != nil {
+		logger.WithError(err).Debug("Could not stop service instance")
+		inst.Status, inst.Message = host.DrainFailed, err.Error()
+		return inst
+	}
+
+	// the scheduler will start a replacement once the instance is gone from
+	// this host.
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		current, err := f.zzk.GetServiceState(ctx, svc.PoolID, svc.ID, state.InstanceID)
+		if err == nil && current.HostID != state.HostID && current.Status == service.StateRunning {
+			inst.Status, inst.HostID = host.DrainMigrated, current.HostID
+			return inst
+		}
+
+		select {
+		case <-timer.C:
+			inst.Status = host.DrainFailed
+			inst.Message = fmt.Sprintf("instance did not start on another host within %s", timeout)
+			return inst
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
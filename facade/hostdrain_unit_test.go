@@ -0,0 +1,221 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/facade"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupDrainHost(hosts []host.Host, states []zkservice.State, svcs []service.Service) {
+	ft.setupMockDFSLocking()
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("h1"), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = host.Host{ID: "h1", PoolID: "default"}
+		})
+	ft.hostStore.On("Put", ft.ctx, host.HostKey("h1"), mock.AnythingOfType("*host.Host")).Return(nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "default").Return(hosts, nil)
+	ft.zzk.On("UpdateHost", mock.AnythingOfType("*host.Host")).Return(nil)
+	ft.poolStore.On("Get", ft.ctx, pool.Key("default"), mock.AnythingOfType("*pool.ResourcePool")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*pool.ResourcePool) = pool.ResourcePool{ID: "default"}
+		})
+	ft.zzk.On("ReassignVirtualIPs", mock.AnythingOfType("*pool.ResourcePool"), "h1").Return([]string{"10.0.0.5"}, nil)
+	ft.zzk.On("GetActiveHosts", ft.ctx, "default", mock.AnythingOfType("*[]string")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			ids := args.Get(2).(*[]string)
+			for _, h := range hosts {
+				*ids = append(*ids, h.ID)
+			}
+		})
+	ft.zzk.On("GetHostStates", ft.ctx, "default", "h1").Return(states, nil)
+	for i := range svcs {
+		ft.serviceStore.On("Get", ft.ctx, svcs[i].ID).Return(&svcs[i], nil)
+	}
+}
+
+func (ft *FacadeUnitTest) Test_DrainHost(c *C) {
+	hosts := []host.Host{
+		{ID: "h1", PoolID: "default"},
+		{ID: "h2", PoolID: "default"},
+		{ID: "h3", PoolID: "default", Unschedulable: true},
+	}
+	svcs := []service.Service{
+		{ID: "web", Name: "web", PoolID: "default", DesiredState: int(service.SVCRun)},
+		{ID: "db", Name: "db", PoolID: "default", StartLevel: 1, DesiredState: int(service.SVCRun)},
+		{ID: "app", Name: "app", PoolID: "default", StartLevel: 2, DesiredState: int(service.SVCRun), HostPolicy: servicedefinition.RequireSeparate},
+		{ID: "ldap", Name: "ldap", PoolID: "default", StartLevel: 2, DesiredState: int(service.SVCRun)},
+		{ID: "job", Name: "job", PoolID: "default", StartLevel: 3, DesiredState: int(service.SVCStop)},
+	}
+	states := []zkservice.State{
+		{HostID: "h1", ServiceID: "web", InstanceID: 0},
+		{HostID: "h1", ServiceID: "db", InstanceID: 0},
+		{HostID: "h1", ServiceID: "app", InstanceID: 1},
+		{HostID: "h1", ServiceID: "ldap", InstanceID: 0, ServiceState: zkservice.ServiceState{Static: true, AssignedIP: "192.168.1.10"}},
+		{HostID: "h1", ServiceID: "job", InstanceID: 0},
+	}
+	ft.setupDrainHost(hosts, states, svcs)
+
+	// the other app instance is already on the only available host
+	ft.zzk.On("GetServiceStates", ft.ctx, "default", "app").Return([]zkservice.State{
+		{HostID: "h1", ServiceID: "app", InstanceID: 1},
+		{HostID: "h2", ServiceID: "app", InstanceID: 0},
+	}, nil)
+	ft.zzk.On("StopServiceInstance", "default", "job", 0).Return(nil)
+	for _, id := range []string{"web", "db"} {
+		ft.zzk.On("StopServiceInstance", "default", id, 0).Return(nil)
+		ft.zzk.On("GetServiceState", ft.ctx, "default", id, 0).Return(&zkservice.State{
+			HostID:                "h2",
+			ServiceID:             id,
+			CurrentStateContainer: zkservice.CurrentStateContainer{Status: service.StateRunning},
+		}, nil)
+	}
+
+	report, err := ft.Facade.DrainHost(ft.ctx, "h1", time.Minute)
+	c.Assert(err, IsNil)
+	c.Assert(report.VirtualIPs, DeepEquals, []string{"10.0.0.5"})
+	c.Assert(report.Instances, HasLen, 5)
+
+	expected := []struct {
+		serviceID string
+		status    string
+		hostID    string
+	}{
+		{"db", host.DrainMigrated, "h2"},
+		{"app", host.DrainSkipped, ""},
+		{"ldap", host.DrainSkipped, ""},
+		{"job", host.DrainStopped, ""},
+		{"web", host.DrainMigrated, "h2"},
+	}
+	for i, e := range expected {
+		inst := report.Instances[i]
+		c.Check(inst.ServiceID, Equals, e.serviceID)
+		c.Check(inst.Status, Equals, e.status)
+		c.Check(inst.HostID, Equals, e.hostID)
+	}
+	c.Assert(report.Drained(), Equals, false)
+
+	ft.hostStore.AssertCalled(c, "Put", ft.ctx, host.HostKey("h1"), mock.MatchedBy(func(h *host.Host) bool {
+		return h.Unschedulable
+	}))
+	ft.zzk.AssertNotCalled(c, "StopServiceInstance", "default", "app", 1)
+	ft.zzk.AssertNotCalled(c, "StopServiceInstance", "default", "ldap", 0)
+}
+
+func (ft *FacadeUnitTest) Test_DrainHostTimeout(c *C) {
+	hosts := []host.Host{{ID: "h1", PoolID: "default"}, {ID: "h2", PoolID: "default"}}
+	svcs := []service.Service{{ID: "web", Name: "web", PoolID: "default", DesiredState: int(service.SVCRun)}}
+	states := []zkservice.State{{HostID: "h1", ServiceID: "web", InstanceID: 0}}
+	ft.setupDrainHost(hosts, states, svcs)
+
+	ft.zzk.On("StopServiceInstance", "default", "web", 0).Return(nil)
+	ft.zzk.On("GetServiceState", ft.ctx, "default", "web", 0).Return(nil, errors.New("no state"))
+
+	report, err := ft.Facade.DrainHost(ft.ctx, "h1", 10*time.Millisecond)
+	c.Assert(err, IsNil)
+	c.Assert(report.Instances, HasLen, 1)
+	c.Assert(report.Instances[0].Status, Equals, host.DrainFailed)
+	c.Assert(report.Instances[0].Message, Equals, "instance did not start on another host within 10ms")
+	c.Assert(report.Drained(), Equals, false)
+}
+
+func (ft *FacadeUnitTest) Test_DrainHostLastSchedulableHost(c *C) {
+	hosts := []host.Host{{ID: "h1", PoolID: "default"}, {ID: "h2", PoolID: "default", Unschedulable: true}}
+	ft.setupDrainHost(hosts, nil, nil)
+
+	_, err := ft.Facade.DrainHost(ft.ctx, "h1", time.Minute)
+	c.Assert(err, Equals, facade.ErrLastSchedulableHost)
+	ft.hostStore.AssertNotCalled(c, "Put", ft.ctx, host.HostKey("h1"), mock.AnythingOfType("*host.Host"))
+}
+
+func (ft *FacadeUnitTest) Test_DrainHostTwice(c *C) {
+	hosts := []host.Host{{ID: "h1", PoolID: "default"}, {ID: "h2", PoolID: "default"}}
+	svcs := []service.Service{{ID: "web", Name: "web", PoolID: "default", DesiredState: int(service.SVCRun)}}
+	states := []zkservice.State{{HostID: "h1", ServiceID: "web", InstanceID: 0}}
+	ft.setupDrainHost(hosts, states, svcs)
+
+	// hold the first drain while the instance is being stopped
+	stopping, release := make(chan struct{}), make(chan struct{})
+	ft.zzk.On("StopServiceInstance", "default", "web", 0).Return(nil).Run(func(mock.Arguments) {
+		close(stopping)
+		<-release
+	})
+	ft.zzk.On("GetServiceState", ft.ctx, "default", "web", 0).Return(nil, errors.New("no state"))
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := ft.Facade.DrainHost(ft.ctx, "h1", 10*time.Millisecond)
+		errc <- err
+	}()
+	<-stopping
+	_, err := ft.Facade.DrainHost(ft.ctx, "h1", 10*time.Millisecond)
+	c.Assert(err, Equals, facade.ErrHostDraining)
+	close(release)
+	c.Assert(<-errc, IsNil)
+}
+
+func (ft *FacadeUnitTest) Test_GetDrainStatus(c *C) {
+	_, err := ft.Facade.GetDrainStatus(ft.ctx, "h2")
+	c.Assert(err, Equals, facade.ErrHostNotDraining)
+
+	hosts := []host.Host{{ID: "h1", PoolID: "default"}, {ID: "h2", PoolID: "default"}}
+	svcs := []service.Service{{ID: "web", Name: "web", PoolID: "default", DesiredState: int(service.SVCRun)}}
+	states := []zkservice.State{{HostID: "h1", ServiceID: "web", InstanceID: 0}}
+	ft.setupDrainHost(hosts, states, svcs)
+
+	ft.zzk.On("StopServiceInstance", "default", "web", 0).Return(nil)
+	ft.zzk.On("GetServiceState", ft.ctx, "default", "web", 0).Return(nil, errors.New("no state"))
+
+	report, err := ft.Facade.DrainHost(ft.ctx, "h1", 10*time.Millisecond)
+	c.Assert(err, IsNil)
+
+	status, err := ft.Facade.GetDrainStatus(ft.ctx, "h1")
+	c.Assert(err, IsNil)
+	c.Assert(status, DeepEquals, report)
+}
+
+func (ft *FacadeUnitTest) Test_UpdateHostKeepsUnschedulable(c *C) {
+	ft.setupMockDFSLocking()
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("h1"), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = host.Host{ID: "h1", PoolID: "default", Unschedulable: true}
+		})
+	ft.poolStore.On("Get", ft.ctx, pool.Key("default"), mock.AnythingOfType("*pool.ResourcePool")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*pool.ResourcePool) = pool.ResourcePool{ID: "default"}
+		})
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "default").Return([]host.Host{}, nil)
+	ft.hostStore.On("Put", ft.ctx, host.HostKey("h1"), mock.AnythingOfType("*host.Host")).Return(nil)
+	ft.zzk.On("UpdateHost", mock.AnythingOfType("*host.Host")).Return(nil)
+
+	entity := &host.Host{ID: "h1", PoolID: "default", Name: "renamed"}
+	err := ft.Facade.UpdateHost(ft.ctx, entity)
+	c.Assert(err, IsNil)
+	c.Assert(entity.Unschedulable, Equals, true)
+}
@@ -254,6 +254,7 @@ func (f *Facade) getInstance(ctx datastore.Context, hst host.Host, svc service.S
 		Scheduled:     state.Scheduled,
 		Started:       state.Started,
 		Terminated:    state.Terminated,
+		Crashes:       state.Crashes,
 	}
 	logger.Debug("Loaded service instance")
 
@@ -440,6 +441,7 @@ func (f *Facade) GetHostStrategyInstances(ctx datastore.Context, hosts []host.Ho
 				inst = service.StrategyInstance{
 					ServiceID:     s.ID,
 					CPUCommitment: int(s.CPUCommitment),
+					CPUQuota:      s.CPUQuota,
 					RAMCommitment: s.RAMCommitment.Value,
 					HostPolicy:    s.HostPolicy,
 				}
@@ -22,13 +22,20 @@ import (
 	"github.com/control-center/serviced/health"
 
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/apitoken"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/snapshotschedule"
 	"github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/domain/webhook"
+	"github.com/control-center/serviced/events"
 	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 // The FacadeInterface is the API for a Facade
@@ -72,7 +79,7 @@ type FacadeInterface interface {
 
 	UpdateServiceTemplate(ctx datastore.Context, template servicetemplate.ServiceTemplate, reloadLogstashConfig bool) error
 
-	DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string) ([]string, error)
+	DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string, parameters map[string]string) ([]string, error)
 
 	DeployTemplateActive() (active []map[string]string, err error)
 
@@ -106,6 +113,12 @@ type FacadeInterface interface {
 
 	FindHostsInPool(ctx datastore.Context, poolID string) ([]host.Host, error)
 
+	DrainHost(ctx datastore.Context, hostID string, timeout time.Duration) (*host.DrainReport, error)
+
+	GetDrainStatus(ctx datastore.Context, hostID string) (*host.DrainReport, error)
+
+	UncordonHost(ctx datastore.Context, hostID string) error
+
 	AddResourcePool(ctx datastore.Context, entity *pool.ResourcePool) error
 
 	GetResourcePool(ctx datastore.Context, poolID string) (*pool.ResourcePool, error)
@@ -120,6 +133,8 @@ type FacadeInterface interface {
 
 	UpdateResourcePool(ctx datastore.Context, entity *pool.ResourcePool) error
 
+	GetVirtualIPStatus(ctx datastore.Context, poolID string) ([]zkservice.VirtualIPStatus, error)
+
 	GetHealthChecksForService(ctx datastore.Context, id string) (map[string]health.HealthCheck, error)
 
 	AddPublicEndpointPort(ctx datastore.Context, serviceid, endpointName, portAddr string, usetls bool, protocol string, isEnabled bool, restart bool) (*servicedefinition.Port, error)
@@ -194,6 +209,16 @@ type FacadeInterface interface {
 
 	DeleteServiceConfig(ctx datastore.Context, fileID string) error
 
+	RenderServiceConfigs(ctx datastore.Context, serviceID string, instanceID int) ([]servicedefinition.ConfigFile, error)
+
+	GetServiceConfigDrift(ctx datastore.Context, serviceID string) ([]service.ConfigDrift, error)
+
+	GetServiceDependencyGraph(ctx datastore.Context, serviceID string) (*service.DependencyGraph, error)
+
+	GetRollingRestartStatus(ctx datastore.Context, serviceID string) ([]service.RollingRestartStatus, error)
+
+	SetServiceImage(ctx datastore.Context, serviceID, imageID string, rollout *service.RollingRestart) (bool, error)
+
 	GetHostStatuses(ctx datastore.Context, hostIDs []string, since time.Time) ([]host.HostStatus, error)
 
 	UpdateServiceCache(ctx datastore.Context) error
@@ -219,4 +244,42 @@ type FacadeInterface interface {
 	StopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error)
 
 	PauseService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error)
+
+	SetSecret(ctx datastore.Context, tenantID, name, value string) error
+
+	GetSecrets(ctx datastore.Context, tenantID string) ([]secret.Secret, error)
+
+	RemoveSecret(ctx datastore.Context, tenantID, name string) error
+
+	CreateAPIToken(ctx datastore.Context, name string, scope apitoken.Scope, expires time.Time) (string, *apitoken.APIToken, error)
+
+	GetAPITokens(ctx datastore.Context) ([]apitoken.APIToken, error)
+
+	RevokeAPIToken(ctx datastore.Context, idOrName string) error
+
+	AuthenticateAPIToken(ctx datastore.Context, bearer string) (*apitoken.APIToken, error)
+
+	AddWebhook(ctx datastore.Context, url string, eventTypes []string, tenantID, secret string) (string, *webhook.Webhook, error)
+
+	GetWebhooks(ctx datastore.Context) ([]webhook.Webhook, error)
+
+	RemoveWebhook(ctx datastore.Context, id string) error
+
+	SetSnapshotSchedule(ctx datastore.Context, serviceID string, interval time.Duration, cron string, retention snapshotschedule.Retention) (*snapshotschedule.SnapshotSchedule, error)
+
+	GetSnapshotSchedules(ctx datastore.Context) ([]snapshotschedule.SnapshotSchedule, error)
+
+	RemoveSnapshotSchedule(ctx datastore.Context, serviceID string) error
+
+	CloneSnapshot(ctx datastore.Context, snapshotID, name string) (string, error)
+
+	DiffSnapshots(ctx datastore.Context, snapshotID, toSnapshotID string) (*dao.SnapshotDiff, error)
+
+	PublishEvent(e events.Event)
+
+	SubscribeEvents(after string, filter events.Filter) (*events.Subscription, []events.Event, bool)
+
+	GetCoordinatorTree(ctx datastore.Context, pth string) (*zzk.TreeNode, error)
+
+	CheckCoordinatorState(ctx datastore.Context, repair bool) ([]zkservice.Inconsistency, error)
 }
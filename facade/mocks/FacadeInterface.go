@@ -9,12 +9,19 @@ import health "github.com/control-center/serviced/health"
 import host "github.com/control-center/serviced/domain/host"
 import mock "github.com/stretchr/testify/mock"
 import pool "github.com/control-center/serviced/domain/pool"
+import secret "github.com/control-center/serviced/domain/secret"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
 import servicetemplate "github.com/control-center/serviced/domain/servicetemplate"
 import time "time"
 import user "github.com/control-center/serviced/domain/user"
+import apitoken "github.com/control-center/serviced/domain/apitoken"
+import events "github.com/control-center/serviced/events"
+import webhook "github.com/control-center/serviced/domain/webhook"
+import snapshotschedule "github.com/control-center/serviced/domain/snapshotschedule"
 import "github.com/control-center/serviced/utils"
+import zzk "github.com/control-center/serviced/zzk"
+import zkservice "github.com/control-center/serviced/zzk/service"
 
 // FacadeInterface is an autogenerated mock type for the FacadeInterface type
 type FacadeInterface struct {
@@ -290,13 +297,13 @@ func (_m *FacadeInterface) DeleteServiceConfig(ctx datastore.Context, fileID str
 	return r0
 }
 
-// DeployTemplate provides a mock function with given fields: ctx, poolID, templateID, deploymentID
-func (_m *FacadeInterface) DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string) ([]string, error) {
-	ret := _m.Called(ctx, poolID, templateID, deploymentID)
+// DeployTemplate provides a mock function with given fields: ctx, poolID, templateID, deploymentID, parameters
+func (_m *FacadeInterface) DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string, parameters map[string]string) ([]string, error) {
+	ret := _m.Called(ctx, poolID, templateID, deploymentID, parameters)
 
 	var r0 []string
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) []string); ok {
-		r0 = rf(ctx, poolID, templateID, deploymentID)
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, map[string]string) []string); ok {
+		r0 = rf(ctx, poolID, templateID, deploymentID, parameters)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
@@ -304,8 +311,8 @@ func (_m *FacadeInterface) DeployTemplate(ctx datastore.Context, poolID string,
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, string) error); ok {
-		r1 = rf(ctx, poolID, templateID, deploymentID)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, string, map[string]string) error); ok {
+		r1 = rf(ctx, poolID, templateID, deploymentID, parameters)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1807,3 +1814,594 @@ func (_m *FacadeInterface) GetServiceNamePath(ctx datastore.Context, serviceID s
 
 	return r0, r1, r2
 }
+
+// SetSecret provides a mock function with given fields: ctx, tenantID, name, value
+func (_m *FacadeInterface) SetSecret(ctx datastore.Context, tenantID string, name string, value string) error {
+	ret := _m.Called(ctx, tenantID, name, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) error); ok {
+		r0 = rf(ctx, tenantID, name, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSecrets provides a mock function with given fields: ctx, tenantID
+func (_m *FacadeInterface) GetSecrets(ctx datastore.Context, tenantID string) ([]secret.Secret, error) {
+	ret := _m.Called(ctx, tenantID)
+
+	var r0 []secret.Secret
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []secret.Secret); ok {
+		r0 = rf(ctx, tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]secret.Secret)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSecret provides a mock function with given fields: ctx, tenantID, name
+func (_m *FacadeInterface) RemoveSecret(ctx datastore.Context, tenantID string, name string) error {
+	ret := _m.Called(ctx, tenantID, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) error); ok {
+		r0 = rf(ctx, tenantID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenderServiceConfigs provides a mock function with given fields: ctx, serviceID, instanceID
+func (_m *FacadeInterface) RenderServiceConfigs(ctx datastore.Context, serviceID string, instanceID int) ([]servicedefinition.ConfigFile, error) {
+	ret := _m.Called(ctx, serviceID, instanceID)
+
+	var r0 []servicedefinition.ConfigFile
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, int) []servicedefinition.ConfigFile); ok {
+		r0 = rf(ctx, serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]servicedefinition.ConfigFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, int) error); ok {
+		r1 = rf(ctx, serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceConfigDrift provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetServiceConfigDrift(ctx datastore.Context, serviceID string) ([]service.ConfigDrift, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 []service.ConfigDrift
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []service.ConfigDrift); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ConfigDrift)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceDependencyGraph provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetServiceDependencyGraph(ctx datastore.Context, serviceID string) (*service.DependencyGraph, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 *service.DependencyGraph
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *service.DependencyGraph); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.DependencyGraph)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UncordonHost provides a mock function with given fields: ctx, hostID
+func (_m *FacadeInterface) UncordonHost(ctx datastore.Context, hostID string) error {
+	ret := _m.Called(ctx, hostID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, hostID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDrainStatus provides a mock function with given fields: ctx, hostID
+func (_m *FacadeInterface) GetDrainStatus(ctx datastore.Context, hostID string) (*host.DrainReport, error) {
+	ret := _m.Called(ctx, hostID)
+
+	var r0 *host.DrainReport
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *host.DrainReport); ok {
+		r0 = rf(ctx, hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.DrainReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DrainHost provides a mock function with given fields: ctx, hostID, timeout
+func (_m *FacadeInterface) DrainHost(ctx datastore.Context, hostID string, timeout time.Duration) (*host.DrainReport, error) {
+	ret := _m.Called(ctx, hostID, timeout)
+
+	var r0 *host.DrainReport
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, time.Duration) *host.DrainReport); ok {
+		r0 = rf(ctx, hostID, timeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.DrainReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, hostID, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCoordinatorTree provides a mock function with given fields: ctx, pth
+func (_m *FacadeInterface) GetCoordinatorTree(ctx datastore.Context, pth string) (*zzk.TreeNode, error) {
+	ret := _m.Called(ctx, pth)
+
+	var r0 *zzk.TreeNode
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *zzk.TreeNode); ok {
+		r0 = rf(ctx, pth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*zzk.TreeNode)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, pth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckCoordinatorState provides a mock function with given fields: ctx, repair
+func (_m *FacadeInterface) CheckCoordinatorState(ctx datastore.Context, repair bool) ([]zkservice.Inconsistency, error) {
+	ret := _m.Called(ctx, repair)
+
+	var r0 []zkservice.Inconsistency
+	if rf, ok := ret.Get(0).(func(datastore.Context, bool) []zkservice.Inconsistency); ok {
+		r0 = rf(ctx, repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]zkservice.Inconsistency)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, bool) error); ok {
+		r1 = rf(ctx, repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVirtualIPStatus provides a mock function with given fields: ctx, poolID
+func (_m *FacadeInterface) GetVirtualIPStatus(ctx datastore.Context, poolID string) ([]zkservice.VirtualIPStatus, error) {
+	ret := _m.Called(ctx, poolID)
+
+	var r0 []zkservice.VirtualIPStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []zkservice.VirtualIPStatus); ok {
+		r0 = rf(ctx, poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]zkservice.VirtualIPStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRollingRestartStatus provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetRollingRestartStatus(ctx datastore.Context, serviceID string) ([]service.RollingRestartStatus, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 []service.RollingRestartStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []service.RollingRestartStatus); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.RollingRestartStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetServiceImage provides a mock function with given fields: ctx, serviceID, imageID, rollout
+func (_m *FacadeInterface) SetServiceImage(ctx datastore.Context, serviceID string, imageID string, rollout *service.RollingRestart) (bool, error) {
+	ret := _m.Called(ctx, serviceID, imageID, rollout)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, *service.RollingRestart) bool); ok {
+		r0 = rf(ctx, serviceID, imageID, rollout)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, *service.RollingRestart) error); ok {
+		r1 = rf(ctx, serviceID, imageID, rollout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAPIToken provides a mock function with given fields: ctx, name, scope, expires
+func (_m *FacadeInterface) CreateAPIToken(ctx datastore.Context, name string, scope apitoken.Scope, expires time.Time) (string, *apitoken.APIToken, error) {
+	ret := _m.Called(ctx, name, scope, expires)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, apitoken.Scope, time.Time) string); ok {
+		r0 = rf(ctx, name, scope, expires)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 *apitoken.APIToken
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, apitoken.Scope, time.Time) *apitoken.APIToken); ok {
+		r1 = rf(ctx, name, scope, expires)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*apitoken.APIToken)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(datastore.Context, string, apitoken.Scope, time.Time) error); ok {
+		r2 = rf(ctx, name, scope, expires)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RevokeAPIToken provides a mock function with given fields: ctx, idOrName
+func (_m *FacadeInterface) RevokeAPIToken(ctx datastore.Context, idOrName string) error {
+	ret := _m.Called(ctx, idOrName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, idOrName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAPITokens provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetAPITokens(ctx datastore.Context) ([]apitoken.APIToken, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []apitoken.APIToken
+	if rf, ok := ret.Get(0).(func(datastore.Context) []apitoken.APIToken); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]apitoken.APIToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthenticateAPIToken provides a mock function with given fields: ctx, bearer
+func (_m *FacadeInterface) AuthenticateAPIToken(ctx datastore.Context, bearer string) (*apitoken.APIToken, error) {
+	ret := _m.Called(ctx, bearer)
+
+	var r0 *apitoken.APIToken
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *apitoken.APIToken); ok {
+		r0 = rf(ctx, bearer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*apitoken.APIToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, bearer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishEvent provides a mock function with given fields: e
+func (_m *FacadeInterface) PublishEvent(e events.Event) {
+	_m.Called(e)
+}
+
+// SubscribeEvents provides a mock function with given fields: after, filter
+func (_m *FacadeInterface) SubscribeEvents(after string, filter events.Filter) (*events.Subscription, []events.Event, bool) {
+	ret := _m.Called(after, filter)
+
+	var r0 *events.Subscription
+	if rf, ok := ret.Get(0).(func(string, events.Filter) *events.Subscription); ok {
+		r0 = rf(after, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*events.Subscription)
+		}
+	}
+
+	var r1 []events.Event
+	if rf, ok := ret.Get(1).(func(string, events.Filter) []events.Event); ok {
+		r1 = rf(after, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]events.Event)
+		}
+	}
+
+	var r2 bool
+	if rf, ok := ret.Get(2).(func(string, events.Filter) bool); ok {
+		r2 = rf(after, filter)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	return r0, r1, r2
+}
+
+// AddWebhook provides a mock function with given fields: ctx, url, eventTypes, tenantID, secret
+func (_m *FacadeInterface) AddWebhook(ctx datastore.Context, url string, eventTypes []string, tenantID string, secret string) (string, *webhook.Webhook, error) {
+	ret := _m.Called(ctx, url, eventTypes, tenantID, secret)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, []string, string, string) string); ok {
+		r0 = rf(ctx, url, eventTypes, tenantID, secret)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 *webhook.Webhook
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, []string, string, string) *webhook.Webhook); ok {
+		r1 = rf(ctx, url, eventTypes, tenantID, secret)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*webhook.Webhook)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(datastore.Context, string, []string, string, string) error); ok {
+		r2 = rf(ctx, url, eventTypes, tenantID, secret)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetWebhooks provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetWebhooks(ctx datastore.Context) ([]webhook.Webhook, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []webhook.Webhook
+	if rf, ok := ret.Get(0).(func(datastore.Context) []webhook.Webhook); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]webhook.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveWebhook provides a mock function with given fields: ctx, id
+func (_m *FacadeInterface) RemoveWebhook(ctx datastore.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSnapshotSchedule provides a mock function with given fields: ctx, serviceID, interval, cron, retention
+func (_m *FacadeInterface) SetSnapshotSchedule(ctx datastore.Context, serviceID string, interval time.Duration, cron string, retention snapshotschedule.Retention) (*snapshotschedule.SnapshotSchedule, error) {
+	ret := _m.Called(ctx, serviceID, interval, cron, retention)
+
+	var r0 *snapshotschedule.SnapshotSchedule
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, time.Duration, string, snapshotschedule.Retention) *snapshotschedule.SnapshotSchedule); ok {
+		r0 = rf(ctx, serviceID, interval, cron, retention)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*snapshotschedule.SnapshotSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, time.Duration, string, snapshotschedule.Retention) error); ok {
+		r1 = rf(ctx, serviceID, interval, cron, retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSnapshotSchedules provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetSnapshotSchedules(ctx datastore.Context) ([]snapshotschedule.SnapshotSchedule, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []snapshotschedule.SnapshotSchedule
+	if rf, ok := ret.Get(0).(func(datastore.Context) []snapshotschedule.SnapshotSchedule); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]snapshotschedule.SnapshotSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSnapshotSchedule provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) RemoveSnapshotSchedule(ctx datastore.Context, serviceID string) error {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CloneSnapshot provides a mock function with given fields: ctx, snapshotID, name
+func (_m *FacadeInterface) CloneSnapshot(ctx datastore.Context, snapshotID string, name string) (string, error) {
+	ret := _m.Called(ctx, snapshotID, name)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) string); ok {
+		r0 = rf(ctx, snapshotID, name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string) error); ok {
+		r1 = rf(ctx, snapshotID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiffSnapshots provides a mock function with given fields: ctx, snapshotID, toSnapshotID
+func (_m *FacadeInterface) DiffSnapshots(ctx datastore.Context, snapshotID string, toSnapshotID string) (*dao.SnapshotDiff, error) {
+	ret := _m.Called(ctx, snapshotID, toSnapshotID)
+
+	var r0 *dao.SnapshotDiff
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) *dao.SnapshotDiff); ok {
+		r0 = rf(ctx, snapshotID, toSnapshotID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.SnapshotDiff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string) error); ok {
+		r1 = rf(ctx, snapshotID, toSnapshotID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
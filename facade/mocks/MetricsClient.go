@@ -54,3 +54,26 @@ func (_m *MetricsClient) GetInstanceMemoryStats(_a0 time.Time, _a1 ...metrics.Se
 
 	return r0, r1
 }
+
+// GetServiceMetric provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *MetricsClient) GetServiceMetric(_a0 time.Duration, _a1 string, _a2 string, _a3 bool) (*metrics.ServiceMetricStats, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 *metrics.ServiceMetricStats
+	if rf, ok := ret.Get(0).(func(time.Duration, string, string, bool) *metrics.ServiceMetricStats); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*metrics.ServiceMetricStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Duration, string, string, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -7,6 +7,7 @@ import "github.com/control-center/serviced/domain/host"
 import "github.com/control-center/serviced/domain/pool"
 import "github.com/control-center/serviced/domain/registry"
 import "github.com/control-center/serviced/domain/service"
+import "github.com/control-center/serviced/zzk"
 import zkservice "github.com/control-center/serviced/zzk/service"
 
 type ZZK struct {
@@ -295,6 +296,135 @@ func (_m *ZZK) GetVirtualIPHostID(poolID string, ip string) (string, error) {
 
 	return r0, r1
 }
+func (_m *ZZK) ReassignVirtualIPs(_pool *pool.ResourcePool, hostID string) ([]string, error) {
+	ret := _m.Called(_pool, hostID)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(*pool.ResourcePool, string) []string); ok {
+		r0 = rf(_pool, hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*pool.ResourcePool, string) error); ok {
+		r1 = rf(_pool, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) GetVirtualIPStatuses(poolID string) ([]zkservice.VirtualIPStatus, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []zkservice.VirtualIPStatus
+	if rf, ok := ret.Get(0).(func(string) []zkservice.VirtualIPStatus); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]zkservice.VirtualIPStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) SetRollingRestartStatus(status service.RollingRestartStatus) error {
+	ret := _m.Called(status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(service.RollingRestartStatus) error); ok {
+		r0 = rf(status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ZZK) GetRollingRestartStatuses(serviceID string) ([]service.RollingRestartStatus, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.RollingRestartStatus
+	if rf, ok := ret.Get(0).(func(string) []service.RollingRestartStatus); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.RollingRestartStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) RemoveRollingRestartStatus(serviceID string) error {
+	ret := _m.Called(serviceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(serviceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ZZK) GetTree(pth string) (*zzk.TreeNode, error) {
+	ret := _m.Called(pth)
+
+	var r0 *zzk.TreeNode
+	if rf, ok := ret.Get(0).(func(string) *zzk.TreeNode); ok {
+		r0 = rf(pth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*zzk.TreeNode)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(pth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+func (_m *ZZK) CheckState(ctx datastore.Context, view zkservice.FacadeView, repair bool) ([]zkservice.Inconsistency, error) {
+	ret := _m.Called(ctx, view, repair)
+
+	var r0 []zkservice.Inconsistency
+	if rf, ok := ret.Get(0).(func(datastore.Context, zkservice.FacadeView, bool) []zkservice.Inconsistency); ok {
+		r0 = rf(ctx, view, repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]zkservice.Inconsistency)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, zkservice.FacadeView, bool) error); ok {
+		r1 = rf(ctx, view, repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ZZK) GetRegistryImage(id string) (*registry.Image, error) {
 	ret := _m.Called(id)
 
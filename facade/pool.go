@@ -20,6 +20,7 @@ import (
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/validation"
+	zkservice "github.com/control-center/serviced/zzk/service"
 	"github.com/zenoss/glog"
 
 	"errors"
@@ -553,6 +554,40 @@ func (f *Facade) GetPoolIPs(ctx datastore.Context, poolID string) (*pool.PoolIPs
 	return &pool.PoolIPs{PoolID: poolID, HostIPs: hostIPs, VirtualIPs: virtualIPs}, nil
 }
 
+// GetVirtualIPStatus returns the current owner and failover history of each
+// virtual IP in a resource pool
+func (f *Facade) GetVirtualIPStatus(ctx datastore.Context, poolID string) ([]zkservice.VirtualIPStatus, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetVirtualIPStatus"))
+	myPool, err := f.GetResourcePool(ctx, poolID)
+	if err != nil {
+		return nil, err
+	} else if myPool == nil {
+		return nil, ErrPoolNotExists
+	}
+
+	statuses, err := f.zzk.GetVirtualIPStatuses(poolID)
+	if err != nil {
+		glog.Errorf("Could not look up virtual ip status for pool %s: %s", poolID, err)
+		return nil, err
+	}
+	statusMap := make(map[string]zkservice.VirtualIPStatus)
+	for _, status := range statuses {
+		statusMap[status.IPAddress] = status
+	}
+
+	// only report on the virtual ips that are still in the pool
+	result := []zkservice.VirtualIPStatus{}
+	for _, vip := range myPool.VirtualIPs {
+		status, ok := statusMap[vip.IP]
+		if !ok {
+			status = zkservice.VirtualIPStatus{IPAddress: vip.IP, History: []zkservice.VirtualIPEvent{}}
+		}
+		status.PreferredHosts = vip.PreferredHosts
+		result = append(result, status)
+	}
+	return result, nil
+}
+
 var defaultRealm = "default"
 
 // GetReadPools returns a list of simplified resource pools
@@ -581,6 +616,7 @@ func (f *Facade) GetReadPools(ctx datastore.Context) ([]pool.ReadPool, error) {
 				MemoryCapacity:    pools[i].MemoryCapacity,
 				MemoryCommitment:  pools[i].MemoryCommitment,
 				ConnectionTimeout: pools[i].ConnectionTimeout,
+				StrictCPU:         pools[i].StrictCPU,
 				Permissions:       pools[i].Permissions,
 			})
 		}
@@ -22,7 +22,9 @@ import (
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
 	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
@@ -167,3 +169,47 @@ func (ft *FacadeUnitTest) Test_GetReadPoolsShouldReturnCorrectValues(c *C) {
 	c.Assert(p.UpdatedAt, TimeEqual, resourcePool.UpdatedAt)
 	c.Assert(p.Permissions, Equals, resourcePool.Permissions)
 }
+
+func (ft *FacadeUnitTest) Test_GetVirtualIPStatus(c *C) {
+	poolID := "somePoolID"
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, poolID).Return([]host.Host{}, nil)
+	ft.poolStore.On("Get", ft.ctx, pool.Key(poolID), mock.AnythingOfType("*pool.ResourcePool")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*pool.ResourcePool) = pool.ResourcePool{
+				ID: poolID,
+				VirtualIPs: []pool.VirtualIP{
+					{PoolID: poolID, IP: "10.0.0.1", PreferredHosts: []string{"host2", "host1"}},
+					{PoolID: poolID, IP: "10.0.0.2"},
+				},
+			}
+		})
+	since := time.Now()
+	ft.zzk.On("GetVirtualIPStatuses", poolID).Return([]zkservice.VirtualIPStatus{
+		{IPAddress: "10.0.0.1", HostID: "host1", Since: since, History: []zkservice.VirtualIPEvent{
+			{Time: since, HostID: "host1", Event: zkservice.IPAssigned},
+		}},
+		{IPAddress: "10.0.0.9", HostID: "host2", Since: since},
+	}, nil)
+
+	result, err := ft.Facade.GetVirtualIPStatus(ft.ctx, poolID)
+	c.Assert(err, IsNil)
+	c.Assert(result, HasLen, 2)
+	c.Assert(result[0].IPAddress, Equals, "10.0.0.1")
+	c.Assert(result[0].HostID, Equals, "host1")
+	c.Assert(result[0].PreferredHosts, DeepEquals, []string{"host2", "host1"})
+	c.Assert(result[0].History, HasLen, 1)
+	c.Assert(result[1].IPAddress, Equals, "10.0.0.2")
+	c.Assert(result[1].HostID, Equals, "")
+	c.Assert(result[1].History, HasLen, 0)
+}
+
+func (ft *FacadeUnitTest) Test_GetVirtualIPStatusNoPool(c *C) {
+	poolID := "somePoolID"
+	ft.poolStore.On("Get", ft.ctx, pool.Key(poolID), mock.AnythingOfType("*pool.ResourcePool")).
+		Return(datastore.ErrNoSuchEntity{Key: pool.Key(poolID)})
+
+	result, err := ft.Facade.GetVirtualIPStatus(ft.ctx, poolID)
+	c.Assert(err, Equals, facade.ErrPoolNotExists)
+	c.Assert(result, IsNil)
+}
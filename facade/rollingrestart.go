@@ -0,0 +1,134 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// defaultRolloutWindow is how long each batch of an image rollout must stay
+// up and healthy if no window is requested
+const defaultRolloutWindow = 30 * time.Second
+
+var (
+	// ErrRollingRestartHalted is returned when a batch of a rolling restart
+	// does not become healthy in time
+	ErrRollingRestartHalted = errors.New("facade: rolling restart halted because a batch did not become healthy")
+	// ErrNoImageToRevert is returned when a halted rolling restart has no
+	// previous image to put back on the service
+	ErrNoImageToRevert = errors.New("facade: no previous image to revert to")
+	// ErrImageUnchanged is returned when a service is set to the image it
+	// already uses
+	ErrImageUnchanged = errors.New("facade: service already uses this image")
+)
+
+// GetRollingRestartStatus returns the progress of the most recent rolling
+// restart of a service, or of every service if serviceID is empty.
+func (f *Facade) GetRollingRestartStatus(ctx datastore.Context, serviceID string) ([]service.RollingRestartStatus, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetRollingRestartStatus"))
+	if serviceID != "" {
+		if _, err := f.serviceStore.Get(ctx, serviceID); err != nil {
+			return nil, err
+		}
+	}
+	return f.zzk.GetRollingRestartStatuses(serviceID)
+}
+
+// updateRollingRestartStatus records the progress of a rolling restart.  The
+// status is only informational, so failing to record it does not stop the
+// restart.
+func (f *Facade) updateRollingRestartStatus(status service.RollingRestartStatus) {
+	status.Updated = time.Now()
+	if err := f.zzk.SetRollingRestartStatus(status); err != nil {
+		plog.WithError(err).WithFields(log.Fields{
+			"serviceid": status.ServiceID,
+			"state":     status.State,
+		}).Warn("Could not record rolling restart status")
+	}
+}
+
+// SetServiceImage changes the image of a service.  If rollout is set and the
+// service is running, its instances are restarted in batches onto the new
+// image; a batch that does not become healthy, or crashes or fails a health
+// check within the rollout window, halts the rollout and the previous image
+// is put back if rollout.Revert is set.  Returns true if a rollout was
+// started.
+func (f *Facade) SetServiceImage(ctx datastore.Context, serviceID, imageID string, rollout *service.RollingRestart) (bool, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetServiceImage"))
+	alog := f.auditLogger.Action(audit.Update).Message(ctx, "Set Service Image").ID(serviceID).Type(service.GetType()).WithField("imageid", imageID)
+
+	if _, err := commons.ParseImageID(imageID); err != nil {
+		return false, alog.Error(err)
+	}
+	if rollout != nil {
+		if err := rollout.ValidEntity(); err != nil {
+			return false, alog.Error(err)
+		}
+	}
+
+	svc, previousImageID, err := f.setServiceImage(ctx, serviceID, imageID)
+	if err != nil {
+		return false, alog.Error(err)
+	}
+	if rollout == nil || svc.DesiredState != int(service.SVCRun) {
+		return false, alog.Error(nil)
+	}
+
+	opts := *rollout
+	opts.Halt = true
+	opts.PreviousImageID = previousImageID
+	if opts.Window == 0 {
+		opts.Window = defaultRolloutWindow
+	}
+	if _, err := f.scheduleServicesWithRestart(ctx, []string{svc.ID}, false, false, service.SVCRestart, false, &opts); err != nil {
+		return false, alog.Error(err)
+	}
+	return true, alog.Error(nil)
+}
+
+// setServiceImage updates the image of a service and returns the updated
+// service and the image it used before
+func (f *Facade) setServiceImage(ctx datastore.Context, serviceID, imageID string) (*service.Service, string, error) {
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return nil, "", err
+	}
+	mutex := getTenantLock(tenantID)
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		return nil, "", err
+	}
+	if svc.ImageID == imageID {
+		return nil, "", ErrImageUnchanged
+	}
+	if err = f.fillServiceConfigs(ctx, svc); err != nil {
+		return nil, "", err
+	}
+	previousImageID := svc.ImageID
+	svc.ImageID = imageID
+	if err = f.updateService(ctx, tenantID, *svc, false, false); err != nil {
+		return nil, "", err
+	}
+	return svc, previousImageID, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_GetRollingRestartStatus(c *C) {
+	status := service.RollingRestartStatus{ServiceID: "service1", State: service.RollingRestartCompleted}
+	ft.serviceStore.On("Get", ft.ctx, "service1").Return(&service.Service{ID: "service1"}, nil)
+	ft.zzk.On("GetRollingRestartStatuses", "service1").Return([]service.RollingRestartStatus{status}, nil)
+	ft.zzk.On("GetRollingRestartStatuses", "").Return([]service.RollingRestartStatus{}, nil)
+
+	statuses, err := ft.Facade.GetRollingRestartStatus(ft.ctx, "service1")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, DeepEquals, []service.RollingRestartStatus{status})
+
+	statuses, err = ft.Facade.GetRollingRestartStatus(ft.ctx, "")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 0)
+}
+
+func (ft *FacadeUnitTest) Test_GetRollingRestartStatusNoService(c *C) {
+	expectedErr := errors.New("service not found")
+	ft.serviceStore.On("Get", ft.ctx, "service1").Return(nil, expectedErr)
+
+	statuses, err := ft.Facade.GetRollingRestartStatus(ft.ctx, "service1")
+	c.Assert(err, Equals, expectedErr)
+	c.Assert(statuses, IsNil)
+}
+
+func (ft *FacadeUnitTest) Test_RestartServiceBadRollingRestart(c *C) {
+	request := dao.ScheduleServiceRequest{
+		ServiceIDs:     []string{"service1"},
+		RollingRestart: &service.RollingRestart{BatchSize: 2, BatchPercent: 50},
+	}
+
+	count, err := ft.Facade.RestartService(ft.ctx, request)
+	c.Assert(err, Equals, service.ErrBatchConflict)
+	c.Assert(count, Equals, 0)
+}
+
+func (ft *FacadeUnitTest) Test_SetServiceImageBadImage(c *C) {
+	started, err := ft.Facade.SetServiceImage(ft.ctx, "service1", "zenoss/core!", nil)
+	c.Assert(err, NotNil)
+	c.Assert(started, Equals, false)
+}
+
+func (ft *FacadeUnitTest) Test_SetServiceImageBadRollout(c *C) {
+	rollout := &service.RollingRestart{Window: -time.Second}
+
+	started, err := ft.Facade.SetServiceImage(ft.ctx, "service1", "zenoss/core:2", rollout)
+	c.Assert(err, Equals, service.ErrBadRollingRestartDuration)
+	c.Assert(started, Equals, false)
+}
+
+func (ft *FacadeUnitTest) Test_SetServiceImageUnchanged(c *C) {
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "service1").Return(&service.ServiceDetails{ID: "service1"}, nil)
+	ft.serviceStore.On("Get", ft.ctx, "service1").Return(&service.Service{ID: "service1", ImageID: "zenoss/core:2"}, nil)
+
+	started, err := ft.Facade.SetServiceImage(ft.ctx, "service1", "zenoss/core:2", nil)
+	c.Assert(err, Equals, facade.ErrImageUnchanged)
+	c.Assert(started, Equals, false)
+}
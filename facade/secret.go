@@ -0,0 +1,153 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/secret"
+)
+
+// ErrNotATenant is returned when a secret is addressed to a service that is
+// not a tenant
+var ErrNotATenant = errors.New("facade: service is not a tenant")
+
+// SetSecret encrypts and stores the value of a tenant secret, replacing any
+// existing value with the same name.
+func (f *Facade) SetSecret(ctx datastore.Context, tenantID, name, value string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetSecret"))
+	// The value is never written to the audit log
+	alog := f.auditLogger.Message(ctx, "Set Secret").Action(audit.Update).
+		ID(name).Type(secret.GetType()).WithField("tenantid", tenantID)
+	if err := f.verifyTenant(ctx, tenantID); err != nil {
+		return alog.Error(err)
+	}
+	encrypted, err := auth.EncryptSecret(value)
+	if err != nil {
+		plog.WithError(err).WithFields(log.Fields{
+			"tenantid": tenantID,
+			"name":     name,
+		}).Error("Could not encrypt secret")
+		return alog.Error(err)
+	}
+	s := &secret.Secret{
+		TenantID:  tenantID,
+		Name:      name,
+		Value:     encrypted,
+		UpdatedAt: time.Now(),
+	}
+	if existing, err := f.secretStore.Get(ctx, tenantID, name); err == nil {
+		s.DatabaseVersion = existing.DatabaseVersion
+	} else if !datastore.IsErrNoSuchEntity(err) {
+		return alog.Error(err)
+	}
+	if err := s.ValidEntity(); err != nil {
+		return alog.Error(err)
+	}
+	if err := f.secretStore.Put(ctx, s); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// GetSecrets returns the secrets of a tenant with their values redacted
+func (f *Facade) GetSecrets(ctx datastore.Context, tenantID string) ([]secret.Secret, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSecrets"))
+	if err := f.verifyTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	secrets, err := f.secretStore.GetSecrets(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]secret.Secret, len(secrets))
+	for i, s := range secrets {
+		result[i] = s.Redact()
+	}
+	return result, nil
+}
+
+// RemoveSecret deletes a tenant secret
+func (f *Facade) RemoveSecret(ctx datastore.Context, tenantID, name string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveSecret"))
+	alog := f.auditLogger.Message(ctx, "Remove Secret").Action(audit.Remove).
+		ID(name).Type(secret.GetType()).WithField("tenantid", tenantID)
+	if err := f.verifyTenant(ctx, tenantID); err != nil {
+		return alog.Error(err)
+	}
+	if err := f.secretStore.Delete(ctx, tenantID, name); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// getSecretValue returns the decrypted value of a tenant secret
+func (f *Facade) getSecretValue(ctx datastore.Context, tenantID, name string) (string, error) {
+	s, err := f.secretStore.Get(ctx, tenantID, name)
+	if err != nil {
+		return "", err
+	}
+	return auth.DecryptSecret(s.Value)
+}
+
+// copySecrets copies the secrets of a tenant to another tenant.  The values
+// are copied as they are, still encrypted.
+func (f *Facade) copySecrets(ctx datastore.Context, fromTenantID, tenantID string) error {
+	secrets, err := f.secretStore.GetSecrets(ctx, fromTenantID)
+	if err != nil {
+		return err
+	}
+	for _, s := range secrets {
+		copied := *s
+		copied.TenantID = tenantID
+		copied.DatabaseVersion = 0
+		if err := f.secretStore.Put(ctx, &copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeSecrets deletes all of the secrets of a tenant
+func (f *Facade) removeSecrets(ctx datastore.Context, tenantID string) error {
+	secrets, err := f.secretStore.GetSecrets(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	for _, s := range secrets {
+		if err := f.secretStore.Delete(ctx, tenantID, s.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyTenant returns an error if the service is not a tenant
+func (f *Facade) verifyTenant(ctx datastore.Context, tenantID string) error {
+	id, err := f.GetTenantID(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if id != tenantID {
+		return ErrNotATenant
+	}
+	return nil
+}
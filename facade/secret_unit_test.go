@@ -0,0 +1,112 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/secret"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_SetSecretEncryptsValue(c *C) {
+	tenantID := getRandomServiceID(c)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, tenantID).Return(&service.ServiceDetails{ID: tenantID}, nil)
+	ft.secretStore.On("Get", ft.ctx, tenantID, "db_password").Return(nil, datastore.ErrNoSuchEntity{})
+	ft.secretStore.On("Put", ft.ctx, mock.AnythingOfType("*secret.Secret")).Return(nil)
+
+	err := ft.Facade.SetSecret(ft.ctx, tenantID, "db_password", "s3cr3t")
+	c.Assert(err, IsNil)
+
+	stored := ft.secretStore.Calls[1].Arguments.Get(1).(*secret.Secret)
+	c.Assert(stored.TenantID, Equals, tenantID)
+	c.Assert(stored.Name, Equals, "db_password")
+	c.Assert(stored.Value, Not(Equals), "s3cr3t")
+	value, err := auth.DecryptSecret(stored.Value)
+	c.Assert(err, IsNil)
+	c.Assert(value, Equals, "s3cr3t")
+}
+
+func (ft *FacadeUnitTest) Test_SetSecretNotATenant(c *C) {
+	tenantID := getRandomServiceID(c)
+	serviceID := getRandomServiceID(c)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, tenantID).Return(&service.ServiceDetails{ID: tenantID}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID, ParentServiceID: tenantID}, nil)
+
+	err := ft.Facade.SetSecret(ft.ctx, serviceID, "db_password", "s3cr3t")
+	c.Assert(err, Equals, facade.ErrNotATenant)
+	ft.secretStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_RemoveSecretNotATenant(c *C) {
+	tenantID := getRandomServiceID(c)
+	serviceID := getRandomServiceID(c)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, tenantID).Return(&service.ServiceDetails{ID: tenantID}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID, ParentServiceID: tenantID}, nil)
+
+	err := ft.Facade.RemoveSecret(ft.ctx, serviceID, "db_password")
+	c.Assert(err, Equals, facade.ErrNotATenant)
+	ft.secretStore.AssertNotCalled(c, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_GetSecretsRedactsValues(c *C) {
+	tenantID := getRandomServiceID(c)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, tenantID).Return(&service.ServiceDetails{ID: tenantID}, nil)
+	ft.secretStore.On("GetSecrets", ft.ctx, tenantID).Return([]*secret.Secret{
+		{TenantID: tenantID, Name: "db_password", Value: "encrypted"},
+	}, nil)
+
+	secrets, err := ft.Facade.GetSecrets(ft.ctx, tenantID)
+	c.Assert(err, IsNil)
+	c.Assert(secrets, HasLen, 1)
+	c.Assert(secrets[0].Name, Equals, "db_password")
+	c.Assert(secrets[0].Value, Equals, secret.Redacted)
+}
+
+func (ft *FacadeUnitTest) Test_GetEvaluatedServiceResolvesSecrets(c *C) {
+	tenantID := getRandomServiceID(c)
+	svc := service.Service{
+		ID:          tenantID,
+		Name:        "tenant",
+		Environment: []string{`DB_PASSWORD={{secret "db_password"}}`},
+		OriginalConfigs: map[string]servicedefinition.ConfigFile{
+			"/etc/db.conf": {Filename: "/etc/db.conf", Content: `password={{secret "db_password"}}`},
+		},
+	}
+	encrypted, err := auth.EncryptSecret("s3cr3t")
+	c.Assert(err, IsNil)
+	ft.serviceStore.On("Get", ft.ctx, tenantID).Return(&svc, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, tenantID).Return(&service.ServiceDetails{ID: tenantID}, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, tenantID, "/"+tenantID).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+	ft.secretStore.On("Get", ft.ctx, tenantID, "db_password").Return(&secret.Secret{TenantID: tenantID, Name: "db_password", Value: encrypted}, nil)
+
+	result, err := ft.Facade.GetEvaluatedService(ft.ctx, tenantID, 0)
+	c.Assert(err, IsNil)
+	c.Assert(result.Environment[0], Equals, "DB_PASSWORD=s3cr3t")
+
+	// the checksum of the rendered file must not reveal the secret
+	checksum := result.ConfigChecksums["/etc/db.conf"]
+	c.Assert(checksum, Not(Equals), "")
+	c.Assert(checksum, Not(Equals), fmt.Sprintf("%x", sha256.Sum256([]byte("password=s3cr3t"))))
+}
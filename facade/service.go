@@ -30,6 +30,7 @@ import (
 	"github.com/zenoss/glog"
 
 	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/commons"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
@@ -39,9 +40,11 @@ import (
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/events"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/scheduler/servicestatemanager"
+	"github.com/control-center/serviced/trace"
 	"github.com/control-center/serviced/utils"
 	zkservice "github.com/control-center/serviced/zzk/service"
 )
@@ -272,6 +275,10 @@ func (f * Facade) getChanges(ctx datastore.Context, svc service.Service) string
 		typeOfS := sUpdated.Type()
 		sCur := reflect.ValueOf(cursvc).Elem()
 		for i := 0; i < sUpdated.NumField(); i++ {
+			// skip unexported fields
+			if typeOfS.Field(i).PkgPath != "" {
+				continue
+			}
 			fUpdated := sUpdated.Field(i)
 			fCur:= sCur.Field(i)
 			fValue := fUpdated.Interface()
@@ -300,6 +307,9 @@ func (f *Facade) UpdateService(ctx datastore.Context, svc service.Service) error
 	if err != nil {
 		return alog.Error(err)
 	}
+	if err := f.validateChangedConfigTemplates(ctx, svc); err != nil {
+		return alog.Error(err)
+	}
 	mutex := getTenantLock(tenantID)
 	mutex.RLock()
 	defer mutex.RUnlock()
@@ -989,6 +999,9 @@ func (f *Facade) RemoveService(ctx datastore.Context, id string) error {
 		return alog.Error(err)
 	}
 	if tenantID == id {
+		if err := f.removeSecrets(ctx, tenantID); err != nil {
+			return alog.Error(err)
+		}
 		if err = f.dfs.Destroy(tenantID); err != nil {
 			return alog.Error(err)
 		}
@@ -1033,6 +1046,9 @@ func (f *Facade) removeService(ctx datastore.Context, id string) error {
 			logger.WithError(err).Error("Could not remove service from zookeeper")
 			return err
 		}
+		if err := f.zzk.RemoveRollingRestartStatus(svc.ID); err != nil {
+			logger.WithError(err).Warn("Could not remove rolling restart status for service")
+		}
 
 		if err := store.Delete(ctx, svc.ID); err != nil {
 			logger.WithError(err).Error("Error while removing service %s")
@@ -1131,7 +1147,23 @@ func (f *Facade) GetEvaluatedService(ctx datastore.Context, serviceID string, in
 // evaluateService translates the service template fields
 func (f *Facade) evaluateService(ctx datastore.Context, svc *service.Service, instanceID int) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.evaluatedService"))
+	getService, getServiceChild := f.serviceLookups(ctx)
+	if err := svc.EvaluateWithSecrets(getService, getServiceChild, f.secretLookup(ctx, svc.ID), instanceID); err != nil {
+		return err
+	}
+	// the agent records the checksums of the files it writes, so that the
+	// master can detect drift without storing any secrets
+	key, err := auth.ChecksumKey()
+	if err != nil {
+		return err
+	}
+	svc.ConfigChecksums = service.ConfigFileChecksums(svc.ConfigFiles, key)
+	return nil
+}
 
+// serviceLookups returns the functions used to look up other services while
+// evaluating service templates
+func (f *Facade) serviceLookups(ctx datastore.Context) (service.GetService, service.FindChildService) {
 	// service lookup
 	getService := func(serviceID string) (service.Service, error) {
 		svc := service.Service{}
@@ -1151,7 +1183,23 @@ func (f *Facade) evaluateService(ctx datastore.Context, svc *service.Service, in
 		}
 		return svc, err
 	}
-	return svc.Evaluate(getService, getServiceChild, instanceID)
+	return getService, getServiceChild
+}
+
+// secretLookup returns the function used to decrypt the tenant secrets
+// referenced by a service; the tenant is only resolved if a secret is
+// referenced
+func (f *Facade) secretLookup(ctx datastore.Context, serviceID string) service.GetSecret {
+	var tenantID string
+	return func(name string) (string, error) {
+		if tenantID == "" {
+			var err error
+			if tenantID, err = f.GetTenantID(ctx, serviceID); err != nil {
+				return "", err
+			}
+		}
+		return f.getSecretValue(ctx, tenantID, name)
+	}
 }
 
 // GetServices looks up all services. Allows filtering by tenant ID, name (regular expression), and/or update time.
@@ -1485,6 +1533,13 @@ func (f *Facade) FindChildService(ctx datastore.Context, parentServiceID string,
 
 // ScheduleService changes a services' desired state and returns the number of affected services
 func (f *Facade) ScheduleServices(ctx datastore.Context, serviceIDs []string, autoLaunch bool, synchronous bool, desiredState service.DesiredState, emergency bool) (int, error) {
+	return f.scheduleServicesWithRestart(ctx, serviceIDs, autoLaunch, synchronous, desiredState, emergency, nil)
+}
+
+// scheduleServicesWithRestart schedules the services like ScheduleServices,
+// passing the options of a rolling restart on to the scheduler with each
+// service.
+func (f *Facade) scheduleServicesWithRestart(ctx datastore.Context, serviceIDs []string, autoLaunch bool, synchronous bool, desiredState service.DesiredState, emergency bool, restart *service.RollingRestart) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.ScheduleService"))
 	if len(serviceIDs) < 1 {
 		return 0, nil
@@ -1503,7 +1558,7 @@ func (f *Facade) ScheduleServices(ctx datastore.Context, serviceIDs []string, au
 	for tenantID, services := range tenantServices {
 		mutex := getTenantLock(tenantID)
 		mutex.RLock()
-		tcount, err := f.scheduleServiceParents(ctx, tenantID, services, autoLaunch, synchronous, desiredState, false, emergency)
+		tcount, err := f.scheduleServiceParents(ctx, tenantID, services, autoLaunch, synchronous, desiredState, false, emergency, restart)
 		mutex.RUnlock()
 		if err != nil {
 			return 0, err
@@ -1573,7 +1628,7 @@ func (f *Facade) countServices(ctx datastore.Context, serviceIDs []string, check
 	return count, nil
 }
 
-func (f *Facade) scheduleServiceParents(ctx datastore.Context, tenantID string, serviceIDs []string, autoLaunch bool, synchronous bool, desiredState service.DesiredState, locked bool, emergency bool) (int, error) {
+func (f *Facade) scheduleServiceParents(ctx datastore.Context, tenantID string, serviceIDs []string, autoLaunch bool, synchronous bool, desiredState service.DesiredState, locked bool, emergency bool, restart *service.RollingRestart) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.scheduleServiceParents"))
 	logger := plog.WithFields(log.Fields{
 		"tenantid":     tenantID,
@@ -1641,6 +1696,9 @@ func (f *Facade) scheduleServiceParents(ctx datastore.Context, tenantID string,
 				}).Warn("Service failed validation for stop")
 				return err
 			}
+			// pass the request's trace on to the scheduler
+			svc.Trace = datastore.TraceOf(ctx).String()
+			svc.RollingRestart = restart
 			svcs = append(svcs, svc)
 			svcIDs = append(svcIDs, svc.ID)
 		}
@@ -1773,6 +1831,15 @@ func (f *Facade) ScheduleServiceBatch(ctx datastore.Context, svcs []*servicestat
 
 			f.auditLogger.WithFields(log.Fields{"action": desiredState.ToAuditAction(), "servicename": svc.Name}).
 				Message(ctx, "Service Scheduled").Entity(svc).Succeeded()
+			f.PublishEvent(events.Event{
+				Type:      events.DesiredStateChanged,
+				TenantID:  tenantID,
+				ServiceID: svc.ID,
+				Detail: map[string]string{
+					"name":         svc.Name,
+					"desiredState": desiredState.ToAuditAction(),
+				},
+			})
 			lock.Lock()
 			servicesToSchedule = append(servicesToSchedule, svc.Service)
 			lock.Unlock()
@@ -1824,18 +1891,45 @@ func (f *Facade) updateDesiredState(ctx datastore.Context, svc *servicestatemana
 	return nil
 }
 
-// rollingRestart restarts a service one instance at a time and waits for it to reach pass health checks.
+// rollingRestart restarts a service in batches of instances, waiting for each
+// batch to pass its health checks before moving on to the next.  The batch
+// size, pause and failure handling come from the options the service was
+// scheduled with; by default instances restart one at a time.
 func (f *Facade) rollingRestart(ctx datastore.Context, svc *service.Service, timeout time.Duration, cancel <-chan interface{}) error {
 	logger := plog.WithFields(log.Fields{
 		"serviceid":   svc.ID,
 		"servicename": svc.Name,
 	})
 
+	opts := service.RollingRestart{}
+	if svc.RollingRestart != nil {
+		opts = *svc.RollingRestart
+	}
+	if opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+	batchSize := opts.GetBatchSize(svc.Instances)
+	status := service.RollingRestartStatus{
+		ServiceID:       svc.ID,
+		ServiceName:     svc.Name,
+		State:           service.RollingRestartRunning,
+		Instances:       svc.Instances,
+		BatchSize:       batchSize,
+		Batches:         (svc.Instances + batchSize - 1) / batchSize,
+		ImageID:         svc.ImageID,
+		PreviousImageID: opts.PreviousImageID,
+		Started:         time.Now(),
+	}
+	f.updateRollingRestartStatus(status)
+
 	// Run through and set all instances to "Pending Restart"
 	for instanceID := 0; instanceID < svc.Instances; instanceID++ {
 		err := f.zzk.UpdateInstanceCurrentState(ctx, svc.PoolID, svc.ID, instanceID, service.StatePendingRestart)
 		if err != nil {
 			logger.WithError(err).Debug("Failed to update instance current state to pending restart")
+			status.State = service.RollingRestartHalted
+			status.Message = err.Error()
+			f.updateRollingRestartStatus(status)
 			return err
 		}
 	}
@@ -1857,86 +1951,141 @@ func (f *Facade) rollingRestart(ctx datastore.Context, svc *service.Service, tim
 	var punctualLock sync.RWMutex
 	punctualInstances := 0
 
-	for instanceID := 0; instanceID < svc.Instances; instanceID++ {
-		ilogger := logger.WithField("instance", instanceID)
-		ilogger.Debug("Restarting instance")
+	for first := 0; first < svc.Instances; first += batchSize {
+		last := first + batchSize
+		if last > svc.Instances {
+			last = svc.Instances
+		}
+		blogger := logger.WithFields(log.Fields{
+			"firstinstance": first,
+			"lastinstance":  last - 1,
+		})
+		blogger.Debug("Restarting batch")
+
+		// Pause between batches
+		if first > 0 && opts.Pause > 0 {
+			select {
+			case <-cancel:
+				status.State = service.RollingRestartCancelled
+				f.updateRollingRestartStatus(status)
+				return nil
+			case <-time.After(opts.Pause):
+			}
+		}
 
 		select {
 		case <-cancel:
+			status.State = service.RollingRestartCancelled
+			f.updateRollingRestartStatus(status)
 			return nil
 		default:
 		}
 
+		status.Batch++
+		f.updateRollingRestartStatus(status)
+
 		// Set up the timeout
 		cancelWait := make(chan struct{})
 		done := make(chan struct{})
+		timedOut := false
 		timer := time.NewTimer(timeout)
-		go func() {
+		go func(count int) {
 			select {
 			case <-timer.C:
-				ilogger.Warn("Timeout waiting for instance to restart")
+				blogger.Warn("Timeout waiting for instances to restart")
+				punctualLock.Lock()
+				timedOut = true
+				punctualLock.Unlock()
 			case <-done:
 				punctualLock.Lock()
-				punctualInstances++
+				punctualInstances += count
 				punctualLock.Unlock()
 			case <-cancel:
-				ilogger.Debug("Rolling restart cancelled")
+				blogger.Debug("Rolling restart cancelled")
 			}
 			timer.Stop()
 			close(cancelWait)
-		}()
+		}(last - first)
 
-		// Before we restart, check the current instance's container ID
-		state, err := f.zzk.GetServiceState(ctx, svc.PoolID, svc.ID, instanceID)
-		if err != nil {
+		fail := func(err error) error {
 			close(done)
-			logger.WithError(err).Debug("Failed to get service's current container ID")
+			status.State = service.RollingRestartHalted
+			status.Message = err.Error()
+			f.updateRollingRestartStatus(status)
 			return err
 		}
 
-		oldContainer := state.ContainerID
-
-		if err = f.zzk.RestartInstance(ctx, svc.PoolID, svc.ID, instanceID); err != nil {
-			close(done)
-			logger.WithError(err).Debug("Failed to restart instance")
-			return err
-		}
+		// Restart each instance in the batch, keeping track of its old container
+		oldContainers := make(map[int]string)
+		for instanceID := first; instanceID < last; instanceID++ {
+			ilogger := blogger.WithField("instance", instanceID)
+			ilogger.Debug("Restarting instance")
 
-		// Increment nextInstance now, since we have started the restart process for this instance
-		nextInstance++
+			// Before we restart, check the current instance's container ID
+			state, err := f.zzk.GetServiceState(ctx, svc.PoolID, svc.ID, instanceID)
+			if err != nil {
+				ilogger.WithError(err).Debug("Failed to get service's current container ID")
+				return fail(err)
+			}
+			oldContainers[instanceID] = state.ContainerID
 
-		// Wait for the instance's containerID to change
-		checkContainer := func(s *zkservice.State, exists bool) bool {
-			if !exists {
-				return true
+			if err = f.zzk.RestartInstance(ctx, svc.PoolID, svc.ID, instanceID); err != nil {
+				ilogger.WithError(err).Debug("Failed to restart instance")
+				return fail(err)
 			}
-			if s.ContainerID != "" && s.ContainerID != oldContainer {
-				return service.InstanceCurrentState(s.Status) == service.StateRunning
+
+			// Increment nextInstance now, since we have started the restart process for this instance
+			nextInstance++
+		}
+
+		// Wait for each instance's containerID to change
+		waitErrs := make(chan error, last-first)
+		for instanceID := first; instanceID < last; instanceID++ {
+			go func(instanceID int) {
+				oldContainer := oldContainers[instanceID]
+				checkContainer := func(s *zkservice.State, exists bool) bool {
+					if !exists {
+						return true
+					}
+					if s.ContainerID != "" && s.ContainerID != oldContainer {
+						return service.InstanceCurrentState(s.Status) == service.StateRunning
+					}
+					return false
+				}
+				waitErrs <- f.zzk.WaitInstance(ctx, svc, instanceID, checkContainer, cancelWait)
+			}(instanceID)
+		}
+		var waitErr error
+		for i := first; i < last; i++ {
+			if err := <-waitErrs; err != nil && waitErr == nil {
+				waitErr = err
 			}
-			return false
 		}
-		if err = f.zzk.WaitInstance(ctx, svc, instanceID, checkContainer, cancelWait); err != nil {
-			close(done)
-			logger.WithError(err).Debug("Failed to wait on instance")
-			return err
+		if waitErr != nil {
+			blogger.WithError(waitErr).Debug("Failed to wait on instance")
+			return fail(waitErr)
 		}
 
-		// Check if we're ready to move on to the next instance on an interval
+		// Check if we're ready to move on to the next batch on an interval
 		ready := func() bool {
-			// I don't care if it is the last instance
-			if instanceID == svc.Instances-1 {
+			// I don't care if it is the last batch, unless its health
+			// decides whether the restart failed
+			if last == svc.Instances && !opts.Halt {
 				return true
 			}
 
 			// Wait for health checks to pass
-			statuses := f.getInstanceHealth(svch, instanceID)
-			for key, status := range statuses {
-				ilogger.WithFields(log.Fields{
-					"status": status,
-					"key":    key,
-				}).Debug("Got health status for instance")
-				if status != health.OK {
-					return false
+			for instanceID := first; instanceID < last; instanceID++ {
+				statuses := f.getInstanceHealth(svch, instanceID)
+				for key, status := range statuses {
+					blogger.WithFields(log.Fields{
+						"instance": instanceID,
+						"status":   status,
+						"key":      key,
+					}).Debug("Got health status for instance")
+					if status != health.OK {
+						return false
+					}
 				}
 			}
 
@@ -1954,7 +2103,7 @@ func (f *Facade) rollingRestart(ctx datastore.Context, svc *service.Service, tim
 		}
 
 		for !cancelled && !ready() {
-			ilogger.Debug("Instance not ready yet, checking again in 500 ms")
+			blogger.Debug("Batch not ready yet, checking again in 500 ms")
 			select {
 			case <-cancelWait:
 				cancelled = true
@@ -1964,9 +2113,39 @@ func (f *Facade) rollingRestart(ctx datastore.Context, svc *service.Service, tim
 			}
 		}
 		hctimer.Stop()
-		ilogger.Debug("Done restarting instance")
+		blogger.Debug("Done restarting batch")
 		close(done)
 		<-cancelWait
+
+		status.Restarted = last
+		f.updateRollingRestartStatus(status)
+
+		punctualLock.RLock()
+		failed := timedOut
+		punctualLock.RUnlock()
+		reason := ""
+		if failed {
+			reason = fmt.Sprintf("instances %d to %d did not become healthy within %s", first, last-1, timeout)
+		} else if opts.Window > 0 {
+			if reason = f.watchBatch(ctx, svc, svch, first, last, opts.Window, cancel); reason != "" {
+				blogger.WithField("reason", reason).Warn("Batch did not stay up and healthy")
+			}
+		}
+		if reason != "" && opts.Halt {
+			blogger.Warn("Batch did not become healthy in time, halting rolling restart")
+			status.State = service.RollingRestartHalted
+			status.Message = reason
+			if opts.Revert {
+				if err := f.revertRollingRestart(ctx, svc, opts.PreviousImageID, last); err != nil {
+					blogger.WithError(err).Error("Could not revert the image of the service")
+					status.Message = fmt.Sprintf("%s; could not revert image: %s", status.Message, err)
+				} else {
+					status.State = service.RollingRestartReverted
+				}
+			}
+			f.updateRollingRestartStatus(status)
+			return ErrRollingRestartHalted
+		}
 	}
 
 	punctualLock.RLock()
@@ -1976,6 +2155,95 @@ func (f *Facade) rollingRestart(ctx datastore.Context, svc *service.Service, tim
 		f.SetServicesCurrentState(ctx, service.SVCCSRunning, svc.ID)
 	}
 
+	status.State = service.RollingRestartCompleted
+	f.updateRollingRestartStatus(status)
+	return nil
+}
+
+// watchBatch watches a batch of restarted instances for the length of the
+// window.  It returns why the batch failed if an instance crashes or fails a
+// health check, or an empty string if the batch stayed up.
+func (f *Facade) watchBatch(ctx datastore.Context, svc *service.Service, svch *service.ServiceHealth, first, last int, window time.Duration, cancel <-chan interface{}) string {
+	containers := make(map[int]string)
+	for instanceID := first; instanceID < last; instanceID++ {
+		state, err := f.zzk.GetServiceState(ctx, svc.PoolID, svc.ID, instanceID)
+		if err != nil {
+			return fmt.Sprintf("instance %d is not running: %s", instanceID, err)
+		}
+		containers[instanceID] = state.ContainerID
+	}
+
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timer.C:
+			return ""
+		case <-cancel:
+			return ""
+		case <-ticker.C:
+		}
+
+		for instanceID := first; instanceID < last; instanceID++ {
+			state, err := f.zzk.GetServiceState(ctx, svc.PoolID, svc.ID, instanceID)
+			if err != nil || state.ContainerID != containers[instanceID] || service.InstanceCurrentState(state.Status) != service.StateRunning {
+				return fmt.Sprintf("instance %d crashed within %s", instanceID, window)
+			}
+			for name, status := range f.getInstanceHealth(svch, instanceID) {
+				if status == health.Failed || status == health.Timeout {
+					return fmt.Sprintf("instance %d failed health check %s within %s", instanceID, name, window)
+				}
+			}
+		}
+	}
+}
+
+// revertServiceImage puts the previous image back on a service
+func (f *Facade) revertServiceImage(ctx datastore.Context, serviceID, previousImageID string) error {
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	mutex := getTenantLock(tenantID)
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	current, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	if err = f.fillServiceConfigs(ctx, current); err != nil {
+		return err
+	}
+	current.ImageID = previousImageID
+	return f.updateService(ctx, tenantID, *current, false, false)
+}
+
+// revertRollingRestart puts the previous image back on a service after a
+// halted rolling restart and restarts the instances that already picked up
+// the new image.
+func (f *Facade) revertRollingRestart(ctx datastore.Context, svc *service.Service, previousImageID string, restarted int) error {
+	if previousImageID == "" || previousImageID == svc.ImageID {
+		return ErrNoImageToRevert
+	}
+	logger := plog.WithFields(log.Fields{
+		"serviceid":       svc.ID,
+		"imageid":         svc.ImageID,
+		"previousimageid": previousImageID,
+	})
+
+	if err := f.revertServiceImage(ctx, svc.ID, previousImageID); err != nil {
+		return err
+	}
+	logger.Info("Reverted the image of the service")
+
+	for instanceID := 0; instanceID < restarted; instanceID++ {
+		if err := f.zzk.RestartInstance(ctx, svc.PoolID, svc.ID, instanceID); err != nil {
+			logger.WithField("instance", instanceID).WithError(err).Warn("Could not restart instance with the previous image")
+		}
+	}
 	return nil
 }
 
@@ -2087,16 +2355,40 @@ func (f *Facade) WaitSingleService(svc *service.Service, dstate service.DesiredS
 
 }
 
+// followSchedule continues the trace of a request that schedules services, so
+// that the scheduler and the agents carry it on.  It returns the context to
+// schedule the services with, and the span of the request, which is nil if
+// the request is not traced.
+func followSchedule(ctx datastore.Context, name string, serviceIDs []string) (datastore.Context, *trace.Span) {
+	span := trace.Follow(name, datastore.TraceOf(ctx))
+	if span == nil {
+		return ctx, nil
+	}
+	span.SetAttribute("serviceids", strings.Join(serviceIDs, ","))
+	return datastore.WithTrace(ctx, span.Context), span
+}
+
 func (f *Facade) StartService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.StartService"))
+	ctx, span := followSchedule(ctx, "Facade.StartService", request.ServiceIDs)
 	successCount, err := f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, request.Synchronous, service.SVCRun, false)
+	span.Finish(err)
 	alog := f.auditLogger.Action(audit.Start).Message(ctx, "Starting Service(s)").Type(service.GetType()).WithFields(log.Fields{"ids": strings.Join(request.ServiceIDs, ", "), "count": successCount})
 	return successCount, alog.Error(err)
 }
 
 func (f *Facade) RestartService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RestartService"))
-	successCount, err := f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, request.Synchronous, service.SVCRestart, false)
+	ctx, span := followSchedule(ctx, "Facade.RestartService", request.ServiceIDs)
+	if request.RollingRestart != nil {
+		if err := request.RollingRestart.ValidEntity(); err != nil {
+			span.Finish(err)
+			alog := f.auditLogger.Action(audit.Restart).Message(ctx, "Restarting Service(s)").Type(service.GetType()).WithFields(log.Fields{"ids": strings.Join(request.ServiceIDs, ", ")})
+			return 0, alog.Error(err)
+		}
+	}
+	successCount, err := f.scheduleServicesWithRestart(ctx, request.ServiceIDs, request.AutoLaunch, request.Synchronous, service.SVCRestart, false, request.RollingRestart)
+	span.Finish(err)
 	alog := f.auditLogger.Action(audit.Restart).Message(ctx, "Restarting Service(s)").Type(service.GetType()).WithFields(log.Fields{"ids": strings.Join(request.ServiceIDs, ", "), "count": successCount})
 	return successCount, alog.Error(err)
 
@@ -2105,9 +2397,11 @@ func (f *Facade) RestartService(ctx datastore.Context, request dao.ScheduleServi
 // RebalanceService does a hard restart:  All services are stopped, and then all services are started again
 func (f *Facade) RebalanceService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RebalanceService"))
+	ctx, span := followSchedule(ctx, "Facade.RebalanceService", request.ServiceIDs)
 
-	forceRestart := func() (int, error) {
-		count, err := f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, true, service.SVCStop, false)
+	forceRestart := func() (count int, err error) {
+		defer func() { span.Finish(err) }()
+		count, err = f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, true, service.SVCStop, false)
 		if err != nil {
 			return count, err
 		}
@@ -2134,12 +2428,17 @@ func (f *Facade) RebalanceService(ctx datastore.Context, request dao.ScheduleSer
 
 func (f *Facade) PauseService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.PauseService"))
-	return f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, request.Synchronous, service.SVCPause, false)
+	ctx, span := followSchedule(ctx, "Facade.PauseService", request.ServiceIDs)
+	successCount, err := f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, request.Synchronous, service.SVCPause, false)
+	span.Finish(err)
+	return successCount, err
 }
 
 func (f *Facade) StopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.StopService"))
+	ctx, span := followSchedule(ctx, "Facade.StopService", request.ServiceIDs)
 	successCount, err := f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, request.Synchronous, service.SVCStop, false)
+	span.Finish(err)
 	alog := f.auditLogger.Action(audit.Stop).Message(ctx, "Stopping Service(s)").Type(service.GetType()).WithFields(log.Fields{"ids": strings.Join(request.ServiceIDs, ", "), "count": successCount})
 	return successCount, alog.Error(err)
 }
@@ -2148,7 +2447,9 @@ func (f *Facade) EmergencyStopService(ctx datastore.Context, request dao.Schedul
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.EmergencyStopService"))
 	alog := f.auditLogger.Message(ctx, "Emergency Stopping Services").Action(audit.Stop).
 		WithField("serviceids", strings.Trim(fmt.Sprintf("%v", request.ServiceIDs), "[]"))
+	ctx, span := followSchedule(ctx, "Facade.EmergencyStopService", request.ServiceIDs)
 	numServices, err := f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, request.Synchronous, service.SVCStop, true)
+	span.Finish(err)
 	return numServices, alog.Error(err)
 }
 
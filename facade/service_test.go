@@ -28,6 +28,7 @@ import (
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicedefinition"
@@ -451,6 +452,32 @@ func (ft *FacadeIntegrationTest) TestFacade_validateServiceUpdate_InvalidService
 	t.Assert(err, NotNil) // This should have returned an ErrInvalidServiceOption error.
 }
 
+func (ft *FacadeIntegrationTest) TestFacade_SetServiceImage_stopped(t *C) {
+	svc := service.Service{
+		ID:           "svc1",
+		Name:         "TestFacade_SetServiceImage",
+		DeploymentID: "deployment_id",
+		PoolID:       "pool_id",
+		ImageID:      "zenoss/core:1",
+		Launch:       "auto",
+		DesiredState: int(service.SVCStop),
+	}
+	err := ft.Facade.AddService(ft.CTX, svc)
+	t.Assert(err, IsNil)
+
+	// a stopped service takes the image without restarting
+	started, err := ft.Facade.SetServiceImage(ft.CTX, svc.ID, "zenoss/core:2", &service.RollingRestart{Revert: true})
+	t.Assert(err, IsNil)
+	t.Assert(started, Equals, false)
+
+	result, err := ft.Facade.GetService(ft.CTX, svc.ID)
+	t.Assert(err, IsNil)
+	t.Assert(result.ImageID, Equals, "zenoss/core:2")
+
+	_, err = ft.Facade.SetServiceImage(ft.CTX, svc.ID, "zenoss/core:2", nil)
+	t.Assert(err, Equals, ErrImageUnchanged)
+}
+
 func (ft *FacadeIntegrationTest) TestFacade_migrateServiceConfigs_noConfigs(t *C) {
 	_, newSvc, err := ft.setupMigrationServices(t, nil)
 	t.Assert(err, IsNil)
@@ -1555,11 +1582,11 @@ func (ft *FacadeIntegrationTest) TestFacade_StoppingParentStopsChildren(c *C) {
 	}
 
 	// start the service
-	if _, err = ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{[]string{"ParentServiceID"}, true, true}); err != nil {
+	if _, err = ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{ServiceIDs: []string{"ParentServiceID"}, AutoLaunch: true, Synchronous: true}); err != nil {
 		c.Fatalf("Unable to stop parent service: %+v, %s", svc, err)
 	}
 	// stop the parent
-	if _, err = ft.Facade.StopService(ft.CTX, dao.ScheduleServiceRequest{[]string{"ParentServiceID"}, true, true}); err != nil {
+	if _, err = ft.Facade.StopService(ft.CTX, dao.ScheduleServiceRequest{ServiceIDs: []string{"ParentServiceID"}, AutoLaunch: true, Synchronous: true}); err != nil {
 		c.Fatalf("Unable to stop parent service: %+v, %s", svc, err)
 	}
 	// verify the children have all stopped
@@ -1712,7 +1739,7 @@ func (ft *FacadeIntegrationTest) TestFacade_EmergencyStopService_Synchronous(c *
 	}
 
 	// start the service
-	if _, err = ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{[]string{"ParentServiceID"}, true, true}); err != nil {
+	if _, err = ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{ServiceIDs: []string{"ParentServiceID"}, AutoLaunch: true, Synchronous: true}); err != nil {
 		c.Fatalf("Unable to stop parent service: %+v, %s", svc, err)
 	}
 
@@ -1955,7 +1982,7 @@ func (ft *FacadeIntegrationTest) TestFacade_EmergencyStopService_Asynchronous(c
 	}
 
 	// start the service
-	if _, err = ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{[]string{"ParentServiceID"}, true, true}); err != nil {
+	if _, err = ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{ServiceIDs: []string{"ParentServiceID"}, AutoLaunch: true, Synchronous: true}); err != nil {
 		c.Fatalf("Unable to stop parent service: %+v, %s", svc, err)
 	}
 
@@ -2271,7 +2298,7 @@ func (ft *FacadeIntegrationTest) TestFacade_StartAndStopService_Synchronous(c *C
 	}()
 
 	// start the parent synchronously
-	if _, err = ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{[]string{"ParentServiceID"}, true, true}); err != nil {
+	if _, err = ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{ServiceIDs: []string{"ParentServiceID"}, AutoLaunch: true, Synchronous: true}); err != nil {
 		c.Fatalf("Unable to start parent service: %+v, %s", svc, err)
 	}
 
@@ -2378,7 +2405,7 @@ func (ft *FacadeIntegrationTest) TestFacade_StartAndStopService_Synchronous(c *C
 	}()
 
 	// stop the parent synchronously
-	if _, err = ft.Facade.StopService(ft.CTX, dao.ScheduleServiceRequest{[]string{"ParentServiceID"}, true, true}); err != nil {
+	if _, err = ft.Facade.StopService(ft.CTX, dao.ScheduleServiceRequest{ServiceIDs: []string{"ParentServiceID"}, AutoLaunch: true, Synchronous: true}); err != nil {
 		c.Fatalf("Unable to start parent service: %+v, %s", svc, err)
 	}
 
@@ -2475,14 +2502,14 @@ func (ft *FacadeIntegrationTest) TestFacade_RebalanceService_Asynchronous(c *C)
 	}
 
 	// start the services and consume the value off the channels
-	count, err := ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{[]string{"ParentServiceID"}, true, false})
+	count, err := ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{ServiceIDs: []string{"ParentServiceID"}, AutoLaunch: true, Synchronous: false})
 	c.Assert(err, IsNil)
 	c.Assert(count, Equals, 2)
 	<-scheduledChannels["ParentServiceID"]
 	<-scheduledChannels["childService1"]
 
 	// rebalance the parent asynchronously
-	count, err = ft.Facade.RebalanceService(ft.CTX, dao.ScheduleServiceRequest{[]string{"ParentServiceID"}, true, false})
+	count, err = ft.Facade.RebalanceService(ft.CTX, dao.ScheduleServiceRequest{ServiceIDs: []string{"ParentServiceID"}, AutoLaunch: true, Synchronous: false})
 	c.Assert(err, IsNil)
 	c.Assert(count, Equals, 2)
 
@@ -2598,7 +2625,7 @@ func (ft *FacadeIntegrationTest) TestFacade_ModifyServiceWhilePending(c *C) {
 	}
 
 	// Start the services asynchronously.  After starting level 1, it will block until we close releaseWait
-	if _, err = ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{[]string{"ParentServiceID"}, true, false}); err != nil {
+	if _, err = ft.Facade.StartService(ft.CTX, dao.ScheduleServiceRequest{ServiceIDs: []string{"ParentServiceID"}, AutoLaunch: true, Synchronous: false}); err != nil {
 		c.Fatalf("Unable to start parent service: %+v, %s", svc, err)
 	}
 
@@ -3785,3 +3812,21 @@ func (ft *FacadeIntegrationTest) TestFacade_getChanges(c *C) {
 	expected := "Name:TestFacade_getChanges_Updated;"
 	c.Assert(expected, Equals, updates)
 }
+
+func (ft *FacadeIntegrationTest) TestFacade_RemoveTenantRemovesSecrets(c *C) {
+	tenant := service.Service{
+		ID:           "secrettenant",
+		Name:         "TestFacade_RemoveTenantRemovesSecrets",
+		DeploymentID: "deployment_id",
+		PoolID:       "pool_id",
+		Launch:       "auto",
+		DesiredState: int(service.SVCStop),
+	}
+	c.Assert(ft.Facade.AddService(ft.CTX, tenant), IsNil)
+	err := ft.Facade.secretStore.Put(ft.CTX, &secret.Secret{TenantID: tenant.ID, Name: "db_password", Value: "encrypted"})
+	c.Assert(err, IsNil)
+
+	c.Assert(ft.Facade.RemoveService(ft.CTX, tenant.ID), IsNil)
+	_, err = ft.Facade.secretStore.Get(ft.CTX, tenant.ID, "db_password")
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
@@ -15,11 +15,15 @@ package facade
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"path"
 	"reflect"
+	"sort"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
@@ -111,6 +115,12 @@ func (f *Facade) AddServiceConfig(ctx datastore.Context, serviceID string, conf
 		return alog.Error(errors.New("config file exists"))
 	}
 
+	// make sure the file can be rendered
+	if err := f.validateConfigTemplate(ctx, serviceID, conf); err != nil {
+		logger.WithError(err).Debug("Could not evaluate service config file")
+		return alog.Error(err)
+	}
+
 	// initialize the database record for the file
 	file, err = serviceconfigfile.New(tenantID, servicePath, conf)
 	if err != nil {
@@ -151,6 +161,12 @@ func (f *Facade) UpdateServiceConfig(ctx datastore.Context, fileID string, conf
 
 	alog = alog.WithField("servicepath", file.ServicePath)
 
+	// make sure the file can be rendered
+	if err := f.validateConfigTemplate(ctx, path.Base(file.ServicePath), conf); err != nil {
+		logger.WithError(err).Debug("Could not evaluate service config file")
+		return alog.Error(err)
+	}
+
 	// update the database record for the file
 	file.ConfFile = conf
 
@@ -183,6 +199,172 @@ func (f *Facade) DeleteServiceConfig(ctx datastore.Context, fileID string) error
 	return nil
 }
 
+// RenderServiceConfigs returns the config files of a service instance as they
+// would be rendered if the instance started now.  Secrets are redacted.
+func (f *Facade) RenderServiceConfigs(ctx datastore.Context, serviceID string, instanceID int) ([]servicedefinition.ConfigFile, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RenderServiceConfigs"))
+	logger := plog.WithFields(log.Fields{
+		"serviceid":  serviceID,
+		"instanceid": instanceID,
+	})
+
+	svc, err := f.GetService(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not load service")
+		return nil, err
+	}
+
+	getService, getServiceChild := f.serviceLookups(ctx)
+	if err := svc.EvaluateConfigFilesTemplate(getService, getServiceChild, instanceID); err != nil {
+		logger.WithError(err).Debug("Could not evaluate config files")
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(svc.ConfigFiles))
+	for key := range svc.ConfigFiles {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	confs := make([]servicedefinition.ConfigFile, len(keys))
+	for i, key := range keys {
+		confs[i] = svc.ConfigFiles[key]
+	}
+	return confs, nil
+}
+
+// GetServiceConfigDrift compares the config files that the running instances
+// of a service were started with against the files that would be rendered for
+// them now, and returns the files that differ.
+func (f *Facade) GetServiceConfigDrift(ctx datastore.Context, serviceID string) ([]service.ConfigDrift, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServiceConfigDrift"))
+	logger := plog.WithField("serviceid", serviceID)
+
+	svc, err := f.GetService(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not load service")
+		return nil, err
+	}
+
+	states, err := f.zzk.GetServiceStates(ctx, svc.PoolID, svc.ID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up running instances")
+		return nil, err
+	}
+
+	getService, getServiceChild := f.serviceLookups(ctx)
+	getSecret := f.secretLookup(ctx, svc.ID)
+	key, err := auth.ChecksumKey()
+	if err != nil {
+		logger.WithError(err).Debug("Could not load the checksum key")
+		return nil, err
+	}
+
+	drift := []service.ConfigDrift{}
+	for _, state := range states {
+		if state.ContainerID == "" {
+			// the instance has not started
+			continue
+		}
+		newDrift := func(filename, status string) service.ConfigDrift {
+			return service.ConfigDrift{
+				ServiceID:  svc.ID,
+				InstanceID: state.InstanceID,
+				HostID:     state.HostID,
+				Filename:   filename,
+				Status:     status,
+			}
+		}
+
+		// instances started by an older version do not report their files
+		if state.ConfigChecksums == nil {
+			drift = append(drift, newDrift("", service.ConfigDriftUnknown))
+			continue
+		}
+
+		rendered := *svc
+		rendered.ConfigFiles = make(map[string]servicedefinition.ConfigFile)
+		for key, conf := range svc.ConfigFiles {
+			rendered.ConfigFiles[key] = conf
+		}
+		if err := rendered.RenderConfigFiles(getService, getServiceChild, getSecret, state.InstanceID); err != nil {
+			logger.WithField("instanceid", state.InstanceID).WithError(err).Debug("Could not evaluate config files")
+			return nil, err
+		}
+		checksums := service.ConfigFileChecksums(rendered.ConfigFiles, key)
+
+		var filenames []string
+		for filename := range checksums {
+			filenames = append(filenames, filename)
+		}
+		for filename := range state.ConfigChecksums {
+			if _, ok := checksums[filename]; !ok {
+				filenames = append(filenames, filename)
+			}
+		}
+		sort.Strings(filenames)
+
+		for _, filename := range filenames {
+			running, isRunning := state.ConfigChecksums[filename]
+			current, isCurrent := checksums[filename]
+			if !isRunning {
+				drift = append(drift, newDrift(filename, service.ConfigDriftAdded))
+			} else if !isCurrent {
+				drift = append(drift, newDrift(filename, service.ConfigDriftRemoved))
+			} else if running != current {
+				drift = append(drift, newDrift(filename, service.ConfigDriftChanged))
+			}
+		}
+	}
+
+	logger.WithField("count", len(drift)).Debug("Compared config files of running instances")
+	return drift, nil
+}
+
+// validateConfigTemplate verifies that a config file of a service can be
+// evaluated.  Secrets are not resolved.
+func (f *Facade) validateConfigTemplate(ctx datastore.Context, serviceID string, conf servicedefinition.ConfigFile) error {
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	return f.validateConfigTemplates(ctx, *svc, map[string]servicedefinition.ConfigFile{conf.Filename: conf})
+}
+
+// validateConfigTemplates verifies that each of the config files can be
+// evaluated for the service.  Secrets are not resolved.
+func (f *Facade) validateConfigTemplates(ctx datastore.Context, svc service.Service, configFiles map[string]servicedefinition.ConfigFile) error {
+	getService, getServiceChild := f.serviceLookups(ctx)
+	for key, conf := range configFiles {
+		svc.ConfigFiles = map[string]servicedefinition.ConfigFile{key: conf}
+		if err := svc.EvaluateConfigFilesTemplate(getService, getServiceChild, 0); err != nil {
+			return fmt.Errorf("could not evaluate config file %s: %s", conf.Filename, err)
+		}
+	}
+	return nil
+}
+
+// validateChangedConfigTemplates verifies that the config files of a service
+// that are being added or changed can be evaluated
+func (f *Facade) validateChangedConfigTemplates(ctx datastore.Context, svc service.Service) error {
+	if len(svc.ConfigFiles) == 0 {
+		return nil
+	}
+	cursvc, err := f.serviceStore.Get(ctx, svc.ID)
+	if err != nil {
+		return err
+	}
+	if err := f.fillServiceConfigs(ctx, cursvc); err != nil {
+		return err
+	}
+	changed := make(map[string]servicedefinition.ConfigFile)
+	for key, conf := range svc.ConfigFiles {
+		if curconf, ok := cursvc.ConfigFiles[key]; !ok || curconf.Content != conf.Content || curconf.Filename != conf.Filename {
+			changed[key] = conf
+		}
+	}
+	return f.validateConfigTemplates(ctx, svc, changed)
+}
+
 // getServicePath returns the tenantID and the full path of the service
 // TODO: update function to include deploymentID in the service path
 func (f *Facade) getServicePath(ctx datastore.Context, serviceID string) (tenantID string, servicePath string, err error) {
@@ -0,0 +1,108 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/domain/secret"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupConfigService(c *C, configFiles ...servicedefinition.ConfigFile) *service.Service {
+	serviceID := getRandomServiceID(c)
+	svc := &service.Service{
+		ID:              serviceID,
+		Name:            "app",
+		PoolID:          "default",
+		OriginalConfigs: make(map[string]servicedefinition.ConfigFile),
+	}
+	for _, conf := range configFiles {
+		svc.OriginalConfigs[conf.Filename] = conf
+	}
+	ft.serviceStore.On("Get", ft.ctx, serviceID).Return(svc, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID, Name: "app"}, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, serviceID, "/"+serviceID).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+	return svc
+}
+
+func (ft *FacadeUnitTest) Test_RenderServiceConfigsRedactsSecrets(c *C) {
+	svc := ft.setupConfigService(c,
+		servicedefinition.ConfigFile{Filename: "/etc/b.conf", Content: `password={{secret "db_password"}}`},
+		servicedefinition.ConfigFile{Filename: "/etc/a.conf", Content: "instance={{.InstanceID}}"},
+	)
+
+	confs, err := ft.Facade.RenderServiceConfigs(ft.ctx, svc.ID, 2)
+	c.Assert(err, IsNil)
+	c.Assert(confs, HasLen, 2)
+	c.Assert(confs[0].Filename, Equals, "/etc/a.conf")
+	c.Assert(confs[0].Content, Equals, "instance=2")
+	c.Assert(confs[1].Filename, Equals, "/etc/b.conf")
+	c.Assert(confs[1].Content, Equals, "password="+secret.Redacted)
+}
+
+func (ft *FacadeUnitTest) Test_GetServiceConfigDrift(c *C) {
+	svc := ft.setupConfigService(c,
+		servicedefinition.ConfigFile{Filename: "/etc/same.conf", Content: "same"},
+		servicedefinition.ConfigFile{Filename: "/etc/changed.conf", Content: "new"},
+		servicedefinition.ConfigFile{Filename: "/etc/added.conf", Content: "added"},
+	)
+	key, err := auth.ChecksumKey()
+	c.Assert(err, IsNil)
+	running := service.ConfigFileChecksums(map[string]servicedefinition.ConfigFile{
+		"/etc/same.conf":    {Filename: "/etc/same.conf", Content: "same"},
+		"/etc/changed.conf": {Filename: "/etc/changed.conf", Content: "old"},
+		"/etc/removed.conf": {Filename: "/etc/removed.conf", Content: "removed"},
+	}, key)
+
+	states := []zkservice.State{
+		{HostID: "host1", ServiceID: svc.ID, InstanceID: 0},
+		{HostID: "host2", ServiceID: svc.ID, InstanceID: 1},
+		{HostID: "host3", ServiceID: svc.ID, InstanceID: 2},
+	}
+	states[1].ContainerID = "container1"
+	states[1].ConfigChecksums = running
+	states[2].ContainerID = "container2"
+	ft.zzk.On("GetServiceStates", ft.ctx, "default", svc.ID).Return(states, nil)
+
+	drift, err := ft.Facade.GetServiceConfigDrift(ft.ctx, svc.ID)
+	c.Assert(err, IsNil)
+	c.Assert(drift, DeepEquals, []service.ConfigDrift{
+		{ServiceID: svc.ID, InstanceID: 1, HostID: "host2", Filename: "/etc/added.conf", Status: service.ConfigDriftAdded},
+		{ServiceID: svc.ID, InstanceID: 1, HostID: "host2", Filename: "/etc/changed.conf", Status: service.ConfigDriftChanged},
+		{ServiceID: svc.ID, InstanceID: 1, HostID: "host2", Filename: "/etc/removed.conf", Status: service.ConfigDriftRemoved},
+		{ServiceID: svc.ID, InstanceID: 2, HostID: "host3", Status: service.ConfigDriftUnknown},
+	})
+}
+
+func (ft *FacadeUnitTest) Test_UpdateServiceConfigRejectsBadTemplate(c *C) {
+	svc := ft.setupConfigService(c)
+	fileID := "fileid"
+	ft.configStore.On("Get", ft.ctx, serviceconfigfile.Key(fileID), mock.AnythingOfType("*serviceconfigfile.SvcConfigFile")).Return(nil).Run(func(args mock.Arguments) {
+		file := args.Get(2).(*serviceconfigfile.SvcConfigFile)
+		file.ID = fileID
+		file.ServicePath = "/" + svc.ID
+		file.ConfFile = servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "ok"}
+	})
+
+	err := ft.Facade.UpdateServiceConfig(ft.ctx, fileID, servicedefinition.ConfigFile{Filename: "/etc/app.conf", Content: "{{.Nope"})
+	c.Assert(err, ErrorMatches, "could not evaluate config file /etc/app.conf: .*")
+	ft.configStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// GetServiceDependencyGraph returns the graph of endpoint dependencies between
+// the services of the tenant that the service belongs to, annotated with the
+// current state of each service.
+func (f *Facade) GetServiceDependencyGraph(ctx datastore.Context, serviceID string) (*service.DependencyGraph, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServiceDependencyGraph"))
+	logger := plog.WithField("serviceid", serviceID)
+
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up tenant")
+		return nil, err
+	}
+	logger = logger.WithField("tenantid", tenantID)
+
+	svcs, err := f.GetServices(ctx, dao.ServiceRequest{TenantID: tenantID})
+	if err != nil {
+		logger.WithError(err).Debug("Could not load services for tenant")
+		return nil, err
+	}
+	graph := service.BuildDependencyGraph(tenantID, svcs)
+
+	serviceIDs := make([]string, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		serviceIDs[i] = node.ServiceID
+	}
+	states, err := f.GetAggregateServices(ctx, time.Now(), serviceIDs)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up the state of services")
+		return nil, err
+	}
+	for i, state := range states {
+		if state.NotFound {
+			// the service was removed while the graph was built
+			graph.Nodes[i].CurrentState = service.SVCCSUnknown
+			continue
+		}
+		graph.Nodes[i].DesiredState = state.DesiredState
+		graph.Nodes[i].CurrentState = state.CurrentState
+		graph.Nodes[i].RunningInstances = len(state.Status)
+		graph.Nodes[i].EmergencyShutdown = state.EmergencyShutdown
+	}
+
+	logger.WithFields(log.Fields{
+		"services":   len(graph.Nodes),
+		"edges":      len(graph.Edges),
+		"unresolved": len(graph.Unresolved),
+		"cycles":     len(graph.Cycles),
+	}).Debug("Built service dependency graph")
+	return graph, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_GetServiceDependencyGraph(c *C) {
+	tenantID := getRandomServiceID(c)
+	childID := getRandomServiceID(c)
+	otherID := getRandomServiceID(c)
+
+	tenant := service.Service{ID: tenantID, Name: "tenant", PoolID: "default", Instances: 1}
+	tenant.Endpoints = []service.ServiceEndpoint{{}}
+	tenant.Endpoints[0].Application = "db"
+	tenant.Endpoints[0].Purpose = "import"
+	child := service.Service{ID: childID, Name: "db", PoolID: "default", ParentServiceID: tenantID, Instances: 1}
+	child.Endpoints = []service.ServiceEndpoint{{}}
+	child.Endpoints[0].Application = "db"
+	child.Endpoints[0].Purpose = "export"
+	other := service.Service{ID: otherID, Name: "other", PoolID: "default"}
+
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, tenantID).Return(&service.ServiceDetails{ID: tenantID}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, childID).Return(&service.ServiceDetails{ID: childID, ParentServiceID: tenantID}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, otherID).Return(&service.ServiceDetails{ID: otherID}, nil)
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{tenant, child, other}, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, tenantID, mock.AnythingOfType("string")).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+
+	ft.serviceStore.On("GetServiceHealth", ft.ctx, childID).Return(&service.ServiceHealth{
+		ID: childID, Name: "db", PoolID: "default", DesiredState: int(service.SVCRun), CurrentState: string(service.SVCCSRunning),
+	}, nil)
+	ft.serviceStore.On("GetServiceHealth", ft.ctx, tenantID).Return(&service.ServiceHealth{
+		ID: tenantID, Name: "tenant", PoolID: "default", DesiredState: int(service.SVCStop), CurrentState: string(service.SVCCSStopped),
+	}, nil)
+	ft.zzk.On("GetServiceStateIDs", "default", childID).Return([]zkservice.StateRequest{{ServiceID: childID}}, nil)
+	ft.zzk.On("GetServiceStateIDs", "default", tenantID).Return([]zkservice.StateRequest{}, nil)
+	ft.metricsClient.On("GetInstanceMemoryStats", mock.Anything, mock.Anything).Return(nil, nil)
+
+	graph, err := ft.Facade.GetServiceDependencyGraph(ft.ctx, childID)
+	c.Assert(err, IsNil)
+	c.Assert(graph.TenantID, Equals, tenantID)
+	c.Assert(graph.Nodes, HasLen, 2)
+	c.Assert(graph.Nodes[0].ServiceID, Equals, childID)
+	c.Assert(graph.Nodes[0].CurrentState, Equals, service.SVCCSRunning)
+	c.Assert(graph.Nodes[0].RunningInstances, Equals, 1)
+	c.Assert(graph.Nodes[1].ServiceID, Equals, tenantID)
+	c.Assert(graph.Nodes[1].DesiredState, Equals, service.SVCStop)
+	c.Assert(graph.Nodes[1].RunningInstances, Equals, 0)
+	c.Assert(graph.Edges, DeepEquals, []service.DependencyEdge{
+		{ImporterID: tenantID, ExporterID: childID, Application: "db", OutOfOrder: false},
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/events"
 )
 
 //AddServiceTemplate  adds a service template to the system. Returns the id of the template added
@@ -290,9 +291,22 @@ func (f *Facade) DeployTemplateStatus(deploymentID string, lastStatus string, ti
 	}
 }
 
-//DeployTemplate creates and deploys a service to the pool and returns the tenant id of the newly deployed service
-func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string) ([]string, error) {
+//DeployTemplate creates and deploys a service to the pool and returns the tenant id of the newly deployed service.
+// The supplied parameters are validated against the template's declared parameters and substituted into its
+// service definitions before they are deployed.
+func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string, parameters map[string]string) (tenantIDs []string, err error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.DeployTemplate"))
+	detail := map[string]string{
+		"poolId":       poolID,
+		"templateId":   templateID,
+		"deploymentId": deploymentID,
+	}
+	f.PublishEvent(events.Event{Type: events.DeploymentStarted, Detail: detail})
+	// the tenants are only known once they are deployed
+	deployed := []string{}
+	defer func() {
+		f.PublishEvent(forTenants(finishedEvent(events.DeploymentFinished, detail, err), deployed))
+	}()
 	alog := f.auditLogger.Message(ctx, "Deploying Service Template").
 		Action(audit.Deploy).ID(templateID).Type(servicetemplate.GetType()).
 		WithFields(logrus.Fields{"poolid": poolID, "deploymentid": deploymentID})
@@ -315,8 +329,18 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 		return nil, alog.Error(err)
 	}
 
-	//check that deployment id does not already exist
 	logger = logger.WithField("template", template.Name)
+	values, err := template.ResolveParameters(parameters)
+	if err != nil {
+		logger.WithError(err).Error("Invalid template parameters")
+		return nil, alog.Error(err)
+	}
+	if err := template.ApplyParameters(values); err != nil {
+		logger.WithError(err).Error("Unable to apply template parameters")
+		return nil, alog.Error(err)
+	}
+
+	//check that deployment id does not already exist
 	if svcs, err := f.serviceStore.GetServicesByDeployment(ctx, deploymentID); err != nil {
 		logger.WithError(err).Error("Unable to validate deploymentID while deploying")
 		return nil, alog.Error(err)
@@ -341,7 +365,7 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 		deployment.UpdateStatus(status)
 	}
 
-	tenantIDs := make([]string, len(template.Services))
+	tenantIDs = make([]string, len(template.Services))
 	for i, sd := range template.Services {
 		logger.WithField("servicename", sd.Name).Info("Deploying service")
 		tenantID, err := f.deployService(ctx, "", "", deploymentID, poolID, false, sd, statusUpdater)
@@ -349,6 +373,7 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 			logger.WithError(err).Error("Could not deploy application")
 			return nil, alog.Error(err)
 		}
+		deployed = append(deployed, tenantID)
 		if err := f.dfs.Create(tenantID); err != nil {
 			logger.WithError(err).WithField("tenantid", tenantID).Error("Could not initialize volume for tenant")
 			return nil, alog.Error(err)
@@ -0,0 +1,249 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/utils"
+)
+
+var (
+	// ErrCloneNameRequired is returned when a snapshot is cloned without a
+	// name for the new application
+	ErrCloneNameRequired = errors.New("facade: a name is required for the cloned application")
+
+	// ErrCloneNoTenant is returned when the services of a snapshot do not
+	// include its tenant
+	ErrCloneNoTenant = errors.New("facade: snapshot does not contain the tenant service")
+
+	vhostSuffixRe = regexp.MustCompile("[^a-z0-9]+")
+)
+
+// CloneSnapshot creates a new application with the given name from a
+// snapshot, and returns the id of its tenant.  The services of the snapshot
+// are copied with new ids, the volume and images of the new tenant are
+// created from the data of the snapshot, and the secrets of the original
+// tenant are copied to the new one.  Ip assignments are kept where the
+// address is still free and are otherwise auto-assigned, the virtual hosts of
+// the copy are renamed and its public ports are disabled, so that it does not
+// collide with the application the snapshot was taken from.
+func (f *Facade) CloneSnapshot(ctx datastore.Context, snapshotID, name string) (string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.CloneSnapshot"))
+	alog := f.auditLogger.Message(ctx, "Clone Snapshot").Action(audit.Add).
+		Type(service.GetType()).WithFields(logrus.Fields{
+		"snapshotid":  snapshotID,
+		"servicename": name,
+	})
+	logger := plog.WithFields(logrus.Fields{
+		"snapshotid": snapshotID,
+		"name":       name,
+	})
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", alog.Error(ErrCloneNameRequired)
+	}
+
+	locker := f.DFSLock(ctx)
+	locker.Lock("clone snapshot")
+	defer locker.Unlock()
+
+	info, err := f.dfs.Info(snapshotID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get info for snapshot")
+		return "", alog.Error(err)
+	}
+	tenantID, err := utils.NewUUID36()
+	if err != nil {
+		return "", alog.Error(err)
+	}
+	alog = alog.ID(tenantID)
+	logger = logger.WithFields(logrus.Fields{
+		"fromtenantid": info.TenantID,
+		"tenantid":     tenantID,
+	})
+	svcs, err := cloneTenantServices(info.Services, info.TenantID, tenantID, name)
+	if err != nil {
+		logger.WithError(err).Debug("Could not copy the services of the snapshot")
+		return "", alog.Error(err)
+	}
+	// check the name before copying any data
+	for i := range svcs {
+		if svcs[i].ID == tenantID {
+			if err := f.validateServiceName(ctx, &svcs[i]); err != nil {
+				logger.WithError(err).Debug("Could not use name for the cloned application")
+				return "", alog.Error(err)
+			}
+		}
+	}
+
+	if err := f.dfs.Clone(snapshotID, tenantID); err != nil {
+		logger.WithError(err).Debug("Could not clone application data from snapshot")
+		f.discardClone(ctx, tenantID)
+		return "", alog.Error(err)
+	}
+	logger.Info("Cloned application data from snapshot")
+
+	mutex := getTenantLock(tenantID)
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	if err := f.addClonedServices(ctx, tenantID, svcs); err != nil {
+		logger.WithError(err).Debug("Could not add the services of the cloned application")
+		f.discardClone(ctx, tenantID)
+		return "", alog.Error(err)
+	}
+	if err := f.copySecrets(ctx, info.TenantID, tenantID); err != nil {
+		logger.WithError(err).Debug("Could not copy the secrets of the cloned application")
+		f.discardClone(ctx, tenantID)
+		return "", alog.Error(err)
+	}
+	logger.Info("Cloned application from snapshot")
+	alog.Succeeded()
+	return tenantID, nil
+}
+
+// addClonedServices adds the services of a cloned application, parents
+// first, and assigns them addresses that do not collide with the application
+// they were copied from.
+func (f *Facade) addClonedServices(ctx datastore.Context, tenantID string, svcs []service.Service) error {
+	svcsmap := make(map[string][]service.Service)
+	for _, svc := range svcs {
+		svcsmap[svc.ParentServiceID] = append(svcsmap[svc.ParentServiceID], svc)
+	}
+	var traverse func(parentID string) error
+	traverse = func(parentID string) error {
+		for _, svc := range svcsmap[parentID] {
+			logger := plog.WithFields(logrus.Fields{
+				"servicename": svc.Name,
+				"serviceid":   svc.ID,
+			})
+			if err := f.addService(ctx, tenantID, svc, false); err != nil {
+				return err
+			}
+			if err := f.restoreIPs(ctx, &svc); err != nil {
+				logger.WithError(err).Warn("Could not assign addresses to cloned service")
+			}
+			if err := traverse(svc.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return traverse("")
+}
+
+// discardClone removes what was created of a clone that could not be
+// completed.
+func (f *Facade) discardClone(ctx datastore.Context, tenantID string) {
+	logger := plog.WithField("tenantid", tenantID)
+	if err := f.removeService(ctx, tenantID); err != nil && !datastore.IsErrNoSuchEntity(err) {
+		logger.WithError(err).Warn("Could not remove the services of the cloned application")
+	}
+	if err := f.removeSecrets(ctx, tenantID); err != nil {
+		logger.WithError(err).Warn("Could not remove the secrets of the cloned application")
+	}
+	if err := f.dfs.Destroy(tenantID); err != nil {
+		logger.WithError(err).Warn("Could not remove the data of the cloned application")
+	}
+	f.zzk.RemoveTenantExports(tenantID)
+	f.zzk.DeleteRegistryLibrary(tenantID)
+}
+
+// cloneTenantServices copies the services of a tenant into a new tenant with
+// the given id and name.  Every service gets a new id, and images of the old
+// tenant are replaced with those of the new one.
+func cloneTenantServices(svcs []service.Service, fromTenantID, tenantID, name string) ([]service.Service, error) {
+	ids := make(map[string]string)
+	for _, svc := range svcs {
+		if svc.ID == fromTenantID {
+			ids[svc.ID] = tenantID
+			continue
+		}
+		id, err := utils.NewUUID36()
+		if err != nil {
+			return nil, err
+		}
+		ids[svc.ID] = id
+	}
+	if _, ok := ids[fromTenantID]; !ok {
+		return nil, ErrCloneNoTenant
+	}
+
+	suffix := strings.Trim(vhostSuffixRe.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if suffix == "" {
+		suffix = tenantID[0:12]
+	}
+
+	now := time.Now()
+	clones := make([]service.Service, len(svcs))
+	for i, svc := range svcs {
+		svc.ID = ids[svc.ID]
+		if svc.ID == tenantID {
+			svc.Name = name
+		} else if parentID, ok := ids[svc.ParentServiceID]; ok {
+			svc.ParentServiceID = parentID
+		} else {
+			return nil, fmt.Errorf("parent %s of service %s is not in the snapshot", svc.ParentServiceID, svc.Name)
+		}
+		if svc.ImageID != "" {
+			imageID, err := commons.ParseImageID(svc.ImageID)
+			if err != nil {
+				return nil, err
+			}
+			if imageID.User == fromTenantID {
+				imageID.User = tenantID
+				svc.ImageID = imageID.String()
+			}
+		}
+		svc.DesiredState = int(service.SVCStop)
+		svc.DatabaseVersion = 0
+		svc.CreatedAt = now
+		svc.UpdatedAt = now
+
+		// copy the endpoints so the snapshot's services are left untouched
+		endpoints := make([]service.ServiceEndpoint, len(svc.Endpoints))
+		for j, ep := range svc.Endpoints {
+			vhosts := make([]servicedefinition.VHost, len(ep.VHostList))
+			for k, vhost := range ep.VHostList {
+				if vhost.Enabled {
+					vhost.Name += "-" + suffix
+				}
+				vhosts[k] = vhost
+			}
+			ep.VHostList = vhosts
+			ports := make([]servicedefinition.Port, len(ep.PortList))
+			for k, port := range ep.PortList {
+				port.Enabled = false
+				ports[k] = port
+			}
+			ep.PortList = ports
+			endpoints[j] = ep
+		}
+		svc.Endpoints = endpoints
+		clones[i] = svc
+	}
+	return clones, nil
+}
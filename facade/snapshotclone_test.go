@@ -0,0 +1,150 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package facade
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/secret"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/volume"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeIntegrationTest) setupCloneSnapshot(c *C) *dfs.SnapshotInfo {
+	tenant := service.Service{
+		ID:           "clonetenant",
+		Name:         "TestFacade_CloneSnapshot",
+		DeploymentID: "deployment_id",
+		PoolID:       "pool_id",
+		Launch:       "auto",
+		DesiredState: int(service.SVCStop),
+	}
+	child := service.Service{
+		ID:              "clonechild",
+		Name:            "web",
+		DeploymentID:    "deployment_id",
+		PoolID:          "pool_id",
+		ImageID:         "clonetenant/web:latest",
+		Launch:          "auto",
+		DesiredState:    int(service.SVCStop),
+		ParentServiceID: tenant.ID,
+		Endpoints: []service.ServiceEndpoint{
+			{
+				Application: "web",
+				Name:        "web",
+				PortNumber:  8080,
+				Protocol:    "tcp",
+				Purpose:     "export",
+				PortList: []servicedefinition.Port{
+					{PortAddr: ":22222", Enabled: true, Protocol: "https"},
+				},
+				VHostList: []servicedefinition.VHost{
+					{Name: "web", Enabled: true},
+				},
+			},
+		},
+	}
+	ft.zzk.On("GetVHost", "web").Return("", "", nil)
+	ft.zzk.On("GetPublicPort", ":22222").Return("", "", nil)
+	c.Assert(ft.Facade.AddService(ft.CTX, tenant), IsNil)
+	c.Assert(ft.Facade.AddService(ft.CTX, child), IsNil)
+
+	svcs, err := ft.Facade.GetServices(ft.CTX, dao.ServiceRequest{TenantID: tenant.ID})
+	c.Assert(err, IsNil)
+	return &dfs.SnapshotInfo{
+		SnapshotInfo: &volume.SnapshotInfo{
+			Name:     "clonetenant_label",
+			TenantID: tenant.ID,
+			Label:    "label",
+		},
+		Services: svcs,
+	}
+}
+
+func (ft *FacadeIntegrationTest) TestFacade_CloneSnapshot(c *C) {
+	info := ft.setupCloneSnapshot(c)
+	ft.dfs.On("Info", info.Name).Return(info, nil)
+	ft.dfs.On("Clone", info.Name, mock.AnythingOfType("string")).Return(nil)
+	ft.zzk.On("GetVHost", "web-staging-copy").Return("", "", nil)
+	err := ft.Facade.secretStore.Put(ft.CTX, &secret.Secret{TenantID: info.TenantID, Name: "db_password", Value: "encrypted"})
+	c.Assert(err, IsNil)
+
+	tenantID, err := ft.Facade.CloneSnapshot(ft.CTX, info.Name, "Staging Copy")
+	c.Assert(err, IsNil)
+	c.Assert(tenantID, Not(Equals), info.TenantID)
+	ft.dfs.AssertCalled(c, "Clone", info.Name, tenantID)
+
+	tenant, err := ft.Facade.GetService(ft.CTX, tenantID)
+	c.Assert(err, IsNil)
+	c.Assert(tenant.Name, Equals, "Staging Copy")
+	c.Assert(tenant.ParentServiceID, Equals, "")
+
+	svcs, err := ft.Facade.GetServices(ft.CTX, dao.ServiceRequest{TenantID: tenantID})
+	c.Assert(err, IsNil)
+	c.Assert(svcs, HasLen, 2)
+	for _, svc := range svcs {
+		if svc.ID == tenantID {
+			continue
+		}
+		c.Assert(svc.ID, Not(Equals), "clonechild")
+		c.Assert(svc.Name, Equals, "web")
+		c.Assert(svc.ParentServiceID, Equals, tenantID)
+		c.Assert(svc.ImageID, Equals, tenantID+"/web:latest")
+		c.Assert(svc.Endpoints[0].VHostList[0].Name, Equals, "web-staging-copy")
+		c.Assert(svc.Endpoints[0].VHostList[0].Enabled, Equals, true)
+		c.Assert(svc.Endpoints[0].PortList[0].Enabled, Equals, false)
+	}
+
+	copied, err := ft.Facade.secretStore.Get(ft.CTX, tenantID, "db_password")
+	c.Assert(err, IsNil)
+	c.Assert(copied.Value, Equals, "encrypted")
+
+	// the original application is left as it was
+	_, err = ft.Facade.secretStore.Get(ft.CTX, info.TenantID, "db_password")
+	c.Assert(err, IsNil)
+	child, err := ft.Facade.GetService(ft.CTX, "clonechild")
+	c.Assert(err, IsNil)
+	c.Assert(child.Endpoints[0].VHostList[0].Name, Equals, "web")
+	c.Assert(child.Endpoints[0].PortList[0].Enabled, Equals, true)
+}
+
+func (ft *FacadeIntegrationTest) TestFacade_CloneSnapshot_NameInUse(c *C) {
+	info := ft.setupCloneSnapshot(c)
+	ft.dfs.On("Info", info.Name).Return(info, nil)
+
+	_, err := ft.Facade.CloneSnapshot(ft.CTX, info.Name, "TestFacade_CloneSnapshot")
+	c.Assert(err, Equals, ErrServiceCollision)
+	ft.dfs.AssertNotCalled(c, "Clone", info.Name, mock.AnythingOfType("string"))
+}
+
+func (ft *FacadeIntegrationTest) TestFacade_CloneSnapshot_DataFails(c *C) {
+	info := ft.setupCloneSnapshot(c)
+	expectedErr := errors.New("could not clone volume")
+	ft.dfs.On("Info", info.Name).Return(info, nil)
+	ft.dfs.On("Clone", info.Name, mock.AnythingOfType("string")).Return(expectedErr)
+
+	_, err := ft.Facade.CloneSnapshot(ft.CTX, info.Name, "Staging Copy")
+	c.Assert(err, Equals, expectedErr)
+
+	svcs, err := ft.Facade.GetServices(ft.CTX, dao.ServiceRequest{})
+	c.Assert(err, IsNil)
+	c.Assert(svcs, HasLen, 2)
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/volume"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_CloneSnapshotRequiresName(c *C) {
+	_, err := ft.Facade.CloneSnapshot(ft.ctx, "tenant_label", " ")
+	c.Assert(err, Equals, facade.ErrCloneNameRequired)
+	ft.dfs.AssertNotCalled(c, "Info", "tenant_label")
+}
+
+func (ft *FacadeUnitTest) Test_CloneSnapshotNoSnapshot(c *C) {
+	expectedErr := errors.New("snapshot not found")
+	ft.dfs.On("Info", "tenant_label").Return(nil, expectedErr)
+
+	_, err := ft.Facade.CloneSnapshot(ft.ctx, "tenant_label", "staging")
+	c.Assert(err, Equals, expectedErr)
+	ft.dfs.AssertNotCalled(c, "Clone", "tenant_label", mock.AnythingOfType("string"))
+}
+
+func (ft *FacadeUnitTest) Test_CloneSnapshotNoTenantService(c *C) {
+	info := &dfs.SnapshotInfo{
+		SnapshotInfo: &volume.SnapshotInfo{TenantID: "tenant", Label: "label"},
+		Services: []service.Service{
+			{ID: "child", Name: "child", ParentServiceID: "tenant"},
+		},
+	}
+	ft.dfs.On("Info", "tenant_label").Return(info, nil)
+
+	_, err := ft.Facade.CloneSnapshot(ft.ctx, "tenant_label", "staging")
+	c.Assert(err, Equals, facade.ErrCloneNoTenant)
+}
@@ -0,0 +1,259 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/volume"
+)
+
+// volatileServiceFields are fields of a service definition that change while
+// an application runs, and are not reported by a snapshot diff.
+var volatileServiceFields = []string{
+	"DatabaseVersion",
+	"CreatedAt",
+	"UpdatedAt",
+	"DesiredState",
+	"CurrentState",
+	"EmergencyShutdown",
+	"AutoScaledUp",
+	"AutoScaledDown",
+}
+
+// DiffSnapshots returns the files and service definitions that differ going
+// from a snapshot to another snapshot of the same application, or to the
+// current state of the application if toSnapshotID is empty.
+func (f *Facade) DiffSnapshots(ctx datastore.Context, snapshotID, toSnapshotID string) (*dao.SnapshotDiff, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.DiffSnapshots"))
+	logger := plog.WithFields(logrus.Fields{
+		"snapshotid":   snapshotID,
+		"tosnapshotid": toSnapshotID,
+	})
+
+	info, toInfo, err := f.snapshotDiffInfo(ctx, snapshotID, toSnapshotID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get info for snapshot")
+		return nil, err
+	}
+	logger = logger.WithField("tenantid", info.TenantID)
+
+	// comparing the data can take a while, so only keep the tenant from
+	// being rolled back or snapshotted in the meantime
+	mutex := getTenantLock(info.TenantID)
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	files, err := f.dfs.Diff(snapshotID, toSnapshotID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not compare the data of the snapshots")
+		return nil, err
+	}
+
+	var svcs []service.Service
+	if toInfo != nil {
+		svcs = toInfo.Services
+	} else {
+		svcs, err = f.GetServices(ctx, dao.ServiceRequest{TenantID: info.TenantID})
+		if err != nil {
+			logger.WithError(err).Debug("Could not get services under tenant")
+			return nil, err
+		}
+	}
+	changes, err := diffServices(info.Services, svcs)
+	if err != nil {
+		logger.WithError(err).Debug("Could not compare the services of the snapshots")
+		return nil, err
+	}
+	if files == nil {
+		files = []volume.FileChange{}
+	}
+	return &dao.SnapshotDiff{
+		SnapshotID:   snapshotID,
+		ToSnapshotID: toSnapshotID,
+		Files:        files,
+		Services:     changes,
+	}, nil
+}
+
+// snapshotDiffInfo returns the info of the snapshots to compare under the
+// dfs lock.  The info of the snapshot to compare to is nil if there is none,
+// and both snapshots must belong to the same tenant.
+func (f *Facade) snapshotDiffInfo(ctx datastore.Context, snapshotID, toSnapshotID string) (*dfs.SnapshotInfo, *dfs.SnapshotInfo, error) {
+	locker := f.DFSLock(ctx)
+	locker.Lock("diff snapshots")
+	defer locker.Unlock()
+
+	info, err := f.dfs.Info(snapshotID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if toSnapshotID == "" {
+		return info, nil, nil
+	}
+	toInfo, err := f.dfs.Info(toSnapshotID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if toInfo.TenantID != info.TenantID {
+		return nil, nil, dfs.ErrDiffTenantMismatch
+	}
+	return info, toInfo, nil
+}
+
+// diffServices returns the services that are added, removed or modified
+// going from one set of service definitions of an application to another,
+// sorted by path.  Services are matched by id.
+func diffServices(from, to []service.Service) ([]dao.ServiceChange, error) {
+	fromPaths, toPaths := servicePaths(from), servicePaths(to)
+	fromSvcs := make(map[string]service.Service)
+	for _, svc := range from {
+		fromSvcs[svc.ID] = svc
+	}
+
+	changes := []dao.ServiceChange{}
+	for _, svc := range to {
+		old, ok := fromSvcs[svc.ID]
+		if !ok {
+			changes = append(changes, dao.ServiceChange{
+				ServiceID: svc.ID,
+				Path:      toPaths[svc.ID],
+				Change:    dao.ServiceAdded,
+			})
+			continue
+		}
+		delete(fromSvcs, svc.ID)
+		fields, err := diffServiceFields(old, svc)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) > 0 {
+			changes = append(changes, dao.ServiceChange{
+				ServiceID: svc.ID,
+				Path:      toPaths[svc.ID],
+				Change:    dao.ServiceModified,
+				Fields:    fields,
+			})
+		}
+	}
+	for _, svc := range from {
+		if _, ok := fromSvcs[svc.ID]; ok {
+			changes = append(changes, dao.ServiceChange{
+				ServiceID: svc.ID,
+				Path:      fromPaths[svc.ID],
+				Change:    dao.ServiceRemoved,
+			})
+		}
+	}
+	sort.Sort(serviceChanges(changes))
+	return changes, nil
+}
+
+// diffServiceFields returns the sorted names of the top-level fields that
+// differ between two definitions of a service, leaving out those that change
+// while the service runs.  The instance count of a service that is autoscaled
+// in both definitions is set by the autoscaler, so it is left out too.
+func diffServiceFields(from, to service.Service) ([]string, error) {
+	fromFields, err := serviceFields(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := serviceFields(to)
+	if err != nil {
+		return nil, err
+	}
+	if from.AutoScale.Enabled() && to.AutoScale.Enabled() {
+		delete(fromFields, "Instances")
+		delete(toFields, "Instances")
+	}
+	fields := []string{}
+	for name, value := range toFields {
+		if !reflect.DeepEqual(fromFields[name], value) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range fromFields {
+		if _, ok := toFields[name]; !ok {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// serviceFields returns the fields of a service definition as they are
+// marshaled, without the volatile fields and endpoint address assignments.
+func serviceFields(svc service.Service) (map[string]interface{}, error) {
+	endpoints := make([]service.ServiceEndpoint, len(svc.Endpoints))
+	for i, ep := range svc.Endpoints {
+		ep.RemoveAssignment()
+		endpoints[i] = ep
+	}
+	svc.Endpoints = endpoints
+	b, err := json.Marshal(svc)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range volatileServiceFields {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+// servicePaths returns the path of each service by its id, built from the
+// names of its ancestors in the given set.
+func servicePaths(svcs []service.Service) map[string]string {
+	svcmap := make(map[string]service.Service)
+	for _, svc := range svcs {
+		svcmap[svc.ID] = svc
+	}
+	paths := make(map[string]string)
+	for _, svc := range svcs {
+		names := []string{}
+		seen := make(map[string]bool)
+		for s, ok := svc, true; ok && !seen[s.ID]; s, ok = svcmap[s.ParentServiceID] {
+			seen[s.ID] = true
+			names = append([]string{s.Name}, names...)
+		}
+		paths[svc.ID] = "/" + strings.Join(names, "/")
+	}
+	return paths
+}
+
+// serviceChanges is a ServiceChange array sortable by path
+type serviceChanges []dao.ServiceChange
+
+func (p serviceChanges) Len() int {
+	return len(p)
+}
+
+func (p serviceChanges) Less(i, j int) bool {
+	return p[i].Path < p[j].Path
+}
+
+func (p serviceChanges) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
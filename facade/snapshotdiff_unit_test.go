@@ -0,0 +1,105 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/volume"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_DiffSnapshots(c *C) {
+	// instances added by the autoscaler are not reported
+	autoscaled := servicedefinition.AutoScale{Metric: "cpu", Target: 50}
+	from := &dfs.SnapshotInfo{
+		SnapshotInfo: &volume.SnapshotInfo{TenantID: "tenant", Label: "label1"},
+		Services: []service.Service{
+			{ID: "tenant", Name: "app", DesiredState: int(service.SVCRun)},
+			{ID: "a", Name: "alpha", ParentServiceID: "tenant", Instances: 1},
+			{ID: "b", Name: "beta", ParentServiceID: "tenant", Startup: "run"},
+			{ID: "d", Name: "delta", ParentServiceID: "tenant", Instances: 2, AutoScale: autoscaled},
+		},
+	}
+	to := &dfs.SnapshotInfo{
+		SnapshotInfo: &volume.SnapshotInfo{TenantID: "tenant", Label: "label2"},
+		Services: []service.Service{
+			{ID: "tenant", Name: "app", DesiredState: int(service.SVCStop)},
+			{ID: "a", Name: "alpha", ParentServiceID: "tenant", Instances: 2, Startup: "run"},
+			{ID: "c", Name: "gamma", ParentServiceID: "a"},
+			{ID: "d", Name: "delta", ParentServiceID: "tenant", Instances: 5, AutoScale: autoscaled, AutoScaledUp: time.Now()},
+		},
+	}
+	files := []volume.FileChange{
+		{Path: "a file", Change: volume.FileModified, Size: 15, OldSize: 9},
+	}
+	ft.dfs.On("Info", "tenant_label1").Return(from, nil)
+	ft.dfs.On("Info", "tenant_label2").Return(to, nil)
+	ft.dfs.On("Diff", "tenant_label1", "tenant_label2").Return(files, nil)
+
+	diff, err := ft.Facade.DiffSnapshots(ft.ctx, "tenant_label1", "tenant_label2")
+	c.Assert(err, IsNil)
+	c.Assert(diff.SnapshotID, Equals, "tenant_label1")
+	c.Assert(diff.ToSnapshotID, Equals, "tenant_label2")
+	c.Assert(diff.Files, DeepEquals, files)
+	c.Assert(diff.Services, DeepEquals, []dao.ServiceChange{
+		{ServiceID: "a", Path: "/app/alpha", Change: dao.ServiceModified, Fields: []string{"Instances", "Startup"}},
+		{ServiceID: "c", Path: "/app/alpha/gamma", Change: dao.ServiceAdded},
+		{ServiceID: "b", Path: "/app/beta", Change: dao.ServiceRemoved},
+	})
+}
+
+func (ft *FacadeUnitTest) Test_DiffSnapshotsNoSnapshot(c *C) {
+	expectedErr := errors.New("snapshot not found")
+	ft.dfs.On("Info", "tenant_label1").Return(nil, expectedErr)
+
+	_, err := ft.Facade.DiffSnapshots(ft.ctx, "tenant_label1", "tenant_label2")
+	c.Assert(err, Equals, expectedErr)
+	ft.dfs.AssertNotCalled(c, "Diff", "tenant_label1", "tenant_label2")
+}
+
+func (ft *FacadeUnitTest) Test_DiffSnapshotsDiffFails(c *C) {
+	info := &dfs.SnapshotInfo{
+		SnapshotInfo: &volume.SnapshotInfo{TenantID: "tenant", Label: "label1"},
+	}
+	ft.dfs.On("Info", "tenant_label1").Return(info, nil)
+	expectedErr := errors.New("could not mount snapshot")
+	ft.dfs.On("Diff", "tenant_label1", "").Return(nil, expectedErr)
+
+	_, err := ft.Facade.DiffSnapshots(ft.ctx, "tenant_label1", "")
+	c.Assert(err, Equals, expectedErr)
+	ft.serviceStore.AssertNotCalled(c, "GetServices")
+}
+
+func (ft *FacadeUnitTest) Test_DiffSnapshotsOtherTenant(c *C) {
+	info := &dfs.SnapshotInfo{
+		SnapshotInfo: &volume.SnapshotInfo{TenantID: "tenant", Label: "label1"},
+	}
+	other := &dfs.SnapshotInfo{
+		SnapshotInfo: &volume.SnapshotInfo{TenantID: "other", Label: "label1"},
+	}
+	ft.dfs.On("Info", "tenant_label1").Return(info, nil)
+	ft.dfs.On("Info", "other_label1").Return(other, nil)
+
+	_, err := ft.Facade.DiffSnapshots(ft.ctx, "tenant_label1", "other_label1")
+	c.Assert(err, Equals, dfs.ErrDiffTenantMismatch)
+	ft.dfs.AssertNotCalled(c, "Diff", "tenant_label1", "other_label1")
+}
@@ -0,0 +1,197 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/snapshotschedule"
+	"github.com/control-center/serviced/volume"
+)
+
+// ErrSnapshotScheduleNotFound is returned when a tenant has no snapshot schedule
+var ErrSnapshotScheduleNotFound = errors.New("facade: snapshot schedule not found")
+
+// SetSnapshotSchedule sets how often the tenant of a service is snapshotted,
+// either at an interval or by a cron expression, and which of its snapshots
+// are kept.  It replaces the tenant's existing schedule, if any.  The first
+// snapshot is taken one interval, or at the first cron time, from now.
+func (f *Facade) SetSnapshotSchedule(ctx datastore.Context, serviceID string, interval time.Duration, cron string, retention snapshotschedule.Retention) (*snapshotschedule.SnapshotSchedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetSnapshotSchedule"))
+	alog := f.auditLogger.Message(ctx, "Set Snapshot Schedule").Action(audit.Update).
+		Type(snapshotschedule.GetType())
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return nil, alog.ID(serviceID).Error(err)
+	}
+	alog = alog.ID(tenantID)
+	schedule := &snapshotschedule.SnapshotSchedule{
+		TenantID:  tenantID,
+		Interval:  interval,
+		Cron:      cron,
+		Retention: retention,
+		LastRun:   time.Now().UTC(),
+		CreatedBy: ctx.User(),
+	}
+	if err := schedule.ValidEntity(); err != nil {
+		return nil, alog.Error(err)
+	}
+	if existing, err := f.scheduleStore.Get(ctx, tenantID); err == nil {
+		schedule.DatabaseVersion = existing.DatabaseVersion
+	} else if !datastore.IsErrNoSuchEntity(err) {
+		return nil, alog.Error(err)
+	}
+	if err := f.scheduleStore.Put(ctx, schedule); err != nil {
+		return nil, alog.Error(err)
+	}
+	alog.WithFields(logrus.Fields{
+		"frequency": schedule.Frequency(),
+		"retention": schedule.Retention.String(),
+	}).Succeeded()
+	return schedule, nil
+}
+
+// GetSnapshotSchedules returns the snapshot schedules of all tenants
+func (f *Facade) GetSnapshotSchedules(ctx datastore.Context) ([]snapshotschedule.SnapshotSchedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSnapshotSchedules"))
+	schedules, err := f.scheduleStore.GetSnapshotSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]snapshotschedule.SnapshotSchedule, len(schedules))
+	for i, s := range schedules {
+		result[i] = *s
+	}
+	return result, nil
+}
+
+// GetSnapshotSchedule returns the snapshot schedule of a tenant
+func (f *Facade) GetSnapshotSchedule(ctx datastore.Context, tenantID string) (*snapshotschedule.SnapshotSchedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSnapshotSchedule"))
+	schedule, err := f.scheduleStore.Get(ctx, tenantID)
+	if datastore.IsErrNoSuchEntity(err) {
+		return nil, ErrSnapshotScheduleNotFound
+	}
+	return schedule, err
+}
+
+// RemoveSnapshotSchedule stops scheduled snapshots of the tenant of a
+// service.  Its existing snapshots are purged by age again.
+func (f *Facade) RemoveSnapshotSchedule(ctx datastore.Context, serviceID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveSnapshotSchedule"))
+	alog := f.auditLogger.Message(ctx, "Remove Snapshot Schedule").Action(audit.Remove).
+		Type(snapshotschedule.GetType())
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return alog.ID(serviceID).Error(err)
+	}
+	alog = alog.ID(tenantID)
+	if _, err := f.scheduleStore.Get(ctx, tenantID); datastore.IsErrNoSuchEntity(err) {
+		return alog.Error(ErrSnapshotScheduleNotFound)
+	} else if err != nil {
+		return alog.Error(err)
+	}
+	if err := f.scheduleStore.Delete(ctx, tenantID); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// RunSnapshotSchedules snapshots the tenants whose schedules are due, and
+// then deletes the snapshots that their retention rules no longer keep.
+func (f *Facade) RunSnapshotSchedules(ctx datastore.Context, now time.Time) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RunSnapshotSchedules"))
+	schedules, err := f.scheduleStore.GetSnapshotSchedules(ctx)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		if !schedule.Due(now) {
+			continue
+		}
+		logger := plog.WithField("tenantid", schedule.TenantID)
+
+		// Record the run first, so that a snapshot that fails is tried
+		// again at the next scheduled time rather than straight away.
+		schedule.LastRun = now.UTC()
+		if err := f.scheduleStore.Put(ctx, schedule); err != nil {
+			logger.WithError(err).Warn("Could not update snapshot schedule")
+			continue
+		}
+		if err := f.runSnapshotSchedule(ctx, schedule); err != nil {
+			logger.WithError(err).Warn("Could not run snapshot schedule")
+		}
+	}
+	return nil
+}
+
+func (f *Facade) runSnapshotSchedule(ctx datastore.Context, schedule *snapshotschedule.SnapshotSchedule) error {
+	logger := plog.WithField("tenantid", schedule.TenantID)
+
+	locker := f.DFSLock(ctx)
+	locker.Lock("scheduled snapshot")
+	defer locker.Unlock()
+
+	snapshotID, err := f.Snapshot(ctx, schedule.TenantID, snapshotschedule.Message, nil, config.GetOptions().SnapshotSpacePercent)
+	if err != nil {
+		return err
+	}
+	logger.WithField("snapshotid", snapshotID).Info("Took scheduled snapshot")
+	return f.pruneSnapshots(ctx, schedule.TenantID, schedule.Retention)
+}
+
+// pruneSnapshots deletes the scheduled snapshots of a tenant that the
+// retention rules do not keep.  Snapshots taken by other means are left
+// alone.  The caller must hold the dfs lock.
+func (f *Facade) pruneSnapshots(ctx datastore.Context, tenantID string, retention snapshotschedule.Retention) error {
+	if retention.IsZero() {
+		return nil
+	}
+	logger := plog.WithField("tenantid", tenantID)
+	snapshotIDs, err := f.ListSnapshots(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	snapshots := []snapshotschedule.Snapshot{}
+	for _, snapshotID := range snapshotIDs {
+		info, err := f.GetSnapshotInfo(ctx, snapshotID)
+		if err == volume.ErrInvalidSnapshot {
+			// deprecated snapshots are left for an administrator to remove
+			continue
+		} else if err != nil {
+			return err
+		}
+		if info.Message != snapshotschedule.Message {
+			continue
+		}
+		snapshots = append(snapshots, snapshotschedule.Snapshot{
+			ID:      snapshotID,
+			Created: info.Created,
+			Tagged:  len(info.Tags) > 0,
+		})
+	}
+	for _, snapshotID := range retention.Expired(snapshots) {
+		if err := f.DeleteSnapshot(ctx, snapshotID); err != nil {
+			return err
+		}
+		logger.WithField("snapshotid", snapshotID).Info("Deleted snapshot expired by retention policy")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/snapshotschedule"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_SetSnapshotScheduleNew(c *C) {
+	tenantID := getRandomServiceID(c)
+	ft.ctx.On("User").Return("admin")
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, tenantID).Return(&service.ServiceDetails{ID: tenantID}, nil)
+	ft.scheduleStore.On("Get", ft.ctx, tenantID).Return(nil, datastore.ErrNoSuchEntity{})
+	var stored *snapshotschedule.SnapshotSchedule
+	ft.scheduleStore.On("Put", ft.ctx, mock.AnythingOfType("*snapshotschedule.SnapshotSchedule")).Return(nil).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*snapshotschedule.SnapshotSchedule)
+	})
+
+	retention := snapshotschedule.Retention{Hourly: 24, Weekly: 4}
+	schedule, err := ft.Facade.SetSnapshotSchedule(ft.ctx, tenantID, 4*time.Hour, "", retention)
+	c.Assert(err, IsNil)
+	c.Assert(stored, Equals, schedule)
+	c.Assert(schedule.TenantID, Equals, tenantID)
+	c.Assert(schedule.Interval, Equals, 4*time.Hour)
+	c.Assert(schedule.Retention, Equals, retention)
+	c.Assert(schedule.CreatedBy, Equals, "admin")
+	c.Assert(schedule.LastRun.IsZero(), Equals, false)
+}
+
+func (ft *FacadeUnitTest) Test_SetSnapshotScheduleReplacesExisting(c *C) {
+	tenantID := getRandomServiceID(c)
+	serviceID := getRandomServiceID(c)
+	ft.ctx.On("User").Return("admin")
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, tenantID).Return(&service.ServiceDetails{ID: tenantID}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID, ParentServiceID: tenantID}, nil)
+	existing := &snapshotschedule.SnapshotSchedule{TenantID: tenantID, Interval: time.Hour}
+	existing.DatabaseVersion = 3
+	ft.scheduleStore.On("Get", ft.ctx, tenantID).Return(existing, nil)
+	ft.scheduleStore.On("Put", ft.ctx, mock.AnythingOfType("*snapshotschedule.SnapshotSchedule")).Return(nil)
+
+	schedule, err := ft.Facade.SetSnapshotSchedule(ft.ctx, serviceID, 0, "0 2 * * *", snapshotschedule.Retention{})
+	c.Assert(err, IsNil)
+	c.Assert(schedule.TenantID, Equals, tenantID)
+	c.Assert(schedule.Interval, Equals, time.Duration(0))
+	c.Assert(schedule.Cron, Equals, "0 2 * * *")
+	c.Assert(schedule.DatabaseVersion, Equals, 3)
+}
+
+func (ft *FacadeUnitTest) Test_SetSnapshotScheduleInvalid(c *C) {
+	tenantID := getRandomServiceID(c)
+	ft.ctx.On("User").Return("admin")
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, tenantID).Return(&service.ServiceDetails{ID: tenantID}, nil)
+
+	_, err := ft.Facade.SetSnapshotSchedule(ft.ctx, tenantID, time.Minute, "", snapshotschedule.Retention{})
+	c.Assert(err, NotNil)
+	_, err = ft.Facade.SetSnapshotSchedule(ft.ctx, tenantID, time.Hour, "0 2 * * *", snapshotschedule.Retention{})
+	c.Assert(err, NotNil)
+	_, err = ft.Facade.SetSnapshotSchedule(ft.ctx, tenantID, time.Hour, "", snapshotschedule.Retention{Daily: -1})
+	c.Assert(err, NotNil)
+	ft.scheduleStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_GetSnapshotScheduleNotFound(c *C) {
+	ft.scheduleStore.On("Get", ft.ctx, "missing").Return(nil, datastore.ErrNoSuchEntity{})
+
+	_, err := ft.Facade.GetSnapshotSchedule(ft.ctx, "missing")
+	c.Assert(err, Equals, facade.ErrSnapshotScheduleNotFound)
+}
+
+func (ft *FacadeUnitTest) Test_RemoveSnapshotScheduleNotFound(c *C) {
+	tenantID := getRandomServiceID(c)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, tenantID).Return(&service.ServiceDetails{ID: tenantID}, nil)
+	ft.scheduleStore.On("Get", ft.ctx, tenantID).Return(nil, datastore.ErrNoSuchEntity{})
+
+	err := ft.Facade.RemoveSnapshotSchedule(ft.ctx, tenantID)
+	c.Assert(err, Equals, facade.ErrSnapshotScheduleNotFound)
+	ft.scheduleStore.AssertNotCalled(c, "Delete", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_RunSnapshotSchedulesNotDue(c *C) {
+	now := time.Now()
+	ft.scheduleStore.On("GetSnapshotSchedules", ft.ctx).Return([]*snapshotschedule.SnapshotSchedule{
+		{TenantID: "tenant", Interval: time.Hour, LastRun: now.Add(-30 * time.Minute)},
+	}, nil)
+
+	err := ft.Facade.RunSnapshotSchedules(ft.ctx, now)
+	c.Assert(err, IsNil)
+	ft.scheduleStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_RunSnapshotSchedulesRecordsFailedRun(c *C) {
+	ft.setupMockDFSLocking()
+	now := time.Now()
+	schedule := &snapshotschedule.SnapshotSchedule{TenantID: "tenant", Interval: time.Hour, LastRun: now.Add(-2 * time.Hour)}
+	ft.scheduleStore.On("GetSnapshotSchedules", ft.ctx).Return([]*snapshotschedule.SnapshotSchedule{schedule}, nil)
+	ft.scheduleStore.On("Put", ft.ctx, schedule).Return(nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "tenant").Return(nil, errors.New("tenant is gone"))
+
+	err := ft.Facade.RunSnapshotSchedules(ft.ctx, now)
+	c.Assert(err, IsNil)
+	c.Assert(schedule.LastRun, Equals, now.UTC())
+	ft.scheduleStore.AssertCalled(c, "Put", ft.ctx, schedule)
+	ft.dfs.AssertNotCalled(c, "List", mock.Anything)
+}
@@ -25,13 +25,17 @@ import (
 	"github.com/control-center/serviced/datastore/elastic"
 	dfsmocks "github.com/control-center/serviced/dfs/mocks"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/apitoken"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/snapshotschedule"
 	"github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/domain/webhook"
 	zzkmocks "github.com/control-center/serviced/facade/mocks"
 	"github.com/control-center/serviced/scheduler/servicestatemanager"
 	"github.com/stretchr/testify/mock"
@@ -66,6 +70,10 @@ func (ft *FacadeIntegrationTest) SetUpSuite(c *gocheck.C) {
 	ft.Mappings = append(ft.Mappings, serviceconfigfile.MAPPING)
 	ft.Mappings = append(ft.Mappings, user.MAPPING)
 	ft.Mappings = append(ft.Mappings, registry.MAPPING)
+	ft.Mappings = append(ft.Mappings, secret.MAPPING)
+	ft.Mappings = append(ft.Mappings, apitoken.MAPPING)
+	ft.Mappings = append(ft.Mappings, webhook.MAPPING)
+	ft.Mappings = append(ft.Mappings, snapshotschedule.MAPPING)
 
 	ft.ElasticTest.SetUpSuite(c)
 	datastore.Register(ft.Driver())
@@ -99,6 +107,7 @@ func (ft *FacadeIntegrationTest) SetUpSuite(c *gocheck.C) {
 	// Create a master key pair
 	pub, priv, _ := auth.GenerateRSAKeyPairPEM(nil)
 	auth.LoadMasterKeysFromPEM(pub, priv)
+	auth.LoadSecretKey(make([]byte, 32))
 }
 
 func (ft *FacadeIntegrationTest) SetUpTest(c *gocheck.C) {
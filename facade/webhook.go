@@ -0,0 +1,111 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/webhook"
+)
+
+// ErrWebhookNotFound is returned when no webhook has the requested id
+var ErrWebhookNotFound = errors.New("facade: webhook not found")
+
+// AddWebhook subscribes a URL to cluster events of the given types (all
+// types if none are given), optionally only those of a single tenant.
+// Deliveries are signed with the secret, or with a generated secret if none
+// is given.  It returns the secret, which cannot be recovered afterwards,
+// along with the stored webhook.
+func (f *Facade) AddWebhook(ctx datastore.Context, url string, eventTypes []string, tenantID, secret string) (string, *webhook.Webhook, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AddWebhook"))
+	// The secret is never written to the audit log
+	alog := f.auditLogger.Message(ctx, "Add Webhook").Action(audit.Add).
+		Type(webhook.GetType()).WithField("url", url)
+	if tenantID != "" {
+		if err := f.verifyTenant(ctx, tenantID); err != nil {
+			return "", nil, alog.Error(err)
+		}
+	}
+	var err error
+	if secret == "" {
+		if secret, err = webhook.NewSecret(); err != nil {
+			return "", nil, alog.Error(err)
+		}
+	}
+	encrypted, err := auth.EncryptSecret(secret)
+	if err != nil {
+		plog.WithError(err).WithField("url", url).Error("Could not encrypt webhook secret")
+		return "", nil, alog.Error(err)
+	}
+	id, err := webhook.NewID()
+	if err != nil {
+		return "", nil, alog.Error(err)
+	}
+	alog = alog.ID(id)
+	w := &webhook.Webhook{
+		ID:        id,
+		URL:       url,
+		Events:    eventTypes,
+		TenantID:  tenantID,
+		Secret:    encrypted,
+		CreatedBy: ctx.User(),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := w.ValidEntity(); err != nil {
+		return "", nil, alog.Error(err)
+	}
+	if err := f.webhookStore.Put(ctx, w); err != nil {
+		return "", nil, alog.Error(err)
+	}
+	f.webhooks.invalidate()
+	alog.Succeeded()
+	result := w.Redact()
+	return secret, &result, nil
+}
+
+// GetWebhooks returns all webhooks with their secrets redacted
+func (f *Facade) GetWebhooks(ctx datastore.Context) ([]webhook.Webhook, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetWebhooks"))
+	hooks, err := f.webhookStore.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]webhook.Webhook, len(hooks))
+	for i, w := range hooks {
+		result[i] = w.Redact()
+	}
+	return result, nil
+}
+
+// RemoveWebhook deletes a webhook; no further events are delivered to it
+func (f *Facade) RemoveWebhook(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveWebhook"))
+	alog := f.auditLogger.Message(ctx, "Remove Webhook").Action(audit.Remove).
+		ID(id).Type(webhook.GetType())
+	if _, err := f.webhookStore.Get(ctx, id); datastore.IsErrNoSuchEntity(err) {
+		return alog.Error(ErrWebhookNotFound)
+	} else if err != nil {
+		return alog.Error(err)
+	}
+	if err := f.webhookStore.Delete(ctx, id); err != nil {
+		return alog.Error(err)
+	}
+	f.webhooks.invalidate()
+	alog.Succeeded()
+	return nil
+}
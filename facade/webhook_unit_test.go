@@ -0,0 +1,178 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/webhook"
+	"github.com/control-center/serviced/events"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_AddWebhookEncryptsSecret(c *C) {
+	ft.ctx.On("User").Return("admin")
+	var stored *webhook.Webhook
+	ft.webhookStore.On("Put", ft.ctx, mock.AnythingOfType("*webhook.Webhook")).Return(nil).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*webhook.Webhook)
+	})
+
+	secret, w, err := ft.Facade.AddWebhook(ft.ctx, "https://chat.example.com/hook", []string{"instance.failed"}, "", "")
+	c.Assert(err, IsNil)
+	c.Assert(secret, Not(Equals), "")
+	c.Assert(w.Secret, Equals, "")
+	c.Assert(w.CreatedBy, Equals, "admin")
+	c.Assert(w.Events, DeepEquals, []string{"instance.failed"})
+
+	c.Assert(stored, NotNil)
+	c.Assert(stored.ID, Equals, w.ID)
+	c.Assert(stored.Secret, Not(Equals), secret)
+	decrypted, err := auth.DecryptSecret(stored.Secret)
+	c.Assert(err, IsNil)
+	c.Assert(decrypted, Equals, secret)
+}
+
+func (ft *FacadeUnitTest) Test_AddWebhookInvalid(c *C) {
+	ft.ctx.On("User").Return("admin")
+
+	_, _, err := ft.Facade.AddWebhook(ft.ctx, "https://chat.example.com/hook", []string{"bogus"}, "", "secret")
+	c.Assert(err, NotNil)
+	_, _, err = ft.Facade.AddWebhook(ft.ctx, "chat.example.com", nil, "", "secret")
+	c.Assert(err, NotNil)
+	ft.webhookStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_RemoveWebhookNotFound(c *C) {
+	ft.webhookStore.On("Get", ft.ctx, "missing").Return(nil, datastore.ErrNoSuchEntity{})
+
+	err := ft.Facade.RemoveWebhook(ft.ctx, "missing")
+	c.Assert(err, Equals, facade.ErrWebhookNotFound)
+	ft.webhookStore.AssertNotCalled(c, "Delete", mock.Anything, mock.Anything)
+}
+
+type webhookDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// runWebhookDelivery delivers webhooks to a receiver that responds with the
+// given status codes, in turn, for the first requests and 200 afterwards.
+// It publishes host.offline events until done returns true.
+func (ft *FacadeUnitTest) runWebhookDelivery(c *C, deadLetterPath string, codes []int, done func([]webhookDelivery) bool) []webhookDelivery {
+	var mu sync.Mutex
+	var deliveries []webhookDelivery
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		deliveries = append(deliveries, webhookDelivery{r.Header, body})
+		if n := len(deliveries); n <= len(codes) {
+			w.WriteHeader(codes[n-1])
+		}
+	}))
+	defer server.Close()
+
+	secret, err := auth.EncryptSecret("secret")
+	c.Assert(err, IsNil)
+	ft.webhookStore.On("GetWebhooks", ft.ctx).Return([]*webhook.Webhook{
+		{ID: "hook", URL: server.URL, Events: []string{"host.offline"}, Secret: secret},
+	}, nil)
+	ft.Facade.SetWebhookRetryPolicy(3, time.Millisecond)
+
+	shutdown := make(chan interface{})
+	stopped := make(chan struct{})
+	go func() {
+		ft.Facade.DeliverWebhooks(ft.ctx, shutdown, deadLetterPath)
+		close(stopped)
+	}()
+	defer func() {
+		close(shutdown)
+		<-stopped
+	}()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		ft.Facade.PublishEvent(events.Event{Type: events.HostOnline, HostID: "host"})
+		ft.Facade.PublishEvent(events.Event{Type: events.HostOffline, HostID: "host"})
+		select {
+		case <-time.After(20 * time.Millisecond):
+		case <-timeout:
+			c.Fatalf("webhook was not delivered")
+		}
+		mu.Lock()
+		result := append([]webhookDelivery{}, deliveries...)
+		mu.Unlock()
+		if done(result) {
+			return result
+		}
+	}
+}
+
+func (ft *FacadeUnitTest) Test_DeliverWebhooksRetries(c *C) {
+	deliveries := ft.runWebhookDelivery(c, "", []int{http.StatusServiceUnavailable}, func(d []webhookDelivery) bool {
+		return len(d) >= 2
+	})
+
+	// the failed delivery is retried with the same event
+	c.Assert(deliveries[1].header.Get(webhook.DeliveryHeader), Equals, deliveries[0].header.Get(webhook.DeliveryHeader))
+	c.Assert(deliveries[1].header.Get(webhook.EventHeader), Equals, "host.offline")
+	c.Assert(deliveries[1].header.Get(webhook.SignatureHeader), Equals, webhook.Sign("secret", deliveries[1].body))
+
+	var e events.Event
+	c.Assert(json.Unmarshal(deliveries[1].body, &e), IsNil)
+	c.Assert(e.Type, Equals, events.HostOffline)
+	c.Assert(e.HostID, Equals, "host")
+}
+
+func (ft *FacadeUnitTest) Test_DeliverWebhooksDeadLetter(c *C) {
+	dir := c.MkDir()
+	deadLetterPath := filepath.Join(dir, "deadletter.log")
+	ft.runWebhookDelivery(c, deadLetterPath, []int{http.StatusBadRequest}, func(d []webhookDelivery) bool {
+		info, err := os.Stat(deadLetterPath)
+		return err == nil && info.Size() > 0
+	})
+
+	file, err := os.Open(deadLetterPath)
+	c.Assert(err, IsNil)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	c.Assert(scanner.Scan(), Equals, true)
+	var record struct {
+		WebhookID string
+		Attempts  int
+		Error     string
+		Event     events.Event
+	}
+	c.Assert(json.Unmarshal(scanner.Bytes(), &record), IsNil)
+
+	// a rejected delivery is not retried
+	c.Assert(record.WebhookID, Equals, "hook")
+	c.Assert(record.Attempts, Equals, 1)
+	c.Assert(record.Error, Matches, ".*400.*")
+	c.Assert(record.Event.Type, Equals, events.HostOffline)
+}
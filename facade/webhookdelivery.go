@@ -0,0 +1,290 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/webhook"
+	"github.com/control-center/serviced/events"
+)
+
+const (
+	// defaultWebhookAttempts is how many times a delivery is tried before it
+	// is written to the dead-letter log
+	defaultWebhookAttempts = 5
+
+	// defaultWebhookBackoff is the wait before the first retry; it doubles
+	// with each attempt
+	defaultWebhookBackoff = 2 * time.Second
+
+	// webhookTimeout limits how long a receiver may take to respond
+	webhookTimeout = 10 * time.Second
+
+	// webhookConcurrency limits the number of deliveries in flight
+	webhookConcurrency = 16
+)
+
+// webhookDispatcher delivers events to the webhooks that subscribe to them.
+type webhookDispatcher struct {
+	mu       sync.Mutex
+	hooks    []*webhook.Webhook
+	loaded   bool
+	attempts int
+	backoff  time.Duration
+	client   *http.Client
+	inflight chan struct{}
+	deadMu   sync.Mutex
+}
+
+func newWebhookDispatcher() *webhookDispatcher {
+	return &webhookDispatcher{
+		attempts: defaultWebhookAttempts,
+		backoff:  defaultWebhookBackoff,
+		client:   &http.Client{Timeout: webhookTimeout},
+		inflight: make(chan struct{}, webhookConcurrency),
+	}
+}
+
+// deadLetter is a record of a delivery that did not succeed
+type deadLetter struct {
+	Time      time.Time
+	WebhookID string
+	URL       string
+	Attempts  int
+	Error     string
+	Event     events.Event
+}
+
+// SetWebhookRetryPolicy sets how many times a webhook delivery is attempted,
+// and the wait before the first retry.
+func (f *Facade) SetWebhookRetryPolicy(attempts int, backoff time.Duration) {
+	f.webhooks.mu.Lock()
+	defer f.webhooks.mu.Unlock()
+	f.webhooks.attempts = attempts
+	f.webhooks.backoff = backoff
+}
+
+// invalidate reloads the webhooks before the next delivery
+func (d *webhookDispatcher) invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.loaded = false
+}
+
+// getHooks returns the webhooks, loading them from the store if they changed
+func (d *webhookDispatcher) getHooks(ctx datastore.Context, store webhook.Store) ([]*webhook.Webhook, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.loaded {
+		hooks, err := store.GetWebhooks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		d.hooks, d.loaded = hooks, true
+	}
+	return d.hooks, nil
+}
+
+// DeliverWebhooks POSTs each cluster event to the webhooks that subscribe to
+// it until shutdown.  Failed deliveries are retried with an increasing
+// delay; those that still fail are appended, one JSON object per line, to
+// the dead-letter log at deadLetterPath.
+func (f *Facade) DeliverWebhooks(ctx datastore.Context, shutdown <-chan interface{}, deadLetterPath string) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	f.webhooks.invalidate()
+	after := ""
+	for {
+		sub, backlog, ok := f.events.Subscribe(after, events.Filter{})
+		if !ok {
+			plog.WithField("after", after).Warn("Missed events while delivering webhooks")
+		}
+		for _, e := range backlog {
+			after = e.ID
+			f.dispatchWebhooks(ctx, shutdown, &wg, e, deadLetterPath)
+		}
+
+		// If the subscription is closed because deliveries fell behind, pick
+		// up where it left off.
+	Receive:
+		for {
+			select {
+			case e, ok := <-sub.Events():
+				if !ok {
+					break Receive
+				}
+				after = e.ID
+				f.dispatchWebhooks(ctx, shutdown, &wg, e, deadLetterPath)
+			case <-shutdown:
+				sub.Close()
+				return
+			}
+		}
+	}
+}
+
+// dispatchWebhooks starts delivering an event to each matching webhook
+func (f *Facade) dispatchWebhooks(ctx datastore.Context, shutdown <-chan interface{}, wg *sync.WaitGroup, e events.Event, deadLetterPath string) {
+	d := f.webhooks
+	hooks, err := d.getHooks(ctx, f.webhookStore)
+	if err != nil {
+		plog.WithError(err).WithField("event", e.ID).Warn("Could not load webhooks")
+		return
+	}
+	for _, hook := range hooks {
+		if !hook.Matches(e) {
+			continue
+		}
+		logger := plog.WithFields(log.Fields{
+			"webhookid": hook.ID,
+			"event":     e.ID,
+		})
+		secret, err := auth.DecryptSecret(hook.Secret)
+		if err != nil {
+			logger.WithError(err).Error("Could not decrypt webhook secret")
+			d.writeDeadLetter(deadLetterPath, hook, e, 0, err)
+			continue
+		}
+		body, err := json.Marshal(e)
+		if err != nil {
+			logger.WithError(err).Error("Could not encode event")
+			continue
+		}
+		select {
+		case d.inflight <- struct{}{}:
+		case <-shutdown:
+			return
+		}
+		wg.Add(1)
+		go func(hook *webhook.Webhook) {
+			defer wg.Done()
+			defer func() { <-d.inflight }()
+			d.deliver(shutdown, hook, e, body, secret, deadLetterPath)
+		}(hook)
+	}
+}
+
+// deliver POSTs an event to a webhook, retrying until it succeeds, the
+// receiver rejects it, or the attempts run out
+func (d *webhookDispatcher) deliver(shutdown <-chan interface{}, hook *webhook.Webhook, e events.Event, body []byte, secret, deadLetterPath string) {
+	d.mu.Lock()
+	attempts, backoff := d.attempts, d.backoff
+	d.mu.Unlock()
+
+	logger := plog.WithFields(log.Fields{
+		"webhookid": hook.ID,
+		"url":       hook.URL,
+		"event":     e.ID,
+	})
+
+	var err error
+	attempt := 0
+	for attempt < attempts {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff << uint(attempt-1)):
+			case <-shutdown:
+				d.writeDeadLetter(deadLetterPath, hook, e, attempt, fmt.Errorf("shut down before delivery: %s", err))
+				return
+			}
+		}
+		attempt++
+		var retry bool
+		if retry, err = d.post(hook.URL, e, body, secret); err == nil {
+			logger.WithField("attempts", attempt).Debug("Delivered webhook")
+			return
+		}
+		logger.WithError(err).WithField("attempt", attempt).Debug("Could not deliver webhook")
+		if !retry {
+			break
+		}
+	}
+	logger.WithError(err).WithField("attempts", attempt).Warn("Giving up on webhook delivery")
+	d.writeDeadLetter(deadLetterPath, hook, e, attempt, err)
+}
+
+// post makes a single delivery.  retry is false if the receiver rejected the
+// delivery such that sending it again will not help.
+func (d *webhookDispatcher) post(url string, e events.Event, body []byte, secret string) (retry bool, err error) {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "serviced-webhook")
+	req.Header.Set(webhook.EventHeader, string(e.Type))
+	req.Header.Set(webhook.DeliveryHeader, e.ID)
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, body))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("receiver responded %s", resp.Status)
+	default:
+		return false, fmt.Errorf("receiver responded %s", resp.Status)
+	}
+}
+
+// writeDeadLetter appends an undelivered event to the dead-letter log
+func (d *webhookDispatcher) writeDeadLetter(path string, hook *webhook.Webhook, e events.Event, attempts int, cause error) {
+	if path == "" {
+		return
+	}
+	logger := plog.WithField("deadletterlog", path)
+	record := deadLetter{
+		Time:      time.Now().UTC(),
+		WebhookID: hook.ID,
+		URL:       hook.URL,
+		Attempts:  attempts,
+		Event:     e,
+	}
+	if cause != nil {
+		record.Error = cause.Error()
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		logger.WithError(err).Error("Could not encode webhook dead letter")
+		return
+	}
+	d.deadMu.Lock()
+	defer d.deadMu.Unlock()
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		logger.WithError(err).Error("Could not open webhook dead-letter log")
+		return
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		logger.WithError(err).Error("Could not write webhook dead-letter log")
+	}
+}
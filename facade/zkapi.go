@@ -456,6 +456,142 @@ func (z *zkf) GetVirtualIPHostID(poolID, ip string) (string, error) {
 	return zks.GetHostID(conn, poolID, ip)
 }
 
+// ReassignVirtualIPs moves the pool's virtual IPs that are assigned to the
+// given host onto other registered hosts in the pool.
+func (z *zkf) ReassignVirtualIPs(_pool *pool.ResourcePool, hostID string) ([]string, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	handler := zks.NewZKAssignmentHandler(&zks.RandomHostSelectionStrategy{}, zks.NewRegisteredHostHandler(conn), conn)
+	return zks.MoveAssignments(conn, handler, hostID, _pool.VirtualIPs)
+}
+
+// GetVirtualIPStatuses returns the owners and failover history of the
+// virtual IPs in a pool.
+func (z *zkf) GetVirtualIPStatuses(poolID string) ([]zks.VirtualIPStatus, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return zks.GetIPStatuses(conn, poolID)
+}
+
+// SetRollingRestartStatus records the progress of the rolling restart of a
+// service.
+func (z *zkf) SetRollingRestartStatus(status service.RollingRestartStatus) error {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return err
+	}
+	return zks.SetRollingRestartStatus(conn, status)
+}
+
+// GetRollingRestartStatuses returns the progress of the most recent rolling
+// restart of a service, or of every service if serviceID is empty.
+func (z *zkf) GetRollingRestartStatuses(serviceID string) ([]service.RollingRestartStatus, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return zks.GetRollingRestartStatuses(conn, serviceID)
+}
+
+// RemoveRollingRestartStatus removes the rolling restart progress of a
+// service.
+func (z *zkf) RemoveRollingRestartStatus(serviceID string) error {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return err
+	}
+	return zks.RemoveRollingRestartStatus(conn, serviceID)
+}
+
+// GetTree returns the node at the given path in the coordinator along with
+// all of its descendants.
+func (z *zkf) GetTree(pth string) (*zzk.TreeNode, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		plog.WithError(err).Debug("Could not acquire root-based connection")
+		return nil, err
+	}
+	return zzk.GetTree(conn, pth)
+}
+
+// CheckState compares the resource pools, hosts, services, instance states,
+// locks and exports in the coordinator with the facade's view and optionally
+// repairs any inconsistencies.
+func (z *zkf) CheckState(ctx datastore.Context, view zks.FacadeView, repair bool) ([]zks.Inconsistency, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("zzk.CheckState"))
+	rootconn, err := getLocalConnection(ctx, "/")
+	if err != nil {
+		plog.WithError(err).Debug("Could not acquire root-based connection")
+		return nil, err
+	}
+
+	found, err := zks.CheckResourcePools(rootconn, view.Pools, repair)
+	if err != nil {
+		return nil, err
+	}
+
+	hostIPs := []string{}
+	for _, p := range view.Pools {
+		logger := plog.WithField("poolid", p.ID)
+
+		conn, err := getLocalConnection(ctx, zzk.GeneratePoolPath(p.ID))
+		if err != nil {
+			logger.WithError(err).Debug("Could not acquire pool-based connection")
+			return nil, err
+		}
+
+		hosts := view.Hosts[p.ID]
+		for _, h := range hosts {
+			hostIPs = append(hostIPs, h.IPAddr)
+			if h.NatIP != "" {
+				hostIPs = append(hostIPs, h.NatIP)
+			}
+			for _, ip := range h.IPs {
+				hostIPs = append(hostIPs, ip.IPAddress)
+			}
+		}
+
+		result, err := zks.CheckHosts(conn, p.ID, hosts, repair)
+		if err != nil {
+			logger.WithError(err).Debug("Could not check hosts in the coordinator")
+			return nil, err
+		}
+		found = append(found, result...)
+
+		result, err = zks.CheckServices(conn, p.ID, view.Services[p.ID], repair)
+		if err != nil {
+			logger.WithError(err).Debug("Could not check services in the coordinator")
+			return nil, err
+		}
+		found = append(found, result...)
+
+		result, err = zks.CheckStates(conn, p.ID, repair)
+		if err != nil {
+			logger.WithError(err).Debug("Could not check instance states in the coordinator")
+			return nil, err
+		}
+		found = append(found, result...)
+
+		result, err = zks.CheckLocks(conn, p.ID, repair)
+		if err != nil {
+			logger.WithError(err).Debug("Could not check locks in the coordinator")
+			return nil, err
+		}
+		found = append(found, result...)
+	}
+
+	result, err := zkr.CheckExports(rootconn, view.TenantIDs, hostIPs, repair)
+	if err != nil {
+		plog.WithError(err).Debug("Could not check exports in the coordinator")
+		return nil, err
+	}
+	return append(found, result...), nil
+}
+
 func (z *zkf) GetRegistryImage(id string) (*registry.Image, error) {
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/zzk"
 	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
@@ -58,5 +59,12 @@ type ZZK interface {
 	RegisterDfsClients(clients ...host.Host) error
 	UnregisterDfsClients(clients ...host.Host) error
 	GetVirtualIPHostID(poolID, ip string) (string, error)
+	ReassignVirtualIPs(_pool *pool.ResourcePool, hostID string) ([]string, error)
+	GetVirtualIPStatuses(poolID string) ([]zkservice.VirtualIPStatus, error)
+	SetRollingRestartStatus(status service.RollingRestartStatus) error
+	GetRollingRestartStatuses(serviceID string) ([]service.RollingRestartStatus, error)
+	RemoveRollingRestartStatus(serviceID string) error
+	GetTree(pth string) (*zzk.TreeNode, error)
+	CheckState(ctx datastore.Context, view zkservice.FacadeView, repair bool) ([]zkservice.Inconsistency, error)
 	UpdateInstanceCurrentState(ctx datastore.Context, poolID, serviceID string, instanceID int, state service.InstanceCurrentState) error
 }
@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"errors"
 	"os/exec"
+	"syscall"
 	"time"
 )
 
@@ -102,6 +103,11 @@ type HealthCheck struct {
 	Timeout   time.Duration
 	Interval  time.Duration
 	Tolerance int
+
+	// SysProcAttr holds the attributes of the script's process, such as the
+	// user that it runs as.  It is set inside the container and is not
+	// serialized.
+	SysProcAttr *syscall.SysProcAttr
 }
 
 // MarshalJSON implements json.Marshaller
@@ -181,6 +187,7 @@ func (hc *HealthCheck) Unknown() HealthStatus {
 func (hc *HealthCheck) Run() (stat HealthStatus) {
 	stat.StartedAt = time.Now()
 	cmd := exec.Command("sh", "-c", hc.Script)
+	cmd.SysProcAttr = hc.SysProcAttr
 	cmd.Start()
 	timer := time.NewTimer(hc.GetTimeout())
 	errC := make(chan error)
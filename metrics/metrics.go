@@ -7,14 +7,24 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/metrics/prometheus"
 	gometrics "github.com/rcrowley/go-metrics"
 	"github.com/zenoss/logri"
 )
 
 var (
 	log = logging.PackageLogger()
+
+	// operationDuration records every timer, whether or not the Metrics
+	// are enabled, so the timings can be scraped
+	operationDuration = prometheus.NewHistogramVec("serviced_operation_duration_seconds",
+		"Time taken by timed serviced operations, such as facade calls", prometheus.DefBuckets, "operation")
 )
 
+func init() {
+	prometheus.Register(operationDuration)
+}
+
 /*
  * To record metrics, enable metrics for a function with ctx.Metrics().Enabled = true
  * and: defer ctx.Metrics().Stop(ctx.Metrics().Start("FunctionName")) where
@@ -48,7 +58,7 @@ type MetricTimer struct {
 // argument to Stop() to record the duration/count.
 func (m *Metrics) Start(name string) *MetricTimer {
 	if !m.Enabled {
-		return nil
+		return &MetricTimer{Name: name, Time: time.Now()}
 	}
 	m.Lock()
 	defer m.Unlock()
@@ -65,7 +75,10 @@ func (m *Metrics) Start(name string) *MetricTimer {
 // When stop is called, calculate the duration.
 func (m *Metrics) Stop(timer *MetricTimer) {
 	if timer != nil {
-		timer.Timer.UpdateSince(timer.Time)
+		if timer.Timer != nil {
+			timer.Timer.UpdateSince(timer.Time)
+		}
+		operationDuration.Observe(time.Since(timer.Time).Seconds(), timer.Name)
 	}
 }
 
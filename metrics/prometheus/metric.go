@@ -0,0 +1,218 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefBuckets are the default histogram buckets, in seconds, for timing
+// operations that take from a few milliseconds to several seconds
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type desc struct {
+	name       string
+	help       string
+	labelNames []string
+}
+
+func (d desc) Name() string { return d.name }
+func (d desc) Help() string { return d.help }
+
+// labels pairs the label names with the values of a child metric
+func (d desc) labels(values []string) Labels {
+	labels := make(Labels, len(d.labelNames))
+	for i, name := range d.labelNames {
+		if i < len(values) {
+			labels[name] = values[i]
+		} else {
+			labels[name] = ""
+		}
+	}
+	return labels
+}
+
+// key identifies a child metric by its label values
+func key(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+// sortedKeys returns the keys of a map of children in a stable order
+func sortedKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GaugeVec is a family of gauges, one per distinct set of label values
+type GaugeVec struct {
+	desc
+	typ    string
+	mu     sync.Mutex
+	values map[string][]string
+	gauges map[string]float64
+}
+
+// NewGaugeVec returns a gauge family partitioned by the given label names
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{
+		desc:   desc{name: name, help: help, labelNames: labelNames},
+		typ:    GaugeType,
+		values: make(map[string][]string),
+		gauges: make(map[string]float64),
+	}
+}
+
+// NewCounterVec returns a counter family partitioned by the given label
+// names.  Counters should only be increased with Add.
+func NewCounterVec(name, help string, labelNames ...string) *GaugeVec {
+	g := NewGaugeVec(name, help, labelNames...)
+	g.typ = CounterType
+	return g
+}
+
+// Type implements Metric
+func (g *GaugeVec) Type() string { return g.typ }
+
+// Set sets the gauge with the given label values
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	k := key(labelValues)
+	g.values[k] = labelValues
+	g.gauges[k] = value
+}
+
+// Add adds delta, which may be negative for gauges, to the gauge with the
+// given label values
+func (g *GaugeVec) Add(delta float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	k := key(labelValues)
+	g.values[k] = labelValues
+	g.gauges[k] += delta
+}
+
+// Collect implements Metric
+func (g *GaugeVec) Collect() []Sample {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	samples := make([]Sample, 0, len(g.gauges))
+	for _, k := range sortedKeys(g.values) {
+		samples = append(samples, Sample{Labels: g.labels(g.values[k]), Value: g.gauges[k]})
+	}
+	return samples
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec is a family of histograms, one per distinct set of label
+// values
+type HistogramVec struct {
+	desc
+	buckets    []float64
+	mu         sync.Mutex
+	values     map[string][]string
+	histograms map[string]*histogram
+}
+
+// NewHistogramVec returns a histogram family with the given upper bucket
+// bounds, partitioned by the given label names
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	bounds := make([]float64, len(buckets))
+	copy(bounds, buckets)
+	sort.Float64s(bounds)
+	return &HistogramVec{
+		desc:       desc{name: name, help: help, labelNames: labelNames},
+		buckets:    bounds,
+		values:     make(map[string][]string),
+		histograms: make(map[string]*histogram),
+	}
+}
+
+// Type implements Metric
+func (h *HistogramVec) Type() string { return HistogramType }
+
+// Observe records a value in the histogram with the given label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	k := key(labelValues)
+	hist, ok := h.histograms[k]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.histograms[k] = hist
+		h.values[k] = labelValues
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += value
+}
+
+// Collect implements Metric
+func (h *HistogramVec) Collect() []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var samples []Sample
+	for _, k := range sortedKeys(h.values) {
+		hist := h.histograms[k]
+		for i, bound := range h.buckets {
+			labels := h.labels(h.values[k])
+			labels["le"] = formatValue(bound)
+			samples = append(samples, Sample{Suffix: "_bucket", Labels: labels, Value: float64(hist.counts[i])})
+		}
+		labels := h.labels(h.values[k])
+		labels["le"] = formatValue(math.Inf(1))
+		samples = append(samples,
+			Sample{Suffix: "_bucket", Labels: labels, Value: float64(hist.count)},
+			Sample{Suffix: "_sum", Labels: h.labels(h.values[k]), Value: hist.sum},
+			Sample{Suffix: "_count", Labels: h.labels(h.values[k]), Value: float64(hist.count)},
+		)
+	}
+	return samples
+}
+
+// GaugeFunc is a gauge family whose samples are computed when the metrics
+// are scraped
+type GaugeFunc struct {
+	desc
+	collect func() []Sample
+}
+
+// NewGaugeFunc returns a gauge family that calls collect on every scrape
+func NewGaugeFunc(name, help string, collect func() []Sample) *GaugeFunc {
+	return &GaugeFunc{desc: desc{name: name, help: help}, collect: collect}
+}
+
+// Type implements Metric
+func (g *GaugeFunc) Type() string { return GaugeType }
+
+// Collect implements Metric
+func (g *GaugeFunc) Collect() []Sample {
+	return g.collect()
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package prometheus
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func write(t *testing.T, r *Registry) string {
+	var buf bytes.Buffer
+	if err := r.WriteTo(bufio.NewWriter(&buf)); err != nil {
+		t.Fatalf("WriteTo failed: %s", err)
+	}
+	return buf.String()
+}
+
+func TestGaugeVec(t *testing.T) {
+	r := NewRegistry()
+	g := NewGaugeVec("test_connections", "Connections by state", "state")
+	r.Register(g)
+	g.Add(1, "connected")
+	g.Add(1, "connected")
+	g.Add(1, "disconnected")
+	g.Add(-1, "disconnected")
+	g.Set(3, `odd "state"`)
+
+	expected := `# HELP test_connections Connections by state
+# TYPE test_connections gauge
+test_connections{state="connected"} 2
+test_connections{state="disconnected"} 0
+test_connections{state="odd \"state\""} 3
+`
+	if actual := write(t, r); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestHistogramVec(t *testing.T) {
+	r := NewRegistry()
+	h := NewHistogramVec("test_duration_seconds", "Call durations", []float64{1, 0.1}, "method", "result")
+	r.Register(h)
+	h.Observe(0.05, "Get", "ok")
+	h.Observe(0.5, "Get", "ok")
+	h.Observe(2, "Get", "ok")
+
+	expected := `# HELP test_duration_seconds Call durations
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="0.1",method="Get",result="ok"} 1
+test_duration_seconds_bucket{le="1",method="Get",result="ok"} 2
+test_duration_seconds_bucket{le="+Inf",method="Get",result="ok"} 3
+test_duration_seconds_sum{method="Get",result="ok"} 2.55
+test_duration_seconds_count{method="Get",result="ok"} 3
+`
+	if actual := write(t, r); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestRegistryOrderAndEmptyFamilies(t *testing.T) {
+	r := NewRegistry()
+	r.Register(NewGaugeFunc("test_b", "B", func() []Sample {
+		return []Sample{{Value: 2}}
+	}))
+	r.Register(NewGaugeFunc("test_a", "A\nmultiline", func() []Sample {
+		return []Sample{{Labels: Labels{"pool": "default"}, Value: 1}}
+	}))
+	r.Register(NewCounterVec("test_empty", "Never incremented"))
+
+	expected := `# HELP test_a A\nmultiline
+# TYPE test_a gauge
+test_a{pool="default"} 1
+# HELP test_b B
+# TYPE test_b gauge
+test_b 2
+`
+	if actual := write(t, r); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	r.Unregister("test_a")
+	r.Unregister("test_b")
+	if actual := write(t, r); actual != "" {
+		t.Errorf("expected no output, got:\n%s", actual)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	c := NewCounterVec("test_total", "Things counted")
+	r.Register(c)
+	c.Add(5)
+
+	w := httptest.NewRecorder()
+	Handler(r).ServeHTTP(w, &http.Request{Method: "GET"})
+	if ct := w.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("expected content type %q, got %q", ContentType, ct)
+	}
+	expected := "# HELP test_total Things counted\n# TYPE test_total counter\ntest_total 5\n"
+	if actual := w.Body.String(); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	if actual := SanitizeName("storage.pool.data-available"); actual != "storage_pool_data_available" {
+		t.Errorf("unexpected name %q", actual)
+	}
+}
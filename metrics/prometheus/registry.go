@@ -0,0 +1,192 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prometheus exposes serviced's own metrics in the Prometheus text
+// exposition format, so they can be scraped rather than pushed.
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/control-center/serviced/logging"
+)
+
+var plog = logging.PackageLogger()
+
+// ContentType is the media type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metric types
+const (
+	CounterType   = "counter"
+	GaugeType     = "gauge"
+	HistogramType = "histogram"
+)
+
+// Sample is a single value of a metric family.  Suffix is appended to the
+// family name, e.g. "_bucket" for histogram buckets.
+type Sample struct {
+	Suffix string
+	Labels Labels
+	Value  float64
+}
+
+// Labels are the name/value pairs that distinguish samples of a family
+type Labels map[string]string
+
+// Metric is a family of samples that share a name, help text and type
+type Metric interface {
+	Name() string
+	Help() string
+	Type() string
+	Collect() []Sample
+}
+
+// Registry is a set of metrics that are written together
+type Registry struct {
+	mu      sync.RWMutex
+	metrics map[string]Metric
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]Metric)}
+}
+
+// DefaultRegistry holds the metrics of this serviced process
+var DefaultRegistry = NewRegistry()
+
+// Register adds a metric to the registry.  A metric with the same name
+// replaces the one already registered.
+func (r *Registry) Register(m Metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics[m.Name()] = m
+}
+
+// Unregister removes a metric by name
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.metrics, name)
+}
+
+// Register adds a metric to the default registry
+func Register(m Metric) {
+	DefaultRegistry.Register(m)
+}
+
+// WriteTo writes every registered metric, in name order, to w
+func (r *Registry) WriteTo(w *bufio.Writer) error {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	metrics := make([]Metric, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		metrics[i] = r.metrics[name]
+	}
+	r.mu.RUnlock()
+
+	for _, m := range metrics {
+		samples := m.Collect()
+		if len(samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", m.Name(), escapeHelp(m.Help()))
+		fmt.Fprintf(w, "# TYPE %s %s\n", m.Name(), m.Type())
+		for _, s := range samples {
+			w.WriteString(m.Name())
+			w.WriteString(s.Suffix)
+			writeLabels(w, s.Labels)
+			w.WriteByte(' ')
+			w.WriteString(formatValue(s.Value))
+			w.WriteByte('\n')
+		}
+	}
+	return w.Flush()
+}
+
+// Handler serves the registry's metrics
+func Handler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		if err := r.WriteTo(bufio.NewWriter(w)); err != nil {
+			plog.WithError(err).Debug("Could not write metrics")
+		}
+	})
+}
+
+func writeLabels(w *bufio.Writer, labels Labels) {
+	if len(labels) == 0 {
+		return
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		fmt.Fprintf(w, "%s=\"%s\"", name, escapeLabel(labels[name]))
+	}
+	w.WriteByte('}')
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// SanitizeName replaces the characters that may not appear in a metric name,
+// such as the dots of OpenTSDB metric names, with underscores
+func SanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == ':' {
+			return r
+		}
+		return '_'
+	}, name)
+}
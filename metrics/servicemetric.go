@@ -0,0 +1,87 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// ServiceMetricStats is a metric averaged over a window of time for each
+// instance of a service that reported it.
+type ServiceMetricStats struct {
+	ServiceID string
+	Metric    string
+	Instances int     // Number of instances that reported the metric
+	Average   float64 // Average value of the metric per reporting instance
+}
+
+// convertV2ServiceMetric averages the datapoints of each instance of the
+// service and then averages across instances.
+func convertV2ServiceMetric(serviceID, metric string, perfData *V2PerformanceData) *ServiceMetricStats {
+	stats := &ServiceMetricStats{ServiceID: serviceID, Metric: metric}
+	var sum float64
+	for _, result := range perfData.Series {
+		if result.Tags["controlplane_service_id"] != serviceID || len(result.Datapoints) < 1 {
+			continue
+		}
+		var total float64
+		for _, dp := range result.Datapoints {
+			total += dp.Value()
+		}
+		sum += total / float64(len(result.Datapoints))
+		stats.Instances++
+	}
+	if stats.Instances > 0 {
+		stats.Average = sum / float64(stats.Instances)
+	}
+	return stats
+}
+
+// GetServiceMetric returns the average value of a metric per instance of a
+// service over the window.  If rate is set, the metric is a counter and its
+// rate per second is averaged instead.
+func (c *Client) GetServiceMetric(window time.Duration, serviceID, metric string, rate bool) (*ServiceMetricStats, error) {
+	logger := log.WithFields(logrus.Fields{
+		"serviceid": serviceID,
+		"metric":    metric,
+	})
+	logger.Debug("Requesting metric for service")
+	secs := int(window.Seconds())
+	options := V2PerformanceOptions{
+		Start:     fmt.Sprintf("%ds-ago", secs),
+		End:       "now",
+		Returnset: "exact",
+		Metrics: []V2MetricOptions{
+			{
+				Metric:      metric,
+				Rate:        rate,
+				RateOptions: V2RateOptions{Counter: rate},
+				Downsample:  fmt.Sprintf("%ds-avg", secs),
+				Tags: map[string][]string{
+					"controlplane_service_id":  []string{serviceID},
+					"controlplane_instance_id": []string{"*"},
+				},
+			},
+		},
+	}
+
+	result, err := c.v2performanceQuery(options)
+	if err != nil {
+		return nil, err
+	}
+	return convertV2ServiceMetric(serviceID, metric, result), nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package metrics
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConvertV2ServiceMetric(t *testing.T) {
+	testData := []byte(`
+	{ "series" : [ { "datapoints" : [ [ 1453766400, 100 ], [ 1453766460, 300 ] ], "metric" : "queue.depth", "tags" : { "controlplane_instance_id" : "0", "controlplane_service_id" : "svc1" } }, { "datapoints" : [ [ 1453766400, 600 ] ], "metric" : "queue.depth", "tags" : { "controlplane_instance_id" : "1", "controlplane_service_id" : "svc1" } }, { "datapoints" : [ ], "metric" : "queue.depth", "tags" : { "controlplane_instance_id" : "2", "controlplane_service_id" : "svc1" } }, { "datapoints" : [ [ 1453766400, 5000 ] ], "metric" : "queue.depth", "tags" : { "controlplane_instance_id" : "0", "controlplane_service_id" : "svc2" } } ], "statuses" : [ { "message" : "", "status" : "SUCCESS" } ] }
+	`)
+
+	var perfdata V2PerformanceData
+	if err := json.Unmarshal(testData, &perfdata); err != nil {
+		t.Fatalf("Could not unmarshal testData: %s", err)
+	}
+
+	actual := convertV2ServiceMetric("svc1", "queue.depth", &perfdata)
+	expected := ServiceMetricStats{ServiceID: "svc1", Metric: "queue.depth", Instances: 2, Average: 400}
+	if *actual != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *actual)
+	}
+
+	actual = convertV2ServiceMetric("svc3", "queue.depth", &perfdata)
+	if actual.Instances != 0 || actual.Average != 0 {
+		t.Errorf("Expected no data, got %+v", *actual)
+	}
+}
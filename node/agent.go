@@ -35,6 +35,7 @@ import (
 	"github.com/control-center/serviced/commons/docker"
 	"github.com/control-center/serviced/commons/iptables"
 	coordclient "github.com/control-center/serviced/coordinator/client"
+	coordetcd "github.com/control-center/serviced/coordinator/client/etcd"
 	coordzk "github.com/control-center/serviced/coordinator/client/zookeeper"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/addressassignment"
@@ -80,6 +81,7 @@ type HostAgent struct {
 	useTLS               bool   // true if TLS should be enabled for MUX
 	proxyRegistry        proxy.ProxyRegistry
 	zkClient             *coordclient.Client
+	zkDriver             string          // name of the coordinator driver used by zkClient
 	maxContainerAge      time.Duration   // maximum age for a stopped container before it is removed
 	virtualAddressSubnet string          // subnet for virtual addresses
 	servicedChain        *iptables.Chain // Assigned IP rule chain
@@ -87,6 +89,7 @@ type HostAgent struct {
 	logstashURL          string
 	dockerLogDriver      string
 	dockerLogConfig      map[string]string
+	seccompProfileDir    string // directory of seccomp profiles that services may name
 	pullreg              registry.Registry
 	zkSessionTimeout     int
 	delegateKeyFile      string
@@ -138,11 +141,14 @@ type AgentOptions struct {
 	LogstashURL          string
 	DockerLogDriver      string
 	DockerLogConfig      map[string]string
+	SeccompProfileDir    string
 	ZKSessionTimeout     int
 	ZKConnectTimeout     int
 	ZKPerHostConnectDelay int
 	ZKReconnectStartDelay int
 	ZKReconnectMaxDelay  int
+	CoordinatorDriver    string
+	EtcdEndpoints        []string
 	DelegateKeyFile      string
 	TokenFile            string
 	ConntrackFlush       bool
@@ -170,6 +176,7 @@ func NewHostAgent(options AgentOptions, reg registry.Registry) (*HostAgent, erro
 	agent.logstashURL = options.LogstashURL
 	agent.dockerLogDriver = options.DockerLogDriver
 	agent.dockerLogConfig = options.DockerLogConfig
+	agent.seccompProfileDir = options.SeccompProfileDir
 	agent.zkSessionTimeout = options.ZKSessionTimeout
 	agent.delegateKeyFile = options.DelegateKeyFile
 	agent.tokenFile = options.TokenFile
@@ -177,13 +184,24 @@ func NewHostAgent(options AgentOptions, reg registry.Registry) (*HostAgent, erro
 	agent.serviceCache = NewServiceCache(options.Master)
 
 	var err error
-	dsn := getZkDSN(options.Zookeepers,
-		agent.zkSessionTimeout,
-		options.ZKConnectTimeout,
-		options.ZKPerHostConnectDelay,
-		options.ZKReconnectStartDelay,
-		options.ZKReconnectMaxDelay)
-	if agent.zkClient, err = coordclient.New("zookeeper", dsn, "", nil); err != nil {
+	var dsn string
+	switch options.CoordinatorDriver {
+	case "etcd":
+		agent.zkDriver = "etcd"
+		dsn = coordetcd.NewDSN(options.EtcdEndpoints, time.Duration(agent.zkSessionTimeout)*time.Second).String()
+	case "memory":
+		// service containers cannot reach a store inside this process
+		return nil, fmt.Errorf("the memory coordinator driver cannot be used by an agent")
+	default:
+		agent.zkDriver = "zookeeper"
+		dsn = getZkDSN(options.Zookeepers,
+			agent.zkSessionTimeout,
+			options.ZKConnectTimeout,
+			options.ZKPerHostConnectDelay,
+			options.ZKReconnectStartDelay,
+			options.ZKReconnectMaxDelay)
+	}
+	if agent.zkClient, err = coordclient.New(agent.zkDriver, dsn, "", nil); err != nil {
 		return nil, err
 	}
 	if agent.storage, err = volume.GetDriver(options.VolumesPath); err != nil {
@@ -47,6 +47,7 @@ type ServiceLogInfo struct {
 type ZkInfo struct {
 	ZkDSN  string
 	PoolID string
+	Driver string // the coordinator driver, zookeeper if empty
 }
 
 func (a *HostAgent) SendLogMessage(serviceLogInfo ServiceLogInfo, _ *struct{}) (err error) {
@@ -236,6 +237,7 @@ func (a *HostAgent) GetZkInfo(_ string, zkInfo *ZkInfo) error {
 	localDSN := a.zkClient.ConnectionString()
 	zkInfo.ZkDSN = strings.Replace(localDSN, "127.0.0.1", strings.Split(a.master, ":")[0], -1)
 	zkInfo.PoolID = a.poolID
+	zkInfo.Driver = a.zkDriver
 	glog.V(4).Infof("ControlCenterAgent.GetZkInfo(): %+v", zkInfo)
 	return nil
 }
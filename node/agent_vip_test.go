@@ -181,3 +181,28 @@ func (t *HostAgentTestSuite) TestReleaseIP_ReleaseSuccess(c *C) {
 	err := t.a.ReleaseIP(ipprefix)
 	c.Assert(err, IsNil)
 }
+
+func (t *HostAgentTestSuite) TestCheckIP_NotBound(c *C) {
+	t.m.On("Find", "1.2.3.4").Return(nil).Once()
+	err := t.a.CheckIP("1.2.3.4", "lo")
+	c.Assert(err, NotNil)
+
+	vip := &IP{Addr: "1.2.3.4/24", Device: "dum0", Label: "dum0:cc12"}
+	t.m.On("Find", "1.2.3.4").Return(vip).Once()
+	err = t.a.CheckIP("1.2.3.4", "lo")
+	c.Assert(err, NotNil)
+}
+
+func (t *HostAgentTestSuite) TestCheckIP_NoInterface(c *C) {
+	vip := &IP{Addr: "1.2.3.4/24", Device: "dummy1234", Label: "dummy1234:cc12"}
+	t.m.On("Find", "1.2.3.4").Return(vip).Once()
+	err := t.a.CheckIP("1.2.3.4", "dummy1234")
+	c.Assert(err, NotNil)
+}
+
+func (t *HostAgentTestSuite) TestCheckIP_Success(c *C) {
+	vip := &IP{Addr: "1.2.3.4/8", Device: "lo", Label: "lo:cc12"}
+	t.m.On("Find", "1.2.3.4").Return(vip).Once()
+	err := t.a.CheckIP("1.2.3.4", "lo")
+	c.Assert(err, IsNil)
+}
@@ -14,7 +14,10 @@
 package node
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"os/exec"
@@ -29,6 +32,7 @@ import (
 	"github.com/control-center/serviced/commons/iptables"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/servicedversion"
 	"github.com/control-center/serviced/utils"
@@ -37,6 +41,8 @@ import (
 	dockerclient "github.com/fsouza/go-dockerclient"
 )
 
+// cfsPeriod is the CFS scheduler period, in microseconds, for CPU quotas
+const cfsPeriod = 100000
 
 func (a *HostAgent) setInstanceState(serviceID string, instanceID int, state service.InstanceCurrentState) error {
 	logger := plog.WithFields(log.Fields{
@@ -195,8 +201,8 @@ func (a *HostAgent) StartContainer(cancel <-chan interface{}, serviceID string,
 	return state, ev, nil
 }
 
-// RestartContainer asynchronously pulls the latest image of a running
-// container before stopping the service.  After the service has stopped, the
+// RestartContainer asynchronously pulls the current image of the service
+// before stopping its running container.  After the service has stopped, the
 // listener will be notified by the event monitor.
 func (a *HostAgent) RestartContainer(cancel <-chan interface{}, serviceID string, instanceID int) error {
 	logger := plog.WithFields(log.Fields{
@@ -224,11 +230,22 @@ func (a *HostAgent) RestartContainer(cancel <-chan interface{}, serviceID string
 		return nil
 	}
 
+	// pull the image the service is set to, which differs from the image of
+	// the running container when the service image is being changed.
+	imageID := ctr.Config.Image
+	a.serviceCache.Invalidate(serviceID, instanceID)
+	if svc, _, _, err := a.serviceCache.GetEvaluatedService(serviceID, instanceID); err != nil {
+		logger.WithError(err).Warn("Could not look up the service image, pulling the image of the running container")
+	} else {
+		imageID = svc.ImageID
+	}
+	logger = logger.WithField("imageid", imageID)
+
 	go func() {
 		a.setInstanceState(serviceID, instanceID, service.StatePulling)
 		for {
 			// relentlessly try to pull the image
-			_, _, err := a.pullImage(logger, cancel, ctr.Config.Image)
+			_, _, err := a.pullImage(logger, cancel, imageID)
 			if err != nil {
 				logger.WithError(err).Debug("Could not pull the service image")
 				// wait 5 seconds and try again
@@ -559,7 +576,7 @@ func (a *HostAgent) setupContainer(tenantID string, svc *service.Service, instan
 	return ctr, state, nil
 }
 
-func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service, instanceID int, imageUUID string) (*dockerclient.Config, *dockerclient.HostConfig, *zkservice.ServiceState, error) {
+func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service, instanceID int, imageUUID string) (*dockerclient.Config, *docker.HostConfig, *zkservice.ServiceState, error) {
 	logger := plog.WithFields(log.Fields{
 		"tenantid":    tenantID,
 		"servicename": svc.Name,
@@ -567,7 +584,7 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 		"instanceid":  instanceID,
 	})
 	cfg := &dockerclient.Config{}
-	hcfg := &dockerclient.HostConfig{}
+	hcfg := &docker.HostConfig{}
 
 	cfg.User = "root"
 	cfg.WorkingDir = "/"
@@ -575,9 +592,10 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 
 	// get the endpoints
 	state := &zkservice.ServiceState{
-		ImageUUID: imageUUID,
-		Paused:    false,
-		HostIP:    a.ipaddress,
+		ImageUUID:       imageUUID,
+		Paused:          false,
+		HostIP:          a.ipaddress,
+		ConfigChecksums: svc.ConfigChecksums,
 	}
 
 	var assignedIP string
@@ -780,6 +798,11 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 	if a.rpcDisableTLS {
 		cmd = append(cmd, "--rpc-disable-tls")
 	}
+	// The controller writes the logforwarder config into the container, so
+	// keep it on a writable volume if the root filesystem is read-only.
+	if svc.Security.ReadOnlyRootFS {
+		cmd = append(cmd, "--forwarder-config", "/tmp/filebeat.conf")
+	}
 	cfg.Cmd = append(cmd,
 		svc.ID,
 		strconv.Itoa(instanceID),
@@ -804,6 +827,13 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 		cfg.CPUShares = svc.CPUShares
 	}
 
+	// Enforce a hard CPU limit through the CFS quota and pin to the cpuset
+	if svc.CPUQuota > 0 {
+		hcfg.CPUPeriod = cfsPeriod
+		hcfg.CPUQuota = int64(svc.CPUQuota * cfsPeriod)
+	}
+	hcfg.CPUSetCPUs = svc.CPUSet
+
 	hcfg.LogConfig.Type = a.dockerLogDriver
 	hcfg.LogConfig.Config = a.dockerLogConfig
 
@@ -815,10 +845,65 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 			Hard: 0,
 		},
 	}
+
+	if err := a.setSecurityProfile(hcfg, svc.Security); err != nil {
+		logger.WithError(err).Error("Could not apply the service's security profile")
+		return nil, nil, nil, err
+	}
 	return cfg, hcfg, state, nil
 }
 
-func (a *HostAgent) createContainer(conf *dockerclient.Config, hostConf *dockerclient.HostConfig, svcID string, instanceID int) (*docker.Container, error) {
+// setSecurityProfile restricts the container's privileges as requested by the
+// service.  The run-as user is applied by the controller to the service
+// command, since the controller itself needs to run as root.
+func (a *HostAgent) setSecurityProfile(hcfg *docker.HostConfig, sec servicedefinition.SecurityProfile) error {
+	hcfg.CapAdd = sec.CapAdd
+	hcfg.CapDrop = sec.CapDrop
+	hcfg.ReadonlyRootfs = sec.ReadOnlyRootFS
+	hcfg.Tmpfs = sec.Tmpfs
+	hcfg.PidsLimit = sec.PidsLimit
+
+	if sec.NoNewPrivileges {
+		hcfg.SecurityOpt = append(hcfg.SecurityOpt, "no-new-privileges")
+	}
+
+	// docker expects the content of the seccomp profile, not its path
+	switch sec.SeccompProfile {
+	case "":
+	case "unconfined":
+		hcfg.SecurityOpt = append(hcfg.SecurityOpt, "seccomp=unconfined")
+	default:
+		filename := filepath.Join(a.seccompProfileDir, sec.SeccompProfile+".json")
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("could not read seccomp profile %s: %s", sec.SeccompProfile, err)
+		}
+		var profile bytes.Buffer
+		if err := json.Compact(&profile, data); err != nil {
+			return fmt.Errorf("could not parse seccomp profile %s: %s", sec.SeccompProfile, err)
+		}
+		hcfg.SecurityOpt = append(hcfg.SecurityOpt, "seccomp="+profile.String())
+	}
+
+	// service ulimits override the defaults
+	for _, ulimit := range sec.Ulimits {
+		limit := dockerclient.ULimit{Name: ulimit.Name, Soft: ulimit.Soft, Hard: ulimit.Hard}
+		found := false
+		for i := range hcfg.Ulimits {
+			if hcfg.Ulimits[i].Name == ulimit.Name {
+				hcfg.Ulimits[i] = limit
+				found = true
+				break
+			}
+		}
+		if !found {
+			hcfg.Ulimits = append(hcfg.Ulimits, limit)
+		}
+	}
+	return nil
+}
+
+func (a *HostAgent) createContainer(conf *dockerclient.Config, hostConf *docker.HostConfig, svcID string, instanceID int) (*docker.Container, error) {
 	logger := plog.WithFields(log.Fields{
 		"serviceid":  svcID,
 		"instanceid": instanceID,
@@ -828,10 +913,10 @@ func (a *HostAgent) createContainer(conf *dockerclient.Config, hostConf *dockerc
 	opts := dockerclient.CreateContainerOptions{
 		Name:       fmt.Sprintf("%s-%d", svcID, instanceID),
 		Config:     conf,
-		HostConfig: hostConf,
+		HostConfig: &hostConf.HostConfig,
 	}
 
-	ctr, err := docker.NewContainer(&opts, false, 10*time.Second, nil, nil)
+	ctr, err := docker.NewContainerWithHostConfig(&opts, hostConf, false, 10*time.Second, nil, nil)
 	if err != nil {
 		logger.WithError(err).Error("Could not create container")
 		return nil, err
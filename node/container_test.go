@@ -16,12 +16,17 @@
 package node
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	regmocks "github.com/control-center/serviced/dfs/registry/mocks"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	dockerclient "github.com/fsouza/go-dockerclient"
 )
 
 func TestSetupContainer_DockerLog(t *testing.T) {
@@ -61,3 +66,90 @@ func TestSetupContainer_DockerLog(t *testing.T) {
 	assert.Equal(hcfg.LogConfig.Config["bravo"], "two")
 	assert.Equal(hcfg.LogConfig.Config["charlie"], "three")
 }
+
+func TestSetupContainer_SecurityProfile(t *testing.T) {
+	assert := assert.New(t)
+
+	seccompDir, err := ioutil.TempDir("", "serviced-seccomp-")
+	assert.Nil(err)
+	defer os.RemoveAll(seccompDir)
+	err = ioutil.WriteFile(filepath.Join(seccompDir, "strict.json"), []byte("{\n  \"defaultAction\": \"SCMP_ACT_ERRNO\"\n}\n"), 0644)
+	assert.Nil(err)
+
+	fakeHostAgent := &HostAgent{
+		uiport:               ":443",
+		virtualAddressSubnet: "0.0.0.0",
+		pullreg:              &regmocks.Registry{},
+		seccompProfileDir:    seccompDir,
+	}
+	fakeService := &service.Service{
+		ImageID: "busybox:latest",
+		ID:      "faketestService",
+		Name:    "fakeTestServiceName",
+		Security: servicedefinition.SecurityProfile{
+			CapAdd:          []string{"NET_ADMIN"},
+			CapDrop:         []string{"ALL"},
+			ReadOnlyRootFS:  true,
+			Tmpfs:           map[string]string{"/run": "size=64m"},
+			User:            "1000",
+			NoNewPrivileges: true,
+			PidsLimit:       100,
+			Ulimits: []servicedefinition.Ulimit{
+				{Name: "core", Soft: 1024, Hard: 1024},
+				{Name: "nofile", Soft: 512, Hard: 2048},
+			},
+			SeccompProfile: "strict",
+		},
+	}
+
+	cfg, hcfg, _, err := fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+
+	// the controller still runs as root and writes its config to /tmp
+	assert.Equal("root", cfg.User)
+	assert.Contains(cfg.Cmd, "--forwarder-config")
+	assert.Equal([]string{"NET_ADMIN"}, hcfg.CapAdd)
+	assert.Equal([]string{"ALL"}, hcfg.CapDrop)
+	assert.True(hcfg.ReadonlyRootfs)
+	assert.Equal(map[string]string{"/run": "size=64m"}, hcfg.Tmpfs)
+	assert.Equal(int64(100), hcfg.PidsLimit)
+	assert.Equal([]string{"no-new-privileges", `seccomp={"defaultAction":"SCMP_ACT_ERRNO"}`}, hcfg.SecurityOpt)
+	assert.Equal([]dockerclient.ULimit{
+		{Name: "core", Soft: 1024, Hard: 1024},
+		{Name: "nofile", Soft: 512, Hard: 2048},
+	}, hcfg.Ulimits)
+
+	// a missing profile is an error
+	fakeService.Security.SeccompProfile = "missing"
+	_, _, _, err = fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.NotNil(err)
+
+	// unconfined does not need a profile
+	fakeService.Security.SeccompProfile = "unconfined"
+	_, hcfg, _, err = fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.Contains(hcfg.SecurityOpt, "seccomp=unconfined")
+}
+
+func TestSetupContainer_CPULimits(t *testing.T) {
+	assert := assert.New(t)
+
+	fakeHostAgent := &HostAgent{
+		uiport:               ":443",
+		virtualAddressSubnet: "0.0.0.0",
+		pullreg:              &regmocks.Registry{},
+	}
+	fakeService := &service.Service{
+		ImageID:  "busybox:latest",
+		ID:       "faketestService",
+		Name:     "fakeTestServiceName",
+		CPUQuota: 1.5,
+		CPUSet:   "0-1",
+	}
+
+	_, hcfg, _, err := fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.Equal(int64(100000), hcfg.CPUPeriod)
+	assert.Equal(int64(150000), hcfg.CPUQuota)
+	assert.Equal("0-1", hcfg.CPUSetCPUs)
+}
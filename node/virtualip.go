@@ -16,6 +16,7 @@ package node
 import (
 	"bufio"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os/exec"
 	"regexp"
@@ -132,6 +133,17 @@ func (v *VirtualIPManager) Bind(ipaddr, device string) error {
 	}
 
 	logger.Debug("Added virtual ip")
+
+	// announce the new owner of the address so that neighbors update their
+	// arp caches right away
+	addr := strings.SplitN(ipaddr, "/", 2)[0]
+	cmd = exec.Command("arping", "-U", "-c", "3", "-I", device, addr)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"command": cmd,
+			"output":  string(output),
+		}).Warn("Could not send gratuitous arp for virtual ip")
+	}
 	return nil
 }
 
@@ -222,3 +234,28 @@ func (a *HostAgent) ReleaseIP(ipprefix string) error {
 
 	return nil
 }
+
+// CheckIP returns an error if the virtual ip is no longer bound to the
+// interface or if the interface has gone down.
+func (a *HostAgent) CheckIP(ipprefix, iface string) error {
+	vip := a.vip.Find(ipprefix)
+	if vip == nil || vip.Device != iface {
+		return fmt.Errorf("virtual ip %s is not bound to interface %s", ipprefix, iface)
+	}
+
+	intf, err := net.InterfaceByName(iface)
+	if err != nil {
+		return err
+	}
+	if intf.Flags&net.FlagUp == 0 {
+		return fmt.Errorf("interface %s is down", iface)
+	}
+
+	// the link may be administratively up while the carrier is lost
+	if data, err := ioutil.ReadFile(fmt.Sprintf("/sys/class/net/%s/operstate", iface)); err == nil {
+		if state := strings.TrimSpace(string(data)); state == "down" || state == "lowerlayerdown" {
+			return fmt.Errorf("interface %s link is %s", iface, state)
+		}
+	}
+	return nil
+}
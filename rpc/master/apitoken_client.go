@@ -0,0 +1,43 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/apitoken"
+)
+
+// CreateAPIToken creates a named, scoped API token and returns its bearer
+// token
+func (c *Client) CreateAPIToken(name string, scope apitoken.Scope, expires time.Time) (string, *apitoken.APIToken, error) {
+	request := CreateAPITokenRequest{Name: name, Scope: scope, ExpiresAt: expires}
+	response := CreateAPITokenResponse{}
+	if err := c.call("CreateAPIToken", request, &response); err != nil {
+		return "", nil, err
+	}
+	return response.Token, &response.APIToken, nil
+}
+
+// GetAPITokens returns all API tokens
+func (c *Client) GetAPITokens() ([]apitoken.APIToken, error) {
+	tokens := []apitoken.APIToken{}
+	err := c.call("GetAPITokens", empty, &tokens)
+	return tokens, err
+}
+
+// RevokeAPIToken revokes an API token by id or name
+func (c *Client) RevokeAPIToken(idOrName string) error {
+	return c.call("RevokeAPIToken", idOrName, nil)
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/apitoken"
+)
+
+// CreateAPITokenRequest describes a new API token
+type CreateAPITokenRequest struct {
+	Name      string
+	Scope     apitoken.Scope
+	ExpiresAt time.Time
+}
+
+// CreateAPITokenResponse carries the bearer token, which is only available
+// when the token is created, along with the stored token
+type CreateAPITokenResponse struct {
+	Token    string
+	APIToken apitoken.APIToken
+}
+
+// CreateAPIToken creates a named, scoped API token
+func (s *Server) CreateAPIToken(request CreateAPITokenRequest, reply *CreateAPITokenResponse) error {
+	bearer, t, err := s.f.CreateAPIToken(s.context(), request.Name, request.Scope, request.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	*reply = CreateAPITokenResponse{Token: bearer, APIToken: *t}
+	return nil
+}
+
+// GetAPITokens returns all API tokens
+func (s *Server) GetAPITokens(unused struct{}, reply *[]apitoken.APIToken) error {
+	tokens, err := s.f.GetAPITokens(s.context())
+	if err != nil {
+		return err
+	}
+	*reply = tokens
+	return nil
+}
+
+// RevokeAPIToken revokes an API token by id or name
+func (s *Server) RevokeAPIToken(idOrName string, _ *struct{}) error {
+	return s.f.RevokeAPIToken(s.context(), idOrName)
+}
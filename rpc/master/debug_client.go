@@ -13,6 +13,11 @@
 
 package master
 
+import (
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
 // Enable internal metrics collection
 func (c *Client) DebugEnableMetrics() (string, error) {
 	result := ""
@@ -32,3 +37,22 @@ func (c *Client) DebugDisableMetrics() (string, error) {
 	}
 	return result, nil
 }
+
+// GetCoordinatorTree returns the coordinator's tree at the given path
+func (c *Client) GetCoordinatorTree(pth string) (*zzk.TreeNode, error) {
+	response := &zzk.TreeNode{}
+	if err := c.call("GetCoordinatorTree", pth, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// CheckCoordinatorState compares the coordinator with the database and
+// optionally repairs any inconsistencies
+func (c *Client) CheckCoordinatorState(repair bool) ([]zkservice.Inconsistency, error) {
+	response := []zkservice.Inconsistency{}
+	if err := c.call("CheckCoordinatorState", repair, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -15,6 +15,8 @@ package master
 
 import (
 	"github.com/control-center/serviced/metrics"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 var metricsTimer *metrics.MetricTimer
@@ -48,3 +50,24 @@ func (s *Server) DebugDisableMetrics(unused struct{}, results *string) error {
 	}
 	return nil
 }
+
+// GetCoordinatorTree returns the coordinator's tree at the given path
+func (s *Server) GetCoordinatorTree(pth string, reply *zzk.TreeNode) error {
+	tree, err := s.f.GetCoordinatorTree(s.context(), pth)
+	if err != nil {
+		return err
+	}
+	*reply = *tree
+	return nil
+}
+
+// CheckCoordinatorState compares the coordinator with the database and
+// optionally repairs any inconsistencies
+func (s *Server) CheckCoordinatorState(repair bool, reply *[]zkservice.Inconsistency) error {
+	found, err := s.f.CheckCoordinatorState(s.context(), repair)
+	if err != nil {
+		return err
+	}
+	*reply = found
+	return nil
+}
@@ -75,6 +75,30 @@ func (c *Client) RemoveHost(hostID string) error {
 	return c.call("RemoveHost", hostID, nil)
 }
 
+//DrainHost puts a host into maintenance mode
+func (c *Client) DrainHost(hostID string, timeout time.Duration) (*host.DrainReport, error) {
+	response := &host.DrainReport{}
+	req := HostDrainRequest{HostID: hostID, Timeout: timeout}
+	if err := c.call("DrainHost", req, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+//GetDrainStatus returns the progress of the most recent drain of a host
+func (c *Client) GetDrainStatus(hostID string) (*host.DrainReport, error) {
+	response := &host.DrainReport{}
+	if err := c.call("GetDrainStatus", hostID, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+//UncordonHost takes a host out of maintenance mode
+func (c *Client) UncordonHost(hostID string) error {
+	return c.call("UncordonHost", hostID, nil)
+}
+
 //FindHostsInPool returns all hosts in a pool
 func (c *Client) FindHostsInPool(poolID string) ([]host.Host, error) {
 	response := make([]host.Host, 0)
@@ -103,6 +103,37 @@ func (s *Server) FindHostsInPool(poolID string, hostReply *[]host.Host) error {
 	return nil
 }
 
+// HostDrainRequest is the request for draining a host
+type HostDrainRequest struct {
+	HostID  string
+	Timeout time.Duration
+}
+
+// DrainHost puts a host into maintenance mode
+func (s *Server) DrainHost(req HostDrainRequest, reply *host.DrainReport) error {
+	report, err := s.f.DrainHost(s.context(), req.HostID, req.Timeout)
+	if err != nil {
+		return err
+	}
+	*reply = *report
+	return nil
+}
+
+// GetDrainStatus returns the progress of the most recent drain of a host
+func (s *Server) GetDrainStatus(hostID string, reply *host.DrainReport) error {
+	report, err := s.f.GetDrainStatus(s.context(), hostID)
+	if err != nil {
+		return err
+	}
+	*reply = *report
+	return nil
+}
+
+// UncordonHost takes a host out of maintenance mode
+func (s *Server) UncordonHost(hostID string, _ *struct{}) error {
+	return s.f.UncordonHost(s.context(), hostID)
+}
+
 type HostAuthenticationRequest struct {
 	HostID    string
 	Timestamp int64
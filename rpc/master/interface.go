@@ -16,17 +16,24 @@ package master
 import (
 	"time"
 
+	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/apitoken"
 	"github.com/control-center/serviced/domain/applicationendpoint"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/snapshotschedule"
 	"github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/domain/webhook"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/volume"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 // The RPC interface is the API for a serviced master.
@@ -75,6 +82,16 @@ type ClientInterface interface {
 	// HostsAuthenticated returns if the hosts passed are authenticated or not
 	HostsAuthenticated(hostIDs []string) (map[string]bool, error)
 
+	// DrainHost puts a host into maintenance mode and moves its instances
+	// and virtual IPs onto other hosts
+	DrainHost(hostID string, timeout time.Duration) (*host.DrainReport, error)
+
+	// GetDrainStatus returns the progress of the most recent drain of a host
+	GetDrainStatus(hostID string) (*host.DrainReport, error)
+
+	// UncordonHost takes a host out of maintenance mode
+	UncordonHost(hostID string) error
+
 	//--------------------------------------------------------------------------
 	// Pool Management Functions
 
@@ -102,6 +119,10 @@ type ClientInterface interface {
 	// RemoveVirtualIP removes a VirtualIP from a specific pool
 	RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error
 
+	// GetVirtualIPStatus returns the owner and failover history of the
+	// virtual IPs in a pool
+	GetVirtualIPStatus(poolID string) ([]zkservice.VirtualIPStatus, error)
+
 	//--------------------------------------------------------------------------
 	// Service Management Functions
 
@@ -126,6 +147,21 @@ type ClientInterface interface {
 	// ClearEmergency will set EmergencyShutdown to false on the service and all child services
 	ClearEmergency(serviceID string) (int, error)
 
+	// RenderServiceConfigs returns the config files of a service instance as they would be rendered now
+	RenderServiceConfigs(serviceID string, instanceID int) ([]servicedefinition.ConfigFile, error)
+
+	// GetServiceConfigDrift returns the config files of running instances that differ from what would be rendered now
+	GetServiceConfigDrift(serviceID string) ([]service.ConfigDrift, error)
+
+	// GetServiceDependencyGraph returns the endpoint dependency graph of the tenant that the service belongs to
+	GetServiceDependencyGraph(serviceID string) (*service.DependencyGraph, error)
+
+	// GetRollingRestartStatus returns the progress of the most recent rolling restart of a service, or of all services
+	GetRollingRestartStatus(serviceID string) ([]service.RollingRestartStatus, error)
+
+	// SetServiceImage changes the image of a service, rolling it out to the running instances if rollout is set
+	SetServiceImage(serviceID, imageID string, rollout *service.RollingRestart) (bool, error)
+
 	//--------------------------------------------------------------------------
 	// Service Instance Management Functions
 
@@ -217,6 +253,73 @@ type ClientInterface interface {
 	// Validate the credentials of the specified user
 	ValidateCredentials(user user.User) (bool, error)
 
+	//--------------------------------------------------------------------------
+	// Secret Management Functions
+
+	// SetSecret encrypts and stores the value of a tenant secret
+	SetSecret(tenantID, name, value string) error
+
+	// GetSecrets returns the secrets of a tenant with their values redacted
+	GetSecrets(tenantID string) ([]secret.Secret, error)
+
+	// RemoveSecret deletes a tenant secret
+	RemoveSecret(tenantID, name string) error
+
+	//--------------------------------------------------------------------------
+	// API Token Management Functions
+
+	// CreateAPIToken creates a named, scoped API token and returns its bearer
+	// token
+	CreateAPIToken(name string, scope apitoken.Scope, expires time.Time) (string, *apitoken.APIToken, error)
+
+	// GetAPITokens returns all API tokens
+	GetAPITokens() ([]apitoken.APIToken, error)
+
+	// RevokeAPIToken revokes an API token by id or name
+	RevokeAPIToken(idOrName string) error
+
+	//--------------------------------------------------------------------------
+	// Webhook Management Functions
+
+	// AddWebhook subscribes a URL to cluster events and returns the secret
+	// that signs deliveries
+	AddWebhook(url string, events []string, tenantID, secret string) (string, *webhook.Webhook, error)
+
+	// GetWebhooks returns all webhooks
+	GetWebhooks() ([]webhook.Webhook, error)
+
+	// RemoveWebhook deletes a webhook
+	RemoveWebhook(id string) error
+
+	//--------------------------------------------------------------------------
+	// Snapshot Schedule Management Functions
+
+	// SetSnapshotSchedule sets when the tenant of a service is snapshotted
+	// and which of its snapshots are kept
+	SetSnapshotSchedule(serviceID string, interval time.Duration, cron string, retention snapshotschedule.Retention) (*snapshotschedule.SnapshotSchedule, error)
+
+	// GetSnapshotSchedules returns the snapshot schedules of all tenants
+	GetSnapshotSchedules() ([]snapshotschedule.SnapshotSchedule, error)
+
+	// RemoveSnapshotSchedule stops scheduled snapshots of the tenant of a
+	// service
+	RemoveSnapshotSchedule(serviceID string) error
+
+	//--------------------------------------------------------------------------
+	// Snapshot Clone Functions
+
+	// CloneSnapshot creates a new application with the given name from a
+	// snapshot, and returns the id of its tenant
+	CloneSnapshot(snapshotID, name string) (string, error)
+
+	//--------------------------------------------------------------------------
+	// Snapshot Diff Functions
+
+	// DiffSnapshots returns the files and services that differ going from a
+	// snapshot to another snapshot, or to the current state of the
+	// application if toSnapshotID is empty
+	DiffSnapshots(snapshotID, toSnapshotID string) (*dao.SnapshotDiff, error)
+
 	//--------------------------------------------------------------------------
 	// Healthcheck Management Functions
 
@@ -241,6 +344,13 @@ type ClientInterface interface {
 	// Disable internal metrics collection
 	DebugDisableMetrics() (string, error)
 
+	// GetCoordinatorTree returns the coordinator's tree at the given path
+	GetCoordinatorTree(pth string) (*zzk.TreeNode, error)
+
+	// CheckCoordinatorState compares the coordinator with the database and
+	// optionally repairs any inconsistencies
+	CheckCoordinatorState(repair bool) ([]zkservice.Inconsistency, error)
+
 	//--------------------------------------------------------------------------
 	// Assignment management functions
 
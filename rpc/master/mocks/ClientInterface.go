@@ -7,6 +7,7 @@ import isvcs "github.com/control-center/serviced/isvcs"
 import master "github.com/control-center/serviced/rpc/master"
 import mock "github.com/stretchr/testify/mock"
 import pool "github.com/control-center/serviced/domain/pool"
+import secret "github.com/control-center/serviced/domain/secret"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
 import servicetemplate "github.com/control-center/serviced/domain/servicetemplate"
@@ -14,6 +15,12 @@ import time "time"
 import user "github.com/control-center/serviced/domain/user"
 import volume "github.com/control-center/serviced/volume"
 import addressassignment "github.com/control-center/serviced/domain/addressassignment"
+import apitoken "github.com/control-center/serviced/domain/apitoken"
+import webhook "github.com/control-center/serviced/domain/webhook"
+import snapshotschedule "github.com/control-center/serviced/domain/snapshotschedule"
+import zzk "github.com/control-center/serviced/zzk"
+import zkservice "github.com/control-center/serviced/zzk/service"
+import dao "github.com/control-center/serviced/dao"
 
 // ClientInterface is an autogenerated mock type for the ClientInterface type
 type ClientInterface struct {
@@ -1207,3 +1214,534 @@ func (_m *ClientInterface) SetIPs(assignmentRequest addressassignment.Assignment
 }
 
 var _ master.ClientInterface = (*ClientInterface)(nil)
+
+// SetSecret provides a mock function with given fields: tenantID, name, value
+func (_m *ClientInterface) SetSecret(tenantID string, name string, value string) error {
+	ret := _m.Called(tenantID, name, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(tenantID, name, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSecrets provides a mock function with given fields: tenantID
+func (_m *ClientInterface) GetSecrets(tenantID string) ([]secret.Secret, error) {
+	ret := _m.Called(tenantID)
+
+	var r0 []secret.Secret
+	if rf, ok := ret.Get(0).(func(string) []secret.Secret); ok {
+		r0 = rf(tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]secret.Secret)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSecret provides a mock function with given fields: tenantID, name
+func (_m *ClientInterface) RemoveSecret(tenantID string, name string) error {
+	ret := _m.Called(tenantID, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(tenantID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenderServiceConfigs provides a mock function with given fields: serviceID, instanceID
+func (_m *ClientInterface) RenderServiceConfigs(serviceID string, instanceID int) ([]servicedefinition.ConfigFile, error) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 []servicedefinition.ConfigFile
+	if rf, ok := ret.Get(0).(func(string, int) []servicedefinition.ConfigFile); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]servicedefinition.ConfigFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceConfigDrift provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceConfigDrift(serviceID string) ([]service.ConfigDrift, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.ConfigDrift
+	if rf, ok := ret.Get(0).(func(string) []service.ConfigDrift); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ConfigDrift)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceDependencyGraph provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceDependencyGraph(serviceID string) (*service.DependencyGraph, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 *service.DependencyGraph
+	if rf, ok := ret.Get(0).(func(string) *service.DependencyGraph); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.DependencyGraph)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UncordonHost provides a mock function with given fields: hostID
+func (_m *ClientInterface) UncordonHost(hostID string) error {
+	ret := _m.Called(hostID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(hostID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDrainStatus provides a mock function with given fields: hostID
+func (_m *ClientInterface) GetDrainStatus(hostID string) (*host.DrainReport, error) {
+	ret := _m.Called(hostID)
+
+	var r0 *host.DrainReport
+	if rf, ok := ret.Get(0).(func(string) *host.DrainReport); ok {
+		r0 = rf(hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.DrainReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DrainHost provides a mock function with given fields: hostID, timeout
+func (_m *ClientInterface) DrainHost(hostID string, timeout time.Duration) (*host.DrainReport, error) {
+	ret := _m.Called(hostID, timeout)
+
+	var r0 *host.DrainReport
+	if rf, ok := ret.Get(0).(func(string, time.Duration) *host.DrainReport); ok {
+		r0 = rf(hostID, timeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.DrainReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(hostID, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCoordinatorTree provides a mock function with given fields: pth
+func (_m *ClientInterface) GetCoordinatorTree(pth string) (*zzk.TreeNode, error) {
+	ret := _m.Called(pth)
+
+	var r0 *zzk.TreeNode
+	if rf, ok := ret.Get(0).(func(string) *zzk.TreeNode); ok {
+		r0 = rf(pth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*zzk.TreeNode)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(pth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckCoordinatorState provides a mock function with given fields: repair
+func (_m *ClientInterface) CheckCoordinatorState(repair bool) ([]zkservice.Inconsistency, error) {
+	ret := _m.Called(repair)
+
+	var r0 []zkservice.Inconsistency
+	if rf, ok := ret.Get(0).(func(bool) []zkservice.Inconsistency); ok {
+		r0 = rf(repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]zkservice.Inconsistency)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVirtualIPStatus provides a mock function with given fields: poolID
+func (_m *ClientInterface) GetVirtualIPStatus(poolID string) ([]zkservice.VirtualIPStatus, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []zkservice.VirtualIPStatus
+	if rf, ok := ret.Get(0).(func(string) []zkservice.VirtualIPStatus); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]zkservice.VirtualIPStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRollingRestartStatus provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetRollingRestartStatus(serviceID string) ([]service.RollingRestartStatus, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.RollingRestartStatus
+	if rf, ok := ret.Get(0).(func(string) []service.RollingRestartStatus); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.RollingRestartStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetServiceImage provides a mock function with given fields: serviceID, imageID, rollout
+func (_m *ClientInterface) SetServiceImage(serviceID string, imageID string, rollout *service.RollingRestart) (bool, error) {
+	ret := _m.Called(serviceID, imageID, rollout)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string, *service.RollingRestart) bool); ok {
+		r0 = rf(serviceID, imageID, rollout)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, *service.RollingRestart) error); ok {
+		r1 = rf(serviceID, imageID, rollout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAPIToken provides a mock function with given fields: name, scope, expires
+func (_m *ClientInterface) CreateAPIToken(name string, scope apitoken.Scope, expires time.Time) (string, *apitoken.APIToken, error) {
+	ret := _m.Called(name, scope, expires)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, apitoken.Scope, time.Time) string); ok {
+		r0 = rf(name, scope, expires)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 *apitoken.APIToken
+	if rf, ok := ret.Get(1).(func(string, apitoken.Scope, time.Time) *apitoken.APIToken); ok {
+		r1 = rf(name, scope, expires)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*apitoken.APIToken)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, apitoken.Scope, time.Time) error); ok {
+		r2 = rf(name, scope, expires)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAPITokens provides a mock function with given fields:
+func (_m *ClientInterface) GetAPITokens() ([]apitoken.APIToken, error) {
+	ret := _m.Called()
+
+	var r0 []apitoken.APIToken
+	if rf, ok := ret.Get(0).(func() []apitoken.APIToken); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]apitoken.APIToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIToken provides a mock function with given fields: idOrName
+func (_m *ClientInterface) RevokeAPIToken(idOrName string) error {
+	ret := _m.Called(idOrName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(idOrName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddWebhook provides a mock function with given fields: url, events, tenantID, secret
+func (_m *ClientInterface) AddWebhook(url string, events []string, tenantID string, secret string) (string, *webhook.Webhook, error) {
+	ret := _m.Called(url, events, tenantID, secret)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, []string, string, string) string); ok {
+		r0 = rf(url, events, tenantID, secret)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 *webhook.Webhook
+	if rf, ok := ret.Get(1).(func(string, []string, string, string) *webhook.Webhook); ok {
+		r1 = rf(url, events, tenantID, secret)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*webhook.Webhook)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, []string, string, string) error); ok {
+		r2 = rf(url, events, tenantID, secret)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetWebhooks provides a mock function with given fields:
+func (_m *ClientInterface) GetWebhooks() ([]webhook.Webhook, error) {
+	ret := _m.Called()
+
+	var r0 []webhook.Webhook
+	if rf, ok := ret.Get(0).(func() []webhook.Webhook); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]webhook.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveWebhook provides a mock function with given fields: id
+func (_m *ClientInterface) RemoveWebhook(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSnapshotSchedule provides a mock function with given fields: serviceID, interval, cron, retention
+func (_m *ClientInterface) SetSnapshotSchedule(serviceID string, interval time.Duration, cron string, retention snapshotschedule.Retention) (*snapshotschedule.SnapshotSchedule, error) {
+	ret := _m.Called(serviceID, interval, cron, retention)
+
+	var r0 *snapshotschedule.SnapshotSchedule
+	if rf, ok := ret.Get(0).(func(string, time.Duration, string, snapshotschedule.Retention) *snapshotschedule.SnapshotSchedule); ok {
+		r0 = rf(serviceID, interval, cron, retention)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*snapshotschedule.SnapshotSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Duration, string, snapshotschedule.Retention) error); ok {
+		r1 = rf(serviceID, interval, cron, retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSnapshotSchedules provides a mock function with given fields:
+func (_m *ClientInterface) GetSnapshotSchedules() ([]snapshotschedule.SnapshotSchedule, error) {
+	ret := _m.Called()
+
+	var r0 []snapshotschedule.SnapshotSchedule
+	if rf, ok := ret.Get(0).(func() []snapshotschedule.SnapshotSchedule); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]snapshotschedule.SnapshotSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSnapshotSchedule provides a mock function with given fields: serviceID
+func (_m *ClientInterface) RemoveSnapshotSchedule(serviceID string) error {
+	ret := _m.Called(serviceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(serviceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CloneSnapshot provides a mock function with given fields: snapshotID, name
+func (_m *ClientInterface) CloneSnapshot(snapshotID string, name string) (string, error) {
+	ret := _m.Called(snapshotID, name)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(snapshotID, name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(snapshotID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiffSnapshots provides a mock function with given fields: snapshotID, toSnapshotID
+func (_m *ClientInterface) DiffSnapshots(snapshotID string, toSnapshotID string) (*dao.SnapshotDiff, error) {
+	ret := _m.Called(snapshotID, toSnapshotID)
+
+	var r0 *dao.SnapshotDiff
+	if rf, ok := ret.Get(0).(func(string, string) *dao.SnapshotDiff); ok {
+		r0 = rf(snapshotID, toSnapshotID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.SnapshotDiff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(snapshotID, toSnapshotID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
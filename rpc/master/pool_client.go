@@ -15,6 +15,7 @@ package master
 
 import (
 	"github.com/control-center/serviced/domain/pool"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 //GetResourcePool gets the pool for the given poolID or nil
@@ -68,3 +69,13 @@ func (c *Client) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 func (c *Client) RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	return c.call("RemoveVirtualIP", requestVirtualIP, nil)
 }
+
+//GetVirtualIPStatus returns the owner and failover history of the virtual IPs
+//in a pool
+func (c *Client) GetVirtualIPStatus(poolID string) ([]zkservice.VirtualIPStatus, error) {
+	response := make([]zkservice.VirtualIPStatus, 0)
+	if err := c.call("GetVirtualIPStatus", poolID, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -17,6 +17,7 @@ import (
 	"errors"
 
 	"github.com/control-center/serviced/domain/pool"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
 // GetResourcePools returns all ResourcePools
@@ -80,3 +81,14 @@ func (s *Server) AddVirtualIP(requestVirtualIP pool.VirtualIP, _ *struct{}) erro
 func (s *Server) RemoveVirtualIP(requestVirtualIP pool.VirtualIP, _ *struct{}) error {
 	return s.f.RemoveVirtualIP(s.context(), requestVirtualIP)
 }
+
+// GetVirtualIPStatus returns the owner and failover history of the virtual
+// IPs in a pool
+func (s *Server) GetVirtualIPStatus(poolID string, reply *[]zkservice.VirtualIPStatus) error {
+	statuses, err := s.f.GetVirtualIPStatus(s.context(), poolID)
+	if err != nil {
+		return err
+	}
+	*reply = statuses
+	return nil
+}
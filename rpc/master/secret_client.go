@@ -0,0 +1,37 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/secret"
+)
+
+// SetSecret encrypts and stores the value of a tenant secret
+func (c *Client) SetSecret(tenantID, name, value string) error {
+	request := SecretRequest{TenantID: tenantID, Name: name, Value: value}
+	return c.call("SetSecret", request, nil)
+}
+
+// GetSecrets returns the secrets of a tenant with their values redacted
+func (c *Client) GetSecrets(tenantID string) ([]secret.Secret, error) {
+	secrets := []secret.Secret{}
+	err := c.call("GetSecrets", tenantID, &secrets)
+	return secrets, err
+}
+
+// RemoveSecret deletes a tenant secret
+func (c *Client) RemoveSecret(tenantID, name string) error {
+	request := SecretRequest{TenantID: tenantID, Name: name}
+	return c.call("RemoveSecret", request, nil)
+}
@@ -0,0 +1,45 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/secret"
+)
+
+// SecretRequest identifies a tenant secret and, when setting it, its value
+type SecretRequest struct {
+	TenantID string
+	Name     string
+	Value    string
+}
+
+// SetSecret encrypts and stores the value of a tenant secret
+func (s *Server) SetSecret(request SecretRequest, _ *struct{}) error {
+	return s.f.SetSecret(s.context(), request.TenantID, request.Name, request.Value)
+}
+
+// GetSecrets returns the redacted secrets of a tenant
+func (s *Server) GetSecrets(tenantID string, reply *[]secret.Secret) error {
+	secrets, err := s.f.GetSecrets(s.context(), tenantID)
+	if err != nil {
+		return err
+	}
+	*reply = secrets
+	return nil
+}
+
+// RemoveSecret deletes a tenant secret
+func (s *Server) RemoveSecret(request SecretRequest, _ *struct{}) error {
+	return s.f.RemoveSecret(s.context(), request.TenantID, request.Name)
+}
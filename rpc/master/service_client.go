@@ -19,6 +19,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 // ServiceUse will use a new image for a given service - this will pull the image and tag it
@@ -123,3 +124,54 @@ func (c *Client) RemoveIPs(args []string) error {
 func (c *Client) SetIPs(r addressassignment.AssignmentRequest) error {
 	return c.call("SetIPs", r, new(string))
 }
+
+// RenderServiceConfigs returns the config files of a service instance as they
+// would be rendered now
+func (c *Client) RenderServiceConfigs(serviceID string, instanceID int) ([]servicedefinition.ConfigFile, error) {
+	request := EvaluateServiceRequest{
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+	}
+	confs := []servicedefinition.ConfigFile{}
+	err := c.call("RenderServiceConfigs", request, &confs)
+	return confs, err
+}
+
+// GetServiceConfigDrift returns the config files of running instances that
+// differ from what would be rendered now
+func (c *Client) GetServiceConfigDrift(serviceID string) ([]service.ConfigDrift, error) {
+	drift := []service.ConfigDrift{}
+	err := c.call("GetServiceConfigDrift", serviceID, &drift)
+	return drift, err
+}
+
+// GetServiceDependencyGraph returns the endpoint dependency graph of the
+// tenant that the service belongs to
+func (c *Client) GetServiceDependencyGraph(serviceID string) (*service.DependencyGraph, error) {
+	graph := &service.DependencyGraph{}
+	if err := c.call("GetServiceDependencyGraph", serviceID, graph); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// GetRollingRestartStatus returns the progress of the most recent rolling
+// restart of a service, or of all services if serviceID is empty
+func (c *Client) GetRollingRestartStatus(serviceID string) ([]service.RollingRestartStatus, error) {
+	response := make([]service.RollingRestartStatus, 0)
+	if err := c.call("GetRollingRestartStatus", serviceID, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// SetServiceImage changes the image of a service, rolling it out to the
+// running instances if rollout is set.  Returns true if a rollout was started.
+func (c *Client) SetServiceImage(serviceID, imageID string, rollout *service.RollingRestart) (bool, error) {
+	request := SetServiceImageRequest{ServiceID: serviceID, ImageID: imageID, Rollout: rollout}
+	started := false
+	if err := c.call("SetServiceImage", request, &started); err != nil {
+		return false, err
+	}
+	return started, nil
+}
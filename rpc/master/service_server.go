@@ -18,6 +18,7 @@ import (
 
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 type ServiceUseRequest struct {
@@ -46,6 +47,12 @@ type EvaluateServiceResponse struct {
 	ServiceNamePath  string
 }
 
+type SetServiceImageRequest struct {
+	ServiceID string
+	ImageID   string
+	Rollout   *service.RollingRestart
+}
+
 type ServiceDetailsByTenantIDRequest struct {
 	TenantID string
 	Since    time.Duration
@@ -161,3 +168,58 @@ func (s *Server) RemoveIPs(args []string, unused *string) error {
 func (s *Server) SetIPs(request addressassignment.AssignmentRequest, unused *string) error {
 	return s.f.SetIPs(s.context(), request)
 }
+
+// RenderServiceConfigs returns the config files of a service instance as they
+// would be rendered now
+func (s *Server) RenderServiceConfigs(request EvaluateServiceRequest, response *[]servicedefinition.ConfigFile) error {
+	confs, err := s.f.RenderServiceConfigs(s.context(), request.ServiceID, request.InstanceID)
+	if err != nil {
+		return err
+	}
+	*response = confs
+	return nil
+}
+
+// GetServiceConfigDrift returns the config files of running instances that
+// differ from what would be rendered now
+func (s *Server) GetServiceConfigDrift(serviceID string, response *[]service.ConfigDrift) error {
+	drift, err := s.f.GetServiceConfigDrift(s.context(), serviceID)
+	if err != nil {
+		return err
+	}
+	*response = drift
+	return nil
+}
+
+// GetServiceDependencyGraph returns the endpoint dependency graph of the
+// tenant that the service belongs to
+func (s *Server) GetServiceDependencyGraph(serviceID string, response *service.DependencyGraph) error {
+	graph, err := s.f.GetServiceDependencyGraph(s.context(), serviceID)
+	if err != nil {
+		return err
+	}
+	*response = *graph
+	return nil
+}
+
+// GetRollingRestartStatus returns the progress of the most recent rolling
+// restart of a service, or of all services if serviceID is empty
+func (s *Server) GetRollingRestartStatus(serviceID string, response *[]service.RollingRestartStatus) error {
+	statuses, err := s.f.GetRollingRestartStatus(s.context(), serviceID)
+	if err != nil {
+		return err
+	}
+	*response = statuses
+	return nil
+}
+
+// SetServiceImage changes the image of a service, rolling it out to the
+// running instances if a rollout is requested
+func (s *Server) SetServiceImage(request SetServiceImageRequest, response *bool) error {
+	started, err := s.f.SetServiceImage(s.context(), request.ServiceID, request.ImageID, request.Rollout)
+	if err != nil {
+		return err
+	}
+	*response = started
+	return nil
+}
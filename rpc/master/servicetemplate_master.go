@@ -45,7 +45,7 @@ func (s *Server) RemoveServiceTemplate(templateID string,  _ *struct{}) error  {
 
 // Deploy a service template
 func (s *Server) DeployTemplate(request servicetemplate.ServiceTemplateDeploymentRequest, response *[]string) error  {
-	tenantIDs, err := s.f.DeployTemplate(s.context(), request.PoolID, request.TemplateID, request.DeploymentID)
+	tenantIDs, err := s.f.DeployTemplate(s.context(), request.PoolID, request.TemplateID, request.DeploymentID, request.Parameters)
 	if err != nil {
 		return err
 	}
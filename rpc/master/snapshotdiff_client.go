@@ -0,0 +1,28 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import "github.com/control-center/serviced/dao"
+
+// DiffSnapshots returns the files and services that differ going from a
+// snapshot to another snapshot, or to the current state of the application
+// if toSnapshotID is empty.
+func (c *Client) DiffSnapshots(snapshotID, toSnapshotID string) (*dao.SnapshotDiff, error) {
+	request := DiffSnapshotsRequest{SnapshotID: snapshotID, ToSnapshotID: toSnapshotID}
+	diff := &dao.SnapshotDiff{}
+	if err := c.call("DiffSnapshots", request, diff); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import "github.com/control-center/serviced/dao"
+
+// DiffSnapshotsRequest is a request to compare a snapshot with another
+// snapshot, or with the current state of its application if ToSnapshotID is
+// empty.
+type DiffSnapshotsRequest struct {
+	SnapshotID   string
+	ToSnapshotID string
+}
+
+// DiffSnapshots returns the files and services that differ between two
+// states of an application
+func (s *Server) DiffSnapshots(request DiffSnapshotsRequest, diff *dao.SnapshotDiff) error {
+	d, err := s.f.DiffSnapshots(s.context(), request.SnapshotID, request.ToSnapshotID)
+	if err != nil {
+		return err
+	}
+	*diff = *d
+	return nil
+}
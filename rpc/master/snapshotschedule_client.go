@@ -0,0 +1,39 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/snapshotschedule"
+)
+
+func (c *Client) SetSnapshotSchedule(serviceID string, interval time.Duration, cron string, retention snapshotschedule.Retention) (*snapshotschedule.SnapshotSchedule, error) {
+	request := SetSnapshotScheduleRequest{ServiceID: serviceID, Interval: interval, Cron: cron, Retention: retention}
+	response := snapshotschedule.SnapshotSchedule{}
+	if err := c.call("SetSnapshotSchedule", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) GetSnapshotSchedules() ([]snapshotschedule.SnapshotSchedule, error) {
+	schedules := []snapshotschedule.SnapshotSchedule{}
+	err := c.call("GetSnapshotSchedules", empty, &schedules)
+	return schedules, err
+}
+
+func (c *Client) RemoveSnapshotSchedule(serviceID string) error {
+	return c.call("RemoveSnapshotSchedule", serviceID, nil)
+}
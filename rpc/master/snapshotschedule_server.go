@@ -0,0 +1,49 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/snapshotschedule"
+)
+
+type SetSnapshotScheduleRequest struct {
+	ServiceID string
+	Interval  time.Duration
+	Cron      string
+	Retention snapshotschedule.Retention
+}
+
+func (s *Server) SetSnapshotSchedule(request SetSnapshotScheduleRequest, reply *snapshotschedule.SnapshotSchedule) error {
+	schedule, err := s.f.SetSnapshotSchedule(s.context(), request.ServiceID, request.Interval, request.Cron, request.Retention)
+	if err != nil {
+		return err
+	}
+	*reply = *schedule
+	return nil
+}
+
+func (s *Server) GetSnapshotSchedules(unused struct{}, reply *[]snapshotschedule.SnapshotSchedule) error {
+	schedules, err := s.f.GetSnapshotSchedules(s.context())
+	if err != nil {
+		return err
+	}
+	*reply = schedules
+	return nil
+}
+
+func (s *Server) RemoveSnapshotSchedule(serviceID string, _ *struct{}) error {
+	return s.f.RemoveSnapshotSchedule(s.context(), serviceID)
+}
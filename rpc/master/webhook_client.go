@@ -0,0 +1,41 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/webhook"
+)
+
+// AddWebhook subscribes a URL to cluster events and returns the secret that
+// signs deliveries
+func (c *Client) AddWebhook(url string, events []string, tenantID, secret string) (string, *webhook.Webhook, error) {
+	request := AddWebhookRequest{URL: url, Events: events, TenantID: tenantID, Secret: secret}
+	response := AddWebhookResponse{}
+	if err := c.call("AddWebhook", request, &response); err != nil {
+		return "", nil, err
+	}
+	return response.Secret, &response.Webhook, nil
+}
+
+// GetWebhooks returns all webhooks
+func (c *Client) GetWebhooks() ([]webhook.Webhook, error) {
+	hooks := []webhook.Webhook{}
+	err := c.call("GetWebhooks", empty, &hooks)
+	return hooks, err
+}
+
+// RemoveWebhook deletes a webhook
+func (c *Client) RemoveWebhook(id string) error {
+	return c.call("RemoveWebhook", id, nil)
+}
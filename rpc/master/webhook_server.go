@@ -0,0 +1,58 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/webhook"
+)
+
+// AddWebhookRequest describes a new webhook
+type AddWebhookRequest struct {
+	URL      string
+	Events   []string
+	TenantID string
+	Secret   string
+}
+
+// AddWebhookResponse carries the secret that signs deliveries, which is only
+// available when the webhook is added, along with the stored webhook
+type AddWebhookResponse struct {
+	Secret  string
+	Webhook webhook.Webhook
+}
+
+// AddWebhook subscribes a URL to cluster events
+func (s *Server) AddWebhook(request AddWebhookRequest, reply *AddWebhookResponse) error {
+	secret, w, err := s.f.AddWebhook(s.context(), request.URL, request.Events, request.TenantID, request.Secret)
+	if err != nil {
+		return err
+	}
+	*reply = AddWebhookResponse{Secret: secret, Webhook: *w}
+	return nil
+}
+
+// GetWebhooks returns all webhooks
+func (s *Server) GetWebhooks(unused struct{}, reply *[]webhook.Webhook) error {
+	hooks, err := s.f.GetWebhooks(s.context())
+	if err != nil {
+		return err
+	}
+	*reply = hooks
+	return nil
+}
+
+// RemoveWebhook deletes a webhook
+func (s *Server) RemoveWebhook(id string, _ *struct{}) error {
+	return s.f.RemoveWebhook(s.context(), id)
+}
@@ -21,9 +21,12 @@ import (
 	"net/rpc"
 	"net/rpc/jsonrpc"
 	"sync"
+	"time"
 
 	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/metrics/prometheus"
+	"github.com/control-center/serviced/trace"
 )
 
 var (
@@ -57,8 +60,16 @@ var (
 	ErrNoAdmin = errors.New("Delegate does not have admin access")
 
 	log = logging.PackageLogger()
+
+	rpcDuration = prometheus.NewHistogramVec("serviced_rpc_duration_seconds",
+		"Time taken to serve RPC calls, from reading the request to writing the response",
+		prometheus.DefBuckets, "method", "result")
 )
 
+func init() {
+	prometheus.Register(rpcDuration)
+}
+
 // Checks the RPC method name to see if authentication is required.
 //  If it is, calls on the client side will include a signed header, which will be
 //  Verified on the server side
@@ -107,6 +118,10 @@ type AuthServerCodec struct {
 	parser       auth.RPCHeaderParser
 	wBuffMutex   sync.Mutex // Make sure we buffer one response at a time
 	lastError    error
+	startMutex   sync.Mutex
+	started      map[uint64]time.Time   // When each outstanding request was read, by sequence
+	spans        map[uint64]*trace.Span // Traced outstanding requests, by sequence
+	current      trace.SpanContext      // Trace of the request whose body is read next
 }
 
 func NewDefaultAuthServerCodec(conn io.ReadWriteCloser) rpc.ServerCodec {
@@ -120,6 +135,8 @@ func NewAuthServerCodec(conn io.ReadWriteCloser, createCodec ServerCodecCreator,
 		buff:         buff,
 		wrappedcodec: createCodec(buff),
 		parser:       parser,
+		started:      make(map[uint64]time.Time),
+		spans:        make(map[uint64]*trace.Span),
 	}
 }
 
@@ -134,6 +151,7 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 
 	// Reset state
 	a.lastError = nil
+	a.current = trace.SpanContext{}
 	a.buff.ReadBuff.Reset()
 
 	ident, body, err := a.parser.ReadHeader(a.conn)
@@ -147,6 +165,9 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 		}
 	}
 
+	// Look for the trace context in the request, if the client sent one
+	parent := readTraceParent(body)
+
 	// Now write the actual request to the buffer
 	if _, err = a.buff.ReadBuff.Write(body); err != nil {
 		return err
@@ -157,7 +178,19 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 		return err
 	}
 
-	log.WithField("ServiceMethod", r.ServiceMethod).Debug("Received RPC request")
+	logger := log.WithField("ServiceMethod", r.ServiceMethod)
+
+	a.startMutex.Lock()
+	a.started[r.Seq] = time.Now()
+	if parent.IsValid() {
+		span := trace.Start(r.ServiceMethod, parent)
+		a.spans[r.Seq] = span
+		a.current = span.Context
+		logger = logger.WithFields(span.Fields())
+	}
+	a.startMutex.Unlock()
+
+	logger.Debug("Received RPC request")
 
 	// Now we can get the method name from r and authenticate if required
 	//  If this fails, save the error to return later
@@ -177,19 +210,28 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 }
 
 // Decodes the request and populates the body object with the body of the request
-//  We don't change anything here, just let the underlying codec handle it.
+//  The underlying codec does the decoding; if the request is traced and the
+//  body can carry a trace, the server span is attached to it.
 //  This always gets called after ReadRequestHeader
 func (a *AuthServerCodec) ReadRequestBody(body interface{}) error {
 	if a.lastError != nil {
 		return a.lastError
 	}
 	// TODO: Use reflection and add the identity to the body if necessary
-	return a.wrappedcodec.ReadRequestBody(body)
+	if err := a.wrappedcodec.ReadRequestBody(body); err != nil {
+		return err
+	}
+	if carrier, ok := body.(trace.Carrier); ok && a.current.IsValid() {
+		carrier.SetTraceContext(a.current)
+	}
+	return nil
 }
 
 //  Encodes the response before sending it back down to the client.
 //  We don't change anything here, just let the underlying codec handle it.
 func (a *AuthServerCodec) WriteResponse(r *rpc.Response, body interface{}) error {
+	a.observe(r)
+
 	// We do need a lock here, because the ServerCodec interface specifies
 	//  that WriteResponse must be safe for concurrent use by multiple goroutines
 	a.wBuffMutex.Lock()
@@ -211,6 +253,32 @@ func (a *AuthServerCodec) WriteResponse(r *rpc.Response, body interface{}) error
 	return nil
 }
 
+// observe records how long the server took to answer a request and
+// finishes its span if it was traced
+func (a *AuthServerCodec) observe(r *rpc.Response) {
+	a.startMutex.Lock()
+	start, ok := a.started[r.Seq]
+	span := a.spans[r.Seq]
+	delete(a.started, r.Seq)
+	delete(a.spans, r.Seq)
+	a.startMutex.Unlock()
+	if span != nil {
+		var err error
+		if r.Error != "" {
+			err = errors.New(r.Error)
+		}
+		span.Finish(err)
+	}
+	if !ok {
+		return
+	}
+	result := "ok"
+	if r.Error != "" {
+		result = "error"
+	}
+	rpcDuration.Observe(time.Since(start).Seconds(), r.ServiceMethod, result)
+}
+
 // Closes the connection on the server side
 //  We don't change anything here, just let the underlying codec handle it.
 func (a *AuthServerCodec) Close() error {
@@ -265,8 +333,12 @@ func (a *AuthClientCodec) WriteRequest(r *rpc.Request, body interface{}) error {
 		return err
 	}
 
-	// Get the request off the buffer
+	// Get the request off the buffer, with the caller's trace if tracing is
+	//  configured and it has one
 	request := a.buff.WriteBuff.Bytes()
+	if trace.Enabled() {
+		request = writeTraceParent(traceOf(body), request)
+	}
 
 	needsAuth := requiresAuthentication(r.ServiceMethod)
 	if err := a.headerBuilder.WriteHeader(a.conn, request, needsAuth); err != nil {
@@ -330,6 +402,46 @@ func (a *AuthClientCodec) Close() error {
 	return err
 }
 
+// traceParentMember starts a JSON-RPC request that carries a trace context.
+// The context is the first member of the request object; servers that do not
+// know about it ignore it, like any unknown member.
+var traceParentMember = []byte(`{"traceparent":"`)
+
+// traceOf returns the trace of an outgoing request: the body's own trace, or
+// else the trace of this process.
+func traceOf(body interface{}) trace.SpanContext {
+	if carrier, ok := body.(trace.Carrier); ok {
+		if sc := carrier.TraceContext(); sc.IsValid() {
+			return sc
+		}
+	}
+	return trace.Default()
+}
+
+// writeTraceParent adds a trace context to a JSON-RPC request
+func writeTraceParent(sc trace.SpanContext, request []byte) []byte {
+	if !sc.IsValid() || len(request) == 0 || request[0] != '{' {
+		return request
+	}
+	traced := append([]byte{}, traceParentMember...)
+	traced = append(traced, sc.String()...)
+	traced = append(traced, '"', ',')
+	return append(traced, request[1:]...)
+}
+
+// readTraceParent returns the trace context of a JSON-RPC request, if any
+func readTraceParent(request []byte) trace.SpanContext {
+	if !bytes.HasPrefix(request, traceParentMember) {
+		return trace.SpanContext{}
+	}
+	value := request[len(traceParentMember):]
+	end := bytes.IndexByte(value, '"')
+	if end < 0 {
+		return trace.SpanContext{}
+	}
+	return trace.Parse(string(value[:end]))
+}
+
 // NewDefaultAuthClient returns a new rpc.Client that uses our default client codec
 func NewDefaultAuthClient(conn io.ReadWriteCloser) *rpc.Client {
 	return rpc.NewClientWithCodec(NewDefaultAuthClientCodec(conn))
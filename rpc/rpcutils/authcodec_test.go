@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"net/rpc"
+	"net/rpc/jsonrpc"
 
 	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
@@ -13,6 +14,7 @@ import (
 	"github.com/control-center/serviced/auth"
 	authmocks "github.com/control-center/serviced/auth/mocks"
 	"github.com/control-center/serviced/rpc/rpcutils/mocks"
+	"github.com/control-center/serviced/trace"
 )
 
 type AuthCodecTest struct {
@@ -165,6 +167,121 @@ func (s *MySuite) TestWriteResponse(c *C) {
 	codectest.conn.AssertExpectations(c)
 }
 
+func (s *MySuite) TestWriteResponseObservesDuration(c *C) {
+	body := 0
+	req := &rpc.Request{ServiceMethod: "RPCTestType.TimedCall", Seq: 42}
+	resp := &rpc.Response{ServiceMethod: "RPCTestType.TimedCall", Seq: 42, Error: "failed"}
+	emptyLenBuff := make([]byte, auth.BodyLenLen)
+	emptyBodyBuff := make([]byte, 0)
+
+	count := func() float64 {
+		for _, sample := range rpcDuration.Collect() {
+			if sample.Suffix == "_count" && sample.Labels["method"] == "RPCTestType.TimedCall" && sample.Labels["result"] == "error" {
+				return sample.Value
+			}
+		}
+		return 0
+	}
+
+	ident := &authmocks.Identity{}
+	ident.On("HasAdminAccess").Return(true).Once()
+	codectest.headerParser.On("ReadHeader", codectest.conn).Return(ident, []byte{}, nil).Once()
+	codectest.wrappedServerCodec.On("ReadRequestHeader", req).Return(nil).Once()
+	err := codectest.authServerCodec.ReadRequestHeader(req)
+	c.Assert(err, IsNil)
+
+	codectest.wrappedServerCodec.On("WriteResponse", resp, body).Return(nil).Once()
+	codectest.conn.On("Write", emptyLenBuff).Return(auth.BodyLenLen, nil).Once()
+	codectest.conn.On("Write", emptyBodyBuff).Return(0, nil).Once()
+	err = codectest.authServerCodec.WriteResponse(resp, body)
+	c.Assert(err, IsNil)
+	c.Assert(count(), Equals, float64(1))
+
+	// A response without an outstanding request is not observed again
+	codectest.wrappedServerCodec.On("WriteResponse", resp, body).Return(nil).Once()
+	codectest.conn.On("Write", emptyLenBuff).Return(auth.BodyLenLen, nil).Once()
+	codectest.conn.On("Write", emptyBodyBuff).Return(0, nil).Once()
+	err = codectest.authServerCodec.WriteResponse(resp, body)
+	c.Assert(err, IsNil)
+	c.Assert(count(), Equals, float64(1))
+}
+
+// tracedBody is a request body that carries a trace
+type tracedBody struct {
+	sc trace.SpanContext
+}
+
+func (b *tracedBody) TraceContext() trace.SpanContext     { return b.sc }
+func (b *tracedBody) SetTraceContext(sc trace.SpanContext) { b.sc = sc }
+
+func (s *MySuite) TestTracePropagation(c *C) {
+	parent := trace.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	content := []byte(`{"method":"AuthenticatingCall","params":[{}],"id":0}`)
+	traced := []byte(`{"traceparent":"` + parent.String() + `","method":"AuthenticatingCall","params":[{}],"id":0}`)
+
+	// Without a configured exporter, requests are sent as they are
+	req := &rpc.Request{ServiceMethod: "AuthenticatingCall"}
+	out := &tracedBody{sc: parent}
+	codectest.wrappedClientCodec.On("WriteRequest", req, out).Return(nil).Run(func(args mock.Arguments) {
+		codectest.buffer.Write(content)
+	}).Twice()
+	codectest.headerBuilder.On("WriteHeader", codectest.conn, content, true).Return(nil).Once()
+	err := codectest.authClientCodec.WriteRequest(req, out)
+	c.Assert(err, IsNil)
+
+	// With one, the client adds the body's trace to the request
+	trace.SetExporter(&testExporter{})
+	defer trace.SetExporter(nil)
+	codectest.headerBuilder.On("WriteHeader", codectest.conn, traced, true).Return(nil).Once()
+	err = codectest.authClientCodec.WriteRequest(req, out)
+	c.Assert(err, IsNil)
+	codectest.headerBuilder.AssertExpectations(c)
+
+	// The server leaves the request for the wrapped codec, which ignores the
+	// trace, and hands a child span to the body
+	req = &rpc.Request{ServiceMethod: "RPCTestType.NonAuthenticatingCall"}
+	codectest.headerParser.On("ReadHeader", codectest.conn).Return(&authmocks.Identity{}, traced, nil).Once()
+	codectest.wrappedServerCodec.On("ReadRequestHeader", req).Return(nil).Run(func(args mock.Arguments) {
+		buf := codectest.authServerCodec.(*AuthServerCodec).buff
+		c.Assert(buf.ReadBuff.Bytes(), DeepEquals, traced)
+	}).Once()
+	err = codectest.authServerCodec.ReadRequestHeader(req)
+	c.Assert(err, IsNil)
+
+	in := &tracedBody{}
+	codectest.wrappedServerCodec.On("ReadRequestBody", in).Return(nil).Once()
+	err = codectest.authServerCodec.ReadRequestBody(in)
+	c.Assert(err, IsNil)
+	c.Assert(in.sc.TraceID, Equals, parent.TraceID)
+	c.Assert(in.sc.SpanID, Not(Equals), parent.SpanID)
+
+	// Untraced and non-JSON requests pass through unchanged
+	c.Assert(writeTraceParent(trace.SpanContext{}, content), DeepEquals, content)
+	c.Assert(writeTraceParent(parent, []byte("contents")), DeepEquals, []byte("contents"))
+	c.Assert(readTraceParent(content).IsValid(), Equals, false)
+}
+
+func (s *MySuite) TestTraceParentIsIgnoredByJSONRPC(c *C) {
+	// A server that predates tracing decodes a traced request as usual
+	parent := trace.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	content := []byte(`{"method":"RPCTestType.Call","params":["value"],"id":7}` + "\n")
+	buff := &ByteBufferReadWriteCloser{}
+	buff.ReadBuff.Write(writeTraceParent(parent, content))
+	codec := jsonrpc.NewServerCodec(buff)
+
+	req := &rpc.Request{}
+	c.Assert(codec.ReadRequestHeader(req), IsNil)
+	c.Assert(req.ServiceMethod, Equals, "RPCTestType.Call")
+	var value string
+	c.Assert(codec.ReadRequestBody(&value), IsNil)
+	c.Assert(value, Equals, "value")
+}
+
+// testExporter discards spans
+type testExporter struct{}
+
+func (e *testExporter) Export(*trace.Span) {}
+
 func (s *MySuite) TestCloseServerCodec(c *C) {
 	// Error on wrapped codec close
 	codectest.wrappedServerCodec.On("Close").Return(ErrTestCodec).Once()
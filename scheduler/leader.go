@@ -22,6 +22,7 @@ import (
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/scheduler/strategy"
 	"github.com/control-center/serviced/zzk"
@@ -53,15 +54,20 @@ func Lead(shutdown <-chan interface{}, conn coordclient.Connection, cpClient dao
 	// creates a listener for services
 	serviceListener := zkservice.NewServiceListener(poolID, &leader)
 
+	// creates listeners that publish instance and host events
+	instanceEvents := zkservice.NewInstanceEventListener(poolID, facade)
+	hostEvents := zkservice.NewHostEventListener(poolID, facade)
+
 	// starts all of the listeners
-	zzk.Start(shutdown, conn, serviceListener, hreg)
+	zzk.Start(shutdown, conn, serviceListener, hreg, instanceEvents, hostEvents)
 }
 
 // SelectHost chooses a host from the pool for the specified service. If the
 // service has an address assignment the host will already be selected. If not
 // the host with the least amount of memory committed to running containers will
 // be chosen.  Returns the hostid, hostip (if it has an address assignment).
-func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
+// The resource pool is looked up once per scheduling pass.
+func (l *leader) SelectHost(sn *zkservice.ServiceNode, pass *zkservice.SchedulingPass) (string, error) {
 	logger := plog.WithFields(log.Fields{
 		"serviceid":   sn.ID,
 		"servicename": sn.Name,
@@ -152,6 +158,18 @@ func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
 		return "", err
 	}
 
-	return StrategySelectHost(sn, hosts, strat, l.facade)
+	// strict pools refuse to commit more cores than a host has
+	strictCPU := false
+	p, err := pass.Load(func() (interface{}, error) {
+		return l.facade.GetResourcePool(datastore.Get(), l.poolID)
+	})
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up resource pool")
+		return "", err
+	} else if rp := p.(*pool.ResourcePool); rp != nil {
+		strictCPU = rp.StrictCPU
+	}
+
+	return StrategySelectHost(sn, hosts, strat, l.facade, strictCPU)
 }
 
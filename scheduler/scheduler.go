@@ -24,6 +24,7 @@ import (
 	"github.com/control-center/serviced/datastore"
 	imgreg "github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/dfs/ttl"
+	"github.com/control-center/serviced/domain/snapshotschedule"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/zzk"
@@ -38,6 +39,12 @@ var (
 	ErrNoAuthenticatedHosts = errors.New("no authenticated hosts found")
 )
 
+// autoScaleInterval is how often the autoscaler checks the services
+const autoScaleInterval = 30 * time.Second
+
+// snapshotScheduleInterval is how often the snapshot schedules are checked
+const snapshotScheduleInterval = time.Minute
+
 type leaderFunc func(<-chan interface{}, coordclient.Connection, dao.ControlPlane, *facade.Facade, string)
 
 type scheduler struct {
@@ -197,10 +204,26 @@ func (s *scheduler) mainloop(conn coordclient.Connection) {
 		go func() {
 			defer glog.Infof("Stopping snapshot ttl")
 			defer wg.Done()
-			ttl.RunSnapshotTTL(s.cpDao, _shutdown, time.Minute, time.Duration(s.snapshotTTL)*time.Hour)
+			ttl.RunSnapshotTTL(s.cpDao, s.retainsSnapshots, _shutdown, time.Minute, time.Duration(s.snapshotTTL)*time.Hour)
 		}()
 	}
 
+	// take scheduled snapshots and apply their retention policies
+	wg.Add(1)
+	go func() {
+		defer glog.Infof("Stopping snapshot schedules")
+		defer wg.Done()
+		s.runSnapshotSchedules(_shutdown, snapshotScheduleInterval)
+	}()
+
+	// autoscale the services that scale on metrics
+	wg.Add(1)
+	go func() {
+		defer glog.Infof("Stopping autoscaler")
+		defer wg.Done()
+		s.runAutoScaler(_shutdown, autoScaleInterval)
+	}()
+
 	// wait for something to happen
 	for {
 		select {
@@ -216,6 +239,51 @@ func (s *scheduler) mainloop(conn coordclient.Connection) {
 	}
 }
 
+// runAutoScaler periodically sets the number of instances of autoscaled
+// services until shutdown
+func (s *scheduler) runAutoScaler(shutdown <-chan interface{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.facade.AutoScaleServices(datastore.Get()); err != nil {
+				plog.WithError(err).Warn("Could not autoscale services")
+			}
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// runSnapshotSchedules periodically takes the snapshots that are due until
+// shutdown
+func (s *scheduler) runSnapshotSchedules(shutdown <-chan interface{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.facade.RunSnapshotSchedules(datastore.Get(), time.Now()); err != nil {
+				plog.WithError(err).Warn("Could not run snapshot schedules")
+			}
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// retainsSnapshots returns true if a snapshot of a tenant was taken by its
+// snapshot schedule, and is purged by the schedule's retention policy instead
+// of by age.
+func (s *scheduler) retainsSnapshots(tenantID string, snapshot dao.SnapshotInfo) bool {
+	if snapshot.Description != snapshotschedule.Message {
+		return false
+	}
+	schedule, err := s.facade.GetSnapshotSchedule(datastore.Get(), tenantID)
+	return err == nil && !schedule.Retention.IsZero()
+}
+
 // Stop stops all scheduler processes for the master
 func (s *scheduler) Stop() {
 	s.Lock()
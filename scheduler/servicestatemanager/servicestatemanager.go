@@ -342,6 +342,24 @@ func (s *BatchServiceStateManager) removeTenant(tenantID string) error {
 	return nil
 }
 
+// QueueDepths returns how many services are waiting to be scheduled for each
+// desired state, across all tenants, not counting the batches being processed
+func (s *BatchServiceStateManager) QueueDepths() map[service.DesiredState]int {
+	depths := make(map[service.DesiredState]int)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, tenantQueues := range s.TenantQueues {
+		for desiredState, queue := range tenantQueues {
+			queue.lock.RLock()
+			for _, batch := range queue.BatchQueue {
+				depths[desiredState] += len(batch.Services)
+			}
+			queue.lock.RUnlock()
+		}
+	}
+	return depths
+}
+
 func (s *BatchServiceStateManager) SyncCurrentStates(svcIDs []string) {
 	states := make(map[string]service.ServiceCurrentState)
 	s.lock.RLock()
@@ -1091,6 +1109,9 @@ func (s *BatchServiceStateManager) updateBatch(batch *ServiceStateChangeBatch) {
 		for _, svc := range services {
 			if id == svc.ID {
 				found = true
+				// keep what the request passed on to the scheduler
+				svc.Trace = batchService.Trace
+				svc.RollingRestart = batchService.RollingRestart
 				batchService.Service = svc
 				newServices[svc.ID] = batchService
 				break
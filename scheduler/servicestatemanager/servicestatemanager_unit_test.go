@@ -2296,3 +2296,24 @@ func (s *ServiceStateManagerSuite) CompareCancellableServices(a, b *ssm.Cancella
 	return a.ID == b.ID && a.DesiredState == b.DesiredState &&
 		a.EmergencyShutdownLevel == b.EmergencyShutdownLevel && a.StartLevel == b.StartLevel
 }
+
+func (s *ServiceStateManagerSuite) TestServiceStateManager_QueueDepths(c *C) {
+	c.Assert(s.serviceStateManager.QueueDepths(), DeepEquals, map[service.DesiredState]int{})
+
+	for _, tenantID := range []string{"tenant1", "tenant2"} {
+		s.serviceStateManager.TenantQueues[tenantID] = make(map[service.DesiredState]*ssm.ServiceStateQueue)
+		s.serviceStateManager.TenantQueues[tenantID][service.SVCRun] = ssm.NewServiceStateQueue(s.facade)
+		s.serviceStateManager.TenantQueues[tenantID][service.SVCStop] = ssm.NewServiceStateQueue(s.facade)
+	}
+	s.facade.On("SetServicesCurrentState", s.ctx, mock.Anything, mock.AnythingOfType("[]string"))
+
+	err := s.serviceStateManager.ScheduleServices(getTestServicesABC(), "tenant1", service.SVCRun, false)
+	c.Assert(err, IsNil)
+	err = s.serviceStateManager.ScheduleServices(getTestServicesDEF(), "tenant2", service.SVCStop, false)
+	c.Assert(err, IsNil)
+
+	c.Assert(s.serviceStateManager.QueueDepths(), DeepEquals, map[service.DesiredState]int{
+		service.SVCRun:  3,
+		service.SVCStop: 3,
+	})
+}
@@ -44,7 +44,7 @@ type StrategyService struct {
 	svc *zkservice.ServiceNode
 }
 
-func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat strategy.Strategy, facade *facade.Facade) (string, error) {
+func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat strategy.Strategy, facade *facade.Facade, strictCPU bool) (string, error) {
 
 	glog.V(2).Infof("Applying %s strategy for service %s", strat.Name(), sn.ID)
 
@@ -73,6 +73,10 @@ func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat stra
 	shosts := []strategy.Host{}
 	for _, h := range hostmap {
 		glog.V(2).Infof("Host %s is running %d service instances", h.HostID(), len(h.services))
+		if !h.fitsCPU(sn, strictCPU) {
+			glog.V(2).Infof("Host %s does not have the cpus for service %s", h.HostID(), sn.ID)
+			continue
+		}
 		shosts = append(shosts, h)
 	}
 	if result, err := strat.SelectHost(&StrategyService{sn}, shosts); result == nil || err != nil {
@@ -84,6 +88,34 @@ func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat stra
 	}
 }
 
+// fitsCPU returns false if the host does not have the cpus the service is
+// pinned to or, in strict mode, if the cores committed to the host's running
+// instances and the service would exceed the cores on the host.
+func (h *StrategyHost) fitsCPU(sn *zkservice.ServiceNode, strict bool) bool {
+	if cpus, _ := servicedefinition.ParseCPUSet(sn.CPUSet); len(cpus) > 0 && cpus[len(cpus)-1] >= h.host.Cores {
+		return false
+	}
+	if !strict {
+		return true
+	}
+	committed := committedCores(sn.CPUQuota, sn.CPUCommitment)
+	for _, s := range h.services {
+		if rs, ok := s.(*StrategyRunningService); ok {
+			committed += committedCores(rs.svc.CPUQuota, rs.svc.CPUCommitment)
+		}
+	}
+	return committed <= float64(h.host.Cores)
+}
+
+// committedCores returns the cores reserved by an instance, which is its hard
+// cpu quota if it has one, otherwise its cpu commitment.
+func committedCores(quota float64, commitment int) float64 {
+	if quota > 0 {
+		return quota
+	}
+	return float64(commitment)
+}
+
 // Implement everything
 
 func (h *StrategyHost) HostID() string {
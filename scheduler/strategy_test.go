@@ -0,0 +1,58 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/scheduler/strategy"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+func TestStrategyHost_FitsCPU(t *testing.T) {
+	h := &StrategyHost{
+		host: host.Host{ID: "host1", Cores: 4},
+		services: []strategy.ServiceConfig{
+			&StrategyRunningService{service.StrategyInstance{ServiceID: "a", CPUCommitment: 1}},
+			&StrategyRunningService{service.StrategyInstance{ServiceID: "b", CPUCommitment: 1, CPUQuota: 1.5}},
+		},
+	}
+
+	// 2.5 cores are committed, leaving room for 1.5
+	sn := &zkservice.ServiceNode{ID: "c", CPUQuota: 1.5}
+	if !h.fitsCPU(sn, true) {
+		t.Errorf("Expected service to fit on host")
+	}
+	sn = &zkservice.ServiceNode{ID: "c", CPUCommitment: 2}
+	if h.fitsCPU(sn, true) {
+		t.Errorf("Expected service not to fit on host in strict mode")
+	}
+	if !h.fitsCPU(sn, false) {
+		t.Errorf("Expected service to fit on host when not strict")
+	}
+
+	// pinned cpus must exist on the host
+	sn = &zkservice.ServiceNode{ID: "c", CPUSet: "2-3"}
+	if !h.fitsCPU(sn, false) {
+		t.Errorf("Expected pinned service to fit on host")
+	}
+	sn = &zkservice.ServiceNode{ID: "c", CPUSet: "0,4"}
+	if h.fitsCPU(sn, false) {
+		t.Errorf("Expected pinned service not to fit on host")
+	}
+}
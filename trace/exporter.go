@@ -0,0 +1,261 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Exporter receives finished spans
+type Exporter interface {
+	Export(*Span)
+}
+
+var (
+	exporterMu sync.RWMutex
+	exporter   Exporter
+)
+
+// SetExporter sets where finished spans are sent.  A nil exporter discards
+// them, and turns off the propagation of traces over RPC; trace ids are still
+// logged.
+func SetExporter(e Exporter) {
+	exporterMu.Lock()
+	defer exporterMu.Unlock()
+	exporter = e
+}
+
+// Enabled returns true if tracing is configured, i.e. an exporter is set
+func Enabled() bool {
+	return getExporter() != nil
+}
+
+func getExporter() Exporter {
+	exporterMu.RLock()
+	defer exporterMu.RUnlock()
+	return exporter
+}
+
+const (
+	batchSize     = 100
+	bufferSize    = 1024
+	flushInterval = 2 * time.Second
+)
+
+// CollectorExporter posts spans to an OpenTelemetry collector using the
+// OTLP/HTTP JSON encoding.  Spans are batched in the background and dropped
+// if the collector cannot keep up.
+type CollectorExporter struct {
+	url     string
+	service string
+	client  *http.Client
+	spans   chan *Span
+	flush   chan chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewCollectorExporter starts an exporter that posts to the collector's
+// traces endpoint, e.g. http://localhost:4318/v1/traces.  The service name
+// identifies this process in the collector.
+func NewCollectorExporter(url, service string) *CollectorExporter {
+	e := &CollectorExporter{
+		url:     url,
+		service: service,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		spans:   make(chan *Span, bufferSize),
+		flush:   make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// Export queues a span to be sent to the collector
+func (e *CollectorExporter) Export(s *Span) {
+	select {
+	case e.spans <- s:
+	default:
+		plog.WithField("span", s.Name).Debug("Trace buffer full, dropping span")
+	}
+}
+
+// Close sends any queued spans and stops the exporter
+func (e *CollectorExporter) Close() {
+	e.once.Do(func() {
+		ack := make(chan struct{})
+		e.flush <- ack
+		<-ack
+		close(e.done)
+	})
+}
+
+func (e *CollectorExporter) run() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	var batch []*Span
+	send := func() {
+		if len(batch) > 0 {
+			e.post(batch)
+			batch = nil
+		}
+	}
+	for {
+		select {
+		case s := <-e.spans:
+			if batch = append(batch, s); len(batch) >= batchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case ack := <-e.flush:
+			for drained := false; !drained; {
+				select {
+				case s := <-e.spans:
+					batch = append(batch, s)
+				default:
+					drained = true
+				}
+			}
+			send()
+			close(ack)
+		case <-e.done:
+			return
+		}
+	}
+}
+
+func (e *CollectorExporter) post(batch []*Span) {
+	data, err := json.Marshal(encodeSpans(e.service, batch))
+	if err != nil {
+		plog.WithError(err).Warn("Could not encode trace spans")
+		return
+	}
+	logger := plog.WithField("url", e.url)
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		logger.WithError(err).Debug("Could not send spans to trace collector")
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		logger.WithField("status", resp.Status).Debug("Trace collector rejected spans")
+	}
+}
+
+// OTLP/HTTP JSON payload, limited to the fields serviced records
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// status code for a span that failed
+const otlpStatusError = 2
+
+func encodeSpans(service string, batch []*Span) otlpRequest {
+	spans := make([]otlpSpan, len(batch))
+	for i, s := range batch {
+		s.mu.Lock()
+		spans[i] = otlpSpan{
+			TraceID:           s.Context.TraceID,
+			SpanID:            s.Context.SpanID,
+			ParentSpanID:      s.ParentID,
+			Name:              s.Name,
+			StartTimeUnixNano: unixNano(s.StartTime),
+			EndTimeUnixNano:   unixNano(s.EndTime),
+			Attributes:        encodeAttributes(s.Attributes),
+		}
+		if s.Error != "" {
+			spans[i].Status = &otlpStatus{Code: otlpStatusError, Message: s.Error}
+		}
+		s.mu.Unlock()
+	}
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: encodeAttributes(map[string]string{"service.name": service}),
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{Scope: otlpScope{Name: "serviced"}, Spans: spans},
+				},
+			},
+		},
+	}
+}
+
+func encodeAttributes(attrs map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]otlpAttribute, len(keys))
+	for i, k := range keys {
+		result[i] = otlpAttribute{Key: k, Value: otlpValue{StringValue: attrs[k]}}
+	}
+	return result
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
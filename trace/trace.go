@@ -0,0 +1,217 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trace follows a request across the CLI, REST, RPC and agent
+// boundaries.  A trace is identified by the W3C traceparent carried with the
+// request; each hop records a span that can be exported to an OpenTelemetry
+// collector.
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/logging"
+)
+
+var plog = logging.PackageLogger()
+
+// SpanContext identifies a span within a trace
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+}
+
+// IsValid returns true if the context identifies a span
+func (sc SpanContext) IsValid() bool {
+	return isID(sc.TraceID, 32) && isID(sc.SpanID, 16)
+}
+
+// String returns the context as a W3C traceparent value, or an empty string
+// if the context is not valid.
+func (sc SpanContext) String() string {
+	if !sc.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID)
+}
+
+// Parse reads a W3C traceparent value.  A malformed value returns an invalid
+// context, which starts a new trace when used as a parent.
+func Parse(traceparent string) SpanContext {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) != 4 || !isHex(parts[0], 2) || !isHex(parts[3], 2) {
+		return SpanContext{}
+	}
+	sc := SpanContext{TraceID: parts[1], SpanID: parts[2]}
+	if !sc.IsValid() {
+		return SpanContext{}
+	}
+	return sc
+}
+
+// isHex returns true if s is n lowercase hex digits
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isID returns true if s is an n digit id; ids of all zeros are invalid
+func isID(s string, n int) bool {
+	return isHex(s, n) && strings.Trim(s, "0") != ""
+}
+
+func newID(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// fall back to the clock rather than dropping the trace
+		plog.WithError(err).Debug("Could not read random bytes for trace id")
+		for i := range b {
+			b[i] = byte(time.Now().UnixNano() >> uint(8*(i%8)))
+		}
+	}
+	return hex.EncodeToString(b)
+}
+
+// Carrier is implemented by request objects that carry a trace across a
+// process boundary.
+type Carrier interface {
+	TraceContext() SpanContext
+	SetTraceContext(SpanContext)
+}
+
+// Span is a timed operation within a trace.  Methods on a nil span do
+// nothing, so callers need not check whether a request is traced.
+type Span struct {
+	Name       string
+	Context    SpanContext
+	ParentID   string
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes map[string]string
+	Error      string
+
+	mu       sync.Mutex
+	finished bool
+}
+
+// Start begins a span.  If the parent is not valid, the span is the root of
+// a new trace.
+func Start(name string, parent SpanContext) *Span {
+	s := &Span{
+		Name:       name,
+		StartTime:  time.Now(),
+		Attributes: make(map[string]string),
+	}
+	if parent.IsValid() {
+		s.Context.TraceID = parent.TraceID
+		s.ParentID = parent.SpanID
+	} else {
+		s.Context.TraceID = newID(16)
+	}
+	s.Context.SpanID = newID(8)
+	return s
+}
+
+// Follow begins a span only if the parent is valid, so that work done for
+// untraced requests stays untraced.
+func Follow(name string, parent SpanContext) *Span {
+	if !parent.IsValid() {
+		return nil
+	}
+	return Start(name, parent)
+}
+
+// TraceParent returns the span's context as a W3C traceparent value
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return ""
+	}
+	return s.Context.String()
+}
+
+// SetAttribute records a key/value pair on the span
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// Fields returns the log fields that correlate a log message with the span
+func (s *Span) Fields() logrus.Fields {
+	if s == nil {
+		return logrus.Fields{}
+	}
+	return logrus.Fields{
+		"traceid": s.Context.TraceID,
+		"spanid":  s.Context.SpanID,
+	}
+}
+
+// Finish ends the span and hands it to the exporter.  Finishing a span more
+// than once has no effect.
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.finished = true
+	s.EndTime = time.Now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	s.mu.Unlock()
+
+	if e := getExporter(); e != nil {
+		e.Export(s)
+	}
+}
+
+var (
+	defaultMu  sync.RWMutex
+	defaultCtx SpanContext
+)
+
+// SetDefault sets the trace context used by outbound requests that do not
+// carry their own, e.g. the root span of a CLI command.
+func SetDefault(sc SpanContext) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultCtx = sc
+}
+
+// Default returns the process trace context set by SetDefault
+func Default() SpanContext {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultCtx
+}
@@ -0,0 +1,155 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	sc := SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	value := sc.String()
+	if value != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Fatalf("unexpected traceparent %q", value)
+	}
+	if parsed := Parse(value); parsed != sc {
+		t.Errorf("expected %+v, got %+v", sc, parsed)
+	}
+
+	for _, bad := range []string{
+		"",
+		"garbage",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+	} {
+		if Parse(bad).IsValid() {
+			t.Errorf("expected %q to be invalid", bad)
+		}
+	}
+	if (SpanContext{}).String() != "" {
+		t.Errorf("expected an invalid context to have no traceparent")
+	}
+}
+
+func TestStart(t *testing.T) {
+	root := Start("root", SpanContext{})
+	if !root.Context.IsValid() {
+		t.Fatalf("expected a valid root context, got %+v", root.Context)
+	}
+	if root.ParentID != "" {
+		t.Errorf("expected root span to have no parent")
+	}
+
+	child := Start("child", root.Context)
+	if child.Context.TraceID != root.Context.TraceID {
+		t.Errorf("expected child to share trace %s, got %s", root.Context.TraceID, child.Context.TraceID)
+	}
+	if child.ParentID != root.Context.SpanID {
+		t.Errorf("expected parent %s, got %s", root.Context.SpanID, child.ParentID)
+	}
+	if child.Context.SpanID == root.Context.SpanID {
+		t.Errorf("expected child to have its own span id")
+	}
+	if fields := child.Fields(); fields["traceid"] != root.Context.TraceID {
+		t.Errorf("unexpected log fields %v", fields)
+	}
+}
+
+type recorder struct {
+	spans []*Span
+}
+
+func (r *recorder) Export(s *Span) {
+	r.spans = append(r.spans, s)
+}
+
+func TestFinish(t *testing.T) {
+	r := &recorder{}
+	SetExporter(r)
+	defer SetExporter(nil)
+
+	s := Start("op", SpanContext{})
+	s.Finish(errors.New("failed"))
+	s.Finish(nil)
+	if len(r.spans) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(r.spans))
+	}
+	if s.Error != "failed" {
+		t.Errorf("expected error to be recorded, got %q", s.Error)
+	}
+	if s.EndTime.Before(s.StartTime) {
+		t.Errorf("span ended before it started")
+	}
+}
+
+func TestCollectorExporter(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("unexpected content type %q", ct)
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		bodies <- data
+	}))
+	defer server.Close()
+
+	e := NewCollectorExporter(server.URL, "serviced-test")
+	s := Start("op", SpanContext{})
+	s.SetAttribute("serviceid", "svc1")
+	s.Finish(nil)
+	e.Export(s)
+	e.Close()
+
+	var req otlpRequest
+	if err := json.Unmarshal(<-bodies, &req); err != nil {
+		t.Fatalf("could not decode payload: %s", err)
+	}
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected payload %+v", req)
+	}
+	if attr := req.ResourceSpans[0].Resource.Attributes; len(attr) != 1 || attr[0].Value.StringValue != "serviced-test" {
+		t.Errorf("unexpected resource attributes %+v", attr)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].TraceID != s.Context.TraceID || spans[0].Name != "op" {
+		t.Errorf("unexpected span %+v", spans[0])
+	}
+	if len(spans[0].Attributes) != 1 || spans[0].Attributes[0].Key != "serviceid" {
+		t.Errorf("unexpected span attributes %+v", spans[0].Attributes)
+	}
+	if spans[0].Status != nil {
+		t.Errorf("expected no error status, got %+v", spans[0].Status)
+	}
+}
+
+func TestNilSpan(t *testing.T) {
+	var s *Span
+	s.SetAttribute("key", "value")
+	s.Finish(nil)
+	if len(s.Fields()) != 0 {
+		t.Errorf("expected no log fields for a nil span")
+	}
+}
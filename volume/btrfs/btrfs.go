@@ -14,6 +14,7 @@
 package btrfs
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -154,6 +155,27 @@ func (d *BtrfsDriver) Create(volumeName string) (volume.Volume, error) {
 	return d.Get(volumeName)
 }
 
+// Clone implements volume.Driver.Clone
+func (d *BtrfsDriver) Clone(snapshotID, volumeName string) (volume.Volume, error) {
+	src, err := d.GetTenant(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	d.Lock()
+	defer d.Unlock()
+	if d.Exists(volumeName) {
+		return nil, volume.ErrVolumeExists
+	}
+	// a writable snapshot of the read-only snapshot becomes the new volume
+	srcPath := src.(*BtrfsVolume).snapshotPath(snapshotID)
+	vdir := path.Join(d.root, volumeName)
+	if output, err := volume.RunBtrFSCmd(d.sudoer, "subvolume", "snapshot", srcPath, vdir); err != nil {
+		glog.Errorf("Could not clone snapshot %s to volume at %s: %s (%s)", snapshotID, vdir, output, err)
+		return nil, ErrBtrfsCreatingSubvolume
+	}
+	return d.Get(volumeName)
+}
+
 func (d *BtrfsDriver) poolDir() string {
 	return filepath.Join(d.root, ".btrfs")
 }
@@ -615,6 +637,32 @@ func (v *BtrfsVolume) Export(label, parent string, writer io.Writer, excludes []
 	return nil
 }
 
+// Diff implements volume.Volume.Diff
+func (v *BtrfsVolume) Diff(label, toLabel string) ([]volume.FileChange, error) {
+	if exists, err := v.snapshotExists(label); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, volume.ErrSnapshotDoesNotExist
+	}
+	from := v.snapshotPath(label)
+	if toLabel == "" {
+		// the volume itself is not read-only, so it cannot be sent
+		return volume.DiffDirectories(from, v.Path())
+	}
+	if exists, err := v.snapshotExists(toLabel); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, volume.ErrSnapshotDoesNotExist
+	}
+	to := v.snapshotPath(toLabel)
+	dump, err := runBtrfsDump(v.sudoer, from, to)
+	if err != nil {
+		glog.Errorf("Could not compare snapshot %s with %s: %s", label, toLabel, err)
+		return nil, err
+	}
+	return volume.DiffPaths(from, to, parseBtrfsDump(dump))
+}
+
 // Import implements volume.Volume.Import
 func (v *BtrfsVolume) Import(label string, reader io.Reader) error {
 	if exists, err := v.snapshotExists(label); err != nil {
@@ -692,6 +740,133 @@ func runBtrfsRecv(reader io.Reader, sudoer bool, path string) error {
 	return nil
 }
 
+// runBtrfsDump describes the changes from parentpath to path, as the
+// commands of a send stream without file data printed by btrfs receive --dump
+func runBtrfsDump(sudoer bool, parentpath, path string) ([]byte, error) {
+	sendArgs := []string{"btrfs", "send", "--no-data", "-p", parentpath, path}
+	if sudoer {
+		sendArgs = append([]string{"sudo", "-n"}, sendArgs...)
+	}
+	dumpArgs := []string{"btrfs", "receive", "--dump"}
+	send := exec.Command(sendArgs[0], sendArgs[1:]...)
+	dump := exec.Command(dumpArgs[0], dumpArgs[1:]...)
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	output := &bytes.Buffer{}
+	send.Stdout = w
+	send.Stderr = os.Stderr
+	dump.Stdin = r
+	dump.Stdout = output
+	dump.Stderr = os.Stderr
+	if err := dump.Start(); err != nil {
+		r.Close()
+		w.Close()
+		glog.Errorf("Error while running command %+v: %s", dumpArgs, err)
+		return nil, volume.ErrBtrfsCommand
+	}
+	sendErr := send.Start()
+	r.Close()
+	w.Close()
+	if sendErr == nil {
+		sendErr = send.Wait()
+	}
+	dumpErr := dump.Wait()
+	if sendErr != nil {
+		glog.Errorf("Error while running command %+v: %s", sendArgs, sendErr)
+		return nil, volume.ErrBtrfsCommand
+	} else if dumpErr != nil {
+		glog.Errorf("Error while running command %+v: %s", dumpArgs, dumpErr)
+		return nil, volume.ErrBtrfsCommand
+	}
+	return output.Bytes(), nil
+}
+
+// parseBtrfsDump returns the paths, relative to the snapshot, of the files
+// that a send stream dump links, unlinks, renames or writes to.  New files
+// are created under temporary names and then renamed, so only their final
+// names are kept by the caller.
+func parseBtrfsDump(output []byte) []string {
+	paths := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := splitEscapedFields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "rename":
+			for _, field := range fields[2:] {
+				if strings.HasPrefix(field, "dest=") {
+					if name := dumpPath(strings.TrimPrefix(field, "dest=")); name != "" {
+						paths = append(paths, name)
+					}
+				}
+			}
+		case "link", "unlink", "truncate", "update_extent", "write", "clone":
+		default:
+			continue
+		}
+		if name := dumpPath(fields[1]); name != "" {
+			paths = append(paths, name)
+		}
+	}
+	return paths
+}
+
+// dumpPath strips the leading ./<snapshot>/ from a path in a send stream
+// dump
+func dumpPath(p string) string {
+	p = strings.TrimPrefix(p, "./")
+	if i := strings.Index(p, "/"); i >= 0 {
+		return p[i+1:]
+	}
+	return ""
+}
+
+// splitEscapedFields splits a line of a send stream dump by whitespace,
+// unescaping the backslash escapes it uses for special characters in paths
+func splitEscapedFields(line string) []string {
+	escapes := map[byte]byte{'a': '\a', 'b': '\b', 'e': 0x1b, 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t', 'v': '\v'}
+	fields := []string{}
+	field := []byte{}
+	inField := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+3 < len(line) && isOctal(line[i+1]) && isOctal(line[i+2]) && isOctal(line[i+3]):
+			n, _ := strconv.ParseUint(line[i+1:i+4], 8, 8)
+			field = append(field, byte(n))
+			i += 3
+		case c == '\\' && i+1 < len(line):
+			i++
+			if e, ok := escapes[line[i]]; ok {
+				field = append(field, e)
+			} else {
+				field = append(field, line[i])
+			}
+		case c == ' ' || c == '\t':
+			if inField {
+				fields = append(fields, string(field))
+				field = []byte{}
+				inField = false
+			}
+			continue
+		default:
+			field = append(field, c)
+		}
+		inField = true
+	}
+	if inField {
+		fields = append(fields, string(field))
+	}
+	return fields
+}
+
+func isOctal(c byte) bool {
+	return c >= '0' && c <= '7'
+}
+
 // Parse output of btrfs fi df command. Older btrfs does not support -b/--raw arg.
 // output without -b:
 /*
@@ -62,6 +62,14 @@ func (s *BtrfsSuite) TestBtrfsSnapshots(c *C) {
 	drivertest.DriverTestSnapshots(c, "btrfs", s.root, btrfsArgs)
 }
 
+func (s *BtrfsSuite) TestBtrfsClone(c *C) {
+	drivertest.DriverTestClone(c, "btrfs", s.root, btrfsArgs)
+}
+
+func (s *BtrfsSuite) TestBtrfsDiff(c *C) {
+	drivertest.DriverTestDiff(c, "btrfs", s.root, btrfsArgs)
+}
+
 func (s *BtrfsSuite) TestBtrfsBadSnapshots(c *C) {
 	badsnapshot := func(label string, vol volume.Volume) error {
 		//create an invalid snapshot by snapshotting and then writing garbage to .SnapshotInfo
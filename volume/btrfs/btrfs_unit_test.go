@@ -236,3 +236,25 @@ func TestParseDF(t *testing.T) {
 		assert.Equal(t, result, tc.out, fmt.Sprintf("%s: %s", tc.label, tc.outmsg))
 	}
 }
+
+func TestParseBtrfsDump(t *testing.T) {
+	output := []byte(`snapshot        ./snap2                         uuid=4d4c1ad3 transid=12 parent_uuid=9f1b2ce0 parent_transid=10
+utimes          ./snap2/                        atime=2017-05-01T10:00:00+0000 mtime=2017-05-01T10:00:00+0000 ctime=2017-05-01T10:00:00+0000
+mkfile          ./snap2/o257-12-0
+rename          ./snap2/o257-12-0               dest=./snap2/dir/new\ file.txt
+truncate        ./snap2/dir/new\ file.txt       size=12
+chown           ./snap2/dir/new\ file.txt       gid=0 uid=0
+update_extent   ./snap2/modified.txt            offset=0 len=4096
+unlink          ./snap2/removed.txt
+rmdir           ./snap2/olddir
+chmod           ./snap2/chmodded.txt            mode=644
+`)
+	expected := []string{"dir/new file.txt", "o257-12-0", "dir/new file.txt", "modified.txt", "removed.txt"}
+	assert.Equal(t, expected, parseBtrfsDump(output))
+}
+
+func TestSplitEscapedFields(t *testing.T) {
+	assert.Equal(t, []string{"rename", "./a b", "dest=./c\td"}, splitEscapedFields(`rename  ./a\ b   dest=./c\td`))
+	assert.Equal(t, []string{"unlink", "./snap/é"}, splitEscapedFields(`unlink ./snap/\303\251`))
+	assert.Equal(t, []string{}, splitEscapedFields("   "))
+}
@@ -232,8 +232,38 @@ func (d *DeviceMapperDriver) Create(volumeName string) (volume.Volume, error) {
 		return nil, volume.ErrVolumeExists
 	}
 
+	return d.create(volumeName, "")
+}
+
+// Clone implements volume.Driver.Clone
+func (d *DeviceMapperDriver) Clone(snapshotID, volumeName string) (volume.Volume, error) {
+	glog.V(2).Infof("Clone() (%s, %s) START", snapshotID, volumeName)
+	defer glog.V(2).Infof("Clone() (%s, %s) END", snapshotID, volumeName)
+
+	// Do not create if the device already exists
+	if d.Exists(volumeName) {
+		return nil, volume.ErrVolumeExists
+	}
+
+	src, err := d.GetTenant(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	snapDeviceHash, err := src.(*DeviceMapperVolume).Metadata.LookupSnapshotDevice(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new volume's device is a thin snapshot of the snapshot device
+	d.DeviceSet.CheckTransactionID()
+	return d.create(volumeName, snapDeviceHash)
+}
+
+// create allocates a new device, based on the given head device if it is
+// set, and mounts it as the volume
+func (d *DeviceMapperDriver) create(volumeName, headDeviceHash string) (volume.Volume, error) {
 	// Create a new device
-	deviceHash, err := d.addDevice("")
+	deviceHash, err := d.addDevice(headDeviceHash)
 	if err != nil {
 		return nil, err
 	}
@@ -1271,32 +1301,11 @@ func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excl
 		return volume.ErrSnapshotDoesNotExist
 	}
 	label = v.rawSnapshotLabel(label)
-	mountpoint, err := ioutil.TempDir("", "serviced-export-volume-")
-	if err != nil {
-		return err
-	}
-	defer os.RemoveAll(mountpoint)
-	deviceHash, err := v.Metadata.LookupSnapshotDevice(label)
+	mountpoint, release, err := v.mountSnapshot(label, "serviced-export-volume-")
 	if err != nil {
 		return err
 	}
-	glog.V(2).Infof("Mounting temporary export device %s", deviceHash)
-	if err := v.driver.DeviceSet.MountDevice(deviceHash, mountpoint, label); err != nil {
-		return err
-	}
-	defer func(d *DeviceMapperDriver, deviceHash, mountpoint string) {
-		// We use the provided UnmountDevice func here, rather than our own
-		// unmount(), because we DO care about Docker's internal bookkeeping
-		// here. Without this, DeviceSet.DeleteDevice will fail.
-		if err := d.DeviceSet.UnmountDevice(deviceHash, mountpoint); err != nil {
-			glog.V(2).Infof("Error unmounting %s (device: %s): %s", mountpoint, deviceHash, err)
-		}
-		d.DeviceSet.Lock()
-		if err := d.DeactivateDevice(deviceHash); err != nil {
-			glog.V(2).Infof("Error deactivating device %s: %s", deviceHash, err)
-		}
-		d.DeviceSet.Unlock()
-	}(v.driver, deviceHash, mountpoint)
+	defer release()
 
 	tarOut := tar.NewWriter(writer)
 
@@ -1346,6 +1355,68 @@ func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excl
 	return tarOut.Close()
 }
 
+// Diff implements volume.Volume.Diff
+func (v *DeviceMapperVolume) Diff(label, toLabel string) ([]volume.FileChange, error) {
+	glog.V(2).Infof("Diff() (%s) START", v.name)
+	defer glog.V(2).Infof("Diff() (%s) END", v.name)
+	if !v.snapshotExists(label) {
+		return nil, volume.ErrSnapshotDoesNotExist
+	}
+	from, release, err := v.mountSnapshot(v.rawSnapshotLabel(label), "serviced-diff-volume-")
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	to := v.Path()
+	if toLabel != "" {
+		if !v.snapshotExists(toLabel) {
+			return nil, volume.ErrSnapshotDoesNotExist
+		}
+		var releaseTo func()
+		to, releaseTo, err = v.mountSnapshot(v.rawSnapshotLabel(toLabel), "serviced-diff-volume-")
+		if err != nil {
+			return nil, err
+		}
+		defer releaseTo()
+	}
+	return volume.DiffDirectories(from, to)
+}
+
+// mountSnapshot mounts the device of the snapshot <label> at a temporary
+// directory, and returns the directory and a function that unmounts it.
+func (v *DeviceMapperVolume) mountSnapshot(label, prefix string) (string, func(), error) {
+	mountpoint, err := ioutil.TempDir("", prefix)
+	if err != nil {
+		return "", nil, err
+	}
+	deviceHash, err := v.Metadata.LookupSnapshotDevice(label)
+	if err != nil {
+		os.RemoveAll(mountpoint)
+		return "", nil, err
+	}
+	glog.V(2).Infof("Mounting temporary snapshot device %s", deviceHash)
+	if err := v.driver.DeviceSet.MountDevice(deviceHash, mountpoint, label); err != nil {
+		os.RemoveAll(mountpoint)
+		return "", nil, err
+	}
+	release := func() {
+		d := v.driver
+		// We use the provided UnmountDevice func here, rather than our own
+		// unmount(), because we DO care about Docker's internal bookkeeping
+		// here. Without this, DeviceSet.DeleteDevice will fail.
+		if err := d.DeviceSet.UnmountDevice(deviceHash, mountpoint); err != nil {
+			glog.V(2).Infof("Error unmounting %s (device: %s): %s", mountpoint, deviceHash, err)
+		}
+		d.DeviceSet.Lock()
+		if err := d.DeactivateDevice(deviceHash); err != nil {
+			glog.V(2).Infof("Error deactivating device %s: %s", deviceHash, err)
+		}
+		d.DeviceSet.Unlock()
+		os.RemoveAll(mountpoint)
+	}
+	return mountpoint, release, nil
+}
+
 func (d *DeviceMapperDriver) Status() (volume.Status, error) {
 	glog.V(2).Info("devicemapper.Status()")
 	dockerStatus := d.DeviceSet.Status()
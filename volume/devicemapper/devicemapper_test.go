@@ -117,6 +117,14 @@ func (s *DeviceMapperSuite) TestDeviceMapperSnapshots(c *C) {
 	drivertest.DriverTestSnapshots(c, "devicemapper", "", devmapArgs)
 }
 
+func (s *DeviceMapperSuite) TestDeviceMapperClone(c *C) {
+	drivertest.DriverTestClone(c, "devicemapper", "", devmapArgs)
+}
+
+func (s *DeviceMapperSuite) TestDeviceMapperDiff(c *C) {
+	drivertest.DriverTestDiff(c, "devicemapper", "", devmapArgs)
+}
+
 func (s *DeviceMapperSuite) TestDeviceMapperSnapshotTags(c *C) {
 	drivertest.DriverTestSnapshotTags(c, "devicemapper", "", devmapArgs)
 }
@@ -0,0 +1,155 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volume
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+)
+
+// ChangeType describes how a file differs between two states of a volume
+type ChangeType string
+
+const (
+	// FileAdded is a file that only exists in the newer state
+	FileAdded ChangeType = "added"
+	// FileRemoved is a file that only exists in the older state
+	FileRemoved ChangeType = "removed"
+	// FileModified is a file whose contents differ between the two states
+	FileModified ChangeType = "modified"
+)
+
+// FileChange is a file that differs between two states of a volume.  Sizes
+// are in bytes, and are 0 in the state where the file does not exist.
+type FileChange struct {
+	Path    string
+	Change  ChangeType
+	Size    int64
+	OldSize int64
+}
+
+// FileChanges is a FileChange array sortable by path
+type FileChanges []FileChange
+
+func (p FileChanges) Len() int {
+	return len(p)
+}
+
+func (p FileChanges) Less(i, j int) bool {
+	return p[i].Path < p[j].Path
+}
+
+func (p FileChanges) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
+
+// DiffDirectories returns the files that differ going from the directory
+// <from> to the directory <to>, sorted by path.  Files are compared by type,
+// size and modification time.  Directories themselves are not reported.
+func DiffDirectories(from, to string) ([]FileChange, error) {
+	fromFiles, err := listFiles(from)
+	if err != nil {
+		return nil, err
+	}
+	toFiles, err := listFiles(to)
+	if err != nil {
+		return nil, err
+	}
+	changes := FileChanges{}
+	for name, toInfo := range toFiles {
+		fromInfo, ok := fromFiles[name]
+		if !ok {
+			changes = append(changes, FileChange{Path: name, Change: FileAdded, Size: toInfo.Size()})
+		} else if fromInfo.Mode() != toInfo.Mode() || fromInfo.Size() != toInfo.Size() || !fromInfo.ModTime().Equal(toInfo.ModTime()) {
+			changes = append(changes, FileChange{Path: name, Change: FileModified, Size: toInfo.Size(), OldSize: fromInfo.Size()})
+		}
+	}
+	for name, fromInfo := range fromFiles {
+		if _, ok := toFiles[name]; !ok {
+			changes = append(changes, FileChange{Path: name, Change: FileRemoved, OldSize: fromInfo.Size()})
+		}
+	}
+	sort.Sort(changes)
+	return changes, nil
+}
+
+// DiffPaths classifies paths, relative to the directories <from> and <to>,
+// that a driver found to have changed.  Paths that exist in only one of the
+// directories were added or removed, and those that exist in both were
+// modified.  Directories and paths that exist in neither are skipped.
+func DiffPaths(from, to string, paths []string) ([]FileChange, error) {
+	changes := FileChanges{}
+	seen := make(map[string]struct{})
+	for _, name := range paths {
+		name = filepath.Clean(name)
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		fromInfo, err := lstat(filepath.Join(from, name))
+		if err != nil {
+			return nil, err
+		}
+		toInfo, err := lstat(filepath.Join(to, name))
+		if err != nil {
+			return nil, err
+		}
+		if (fromInfo != nil && fromInfo.IsDir()) || (toInfo != nil && toInfo.IsDir()) {
+			continue
+		}
+		switch {
+		case fromInfo == nil && toInfo != nil:
+			changes = append(changes, FileChange{Path: name, Change: FileAdded, Size: toInfo.Size()})
+		case fromInfo != nil && toInfo == nil:
+			changes = append(changes, FileChange{Path: name, Change: FileRemoved, OldSize: fromInfo.Size()})
+		case fromInfo != nil && toInfo != nil:
+			changes = append(changes, FileChange{Path: name, Change: FileModified, Size: toInfo.Size(), OldSize: fromInfo.Size()})
+		}
+	}
+	sort.Sort(changes)
+	return changes, nil
+}
+
+// listFiles returns everything but the directories under root, by path
+// relative to root
+func listFiles(root string) (map[string]os.FileInfo, error) {
+	files := make(map[string]os.FileInfo)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if info.IsDir() {
+			return nil
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[name] = info
+		return nil
+	})
+	return files, err
+}
+
+// lstat returns nil if the path does not exist
+func lstat(path string) (os.FileInfo, error) {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if perr, ok := err.(*os.PathError); ok && perr.Err == syscall.ENOTDIR {
+		return nil, nil
+	}
+	return info, err
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package volume_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/control-center/serviced/volume"
+	. "gopkg.in/check.v1"
+)
+
+type DiffSuite struct{}
+
+var _ = Suite(&DiffSuite{})
+
+func (s *DiffSuite) setupDirectories(c *C) (string, string) {
+	from, to := c.MkDir(), c.MkDir()
+	then := time.Now().Add(-time.Hour)
+	for _, root := range []string{from, to} {
+		c.Assert(os.MkdirAll(filepath.Join(root, "dir"), 0755), IsNil)
+		c.Assert(ioutil.WriteFile(filepath.Join(root, "dir", "same"), []byte("same"), 0644), IsNil)
+		c.Assert(os.Chtimes(filepath.Join(root, "dir", "same"), then, then), IsNil)
+	}
+	c.Assert(ioutil.WriteFile(filepath.Join(from, "removed"), []byte("removed"), 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(from, "dir", "modified"), []byte("old"), 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(to, "dir", "modified"), []byte("newer"), 0644), IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(to, "newdir"), 0755), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(to, "newdir", "added"), []byte("added!"), 0644), IsNil)
+	return from, to
+}
+
+func (s *DiffSuite) TestDiffDirectories(c *C) {
+	from, to := s.setupDirectories(c)
+	changes, err := DiffDirectories(from, to)
+	c.Assert(err, IsNil)
+	c.Assert(changes, DeepEquals, []FileChange{
+		{Path: "dir/modified", Change: FileModified, Size: 5, OldSize: 3},
+		{Path: "newdir/added", Change: FileAdded, Size: 6},
+		{Path: "removed", Change: FileRemoved, OldSize: 7},
+	})
+}
+
+func (s *DiffSuite) TestDiffDirectories_NoDirectory(c *C) {
+	from := c.MkDir()
+	_, err := DiffDirectories(from, filepath.Join(from, "missing"))
+	c.Assert(err, NotNil)
+}
+
+func (s *DiffSuite) TestDiffPaths(c *C) {
+	from, to := s.setupDirectories(c)
+	paths := []string{"removed", "newdir/added", "dir/modified", "dir/modified", "newdir", "o257-12-0", "removed/child"}
+	changes, err := DiffPaths(from, to, paths)
+	c.Assert(err, IsNil)
+	c.Assert(changes, DeepEquals, []FileChange{
+		{Path: "dir/modified", Change: FileModified, Size: 5, OldSize: 3},
+		{Path: "newdir/added", Change: FileAdded, Size: 6},
+		{Path: "removed", Change: FileRemoved, OldSize: 7},
+	})
+}
@@ -319,6 +319,91 @@ func DriverTestSnapshots(c *C, drivername volume.DriverType, root string, args [
 	c.Assert(driver.Exists("Base"), Equals, false)
 }
 
+func DriverTestClone(c *C, drivername volume.DriverType, root string, args []string) {
+	driver := newDriver(c, drivername, root, args)
+	defer cleanup(c, driver)
+
+	vol := createBase(c, driver, "Base")
+	verifyBase(c, driver, vol)
+
+	err := vol.Snapshot("Snap", "snapshot-message-0", []string{})
+	c.Assert(err, IsNil)
+
+	// Change the active volume after the snapshot
+	writeExtra(c, driver, vol, "differentfile")
+
+	// Clone the snapshot into a new volume with the data of the snapshot
+	clone, err := driver.Clone("Base_Snap", "Clone")
+	c.Assert(err, IsNil)
+	c.Assert(clone.Name(), Equals, "Clone")
+	c.Assert(clone.Tenant(), Equals, "Clone")
+	verifyBase(c, driver, clone)
+
+	// The volumes are independent of each other
+	writeExtra(c, driver, clone, "clonefile")
+	verifyBaseWithExtra(c, driver, vol)
+	snaps, err := clone.Snapshots()
+	c.Assert(err, IsNil)
+	c.Assert(snaps, HasLen, 0)
+
+	// Clone into an existing volume
+	_, err = driver.Clone("Base_Snap", "Clone")
+	c.Assert(err, Equals, volume.ErrVolumeExists)
+
+	// Clone a snapshot that doesn't exist
+	_, err = driver.Clone("Base_Snap2", "Clone2")
+	c.Assert(err, NotNil)
+	c.Assert(driver.Exists("Clone2"), Equals, false)
+
+	c.Assert(driver.Remove("Clone"), IsNil)
+	c.Assert(driver.Remove("Base"), IsNil)
+	c.Assert(driver.Exists("Clone"), Equals, false)
+}
+
+func DriverTestDiff(c *C, drivername volume.DriverType, root string, args []string) {
+	driver := newDriver(c, drivername, root, args)
+	defer cleanup(c, driver)
+
+	vol := createBase(c, driver, "Base")
+	verifyBase(c, driver, vol)
+
+	err := vol.Snapshot("Snap", "snapshot-message-0", []string{})
+	c.Assert(err, IsNil)
+
+	// Add a file and change a file for the next snapshot
+	writeExtra(c, driver, vol, "differentfile")
+	err = ioutil.WriteFile(path.Join(vol.Path(), "a file"), []byte("Some other data"), 0222|os.ModeSetuid)
+	c.Assert(err, IsNil)
+	err = vol.Snapshot("Snap2", "snapshot-message-1", []string{})
+	c.Assert(err, IsNil)
+
+	// Remove a file after the last snapshot
+	err = os.Remove(path.Join(vol.Path(), "differentfile"))
+	c.Assert(err, IsNil)
+
+	changes, err := vol.Diff("Snap", "Snap2")
+	c.Assert(err, IsNil)
+	c.Assert(changes, DeepEquals, []volume.FileChange{
+		{Path: "a file", Change: volume.FileModified, Size: 15, OldSize: 9},
+		{Path: "differentfile", Change: volume.FileAdded, Size: 9},
+	})
+
+	// Compare with the current state of the volume
+	changes, err = vol.Diff("Snap2", "")
+	c.Assert(err, IsNil)
+	c.Assert(changes, DeepEquals, []volume.FileChange{
+		{Path: "differentfile", Change: volume.FileRemoved, OldSize: 9},
+	})
+
+	// Compare with a snapshot that doesn't exist
+	_, err = vol.Diff("Snap3", "")
+	c.Assert(err, Equals, volume.ErrSnapshotDoesNotExist)
+	_, err = vol.Diff("Snap", "Snap3")
+	c.Assert(err, Equals, volume.ErrSnapshotDoesNotExist)
+
+	c.Assert(driver.Remove("Base"), IsNil)
+}
+
 func DriverTestSnapshotTags(c *C, drivername volume.DriverType, root string, args []string) {
 	driver := newDriver(c, drivername, root, args)
 	defer cleanup(c, driver)
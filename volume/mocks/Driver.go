@@ -42,6 +42,14 @@ func (m *Driver) Create(volumeName string) (volume.Volume, error) {
 
 	return r0, r1
 }
+func (m *Driver) Clone(snapshotID string, volumeName string) (volume.Volume, error) {
+	ret := m.Called(snapshotID, volumeName)
+
+	r0 := ret.Get(0).(volume.Volume)
+	r1 := ret.Error(1)
+
+	return r0, r1
+}
 func (m *Driver) Remove(volumeName string) error {
 	ret := m.Called(volumeName)
 
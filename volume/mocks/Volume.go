@@ -161,6 +161,27 @@ func (_m *Volume) Rollback(label string) error {
 
 	return r0
 }
+func (_m *Volume) Diff(label string, toLabel string) ([]volume.FileChange, error) {
+	ret := _m.Called(label, toLabel)
+
+	var r0 []volume.FileChange
+	if rf, ok := ret.Get(0).(func(string, string) []volume.FileChange); ok {
+		r0 = rf(label, toLabel)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]volume.FileChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(label, toLabel)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *Volume) TagSnapshot(label string, tagName string) error {
 	ret := _m.Called(label, tagName)
 
@@ -147,6 +147,11 @@ func (d *NFSDriver) Create(volumeName string) (volume.Volume, error) {
 	return nil, ErrNotSupported
 }
 
+// Clone implements volume.Driver.Clone
+func (d *NFSDriver) Clone(snapshotID, volumeName string) (volume.Volume, error) {
+	return nil, ErrNotSupported
+}
+
 // Remove implements volume.Driver.Remove
 func (d *NFSDriver) Remove(volumeName string) error {
 	return ErrNotSupported
@@ -243,6 +248,11 @@ func (v *NFSVolume) Rollback(label string) (err error) {
 	return ErrNotSupported
 }
 
+// Diff implements volume.Volume.Diff
+func (v *NFSVolume) Diff(label, toLabel string) ([]volume.FileChange, error) {
+	return nil, ErrNotSupported
+}
+
 // Export implements volume.Volume.Export
 func (v *NFSVolume) Export(label, parent string, writer io.Writer, excludes []string) error {
 	return ErrNotSupported
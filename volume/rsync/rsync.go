@@ -108,6 +108,32 @@ func (d *RsyncDriver) Create(volumeName string) (volume.Volume, error) {
 	return d.Get(volumeName)
 }
 
+// Clone implements volume.Driver.Clone
+func (d *RsyncDriver) Clone(snapshotID, volumeName string) (volume.Volume, error) {
+	src, err := d.GetTenant(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	srcPath := src.(*RsyncVolume).snapshotPath(snapshotID)
+	if exists, err := volume.IsDir(srcPath); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, volume.ErrSnapshotDoesNotExist
+	}
+	vol, err := d.Create(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	rsync := exec.Command("rsync", "-a", srcPath+"/", vol.Path()+"/")
+	glog.V(0).Infof("About to execute: %s", rsync)
+	if output, err := rsync.CombinedOutput(); err != nil {
+		glog.Errorf("Could not copy snapshot %s to volume %s: %s", snapshotID, volumeName, string(output))
+		d.Remove(volumeName)
+		return nil, err
+	}
+	return vol, nil
+}
+
 // Remove implements volume.Driver.Remove
 func (d *RsyncDriver) Remove(volumeName string) error {
 	v, err := d.Get(volumeName)
@@ -648,6 +674,63 @@ func (v *RsyncVolume) Rollback(label string) (err error) {
 	return nil
 }
 
+// Diff implements volume.Volume.Diff
+func (v *RsyncVolume) Diff(label, toLabel string) ([]volume.FileChange, error) {
+	v.Lock()
+	defer v.Unlock()
+	from := v.snapshotPath(label)
+	if exists, err := volume.IsDir(from); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, volume.ErrSnapshotDoesNotExist
+	}
+	to := v.Path()
+	if toLabel != "" {
+		to = v.snapshotPath(toLabel)
+		if exists, err := volume.IsDir(to); err != nil {
+			return nil, err
+		} else if !exists {
+			return nil, volume.ErrSnapshotDoesNotExist
+		}
+	}
+	// a dry run of syncing the older state from the newer one itemizes the
+	// files that changed between them
+	rsync := exec.Command("rsync", "-a", "--dry-run", "--delete", "--out-format=%i %n", to+"/", from+"/")
+	glog.V(2).Infof("About to execute: %s", rsync)
+	output, err := rsync.Output()
+	if err != nil {
+		glog.Errorf("Could not compare %s with %s: %s", from, to, err)
+		return nil, err
+	}
+	return volume.DiffPaths(from, to, parseItemizedChanges(output))
+}
+
+// parseItemizedChanges returns the paths of the files that rsync would
+// create, update or delete, as itemized by --out-format="%i %n".  Directories
+// and files whose attributes alone would change are skipped.
+func parseItemizedChanges(output []byte) []string {
+	paths := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		// the itemized changes are 11 characters long, then the name follows
+		if len(line) < 13 || strings.HasSuffix(line, "/") {
+			continue
+		}
+		itemize, name := line[:11], line[12:]
+		switch itemize[0] {
+		case '*':
+			// *deleting
+		case '<', '>', 'c', 'h':
+			if itemize[1] == 'd' {
+				continue
+			}
+		default:
+			continue
+		}
+		paths = append(paths, name)
+	}
+	return paths
+}
+
 // Export implements volume.Volume.Export
 func (v *RsyncVolume) Export(label, parent string, writer io.Writer, excludes []string) error {
 	if len(excludes) > 0 {
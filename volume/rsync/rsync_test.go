@@ -61,6 +61,14 @@ func (s *RsyncSuite) TestRsyncSnapshots(c *C) {
 	drivertest.DriverTestSnapshots(c, "rsync", "", rsyncArgs)
 }
 
+func (s *RsyncSuite) TestRsyncClone(c *C) {
+	drivertest.DriverTestClone(c, "rsync", "", rsyncArgs)
+}
+
+func (s *RsyncSuite) TestRsyncDiff(c *C) {
+	drivertest.DriverTestDiff(c, "rsync", "", rsyncArgs)
+}
+
 func (s *RsyncSuite) TestRsyncSnapshotTags(c *C) {
 	drivertest.DriverTestSnapshotTags(c, "rsync", "", rsyncArgs)
 }
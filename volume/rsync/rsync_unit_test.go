@@ -70,3 +70,17 @@ func TestParseDF(t *testing.T) {
 		assert.Equal(t, result, tc.out, fmt.Sprintf("%s: %s", tc.label, tc.outmsg))
 	}
 }
+
+func TestParseItemizedChanges(t *testing.T) {
+	output := []byte(`*deleting   removed.txt
+*deleting   olddir/
+.d..t...... ./
+>f+++++++++ added.txt
+>f.st...... dir/modified file.txt
+.f...p..... chmodded.txt
+cL+++++++++ link
+cd+++++++++ newdir/
+`)
+	expected := []string{"removed.txt", "added.txt", "dir/modified file.txt", "link"}
+	assert.Equal(t, expected, parseItemizedChanges(output))
+}
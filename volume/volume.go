@@ -99,6 +99,10 @@ type Driver interface {
 	// Create creates a volume with the given name and returns it. The volume
 	// must not exist already.
 	Create(volumeName string) (Volume, error)
+	// Clone creates a volume with the given name from the data of a
+	// snapshot of another volume, and returns it. The volume must not exist
+	// already.
+	Clone(snapshotID, volumeName string) (Volume, error)
 	// Remove removes an existing device. If the device doesn't exist, the
 	// removal is a no-op
 	Remove(volumeName string) error
@@ -155,6 +159,10 @@ type Volume interface {
 	UntagSnapshot(tagName string) (string, error)
 	// GetSnapshotWithTag returns info about the snapshot with the given tag, or nil if there isn't one
 	GetSnapshotWithTag(tagName string) (*SnapshotInfo, error)
+	// Diff returns the files that differ going from the snapshot stored as
+	// <label> to the snapshot stored as <toLabel>, or to the current state of
+	// the volume if <toLabel> is empty
+	Diff(label, toLabel string) ([]FileChange, error)
 	// Export exports the snapshot stored as <label> to <filename>
 	Export(label, parent string, writer io.Writer, excludes []string) error
 	// Import imports the exported snapshot at <filename> as <label>
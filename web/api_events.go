@@ -0,0 +1,143 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/control-center/serviced/events"
+	"github.com/zenoss/go-json-rest"
+)
+
+// eventsPath is the path of the event stream.  It is served ahead of the
+// REST handler, whose response writer cannot be flushed.
+const eventsPath = "/api/v2/events"
+
+// eventKeepAlive is how often a comment is written to an idle event stream,
+// so that proxies do not time out the connection.
+var eventKeepAlive = 15 * time.Second
+
+var errStreamingUnsupported = errors.New("streaming is not supported by the connection")
+
+// serveEvents streams cluster state changes as server-sent events.  The
+// stream can be restricted with the tenant and service query parameters.  A
+// client resumes a stream by sending the id of the last event it received
+// in the Last-Event-ID header, or in the since query parameter; if the
+// events it missed are no longer available, a resync event is sent first.
+func (sc *ServiceConfig) serveEvents(shutdown <-chan interface{}, w http.ResponseWriter, r *http.Request) {
+	rw := rest.NewResponseWriter(w, false)
+	query := r.URL.Query()
+	filter := events.Filter{
+		TenantID:  query.Get("tenant"),
+		ServiceID: query.Get("service"),
+	}
+
+	// API tokens restricted to tenants may only follow the services of those
+	// tenants.  A tenant id is also the id of its root service.
+	params := make(map[string]string)
+	if filter.ServiceID != "" {
+		params["serviceId"] = filter.ServiceID
+	} else if filter.TenantID != "" {
+		params["serviceId"] = filter.TenantID
+	}
+	req := &rest.Request{Request: r, PathParams: params}
+	if _, present, ok := sc.apiTokenLogin(req); present && !ok {
+		restUnauthorized(&rw)
+		return
+	} else if !present && !loginOK(&rw, req) {
+		restUnauthorized(&rw)
+		return
+	}
+
+	if r.Method != "GET" {
+		rest.Error(&rw, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		restServerError(&rw, errStreamingUnsupported)
+		return
+	}
+
+	after := r.Header.Get("Last-Event-ID")
+	if after == "" {
+		after = query.Get("since")
+	}
+	sub, backlog, resumed := sc.facade.SubscribeEvents(after, filter)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !resumed {
+		writeEvent(w, events.Event{Type: events.Resync, Time: time.Now().UTC()})
+	}
+	for _, e := range backlog {
+		writeEvent(w, e)
+	}
+	flusher.Flush()
+
+	var closed <-chan bool
+	if notifier, ok := w.(http.CloseNotifier); ok {
+		closed = notifier.CloseNotify()
+	}
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case e, ok := <-sub.Events():
+			if !ok {
+				// The client fell behind; it reconnects with the id of the
+				// last event it received.
+				plog.WithField("filter", filter).Debug("Dropped slow event stream")
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		case <-shutdown:
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes an event in the server-sent event format.
+func writeEvent(w io.Writer, e events.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if e.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", e.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+	return err
+}
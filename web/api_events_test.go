@@ -0,0 +1,79 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/domain/apitoken"
+	"github.com/control-center/serviced/events"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestServeEvents_Unauthorized(c *C) {
+	s.mockAPIToken(nil)
+	request := s.buildAPITokenRequest("GET", eventsPath)
+
+	s.ctx.sc.serveEvents(nil, s.recorder, request.Request)
+	c.Assert(s.recorder.Code, Equals, http.StatusUnauthorized)
+	s.mockFacade.AssertNotCalled(c, "SubscribeEvents")
+}
+
+func (s *TestWebSuite) TestServeEvents_TenantScope(c *C) {
+	s.mockAPIToken(&apitoken.APIToken{ID: "abc", Name: "ci", Scope: apitoken.Scope{Tenants: []string{"tenant"}}, ExpiresAt: time.Now().Add(time.Hour)})
+	s.mockFacade.On("GetTenantID", mock.Anything, "other").Return("other", nil)
+
+	// a tenant-scoped token must filter by one of its tenants
+	request := s.buildAPITokenRequest("GET", eventsPath)
+	s.ctx.sc.serveEvents(nil, s.recorder, request.Request)
+	c.Assert(s.recorder.Code, Equals, http.StatusUnauthorized)
+
+	s.recorder = httptest.NewRecorder()
+	request = s.buildAPITokenRequest("GET", eventsPath+"?tenant=other")
+	s.ctx.sc.serveEvents(nil, s.recorder, request.Request)
+	c.Assert(s.recorder.Code, Equals, http.StatusUnauthorized)
+	s.mockFacade.AssertNotCalled(c, "SubscribeEvents")
+}
+
+func (s *TestWebSuite) TestServeEvents_Stream(c *C) {
+	s.mockAPIToken(&apitoken.APIToken{ID: "abc", Name: "ci", Scope: apitoken.Scope{ReadOnly: true}, ExpiresAt: time.Now().Add(time.Hour)})
+
+	bus := events.NewBus(10)
+	missed := bus.Publish(events.Event{Type: events.InstanceStarted, TenantID: "tenant", ServiceID: "svc"})
+	sub, backlog, resumed := bus.Subscribe("unknown", events.Filter{TenantID: "tenant"})
+	filter := events.Filter{TenantID: "tenant"}
+	s.mockFacade.On("SubscribeEvents", "unknown", filter).Return(sub, backlog, resumed)
+
+	live := bus.Publish(events.Event{Type: events.InstanceStopped, TenantID: "tenant", ServiceID: "svc"})
+	bus.Publish(events.Event{Type: events.InstanceStopped, TenantID: "other", ServiceID: "svc2"})
+	sub.Close()
+
+	request := s.buildAPITokenRequest("GET", eventsPath+"?tenant=tenant")
+	request.Header.Set("Last-Event-ID", "unknown")
+	s.ctx.sc.serveEvents(nil, s.recorder, request.Request)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	c.Assert(s.recorder.Header().Get("Content-Type"), Equals, "text/event-stream")
+	body := s.recorder.Body.String()
+	c.Assert(strings.HasPrefix(body, "event: resync\n"), Equals, true)
+	c.Assert(strings.Contains(body, "id: "+missed.ID+"\n"), Equals, false)
+	c.Assert(strings.Contains(body, "id: "+live.ID+"\nevent: instance.stopped\ndata: {"), Equals, true)
+	c.Assert(strings.Contains(body, "svc2"), Equals, false)
+}
@@ -0,0 +1,105 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/zzk/session"
+	"github.com/zenoss/go-json-rest"
+)
+
+// sessionDetails describes a UI session to an administrator
+type sessionDetails struct {
+	ID         string
+	User       string
+	CreatedAt  time.Time
+	AccessedAt time.Time
+	ExpiresAt  time.Time
+	Current    bool // The session making the request
+}
+
+func newSessionDetails(s *session.Session, currentID string) sessionDetails {
+	expires := s.AccessedAt.Add(sessionIdleTimeout)
+	if sessionMaxAge > 0 && s.CreatedAt.Add(sessionMaxAge).Before(expires) {
+		expires = s.CreatedAt.Add(sessionMaxAge)
+	}
+	return sessionDetails{
+		ID:         s.ID,
+		User:       s.User,
+		CreatedAt:  s.CreatedAt,
+		AccessedAt: s.AccessedAt,
+		ExpiresAt:  expires,
+		Current:    s.ID == currentID,
+	}
+}
+
+// currentSessionID returns the id of the session making the request, if any
+func currentSessionID(r *rest.Request) string {
+	cookie, err := r.Request.Cookie(sessionCookie)
+	if err != nil {
+		return ""
+	}
+	value, err := url.QueryUnescape(strings.Replace(cookie.Value, "+", url.QueryEscape("+"), -1))
+	if err != nil {
+		return ""
+	}
+	return sessionID(value)
+}
+
+// restGetSessions lists the active UI sessions, optionally only those of the
+// user given by the "user" query parameter
+func restGetSessions(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	all, err := sessions.List()
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+	user := r.URL.Query().Get("user")
+	currentID := currentSessionID(r)
+	now := time.Now()
+	result := []sessionDetails{}
+	for _, s := range all {
+		if sessionExpired(s, now) || (user != "" && s.User != user) {
+			continue
+		}
+		result = append(result, newSessionDetails(s, currentID))
+	}
+	w.WriteJson(result)
+}
+
+// restRemoveSession ends a UI session
+func restRemoveSession(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	id, err := url.QueryUnescape(r.PathParam("sessionId"))
+	if err != nil {
+		restBadRequest(w, err)
+		return
+	}
+	if _, err := sessions.Get(id); err == session.ErrNoSession {
+		writeJSON(w, &simpleResponse{"Session not found", homeLink()}, http.StatusNotFound)
+		return
+	} else if err != nil {
+		restServerError(w, err)
+		return
+	}
+	if err := sessions.Delete(id); err != nil {
+		restServerError(w, err)
+		return
+	}
+	plog.WithField("session", id).WithField("user", ctx.username).Info("Removed UI session")
+	w.WriteJson(&simpleResponse{"Removed session", homeLink()})
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/apitoken"
+	"github.com/zenoss/go-json-rest"
+)
+
+var apiTokenAuditLogger = audit.NewLogger()
+
+// errAPITokenScope is returned when a request is outside an API token's scope
+var errAPITokenScope = errors.New("request is not allowed by the API token's scope")
+
+// apiTokenLogin authenticates a request that carries an API token as its
+// bearer token.  present is false if the request has no API token, in which
+// case the other login methods apply.  Otherwise, ok reports whether the
+// token is active and its scope allows the request.  Each use of a token is
+// recorded in the audit log with the token as the acting principal.
+func (sc *ServiceConfig) apiTokenLogin(r *rest.Request) (principal string, present, ok bool) {
+	bearer, err := auth.ExtractRestToken(r.Request)
+	if err != nil {
+		return "", false, false
+	}
+	if _, _, isToken := apitoken.Parse(bearer); !isToken {
+		return "", false, false
+	}
+
+	ctx := datastore.GetNewInstance()
+	logger := plog.WithField("url", r.URL.String())
+	t, err := sc.facade.AuthenticateAPIToken(ctx, bearer)
+	if err != nil {
+		logger.WithError(err).Debug("Rejected API token")
+		return "", true, false
+	}
+
+	ctx.SetUser(t.Principal())
+	alog := apiTokenAuditLogger.Message(ctx, "API Token Request").Action(r.Method).
+		ID(t.ID).Type(apitoken.GetType()).WithField("path", r.URL.Path)
+	if err := sc.checkAPITokenScope(ctx, t, r); err != nil {
+		logger.WithError(err).WithField("token", t.Name).Debug("API token scope does not allow request")
+		alog.Error(err)
+		return "", true, false
+	}
+	alog.Succeeded()
+	return t.Principal(), true, true
+}
+
+// checkAPITokenScope returns an error if the request is outside the token's
+// scope.  Tokens restricted to tenants may only address services, by id,
+// that belong to those tenants.
+func (sc *ServiceConfig) checkAPITokenScope(ctx datastore.Context, t *apitoken.APIToken, r *rest.Request) error {
+	if !t.Scope.AllowsMethod(r.Method) {
+		return errAPITokenScope
+	}
+	if len(t.Scope.Tenants) == 0 {
+		return nil
+	}
+	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
+	if err != nil || serviceID == "" {
+		return errAPITokenScope
+	}
+	tenantID, err := sc.facade.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	if !t.Scope.AllowsTenant(tenantID) {
+		return errAPITokenScope
+	}
+	return nil
+}
@@ -26,12 +26,14 @@ import (
 	"strings"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/config"
 	daoclient "github.com/control-center/serviced/dao/client"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/rpc/master"
+	"github.com/control-center/serviced/trace"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/zzk"
 	"github.com/control-center/serviced/zzk/registry"
@@ -68,6 +70,12 @@ type Auth0Config struct {
 	Auth0Scope    string
 }
 
+// OIDCConfig tells the UI whether login through an OIDC provider is enabled
+type OIDCConfig struct {
+	Enabled  bool
+	LoginURL string
+}
+
 var defaultHostAlias string
 var uiConfig UIConfig
 
@@ -109,6 +117,7 @@ func NewServiceConfig(bindPort string, agentPort string, stats bool, hostaliases
 	}
 
 	adminGroup = aGroup
+	userCredentials = facade
 
 	snapshotSpacePercent = configuredSnapshotSpacePercent
 
@@ -198,6 +207,10 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 			return
 		}
 		r.URL.Path = cleanPath(r.URL.Path)
+		if r.URL.Path == eventsPath {
+			sc.serveEvents(shutdown, w, r)
+			return
+		}
 		uiHandler.ServeHTTP(w, r)
 	}
 
@@ -296,7 +309,10 @@ func (sc *ServiceConfig) unAuthorizedClient(realfunc handlerClientFunc) handlerF
 
 func (sc *ServiceConfig) authorizedClient(realfunc handlerClientFunc) handlerFunc {
 	return func(w *rest.ResponseWriter, r *rest.Request) {
-		if !loginOK(w, r) {
+		if _, present, ok := sc.apiTokenLogin(r); present && !ok {
+			restUnauthorized(w)
+			return
+		} else if !present && !loginOK(w, r) {
 			restUnauthorized(w)
 			return
 		}
@@ -348,6 +364,7 @@ func (sc *ServiceConfig) newRequestHandler(check checkFunc, realfunc ctxhandlerF
 			return
 		}
 		reqCtx := newRequestContextFromRequest(sc, r)
+		reqCtx.startTrace(w, r)
 		defer reqCtx.end()
 		realfunc(w, r, reqCtx)
 	}
@@ -361,7 +378,23 @@ func (sc *ServiceConfig) checkAuth(realfunc ctxhandlerFunc) handlerFunc {
 		}
 		return true
 	}
-	return sc.newRequestHandler(check, realfunc)
+	handler := sc.newRequestHandler(check, realfunc)
+	return func(w *rest.ResponseWriter, r *rest.Request) {
+		principal, present, ok := sc.apiTokenLogin(r)
+		if !present {
+			handler(w, r)
+			return
+		} else if !ok {
+			restUnauthorized(w)
+			return
+		}
+		// Requests made with an API token act as the token
+		reqCtx := newRequestContext(sc)
+		reqCtx.username = principal
+		reqCtx.startTrace(w, r)
+		defer reqCtx.end()
+		realfunc(w, r, reqCtx)
+	}
 }
 
 func (sc *ServiceConfig) noAuth(realfunc ctxhandlerFunc) handlerFunc {
@@ -376,6 +409,7 @@ type requestContext struct {
 	master   master.ClientInterface
 	dataCtx  datastore.Context
 	username string
+	span     *trace.Span
 }
 
 func newRequestContext(sc *ServiceConfig) *requestContext {
@@ -416,16 +450,37 @@ func (ctx *requestContext) getDatastoreContext() datastore.Context {
 		context.SetUser(ctx.username)
 	}
 
+	if ctx.span != nil {
+		return datastore.WithTrace(context, ctx.span.Context)
+	}
 	return context
 }
 
+// startTrace continues the caller's trace, or starts one for requests that
+// change state, and returns the traceparent in the response headers.
+func (ctx *requestContext) startTrace(w *rest.ResponseWriter, r *rest.Request) {
+	parent := trace.Parse(r.Header.Get(traceParentHeader))
+	if !parent.IsValid() && r.Method == "GET" {
+		return
+	}
+	ctx.span = trace.Start(r.Method+" "+r.URL.Path, parent)
+	if len(ctx.username) > 0 {
+		ctx.span.SetAttribute("user", ctx.username)
+	}
+	w.Header().Set(traceParentHeader, ctx.span.TraceParent())
+}
+
 func (ctx *requestContext) end() error {
+	ctx.span.Finish(nil)
 	if ctx.master != nil {
 		return ctx.master.Close()
 	}
 	return nil
 }
 
+// traceParentHeader carries the W3C trace context of a request
+const traceParentHeader = "traceparent"
+
 type ctxhandlerFunc func(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext)
 type checkFunc func(w *rest.ResponseWriter, r *rest.Request) bool
 
@@ -528,4 +583,12 @@ func restGetAuth0Config(w *rest.ResponseWriter, r *rest.Request, ctx *requestCon
 	w.Write([]byte("var Auth0Config = "))
 	w.WriteJson(auth0Config)
 	w.Write([]byte(";\n"))
+
+	oidcConfig := OIDCConfig{
+		Enabled:  auth.OIDCIsConfigured(),
+		LoginURL: "/oidc/login",
+	}
+	w.Write([]byte("var OIDCConfig = "))
+	w.WriteJson(oidcConfig)
+	w.Write([]byte(";\n"))
 }
@@ -0,0 +1,148 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/config"
+	"github.com/zenoss/go-json-rest"
+)
+
+const oidcStateCookie = "ZOIDCState"
+
+// oidcProvider identifies the sessions started by the OIDC provider
+const oidcProvider = "oidc"
+
+// oidcStateMaxAge is how long, in seconds, a user has to log in with the
+// OIDC provider.
+const oidcStateMaxAge = 300
+
+func loginWithOIDCTokenOK(r *rest.Request, token string) (auth.OIDCToken, bool) {
+	if !auth.OIDCIsConfigured() {
+		return nil, false
+	}
+	oidcToken, err := auth.GetOIDCProvider().ParseToken(token)
+	if err != nil {
+		msg := "Unable to parse OIDC token"
+		plog.WithError(err).WithField("url", r.URL.String()).Debug(msg)
+		return nil, false
+	} else if !oidcToken.HasAdminAccess() {
+		msg := "Could not login with OIDC token. Insufficient permissions."
+		plog.WithField("url", r.URL.String()).WithField("user", oidcToken.User()).Debug(msg)
+		return nil, false
+	}
+	return oidcToken, true
+}
+
+// oidcRedirectURL returns the url the OIDC provider sends the user back to
+// after logging in.
+func oidcRedirectURL(r *rest.Request) string {
+	if url := config.GetOptions().OIDCRedirectURL; url != "" {
+		return url
+	}
+	scheme := "https"
+	if r.Request.TLS == nil {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/oidc/callback", scheme, r.Host)
+}
+
+/*
+ * Redirect to the OIDC provider's login page
+ */
+func restOIDCLogin(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	if !auth.OIDCIsConfigured() {
+		writeJSON(w, &simpleResponse{"OIDC login is not configured", loginLink()}, http.StatusNotFound)
+		return
+	}
+
+	state, err := randomStr()
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	authURL, err := auth.GetOIDCProvider().AuthCodeURL(oidcRedirectURL(r), state)
+	if err != nil {
+		plog.WithError(err).Warn("Could not look up OIDC provider")
+		restServerError(w, err)
+		return
+	}
+
+	// the state is checked on the callback to make sure that the login was
+	// started by this browser
+	http.SetCookie(
+		w.ResponseWriter,
+		&http.Cookie{
+			Name:     oidcStateCookie,
+			Value:    state,
+			Path:     "/oidc",
+			MaxAge:   oidcStateMaxAge,
+			Secure:   r.Request.TLS != nil,
+			HttpOnly: true,
+		})
+	http.Redirect(w.ResponseWriter, r.Request, authURL, http.StatusFound)
+}
+
+/*
+ * Complete a login with the OIDC provider and start a session
+ */
+func restOIDCCallback(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	if !auth.OIDCIsConfigured() {
+		writeJSON(w, &simpleResponse{"OIDC login is not configured", loginLink()}, http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	cookie, err := r.Request.Cookie(oidcStateCookie)
+	http.SetCookie(
+		w.ResponseWriter,
+		&http.Cookie{
+			Name:   oidcStateCookie,
+			Value:  "",
+			Path:   "/oidc",
+			MaxAge: -1,
+		})
+	if err != nil || cookie.Value == "" || cookie.Value != query.Get("state") {
+		plog.Warn("OIDC login state does not match")
+		writeJSON(w, &simpleResponse{"Login failed", loginLink()}, http.StatusUnauthorized)
+		return
+	}
+	if e := query.Get("error"); e != "" {
+		plog.WithField("error", e).WithField("description", query.Get("error_description")).Warn("OIDC provider rejected login")
+		writeJSON(w, &simpleResponse{"Login failed", loginLink()}, http.StatusUnauthorized)
+		return
+	}
+
+	token, err := auth.GetOIDCProvider().Exchange(query.Get("code"), oidcRedirectURL(r))
+	if err != nil {
+		plog.WithError(err).Warn("Could not complete OIDC login")
+		writeJSON(w, &simpleResponse{"Login failed", loginLink()}, http.StatusUnauthorized)
+		return
+	}
+	if !token.HasAdminAccess() {
+		plog.WithField("user", token.User()).Warn("Could not login with OIDC. Insufficient permissions.")
+		writeJSON(w, &simpleResponse{"Insufficient permissions", loginLink()}, http.StatusUnauthorized)
+		return
+	}
+
+	if err := startSession(w, token.User(), oidcProvider); err != nil {
+		restServerError(w, err)
+		return
+	}
+	http.Redirect(w.ResponseWriter, r.Request, "/", http.StatusFound)
+}
@@ -714,7 +714,7 @@ func restStopService(w *rest.ResponseWriter, r *rest.Request, ctx *requestContex
 
 	serviceFacade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
-	_, err = serviceFacade.StopService(dataCtx, dao.ScheduleServiceRequest{[]string{serviceID}, autoLaunch, false})
+	_, err = serviceFacade.StopService(dataCtx, dao.ScheduleServiceRequest{ServiceIDs: []string{serviceID}, AutoLaunch: autoLaunch, Synchronous: false})
 	if err != nil {
 		logger.WithError(err).Error("Unexpected error stopping service")
 		restServerError(w, err)
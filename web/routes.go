@@ -103,6 +103,8 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		// Login
 		rest.Route{"POST", "/login", gz(sc.noAuth(restLogin))},
 		rest.Route{"DELETE", "/login", gz(restLogout)},
+		rest.Route{"GET", "/oidc/login", sc.noAuth(restOIDCLogin)},
+		rest.Route{"GET", "/oidc/callback", sc.noAuth(restOIDCCallback)},
 
 		// "Misc" stuff
 		rest.Route{"GET", "/top/services", gz(sc.checkAuth(restGetTopServices))},
@@ -144,6 +146,8 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		rest.Route{"PUT", "/api/v2/services/:serviceId/context", gz(sc.checkAuth(putServiceContext))},
 		rest.Route{"GET", "/api/v2/statuses", gz(sc.checkAuth(restGetAggregateServices))},
 		rest.Route{"GET", "/api/v2/hoststatuses", gz(sc.checkAuth(getHostStatuses))},
+		rest.Route{"GET", "/api/v2/sessions", gz(sc.checkAuth(restGetSessions))},
+		rest.Route{"DELETE", "/api/v2/sessions/:sessionId", gz(sc.checkAuth(restRemoveSession))},
 
 		rest.Route{"GET", "/api/v2/services/:serviceId/serviceconfigs", gz(sc.checkAuth(restGetServiceConfigFiles))},
 		rest.Route{"POST", "/api/v2/services/:serviceId/serviceconfigs", gz(sc.checkAuth(restAddServiceConfigFile))},
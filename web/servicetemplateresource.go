@@ -88,7 +88,7 @@ func restDeployAppTemplate(w *rest.ResponseWriter, r *rest.Request, ctx *request
 		restBadRequest(w, err)
 		return
 	}
-	tenantIDs, err := ctx.getFacade().DeployTemplate(ctx.getDatastoreContext(), payload.PoolID, payload.TemplateID, payload.DeploymentID)
+	tenantIDs, err := ctx.getFacade().DeployTemplate(ctx.getDatastoreContext(), payload.PoolID, payload.TemplateID, payload.DeploymentID, payload.Parameters)
 	if err != nil {
 		glog.Error("Could not deploy template: ", err)
 		restServerError(w, err)
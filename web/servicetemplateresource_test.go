@@ -172,6 +172,7 @@ func (s *TestWebSuite) TestRestDeployAppTemplate(c *C) {
 		PoolID:       "somePoolID",
 		TemplateID:   "someTemplateID",
 		DeploymentID: "someDeploymentID",
+		Parameters:   map[string]string{"someParam": "someValue"},
 	}
 	jsonPayload, err := json.Marshal(&payload)
 	if err != nil {
@@ -179,7 +180,7 @@ func (s *TestWebSuite) TestRestDeployAppTemplate(c *C) {
 	}
 	request := s.buildRequest("GET", "/templates/deploy", string(jsonPayload))
 	s.mockFacade.
-		On("DeployTemplate", s.ctx.getDatastoreContext(), payload.PoolID, payload.TemplateID, payload.DeploymentID).
+		On("DeployTemplate", s.ctx.getDatastoreContext(), payload.PoolID, payload.TemplateID, payload.DeploymentID, payload.Parameters).
 		Return(expectedResult, nil)
 	s.mockFacade.
 		On("AssignIPs", s.ctx.getDatastoreContext(), mock.AnythingOfType("addressassignment.AssignmentRequest")).
@@ -195,7 +196,7 @@ func (s *TestWebSuite) TestRestDeployAppTemplateFails(c *C) {
 	expectedError := fmt.Errorf("mock DeployTemplate failed")
 	request := s.buildRequest("GET", "/templates/deploy", `{"DeploymentID": "someID"}`)
 	s.mockFacade.
-		On("DeployTemplate", s.ctx.getDatastoreContext(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		On("DeployTemplate", s.ctx.getDatastoreContext(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.Anything).
 		Return(nil, expectedError)
 
 	restDeployAppTemplate(&(s.writer), &request, s.ctx)
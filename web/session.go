@@ -15,20 +15,25 @@ package web
 
 import (
 	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
 	userdomain "github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/zzk/session"
 	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -38,15 +43,15 @@ const auth0TokenCookie = "auth0AccessToken"
 
 var adminGroup = "sudo"
 
-type sessionT struct {
-	ID       string
-	User     string
-	creation time.Time
-	access   time.Time
-}
+// shadowFile holds the password hashes of local system users
+var shadowFile = "/etc/shadow"
+
+// userCredentials looks up the passwords of Control Center users
+var userCredentials facade.FacadeInterface
 
-var sessions map[string]*sessionT
-var sessionsLock = &sync.RWMutex{}
+// sessionAccessInterval is how often the use of a session is recorded and
+// the credentials it was created with are checked
+const sessionAccessInterval = time.Minute
 
 var allowRootLogin bool = true
 
@@ -63,66 +68,98 @@ func init() {
 	if utils.Platform == utils.Rhel {
 		adminGroup = "wheel"
 	}
-
-	sessions = make(map[string]*sessionT)
-	go purgeOldsessionTs()
 }
 
-func purgeOldsessionTs() {
-
-	// use a closure to facilitate safe locking regardless of when the purge function returns
-	doPurge := func() {
-		sessionsLock.Lock()
-		defer sessionsLock.Unlock()
+func purgeOldSessions() {
+	for {
+		time.Sleep(time.Second * 60)
 
-		if len(sessions) == 0 {
-			return
-		}
+		purgeExpiredSessions(time.Now())
+	}
+}
 
-		glog.V(1).Info("Searching for expired sessions")
-		cutoff := time.Now().UTC().Unix() - int64((30 * time.Minute).Seconds())
-		toDel := []string{}
-		for key, value := range sessions {
-			if value.access.UTC().Unix() < cutoff {
-				toDel = append(toDel, key)
+// purgeExpiredSessions deletes the sessions that have gone unused for too
+// long or have exceeded their maximum age
+func purgeExpiredSessions(now time.Time) {
+	all, err := sessions.List()
+	if err != nil {
+		plog.WithError(err).Warn("Unable to list sessions")
+		return
+	}
+	glog.V(1).Info("Searching for expired sessions")
+	for _, s := range all {
+		if sessionExpired(s, now) {
+			glog.V(0).Infof("Deleting session %s (expired)", s.ID)
+			if err := sessions.Delete(s.ID); err != nil {
+				plog.WithError(err).WithField("session", s.ID).Warn("Unable to delete session")
 			}
 		}
-		for _, key := range toDel {
-			glog.V(0).Infof("Deleting session %s (exceeded max age)", key)
-			delete(sessions, key)
-		}
 	}
+}
 
-	for {
-		time.Sleep(time.Second * 60)
-
-		doPurge()
+// sessionExpired returns true if the session has gone unused for too long or
+// has exceeded its maximum age
+func sessionExpired(s *session.Session, now time.Time) bool {
+	if now.Sub(s.AccessedAt) > sessionIdleTimeout {
+		return true
 	}
+	return sessionMaxAge > 0 && now.Sub(s.CreatedAt) > sessionMaxAge
 }
 
 /*
  * This function should be called by any secure REST resource
  */
 func loginWithBasicAuthOK(r *rest.Request) bool {
+	return loginWithSessionOK(r, func(*session.Session) bool { return true })
+}
+
+// loginWithOIDCSessionOK accepts only sessions that were started by the OIDC
+// provider
+func loginWithOIDCSessionOK(r *rest.Request) bool {
+	return loginWithSessionOK(r, func(s *session.Session) bool { return s.Provider == oidcProvider })
+}
+
+// loginWithSessionOK returns true if the request's session cookie identifies
+// a live session that is accepted
+func loginWithSessionOK(r *rest.Request, accept func(*session.Session) bool) bool {
 	cookie, err := r.Request.Cookie(sessionCookie)
 	if err != nil {
 		glog.V(1).Info("Error getting cookie ", err)
 		return false
 	}
-	sessionsLock.Lock()
-	defer sessionsLock.Unlock()
 	value, err := url.QueryUnescape(strings.Replace(cookie.Value, "+", url.QueryEscape("+"), -1))
 	if err != nil {
 		glog.Warning("Unable to decode session ", cookie.Value)
 		return false
 	}
-	session, err := findsessionT(value)
+	sid := sessionID(value)
+	s, err := sessions.Get(sid)
 	if err != nil {
-		glog.Info("Unable to find session ", value)
+		glog.Info("Unable to find session ", sid)
 		return false
 	}
-	session.access = time.Now()
-	glog.V(2).Infof("sessionT %s used", session.ID)
+	now := time.Now()
+	if sessionExpired(s, now) {
+		glog.V(0).Infof("Deleting session %s (expired)", sid)
+		sessions.Delete(sid)
+		return false
+	}
+	if !accept(s) {
+		glog.V(1).Infof("Session %s was not started by an accepted provider", sid)
+		return false
+	}
+	if now.Sub(s.AccessedAt) >= sessionAccessInterval {
+		if s.Fingerprint != credentialFingerprint(s.User) {
+			glog.V(0).Infof("Deleting session %s (credentials changed)", sid)
+			sessions.Delete(sid)
+			return false
+		}
+		s.AccessedAt = now
+		if err := sessions.Put(s); err != nil {
+			plog.WithError(err).WithField("session", sid).Warn("Unable to record session access")
+		}
+	}
+	glog.V(2).Infof("session %s used", sid)
 	return true
 }
 
@@ -182,12 +219,25 @@ func loginOK(w *rest.ResponseWriter, r *rest.Request) bool {
 		plog.WithError(tErr).WithField("url", r.URL.String()).Debug(msg)
 		return false
 	}
-	if auth.Auth0IsConfigured() {
-		if auth0LoginOK(w, r, token) {
+	// try each configured provider before falling back to rest tokens
+	if auth.Auth0IsConfigured() && auth0LoginOK(w, r, token) {
+		return true
+	}
+	if auth.OIDCIsConfigured() && token != "null" && token != "" {
+		// OIDC bearer tokens are accepted alongside rest tokens; browser
+		// logins through the provider use a session.
+		if _, ok := loginWithOIDCTokenOK(r, token); ok {
 			return true
 		}
+	}
+	if auth.Auth0IsConfigured() {
 		// CC-4109: even with auth0 configured, we still need token authentication for REST calls.
-		return loginWithTokenOK(r, token)
+		if token != "null" && token != "" {
+			return loginWithTokenOK(r, token)
+		}
+		// local logins are not allowed alongside auth0, so only sessions
+		// started by the OIDC provider are accepted
+		return auth.OIDCIsConfigured() && loginWithOIDCSessionOK(r)
 	}
 	return basicAuthLoginOK(w, r, token)
 }
@@ -247,9 +297,13 @@ func restLogout(w *rest.ResponseWriter, r *rest.Request) {
 	cookie, err := r.Request.Cookie(sessionCookie)
 	if err != nil {
 		glog.V(2).Info("Unable to read session cookie")
-	} else {
-		deleteSessionT(cookie.Value)
-		glog.V(2).Infof("Deleted session %s for explicit logout", cookie.Value)
+	} else if value, err := url.QueryUnescape(strings.Replace(cookie.Value, "+", url.QueryEscape("+"), -1)); err == nil {
+		sid := sessionID(value)
+		if err := sessions.Delete(sid); err != nil {
+			plog.WithError(err).WithField("session", sid).Warn("Unable to delete session")
+		} else {
+			glog.V(2).Infof("Deleted session %s for explicit logout", sid)
+		}
 	}
 
 	// Blank out all login cookies
@@ -291,39 +345,56 @@ func restLoginWithBasicAuth(w *rest.ResponseWriter, r *rest.Request, ctx *reques
 	}
 
 	if validateLogin(&creds, client) {
-		sessionsLock.Lock()
-		defer sessionsLock.Unlock()
-
-		session, err := createsessionT(creds.Username)
-		if err != nil {
-			writeJSON(w, &simpleResponse{"sessionT could not be created", loginLink()}, http.StatusInternalServerError)
+		if err := startSession(w, creds.Username, ""); err != nil {
+			writeJSON(w, &simpleResponse{"Session could not be created", loginLink()}, http.StatusInternalServerError)
 			return
 		}
-		sessions[session.ID] = session
-
-		glog.V(1).Info("Created authenticated session: ", session.ID)
-		http.SetCookie(
-			w.ResponseWriter,
-			&http.Cookie{
-				Name:   sessionCookie,
-				Value:  session.ID,
-				Path:   "/",
-				MaxAge: 0,
-			})
-		http.SetCookie(
-			w.ResponseWriter,
-			&http.Cookie{
-				Name:   usernameCookie,
-				Value:  creds.Username,
-				Path:   "/",
-				MaxAge: 0,
-			})
 		w.WriteJson(&simpleResponse{"Accepted", homeLink()})
 	} else {
 		writeJSON(w, &simpleResponse{"Login failed", loginLink()}, http.StatusUnauthorized)
 	}
 }
 
+// startSession creates an authenticated session for the user and sets the
+// session cookies.  The provider is empty for local users.
+func startSession(w *rest.ResponseWriter, username, provider string) error {
+	token, err := randomSessionToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	s := &session.Session{
+		ID:          sessionID(token),
+		User:        username,
+		Provider:    provider,
+		Fingerprint: credentialFingerprint(username),
+		CreatedAt:   now,
+		AccessedAt:  now,
+	}
+	if err := sessions.Put(s); err != nil {
+		return err
+	}
+
+	glog.V(1).Info("Created authenticated session: ", s.ID)
+	http.SetCookie(
+		w.ResponseWriter,
+		&http.Cookie{
+			Name:   sessionCookie,
+			Value:  token,
+			Path:   "/",
+			MaxAge: 0,
+		})
+	http.SetCookie(
+		w.ResponseWriter,
+		&http.Cookie{
+			Name:   usernameCookie,
+			Value:  username,
+			Path:   "/",
+			MaxAge: 0,
+		})
+	return nil
+}
+
 /*
  * Perform login, return JSON
  */
@@ -338,6 +409,9 @@ func restLogin(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 		if _, ok := loginWithAuth0TokenOK(r, token); ok {
 			w.WriteJson(&simpleResponse{"Accepted", homeLink()})
 			return
+		} else if _, ok := loginWithOIDCTokenOK(r, token); ok {
+			w.WriteJson(&simpleResponse{"Accepted", homeLink()})
+			return
 		} else if loginWithTokenOK(r, token) {
 			w.WriteJson(&simpleResponse{"Accepted", homeLink()})
 			return
@@ -376,33 +450,60 @@ func cpValidateLogin(creds *login, client master.ClientInterface) bool {
 	return result
 }
 
-func createsessionT(user string) (*sessionT, error) {
-	sid, err := randomsessionTId()
-	if err != nil {
-		return nil, err
+// credentialFingerprint identifies the credentials a user logs in with, so
+// that the user's sessions end when the password changes.  It returns an
+// empty string if the credentials are unknown, such as for users logged in
+// through an identity provider.
+func credentialFingerprint(username string) string {
+	var secret string
+	if userCredentials != nil {
+		if u, err := userCredentials.GetUser(datastore.GetNewInstance(), username); err == nil {
+			secret = u.Password
+		}
+	}
+	if secret == "" {
+		secret = shadowPassword(username)
+	}
+	if secret == "" {
+		return ""
 	}
-	return &sessionT{sid, user, time.Now(), time.Now()}, nil
+	sum := sha256.Sum256([]byte(username + ":" + secret))
+	return hex.EncodeToString(sum[:])
 }
 
-func findsessionT(sid string) (*sessionT, error) {
-	session, ok := sessions[sid]
-	if !ok {
-		return nil, errors.New("sessionT not found")
+// shadowPassword returns the password hash of a local system user
+func shadowPassword(username string) string {
+	data, err := ioutil.ReadFile(shadowFile)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) >= 2 && fields[0] == username {
+			return fields[1]
+		}
 	}
-	return session, nil
+	return ""
 }
 
-func randomsessionTId() (string, error) {
+func randomSessionToken() (string, error) {
 	s, err := randomStr()
 	if err != nil {
 		return "", err
 	}
-	if sessions[s] != nil {
-		return "", errors.New("sessionT ID collided")
+	if _, err := sessions.Get(sessionID(s)); err == nil {
+		return "", errors.New("session ID collided")
 	}
 	return s, nil
 }
 
+// sessionID returns the id a session is stored under, which is derived from
+// the session's token so that the token itself is never stored
+func sessionID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func randomStr() (string, error) {
 	sid := make([]byte, 32)
 	n, err := rand.Read(sid)
@@ -416,13 +517,6 @@ func randomStr() (string, error) {
 	return base64.StdEncoding.EncodeToString(sid), nil
 }
 
-func deleteSessionT(sid string) {
-	sessionsLock.Lock()
-	defer sessionsLock.Unlock()
-
-	delete(sessions, sid)
-}
-
 func getUser(r *rest.Request) (string, error) {
 	for _, cookie := range r.Cookies() {
 		if cookie.Name == usernameCookie {
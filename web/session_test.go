@@ -0,0 +1,198 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/zzk/session"
+	"github.com/zenoss/go-json-rest"
+	. "gopkg.in/check.v1"
+)
+
+// startTestSession logs the user in and returns the new session's cookie
+func (s *TestWebSuite) startTestSession(c *C, username string) *http.Cookie {
+	return s.startProviderSession(c, username, "")
+}
+
+// startProviderSession logs the user in through a provider and returns the
+// new session's cookie
+func (s *TestWebSuite) startProviderSession(c *C, username, provider string) *http.Cookie {
+	c.Assert(startSession(&s.writer, username, provider), IsNil)
+	for _, cookie := range (&http.Response{Header: s.recorder.HeaderMap}).Cookies() {
+		if cookie.Name == sessionCookie {
+			return cookie
+		}
+	}
+	c.Fatalf("no session cookie was set")
+	return nil
+}
+
+func (s *TestWebSuite) setUpSessions(c *C) {
+	sessions = newMemorySessionStore()
+	shadow, err := ioutil.TempFile("", "shadow")
+	c.Assert(err, IsNil)
+	shadow.WriteString("root:$6$old:17000:0:99999:7:::\n")
+	shadow.Close()
+	shadowFile = shadow.Name()
+}
+
+func (s *TestWebSuite) tearDownSessions() {
+	os.Remove(shadowFile)
+	shadowFile = "/etc/shadow"
+}
+
+func (s *TestWebSuite) TestSession_LoginAndLogout(c *C) {
+	s.setUpSessions(c)
+	defer s.tearDownSessions()
+
+	cookie := s.startTestSession(c, "root")
+	all, _ := sessions.List()
+	c.Assert(all, HasLen, 1)
+	c.Assert(all[0].ID, Not(Equals), cookie.Value)
+	c.Assert(all[0].ID, Equals, sessionID(cookie.Value))
+
+	request := s.buildRequest("GET", "/services", "")
+	request.AddCookie(cookie)
+	c.Assert(loginWithBasicAuthOK(&request), Equals, true)
+
+	restLogout(&s.writer, &request)
+	c.Assert(loginWithBasicAuthOK(&request), Equals, false)
+}
+
+func (s *TestWebSuite) TestSession_Expiry(c *C) {
+	s.setUpSessions(c)
+	defer s.tearDownSessions()
+
+	cookie := s.startTestSession(c, "root")
+	request := s.buildRequest("GET", "/services", "")
+	request.AddCookie(cookie)
+
+	// idle too long
+	stored, err := sessions.Get(sessionID(cookie.Value))
+	c.Assert(err, IsNil)
+	stored.AccessedAt = time.Now().Add(-sessionIdleTimeout - time.Minute)
+	sessions.Put(stored)
+	c.Assert(loginWithBasicAuthOK(&request), Equals, false)
+	_, err = sessions.Get(stored.ID)
+	c.Assert(err, Equals, session.ErrNoSession)
+
+	// older than the maximum age, despite recent use
+	stored.AccessedAt = time.Now()
+	stored.CreatedAt = time.Now().Add(-sessionMaxAge - time.Minute)
+	sessions.Put(stored)
+	c.Assert(loginWithBasicAuthOK(&request), Equals, false)
+}
+
+func (s *TestWebSuite) TestSession_PasswordChange(c *C) {
+	s.setUpSessions(c)
+	defer s.tearDownSessions()
+
+	cookie := s.startTestSession(c, "root")
+	request := s.buildRequest("GET", "/services", "")
+	request.AddCookie(cookie)
+	stored, err := sessions.Get(sessionID(cookie.Value))
+	c.Assert(err, IsNil)
+	c.Assert(stored.Fingerprint, Not(Equals), "")
+
+	// credentials are checked when the session's use is next recorded
+	c.Assert(ioutil.WriteFile(shadowFile, []byte("root:$6$new:17001:0:99999:7:::\n"), 0600), IsNil)
+	c.Assert(loginWithBasicAuthOK(&request), Equals, true)
+	stored.AccessedAt = time.Now().Add(-sessionAccessInterval)
+	sessions.Put(stored)
+	c.Assert(loginWithBasicAuthOK(&request), Equals, false)
+	_, err = sessions.Get(stored.ID)
+	c.Assert(err, Equals, session.ErrNoSession)
+}
+
+func (s *TestWebSuite) TestSession_Purge(c *C) {
+	s.setUpSessions(c)
+	defer s.tearDownSessions()
+
+	now := time.Now()
+	sessions.Put(&session.Session{ID: "fresh", User: "root", CreatedAt: now, AccessedAt: now})
+	sessions.Put(&session.Session{ID: "idle", User: "root", CreatedAt: now, AccessedAt: now.Add(-sessionIdleTimeout - time.Second)})
+	purgeExpiredSessions(now)
+
+	all, err := sessions.List()
+	c.Assert(err, IsNil)
+	c.Assert(all, HasLen, 1)
+	c.Assert(all[0].ID, Equals, "fresh")
+}
+
+func (s *TestWebSuite) TestSession_ListAndRemove(c *C) {
+	s.setUpSessions(c)
+	defer s.tearDownSessions()
+
+	cookie := s.startTestSession(c, "root")
+	now := time.Now()
+	sessions.Put(&session.Session{ID: "other", User: "admin", CreatedAt: now, AccessedAt: now})
+
+	request := s.buildRequest("GET", "/api/v2/sessions?user=root", "")
+	request.AddCookie(cookie)
+	s.recorder.Body.Reset()
+	restGetSessions(&s.writer, &request, s.ctx)
+	result := []sessionDetails{}
+	s.getResult(c, &result)
+	c.Assert(result, HasLen, 1)
+	c.Assert(result[0].User, Equals, "root")
+	c.Assert(result[0].Current, Equals, true)
+
+	request = s.buildRequest("DELETE", "/api/v2/sessions/other", "")
+	request.PathParams["sessionId"] = "other"
+	s.recorder.Body.Reset()
+	restRemoveSession(&s.writer, &request, s.ctx)
+	_, err := sessions.Get("other")
+	c.Assert(err, Equals, session.ErrNoSession)
+
+	s.recorder = httptest.NewRecorder()
+	s.writer = rest.NewResponseWriter(s.recorder, false)
+	restRemoveSession(&s.writer, &request, s.ctx)
+	c.Assert(s.recorder.Code, Equals, http.StatusNotFound)
+}
+
+func (s *TestWebSuite) TestSession_Auth0WithOIDC(c *C) {
+	s.setUpSessions(c)
+	defer s.tearDownSessions()
+
+	opts := config.GetOptions()
+	defer config.LoadOptions(opts)
+	withProviders := opts
+	withProviders.Auth0Domain = "example.auth0.com"
+	withProviders.Auth0Audience = "audience"
+	withProviders.Auth0Group = "admins"
+	withProviders.Auth0ClientID = "auth0client"
+	withProviders.Auth0Scope = "openid"
+	withProviders.OIDCIssuer = "https://issuer.example.com"
+	withProviders.OIDCClientID = "oidcclient"
+	config.LoadOptions(withProviders)
+
+	// local sessions are refused while auth0 is configured
+	request := s.buildRequest("GET", "/services", "")
+	request.AddCookie(s.startTestSession(c, "root"))
+	c.Assert(loginOK(&s.writer, &request), Equals, false)
+
+	now := time.Now()
+	sessions.Put(&session.Session{ID: sessionID("oidctoken"), User: "root", Provider: oidcProvider, CreatedAt: now, AccessedAt: now})
+	request = s.buildRequest("GET", "/services", "")
+	request.AddCookie(&http.Cookie{Name: sessionCookie, Value: "oidctoken"})
+	c.Assert(loginOK(&s.writer, &request), Equals, true)
+}
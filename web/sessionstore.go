@@ -0,0 +1,104 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/zzk/session"
+)
+
+// sessionStore keeps UI sessions
+type sessionStore interface {
+	Get(id string) (*session.Session, error)
+	Put(s *session.Session) error
+	Delete(id string) error
+	List() ([]*session.Session, error)
+}
+
+var (
+	sessions           sessionStore = newMemorySessionStore()
+	sessionIdleTimeout              = 30 * time.Minute
+	sessionMaxAge                   = 12 * time.Hour
+
+	purgeSessions sync.Once
+)
+
+// ConfigureSessions sets where UI sessions are kept, either in the
+// coordinator or in memory, how long they may go unused, and how long they
+// may last regardless of use.  A maxAge of 0 means no limit.  It must be
+// called once, before the UI is served, as it also starts purging expired
+// sessions.
+func ConfigureSessions(store string, idleTimeout, maxAge time.Duration) error {
+	switch store {
+	case "coordinator":
+		sessions = session.NewStore()
+	case "memory":
+		sessions = newMemorySessionStore()
+	default:
+		return fmt.Errorf("unknown session store %q", store)
+	}
+	sessionIdleTimeout = idleTimeout
+	sessionMaxAge = maxAge
+	purgeSessions.Do(func() { go purgeOldSessions() })
+	return nil
+}
+
+// memorySessionStore keeps sessions in this process, so they are lost when
+// it exits
+type memorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]session.Session
+}
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{sessions: make(map[string]session.Session)}
+}
+
+func (m *memorySessionStore) Get(id string) (*session.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, session.ErrNoSession
+	}
+	return &s, nil
+}
+
+func (m *memorySessionStore) Put(s *session.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[s.ID] = *s
+	return nil
+}
+
+func (m *memorySessionStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *memorySessionStore) List() ([]*session.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]*session.Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		s := s
+		result = append(result, &s)
+	}
+	return result, nil
+}
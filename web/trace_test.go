@@ -0,0 +1,59 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/trace"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestStartTrace_ContinuesCallerTrace(c *C) {
+	parent := trace.SpanContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}
+	request := s.buildRequest("GET", "http://www.example.com/services", "")
+	request.Header.Set(traceParentHeader, parent.String())
+
+	s.ctx.startTrace(&s.writer, &request)
+	defer s.ctx.end()
+
+	c.Assert(s.ctx.span, NotNil)
+	c.Assert(s.ctx.span.Context.TraceID, Equals, parent.TraceID)
+	c.Assert(s.ctx.span.ParentID, Equals, parent.SpanID)
+	c.Assert(s.recorder.Header().Get(traceParentHeader), Equals, s.ctx.span.TraceParent())
+	c.Assert(datastore.TraceOf(s.ctx.getDatastoreContext()), Equals, s.ctx.span.Context)
+}
+
+func (s *TestWebSuite) TestStartTrace_StartsTraceForChanges(c *C) {
+	request := s.buildRequest("PUT", "http://www.example.com/services/123/start", "")
+
+	s.ctx.startTrace(&s.writer, &request)
+	defer s.ctx.end()
+
+	c.Assert(s.ctx.span, NotNil)
+	c.Assert(s.ctx.span.ParentID, Equals, "")
+	c.Assert(s.recorder.Header().Get(traceParentHeader), Equals, s.ctx.span.TraceParent())
+}
+
+func (s *TestWebSuite) TestStartTrace_IgnoresUntracedReads(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/services", "")
+
+	s.ctx.startTrace(&s.writer, &request)
+	defer s.ctx.end()
+
+	c.Assert(s.ctx.span, IsNil)
+	c.Assert(s.recorder.Header().Get(traceParentHeader), Equals, "")
+	c.Assert(datastore.TraceOf(s.ctx.getDatastoreContext()).IsValid(), Equals, false)
+}
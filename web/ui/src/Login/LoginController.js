@@ -18,6 +18,10 @@
 
         enableLoginButton();
 
+        // offer login through a generic OpenID Connect provider if one is
+        // configured
+        $scope.oidcLoginURL = (window.OIDCConfig && window.OIDCConfig.Enabled) ? window.OIDCConfig.LoginURL : "";
+
         $scope.$emit("ready");
 
         $scope.version = "";
@@ -18,7 +18,6 @@ import (
 	"sync"
 
 	"github.com/control-center/serviced/coordinator/client"
-	"github.com/control-center/serviced/coordinator/client/zookeeper"
 )
 
 type watcher struct {
@@ -94,7 +93,7 @@ func (l *LeaderListener) Run(cancel <-chan interface{}, conn client.Connection)
 
 		// if there is a leader, figure out which one it is and update the watch
 		if len(ch) > 0 {
-			leader, err := zookeeper.GetLowestSequence(ch)
+			leader, err := client.GetLowestSequence(ch)
 			if err != nil {
 				logger.WithError(err).Error("Could not determine leader from nodes")
 				return
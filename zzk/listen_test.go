@@ -11,7 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build unit integration,!quick
 
 package zzk_test
 
@@ -33,7 +33,7 @@ func (t *ZZKTest) TestManage(c *C) {
 
 	var shutdownRecv <-chan interface{} = shutdown
 	s := &mocks.Listener2{}
-	s.On("Listen", shutdownRecv, mock.AnythingOfType("*zookeeper.Connection")).Return().WaitUntil(exit).Twice()
+	s.On("Listen", shutdownRecv, mock.AnythingOfType(TestConnectionType)).Return().WaitUntil(exit).Twice()
 	s.On("Exited").Return().Once()
 
 	done := make(chan struct{})
@@ -77,7 +77,7 @@ func (t *ZZKTest) TestListen2(c *C) {
 	pathname := "/listentest"
 
 	s := &mocks.Spawner{}
-	s.On("SetConn", mock.AnythingOfType("*zookeeper.Connection")).Return().Once()
+	s.On("SetConn", mock.AnythingOfType(TestConnectionType)).Return().Once()
 
 	s.On("Path").Return(pathname)
 
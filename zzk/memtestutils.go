@@ -0,0 +1,64 @@
+// Copyright 2014 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit,!integration
+
+package zzk
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/client/memory"
+	. "gopkg.in/check.v1"
+)
+
+// NOTE: this constant can be adjusted to satisfy race conditions
+const ZKTestTimeout = 5 * time.Second
+
+// TestConnectionType is the type of the connections returned by the local
+// client
+const TestConnectionType = "*memory.Connection"
+
+// ZZKTestSuite initializes the local client with the in-memory coordinator,
+// so that the unit tests which embed it do not need a zookeeper server.  The
+// integration tests run the same suites against zookeeper.
+type ZZKTestSuite struct{}
+
+func (t *ZZKTestSuite) SetUpSuite(c *C) {
+	memory.Reset("")
+	zclient, err := client.New("memory", "", "", nil)
+	if err != nil {
+		c.Fatalf("Could not connect to the memory coordinator: %s", err)
+	}
+	InitializeLocalClient(zclient)
+}
+
+func (t *ZZKTestSuite) TearDownSuite(c *C) {
+	ShutdownConnections()
+}
+
+func (t *ZZKTestSuite) SetUpTest(c *C) {
+	// delete the contents of the coordinator for every test
+	conn, err := GetLocalConnection("/")
+	if err != nil {
+		c.Fatalf("Could not get connection to the coordinator: %s", err)
+	}
+
+	children, err := conn.Children("/")
+	for _, child := range children {
+		if err := conn.Delete("/" + child); err != nil {
+			c.Logf("Could not delete %s: %s", child, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/zzk/service"
+)
+
+// CheckExports looks for exported endpoints that belong to tenants that no
+// longer exist or to hosts that are not known, and optionally deletes them.
+func CheckExports(conn client.Connection, tenantIDs, hostIPs []string, repair bool) ([]service.Inconsistency, error) {
+	basepth := "/net/export"
+	logger := plog.WithField("zkpath", basepth)
+
+	tenants := make(map[string]struct{})
+	for _, tenantID := range tenantIDs {
+		tenants[tenantID] = struct{}{}
+	}
+	ips := make(map[string]struct{})
+	for _, ip := range hostIPs {
+		ips[ip] = struct{}{}
+	}
+
+	found := []service.Inconsistency{}
+
+	ch, err := conn.Children(basepth)
+	if err == client.ErrNoNode {
+		return found, nil
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up exports")
+		return nil, err
+	}
+	sort.Strings(ch)
+
+	for _, tenantID := range ch {
+		tenantpth := path.Join(basepth, tenantID)
+		tenantlog := logger.WithField("tenantid", tenantID)
+
+		if _, ok := tenants[tenantID]; !ok {
+			dangling := service.Inconsistency{
+				Type:    service.DanglingExport,
+				Path:    tenantpth,
+				Message: fmt.Sprintf("exports belong to unknown tenant %s", tenantID),
+			}
+			if repair {
+				if err := DeleteExports(conn, tenantID); err != nil {
+					return nil, err
+				}
+				dangling.Repaired = true
+			}
+			found = append(found, dangling)
+			continue
+		}
+
+		apps, err := conn.Children(tenantpth)
+		if err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			tenantlog.WithError(err).Debug("Could not look up exported applications")
+			return nil, err
+		}
+		sort.Strings(apps)
+
+		for _, app := range apps {
+			apppth := path.Join(tenantpth, app)
+			exports, err := conn.Children(apppth)
+			if err == client.ErrNoNode {
+				continue
+			} else if err != nil {
+				tenantlog.WithField("application", app).WithError(err).Debug("Could not look up exports for application")
+				return nil, err
+			}
+			sort.Strings(exports)
+
+			for _, name := range exports {
+				pth := path.Join(apppth, name)
+				explog := tenantlog.WithFields(log.Fields{
+					"application": app,
+					"zkpath":      pth,
+				})
+
+				export := &ExportDetails{}
+				if err := conn.Get(pth, export); err == client.ErrNoNode {
+					continue
+				} else if err != nil && err != client.ErrEmptyNode {
+					explog.WithError(err).Debug("Could not look up export")
+					return nil, err
+				}
+				if _, ok := ips[export.HostIP]; ok {
+					continue
+				}
+
+				dangling := service.Inconsistency{
+					Type:    service.DanglingExport,
+					Path:    pth,
+					Message: fmt.Sprintf("export of %s is on unknown host ip %q", app, export.HostIP),
+				}
+				if repair {
+					if err := conn.Delete(pth); err != nil && err != client.ErrNoNode {
+						explog.WithError(err).Debug("Could not delete export")
+						return nil, err
+					}
+					dangling.Repaired = true
+				}
+				found = append(found, dangling)
+			}
+		}
+	}
+
+	if repair && len(found) > 0 {
+		logger.WithField("count", len(found)).Info("Repaired exports in the coordinator")
+	}
+	return found, nil
+}
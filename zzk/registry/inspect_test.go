@@ -0,0 +1,61 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit integration,!quick
+
+package registry_test
+
+import (
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/registry"
+	"github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestCheckExports(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	c.Assert(conn.CreateDir("/net/export/tenant/app"), IsNil)
+	c.Assert(conn.CreateDir("/net/export/removed/app"), IsNil)
+	live, err := conn.CreateEphemeral("/net/export/tenant/app/tenant-app-0", &ExportDetails{HostIP: "10.0.0.1", InstanceID: 0})
+	c.Assert(err, IsNil)
+	dangling, err := conn.CreateEphemeral("/net/export/tenant/app/tenant-app-1", &ExportDetails{HostIP: "10.0.0.2", InstanceID: 1})
+	c.Assert(err, IsNil)
+
+	found, err := CheckExports(conn, []string{"tenant"}, []string{"10.0.0.1"}, false)
+	c.Assert(err, IsNil)
+	c.Assert(found, HasLen, 2)
+	c.Check(found[0], DeepEquals, service.Inconsistency{
+		Type:    service.DanglingExport,
+		Path:    "/net/export/removed",
+		Message: "exports belong to unknown tenant removed",
+	})
+	c.Check(found[1].Path, Equals, dangling)
+
+	found, err = CheckExports(conn, []string{"tenant"}, []string{"10.0.0.1"}, true)
+	c.Assert(err, IsNil)
+	c.Assert(found, HasLen, 2)
+	c.Check(found[0].Repaired, Equals, true)
+	c.Check(found[1].Repaired, Equals, true)
+
+	ok, err := conn.Exists("/net/export/removed")
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	ok, err = conn.Exists(dangling)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, false)
+	ok, err = conn.Exists(live)
+	c.Assert(err, IsNil)
+	c.Check(ok, Equals, true)
+}
@@ -22,6 +22,7 @@ import (
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
 )
 
 var (
@@ -50,6 +51,7 @@ type AssignmentHandler interface {
 //
 type ZKAssignmentHandler struct {
 	Timeout               time.Duration
+	FailureTimeout        time.Duration // how long a host that failed a virtual IP is skipped
 	connection            client.Connection
 	hostHandler           RegisteredHostHandler
 	hostSelectionStrategy HostSelectionStrategy
@@ -68,6 +70,7 @@ func NewZKAssignmentHandler(strategy HostSelectionStrategy,
 		connection:            connection,
 		mu:                    &sync.Mutex{},
 		Timeout:               time.Second * 10,
+		FailureTimeout:        time.Minute,
 		timeouts:              make(map[string]map[string]time.Time),
 	}
 }
@@ -100,7 +103,56 @@ func (h *ZKAssignmentHandler) Unassign(poolID, ipAddress string) error {
 	}).Debug("Unassigning IP")
 
 	request := IPRequest{PoolID: poolID, HostID: assignedHost, IPAddress: ipAddress}
-	return DeleteIP(h.connection, request)
+	if err := DeleteIP(h.connection, request); err != nil {
+		return err
+	}
+
+	recordIPEvent(h.connection, poolID, ipAddress, VirtualIPEvent{
+		Time:   time.Now(),
+		HostID: assignedHost,
+		Event:  IPUnassigned,
+	})
+	return nil
+}
+
+// MoveAssignments reassigns any of the provided virtual IPs that are currently
+// assigned to the given host.  The host should already be excluded from the
+// registered hosts, otherwise the IP may be assigned right back to it.
+// Returns the addresses that were moved.
+func MoveAssignments(conn client.Connection, handler AssignmentHandler, hostID string, vips []pool.VirtualIP) ([]string, error) {
+	moved := []string{}
+	for _, vip := range vips {
+		logger := plog.WithFields(log.Fields{
+			"poolid":    vip.PoolID,
+			"hostid":    hostID,
+			"ipaddress": vip.IP,
+		})
+
+		assignedHost, err := GetHostID(conn, vip.PoolID, vip.IP)
+		if err == ErrNoAssignedHost {
+			continue
+		} else if err != nil {
+			logger.WithError(err).Debug("Could not look up virtual IP assignment")
+			return moved, err
+		} else if assignedHost != hostID {
+			continue
+		}
+
+		if err := handler.Unassign(vip.PoolID, vip.IP); err != nil && err != ErrNoAssignedHost {
+			logger.WithError(err).Debug("Could not unassign virtual IP")
+			return moved, err
+		}
+
+		// the pool listener may have already picked up the address
+		if err := handler.Assign(vip.PoolID, vip.IP, vip.Netmask, vip.BindInterface); err != nil && err != ErrAlreadyAssigned {
+			logger.WithError(err).Debug("Could not reassign virtual IP")
+			return moved, err
+		}
+
+		logger.Debug("Moved virtual IP off of host")
+		moved = append(moved, vip.IP)
+	}
+	return moved, nil
 }
 
 func (h *ZKAssignmentHandler) getAssignedHostID(poolID, ipAddress string) (string, error) {
@@ -163,6 +215,7 @@ func (h *ZKAssignmentHandler) assignToHost(poolID, ipAddress, netmask, binding s
 	}).Debug("Found hosts")
 
 	hosts = h.filterOutExcludedHosts(ipAddress, hosts)
+	hosts = h.filterOutFailedHosts(poolID, ipAddress, hosts)
 	if len(hosts) == 0 {
 		return ErrNoHosts
 	}
@@ -172,9 +225,13 @@ func (h *ZKAssignmentHandler) assignToHost(poolID, ipAddress, netmask, binding s
 		"ipaddress": getIDString(hosts),
 	}).Debug("Selecting from hosts")
 
-	host, err := h.hostSelectionStrategy.Select(hosts)
-	if err != nil {
-		return err
+	// prefer the hosts configured on the virtual IP, in order
+	host, ok := selectPreferredHost(hosts, h.getPreferredHosts(poolID, ipAddress))
+	if !ok {
+		host, err = h.hostSelectionStrategy.Select(hosts)
+		if err != nil {
+			return err
+		}
 	}
 
 	h.addExcludeHost(ipAddress, host)
@@ -182,7 +239,54 @@ func (h *ZKAssignmentHandler) assignToHost(poolID, ipAddress, netmask, binding s
 	plog.WithField("host", host.ID).Debug("Assigning IP")
 
 	request := IPRequest{PoolID: poolID, HostID: host.ID, IPAddress: ipAddress}
-	return CreateIP(h.connection, request, netmask, binding)
+	if err := CreateIP(h.connection, request, netmask, binding); err != nil {
+		return err
+	}
+
+	recordIPEvent(h.connection, poolID, ipAddress, VirtualIPEvent{
+		Time:   time.Now(),
+		HostID: host.ID,
+		Event:  IPAssigned,
+	})
+	return nil
+}
+
+// getPreferredHosts returns the preferred hosts of a virtual IP in the pool
+func (h *ZKAssignmentHandler) getPreferredHosts(poolID, ipAddress string) []string {
+	node := &PoolNode{ResourcePool: &pool.ResourcePool{}}
+	if err := h.connection.Get(Base().Pools().ID(poolID).Path(), node); err != nil {
+		plog.WithError(err).WithField("poolid", poolID).Debug("Could not look up pool for preferred hosts")
+		return nil
+	}
+	for _, vip := range node.VirtualIPs {
+		if vip.IP == ipAddress {
+			return vip.PreferredHosts
+		}
+	}
+	return nil
+}
+
+// selectPreferredHost returns the first preferred host that is available
+func selectPreferredHost(hosts []host.Host, preferred []string) (host.Host, bool) {
+	for _, hostID := range preferred {
+		for _, h := range hosts {
+			if h.ID == hostID {
+				return h, true
+			}
+		}
+	}
+	return host.Host{}, false
+}
+
+// recordIPEvent adds to the failover history of a virtual IP.  The history is
+// informational, so failures are logged rather than returned.
+func recordIPEvent(conn client.Connection, poolID, ipAddress string, event VirtualIPEvent) {
+	if err := RecordIPEvent(conn, poolID, ipAddress, event); err != nil {
+		plog.WithError(err).WithFields(log.Fields{
+			"poolid":    poolID,
+			"ipaddress": ipAddress,
+		}).Warn("Could not record virtual ip event")
+	}
 }
 
 func (h *ZKAssignmentHandler) addExcludeHost(ipAddress string, host host.Host) {
@@ -221,6 +325,35 @@ func (h *ZKAssignmentHandler) filterOutExcludedHosts(ipAddress string, hosts []h
 	return filteredHosts
 }
 
+// filterOutFailedHosts removes the hosts that recently failed to keep the
+// virtual IP healthy, so that it fails over to a different host.
+func (h *ZKAssignmentHandler) filterOutFailedHosts(poolID, ipAddress string, hosts []host.Host) []host.Host {
+	status := &VirtualIPStatus{}
+	if err := h.connection.Get(ipStatusPath(poolID, ipAddress), status); err != nil {
+		return hosts
+	}
+
+	failedHostIDs := []string{}
+	cutoff := time.Now().Add(-h.FailureTimeout)
+	for _, event := range status.History {
+		if event.Time.Before(cutoff) {
+			break
+		}
+		if event.Event == IPFailed {
+			failedHostIDs = append(failedHostIDs, event.HostID)
+		}
+	}
+
+	filteredHosts := []host.Host{}
+	for _, host := range hosts {
+		if !containsHostID(failedHostIDs, host.ID) {
+			filteredHosts = append(filteredHosts, host)
+		}
+	}
+
+	return filteredHosts
+}
+
 func containsHostID(hostIDs []string, id string) bool {
 	for _, hostID := range hostIDs {
 		if hostID == id {
@@ -11,7 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build unit integration,!quick
 
 package service_test
 
@@ -20,6 +20,7 @@ import (
 
 	"github.com/control-center/serviced/coordinator/client"
 	h "github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/zzk"
 	. "github.com/control-center/serviced/zzk/service"
 	"github.com/control-center/serviced/zzk/service/mocks"
@@ -53,7 +54,8 @@ func (s *ZKAssignmentHandlerTestSuite) SetUpTest(c *C) {
 	s.connection, err = zzk.GetLocalConnection("/")
 	c.Assert(err, IsNil)
 
-	strategy := &mocks.HostSelectionStrategy{}
+	s.strategy = mocks.HostSelectionStrategy{}
+	strategy := &s.strategy
 	s.selectHostCall = strategy.On("Select", mock.AnythingOfType("[]host.Host")).
 		Return(s.testHost, nil)
 
@@ -129,6 +131,69 @@ func (s *ZKAssignmentHandlerTestSuite) TestExcludesHostAfterAssigning(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *ZKAssignmentHandlerTestSuite) TestAssignsPreferredHost(c *C) {
+	preferredHost := h.Host{ID: "preferredHost", PoolID: "poolid"}
+	s.registeredHostHandler.ExpectedCalls = nil
+	s.registeredHostHandler.On("GetRegisteredHosts", "poolid").
+		Return([]h.Host{s.testHost, preferredHost}, nil)
+	err := s.connection.Create(
+		Base().Pools().ID("poolid").Hosts().ID("preferredHost").Path(),
+		&HostNode{Host: &preferredHost},
+	)
+	c.Assert(err, IsNil)
+
+	err = UpdateResourcePool(s.connection, pool.ResourcePool{
+		ID: "poolid",
+		VirtualIPs: []pool.VirtualIP{
+			{PoolID: "poolid", IP: "7.7.7.7", PreferredHosts: []string{"unknownHost", "preferredHost"}},
+		},
+	})
+	c.Assert(err, IsNil)
+
+	err = s.assignmentHandler.Assign("poolid", "7.7.7.7", "netmask", "http")
+	c.Assert(err, IsNil)
+	s.assertNodeHasChildren(c, "pools/poolid/ips", []string{"preferredHost-7.7.7.7"})
+	s.strategy.AssertNotCalled(c, "Select", mock.Anything)
+}
+
+func (s *ZKAssignmentHandlerTestSuite) TestRecordsHistory(c *C) {
+	err := s.assignmentHandler.Assign("poolid", "7.7.7.7", "netmask", "http")
+	c.Assert(err, IsNil)
+
+	statuses, err := GetIPStatuses(s.connection, "poolid")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].HostID, Equals, "testHost")
+	c.Assert(statuses[0].History, HasLen, 1)
+	c.Assert(statuses[0].History[0].Event, Equals, IPAssigned)
+
+	err = s.assignmentHandler.Unassign("poolid", "7.7.7.7")
+	c.Assert(err, IsNil)
+
+	statuses, err = GetIPStatuses(s.connection, "poolid")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].HostID, Equals, "")
+	c.Assert(statuses[0].History, HasLen, 2)
+	c.Assert(statuses[0].History[0].Event, Equals, IPUnassigned)
+}
+
+func (s *ZKAssignmentHandlerTestSuite) TestExcludesFailedHost(c *C) {
+	err := RecordIPEvent(s.connection, "poolid", "7.7.7.7", VirtualIPEvent{
+		Time:   time.Now(),
+		HostID: "testHost",
+		Event:  IPFailed,
+	})
+	c.Assert(err, IsNil)
+
+	err = s.assignmentHandler.Assign("poolid", "7.7.7.7", "netmask", "http")
+	c.Assert(err, Equals, ErrNoHosts)
+
+	s.assignmentHandler.FailureTimeout = 0
+	err = s.assignmentHandler.Assign("poolid", "7.7.7.7", "netmask", "http")
+	c.Assert(err, IsNil)
+}
+
 func (s *ZKAssignmentHandlerTestSuite) assertNodeHasChildren(c *C, path string, children []string) {
 	obtained, err := s.connection.Children(path)
 	c.Assert(err, IsNil)
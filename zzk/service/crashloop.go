@@ -0,0 +1,59 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import "time"
+
+const (
+	// crashMinLifespan is the least amount of time a container must run for
+	// its exit not to be counted as a crash.
+	crashMinLifespan = 60 * time.Second
+
+	// crashLoopThreshold is the number of consecutive crashes after which an
+	// instance is considered to be crash looping.
+	crashLoopThreshold = 3
+
+	// crashBackoffBase is the delay before restarting an instance after its
+	// second consecutive crash; it doubles with every crash thereafter.
+	crashBackoffBase = 5 * time.Second
+
+	// crashBackoffMax is the longest an instance will wait to be restarted.
+	crashBackoffMax = 5 * time.Minute
+)
+
+// isCrash returns true if the container exited on its own shortly after it
+// was started.  Exits caused by a restart are not counted.
+func isCrash(ssdat *ServiceState, timeExit time.Time) bool {
+	if ssdat.Started.IsZero() || !ssdat.Restarted.Before(ssdat.Started) {
+		return false
+	}
+	return timeExit.Sub(ssdat.Started) < crashMinLifespan
+}
+
+// crashBackoff returns how long to wait before restarting an instance that
+// has crashed the given number of consecutive times.  The first crash is
+// restarted immediately.
+func crashBackoff(crashes int) time.Duration {
+	if crashes < 2 {
+		return 0
+	}
+	backoff := crashBackoffBase
+	for i := 2; i < crashes; i++ {
+		backoff *= 2
+		if backoff >= crashBackoffMax {
+			return crashBackoffMax
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type CrashLoopTestSuite struct{}
+
+var _ = Suite(&CrashLoopTestSuite{})
+
+func (s *CrashLoopTestSuite) TestIsCrash(c *C) {
+	started := time.Now()
+
+	// never started
+	c.Check(isCrash(&ServiceState{}, started), Equals, false)
+
+	// exited right away
+	ssdat := &ServiceState{Started: started}
+	c.Check(isCrash(ssdat, started.Add(time.Second)), Equals, true)
+
+	// ran long enough
+	c.Check(isCrash(ssdat, started.Add(crashMinLifespan)), Equals, false)
+
+	// restarted
+	ssdat.Restarted = started.Add(time.Second)
+	c.Check(isCrash(ssdat, started.Add(2*time.Second)), Equals, false)
+}
+
+func (s *CrashLoopTestSuite) TestCrashBackoff(c *C) {
+	c.Check(crashBackoff(0), Equals, time.Duration(0))
+	c.Check(crashBackoff(1), Equals, time.Duration(0))
+	c.Check(crashBackoff(2), Equals, crashBackoffBase)
+	c.Check(crashBackoff(3), Equals, 2*crashBackoffBase)
+	c.Check(crashBackoff(4), Equals, 4*crashBackoffBase)
+	c.Check(crashBackoff(100), Equals, crashBackoffMax)
+}
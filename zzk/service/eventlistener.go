@@ -0,0 +1,317 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+	"strconv"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/events"
+)
+
+// EventPublisher receives the cluster state changes observed by the event
+// listeners.
+type EventPublisher interface {
+	PublishEvent(e events.Event)
+}
+
+// InstanceEventListener watches the instances of each service in a pool and
+// publishes an event whenever an instance starts, stops or fails.
+type InstanceEventListener struct {
+	conn      client.Connection
+	poolid    string
+	publisher EventPublisher
+}
+
+// NewInstanceEventListener instantiates a new InstanceEventListener
+func NewInstanceEventListener(poolid string, publisher EventPublisher) *InstanceEventListener {
+	return &InstanceEventListener{poolid: poolid, publisher: publisher}
+}
+
+// SetConnection implements zzk.Listener
+func (l *InstanceEventListener) SetConnection(conn client.Connection) { l.conn = conn }
+
+// GetPath implements zzk.Listener
+func (l *InstanceEventListener) GetPath(nodes ...string) string {
+	base := append([]string{"/pools", l.poolid, "services"}, nodes...)
+	return path.Join(base...)
+}
+
+// Ready implements zzk.Listener
+func (l *InstanceEventListener) Ready() error { return nil }
+
+// Done implements zzk.Listener
+func (l *InstanceEventListener) Done() {}
+
+// PostProcess implements zzk.Listener
+func (l *InstanceEventListener) PostProcess(p map[string]struct{}) {}
+
+// Spawn watches the instances of a service and starts a watch on each new
+// instance.
+func (l *InstanceEventListener) Spawn(shutdown <-chan interface{}, serviceID string) {
+	logger := plog.WithFields(log.Fields{
+		"poolid":    l.poolid,
+		"serviceid": serviceID,
+	})
+
+	// removed is closed when an instance disappears from the service
+	instances := make(map[string]chan struct{})
+	var wg sync.WaitGroup
+
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		for _, removed := range instances {
+			close(removed)
+		}
+		wg.Wait()
+	}()
+
+	for {
+		ch, ev, err := l.conn.ChildrenW(l.GetPath(serviceID), done)
+		if err == client.ErrNoNode {
+			logger.Debug("Service has been removed; stopping instance event listener")
+			return
+		} else if err != nil {
+			logger.WithError(err).Error("Could not watch instances of service")
+			return
+		}
+
+		current := make(map[string]struct{})
+		for _, stateID := range ch {
+			current[stateID] = struct{}{}
+			if _, ok := instances[stateID]; ok {
+				continue
+			}
+			hostID, _, instanceID, err := ParseStateID(stateID)
+			if err != nil {
+				logger.WithField("stateid", stateID).WithError(err).Debug("Could not parse state")
+				continue
+			}
+			removed := make(chan struct{})
+			instances[stateID] = removed
+			wg.Add(1)
+			go func(stateID, hostID string, instanceID int) {
+				defer wg.Done()
+				l.watchInstance(shutdown, removed, serviceID, stateID, hostID, instanceID)
+			}(stateID, hostID, instanceID)
+		}
+		for stateID, removed := range instances {
+			if _, ok := current[stateID]; !ok {
+				close(removed)
+				delete(instances, stateID)
+			}
+		}
+
+		select {
+		case <-ev:
+		case <-shutdown:
+			return
+		}
+		close(done)
+		done = make(chan struct{})
+	}
+}
+
+// watchInstance publishes the transitions of an instance's current state
+// until the instance is removed or the listener shuts down.  The state the
+// instance is first seen in is not published, so that electing a new leader
+// does not replay the state of every running instance.
+func (l *InstanceEventListener) watchInstance(shutdown <-chan interface{}, removed <-chan struct{}, serviceID, stateID, hostID string, instanceID int) {
+	logger := plog.WithFields(log.Fields{
+		"poolid":     l.poolid,
+		"serviceid":  serviceID,
+		"hostid":     hostID,
+		"instanceid": instanceID,
+	})
+
+	publish := func(typ events.Type, detail map[string]string) {
+		l.publisher.PublishEvent(events.Event{
+			Type:       typ,
+			ServiceID:  serviceID,
+			HostID:     hostID,
+			InstanceID: instanceID,
+			Detail:     detail,
+		})
+	}
+
+	var last *CurrentStateContainer
+	stopped := func() {
+		if last != nil && last.Status != service.StateStopped && last.Status != service.StateEmergencyStopped {
+			publish(events.InstanceStopped, map[string]string{"status": string(service.StateStopped)})
+		}
+	}
+
+	done := make(chan struct{})
+	defer func() { close(done) }()
+	for {
+		cs := &CurrentStateContainer{}
+		ev, err := l.conn.GetW(l.GetPath(serviceID, stateID, "current"), cs, done)
+		if err == client.ErrNoNode {
+			stopped()
+			return
+		} else if err != nil {
+			logger.WithError(err).Debug("Could not watch current state of instance")
+			return
+		}
+
+		if last != nil {
+			if typ, ok := instanceTransition(last, cs); ok {
+				detail := map[string]string{"status": string(cs.Status)}
+				if cs.Crashes > 0 {
+					detail["crashes"] = strconv.Itoa(cs.Crashes)
+				}
+				publish(typ, detail)
+			}
+		}
+		last = cs
+
+		select {
+		case <-ev:
+		case <-removed:
+			stopped()
+			return
+		case <-shutdown:
+			return
+		}
+		close(done)
+		done = make(chan struct{})
+	}
+}
+
+// instanceTransition returns the type of event, if any, that describes the
+// change of an instance from one current state to the next.
+func instanceTransition(prev, next *CurrentStateContainer) (events.Type, bool) {
+	switch {
+	case next.Status == service.StateCrashLoop && prev.Status != service.StateCrashLoop:
+		return events.InstanceFailed, true
+	case next.Crashes > prev.Crashes:
+		return events.InstanceFailed, true
+	case next.Status == service.StateRunning && prev.Status != service.StateRunning:
+		return events.InstanceStarted, true
+	case (next.Status == service.StateStopped || next.Status == service.StateEmergencyStopped) &&
+		prev.Status != service.StateStopped && prev.Status != service.StateEmergencyStopped:
+		return events.InstanceStopped, true
+	}
+	return "", false
+}
+
+// HostEventListener watches the hosts in a pool and publishes an event
+// whenever a host comes online or goes offline.
+type HostEventListener struct {
+	conn      client.Connection
+	poolid    string
+	publisher EventPublisher
+}
+
+// NewHostEventListener instantiates a new HostEventListener
+func NewHostEventListener(poolid string, publisher EventPublisher) *HostEventListener {
+	return &HostEventListener{poolid: poolid, publisher: publisher}
+}
+
+// SetConnection implements zzk.Listener
+func (l *HostEventListener) SetConnection(conn client.Connection) { l.conn = conn }
+
+// GetPath implements zzk.Listener
+func (l *HostEventListener) GetPath(nodes ...string) string {
+	base := append([]string{"/pools", l.poolid, "hosts"}, nodes...)
+	return path.Join(base...)
+}
+
+// Ready implements zzk.Listener
+func (l *HostEventListener) Ready() error { return nil }
+
+// Done implements zzk.Listener
+func (l *HostEventListener) Done() {}
+
+// PostProcess implements zzk.Listener
+func (l *HostEventListener) PostProcess(p map[string]struct{}) {}
+
+// Spawn watches the online status of a host.  As with instances, the status
+// the host is first seen in is not published.
+func (l *HostEventListener) Spawn(shutdown <-chan interface{}, hostID string) {
+	logger := plog.WithFields(log.Fields{
+		"poolid": l.poolid,
+		"hostid": hostID,
+	})
+
+	first := true
+	isOnline := false
+
+	done := make(chan struct{})
+	defer func() { close(done) }()
+	for {
+		// path: /pools/<poolid>/hosts/<hostid>
+		exists, availEv, err := l.conn.ExistsW(l.GetPath(hostID), done)
+		if err != nil {
+			logger.WithError(err).Debug("Could not look up host")
+			return
+		}
+		if !exists {
+			if isOnline {
+				l.publish(events.HostOffline, hostID)
+			}
+			return
+		}
+
+		// path: /pools/<poolid>/hosts/<hostid>/online
+		onlinepth := l.GetPath(hostID, "online")
+		online, onlineEv, err := l.conn.ExistsW(onlinepth, done)
+		if err != nil {
+			logger.WithError(err).Debug("Could not check online status of host")
+			return
+		}
+		if online {
+			ch, childEv, err := l.conn.ChildrenW(onlinepth, done)
+			if err == nil {
+				onlineEv = childEv
+			} else if err != client.ErrNoNode {
+				logger.WithError(err).Debug("Could not verify online status of host")
+				return
+			}
+			online = len(ch) > 0
+		}
+
+		if !first && online != isOnline {
+			if online {
+				l.publish(events.HostOnline, hostID)
+			} else {
+				l.publish(events.HostOffline, hostID)
+			}
+		}
+		first = false
+		isOnline = online
+
+		select {
+		case <-availEv:
+		case <-onlineEv:
+		case <-shutdown:
+			return
+		}
+		close(done)
+		done = make(chan struct{})
+	}
+}
+
+func (l *HostEventListener) publish(typ events.Type, hostID string) {
+	l.publisher.PublishEvent(events.Event{
+		Type:   typ,
+		HostID: hostID,
+		Detail: map[string]string{"poolId": l.poolid},
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/events"
+	. "gopkg.in/check.v1"
+)
+
+type EventListenerTestSuite struct{}
+
+var _ = Suite(&EventListenerTestSuite{})
+
+func (s *EventListenerTestSuite) TestInstanceTransition(c *C) {
+	state := func(status service.InstanceCurrentState, crashes int) *CurrentStateContainer {
+		return &CurrentStateContainer{Status: status, Crashes: crashes}
+	}
+
+	typ, ok := instanceTransition(state(service.StateStarting, 0), state(service.StateRunning, 0))
+	c.Check(ok, Equals, true)
+	c.Check(typ, Equals, events.InstanceStarted)
+
+	_, ok = instanceTransition(state(service.StateRunning, 0), state(service.StateRunning, 0))
+	c.Check(ok, Equals, false)
+
+	typ, ok = instanceTransition(state(service.StateStopping, 0), state(service.StateStopped, 0))
+	c.Check(ok, Equals, true)
+	c.Check(typ, Equals, events.InstanceStopped)
+
+	typ, ok = instanceTransition(state(service.StateEmergencyStopping, 0), state(service.StateEmergencyStopped, 0))
+	c.Check(ok, Equals, true)
+	c.Check(typ, Equals, events.InstanceStopped)
+
+	_, ok = instanceTransition(state(service.StateEmergencyStopped, 0), state(service.StateStopped, 0))
+	c.Check(ok, Equals, false)
+
+	// a crash is a failure even if the instance is restarted right away
+	typ, ok = instanceTransition(state(service.StateRunning, 0), state(service.StateStarting, 1))
+	c.Check(ok, Equals, true)
+	c.Check(typ, Equals, events.InstanceFailed)
+
+	typ, ok = instanceTransition(state(service.StateStarting, 3), state(service.StateCrashLoop, 3))
+	c.Check(ok, Equals, true)
+	c.Check(typ, Equals, events.InstanceFailed)
+
+	_, ok = instanceTransition(state(service.StateCrashLoop, 3), state(service.StateCrashLoop, 3))
+	c.Check(ok, Equals, false)
+}
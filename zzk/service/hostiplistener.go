@@ -16,6 +16,7 @@ package service
 import (
 	"path"
 	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
@@ -31,10 +32,16 @@ type HostIPHandler interface {
 	// Release removes a virtual ip from an interface.  If the interface doesn't
 	// exist, then this command returns nil.
 	ReleaseIP(ip string) error
+
+	// CheckIP returns an error if a bound virtual ip is no longer healthy on
+	// the interface.
+	CheckIP(ip, iface string) error
 }
 
 // HostIPListener is the listener for monitoring virtual ips.
 type HostIPListener struct {
+	ProbeInterval time.Duration // how often to check the health of bound ips
+
 	hostid  string
 	handler HostIPHandler
 	conn    client.Connection
@@ -53,11 +60,12 @@ func NewHostIPListener(hostid string, handler HostIPHandler, binds []string) *Ho
 	}
 
 	return &HostIPListener{
-		hostid:  hostid,
-		handler: handler,
-		active:  &sync.WaitGroup{},
-		passive: passive,
-		mu:      &sync.Mutex{},
+		ProbeInterval: 10 * time.Second,
+		hostid:        hostid,
+		handler:       handler,
+		active:        &sync.WaitGroup{},
+		passive:       passive,
+		mu:            &sync.Mutex{},
 	}
 }
 
@@ -189,11 +197,33 @@ func (l *HostIPListener) Spawn(cancel <-chan struct{}, ipid string) {
 			return
 		}
 
-		select {
-		case <-hevt:
-		case <-pevt:
-		case <-cancel:
+		// wait for changes, and probe the health of the binding until then
+		probe := time.NewTimer(l.ProbeInterval)
+	wait:
+		for {
+			select {
+			case <-hevt:
+				break wait
+			case <-pevt:
+				break wait
+			case <-cancel:
+				break wait
+			case <-probe.C:
+				if err := l.handler.CheckIP(ipaddr, hdat.BindInterface); err != nil {
+					logger.WithError(err).Warn("Virtual ip failed its health check, releasing")
+					recordIPEvent(l.conn, "", ipaddr, VirtualIPEvent{
+						Time:   time.Now(),
+						HostID: l.hostid,
+						Event:  IPFailed,
+						Reason: err.Error(),
+					})
+					l.release(req)
+					return
+				}
+				probe.Reset(l.ProbeInterval)
+			}
 		}
+		probe.Stop()
 
 		// cancel takes precedence
 		select {
@@ -11,7 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build unit integration,!quick
 
 package service_test
 
@@ -344,3 +344,24 @@ func (t *HostIPListenerSuite) TestSpawn_StartsCancels(c *C) {
 	assertCancels("199.18.12.1")
 	assertCancels(testcacheip)
 }
+
+func (t *HostIPListenerSuite) TestSpawn_ProbeFails(c *C) {
+	const iface = "dummy0"
+	t.listener.ProbeInterval = 700 * time.Millisecond
+	t.handler.On("CheckIP", testcacheip, iface).Return(nil).Once()
+	t.handler.On("CheckIP", testcacheip, iface).Return(errors.New("interface is down")).Once()
+	t.handler.On("ReleaseIP", testcacheip).Return(nil).Once()
+
+	cancel := make(chan struct{})
+	defer close(cancel)
+	req, done := t.assertSpawnStarts(c, cancel, testcacheip)
+	t.assertSpawnStops(c, done)
+	t.assertClean(c, req.IPID())
+
+	statuses, err := GetIPStatuses(t.conn, "")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].History[0].HostID, Equals, testhostid)
+	c.Assert(statuses[0].History[0].Event, Equals, IPFailed)
+	c.Assert(statuses[0].History[0].Reason, Equals, "interface is down")
+}
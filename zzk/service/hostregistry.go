@@ -404,6 +404,12 @@ func GetRegisteredHostsForPool(conn client.Connection, poolID string) ([]host.Ho
 					// TODO: wrap error?
 					return nil, err
 				}
+
+				// drained hosts are not available for scheduling
+				if hdat.Unschedulable {
+					hstlog.Debug("Host is unschedulable")
+					continue
+				}
 				hosts = append(hosts, hdat)
 			}
 		}
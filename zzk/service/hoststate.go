@@ -15,6 +15,7 @@ package service
 
 import (
 	"path"
+	"strconv"
 	"sync"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/trace"
 )
 
 // HostStateHandler is the handler for running the HostListener
@@ -42,7 +44,7 @@ type HostStateHandler interface {
 	// channel that triggers when the container has stopped.
 	StartContainer(cancel <-chan interface{}, serviceID string, instanceID int) (*ServiceState, <-chan time.Time, error)
 
-	// RestartContainer asynchronously prepulls the latest image before
+	// RestartContainer asynchronously prepulls the current service image before
 	// stopping the container.  It only returns an error if there is a problem
 	// with docker and not if the container is not running or doesn't exist.
 	RestartContainer(cancel <-chan interface{}, serviceID string, instanceID int) error
@@ -194,6 +196,9 @@ func (l *HostStateListener) Spawn(cancel <-chan interface{}, stateID string) {
 	done := make(chan struct{})
 	defer func() { close(done) }()
 
+	// restartAt is when a crashed container may be started again
+	var restartAt time.Time
+
 	for {
 		// set up a listener on the host state node
 		hspth := l.GetPath(stateID)
@@ -219,10 +224,20 @@ func (l *HostStateListener) Spawn(cancel <-chan interface{}, stateID string) {
 			return
 		}
 
-		// set the state of this instance
-		containerExit, ssdat, ok = l.setInstanceState(containerExit, ssdat, hsdat, stateID, serviceID, instanceID, req, logger)
-		if !ok {
-			return
+		// set the state of this instance, unless a crashed container is
+		// waiting to be restarted
+		var backoff <-chan time.Time
+		if hsdat.DesiredState != service.SVCRun {
+			restartAt = time.Time{}
+		}
+		if wait := restartAt.Sub(time.Now()); containerExit == nil && wait > 0 {
+			logger.WithField("backoff", wait).Debug("Waiting to restart crashed container")
+			backoff = time.After(wait)
+		} else {
+			containerExit, ssdat, ok = l.setInstanceState(containerExit, ssdat, hsdat, stateID, serviceID, instanceID, req, logger)
+			if !ok {
+				return
+			}
 		}
 
 		var ipevt <-chan client.Event
@@ -264,11 +279,15 @@ func (l *HostStateListener) Spawn(cancel <-chan interface{}, stateID string) {
 				logger.WithField("ip", ireq.IPID()).Infof("Host state listener received signal from host ip connection: %s", ipEvent.Err.Error())
 			}
 		case timeExit := <-containerExit:
-			ssdat, ok = l.handleContainerExit(timeExit, ssdat, stateID, req, logger)
+			var wait time.Duration
+			ssdat, wait, ok = l.handleContainerExit(timeExit, ssdat, stateID, req, logger)
 			if !ok {
 				return
 			}
+			restartAt = timeExit.Add(wait)
 			containerExit = nil
+		case <-backoff:
+			logger.Debug("Restarting crashed container")
 		case <-cancel:
 			logger.Debug("Host state listener received signal to cancel listening")
 			return
@@ -283,6 +302,16 @@ func (l *HostStateListener) Spawn(cancel <-chan interface{}, stateID string) {
 	}
 }
 
+// traceStart begins a span for starting an instance if the request that
+// scheduled it was traced.
+func (l *HostStateListener) traceStart(hsdat *HostState, serviceID string, instanceID int) *trace.Span {
+	span := trace.Follow("HostStateListener.StartContainer", trace.Parse(hsdat.Trace))
+	span.SetAttribute("hostid", l.hostID)
+	span.SetAttribute("serviceid", serviceID)
+	span.SetAttribute("instanceid", strconv.Itoa(instanceID))
+	return span
+}
+
 func (l *HostStateListener) setInstanceState(containerExit <-chan time.Time, ssdat *ServiceState, hsdat *HostState,
 	stateID, serviceID string, instanceID int, req StateRequest, logger *log.Entry) (<-chan time.Time, *ServiceState, bool) {
 
@@ -306,7 +335,10 @@ func (l *HostStateListener) setInstanceState(containerExit <-chan time.Time, ssd
 	case service.SVCRun:
 		if containerExit == nil {
 			// container is detached because it doesn't exist
+			span := l.traceStart(hsdat, serviceID, instanceID)
+			logger = logger.WithFields(span.Fields())
 			ssdat, containerExit, err = l.handler.StartContainer(l.shutdown, serviceID, instanceID)
+			span.Finish(err)
 			if err != nil {
 				logger.WithError(err).Error("Could not start container")
 				l.cleanUpContainers([]string{stateID}, true)
@@ -425,7 +457,7 @@ func (l *HostStateListener) setInstanceState(containerExit <-chan time.Time, ssd
 }
 
 func (l *HostStateListener) handleContainerExit(timeExit time.Time, ssdat *ServiceState, stateID string,
-	req StateRequest, logger *log.Entry) (*ServiceState, bool) {
+	req StateRequest, logger *log.Entry) (*ServiceState, time.Duration, bool) {
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -433,22 +465,50 @@ func (l *HostStateListener) handleContainerExit(timeExit time.Time, ssdat *Servi
 	// Don't do anything if we are shutting down, the shutdown cleanup will handle it
 	select {
 	case <-l.shutdown:
-		return nil, false
+		return nil, 0, false
 	default:
 	}
 
 	// set the service state
+	crashed := isCrash(ssdat, timeExit)
 	ssdat.Terminated = timeExit
 	l.setExistingThread(stateID, ssdat, nil)
-	logger.WithField("terminated", timeExit).Warn("Container exited, restarting")
 
-	if err := l.updateServiceStateInZK(ssdat, req); err != nil {
+	// count consecutive crashes
+	crashes := 0
+	if err := UpdateState(l.conn, req, func(s *State) bool {
+		s.ServiceState = *ssdat
+		if crashed {
+			s.Crashes++
+		} else {
+			s.Crashes = 0
+		}
+		if s.Crashes >= crashLoopThreshold {
+			s.Status = service.StateCrashLoop
+		}
+		crashes = s.Crashes
+		return true
+	}); err != nil {
 		logger.WithError(err).Error("Could not set state for stopped container")
 		// TODO: we currently don't support containers restarting if
 		// shut down during an outage, so don't bother
-		return nil, false
+		return nil, 0, false
+	}
+
+	backoff := crashBackoff(crashes)
+	logger = logger.WithFields(log.Fields{
+		"terminated": timeExit,
+		"crashes":    crashes,
+		"backoff":    backoff,
+	})
+	if crashes == crashLoopThreshold {
+		// the InstanceEventListener publishes an instance.failed event when
+		// it sees the crash loop status
+		logger.Warn("Container is crash looping, backing off restarts")
+	} else {
+		logger.Warn("Container exited, restarting")
 	}
-	return ssdat, true
+	return ssdat, backoff, true
 }
 
 // Gets a list of state IDs for all existing threads
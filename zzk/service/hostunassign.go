@@ -14,6 +14,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/control-center/serviced/coordinator/client"
 )
 
@@ -73,6 +75,13 @@ func (h *ZKHostUnassignmentHandler) UnassignAll(poolID, hostID string) error {
 		if err != nil {
 			return err
 		}
+
+		recordIPEvent(h.connection, poolID, ip, VirtualIPEvent{
+			Time:   time.Now(),
+			HostID: hostID,
+			Event:  IPFailed,
+			Reason: "host is offline",
+		})
 	}
 
 	return nil
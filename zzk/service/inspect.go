@@ -0,0 +1,460 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// InconsistencyType describes how the coordinator differs from the database
+type InconsistencyType string
+
+const (
+	// MissingNode is an object in the database without a node in the
+	// coordinator
+	MissingNode InconsistencyType = "missing"
+
+	// UnknownNode is a node in the coordinator without an object in the
+	// database
+	UnknownNode InconsistencyType = "unknown"
+
+	// InvalidState is an instance node whose state id cannot be parsed
+	InvalidState InconsistencyType = "invalid state"
+
+	// OrphanState is an instance that is missing its host state, service
+	// state or status
+	OrphanState InconsistencyType = "orphan state"
+
+	// StaleLock is a lock that is not held by any running operation
+	StaleLock InconsistencyType = "stale lock"
+
+	// DanglingExport is an exported endpoint that does not belong to a
+	// running instance
+	DanglingExport InconsistencyType = "dangling export"
+)
+
+// Inconsistency is a difference between the coordinator and the database
+type Inconsistency struct {
+	Type     InconsistencyType
+	Path     string
+	Message  string
+	Repaired bool
+}
+
+// FacadeView is the data that the facade expects to find in the coordinator
+type FacadeView struct {
+	Pools     []pool.ResourcePool
+	Hosts     map[string][]host.Host       // hosts by pool id
+	Services  map[string][]service.Service // services by pool id
+	TenantIDs []string
+}
+
+// markRepaired flags all of the inconsistencies as repaired
+func markRepaired(found []Inconsistency) {
+	for i := range found {
+		found[i].Repaired = true
+	}
+}
+
+// diffChildren compares the children of a node with a list of ids.  It
+// returns the ids that are missing from the coordinator and the children
+// that are unknown to the database, both sorted.
+func diffChildren(conn client.Connection, pth string, ids []string) ([]string, []string, error) {
+	ch, err := conn.Children(pth)
+	if err != nil && err != client.ErrNoNode {
+		return nil, nil, err
+	}
+
+	chmap := make(map[string]struct{})
+	for _, id := range ch {
+		chmap[id] = struct{}{}
+	}
+
+	missing := []string{}
+	for _, id := range ids {
+		if _, ok := chmap[id]; ok {
+			delete(chmap, id)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	unknown := []string{}
+	for id := range chmap {
+		unknown = append(unknown, id)
+	}
+
+	sort.Strings(missing)
+	sort.Strings(unknown)
+	return missing, unknown, nil
+}
+
+// CheckResourcePools compares the resource pools in the coordinator with the
+// provided list and optionally synchronizes them.
+func CheckResourcePools(conn client.Connection, pools []pool.ResourcePool, repair bool) ([]Inconsistency, error) {
+	logger := plog.WithField("zkpath", "/pools")
+
+	ids := make([]string, len(pools))
+	for i, p := range pools {
+		ids[i] = p.ID
+	}
+
+	missing, unknown, err := diffChildren(conn, "/pools", ids)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up resource pools")
+		return nil, err
+	}
+
+	found := []Inconsistency{}
+	for _, id := range missing {
+		found = append(found, Inconsistency{
+			Type:    MissingNode,
+			Path:    path.Join("/pools", id),
+			Message: fmt.Sprintf("resource pool %s is not in the coordinator", id),
+		})
+	}
+	for _, id := range unknown {
+		found = append(found, Inconsistency{
+			Type:    UnknownNode,
+			Path:    path.Join("/pools", id),
+			Message: fmt.Sprintf("resource pool %s is not in the database", id),
+		})
+	}
+
+	if repair && len(found) > 0 {
+		if err := SyncResourcePools(conn, pools); err != nil {
+			return nil, err
+		}
+		markRepaired(found)
+		logger.WithField("count", len(found)).Info("Repaired resource pools in the coordinator")
+	}
+	return found, nil
+}
+
+// CheckHosts compares the hosts in the coordinator with the provided list and
+// optionally synchronizes them. (uses a pool-based connection)
+func CheckHosts(conn client.Connection, poolID string, hosts []host.Host, repair bool) ([]Inconsistency, error) {
+	basepth := path.Join("/pools", poolID, "hosts")
+	logger := plog.WithFields(log.Fields{
+		"poolid": poolID,
+		"zkpath": basepth,
+	})
+
+	ids := make([]string, len(hosts))
+	for i, h := range hosts {
+		ids[i] = h.ID
+	}
+
+	missing, unknown, err := diffChildren(conn, "/hosts", ids)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up hosts")
+		return nil, err
+	}
+
+	found := []Inconsistency{}
+	for _, id := range missing {
+		found = append(found, Inconsistency{
+			Type:    MissingNode,
+			Path:    path.Join(basepth, id),
+			Message: fmt.Sprintf("host %s is not in the coordinator", id),
+		})
+	}
+	for _, id := range unknown {
+		found = append(found, Inconsistency{
+			Type:    UnknownNode,
+			Path:    path.Join(basepth, id),
+			Message: fmt.Sprintf("host %s is not in the database", id),
+		})
+	}
+
+	if repair && len(found) > 0 {
+		if err := SyncHosts(conn, hosts); err != nil {
+			return nil, err
+		}
+		markRepaired(found)
+		logger.WithField("count", len(found)).Info("Repaired hosts in the coordinator")
+	}
+	return found, nil
+}
+
+// CheckServices compares the services in the coordinator with the provided
+// list and optionally synchronizes them. (uses a pool-based connection)
+func CheckServices(conn client.Connection, poolID string, svcs []service.Service, repair bool) ([]Inconsistency, error) {
+	basepth := path.Join("/pools", poolID, "services")
+	logger := plog.WithFields(log.Fields{
+		"poolid": poolID,
+		"zkpath": basepth,
+	})
+
+	ids := make([]string, len(svcs))
+	for i, s := range svcs {
+		ids[i] = s.ID
+	}
+
+	missing, unknown, err := diffChildren(conn, "/services", ids)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up services")
+		return nil, err
+	}
+
+	found := []Inconsistency{}
+	for _, id := range missing {
+		found = append(found, Inconsistency{
+			Type:    MissingNode,
+			Path:    path.Join(basepth, id),
+			Message: fmt.Sprintf("service %s is not in the coordinator", id),
+		})
+	}
+	for _, id := range unknown {
+		found = append(found, Inconsistency{
+			Type:    UnknownNode,
+			Path:    path.Join(basepth, id),
+			Message: fmt.Sprintf("service %s is not in the database", id),
+		})
+	}
+
+	if repair && len(found) > 0 {
+		if err := SyncServices(conn, svcs); err != nil {
+			return nil, err
+		}
+		markRepaired(found)
+		logger.WithField("count", len(found)).Info("Repaired services in the coordinator")
+	}
+	return found, nil
+}
+
+// CheckStates looks for host and service states that cannot be parsed or
+// are missing their counterpart, and optionally deletes them. (uses a
+// pool-based connection)
+func CheckStates(conn client.Connection, poolID string, repair bool) ([]Inconsistency, error) {
+	basepth := path.Join("/pools", poolID)
+	logger := plog.WithField("poolid", poolID)
+
+	found := []Inconsistency{}
+	reported := make(map[string]struct{})
+
+	hostIDs, err := conn.Children("/hosts")
+	if err != nil && err != client.ErrNoNode {
+		logger.WithError(err).Debug("Could not look up hosts")
+		return nil, err
+	}
+	sort.Strings(hostIDs)
+
+	for _, hostID := range hostIDs {
+		stateIDs, err := conn.Children(path.Join("/hosts", hostID, "instances"))
+		if err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			logger.WithField("hostid", hostID).WithError(err).Debug("Could not look up states on host")
+			return nil, err
+		}
+		sort.Strings(stateIDs)
+
+		for _, stateID := range stateIDs {
+			pth := path.Join(basepth, "hosts", hostID, "instances", stateID)
+			_, serviceID, instanceID, err := ParseStateID(stateID)
+			if err != nil {
+				found = append(found, Inconsistency{
+					Type:    InvalidState,
+					Path:    pth,
+					Message: fmt.Sprintf("host state %s has an invalid id", stateID),
+				})
+				continue
+			}
+
+			req := StateRequest{HostID: hostID, ServiceID: serviceID, InstanceID: instanceID}
+			if ok, err := IsValidState(conn, req); err != nil {
+				return nil, err
+			} else if !ok {
+				found = append(found, Inconsistency{
+					Type:    OrphanState,
+					Path:    pth,
+					Message: fmt.Sprintf("host state %s has no matching service state", stateID),
+				})
+				reported[stateID] = struct{}{}
+			}
+		}
+	}
+
+	serviceIDs, err := conn.Children("/services")
+	if err != nil && err != client.ErrNoNode {
+		logger.WithError(err).Debug("Could not look up services")
+		return nil, err
+	}
+	sort.Strings(serviceIDs)
+
+	for _, serviceID := range serviceIDs {
+		stateIDs, err := conn.Children(path.Join("/services", serviceID))
+		if err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			logger.WithField("serviceid", serviceID).WithError(err).Debug("Could not look up states on service")
+			return nil, err
+		}
+		sort.Strings(stateIDs)
+
+		for _, stateID := range stateIDs {
+			pth := path.Join(basepth, "services", serviceID, stateID)
+			hostID, _, instanceID, err := ParseStateID(stateID)
+			if err != nil {
+				found = append(found, Inconsistency{
+					Type:    InvalidState,
+					Path:    pth,
+					Message: fmt.Sprintf("service state %s has an invalid id", stateID),
+				})
+				continue
+			}
+
+			// the host side has already reported this instance
+			if _, ok := reported[stateID]; ok {
+				continue
+			}
+
+			req := StateRequest{HostID: hostID, ServiceID: serviceID, InstanceID: instanceID}
+			if ok, err := IsValidState(conn, req); err != nil {
+				return nil, err
+			} else if !ok {
+				found = append(found, Inconsistency{
+					Type:    OrphanState,
+					Path:    pth,
+					Message: fmt.Sprintf("service state %s has no matching host state", stateID),
+				})
+			}
+		}
+	}
+
+	if repair && len(found) > 0 {
+		for _, hostID := range hostIDs {
+			if err := CleanHostStates(conn, "", hostID); err != nil {
+				return nil, err
+			}
+		}
+		for _, serviceID := range serviceIDs {
+			if err := CleanServiceStates(conn, "", serviceID); err != nil {
+				return nil, err
+			}
+		}
+		markRepaired(found)
+		logger.WithField("count", len(found)).Info("Repaired instance states in the coordinator")
+	}
+	return found, nil
+}
+
+// CheckLocks looks for services that are still flagged as locked and for
+// scheduling locks on offline hosts, and optionally releases them.  The
+// caller must ensure that no operation that locks services is in progress,
+// otherwise its locks are reported as stale. (uses a pool-based connection)
+func CheckLocks(conn client.Connection, poolID string, repair bool) ([]Inconsistency, error) {
+	basepth := path.Join("/pools", poolID)
+	logger := plog.WithField("poolid", poolID)
+
+	found := []Inconsistency{}
+
+	// services whose lock flag outlived the operation that set it
+	serviceIDs, err := conn.Children("/services")
+	if err != nil && err != client.ErrNoNode {
+		logger.WithError(err).Debug("Could not look up services")
+		return nil, err
+	}
+	sort.Strings(serviceIDs)
+
+	for _, serviceID := range serviceIDs {
+		pth := path.Join("/services", serviceID)
+		node := &ServiceNode{}
+		if err := conn.Get(pth, node); err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			logger.WithField("serviceid", serviceID).WithError(err).Debug("Could not look up service")
+			return nil, err
+		}
+		if !node.Locked {
+			continue
+		}
+
+		stale := Inconsistency{
+			Type:    StaleLock,
+			Path:    path.Join(basepth, pth),
+			Message: fmt.Sprintf("service %s is locked", serviceID),
+		}
+		if repair {
+			node.Locked = false
+			if err := conn.Set(pth, node); err != nil {
+				logger.WithField("serviceid", serviceID).WithError(err).Debug("Could not unlock service")
+				return nil, err
+			}
+			stale.Repaired = true
+		}
+		found = append(found, stale)
+	}
+
+	// scheduling locks on hosts that are offline are only legitimate while
+	// the host is being removed, which holds the service lock
+	if ok, err := IsServiceLocked(conn); err != nil {
+		logger.WithError(err).Debug("Could not check the service lock")
+		return nil, err
+	} else if ok {
+		return found, nil
+	}
+
+	hostIDs, err := conn.Children("/hosts")
+	if err != nil && err != client.ErrNoNode {
+		logger.WithError(err).Debug("Could not look up hosts")
+		return nil, err
+	}
+	sort.Strings(hostIDs)
+
+	for _, hostID := range hostIDs {
+		lockpth := path.Join("/hosts", hostID, "locked")
+		locks, err := conn.Children(lockpth)
+		if err != nil && err != client.ErrNoNode {
+			logger.WithField("hostid", hostID).WithError(err).Debug("Could not look up host locks")
+			return nil, err
+		} else if len(locks) == 0 {
+			continue
+		}
+
+		if ok, err := IsHostOnline(conn, "", hostID); err != nil {
+			return nil, err
+		} else if ok {
+			continue
+		}
+
+		sort.Strings(locks)
+		for _, lock := range locks {
+			stale := Inconsistency{
+				Type:    StaleLock,
+				Path:    path.Join(basepth, lockpth, lock),
+				Message: fmt.Sprintf("offline host %s is locked", hostID),
+			}
+			if repair {
+				if err := conn.Delete(path.Join(lockpth, lock)); err != nil && err != client.ErrNoNode {
+					logger.WithField("hostid", hostID).WithError(err).Debug("Could not delete host lock")
+					return nil, err
+				}
+				stale.Repaired = true
+			}
+			found = append(found, stale)
+		}
+	}
+
+	return found, nil
+}
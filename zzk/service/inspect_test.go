@@ -0,0 +1,177 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/client/memory"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+const inspectDSN = "inspect"
+
+var _ = Suite(&InspectTestSuite{})
+
+type InspectTestSuite struct {
+	root client.Connection
+	conn client.Connection
+}
+
+func (s *InspectTestSuite) SetUpTest(c *C) {
+	var err error
+	drv := &memory.Driver{}
+	s.root, err = drv.GetConnection(inspectDSN, "/")
+	c.Assert(err, IsNil)
+	s.conn, err = drv.GetConnection(inspectDSN, "/pools/pool")
+	c.Assert(err, IsNil)
+
+	// the coordinator has pool and gone, hosts h1 and h2, and services s1
+	// and s2
+	c.Assert(UpdateResourcePool(s.root, pool.ResourcePool{ID: "pool"}), IsNil)
+	c.Assert(UpdateResourcePool(s.root, pool.ResourcePool{ID: "gone"}), IsNil)
+	c.Assert(AddHost(s.conn, host.Host{ID: "h1", PoolID: "pool"}), IsNil)
+	c.Assert(AddHost(s.conn, host.Host{ID: "h2", PoolID: "pool"}), IsNil)
+	c.Assert(UpdateService(s.conn, &service.Service{ID: "s1", PoolID: "pool"}, true, false), IsNil)
+	c.Assert(UpdateService(s.conn, &service.Service{ID: "s2", PoolID: "pool"}, false, false), IsNil)
+}
+
+func (s *InspectTestSuite) TearDownTest(c *C) {
+	s.conn.Close()
+	s.root.Close()
+	memory.Reset(inspectDSN)
+}
+
+func (s *InspectTestSuite) TestCheckResourcePools(c *C) {
+	pools := []pool.ResourcePool{{ID: "pool"}, {ID: "pool2"}}
+
+	found, err := CheckResourcePools(s.root, pools, false)
+	c.Assert(err, IsNil)
+	c.Assert(found, DeepEquals, []Inconsistency{
+		{Type: MissingNode, Path: "/pools/pool2", Message: "resource pool pool2 is not in the coordinator"},
+		{Type: UnknownNode, Path: "/pools/gone", Message: "resource pool gone is not in the database"},
+	})
+
+	found, err = CheckResourcePools(s.root, pools, true)
+	c.Assert(err, IsNil)
+	c.Assert(found, HasLen, 2)
+	c.Assert(found[0].Repaired, Equals, true)
+	c.Assert(found[1].Repaired, Equals, true)
+
+	found, err = CheckResourcePools(s.root, pools, false)
+	c.Assert(err, IsNil)
+	c.Assert(found, HasLen, 0)
+}
+
+func (s *InspectTestSuite) TestCheckHostsAndServices(c *C) {
+	hosts := []host.Host{{ID: "h1", PoolID: "pool"}, {ID: "h3", PoolID: "pool"}}
+	svcs := []service.Service{{ID: "s1", PoolID: "pool"}, {ID: "s3", PoolID: "pool"}}
+
+	found, err := CheckHosts(s.conn, "pool", hosts, false)
+	c.Assert(err, IsNil)
+	c.Assert(found, DeepEquals, []Inconsistency{
+		{Type: MissingNode, Path: "/pools/pool/hosts/h3", Message: "host h3 is not in the coordinator"},
+		{Type: UnknownNode, Path: "/pools/pool/hosts/h2", Message: "host h2 is not in the database"},
+	})
+
+	found, err = CheckServices(s.conn, "pool", svcs, false)
+	c.Assert(err, IsNil)
+	c.Assert(found, DeepEquals, []Inconsistency{
+		{Type: MissingNode, Path: "/pools/pool/services/s3", Message: "service s3 is not in the coordinator"},
+		{Type: UnknownNode, Path: "/pools/pool/services/s2", Message: "service s2 is not in the database"},
+	})
+
+	_, err = CheckHosts(s.conn, "pool", hosts, true)
+	c.Assert(err, IsNil)
+	_, err = CheckServices(s.conn, "pool", svcs, true)
+	c.Assert(err, IsNil)
+
+	ch, err := s.conn.Children("/hosts")
+	c.Assert(err, IsNil)
+	c.Assert(ch, DeepEquals, []string{"h1", "h3"})
+	ch, err = s.conn.Children("/services")
+	c.Assert(err, IsNil)
+	c.Assert(ch, DeepEquals, []string{"s1", "s3"})
+}
+
+func (s *InspectTestSuite) TestCheckStates(c *C) {
+	req := StateRequest{HostID: "h1", ServiceID: "s1", InstanceID: 0}
+	c.Assert(CreateState(s.conn, req), IsNil)
+
+	// a host state without a service state and a service state that cannot
+	// be parsed
+	c.Assert(s.conn.Create("/hosts/h1/instances/h1-s1-1", &HostState{}), IsNil)
+	c.Assert(s.conn.Create("/services/s1/bogus", &ServiceState{}), IsNil)
+
+	found, err := CheckStates(s.conn, "pool", false)
+	c.Assert(err, IsNil)
+	c.Assert(found, DeepEquals, []Inconsistency{
+		{Type: OrphanState, Path: "/pools/pool/hosts/h1/instances/h1-s1-1", Message: "host state h1-s1-1 has no matching service state"},
+		{Type: InvalidState, Path: "/pools/pool/services/s1/bogus", Message: "service state bogus has an invalid id"},
+	})
+
+	found, err = CheckStates(s.conn, "pool", true)
+	c.Assert(err, IsNil)
+	c.Assert(found, HasLen, 2)
+
+	found, err = CheckStates(s.conn, "pool", false)
+	c.Assert(err, IsNil)
+	c.Assert(found, HasLen, 0)
+
+	ok, err := IsValidState(s.conn, req)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+}
+
+func (s *InspectTestSuite) TestCheckLocks(c *C) {
+	// h1 is offline but holds a scheduling lock
+	lock, err := s.conn.NewLock("/hosts/h1/locked")
+	c.Assert(err, IsNil)
+	c.Assert(lock.Lock(), IsNil)
+
+	found, err := CheckLocks(s.conn, "pool", false)
+	c.Assert(err, IsNil)
+	c.Assert(found, HasLen, 2)
+	c.Assert(found[0], DeepEquals, Inconsistency{Type: StaleLock, Path: "/pools/pool/services/s1", Message: "service s1 is locked"})
+	c.Assert(found[1].Type, Equals, StaleLock)
+	c.Assert(found[1].Message, Equals, "offline host h1 is locked")
+
+	// host locks are legitimate while the service lock is held
+	svclock, err := ServiceLock(s.conn)
+	c.Assert(err, IsNil)
+	c.Assert(svclock.Lock(), IsNil)
+	found, err = CheckLocks(s.conn, "pool", false)
+	c.Assert(err, IsNil)
+	c.Assert(found, HasLen, 1)
+	c.Assert(svclock.Unlock(), IsNil)
+
+	found, err = CheckLocks(s.conn, "pool", true)
+	c.Assert(err, IsNil)
+	c.Assert(found, HasLen, 2)
+	c.Assert(found[0].Repaired, Equals, true)
+	c.Assert(found[1].Repaired, Equals, true)
+
+	node := &ServiceNode{}
+	c.Assert(s.conn.Get("/services/s1", node), IsNil)
+	c.Assert(node.Locked, Equals, false)
+
+	found, err = CheckLocks(s.conn, "pool", false)
+	c.Assert(err, IsNil)
+	c.Assert(found, HasLen, 0)
+}
@@ -0,0 +1,152 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// Virtual IP events recorded in the failover history
+const (
+	IPAssigned   = "assigned"
+	IPUnassigned = "unassigned"
+	IPFailed     = "failed"
+)
+
+// maxIPHistory is the number of events kept for each virtual IP
+const maxIPHistory = 20
+
+// VirtualIPEvent is an entry in the failover history of a virtual IP
+type VirtualIPEvent struct {
+	Time   time.Time
+	HostID string
+	Event  string
+	Reason string
+}
+
+// VirtualIPStatus is the current owner and failover history of a virtual IP
+type VirtualIPStatus struct {
+	IPAddress      string
+	HostID         string // Host the virtual IP is assigned to, if any
+	Since          time.Time
+	PreferredHosts []string
+	History        []VirtualIPEvent // Most recent first
+	version        interface{}
+}
+
+// Version implements client.Node
+func (s *VirtualIPStatus) Version() interface{} {
+	return s.version
+}
+
+// SetVersion implements client.Node
+func (s *VirtualIPStatus) SetVersion(version interface{}) {
+	s.version = version
+}
+
+func ipStatusPath(poolID, ipAddress string) string {
+	basepth := "/"
+	if poolID != "" {
+		basepth = path.Join("/pools", poolID)
+	}
+	return path.Join(basepth, "ipstatus", ipAddress)
+}
+
+// RecordIPEvent adds an event to the history of a virtual IP and updates its
+// owner.  As with IPRequest, an empty poolID means the connection is already
+// rooted at the pool.
+func RecordIPEvent(conn client.Connection, poolID, ipAddress string, event VirtualIPEvent) error {
+	logger := plog.WithFields(log.Fields{
+		"poolid":    poolID,
+		"ipaddress": ipAddress,
+		"hostid":    event.HostID,
+		"event":     event.Event,
+	})
+
+	pth := ipStatusPath(poolID, ipAddress)
+	status := &VirtualIPStatus{}
+	if err := conn.Get(pth, status); err == client.ErrNoNode {
+		status = &VirtualIPStatus{}
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up virtual ip status")
+		return err
+	}
+	status.IPAddress = ipAddress
+
+	switch event.Event {
+	case IPAssigned:
+		status.HostID = event.HostID
+		status.Since = event.Time
+	default:
+		if status.HostID == event.HostID {
+			status.HostID = ""
+			status.Since = event.Time
+		}
+	}
+
+	status.History = append([]VirtualIPEvent{event}, status.History...)
+	if len(status.History) > maxIPHistory {
+		status.History = status.History[:maxIPHistory]
+	}
+
+	if status.Version() == nil {
+		err := conn.Create(pth, status)
+		if err == client.ErrNodeExists {
+			// somebody beat us to it, so try again
+			return RecordIPEvent(conn, poolID, ipAddress, event)
+		} else if err != nil {
+			logger.WithError(err).Debug("Could not create virtual ip status")
+			return err
+		}
+	} else if err := conn.Set(pth, status); err == client.ErrBadVersion {
+		// somebody updated it since we read it, so try again
+		return RecordIPEvent(conn, poolID, ipAddress, event)
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not update virtual ip status")
+		return err
+	}
+
+	logger.Debug("Recorded virtual ip event")
+	return nil
+}
+
+// GetIPStatuses returns the status of the virtual IPs that have been assigned
+// in a pool, sorted by address
+func GetIPStatuses(conn client.Connection, poolID string) ([]VirtualIPStatus, error) {
+	pth := path.Dir(ipStatusPath(poolID, "ip"))
+	ips, err := conn.Children(pth)
+	if err == client.ErrNoNode {
+		return []VirtualIPStatus{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	sort.Strings(ips)
+
+	statuses := []VirtualIPStatus{}
+	for _, ip := range ips {
+		status := &VirtualIPStatus{}
+		if err := conn.Get(path.Join(pth, ip), status); err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, *status)
+	}
+	return statuses, nil
+}
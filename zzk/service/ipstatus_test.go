@@ -0,0 +1,134 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/client/memory"
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+const ipStatusDSN = "ipstatus"
+
+var _ = Suite(&IPStatusTestSuite{})
+
+type IPStatusTestSuite struct {
+	conn client.Connection
+}
+
+func (s *IPStatusTestSuite) SetUpTest(c *C) {
+	var err error
+	drv := &memory.Driver{}
+	s.conn, err = drv.GetConnection(ipStatusDSN, "/")
+	c.Assert(err, IsNil)
+}
+
+func (s *IPStatusTestSuite) TearDownTest(c *C) {
+	s.conn.Close()
+	memory.Reset(ipStatusDSN)
+}
+
+func (s *IPStatusTestSuite) TestGetIPStatuses_Empty(c *C) {
+	statuses, err := GetIPStatuses(s.conn, "pool")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 0)
+}
+
+func (s *IPStatusTestSuite) TestRecordIPEvent(c *C) {
+	now := time.Now()
+	events := []VirtualIPEvent{
+		{Time: now, HostID: "h1", Event: IPAssigned},
+		{Time: now.Add(time.Second), HostID: "h1", Event: IPFailed, Reason: "host is offline"},
+		{Time: now.Add(2 * time.Second), HostID: "h2", Event: IPAssigned},
+	}
+	for _, event := range events {
+		c.Assert(RecordIPEvent(s.conn, "pool", "10.0.0.2", event), IsNil)
+	}
+	c.Assert(RecordIPEvent(s.conn, "pool", "10.0.0.1", events[0]), IsNil)
+
+	statuses, err := GetIPStatuses(s.conn, "pool")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 2)
+	c.Assert(statuses[0].IPAddress, Equals, "10.0.0.1")
+	c.Assert(statuses[0].HostID, Equals, "h1")
+	c.Assert(statuses[1].IPAddress, Equals, "10.0.0.2")
+	c.Assert(statuses[1].HostID, Equals, "h2")
+	c.Assert(statuses[1].Since.Equal(events[2].Time), Equals, true)
+	c.Assert(statuses[1].History, HasLen, 3)
+	c.Assert(statuses[1].History[0].HostID, Equals, "h2")
+	c.Assert(statuses[1].History[1].Reason, Equals, "host is offline")
+
+	// unassigning a host that no longer owns the ip keeps the owner
+	c.Assert(RecordIPEvent(s.conn, "pool", "10.0.0.2", VirtualIPEvent{Time: now, HostID: "h1", Event: IPUnassigned}), IsNil)
+	statuses, err = GetIPStatuses(s.conn, "pool")
+	c.Assert(err, IsNil)
+	c.Assert(statuses[1].HostID, Equals, "h2")
+}
+
+func (s *IPStatusTestSuite) TestRecordIPEvent_History(c *C) {
+	for i := 0; i < 25; i++ {
+		event := VirtualIPEvent{Time: time.Now(), HostID: fmt.Sprintf("h%d", i), Event: IPAssigned}
+		c.Assert(RecordIPEvent(s.conn, "", "10.0.0.1", event), IsNil)
+	}
+
+	statuses, err := GetIPStatuses(s.conn, "")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].HostID, Equals, "h24")
+	c.Assert(statuses[0].History, HasLen, 20)
+	c.Assert(statuses[0].History[19].HostID, Equals, "h5")
+}
+
+// conflictConn updates a node behind the caller's back before the caller's
+// first update, so that the caller's update has a stale version
+type conflictConn struct {
+	client.Connection
+	conflicted bool
+}
+
+func (cc *conflictConn) Set(p string, node client.Node) error {
+	if !cc.conflicted {
+		cc.conflicted = true
+		status := &VirtualIPStatus{}
+		if err := cc.Connection.Get(p, status); err != nil {
+			return err
+		}
+		status.History = append([]VirtualIPEvent{{HostID: "h2", Event: IPFailed}}, status.History...)
+		if err := cc.Connection.Set(p, status); err != nil {
+			return err
+		}
+	}
+	return cc.Connection.Set(p, node)
+}
+
+func (s *IPStatusTestSuite) TestRecordIPEvent_Conflict(c *C) {
+	c.Assert(RecordIPEvent(s.conn, "pool", "10.0.0.1", VirtualIPEvent{Time: time.Now(), HostID: "h1", Event: IPAssigned}), IsNil)
+
+	conn := &conflictConn{Connection: s.conn}
+	c.Assert(RecordIPEvent(conn, "pool", "10.0.0.1", VirtualIPEvent{Time: time.Now(), HostID: "h3", Event: IPAssigned}), IsNil)
+
+	statuses, err := GetIPStatuses(s.conn, "pool")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].HostID, Equals, "h3")
+	c.Assert(statuses[0].History, HasLen, 3)
+	c.Assert(statuses[0].History[0].HostID, Equals, "h3")
+	c.Assert(statuses[0].History[1].HostID, Equals, "h2")
+}
@@ -30,3 +30,15 @@ func (_m *HostIPHandler) ReleaseIP(ip string) error {
 
 	return r0
 }
+func (_m *HostIPHandler) CheckIP(ip string, iface string) error {
+	ret := _m.Called(ip, iface)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(ip, iface)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
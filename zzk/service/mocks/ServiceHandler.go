@@ -7,19 +7,19 @@ type ServiceHandler struct {
 	mock.Mock
 }
 
-func (_m *ServiceHandler) SelectHost(_a0 *service.ServiceNode) (string, error) {
-	ret := _m.Called(_a0)
+func (_m *ServiceHandler) SelectHost(_a0 *service.ServiceNode, _a1 *service.SchedulingPass) (string, error) {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*service.ServiceNode) string); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(*service.ServiceNode, *service.SchedulingPass) string); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*service.ServiceNode) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(*service.ServiceNode, *service.SchedulingPass) error); ok {
+		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -58,6 +58,11 @@ func (p *ZKPath) IPs() *ZKPath {
 	return p.concat("ips")
 }
 
+// IPStatus appends the node name for virtual IP status to the zookeeper path.
+func (p *ZKPath) IPStatus() *ZKPath {
+	return p.concat("ipstatus")
+}
+
 // Locked appends the node name for locked to the zookeeper path.
 func (p *ZKPath) Locked() *ZKPath {
 	return p.concat("locked")
@@ -0,0 +1,117 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+	"sort"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// RollingRestartNode is the progress of the most recent rolling restart of a
+// service, as stored in zookeeper
+type RollingRestartNode struct {
+	service.RollingRestartStatus
+	version interface{}
+}
+
+// Version implements client.Node
+func (node *RollingRestartNode) Version() interface{} {
+	return node.version
+}
+
+// SetVersion implements client.Node
+func (node *RollingRestartNode) SetVersion(version interface{}) {
+	node.version = version
+}
+
+func rollingRestartPath(serviceID string) string {
+	return path.Join("/rollingrestarts", serviceID)
+}
+
+// SetRollingRestartStatus records the progress of the rolling restart of a
+// service, replacing that of any earlier rolling restart
+func SetRollingRestartStatus(conn client.Connection, status service.RollingRestartStatus) error {
+	logger := plog.WithField("serviceid", status.ServiceID)
+
+	pth := rollingRestartPath(status.ServiceID)
+	node := &RollingRestartNode{}
+	if err := conn.Get(pth, node); err == client.ErrNoNode {
+		node = &RollingRestartNode{}
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up rolling restart status")
+		return err
+	}
+	node.RollingRestartStatus = status
+
+	if node.Version() == nil {
+		err := conn.Create(pth, node)
+		if err == client.ErrNodeExists {
+			// somebody beat us to it, so try again
+			return SetRollingRestartStatus(conn, status)
+		} else if err != nil {
+			logger.WithError(err).Debug("Could not create rolling restart status")
+			return err
+		}
+	} else if err := conn.Set(pth, node); err == client.ErrBadVersion {
+		// somebody updated it since we read it, so try again
+		return SetRollingRestartStatus(conn, status)
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not update rolling restart status")
+		return err
+	}
+
+	logger.WithField("state", status.State).Debug("Updated rolling restart status")
+	return nil
+}
+
+// GetRollingRestartStatuses returns the progress of the most recent rolling
+// restart of a service, or of every service sorted by id if serviceID is
+// empty
+func GetRollingRestartStatuses(conn client.Connection, serviceID string) ([]service.RollingRestartStatus, error) {
+	serviceIDs := []string{serviceID}
+	if serviceID == "" {
+		var err error
+		if serviceIDs, err = conn.Children(rollingRestartPath("")); err == client.ErrNoNode {
+			return []service.RollingRestartStatus{}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		sort.Strings(serviceIDs)
+	}
+
+	statuses := []service.RollingRestartStatus{}
+	for _, id := range serviceIDs {
+		node := &RollingRestartNode{}
+		if err := conn.Get(rollingRestartPath(id), node); err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, node.RollingRestartStatus)
+	}
+	return statuses, nil
+}
+
+// RemoveRollingRestartStatus removes the rolling restart progress of a
+// service
+func RemoveRollingRestartStatus(conn client.Connection, serviceID string) error {
+	err := conn.Delete(rollingRestartPath(serviceID))
+	if err != nil && err != client.ErrNoNode {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/client/memory"
+	"github.com/control-center/serviced/domain/service"
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+const rollingRestartDSN = "rollingrestart"
+
+var _ = Suite(&RollingRestartTestSuite{})
+
+type RollingRestartTestSuite struct {
+	conn client.Connection
+}
+
+func (s *RollingRestartTestSuite) SetUpTest(c *C) {
+	var err error
+	drv := &memory.Driver{}
+	s.conn, err = drv.GetConnection(rollingRestartDSN, "/")
+	c.Assert(err, IsNil)
+}
+
+func (s *RollingRestartTestSuite) TearDownTest(c *C) {
+	s.conn.Close()
+	memory.Reset(rollingRestartDSN)
+}
+
+func (s *RollingRestartTestSuite) TestGetRollingRestartStatuses_Empty(c *C) {
+	statuses, err := GetRollingRestartStatuses(s.conn, "")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 0)
+
+	statuses, err = GetRollingRestartStatuses(s.conn, "service1")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 0)
+}
+
+func (s *RollingRestartTestSuite) TestSetRollingRestartStatus(c *C) {
+	c.Assert(SetRollingRestartStatus(s.conn, service.RollingRestartStatus{ServiceID: "service2", State: service.RollingRestartRunning}), IsNil)
+	c.Assert(SetRollingRestartStatus(s.conn, service.RollingRestartStatus{ServiceID: "service1", State: service.RollingRestartRunning}), IsNil)
+	c.Assert(SetRollingRestartStatus(s.conn, service.RollingRestartStatus{ServiceID: "service1", State: service.RollingRestartCompleted, Batch: 2}), IsNil)
+
+	statuses, err := GetRollingRestartStatuses(s.conn, "service1")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].State, Equals, service.RollingRestartCompleted)
+	c.Assert(statuses[0].Batch, Equals, 2)
+
+	statuses, err = GetRollingRestartStatuses(s.conn, "")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 2)
+	c.Assert(statuses[0].ServiceID, Equals, "service1")
+	c.Assert(statuses[1].ServiceID, Equals, "service2")
+
+	c.Assert(RemoveRollingRestartStatus(s.conn, "service1"), IsNil)
+	c.Assert(RemoveRollingRestartStatus(s.conn, "service1"), IsNil)
+	statuses, err = GetRollingRestartStatuses(s.conn, "")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 1)
+	c.Assert(statuses[0].ServiceID, Equals, "service2")
+}
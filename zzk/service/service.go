@@ -45,9 +45,12 @@ type ServiceNode struct {
 	Instances                   int
 	RAMCommitment               utils.EngNotation
 	CPUCommitment               int
+	CPUQuota                    float64
+	CPUSet                      string
 	ChangeOptions               []servicedefinition.ChangeOption
 	AddressAssignment           addressassignment.AddressAssignment
 	ShouldHaveAddressAssignment bool
+	Trace                       string // traceparent of the request that last scheduled the service
 	//non-service fields
 	Locked  bool
 	version interface{}
@@ -60,9 +63,12 @@ func NewServiceNodeFromService(s *service.Service) (*ServiceNode, error) {
 		DesiredState:  s.DesiredState,
 		Instances:     s.Instances,
 		CPUCommitment: int(s.CPUCommitment),
+		CPUQuota:      s.CPUQuota,
+		CPUSet:        s.CPUSet,
 		RAMCommitment: s.RAMCommitment,
 		ChangeOptions: s.ChangeOptions,
 		HostPolicy:    s.HostPolicy,
+		Trace:         s.Trace,
 	}
 
 	// Copy address assignment if it exists. Note whether assignment is expected, so the scheduler can verify it later.
@@ -26,7 +26,22 @@ import (
 
 // ServiceHandler handles all non-zookeeper interactions required by the service
 type ServiceHandler interface {
-	SelectHost(*ServiceNode) (string, error)
+	SelectHost(*ServiceNode, *SchedulingPass) (string, error)
+}
+
+// SchedulingPass is shared by the instances that are started while a service
+// is synced, so that the handler can look up what they have in common once.
+type SchedulingPass struct {
+	once  sync.Once
+	value interface{}
+	err   error
+}
+
+// Load returns the result of the first load of the pass.  Later calls do
+// not call load.
+func (p *SchedulingPass) Load(load func() (interface{}, error)) (interface{}, error) {
+	p.once.Do(func() { p.value, p.err = load() })
+	return p.value, p.err
 }
 
 // ServiceListener is the listener for /services
@@ -240,6 +255,7 @@ func (l *ServiceListener) Sync(isLocked bool, sn *ServiceNode, reqs []StateReque
 	SortStateRequests(reqs)
 
 	// Start instances if there is a deficit
+	pass := &SchedulingPass{}
 	delta := 0
 	i := -1
 	for ; count < sn.Instances; count++ {
@@ -254,7 +270,7 @@ func (l *ServiceListener) Sync(isLocked bool, sn *ServiceNode, reqs []StateReque
 		}
 
 		// Create the instance if the service is not locked
-		if !l.Start(sn, i) {
+		if !l.Start(sn, i, pass) {
 			return delta, false
 		}
 		delta++
@@ -278,7 +294,7 @@ func (l *ServiceListener) Sync(isLocked bool, sn *ServiceNode, reqs []StateReque
 }
 
 // Start schedules a service instance
-func (l *ServiceListener) Start(sn *ServiceNode, instanceID int) bool {
+func (l *ServiceListener) Start(sn *ServiceNode, instanceID int, pass *SchedulingPass) bool {
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -291,7 +307,7 @@ func (l *ServiceListener) Start(sn *ServiceNode, instanceID int) bool {
 	})
 
 	// pick a host
-	hostID, err := l.handler.SelectHost(sn)
+	hostID, err := l.handler.SelectHost(sn, pass)
 	if err != nil {
 		logger.WithError(err).Warn("Could not select host")
 		return false
@@ -314,6 +330,7 @@ func (l *ServiceListener) Start(sn *ServiceNode, instanceID int) bool {
 		HostID:     hostID,
 		ServiceID:  sn.ID,
 		InstanceID: instanceID,
+		Trace:      sn.Trace,
 	}
 
 	// make sure the state exists on neither the service nor the host
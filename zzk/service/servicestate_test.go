@@ -11,7 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build unit integration,!quick
 
 package service_test
 
@@ -54,7 +54,7 @@ func (t *ZZKTest) TestServiceListener_Spawn(c *C) {
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", mock.AnythingOfType("*service.ServiceNode")).Return("hostid", nil)
+	handler.On("SelectHost", mock.AnythingOfType("*service.ServiceNode"), mock.AnythingOfType("*service.SchedulingPass")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -219,7 +219,7 @@ func (t *ZZKTest) TestServiceListener_Sync_Unlocked(c *C) {
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("*service.SchedulingPass")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -365,7 +365,7 @@ func (t *ZZKTest) TestServiceListener_Sync_Locked(c *C) {
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("*service.SchedulingPass")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -425,7 +425,7 @@ func (t *ZZKTest) TestServiceListener_Sync_RestartAllOnInstanceChanged(c *C) {
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("*service.SchedulingPass")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -481,7 +481,7 @@ func (t *ZZKTest) TestServiceListener_Sync_RestartAllOnInstanceZeroDown_Test1(c
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("*service.SchedulingPass")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -539,7 +539,7 @@ func (t *ZZKTest) TestServiceListener_Sync_RestartAllOnInstanceZeroDown_Test2(c
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("*service.SchedulingPass")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -599,7 +599,7 @@ func (t *ZZKTest) TestServiceListener_Sync_RestartAllOnInstanceZeroDown_Test3(c
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("*service.SchedulingPass")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -654,7 +654,7 @@ func (t *ZZKTest) TestServiceListener_Sync_RestartAllOnInstanceZeroDown_Test4(c
 	// an online host
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/online/online")
 	c.Assert(err, IsNil)
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("*service.SchedulingPass")).Return("hostid", nil)
 
 	listener := NewServiceListener("poolid", handler)
 	listener.SetConnection(conn)
@@ -709,10 +709,10 @@ func (t *ZZKTest) TestServiceListener_Start(c *C) {
 	listener.SetConnection(conn)
 
 	// no host
-	handler.On("SelectHost", sn).Return("", ErrTestHostNotFound).Once()
-	c.Assert(listener.Start(sn, 0), Equals, false)
+	handler.On("SelectHost", sn, mock.AnythingOfType("*service.SchedulingPass")).Return("", ErrTestHostNotFound).Once()
+	c.Assert(listener.Start(sn, 0, &SchedulingPass{}), Equals, false)
 
-	handler.On("SelectHost", sn).Return("hostid", nil)
+	handler.On("SelectHost", sn, mock.AnythingOfType("*service.SchedulingPass")).Return("hostid", nil)
 
 	// host state exists
 	req := StateRequest{
@@ -723,10 +723,26 @@ func (t *ZZKTest) TestServiceListener_Start(c *C) {
 	}
 	err = conn.CreateDir("/pools/poolid/hosts/hostid/instances/" + req.StateID())
 	c.Assert(err, IsNil)
-	c.Check(listener.Start(sn, 0), Equals, true)
+	c.Check(listener.Start(sn, 0, &SchedulingPass{}), Equals, true)
 
 	// host state does not exist
-	c.Check(listener.Start(sn, 1), Equals, true)
+	c.Check(listener.Start(sn, 1, &SchedulingPass{}), Equals, true)
+}
+
+func (t *ZZKTest) TestSchedulingPass_Load(c *C) {
+	pass := &SchedulingPass{}
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return "pool", ErrTestHostNotFound
+	}
+
+	for i := 0; i < 2; i++ {
+		value, err := pass.Load(load)
+		c.Check(value, Equals, "pool")
+		c.Check(err, Equals, ErrTestHostNotFound)
+	}
+	c.Assert(loads, Equals, 1)
 }
 
 func (t *ZZKTest) TestServiceListener_Stop_Offline(c *C) {
@@ -63,11 +63,17 @@ type ServiceState struct {
 	Started     time.Time
 	Restarted   time.Time
 	Terminated  time.Time
-	version     interface{}
+	// ConfigChecksums are the checksums of the config files that the
+	// instance was started with, keyed by filename
+	ConfigChecksums map[string]string
+	version         interface{}
 }
 
 type CurrentStateContainer struct {
-	Status  service.InstanceCurrentState
+	Status service.InstanceCurrentState
+	// Crashes is the number of consecutive times the instance's container
+	// exited shortly after it was started
+	Crashes int
 	version interface{}
 }
 
@@ -93,6 +99,7 @@ func (s *ServiceState) SetVersion(version interface{}) {
 type HostState struct {
 	DesiredState service.DesiredState
 	Scheduled    time.Time
+	Trace        string // traceparent of the request that scheduled the instance
 	version      interface{}
 }
 
@@ -122,6 +129,7 @@ type StateRequest struct {
 	HostID     string
 	ServiceID  string
 	InstanceID int
+	Trace      string // traceparent passed on to the host state
 }
 
 func (req StateRequest) StateID() string {
@@ -471,6 +479,7 @@ func CreateState(conn client.Connection, req StateRequest) error {
 	hsdat := &HostState{
 		DesiredState: service.SVCRun,
 		Scheduled:    time.Now(),
+		Trace:        req.Trace,
 	}
 	t.Create(hspth, hsdat)
 
@@ -0,0 +1,136 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package session keeps web UI sessions in the coordinator, so that they
+// survive master restarts and can be shared by several UI servers.
+package session
+
+import (
+	"errors"
+	"path"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/zzk"
+)
+
+const sessionPath = "/websessions"
+
+// ErrNoSession is returned when a session does not exist
+var ErrNoSession = errors.New("session not found")
+
+// Session is an authenticated web UI session.  The ID is derived from the
+// session's token, which is not stored.
+type Session struct {
+	ID          string    // Identifies the session
+	User        string    // User that logged in
+	Provider    string    // Identity provider the user logged in with; empty for local users
+	Fingerprint string    // Identifies the credentials the user logged in with
+	CreatedAt   time.Time // Time the user logged in
+	AccessedAt  time.Time // Time the session was last used
+	version     interface{}
+}
+
+// Version implements client.Node
+func (s *Session) Version() interface{} {
+	return s.version
+}
+
+// SetVersion implements client.Node
+func (s *Session) SetVersion(version interface{}) {
+	s.version = version
+}
+
+// Store keeps sessions in the coordinator
+type Store struct {
+	getConnection func() (client.Connection, error)
+}
+
+// NewStore returns a store that uses the local coordinator connection
+func NewStore() *Store {
+	return &Store{
+		getConnection: func() (client.Connection, error) {
+			return zzk.GetLocalConnection("/")
+		},
+	}
+}
+
+// Get returns a session by id
+func (st *Store) Get(id string) (*Session, error) {
+	conn, err := st.getConnection()
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{}
+	if err := conn.Get(path.Join(sessionPath, id), s); err == client.ErrNoNode {
+		return nil, ErrNoSession
+	} else if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Put adds or updates a session
+func (st *Store) Put(s *Session) error {
+	conn, err := st.getConnection()
+	if err != nil {
+		return err
+	}
+	pth := path.Join(sessionPath, s.ID)
+	existing := &Session{}
+	if err := conn.Get(pth, existing); err == client.ErrNoNode {
+		s.SetVersion(nil)
+		return conn.Create(pth, s)
+	} else if err != nil {
+		return err
+	}
+	s.SetVersion(existing.Version())
+	return conn.Set(pth, s)
+}
+
+// Delete removes a session
+func (st *Store) Delete(id string) error {
+	conn, err := st.getConnection()
+	if err != nil {
+		return err
+	}
+	if err := conn.Delete(path.Join(sessionPath, id)); err != nil && err != client.ErrNoNode {
+		return err
+	}
+	return nil
+}
+
+// List returns all sessions
+func (st *Store) List() ([]*Session, error) {
+	conn, err := st.getConnection()
+	if err != nil {
+		return nil, err
+	}
+	ids, err := conn.Children(sessionPath)
+	if err == client.ErrNoNode {
+		return []*Session{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	sessions := make([]*Session, 0, len(ids))
+	for _, id := range ids {
+		s := &Session{}
+		if err := conn.Get(path.Join(sessionPath, id), s); err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit integration,!quick
+
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/zzk"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&ZZKTest{})
+
+type ZZKTest struct {
+	zzk.ZZKTestSuite
+}
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+func (t *ZZKTest) TestStore(c *C) {
+	store := NewStore()
+
+	sessions, err := store.List()
+	c.Assert(err, IsNil)
+	c.Assert(sessions, HasLen, 0)
+
+	_, err = store.Get("abc")
+	c.Assert(err, Equals, ErrNoSession)
+
+	now := time.Now().UTC().Round(time.Second)
+	s := &Session{ID: "abc", User: "admin", CreatedAt: now, AccessedAt: now}
+	c.Assert(store.Put(s), IsNil)
+
+	actual, err := store.Get("abc")
+	c.Assert(err, IsNil)
+	c.Assert(actual.User, Equals, "admin")
+	c.Assert(actual.CreatedAt.Equal(now), Equals, true)
+
+	actual.AccessedAt = now.Add(time.Minute)
+	c.Assert(store.Put(actual), IsNil)
+	c.Assert(store.Put(&Session{ID: "def", User: "other"}), IsNil)
+
+	sessions, err = store.List()
+	c.Assert(err, IsNil)
+	c.Assert(sessions, HasLen, 2)
+
+	c.Assert(store.Delete("abc"), IsNil)
+	c.Assert(store.Delete("abc"), IsNil)
+	_, err = store.Get("abc")
+	c.Assert(err, Equals, ErrNoSession)
+}
@@ -27,6 +27,10 @@ import (
 // NOTE: this constant can be adjusted to satisfy race conditions
 const ZKTestTimeout = 5 * time.Second
 
+// TestConnectionType is the type of the connections returned by the local
+// client
+const TestConnectionType = "*zookeeper.Connection"
+
 type ZZKTestSuite struct {
 	isvcs.ManagerTestSuite
 }
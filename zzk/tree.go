@@ -0,0 +1,79 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zzk
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// TreeNode is a node in a dump of the coordinator's tree
+type TreeNode struct {
+	Path     string
+	Data     json.RawMessage `json:",omitempty"`
+	Children []TreeNode      `json:",omitempty"`
+}
+
+// rawNode keeps the stored data of a node without decoding it
+type rawNode struct {
+	data    json.RawMessage
+	version interface{}
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (node *rawNode) UnmarshalJSON(data []byte) error {
+	node.data = append(node.data[:0], data...)
+	return nil
+}
+
+// Version implements client.Node
+func (node *rawNode) Version() interface{} { return node.version }
+
+// SetVersion implements client.Node
+func (node *rawNode) SetVersion(version interface{}) { node.version = version }
+
+// GetTree returns the node at the given path along with all of its
+// descendants.  Nodes are returned in the order of their names.
+func GetTree(conn client.Connection, pth string) (*TreeNode, error) {
+	pth = path.Join("/", pth)
+
+	node := &rawNode{}
+	if err := conn.Get(pth, node); err != nil && err != client.ErrEmptyNode {
+		return nil, err
+	}
+
+	ch, err := conn.Children(pth)
+	if err == client.ErrNoNode {
+		// the node was deleted while the tree was being walked
+		ch = []string{}
+	} else if err != nil {
+		return nil, err
+	}
+	sort.Strings(ch)
+
+	tree := &TreeNode{Path: pth, Data: node.data}
+	for _, name := range ch {
+		child, err := GetTree(conn, path.Join(pth, name))
+		if err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		tree.Children = append(tree.Children, *child)
+	}
+	return tree, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit integration,!quick
+
+package zzk_test
+
+import (
+	"encoding/json"
+
+	"github.com/control-center/serviced/coordinator/client"
+	. "github.com/control-center/serviced/zzk"
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestGetTree(c *C) {
+	conn, err := GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	c.Assert(conn.CreateDir("/tree/b"), IsNil)
+	c.Assert(conn.Create("/tree/a", &HostLeader{HostID: "host"}), IsNil)
+
+	tree, err := GetTree(conn, "tree")
+	c.Assert(err, IsNil)
+	c.Assert(tree.Path, Equals, "/tree")
+	c.Assert(tree.Children, HasLen, 2)
+	c.Assert(tree.Children[0].Path, Equals, "/tree/a")
+	c.Assert(tree.Children[1].Path, Equals, "/tree/b")
+
+	leader := &HostLeader{}
+	c.Assert(json.Unmarshal(tree.Children[0].Data, leader), IsNil)
+	c.Assert(leader.HostID, Equals, "host")
+
+	_, err = GetTree(conn, "/missing")
+	c.Assert(err, Equals, client.ErrNoNode)
+}